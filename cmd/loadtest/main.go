@@ -0,0 +1,394 @@
+// Command loadtest drives a configurable mix of tool calls against a running
+// OpsRamp MCP server and reports latency percentiles, error rates and OpsRamp
+// call amplification (OpsRamp API requests per tool call), for sizing
+// deployments before rollout.
+//
+//	go run ./cmd/loadtest -url http://localhost:8080 -mix search -concurrency 20 -duration 1m
+//
+// Calls are sent as JSON-RPC tools/call requests to the server's /mcp
+// endpoint. The mixed profile posts alert notes, so it refuses to run unless
+// the alerts tool is served by the mock backend (TOOL_BACKENDS=alerts=mock)
+// or -allow-live-mutations is set.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+// backendMetaKey is the tools/list _meta key reporting which backend serves a tool
+const backendMetaKey = "or-mcp/backend"
+
+// toolCall is one kind of call in a load mix, picked in proportion to its weight
+type toolCall struct {
+	name    string
+	tool    string
+	weight  int
+	mutates bool
+	args    func(o *options) map[string]interface{}
+}
+
+// options holds the command line flags
+type options struct {
+	url                string
+	mix                string
+	concurrency        int
+	duration           time.Duration
+	requests           int64
+	timeout            time.Duration
+	resourceID         string
+	alertID            string
+	allowLiveMutations bool
+	maxErrorRate       float64
+}
+
+// mixes are the supported load profiles
+var mixes = map[string][]toolCall{
+	"search": {
+		{name: "resources.search", tool: "resources", weight: 6, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "search", "params": map[string]interface{}{"pageSize": 50}}
+		}},
+		{name: "resources.list", tool: "resources", weight: 2, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "list"}
+		}},
+		{name: "alerts.search", tool: "alerts", weight: 2, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "search", "timeRange": "last24h"}
+		}},
+	},
+	"get": {
+		{name: "resources.get", tool: "resources", weight: 4, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "get", "id": o.resourceID}
+		}},
+		{name: "resources.getDetailed", tool: "resources", weight: 2, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "getDetailed", "id": o.resourceID}
+		}},
+		{name: "resources.scorecard", tool: "resources", weight: 1, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "scorecard", "id": o.resourceID}
+		}},
+		{name: "alerts.get", tool: "alerts", weight: 3, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "get", "id": o.alertID}
+		}},
+	},
+	"mixed": {
+		{name: "resources.search", tool: "resources", weight: 3, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "search", "params": map[string]interface{}{"pageSize": 50}}
+		}},
+		{name: "resources.get", tool: "resources", weight: 3, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "get", "id": o.resourceID}
+		}},
+		{name: "alerts.get", tool: "alerts", weight: 2, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "get", "id": o.alertID}
+		}},
+		{name: "alerts.getThread", tool: "alerts", weight: 1, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "getThread", "id": o.alertID}
+		}},
+		{name: "alerts.addNote", tool: "alerts", weight: 1, mutates: true, args: func(o *options) map[string]interface{} {
+			return map[string]interface{}{"action": "addNote", "id": o.alertID, "note": "load test note", "requestedBy": "loadtest"}
+		}},
+	},
+}
+
+// sample is the outcome of one tool call
+type sample struct {
+	name    string
+	latency time.Duration
+	err     error
+}
+
+// stats aggregates the samples for one call kind
+type stats struct {
+	latencies []time.Duration
+	errors    int
+	lastError string
+}
+
+func main() {
+	o := &options{}
+	flag.StringVar(&o.url, "url", "http://localhost:8080", "Base URL of the MCP server")
+	flag.StringVar(&o.mix, "mix", "mixed", "Load profile: search, get or mixed")
+	flag.IntVar(&o.concurrency, "concurrency", 10, "Number of concurrent clients")
+	flag.DurationVar(&o.duration, "duration", 30*time.Second, "How long to run")
+	flag.Int64Var(&o.requests, "requests", 0, "Stop after this many tool calls (0 means run for -duration)")
+	flag.DurationVar(&o.timeout, "timeout", 30*time.Second, "Per-call timeout")
+	flag.StringVar(&o.resourceID, "resource-id", "", "Resource ID used by get calls")
+	flag.StringVar(&o.alertID, "alert-id", "", "Alert ID used by alert calls")
+	flag.BoolVar(&o.allowLiveMutations, "allow-live-mutations", false, "Allow mutating calls against live backends")
+	flag.Float64Var(&o.maxErrorRate, "max-error-rate", 1, "Exit non-zero when the overall error rate exceeds this fraction")
+	flag.Parse()
+
+	calls, ok := mixes[o.mix]
+	if !ok {
+		log.Fatalf("Unknown mix %q: use search, get or mixed", o.mix)
+	}
+	if o.concurrency < 1 {
+		log.Fatalf("Concurrency must be at least 1")
+	}
+	o.url = strings.TrimRight(o.url, "/")
+	httpClient := &http.Client{Timeout: o.timeout}
+
+	if err := preflight(httpClient, o, calls); err != nil {
+		log.Fatalf("Preflight failed: %v", err)
+	}
+
+	before, countersOK := opsRampRequests(httpClient, o.url)
+	start := time.Now()
+	samples := run(httpClient, o, calls)
+	elapsed := time.Since(start)
+	after, _ := opsRampRequests(httpClient, o.url)
+
+	errorRate := report(os.Stdout, samples, elapsed, countersOK, after-before)
+	if errorRate > o.maxErrorRate {
+		fmt.Fprintf(os.Stderr, "Error rate %.2f%% exceeds the maximum of %.2f%%\n", errorRate*100, o.maxErrorRate*100)
+		os.Exit(1)
+	}
+}
+
+// preflight checks that every tool in the mix is registered, that get calls
+// have IDs, and that mutating calls only reach mock backends
+func preflight(httpClient *http.Client, o *options, calls []toolCall) error {
+	result, err := rpc(httpClient, o.url, 0, "tools/list", map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to list tools: %w", err)
+	}
+	var listed struct {
+		Tools []struct {
+			Name string                 `json:"name"`
+			Meta map[string]interface{} `json:"_meta"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(result, &listed); err != nil {
+		return fmt.Errorf("failed to parse tools/list result: %w", err)
+	}
+	backends := make(map[string]string, len(listed.Tools))
+	for _, tool := range listed.Tools {
+		backend, _ := tool.Meta[backendMetaKey].(string)
+		backends[tool.Name] = backend
+	}
+
+	for _, call := range calls {
+		backend, ok := backends[call.tool]
+		if !ok {
+			return fmt.Errorf("tool %s is not registered on the server", call.tool)
+		}
+		args := call.args(o)
+		if id, ok := args["id"]; ok && id == "" {
+			return fmt.Errorf("%s needs an ID: set -resource-id and -alert-id", call.name)
+		}
+		if call.mutates && backend != "mock" && !o.allowLiveMutations {
+			return fmt.Errorf("%s mutates data but the %s tool uses the %q backend; use TOOL_BACKENDS=%s=mock or -allow-live-mutations",
+				call.name, call.tool, backend, call.tool)
+		}
+	}
+	return nil
+}
+
+// run drives the mix until the duration elapses or the request budget is spent
+func run(httpClient *http.Client, o *options, calls []toolCall) []sample {
+	totalWeight := 0
+	for _, call := range calls {
+		totalWeight += call.weight
+	}
+	pick := func() toolCall {
+		n := rand.IntN(totalWeight)
+		for _, call := range calls {
+			if n < call.weight {
+				return call
+			}
+			n -= call.weight
+		}
+		return calls[len(calls)-1]
+	}
+
+	var (
+		mu      sync.Mutex
+		samples []sample
+		sent    atomic.Int64
+		wg      sync.WaitGroup
+	)
+	deadline := time.Now().Add(o.duration)
+	log.Printf("Running %s mix against %s with %d clients for %s", o.mix, o.url, o.concurrency, o.duration)
+
+	for worker := 0; worker < o.concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				id := sent.Add(1)
+				if o.requests > 0 && id > o.requests {
+					return
+				}
+				call := pick()
+				start := time.Now()
+				err := callTool(httpClient, o.url, id, call.tool, call.args(o))
+				s := sample{name: call.name, latency: time.Since(start), err: err}
+
+				mu.Lock()
+				samples = append(samples, s)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return samples
+}
+
+// callTool sends a tools/call request, treating tool error results as failures
+func callTool(httpClient *http.Client, baseURL string, id int64, tool string, args map[string]interface{}) error {
+	result, err := rpc(httpClient, baseURL, id, "tools/call", map[string]interface{}{"name": tool, "arguments": args})
+	if err != nil {
+		return err
+	}
+	var toolResult struct {
+		IsError bool `json:"isError"`
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(result, &toolResult); err != nil {
+		return fmt.Errorf("failed to parse tool result: %w", err)
+	}
+	if toolResult.IsError {
+		message := "tool returned an error"
+		if len(toolResult.Content) > 0 {
+			message = toolResult.Content[0].Text
+		}
+		return fmt.Errorf("%s", message)
+	}
+	return nil
+}
+
+// rpc posts a JSON-RPC request to the server's /mcp endpoint and returns the result
+func rpc(httpClient *http.Client, baseURL string, id int64, method string, params interface{}) (json.RawMessage, error) {
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Post(baseURL+"/mcp", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("invalid JSON-RPC response: %w", err)
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("JSON-RPC error %d: %s", response.Error.Code, response.Error.Message)
+	}
+	return response.Result, nil
+}
+
+// opsRampRequests reads the server's count of requests sent to OpsRamp
+func opsRampRequests(httpClient *http.Client, baseURL string) (int64, bool) {
+	resp, err := httpClient.Get(baseURL + "/debug")
+	if err != nil {
+		return 0, false
+	}
+	defer resp.Body.Close()
+	var debug struct {
+		OpsRampRequests *int64 `json:"opsrampRequests"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&debug); err != nil || debug.OpsRampRequests == nil {
+		return 0, false
+	}
+	return *debug.OpsRampRequests, true
+}
+
+// report prints per-call and overall statistics and returns the overall error rate
+func report(w io.Writer, samples []sample, elapsed time.Duration, countersOK bool, opsRampCalls int64) float64 {
+	byName := make(map[string]*stats)
+	total := &stats{}
+	for _, s := range samples {
+		st, ok := byName[s.name]
+		if !ok {
+			st = &stats{}
+			byName[s.name] = st
+		}
+		for _, target := range []*stats{st, total} {
+			target.latencies = append(target.latencies, s.latency)
+			if s.err != nil {
+				target.errors++
+				target.lastError = s.err.Error()
+			}
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "call\tcalls\terrors\terror %\tp50\tp90\tp99\tmax\t")
+	row := func(name string, st *stats) {
+		sorted := append([]time.Duration(nil), st.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%s\t%s\t%s\t%s\t\n", name, len(sorted), st.errors,
+			rate(st.errors, len(sorted))*100, percentile(sorted, 0.50), percentile(sorted, 0.90),
+			percentile(sorted, 0.99), percentile(sorted, 1))
+	}
+	for _, name := range names {
+		row(name, byName[name])
+	}
+	row("total", total)
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nThroughput: %.1f calls/s over %s\n", float64(len(samples))/elapsed.Seconds(), elapsed.Round(time.Millisecond))
+	if countersOK && len(samples) > 0 {
+		fmt.Fprintf(w, "OpsRamp calls: %d (%.2f per tool call)\n", opsRampCalls, float64(opsRampCalls)/float64(len(samples)))
+	} else {
+		fmt.Fprintln(w, "OpsRamp calls: unknown (the server's /debug endpoint does not report them)")
+	}
+	for _, name := range names {
+		if st := byName[name]; st.lastError != "" {
+			fmt.Fprintf(w, "Last %s error: %s\n", name, st.lastError)
+		}
+	}
+	return rate(total.errors, len(total.latencies))
+}
+
+// percentile returns the p-th percentile (0 < p <= 1) of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(index, 0)].Round(time.Microsecond)
+}
+
+// rate returns n/total, or 0 when total is 0
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
@@ -5,7 +5,6 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -13,31 +12,31 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/opsramp/or-mcp-v2/common"
 	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/locks"
 	"github.com/opsramp/or-mcp-v2/pkg/tools"
 )
 
 const (
-	// LogDir is the directory where logs will be stored
-	LogDir = "output/logs"
 	// LogFileName is the name of the log file
 	LogFileName = "or-mcp.log"
 )
 
 func main() {
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(LogDir, 0750); err != nil {
-		log.Printf("Failed to create log directory: %v", err)
+	// Check the output locations are writable
+	outputPaths := common.ResolveOutputPaths()
+	if err := outputPaths.CheckWritable(); err != nil {
+		log.Printf("Output locations are not writable: %v", err)
 	}
 
 	// Initialize the logger
-	customLogger, err := common.InitLogger(common.DEBUG, LogDir, LogFileName)
+	customLogger, err := common.InitLogger(common.DEBUG, outputPaths.LogDir, LogFileName)
 	if err != nil {
 		log.Printf("Failed to initialize logger: %v", err)
 		log.Printf("Using default logger")
 	} else {
 		defer customLogger.Close()
 		customLogger.Info("Starting OpsRamp MCP server")
-		customLogger.Info("Log file: %s", filepath.Join(LogDir, LogFileName))
+		customLogger.Info("Log file: %s", filepath.Join(outputPaths.LogDir, LogFileName))
 	}
 
 	// Get the logger
@@ -65,6 +64,9 @@ func main() {
 		}
 	}
 
+	// Label log lines with the tenant and environment
+	logger.SetLabels(config.ServerTelemetryLabels())
+
 	// Validate OpsRamp config
 	logger.Info("Validating OpsRamp configuration...")
 	if err := validateOpsRampConfig(&config.OpsRamp); err != nil {
@@ -74,6 +76,9 @@ func main() {
 		logger.Info("OpsRamp configuration is valid")
 	}
 
+	// Configure the advisory locks taken by bulk mutations
+	locks.SetDefault(locks.NewManager(config.Locks))
+
 	// Create OpsRamp client and store it globally
 	opsRampClient := client.NewOpsRampClient(config)
 	// Set the global client for use by tools
@@ -89,34 +94,82 @@ func main() {
 	}
 
 	// Create MCP server
-	s := server.NewMCPServer("or-mcp-v2", "1.0.0")
+	s := server.NewMCPServer("or-mcp-v2", "1.0.0",
+		server.WithLogging(),
+		server.WithToolHandlerMiddleware(tools.ClientLoggingMiddleware),
+		server.WithToolHandlerMiddleware(tools.SessionContextMiddleware),
+		server.WithToolHandlerMiddleware(tools.ToolHooksMiddleware),
+		server.WithToolHandlerMiddleware(tools.DeadLetterMiddleware),
+		server.WithToolHandlerMiddleware(tools.ExamplesMiddleware),
+		server.WithToolHandlerMiddleware(tools.SuggestionsMiddleware),
+	)
 
 	// Register all tools in alphabetical order
 	logger.Info("Registering MCP tools...")
 
+	adminTool, adminHandler := tools.NewAdminMcpTool()
+	s.AddTool(adminTool, adminHandler)
+	tools.RegisterToolSchema(adminTool)
+
 	acctTool, acctHandler := tools.NewAccountsMcpTool()
 	s.AddTool(acctTool, acctHandler)
+	tools.RegisterToolSchema(acctTool)
+
+	alertsTool, alertsHandler := tools.NewAlertsMcpTool()
+	s.AddTool(alertsTool, alertsHandler)
+	tools.RegisterToolSchema(alertsTool)
+
+	clientsTool, clientsHandler := tools.NewClientsMcpTool()
+	s.AddTool(clientsTool, clientsHandler)
+	tools.RegisterToolSchema(clientsTool)
 
 	devTool, devHandler := tools.NewDevicesMcpTool()
 	s.AddTool(devTool, devHandler)
+	tools.RegisterToolSchema(devTool)
 
 	evtTool, evtHandler := tools.NewEventsMcpTool()
 	s.AddTool(evtTool, evtHandler)
+	tools.RegisterToolSchema(evtTool)
 
 	intTool, intHandler := tools.NewIntegrationsMcpTool()
 	s.AddTool(intTool, intHandler)
+	tools.RegisterToolSchema(intTool)
 
 	jobsTool, jobsHandler := tools.NewJobsMcpTool()
 	s.AddTool(jobsTool, jobsHandler)
+	tools.RegisterToolSchema(jobsTool)
 
 	monTool, monHandler := tools.NewMonitoringMcpTool()
 	s.AddTool(monTool, monHandler)
+	tools.RegisterToolSchema(monTool)
+
+	monitorsTool, monitorsHandler := tools.NewMonitorsMcpTool()
+	s.AddTool(monitorsTool, monitorsHandler)
+	tools.RegisterToolSchema(monitorsTool)
+
+	metricsTool, metricsHandler := tools.NewMetricsMcpTool()
+	s.AddTool(metricsTool, metricsHandler)
+	tools.RegisterToolSchema(metricsTool)
 
 	polTool, polHandler := tools.NewPoliciesMcpTool()
 	s.AddTool(polTool, polHandler)
+	tools.RegisterToolSchema(polTool)
 
 	resTool, resHandler := tools.NewResourcesMcpTool()
 	s.AddTool(resTool, resHandler)
+	tools.RegisterToolSchema(resTool)
+
+	sessionTool, sessionHandler := tools.NewSessionMcpTool()
+	s.AddTool(sessionTool, sessionHandler)
+	tools.RegisterToolSchema(sessionTool)
+
+	usageTool, usageHandler := tools.NewUsageMcpTool()
+	s.AddTool(usageTool, usageHandler)
+	tools.RegisterToolSchema(usageTool)
+
+	utilityTool, utilityHandler := tools.NewUtilityMcpTool()
+	s.AddTool(utilityTool, utilityHandler)
+	tools.RegisterToolSchema(utilityTool)
 
 	logger.Info("All tools registered successfully")
 
@@ -8,23 +8,34 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/crash"
 	"github.com/opsramp/or-mcp-v2/pkg/handlers"
+	"github.com/opsramp/or-mcp-v2/pkg/locks"
 	"github.com/opsramp/or-mcp-v2/pkg/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/notify"
+	"github.com/opsramp/or-mcp-v2/pkg/remotewrite"
+	"github.com/opsramp/or-mcp-v2/pkg/scheduler"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
 	"github.com/opsramp/or-mcp-v2/pkg/tools"
+	"github.com/opsramp/or-mcp-v2/pkg/tracing"
 )
 
 const (
-	// LogDir is the directory where logs will be stored
-	LogDir = "output/logs"
 	// LogFileName is the name of the log file
 	LogFileName = "or-mcp.log"
 	// DefaultPort is the default port to listen on
 	DefaultPort = 8080
+	// ServerVersion is the version reported to MCP clients and in traces
+	ServerVersion = "1.0.0"
 )
 
 // ServerConfig holds the server configuration
@@ -33,13 +44,20 @@ type ServerConfig struct {
 	DebugMode bool
 	Logger    *common.CustomLogger
 	StartTime time.Time
+	// HTTP is the listener address, TLS and security header configuration
+	HTTP common.HTTPConfig
+	// Auth is the authentication required of HTTP clients
+	Auth common.AuthConfig
 }
 
 // MCPServerComponents holds all MCP server components
 type MCPServerComponents struct {
 	MCPServer        *server.MCPServer
 	SSEServer        *server.SSEServer
+	StreamableHTTP   *server.StreamableHTTPServer
 	InspectorHandler *mcp.InspectorHandler
+	LongPollHandler  *mcp.LongPollHandler
+	WebSocketHandler *mcp.WebSocketHandler
 	HTTPHandlers     *handlers.HTTPHandlers
 	RegisteredTools  []string
 }
@@ -53,20 +71,56 @@ func main() {
 	}
 	defer config.Logger.Close()
 
+	// Write a health snapshot to the crash directory if the server crashes
+	installCrashReporter(config.Logger)
+	defer crash.Recover()
+
+	// Label server log lines with the tenant and environment
+	if labelConfig, err := common.LoadConfig(""); err == nil {
+		config.Logger.SetLabels(labelConfig.ServerTelemetryLabels())
+	}
+
+	// Open the local state store shared by server subsystems
+	if stateStore := openStateStore(config.Logger); stateStore != nil {
+		defer stateStore.Close()
+		startNotificationQueue(config.Logger, stateStore)
+		startScheduler(config.Logger, stateStore)
+	}
+
+	// Configure the advisory locks taken by bulk mutations
+	if lockConfig, err := common.LoadConfig(""); err == nil {
+		locks.SetDefault(locks.NewManager(lockConfig.Locks))
+	}
+
+	// Forward selected OpsRamp metrics to Prometheus, if configured
+	startRemoteWriteExporter(config.Logger)
+
+	// Export traces of MCP requests to an OTLP collector, if configured
+	if shutdown := startTracing(config.Logger); shutdown != nil {
+		defer shutdown()
+	}
+
 	// Create MCP server components
 	components, err := createMCPServerComponents(config)
 	if err != nil {
 		config.Logger.Fatal("Failed to create MCP server components: %v", err)
 	}
 
-	// Perform startup health check
-	if err := performStartupHealthCheck(config.Logger); err != nil {
-		config.Logger.Warn("Startup health check failed: %v", err)
-		config.Logger.Info("Continuing with server startup despite health check failure")
+	// Probe connected clients with heartbeats to find sessions that stopped reading
+	startHeartbeats(config.Logger, components.MCPServer)
+
+	// Register tenants, then check their health once the server is serving so
+	// slow OpsRamp responses do not delay startup; /readiness reports progress
+	registry, err := registerTenants(config.Logger)
+	if err != nil {
+		config.Logger.Warn("Failed to register tenants: %v", err)
 	}
 
 	// Start the HTTP server
 	httpServer := createHTTPServer(config, components)
+	if registry != nil {
+		go performStartupHealthCheck(config.Logger, registry)
+	}
 	startServer(config, httpServer)
 }
 
@@ -74,20 +128,22 @@ func main() {
 func initializeServerConfig() (*ServerConfig, error) {
 	startTime := time.Now()
 
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(LogDir, 0750); err != nil {
-		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	// Fail fast when an output location is not writable, e.g. on a
+	// read-only container filesystem without OUTPUT_DIR set
+	outputPaths := common.ResolveOutputPaths()
+	if err := outputPaths.CheckWritable(); err != nil {
+		return nil, fmt.Errorf("output locations are not writable: %w", err)
 	}
 
 	// Initialize the logger
-	logger, err := common.InitLogger(common.DEBUG, LogDir, LogFileName)
+	logger, err := common.InitLogger(common.DEBUG, outputPaths.LogDir, LogFileName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
 	// Log server startup
 	logger.Info("Starting HPE OpsRamp MCP server")
-	logger.Info("Log file: %s", filepath.Join(LogDir, LogFileName))
+	logger.Info("Log file: %s", filepath.Join(outputPaths.LogDir, LogFileName))
 
 	// Determine port from environment variable
 	port := DefaultPort
@@ -100,24 +156,208 @@ func initializeServerConfig() (*ServerConfig, error) {
 		}
 	}
 
+	// Set the levels of individual log modules, e.g. LOG_LEVELS=client=debug,transport=warn
+	if spec := os.Getenv("LOG_LEVELS"); spec != "" {
+		if err := common.SetModuleLevels(spec); err != nil {
+			logger.Warn("Ignoring LOG_LEVELS: %v", err)
+		} else {
+			logger.Info("Log levels by module: %s", spec)
+		}
+	}
+
+	// The listener address and TLS come from the http config section
+	httpConfig, err := common.ResolveHTTPConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Client authentication comes from the auth config section
+	authConfig, err := common.ResolveAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if debug mode is enabled
 	debugMode := os.Getenv("DEBUG") == "true"
 	if debugMode {
 		logger.Info("*** DEBUG MODE ENABLED ***")
 	}
 
+	// Strict protocol mode disables the MCP Inspector compatibility shims
+	if common.StrictProtocol() {
+		logger.Info("*** STRICT PROTOCOL MODE ENABLED: Inspector compatibility shims disabled ***")
+	}
+
 	return &ServerConfig{
 		Port:      port,
 		DebugMode: debugMode,
 		Logger:    logger,
 		StartTime: startTime,
+		HTTP:      httpConfig,
+		Auth:      authConfig,
 	}, nil
 }
 
+// openStateStore opens the local state store and registers it as the process default.
+// Persistence is optional: on failure the server continues without it.
+func openStateStore(logger *common.CustomLogger) *store.Store {
+	stateStore, err := store.Open(common.ResolveOutputPaths().StatePath)
+	if err != nil {
+		logger.Warn("State store unavailable, continuing without persistence: %v", err)
+		return nil
+	}
+
+	store.SetDefault(stateStore)
+	return stateStore
+}
+
+// startNotificationQueue starts delivering queued outbound notifications,
+// including any left over from a previous run
+func startNotificationQueue(logger *common.CustomLogger, stateStore *store.Store) {
+	config, err := common.LoadConfig("")
+	if err != nil {
+		logger.Warn("Notification queue disabled: %v", err)
+		return
+	}
+
+	queue := notify.NewQueue(stateStore, config)
+	notify.SetDefault(queue)
+	go queue.Run(context.Background(), notify.DefaultPollInterval)
+
+	if pending, err := queue.Pending(); err == nil && len(pending) > 0 {
+		logger.Info("Resuming delivery of %d queued notifications", len(pending))
+	}
+}
+
+// startScheduler starts running scheduled tool actions, including entries
+// persisted before a restart
+func startScheduler(logger *common.CustomLogger, stateStore *store.Store) {
+	s := scheduler.NewScheduler(stateStore)
+	scheduler.SetDefault(s)
+	go s.Run(client.WithPriority(context.Background(), client.PriorityBackground), scheduler.DefaultPollInterval)
+
+	if entries, err := s.List(); err == nil && len(entries) > 0 {
+		logger.Info("Resuming %d scheduled actions", len(entries))
+	}
+}
+
+// installCrashReporter writes a health snapshot to the crash directory when
+// the server panics or exits on a fatal error
+func installCrashReporter(logger *common.CustomLogger) {
+	// The snapshot includes the configuration, with its secrets redacted
+	appConfig, _ := common.LoadConfig("")
+	dir := common.ResolveOutputPaths().CrashDir
+	reporter := crash.NewReporter(dir, appConfig)
+	if err := reporter.Install(); err != nil {
+		logger.Warn("Crash snapshots are disabled: %v", err)
+		return
+	}
+	crash.SetDefault(reporter)
+	logger.Info("Crash snapshots will be written to %s", dir)
+}
+
+// startHeartbeats sends heartbeats to connected clients, recording which
+// sessions stopped reading them, and disconnects those sessions when
+// sessions.cleanup_stale is set. It replaces the SSE transport's keep-alive.
+func startHeartbeats(logger *common.CustomLogger, mcpServer *server.MCPServer) {
+	sessionsConfig := common.SessionsConfig{HeartbeatSeconds: common.DefaultHeartbeatSeconds}
+	if config, err := common.LoadConfig(""); err == nil {
+		sessionsConfig = config.Sessions
+	}
+
+	session.SetUnregisterFunc(func(id string) {
+		mcpServer.UnregisterSession(context.Background(), id)
+	})
+	interval := time.Duration(sessionsConfig.HeartbeatSeconds) * time.Second
+	go session.RunHeartbeats(context.Background(), interval, sessionsConfig.CleanupStale, func(ids []string) {
+		logger.Warn("Disconnected %d stale sessions: %v", len(ids), ids)
+	})
+	logger.Info("Sending client heartbeats every %s (stale session cleanup: %t)", interval, sessionsConfig.CleanupStale)
+}
+
+// startRemoteWriteExporter starts forwarding the configured resource metrics
+// to a Prometheus remote_write endpoint when remote_write.url is set
+func startRemoteWriteExporter(logger *common.CustomLogger) {
+	config, err := common.LoadConfig("")
+	if err != nil || config.RemoteWrite.URL == "" {
+		return
+	}
+
+	resources := tools.NewOpsRampResourcesAPI(client.SharedClient(config))
+	exporter := remotewrite.NewExporter(config, resources)
+	go exporter.Run(client.WithPriority(context.Background(), client.PriorityBackground))
+
+	logger.Info("Exporting %d metrics of %d resources to remote_write endpoint %s",
+		len(config.RemoteWrite.Metrics), len(config.RemoteWrite.Resources), config.RemoteWrite.URL)
+}
+
+// startTracing starts exporting traces when an OTLP endpoint is configured
+// and returns a function that flushes the remaining spans. Tracing is
+// optional: on failure the server continues without it.
+func startTracing(logger *common.CustomLogger) func() {
+	config, err := common.ResolveTracingConfig()
+	if err != nil {
+		logger.Warn("Tracing disabled: %v", err)
+		return nil
+	}
+	shutdown, err := tracing.Start(context.Background(), config, ServerVersion)
+	if err != nil {
+		logger.Warn("Tracing disabled: %v", err)
+		return nil
+	}
+	if config.Endpoint == "" {
+		return nil
+	}
+
+	logger.Info("Exporting traces to %s as %s (sample ratio %g)", config.Endpoint, config.ServiceName, config.SampleRatio)
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			logger.Warn("Failed to flush traces: %v", err)
+		}
+	}
+}
+
 // createMCPServerComponents creates all MCP server components
 func createMCPServerComponents(config *ServerConfig) (*MCPServerComponents, error) {
+	// Track connected sessions and drop per-session state when a client disconnects
+	hooks := &server.Hooks{}
+	hooks.AddOnRegisterSession(func(ctx context.Context, clientSession server.ClientSession) {
+		session.MarkConnected(clientSession.SessionID())
+		session.TrackClient(clientSession)
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, clientSession server.ClientSession) {
+		session.Remove(clientSession.SessionID())
+	})
+
+	// Describe each tool in tools/list and page the catalog
+	hooks.AddAfterListTools(tools.CatalogListToolsHook)
+
+	// Describe this deployment's tools, limits and tenants in the initialize
+	// response; the text is built once every tool is registered
+	var instructions string
+	hooks.AddAfterInitialize(func(ctx context.Context, id any, message *mcpgo.InitializeRequest, result *mcpgo.InitializeResult) {
+		result.Instructions = instructions
+	})
+
 	// Create MCP server
-	mcpServer := server.NewMCPServer("HPE OpsRamp MCP", "1.0.0")
+	mcpServer := server.NewMCPServer("HPE OpsRamp MCP", ServerVersion,
+		server.WithHooks(hooks),
+		server.WithLogging(),
+		server.WithResourceCapabilities(false, false),
+		server.WithToolHandlerMiddleware(tools.InFlightMiddleware),
+		server.WithToolHandlerMiddleware(tools.TracingMiddleware),
+		server.WithToolHandlerMiddleware(tools.InstrumentationMiddleware),
+		server.WithToolHandlerMiddleware(tools.ClientLoggingMiddleware),
+		server.WithToolHandlerMiddleware(tools.SessionContextMiddleware),
+		server.WithToolHandlerMiddleware(tools.ArgumentCoercionMiddleware),
+		server.WithToolHandlerMiddleware(tools.ToolHooksMiddleware),
+		server.WithToolHandlerMiddleware(tools.DeadLetterMiddleware),
+		server.WithToolHandlerMiddleware(tools.ExamplesMiddleware),
+		server.WithToolHandlerMiddleware(tools.BudgetMiddleware),
+		server.WithToolHandlerMiddleware(tools.SuggestionsMiddleware),
+	)
 
 	// Register tools
 	registeredTools := make([]string, 0)
@@ -125,19 +365,93 @@ func createMCPServerComponents(config *ServerConfig) (*MCPServerComponents, erro
 	// Register integrations tool
 	integrationsTool, integrationsHandler := tools.NewIntegrationsMcpTool()
 	mcpServer.AddTool(integrationsTool, integrationsHandler)
+	tools.RegisterToolSchema(integrationsTool)
+	tools.RegisterToolHandler(integrationsTool, integrationsHandler)
 	registeredTools = append(registeredTools, integrationsTool.Name)
 	config.Logger.Info("Registered tool: %s", integrationsTool.Name)
 
 	// Register resources tool
 	resourcesTool, resourcesHandler := tools.NewResourcesMcpTool()
 	mcpServer.AddTool(resourcesTool, resourcesHandler)
+	tools.RegisterToolSchema(resourcesTool)
+	tools.RegisterToolHandler(resourcesTool, resourcesHandler)
 	registeredTools = append(registeredTools, resourcesTool.Name)
 	config.Logger.Info("Registered tool: %s", resourcesTool.Name)
 
+	// Register alerts tool
+	alertsTool, alertsHandler := tools.NewAlertsMcpTool()
+	mcpServer.AddTool(alertsTool, alertsHandler)
+	tools.RegisterToolSchema(alertsTool)
+	tools.RegisterToolHandler(alertsTool, alertsHandler)
+	registeredTools = append(registeredTools, alertsTool.Name)
+	config.Logger.Info("Registered tool: %s", alertsTool.Name)
+
+	// Register usage tool
+	usageTool, usageHandler := tools.NewUsageMcpTool()
+	mcpServer.AddTool(usageTool, usageHandler)
+	tools.RegisterToolSchema(usageTool)
+	tools.RegisterToolHandler(usageTool, usageHandler)
+	registeredTools = append(registeredTools, usageTool.Name)
+	config.Logger.Info("Registered tool: %s", usageTool.Name)
+
+	// Register partner-scoped clients tool
+	clientsTool, clientsHandler := tools.NewClientsMcpTool()
+	mcpServer.AddTool(clientsTool, clientsHandler)
+	tools.RegisterToolSchema(clientsTool)
+	tools.RegisterToolHandler(clientsTool, clientsHandler)
+	registeredTools = append(registeredTools, clientsTool.Name)
+	config.Logger.Info("Registered tool: %s", clientsTool.Name)
+
+	// Register custom monitors tool
+	monitorsTool, monitorsHandler := tools.NewMonitorsMcpTool()
+	mcpServer.AddTool(monitorsTool, monitorsHandler)
+	tools.RegisterToolSchema(monitorsTool)
+	tools.RegisterToolHandler(monitorsTool, monitorsHandler)
+	registeredTools = append(registeredTools, monitorsTool.Name)
+	config.Logger.Info("Registered tool: %s", monitorsTool.Name)
+
+	// Register metrics tool
+	metricsTool, metricsHandler := tools.NewMetricsMcpTool()
+	mcpServer.AddTool(metricsTool, metricsHandler)
+	tools.RegisterToolSchema(metricsTool)
+	tools.RegisterToolHandler(metricsTool, metricsHandler)
+	registeredTools = append(registeredTools, metricsTool.Name)
+	config.Logger.Info("Registered tool: %s", metricsTool.Name)
+
+	// Register session context tool
+	sessionTool, sessionHandler := tools.NewSessionMcpTool()
+	mcpServer.AddTool(sessionTool, sessionHandler)
+	tools.RegisterToolSchema(sessionTool)
+	tools.RegisterToolHandler(sessionTool, sessionHandler)
+	registeredTools = append(registeredTools, sessionTool.Name)
+	config.Logger.Info("Registered tool: %s", sessionTool.Name)
+
+	// Register result utility tool
+	utilityTool, utilityHandler := tools.NewUtilityMcpTool()
+	mcpServer.AddTool(utilityTool, utilityHandler)
+	tools.RegisterToolSchema(utilityTool)
+	tools.RegisterToolHandler(utilityTool, utilityHandler)
+	registeredTools = append(registeredTools, utilityTool.Name)
+	config.Logger.Info("Registered tool: %s", utilityTool.Name)
+
+	// Register admin tool
+	adminTool, adminHandler := tools.NewAdminMcpTool()
+	mcpServer.AddTool(adminTool, adminHandler)
+	tools.RegisterToolSchema(adminTool)
+	tools.RegisterToolHandler(adminTool, adminHandler)
+	registeredTools = append(registeredTools, adminTool.Name)
+	config.Logger.Info("Registered tool: %s", adminTool.Name)
+
+	// Serve exported results as MCP resources
+	exportTemplate, exportHandler := tools.NewExportResourceTemplate()
+	mcpServer.AddResourceTemplate(exportTemplate, exportHandler)
+
+	// Without a config the instructions describe only the tools
+	appConfig, _ := common.LoadConfig("")
+	instructions = tools.ServerInstructions(appConfig, registeredTools)
+
 	// Create SSE server with appropriate options for MCP
 	sseOptions := []server.SSEOption{
-		server.WithKeepAlive(true),
-		server.WithKeepAliveInterval(30 * time.Second),
 		server.WithMessageEndpoint("/mcp-message"),
 		server.WithSSEEndpoint("/sse"),
 		server.WithUseFullURLForMessageEndpoint(true),
@@ -148,44 +462,116 @@ func createMCPServerComponents(config *ServerConfig) (*MCPServerComponents, erro
 	config.Logger.Debug("SSE server created with %d tools", len(registeredTools))
 	config.Logger.Debug("Registered tools: %v", registeredTools)
 
+	// Create the Streamable HTTP transport for clients that no longer use SSE
+	streamableHTTP := server.NewStreamableHTTPServer(mcpServer)
+
+	// Transports log as one module so their level can be changed together
+	transportLogger := config.Logger.WithModule(common.ModuleTransport)
+
 	// Create MCP Inspector compatibility handler
-	inspectorHandler := mcp.NewInspectorHandler(mcpServer, config.Logger)
+	inspectorHandler := mcp.NewInspectorHandler(mcpServer, transportLogger)
+	inspectorHandler.SetInstructions(instructions)
+
+	// Create long-poll transport for clients behind proxies that strip SSE
+	longPollHandler := mcp.NewLongPollHandler(mcpServer, transportLogger)
+
+	// Create WebSocket transport for clients behind proxies that buffer SSE
+	webSocketHandler := mcp.NewWebSocketHandler(mcpServer, transportLogger)
 
 	// Create HTTP handlers
-	httpHandlers := handlers.NewHTTPHandlers(mcpServer, sseServer, config.Logger, config.StartTime, registeredTools)
+	httpHandlers := handlers.NewHTTPHandlers(mcpServer, sseServer, transportLogger, config.StartTime, registeredTools)
+	httpHandlers.SetStreamableHTTP(streamableHTTP)
 
 	return &MCPServerComponents{
 		MCPServer:        mcpServer,
 		SSEServer:        sseServer,
+		StreamableHTTP:   streamableHTTP,
 		InspectorHandler: inspectorHandler,
+		LongPollHandler:  longPollHandler,
+		WebSocketHandler: webSocketHandler,
 		HTTPHandlers:     httpHandlers,
 		RegisteredTools:  registeredTools,
 	}, nil
 }
 
-// performStartupHealthCheck performs a real API call to verify connectivity
-func performStartupHealthCheck(logger *common.CustomLogger) error {
-	// Load configuration
+// Startup health check retry policy: attempts and the initial delay between
+// them, which doubles after each attempt up to client.MaxStartupBackoff
+const (
+	startupCheckAttempts = 5
+	startupCheckBackoff  = 2 * time.Second
+)
+
+// registerTenants registers every configured tenant so calls can be served
+// before their health is known
+func registerTenants(logger *common.CustomLogger) (*client.TenantRegistry, error) {
 	config, err := common.LoadConfig("")
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Create the integrations API
-	integrationsAPI, err := tools.NewOpsRampIntegrationsAPI(&config.OpsRamp)
+	logger.Info("Outbound requests restricted to: %s", strings.Join(client.NewEgressPolicy(config).Hosts(), ", "))
+
+	registry := client.NewTenantRegistry(config)
+	client.SetTenantRegistry(registry)
+
+	// Tenants onboarded through the admin tool outlive restarts in the state store
+	restored, err := tools.RestoreTenants(registry, store.Default())
 	if err != nil {
-		return fmt.Errorf("failed to create integrations API: %w", err)
+		logger.Warn("Failed to restore onboarded tenants: %v", err)
+	} else if len(restored) > 0 {
+		logger.Info("Restored onboarded tenants: %s", strings.Join(restored, ", "))
 	}
+	return registry, nil
+}
+
+// performStartupHealthCheck probes every tenant with bounded retries and then
+// starts background health probing. It runs after the server starts serving;
+// unreachable tenants are marked degraded rather than failing startup.
+func performStartupHealthCheck(logger *common.CustomLogger, registry *client.TenantRegistry) {
+	failures := registry.CheckStartup(context.Background(), startupCheckAttempts, startupCheckBackoff)
 
-	// Make a real API call (e.g., list integrations)
-	integrations, err := integrationsAPI.List(context.Background())
+	// Keep probing so degraded tenants recover automatically
+	registry.StartProbing(context.Background(), client.DefaultProbeInterval)
+
+	names := registry.Names()
+	if len(failures) > 0 {
+		logCredentialFailures(logger, registry, failures)
+	}
+
+	switch registry.StartupStatus().Status {
+	case client.StartupCheckFailed:
+		logger.Warn("Startup health check failed: all %d tenants unreachable; see the credential checks above", len(names))
+	case client.StartupCheckDegraded:
+		logger.Warn("Startup health check: %d of %d tenants degraded; calls to them will fail until they recover", len(failures), len(names))
+	default:
+		logger.Info("Startup health check passed: %d tenants reachable", len(names))
+	}
+}
+
+// logCredentialFailures validates the credentials of tenants that failed their
+// probe and logs the specific cause and fix for each one
+func logCredentialFailures(logger *common.CustomLogger, registry *client.TenantRegistry, failures map[string]error) {
+	failed := make([]string, 0, len(failures))
+	for name := range failures {
+		failed = append(failed, name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	checks, err := registry.ValidateCredentials(ctx, failed...)
 	if err != nil {
-		return fmt.Errorf("startup health check failed: %w", err)
+		logger.Error("Failed to validate tenant credentials: %v", err)
+		return
 	}
 
-	// Log success
-	logger.Info("Startup health check passed: successfully listed %d integrations", len(integrations))
-	return nil
+	for _, check := range checks {
+		if check.Valid {
+			logger.Warn("Tenant %s: credentials are valid but the probe failed: %v", check.Tenant, failures[check.Tenant])
+			continue
+		}
+		logger.Error("Tenant %s: credential check failed at %s (%s): %s. %s",
+			check.Tenant, check.Step, check.Failure, check.Message, check.Hint)
+	}
 }
 
 // createHTTPServer creates and configures the HTTP server
@@ -197,6 +583,8 @@ func createHTTPServer(config *ServerConfig, components *MCPServerComponents) *ht
 	mux.HandleFunc("/health", components.HTTPHandlers.HealthHandler)
 	mux.HandleFunc("/readiness", components.HTTPHandlers.ReadinessHandler)
 	mux.HandleFunc("/debug", components.HTTPHandlers.DebugHandler)
+	mux.HandleFunc("/metrics", components.HTTPHandlers.MetricsHandler)
+	// /mcp serves Streamable HTTP clients and sessionless JSON requests
 	mux.HandleFunc("/mcp", components.HTTPHandlers.MCPHandler)
 
 	// Register SSE endpoint (native MCP-Go implementation)
@@ -208,15 +596,58 @@ func createHTTPServer(config *ServerConfig, components *MCPServerComponents) *ht
 	// Register MCP Inspector compatibility endpoint (for direct connections)
 	mux.HandleFunc("/message", components.InspectorHandler.HandleMessage)
 
+	// Register long-poll fallback endpoints for environments that block SSE
+	mux.HandleFunc("/poll", components.LongPollHandler.Poll)
+	mux.HandleFunc("/poll/connect", components.LongPollHandler.Connect)
+	mux.HandleFunc("/poll/message", components.LongPollHandler.Message)
+
+	// Register the WebSocket endpoint for environments that buffer SSE
+	mux.Handle("/ws", components.WebSocketHandler)
+
+	// Register the OpsRamp alert webhook when a webhook secret is configured
+	if webhook := openAlertWebhook(config.Logger); webhook != nil {
+		mux.Handle("/webhooks/alerts", webhook)
+	}
+
 	config.Logger.Debug("HTTP routes configured")
 
+	// Trace MCP messages, once the client is authenticated
+	handler := handlers.Tracing(mux)
+
+	// Require authentication on the endpoints that need it, when configured
+	if auth := handlers.NewAuthenticator(config.Auth, config.Logger); auth != nil {
+		handler = auth.Middleware(handler)
+		config.Logger.Info("HTTP authentication enabled")
+	} else {
+		config.Logger.Warn("HTTP authentication is not configured; every endpoint is open")
+	}
+
+	// Wrap all routes with the security headers and the access log
+	handler = handlers.SecurityHeaders(handler, config.HTTP.HSTSMaxAgeSeconds)
+	if accessLog := openAccessLog(config.Logger); accessLog != nil {
+		handler = accessLog.Middleware(handler)
+	}
+
+	tlsConfig, err := handlers.ServerTLSConfig(config.HTTP.TLS)
+	if err != nil {
+		config.Logger.Fatal("Failed to configure TLS: %v", err)
+	}
+
 	// Create HTTP server
-	portString := fmt.Sprintf(":%d", config.Port)
-	config.Logger.Info("Server listening on %s", portString)
+	address := config.HTTP.Listen
+	if address == "" {
+		address = fmt.Sprintf(":%d", config.Port)
+	}
+	if tlsConfig != nil {
+		config.Logger.Info("Server listening on %s with TLS", address)
+	} else {
+		config.Logger.Info("Server listening on %s", address)
+	}
 
 	return &http.Server{
-		Addr:    portString,
-		Handler: mux,
+		Addr:      address,
+		Handler:   handler,
+		TLSConfig: tlsConfig,
 		// Increase timeouts for long-running operations
 		ReadTimeout:  120 * time.Second,
 		WriteTimeout: 120 * time.Second,
@@ -224,12 +655,81 @@ func createHTTPServer(config *ServerConfig, components *MCPServerComponents) *ht
 	}
 }
 
+// openAccessLog opens the HTTP access log. Access logging is optional: on
+// failure the server continues without it.
+func openAccessLog(logger *common.CustomLogger) *handlers.AccessLogger {
+	path := common.ResolveOutputPaths().AccessLogPath
+	var sampling map[string]float64
+	var labels map[string]string
+	if config, err := common.LoadConfig(""); err == nil {
+		sampling = config.AccessLog.Sampling
+		labels = config.ServerTelemetryLabels()
+	}
+
+	accessLog, err := handlers.OpenAccessLog(path, sampling)
+	if err != nil {
+		logger.Warn("Access log unavailable, continuing without it: %v", err)
+		return nil
+	}
+	accessLog.SetLabels(labels)
+
+	logger.Info("Access log: %s", path)
+	return accessLog
+}
+
+// openAlertWebhook creates the alert webhook handler. The webhook is
+// optional: without a configured secret the endpoint is not registered.
+func openAlertWebhook(logger *common.CustomLogger) *handlers.AlertWebhookHandler {
+	config, err := common.LoadConfig("")
+	if err != nil {
+		return nil
+	}
+	webhook := handlers.NewAlertWebhookHandler(config, logger)
+	if webhook != nil {
+		logger.Info("Alert webhook: /webhooks/alerts")
+	}
+	return webhook
+}
+
+// shutdownNoticeDelay gives the transports time to deliver the shutdown
+// notification before connections are closed
+const shutdownNoticeDelay = 2 * time.Second
+
+// announceShutdown sends a shutdown status notification to every connected
+// client, with the configured downtime as the time to wait before
+// reconnecting, so agent frameworks can pause work instead of surfacing a
+// dropped connection. /readiness reports the server unready from then on.
+func announceShutdown(logger *common.CustomLogger, received os.Signal) {
+	downtime := common.DefaultShutdownDowntimeSeconds
+	if config, err := common.LoadConfig(""); err == nil {
+		downtime = config.Sessions.ShutdownDowntimeSeconds
+	}
+
+	delivered := session.BroadcastStatus(session.StatusNotice{
+		Status:                  session.StatusShuttingDown,
+		Reason:                  fmt.Sprintf("The server is shutting down (%s); reconnect after the downtime", received),
+		ExpectedDowntimeSeconds: downtime,
+		RetryAfterSeconds:       downtime,
+	})
+	logger.Info("Announced shutdown to %d connected clients", delivered)
+	if delivered > 0 {
+		time.Sleep(shutdownNoticeDelay)
+	}
+}
+
 // startServer starts the HTTP server and handles graceful shutdown
 func startServer(config *ServerConfig, httpServer *http.Server) {
 	// Start the server in a goroutine
 	go func() {
 		config.Logger.Info("Starting HTTP server on %s", httpServer.Addr)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if httpServer.TLSConfig != nil {
+			// The certificates come from the TLS config, not from files
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			config.Logger.Fatal("Failed to start server: %v", err)
 		}
 	}()
@@ -237,7 +737,10 @@ func startServer(config *ServerConfig, httpServer *http.Server) {
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	received := <-quit
+
+	// Tell connected clients before their connections close
+	announceShutdown(config.Logger, received)
 
 	// Create shutdown context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -0,0 +1,122 @@
+// Command validate checks tool call payloads against the tool schemas of the
+// OpsRamp MCP server without a server or OpsRamp credentials, prints each
+// normalized call and exits non-zero when any payload has a problem. It is
+// meant for CI over a library of canned agent prompts.
+//
+//	go run ./cmd/validate prompts/*.json
+//
+// A payload is a JSON object {"tool": ..., "action": ..., "arguments": {...}},
+// or an array of them. The action may also be given in the arguments. With no
+// files, or "-", payloads are read from stdin. The normalized call has the
+// numeric and boolean strings coerced as the server would, and its
+// arguments sorted.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/tools"
+)
+
+func main() {
+	quiet := flag.Bool("quiet", false, "Only report problems, without printing the normalized calls")
+	flag.Parse()
+
+	// Tool definitions log through the default logger, which writes to stdout
+	common.GetLogger().SetLevel(common.FATAL)
+
+	sources := flag.Args()
+	if len(sources) == 0 {
+		sources = []string{"-"}
+	}
+
+	definitions := tools.ToolDefinitions()
+	failed, checked := 0, 0
+	for _, source := range sources {
+		calls, err := readPayloads(source)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", source, err)
+			failed++
+			continue
+		}
+		for i, call := range calls {
+			checked++
+			name := source
+			if source == "-" {
+				name = "stdin"
+			}
+			if len(calls) > 1 {
+				name = fmt.Sprintf("%s[%d]", name, i)
+			}
+			if !validate(definitions, name, call, *quiet) {
+				failed++
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%d payloads checked, %d with problems\n", checked, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// readPayloads reads the tool calls of a file, or of stdin for "-". The file
+// holds one call or an array of them.
+func readPayloads(source string) ([]tools.ToolCall, error) {
+	var data []byte
+	var err error
+	if source == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var calls []tools.ToolCall
+		if err := strictUnmarshal(trimmed, &calls); err != nil {
+			return nil, fmt.Errorf("invalid payload array: %w", err)
+		}
+		return calls, nil
+	}
+	var call tools.ToolCall
+	if err := strictUnmarshal(trimmed, &call); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	return []tools.ToolCall{call}, nil
+}
+
+// strictUnmarshal decodes JSON, rejecting fields a tool call does not have
+func strictUnmarshal(data []byte, value interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(value)
+}
+
+// validate checks one call, printing the normalized call to stdout unless
+// quiet and its problems to stderr, and reports whether it has none
+func validate(definitions []mcp.Tool, name string, call tools.ToolCall, quiet bool) bool {
+	normalized, problems := tools.ValidateToolCall(definitions, call)
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", name, problem)
+	}
+	if normalized != nil && !quiet {
+		pretty, err := json.MarshalIndent(normalized, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			return false
+		}
+		fmt.Println(string(pretty))
+	}
+	return len(problems) == 0
+}
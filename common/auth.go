@@ -2,20 +2,60 @@ package common
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// OAuth2Config holds configuration for OAuth2.0
+// OAuth2Config holds configuration for OAuth2.0. SecondaryClientID and
+// SecondaryClientSecret are optional standby credentials, used once the
+// authorization server or OpsRamp stops accepting the primary ones.
 type OAuth2Config struct {
 	ClientID     string
 	ClientSecret string
 	TokenURL     string
 	Scopes       []string
+
+	SecondaryClientID     string
+	SecondaryClientSecret string
+}
+
+// credentialFailovers counts the switches between primary and secondary
+// credentials of every AuthClient
+var credentialFailovers atomic.Int64
+
+// CredentialFailoverCount returns the number of times since startup that an
+// AuthClient switched credentials because the ones in use were rejected
+func CredentialFailoverCount() int64 {
+	return credentialFailovers.Load()
+}
+
+// tokenStatusError is a token request answered with an error status
+type tokenStatusError struct {
+	StatusCode int
+}
+
+func (e *tokenStatusError) Error() string {
+	return fmt.Sprintf("token request returned status %d", e.StatusCode)
+}
+
+// credentialsRejected reports whether a token request failed because the
+// authorization server refused the credentials, rather than being unreachable
+func credentialsRejected(err error) bool {
+	var statusErr *tokenStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	switch statusErr.StatusCode {
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	}
+	return false
 }
 
 // TokenResponse represents the OAuth2.0 token response
@@ -35,6 +75,10 @@ type AuthClient struct {
 	mu          sync.Mutex
 	httpClient  *http.Client
 	logger      *CustomLogger
+	// static clients never fetch tokens, so their token is never invalidated
+	static bool
+	// secondaryActive is set while tokens are fetched with the secondary credentials
+	secondaryActive bool
 }
 
 // NewAuthClient creates a new AuthClient
@@ -49,6 +93,35 @@ func NewAuthClient(config OAuth2Config) *AuthClient {
 	}
 }
 
+// NewStaticAuthClient creates an AuthClient that always returns the given
+// token and never contacts an authorization server
+func NewStaticAuthClient(token string) *AuthClient {
+	return &AuthClient{
+		token:       token,
+		tokenExpiry: time.Now().AddDate(100, 0, 0),
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		logger:      GetLogger(),
+		static:      true,
+	}
+}
+
+// Invalidate discards the cached token if it is still the given token, so
+// the next GetToken fetches a new one. Passing the rejected token keeps
+// concurrent requests that fail with it from discarding its replacement.
+// It reports whether the token was discarded.
+func (a *AuthClient) Invalidate(token string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.static || token == "" || a.token != token {
+		return false
+	}
+	a.token = ""
+	a.tokenExpiry = time.Time{}
+	a.logger.Info("Discarded the cached token after OpsRamp rejected it")
+	return true
+}
+
 // GetToken retrieves a valid OAuth2.0 token, refreshing if necessary
 func (a *AuthClient) GetToken() (string, error) {
 	a.mu.Lock()
@@ -62,12 +135,23 @@ func (a *AuthClient) GetToken() (string, error) {
 
 	a.logger.Info("Token expired or not present, fetching new token")
 
-	// We need to get a new token
-	tokenResp, err := a.fetchNewToken()
+	// We need to get a new token, from the standby credentials if the
+	// authorization server refuses the ones in use
+	clientID, clientSecret := a.credentials(a.secondaryActive)
+	tokenResp, err := a.fetchNewToken(clientID, clientSecret)
+	if err != nil && credentialsRejected(err) && a.hasSecondary() {
+		clientID, clientSecret = a.credentials(!a.secondaryActive)
+		if standbyResp, standbyErr := a.fetchNewToken(clientID, clientSecret); standbyErr == nil {
+			a.switchCredentials(err)
+			tokenResp, err = standbyResp, nil
+		}
+	}
 	if err != nil {
+		TokenRefreshes.Inc("failure")
 		a.logger.Error("Failed to fetch token: %v", err)
 		return "", fmt.Errorf("failed to fetch token: %w", err)
 	}
+	TokenRefreshes.Inc("success")
 
 	// Store the token and its expiry time
 	a.token = tokenResp.AccessToken
@@ -79,15 +163,64 @@ func (a *AuthClient) GetToken() (string, error) {
 	return a.token, nil
 }
 
+// FailOver switches to the standby credentials after OpsRamp rejected a
+// fresh token fetched with the ones in use, discarding that token. It
+// reports whether there was a switch; only the first caller that saw the
+// rejected token switches.
+func (a *AuthClient) FailOver(token string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.static || !a.hasSecondary() || token == "" || a.token != token {
+		return false
+	}
+	a.switchCredentials(fmt.Errorf("OpsRamp rejected a new token"))
+	a.token = ""
+	a.tokenExpiry = time.Time{}
+	return true
+}
+
+// UsingSecondary reports whether tokens are fetched with the secondary credentials
+func (a *AuthClient) UsingSecondary() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.secondaryActive
+}
+
+// hasSecondary reports whether standby credentials are configured
+func (a *AuthClient) hasSecondary() bool {
+	return a.Config.SecondaryClientID != "" && a.Config.SecondaryClientSecret != ""
+}
+
+// credentials returns the primary or secondary client ID and secret
+func (a *AuthClient) credentials(secondary bool) (string, string) {
+	if secondary {
+		return a.Config.SecondaryClientID, a.Config.SecondaryClientSecret
+	}
+	return a.Config.ClientID, a.Config.ClientSecret
+}
+
+// switchCredentials swaps the credentials in use, counting and logging the
+// switch so the rejected credentials get rotated. The caller holds mu.
+func (a *AuthClient) switchCredentials(reason error) {
+	from, to := "primary", "secondary"
+	if a.secondaryActive {
+		from, to = to, from
+	}
+	a.secondaryActive = !a.secondaryActive
+	credentialFailovers.Add(1)
+	a.logger.Warn("Switched from the %s to the %s OpsRamp credentials: %v; rotate the %s credentials", from, to, reason, from)
+}
+
 // fetchNewToken gets a new OAuth2.0 token from the authorization server
-func (a *AuthClient) fetchNewToken() (*TokenResponse, error) {
+func (a *AuthClient) fetchNewToken(clientID, clientSecret string) (*TokenResponse, error) {
 	a.logger.Debug("Preparing token request to %s", a.Config.TokenURL)
 
 	// Prepare the token request as form data (x-www-form-urlencoded)
 	formData := url.Values{}
 	formData.Set("grant_type", "client_credentials")
-	formData.Set("client_id", a.Config.ClientID)
-	formData.Set("client_secret", a.Config.ClientSecret)
+	formData.Set("client_id", clientID)
+	formData.Set("client_secret", clientSecret)
 
 	// Add scopes if specified
 	if len(a.Config.Scopes) > 0 {
@@ -123,7 +256,7 @@ func (a *AuthClient) fetchNewToken() (*TokenResponse, error) {
 	// Check for HTTP errors
 	if resp.StatusCode != http.StatusOK {
 		a.logger.Error("Token request returned non-OK status: %d", resp.StatusCode)
-		return nil, fmt.Errorf("token request returned status %d", resp.StatusCode)
+		return nil, &tokenStatusError{StatusCode: resp.StatusCode}
 	}
 
 	// Parse the response
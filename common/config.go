@@ -1,9 +1,14 @@
 package common
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"gopkg.in/yaml.v2"
@@ -11,17 +16,389 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	OpsRamp OpsRampConfig `yaml:"opsramp"`
+	OpsRamp   OpsRampConfig   `yaml:"opsramp"`
+	Tenants   []TenantConfig  `yaml:"tenants"`
+	Responses ResponsesConfig `yaml:"responses"`
+	Output    OutputConfig    `yaml:"output"`
+	State     StateConfig     `yaml:"state"`
+	AccessLog AccessLogConfig `yaml:"access_log"`
+	// Tools selects the backend serving each tool, keyed by tool name
+	Tools map[string]ToolBackendConfig `yaml:"tools"`
+	// Mock configures the data served by tools with the mock backend
+	Mock   MockConfig   `yaml:"mock"`
+	Egress EgressConfig `yaml:"egress"`
+	Locks  LocksConfig  `yaml:"locks"`
+	// RemoteWrite configures the optional Prometheus remote_write exporter
+	RemoteWrite RemoteWriteConfig `yaml:"remote_write"`
+	// ResourceTemplates are named resource creation templates, keyed by name
+	ResourceTemplates map[string]ResourceTemplate `yaml:"resource_templates"`
+	// Webhooks configures the inbound OpsRamp alert webhook
+	Webhooks WebhooksConfig `yaml:"webhooks"`
+	// Deletes configures the confirmation required for large bulk deletes
+	Deletes DeletesConfig `yaml:"deletes"`
+	// Sessions configures heartbeats to connected clients
+	Sessions SessionsConfig `yaml:"sessions"`
+
+	Concurrency ConcurrencyConfig `yaml:"concurrency"`
+	// Budgets bounds the OpsRamp API calls tool calls may make
+	Budgets BudgetsConfig `yaml:"budgets"`
+	// Catalog configures the tools/list response
+	Catalog CatalogConfig `yaml:"catalog"`
+	// HTTP configures the server's own listener
+	HTTP HTTPConfig `yaml:"http"`
+	// Auth requires clients of the HTTP endpoints to authenticate
+	Auth AuthConfig `yaml:"auth"`
+	// Tracing exports OpenTelemetry traces of requests and tool calls
+	Tracing TracingConfig `yaml:"tracing"`
+}
+
+// ResourceTemplate is a resource creation payload with {{variable}}
+// placeholders in its string values. Templates are defined in config or saved
+// to the state store, so they are tagged for both YAML and JSON.
+type ResourceTemplate struct {
+	Description string                      `yaml:"description" json:"description,omitempty"`
+	Variables   map[string]TemplateVariable `yaml:"variables" json:"variables"`
+	// Resource is a ResourceCreateRequest, e.g. {resourceType: Server, hostName: "{{hostname}}"}
+	Resource map[string]interface{} `yaml:"resource" json:"resource"`
+}
+
+// TemplateVariable declares a template placeholder. Format is "ip" or
+// "hostname"; Pattern is a regular expression the whole value must match.
+type TemplateVariable struct {
+	Description string `yaml:"description" json:"description,omitempty"`
+	Required    bool   `yaml:"required" json:"required,omitempty"`
+	Default     string `yaml:"default" json:"default,omitempty"`
+	Format      string `yaml:"format" json:"format,omitempty"`
+	Pattern     string `yaml:"pattern" json:"pattern,omitempty"`
+}
+
+// RemoteWriteConfig configures the exporter that periodically pulls metrics
+// for selected resources and forwards them to a Prometheus remote_write
+// endpoint. The exporter is disabled when URL is empty.
+type RemoteWriteConfig struct {
+	URL             string   `yaml:"url"`
+	IntervalSeconds int      `yaml:"interval_seconds"`
+	Resources       []string `yaml:"resources"`
+	Metrics         []string `yaml:"metrics"`
+	// Labels are added to every exported series, e.g. {source: opsramp}
+	Labels      map[string]string `yaml:"labels"`
+	BearerToken string            `yaml:"bearer_token"`
+	Username    string            `yaml:"username"`
+	Password    string            `yaml:"password"`
+}
+
+// DefaultRemoteWriteInterval is how often metrics are exported when no interval is configured
+const DefaultRemoteWriteInterval = 60
+
+// WebhooksConfig configures the endpoint that receives OpsRamp alert
+// webhooks and notifies the sessions watching the alerted resource. The
+// endpoint is disabled when Secret is empty; OpsRamp must send the secret in
+// the X-Webhook-Secret header.
+type WebhooksConfig struct {
+	Secret string `yaml:"secret"`
+	// AlertLinkTemplate builds the deep link sent with each alert; {tenant_url}
+	// and {id} are replaced with the tenant URL and the alert ID
+	AlertLinkTemplate string `yaml:"alert_link_template"`
+}
+
+// DeletesConfig controls the confirmation token that bulk deletes of more
+// than ConfirmationThreshold resources must present. Tokens are signed with
+// SigningKey, or with a random per-process key when it is empty; set it when
+// several replicas serve the same clients.
+type DeletesConfig struct {
+	ConfirmationThreshold int    `yaml:"confirmation_threshold"`
+	TokenTTLSeconds       int    `yaml:"token_ttl_seconds"`
+	SigningKey            string `yaml:"signing_key"`
+}
+
+// Delete confirmation defaults
+const (
+	DefaultDeleteConfirmationThreshold = 10
+	DefaultDeleteTokenTTL              = 300
+)
+
+// SessionsConfig controls the heartbeats sent to connected clients every
+// HeartbeatSeconds. Sessions whose clients stop reading them are listed by
+// the admin tool's staleSessions action, and disconnected automatically when
+// CleanupStale is set. ShutdownDowntimeSeconds is the downtime announced to
+// connected clients when the server shuts down, after which they should
+// reconnect.
+type SessionsConfig struct {
+	HeartbeatSeconds        int  `yaml:"heartbeat_seconds"`
+	CleanupStale            bool `yaml:"cleanup_stale"`
+	ShutdownDowntimeSeconds int  `yaml:"shutdown_downtime_seconds"`
+}
+
+// ConcurrencyConfig bounds the concurrent OpsRamp requests of each tenant.
+// Interactive requests, made for tool calls, and background requests, made
+// by bulk actions, paging scans, scheduled actions and exporters, have
+// separate pools, so background work cannot delay interactive calls.
+type ConcurrencyConfig struct {
+	Interactive int `yaml:"interactive"`
+	Background  int `yaml:"background"`
+}
+
+// Request concurrency defaults
+const (
+	DefaultInteractiveConcurrency = 16
+	DefaultBackgroundConcurrency  = 4
+)
+
+// BudgetsConfig bounds the OpsRamp API calls of tool calls over any hour,
+// as estimated before each call runs. A limit of 0 is unlimited. In trim
+// mode a call over budget that can be made smaller, such as a schema sample
+// or an impact analysis depth, is trimmed to fit; other calls are rejected.
+type BudgetsConfig struct {
+	SessionCallsPerHour int    `yaml:"session_calls_per_hour"`
+	CallsPerHour        int    `yaml:"calls_per_hour"`
+	Mode                string `yaml:"mode"`
+}
+
+// CatalogConfig configures tools/list. PageSize bounds the tools returned
+// per page; clients request the next page with the returned cursor.
+type CatalogConfig struct {
+	PageSize int `yaml:"page_size"`
+}
+
+// DefaultCatalogPageSize is the default number of tools per tools/list page
+const DefaultCatalogPageSize = 50
+
+// HTTPConfig configures the server's listener. Listen is the address to bind,
+// e.g. 127.0.0.1:8080; it defaults to every interface on PORT. Every response
+// carries standard security headers, and responses over TLS also carry HSTS
+// for HSTSMaxAgeSeconds (default one year, -1 to leave it out).
+type HTTPConfig struct {
+	Listen            string    `yaml:"listen"`
+	TLS               TLSConfig `yaml:"tls"`
+	HSTSMaxAgeSeconds int       `yaml:"hsts_max_age_seconds"`
+}
+
+// TLSConfig enables TLS on the listener, with a certificate and key from
+// CertFile and KeyFile, or with certificates obtained from an ACME CA such as
+// Let's Encrypt for ACMEDomains. ACME certificates are cached in
+// ACMECacheDir (default the acme directory under the output directory) and
+// use the TLS-ALPN-01 challenge, so the listener must be reachable on port
+// 443 of each domain.
+type TLSConfig struct {
+	CertFile     string   `yaml:"cert_file"`
+	KeyFile      string   `yaml:"key_file"`
+	ACMEDomains  []string `yaml:"acme_domains"`
+	ACMEEmail    string   `yaml:"acme_email"`
+	ACMECacheDir string   `yaml:"acme_cache_dir"`
+	// ACMEDirectoryURL selects another CA, e.g. the Let's Encrypt staging
+	// directory; the default is Let's Encrypt production
+	ACMEDirectoryURL string `yaml:"acme_directory_url"`
+}
+
+// Enabled reports whether the listener serves TLS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || len(c.ACMEDomains) > 0
+}
+
+// DefaultHSTSMaxAgeSeconds is the default HSTS max-age of one year
+const DefaultHSTSMaxAgeSeconds = 31536000
+
+// AuthConfig requires clients of the HTTP endpoints to present a static API
+// key in X-API-Key, a static bearer token, or a bearer token that an OAuth2
+// introspection endpoint (RFC 7662) reports active. Authentication is off
+// until a credential or an introspection URL is configured. Endpoints sets
+// the mode of a path, "required" or "none", which also covers the paths
+// below it; /health, /readiness and /webhooks/alerts, which checks its own
+// secret, default to none and every other path to required.
+type AuthConfig struct {
+	APIKeys       []AuthCredential    `yaml:"api_keys"`
+	BearerTokens  []AuthCredential    `yaml:"bearer_tokens"`
+	Introspection IntrospectionConfig `yaml:"introspection"`
+	Endpoints     map[string]string   `yaml:"endpoints"`
+}
+
+// AuthCredential is a named static API key or bearer token. The name
+// identifies the caller in logs; the secret is never logged.
+type AuthCredential struct {
+	Name   string `yaml:"name"`
+	Secret string `yaml:"secret"`
+}
+
+// IntrospectionConfig validates bearer tokens with an OAuth2 token
+// introspection endpoint, authenticating with ClientID and ClientSecret.
+// With RequiredScope set, a token must carry that scope. Active tokens are
+// cached for CacheSeconds (default 60), and never past their expiry.
+type IntrospectionConfig struct {
+	URL           string `yaml:"url"`
+	ClientID      string `yaml:"client_id"`
+	ClientSecret  string `yaml:"client_secret"`
+	RequiredScope string `yaml:"required_scope"`
+	CacheSeconds  int    `yaml:"cache_seconds"`
+}
+
+// Enabled reports whether the HTTP endpoints require authentication
+func (c AuthConfig) Enabled() bool {
+	return len(c.APIKeys) > 0 || len(c.BearerTokens) > 0 || c.Introspection.URL != ""
+}
+
+// Endpoint authentication modes
+const (
+	AuthRequired = "required"
+	AuthNone     = "none"
+)
+
+// errInvalidAuthConfig marks a config file rejected for its auth section,
+// which must stop the server rather than leave it unauthenticated
+var errInvalidAuthConfig = errors.New("auth configuration validation failed")
+
+// DefaultIntrospectionCacheSeconds is how long an active introspected
+// token is trusted by default
+const DefaultIntrospectionCacheSeconds = 60
+
+// TracingConfig exports OpenTelemetry traces over OTLP/HTTP to Endpoint,
+// the collector's traces URL such as http://otel-collector:4318/v1/traces;
+// tracing is off without it. Headers are sent with every export, e.g. an
+// API key for a hosted backend, so their values are redacted from config
+// dumps whatever their names. SampleRatio is the fraction of new traces
+// recorded (default 1); a request that carries a sampled W3C traceparent is
+// always recorded.
+type TracingConfig struct {
+	Endpoint    string            `yaml:"endpoint"`
+	Headers     map[string]string `yaml:"headers"`
+	ServiceName string            `yaml:"service_name"`
+	SampleRatio float64           `yaml:"sample_ratio"`
+}
+
+// DefaultTracingServiceName is the service name of exported spans
+const DefaultTracingServiceName = "or-mcp-server"
+
+// Budget modes
+const (
+	BudgetModeTrim   = "trim"
+	BudgetModeReject = "reject"
+)
+
+// DefaultHeartbeatSeconds is the default interval between client heartbeats
+const DefaultHeartbeatSeconds = 30
+
+// DefaultShutdownDowntimeSeconds is the default downtime announced at shutdown
+const DefaultShutdownDowntimeSeconds = 30
+
+// DefaultAlertLinkTemplate links to an alert in the OpsRamp portal
+const DefaultAlertLinkTemplate = "{tenant_url}/portal/alert-browser/alert-details/{id}"
+
+// LocksConfig controls the advisory locks taken by bulk mutations. Mode is
+// "queue" (wait up to WaitSeconds for the lock) or "fail" (fail immediately).
+type LocksConfig struct {
+	Mode        string `yaml:"mode"`
+	WaitSeconds int    `yaml:"wait_seconds"`
+}
+
+// EgressConfig holds the outbound host allowlist. Tenant and auth hosts are
+// always allowed; AllowedHosts adds further hosts such as notification
+// webhooks. A leading "*." matches any subdomain.
+type EgressConfig struct {
+	AllowedHosts []string `yaml:"allowed_hosts"`
+}
+
+// ResponsesConfig holds defaults for how tool results are rendered
+type ResponsesConfig struct {
+	LLMCompact bool `yaml:"llm_compact"`
+	// Locale is the default language of generated summaries, e.g. "de"
+	Locale string `yaml:"locale"`
+	// HandleThresholdBytes is the rendered size above which a result is
+	// returned as a result handle plus summary instead of inline (0 uses the default)
+	HandleThresholdBytes int `yaml:"handle_threshold_bytes"`
+	// MaxTextLength is the length in characters above which text fields of a
+	// result are truncated with a marker (0 uses the default, -1 disables)
+	MaxTextLength int `yaml:"max_text_length"`
+	// Fields restricts the fields tool results may contain, by tool name.
+	// The "*" entry applies to every tool.
+	Fields map[string]FieldPolicy `yaml:"fields"`
+}
+
+// FieldPolicy lists the result fields a tool may or may not return. Names
+// match JSON field names at any depth, case-insensitively, and may use
+// wildcards such as "ip*". Denied fields are always removed; when Allow is
+// set, only the allowed fields are kept.
+type FieldPolicy struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// TenantConfig describes an additional OpsRamp tenant served in multi-tenant mode
+type TenantConfig struct {
+	Name          string `yaml:"name"`
+	OpsRampConfig `yaml:",inline"`
 }
 
+// OutputConfig sets where the server writes files. Dir is the base of every
+// output location that is not set explicitly, so a read-only container
+// filesystem only needs Dir pointed at a writable volume.
+type OutputConfig struct {
+	Dir    string `yaml:"dir"`
+	LogDir string `yaml:"log_dir"`
+	// CrashDir receives the health snapshot written when the server crashes
+	CrashDir string `yaml:"crash_dir"`
+}
+
+// StateConfig holds the local state store configuration
+type StateConfig struct {
+	Path string `yaml:"path"`
+}
+
+// AccessLogConfig holds the HTTP access log configuration
+type AccessLogConfig struct {
+	Path string `yaml:"path"`
+	// Sampling maps a path prefix to the fraction of requests logged (0 to 1)
+	Sampling map[string]float64 `yaml:"sampling"`
+}
+
+// Tool backends
+const (
+	// BackendLive serves a tool from the OpsRamp API
+	BackendLive = "live"
+	// BackendMock serves a tool from built-in sample data, or from the
+	// fixtures in mock.fixtures
+	BackendMock = "mock"
+	// BackendSnapshot replays recorded OpsRamp responses from a snapshot file
+	BackendSnapshot = "snapshot"
+)
+
+// MockConfig configures the mock backend. Fixtures is a directory of
+// resources, device_groups, integrations and alerts files (YAML or JSON)
+// served instead of the built-in sample data.
+type MockConfig struct {
+	Fixtures string `yaml:"fixtures"`
+}
+
+// ToolBackendConfig selects where a tool's data comes from
+type ToolBackendConfig struct {
+	Backend  string `yaml:"backend"`
+	Snapshot string `yaml:"snapshot"`
+}
+
+// DefaultOutputDir is the default base directory of the files the server writes
+const DefaultOutputDir = "output"
+
+// DefaultServiceAccount is the author recorded on notes posted by this server
+const DefaultServiceAccount = "or-mcp-service"
+
 // OpsRampConfig holds the OpsRamp API configuration
 type OpsRampConfig struct {
-	TenantURL  string          `yaml:"tenant_url"`
-	AuthURL    string          `yaml:"auth_url"`
-	AuthKey    string          `yaml:"auth_key"`
-	AuthSecret string          `yaml:"auth_secret"`
-	TenantID   string          `yaml:"tenant_id"`
-	Resources  ResourcesConfig `yaml:"resources"`
+	TenantURL      string          `yaml:"tenant_url"`
+	AuthURL        string          `yaml:"auth_url"`
+	AuthKey        string          `yaml:"auth_key"`
+	AuthSecret     string          `yaml:"auth_secret"`
+	TenantID       string          `yaml:"tenant_id"`
+	PartnerID      string          `yaml:"partner_id"`
+	ServiceAccount string          `yaml:"service_account"`
+	Resources      ResourcesConfig `yaml:"resources"`
+	// Environment labels the tenant's logs and metrics, e.g. production;
+	// additional tenants without one inherit the primary tenant's
+	Environment string `yaml:"environment"`
+	// Labels are extra telemetry labels for the tenant, e.g. customer: acme
+	Labels map[string]string `yaml:"labels"`
+	// SecondaryAuthKey and SecondaryAuthSecret are standby credentials the
+	// client switches to when OpsRamp stops accepting the primary ones, so
+	// credentials can be rotated without a maintenance window
+	SecondaryAuthKey    string `yaml:"secondary_auth_key"`
+	SecondaryAuthSecret string `yaml:"secondary_auth_secret"`
 }
 
 // ResourcesConfig holds resource management specific configuration
@@ -29,6 +406,7 @@ type ResourcesConfig struct {
 	DefaultPageSize int  `yaml:"default_page_size"`
 	MaxPageSize     int  `yaml:"max_page_size"`
 	CacheTTL        int  `yaml:"cache_ttl"`
+	EntityCacheTTL  int  `yaml:"entity_cache_ttl"` // seconds a resource or integration fetched by ID is reused
 	EnableBulkOps   bool `yaml:"enable_bulk_operations"`
 	MaxBulkSize     int  `yaml:"max_bulk_size"`
 	RequestTimeout  int  `yaml:"request_timeout"`
@@ -48,6 +426,11 @@ func LoadConfig(configPath string) (*Config, error) {
 
 	// Override with environment variables if they exist
 	overrideConfigFromEnv(config)
+	applyOutputDefaults(config)
+
+	if err := validateToolBackends(config.Tools); err != nil {
+		return nil, fmt.Errorf("tool backend configuration validation failed: %w", err)
+	}
 
 	return config, nil
 }
@@ -93,9 +476,60 @@ func loadConfigFromFile(configPath string) (*Config, error) {
 
 	// Apply defaults and validate
 	applyResourceDefaults(&config.OpsRamp.Resources)
+	if config.OpsRamp.ServiceAccount == "" {
+		config.OpsRamp.ServiceAccount = DefaultServiceAccount
+	}
 	if err := validateResourceConfig(&config.OpsRamp.Resources); err != nil {
 		return nil, fmt.Errorf("resource configuration validation failed: %w", err)
 	}
+	if err := validateTelemetryLabels(config.OpsRamp.Labels); err != nil {
+		return nil, fmt.Errorf("telemetry label validation failed: %w", err)
+	}
+	if err := validateSecondaryCredentials(config.OpsRamp); err != nil {
+		return nil, fmt.Errorf("opsramp configuration validation failed: %w", err)
+	}
+	if err := validateTenants(&config); err != nil {
+		return nil, fmt.Errorf("tenant configuration validation failed: %w", err)
+	}
+	if err := validateAccessLogConfig(&config.AccessLog); err != nil {
+		return nil, fmt.Errorf("access log configuration validation failed: %w", err)
+	}
+	if err := validateEgressConfig(&config.Egress); err != nil {
+		return nil, fmt.Errorf("egress configuration validation failed: %w", err)
+	}
+	if err := validateRemoteWriteConfig(&config.RemoteWrite); err != nil {
+		return nil, fmt.Errorf("remote_write configuration validation failed: %w", err)
+	}
+	if err := validateWebhooksConfig(&config.Webhooks); err != nil {
+		return nil, fmt.Errorf("webhooks configuration validation failed: %w", err)
+	}
+	if err := validateDeletesConfig(&config.Deletes); err != nil {
+		return nil, fmt.Errorf("deletes configuration validation failed: %w", err)
+	}
+	if err := validateSessionsConfig(&config.Sessions); err != nil {
+		return nil, fmt.Errorf("sessions configuration validation failed: %w", err)
+	}
+	if err := validateConcurrencyConfig(&config.Concurrency); err != nil {
+		return nil, fmt.Errorf("concurrency configuration validation failed: %w", err)
+	}
+	if err := validateBudgetsConfig(&config.Budgets); err != nil {
+		return nil, fmt.Errorf("budgets configuration validation failed: %w", err)
+	}
+	if err := validateCatalogConfig(&config.Catalog); err != nil {
+		return nil, fmt.Errorf("catalog configuration validation failed: %w", err)
+	}
+	if err := validateHTTPConfig(&config.HTTP); err != nil {
+		return nil, fmt.Errorf("http configuration validation failed: %w", err)
+	}
+	if err := validateAuthConfig(&config.Auth); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidAuthConfig, err)
+	}
+	if err := validateTracingConfig(&config.Tracing); err != nil {
+		return nil, fmt.Errorf("tracing configuration validation failed: %w", err)
+	}
+	if err := validateFieldPolicies(config.Responses.Fields); err != nil {
+		return nil, fmt.Errorf("response field configuration validation failed: %w", err)
+	}
 
 	return &config, nil
 }
@@ -115,9 +549,165 @@ func overrideConfigFromEnv(config *Config) {
 	if val := os.Getenv("OPSRAMP_AUTH_SECRET"); val != "" {
 		config.OpsRamp.AuthSecret = val
 	}
+	if val := os.Getenv("OPSRAMP_SECONDARY_AUTH_KEY"); val != "" {
+		config.OpsRamp.SecondaryAuthKey = val
+	}
+	if val := os.Getenv("OPSRAMP_SECONDARY_AUTH_SECRET"); val != "" {
+		config.OpsRamp.SecondaryAuthSecret = val
+	}
 	if val := os.Getenv("OPSRAMP_TENANT_ID"); val != "" {
 		config.OpsRamp.TenantID = val
 	}
+	if val := os.Getenv("OPSRAMP_PARTNER_ID"); val != "" {
+		config.OpsRamp.PartnerID = val
+	}
+	if val := os.Getenv("OPSRAMP_SERVICE_ACCOUNT"); val != "" {
+		config.OpsRamp.ServiceAccount = val
+	}
+	if val := os.Getenv("OPSRAMP_ENVIRONMENT"); val != "" {
+		config.OpsRamp.Environment = val
+	}
+
+	// Responses config
+	if val := os.Getenv("LLM_COMPACT"); val != "" {
+		config.Responses.LLMCompact = val == "true"
+	}
+	if val := os.Getenv("RESPONSE_LOCALE"); val != "" {
+		config.Responses.Locale = val
+	}
+	if val := os.Getenv("RESPONSE_DENY_FIELDS"); val != "" {
+		if config.Responses.Fields == nil {
+			config.Responses.Fields = make(map[string]FieldPolicy)
+		}
+		policy := config.Responses.Fields["*"]
+		policy.Deny = append(policy.Deny, SplitFieldList(val)...)
+		config.Responses.Fields["*"] = policy
+	}
+
+	// Locks config
+	if val := os.Getenv("BULK_LOCK_MODE"); val != "" {
+		config.Locks.Mode = val
+	}
+
+	// Output locations config
+	if val := os.Getenv("OUTPUT_DIR"); val != "" {
+		config.Output.Dir = val
+	}
+	if val := os.Getenv("LOG_DIR"); val != "" {
+		config.Output.LogDir = val
+	}
+	if val := os.Getenv("CRASH_DIR"); val != "" {
+		config.Output.CrashDir = val
+	}
+
+	// State store config
+	if val := os.Getenv("STATE_STORE_PATH"); val != "" {
+		config.State.Path = val
+	}
+
+	// Access log config
+	if val := os.Getenv("ACCESS_LOG_PATH"); val != "" {
+		config.AccessLog.Path = val
+	}
+
+	// Egress allowlist config
+	if val := os.Getenv("EGRESS_ALLOWED_HOSTS"); val != "" {
+		config.Egress.AllowedHosts = strings.Split(val, ",")
+	}
+
+	// Remote write exporter config
+	if val := os.Getenv("REMOTE_WRITE_URL"); val != "" {
+		config.RemoteWrite.URL = val
+	}
+	if val := os.Getenv("REMOTE_WRITE_BEARER_TOKEN"); val != "" {
+		config.RemoteWrite.BearerToken = val
+	}
+
+	// Alert webhook config
+	if val := os.Getenv("WEBHOOK_SECRET"); val != "" {
+		config.Webhooks.Secret = val
+	}
+
+	// Delete confirmation config
+	if val := os.Getenv("DELETE_SIGNING_KEY"); val != "" {
+		config.Deletes.SigningKey = val
+	}
+
+	// Session heartbeat config
+	if val := os.Getenv("STALE_SESSION_CLEANUP"); val != "" {
+		config.Sessions.CleanupStale = val == "true"
+	}
+
+	// Listener address and TLS
+	if val := os.Getenv("HTTP_LISTEN"); val != "" {
+		config.HTTP.Listen = val
+	}
+	if val := os.Getenv("TLS_CERT_FILE"); val != "" {
+		config.HTTP.TLS.CertFile = val
+	}
+	if val := os.Getenv("TLS_KEY_FILE"); val != "" {
+		config.HTTP.TLS.KeyFile = val
+	}
+	if val := os.Getenv("ACME_DOMAINS"); val != "" {
+		config.HTTP.TLS.ACMEDomains = strings.Split(val, ",")
+	}
+	if val := os.Getenv("ACME_EMAIL"); val != "" {
+		config.HTTP.TLS.ACMEEmail = val
+	}
+
+	// HTTP authentication, e.g. AUTH_API_KEYS=ci=secret1,agent=secret2
+	if val := os.Getenv("AUTH_API_KEYS"); val != "" {
+		config.Auth.APIKeys = nil
+		for _, entry := range strings.Split(val, ",") {
+			name, secret, _ := strings.Cut(strings.TrimSpace(entry), "=")
+			config.Auth.APIKeys = append(config.Auth.APIKeys, AuthCredential{Name: name, Secret: secret})
+		}
+	}
+	if val := os.Getenv("AUTH_INTROSPECTION_CLIENT_SECRET"); val != "" {
+		config.Auth.Introspection.ClientSecret = val
+	}
+
+	// Trace export, with the standard OpenTelemetry variables
+	if val := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); val != "" {
+		config.Tracing.Endpoint = val
+	} else if val := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); val != "" {
+		config.Tracing.Endpoint = strings.TrimSuffix(val, "/") + "/v1/traces"
+	}
+	if val := os.Getenv("OTEL_SERVICE_NAME"); val != "" {
+		config.Tracing.ServiceName = val
+	}
+
+	// Mock backend fixtures
+	if val := os.Getenv("MOCK_FIXTURES"); val != "" {
+		config.Mock.Fixtures = val
+	}
+
+	// Tool backends, e.g. TOOL_BACKENDS=alerts=mock,resources=snapshot:path/to/snapshot.json
+	if val := os.Getenv("TOOL_BACKENDS"); val != "" {
+		if config.Tools == nil {
+			config.Tools = make(map[string]ToolBackendConfig)
+		}
+		for _, entry := range strings.Split(val, ",") {
+			name, selection, _ := strings.Cut(strings.TrimSpace(entry), "=")
+			backend, snapshot, _ := strings.Cut(selection, ":")
+			config.Tools[name] = ToolBackendConfig{Backend: backend, Snapshot: snapshot}
+		}
+	}
+}
+
+// ToolBackend returns the backend selected for a tool, defaulting to live
+func (c *Config) ToolBackend(name string) ToolBackendConfig {
+	backend := c.Tools[name]
+	if backend.Backend == "" {
+		backend.Backend = BackendLive
+	}
+	return backend
+}
+
+// StrictProtocol reports whether STRICT_PROTOCOL is set, which disables the MCP
+// Inspector compatibility shims so the server can be run against conformance suites
+func StrictProtocol() bool {
+	return os.Getenv("STRICT_PROTOCOL") == "true"
 }
 
 // GetEnvOrDefault gets an environment variable or returns a default value
@@ -139,6 +729,9 @@ func applyResourceDefaults(config *ResourcesConfig) {
 	if config.CacheTTL == 0 {
 		config.CacheTTL = 300 // 5 minutes
 	}
+	if config.EntityCacheTTL == 0 {
+		config.EntityCacheTTL = 30
+	}
 	if config.MaxBulkSize == 0 {
 		config.MaxBulkSize = 100
 	}
@@ -156,6 +749,416 @@ func applyResourceDefaults(config *ResourcesConfig) {
 	}
 }
 
+// validateTenants validates the additional tenant definitions and applies resource defaults to each
+func validateTenants(config *Config) error {
+	seen := make(map[string]bool)
+	for i := range config.Tenants {
+		tenant := &config.Tenants[i]
+		if tenant.Name == "" {
+			return fmt.Errorf("tenant at index %d is missing a name", i)
+		}
+		if tenant.Name == "default" {
+			return fmt.Errorf("tenant name 'default' is reserved for the opsramp section")
+		}
+		if seen[tenant.Name] {
+			return fmt.Errorf("duplicate tenant name: %s", tenant.Name)
+		}
+		seen[tenant.Name] = true
+
+		if err := PrepareTenant(tenant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrepareTenant applies the defaults of an additional tenant and validates
+// its resource settings and labels, for tenants in the config file and those
+// onboarded at runtime alike
+func PrepareTenant(tenant *TenantConfig) error {
+	if tenant.ServiceAccount == "" {
+		tenant.ServiceAccount = DefaultServiceAccount
+	}
+	applyResourceDefaults(&tenant.Resources)
+	if err := validateResourceConfig(&tenant.Resources); err != nil {
+		return fmt.Errorf("tenant %s: %w", tenant.Name, err)
+	}
+	if err := validateTelemetryLabels(tenant.Labels); err != nil {
+		return fmt.Errorf("tenant %s: %w", tenant.Name, err)
+	}
+	if err := validateSecondaryCredentials(tenant.OpsRampConfig); err != nil {
+		return fmt.Errorf("tenant %s: %w", tenant.Name, err)
+	}
+	return nil
+}
+
+// validateSecondaryCredentials checks that standby credentials are complete
+func validateSecondaryCredentials(config OpsRampConfig) error {
+	if (config.SecondaryAuthKey == "") != (config.SecondaryAuthSecret == "") {
+		return fmt.Errorf("secondary_auth_key and secondary_auth_secret must be set together")
+	}
+	return nil
+}
+
+// Telemetry labels attached to every log line and exported metric
+const (
+	TelemetryLabelTenant      = "tenant"
+	TelemetryLabelEnvironment = "environment"
+)
+
+// telemetryLabelName restricts label names to those valid in Prometheus
+var telemetryLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// validateTelemetryLabels checks that configured label names are valid and
+// do not shadow the tenant and environment labels
+func validateTelemetryLabels(labels map[string]string) error {
+	for name := range labels {
+		if !telemetryLabelName.MatchString(name) || strings.HasPrefix(name, "__") {
+			return fmt.Errorf("invalid telemetry label name %q", name)
+		}
+		if name == TelemetryLabelTenant || name == TelemetryLabelEnvironment {
+			return fmt.Errorf("telemetry label %q is reserved; set it with the tenant name or environment", name)
+		}
+	}
+	return nil
+}
+
+// TelemetryLabels returns the labels identifying a tenant in logs and
+// metrics: its name, its environment and its configured labels. Tenant
+// "default" is the primary opsramp section; an empty tenant returns the
+// primary section's labels without a tenant label, for telemetry that is
+// not specific to one tenant.
+func (c *Config) TelemetryLabels(tenant string) map[string]string {
+	profile := c.OpsRamp
+	for _, t := range c.Tenants {
+		if t.Name == tenant {
+			profile = t.OpsRampConfig
+			break
+		}
+	}
+
+	labels := make(map[string]string, len(profile.Labels)+2)
+	for name, value := range profile.Labels {
+		labels[name] = value
+	}
+	if tenant != "" {
+		labels[TelemetryLabelTenant] = tenant
+	}
+	environment := profile.Environment
+	if environment == "" {
+		environment = c.OpsRamp.Environment
+	}
+	if environment != "" {
+		labels[TelemetryLabelEnvironment] = environment
+	}
+	return labels
+}
+
+// ServerTelemetryLabels returns the labels for telemetry that is not tied to
+// a call to one tenant, such as server and access log lines. In
+// single-tenant mode these are the primary tenant's labels; in multi-tenant
+// mode only the primary environment applies to the whole server.
+func (c *Config) ServerTelemetryLabels() map[string]string {
+	if len(c.Tenants) == 0 {
+		return c.TelemetryLabels("default")
+	}
+	labels := make(map[string]string, 1)
+	if c.OpsRamp.Environment != "" {
+		labels[TelemetryLabelEnvironment] = c.OpsRamp.Environment
+	}
+	return labels
+}
+
+// validateAccessLogConfig validates the access log sampling rates
+func validateAccessLogConfig(config *AccessLogConfig) error {
+	for prefix, rate := range config.Sampling {
+		if !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("sampling path %q must start with /", prefix)
+		}
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("sampling rate for %s must be between 0 and 1", prefix)
+		}
+	}
+	return nil
+}
+
+// validateFieldPolicies checks the response field patterns are well formed
+func validateFieldPolicies(policies map[string]FieldPolicy) error {
+	for tool, policy := range policies {
+		for _, pattern := range append(append([]string{}, policy.Allow...), policy.Deny...) {
+			if _, err := path.Match(pattern, ""); err != nil || strings.TrimSpace(pattern) == "" {
+				return fmt.Errorf("invalid field pattern %q for tool %s", pattern, tool)
+			}
+		}
+	}
+	return nil
+}
+
+// SplitFieldList splits a comma-separated list of field names
+func SplitFieldList(value string) []string {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// validateEgressConfig validates the outbound host allowlist entries
+func validateEgressConfig(config *EgressConfig) error {
+	for i, host := range config.AllowedHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" || host == "*." {
+			return fmt.Errorf("allowed host at index %d is empty", i)
+		}
+		if strings.ContainsAny(host, "/:@") {
+			return fmt.Errorf("allowed host %q must be a bare host name without scheme, port or path", host)
+		}
+		config.AllowedHosts[i] = host
+	}
+	return nil
+}
+
+// validateRemoteWriteConfig validates the remote_write exporter settings and
+// applies the default interval
+func validateRemoteWriteConfig(config *RemoteWriteConfig) error {
+	if config.URL == "" {
+		return nil
+	}
+	if !strings.HasPrefix(config.URL, "https://") && !strings.HasPrefix(config.URL, "http://") {
+		return fmt.Errorf("url %q must be an absolute http(s) URL", config.URL)
+	}
+	if len(config.Resources) == 0 || len(config.Metrics) == 0 {
+		return fmt.Errorf("resources and metrics must both be listed")
+	}
+	if config.BearerToken != "" && config.Username != "" {
+		return fmt.Errorf("bearer_token and username are mutually exclusive")
+	}
+	if config.IntervalSeconds == 0 {
+		config.IntervalSeconds = DefaultRemoteWriteInterval
+	}
+	if config.IntervalSeconds < 10 || config.IntervalSeconds > 3600 {
+		return fmt.Errorf("interval_seconds must be between 10 and 3600")
+	}
+	return nil
+}
+
+// validateWebhooksConfig validates the alert webhook settings and applies
+// the default alert link template
+func validateWebhooksConfig(config *WebhooksConfig) error {
+	if config.AlertLinkTemplate == "" {
+		config.AlertLinkTemplate = DefaultAlertLinkTemplate
+	}
+	if !strings.Contains(config.AlertLinkTemplate, "{id}") {
+		return fmt.Errorf("alert_link_template %q must contain {id}", config.AlertLinkTemplate)
+	}
+	if config.Secret != "" && len(config.Secret) < 16 {
+		return fmt.Errorf("secret must be at least 16 characters")
+	}
+	return nil
+}
+
+// validateDeletesConfig validates the delete confirmation settings and
+// applies their defaults
+func validateDeletesConfig(config *DeletesConfig) error {
+	if config.ConfirmationThreshold == 0 {
+		config.ConfirmationThreshold = DefaultDeleteConfirmationThreshold
+	}
+	if config.TokenTTLSeconds == 0 {
+		config.TokenTTLSeconds = DefaultDeleteTokenTTL
+	}
+	if config.ConfirmationThreshold < 1 {
+		return fmt.Errorf("confirmation_threshold must be at least 1")
+	}
+	if config.TokenTTLSeconds < 30 || config.TokenTTLSeconds > 3600 {
+		return fmt.Errorf("token_ttl_seconds must be between 30 and 3600")
+	}
+	if config.SigningKey != "" && len(config.SigningKey) < 16 {
+		return fmt.Errorf("signing_key must be at least 16 characters")
+	}
+	return nil
+}
+
+// validateSessionsConfig validates the heartbeat and shutdown settings and
+// applies their defaults
+func validateSessionsConfig(config *SessionsConfig) error {
+	if config.HeartbeatSeconds == 0 {
+		config.HeartbeatSeconds = DefaultHeartbeatSeconds
+	}
+	if config.HeartbeatSeconds < 5 || config.HeartbeatSeconds > 300 {
+		return fmt.Errorf("heartbeat_seconds must be between 5 and 300")
+	}
+	if config.ShutdownDowntimeSeconds == 0 {
+		config.ShutdownDowntimeSeconds = DefaultShutdownDowntimeSeconds
+	}
+	if config.ShutdownDowntimeSeconds < 1 || config.ShutdownDowntimeSeconds > 3600 {
+		return fmt.Errorf("shutdown_downtime_seconds must be between 1 and 3600")
+	}
+	return nil
+}
+
+// validateConcurrencyConfig validates the request pool sizes and applies
+// their defaults
+func validateConcurrencyConfig(config *ConcurrencyConfig) error {
+	if config.Interactive == 0 {
+		config.Interactive = DefaultInteractiveConcurrency
+	}
+	if config.Background == 0 {
+		config.Background = DefaultBackgroundConcurrency
+	}
+	if config.Interactive < 1 || config.Interactive > 256 {
+		return fmt.Errorf("interactive must be between 1 and 256")
+	}
+	if config.Background < 1 || config.Background > 256 {
+		return fmt.Errorf("background must be between 1 and 256")
+	}
+	return nil
+}
+
+// validateBudgetsConfig validates the call budgets and applies the default mode
+func validateBudgetsConfig(config *BudgetsConfig) error {
+	if config.SessionCallsPerHour < 0 || config.CallsPerHour < 0 {
+		return fmt.Errorf("budgets cannot be negative")
+	}
+	switch config.Mode {
+	case "":
+		config.Mode = BudgetModeTrim
+	case BudgetModeTrim, BudgetModeReject:
+	default:
+		return fmt.Errorf("mode must be %s or %s", BudgetModeTrim, BudgetModeReject)
+	}
+	return nil
+}
+
+// validateCatalogConfig validates the tools/list page size and applies its default
+func validateCatalogConfig(config *CatalogConfig) error {
+	if config.PageSize == 0 {
+		config.PageSize = DefaultCatalogPageSize
+	}
+	if config.PageSize < 1 || config.PageSize > 500 {
+		return fmt.Errorf("page_size must be between 1 and 500")
+	}
+	return nil
+}
+
+// validateHTTPConfig validates the listener address and TLS settings and
+// applies the HSTS default
+func validateHTTPConfig(config *HTTPConfig) error {
+	if config.Listen != "" {
+		if _, port, err := net.SplitHostPort(config.Listen); err != nil || port == "" {
+			return fmt.Errorf("listen must be host:port or :port, got %q", config.Listen)
+		}
+	}
+	if config.HSTSMaxAgeSeconds == 0 {
+		config.HSTSMaxAgeSeconds = DefaultHSTSMaxAgeSeconds
+	}
+	if config.HSTSMaxAgeSeconds < -1 {
+		return fmt.Errorf("hsts_max_age_seconds must be -1 or more")
+	}
+
+	tls := config.TLS
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		return fmt.Errorf("tls cert_file and key_file must be set together")
+	}
+	if tls.CertFile != "" && len(tls.ACMEDomains) > 0 {
+		return fmt.Errorf("tls takes either cert_file and key_file or acme_domains, not both")
+	}
+	for _, domain := range tls.ACMEDomains {
+		if domain == "" || strings.ContainsAny(domain, ":/*") {
+			return fmt.Errorf("tls acme_domains must be host names, got %q", domain)
+		}
+	}
+	return nil
+}
+
+// validateAuthConfig validates the HTTP authentication settings and applies
+// the introspection cache default
+func validateAuthConfig(config *AuthConfig) error {
+	names := make(map[string]bool)
+	for kind, credentials := range map[string][]AuthCredential{"api_keys": config.APIKeys, "bearer_tokens": config.BearerTokens} {
+		for i, credential := range credentials {
+			if credential.Name == "" {
+				return fmt.Errorf("%s[%d] has no name", kind, i)
+			}
+			if names[credential.Name] {
+				return fmt.Errorf("credential name %q is used more than once", credential.Name)
+			}
+			names[credential.Name] = true
+			if len(credential.Secret) < 16 {
+				return fmt.Errorf("%s %q secret must be at least 16 characters", kind, credential.Name)
+			}
+		}
+	}
+
+	introspection := &config.Introspection
+	if introspection.URL != "" {
+		parsed, err := url.Parse(introspection.URL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return fmt.Errorf("introspection url must be an http or https URL, got %q", introspection.URL)
+		}
+		if introspection.ClientID == "" || introspection.ClientSecret == "" {
+			return fmt.Errorf("introspection client_id and client_secret are required")
+		}
+	}
+	if introspection.CacheSeconds == 0 {
+		introspection.CacheSeconds = DefaultIntrospectionCacheSeconds
+	}
+	if introspection.CacheSeconds < 0 || introspection.CacheSeconds > 3600 {
+		return fmt.Errorf("introspection cache_seconds must be between 1 and 3600")
+	}
+
+	for path, mode := range config.Endpoints {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("endpoint %q must be a path starting with /", path)
+		}
+		if mode != AuthRequired && mode != AuthNone {
+			return fmt.Errorf("endpoint %s mode must be %q or %q, got %q", path, AuthRequired, AuthNone, mode)
+		}
+	}
+	return nil
+}
+
+// validateTracingConfig validates the trace export settings and applies
+// their defaults
+func validateTracingConfig(config *TracingConfig) error {
+	if config.Endpoint != "" {
+		parsed, err := url.Parse(config.Endpoint)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return fmt.Errorf("endpoint must be an http or https URL, got %q", config.Endpoint)
+		}
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = DefaultTracingServiceName
+	}
+	if config.SampleRatio == 0 {
+		config.SampleRatio = 1
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return fmt.Errorf("sample_ratio must be between 0 and 1")
+	}
+	return nil
+}
+
+// validateToolBackends validates the per-tool backend selections
+func validateToolBackends(tools map[string]ToolBackendConfig) error {
+	for name, tool := range tools {
+		if name == "" {
+			return fmt.Errorf("tool backend entry is missing a tool name")
+		}
+		switch tool.Backend {
+		case "", BackendLive, BackendMock:
+		case BackendSnapshot:
+			if tool.Snapshot == "" {
+				return fmt.Errorf("tool %s: snapshot backend requires a snapshot path", name)
+			}
+		default:
+			return fmt.Errorf("tool %s: unknown backend %q (expected live, mock or snapshot)", name, tool.Backend)
+		}
+	}
+	return nil
+}
+
 // validateResourceConfig validates resource configuration values
 func validateResourceConfig(config *ResourcesConfig) error {
 	if config.DefaultPageSize < 1 || config.DefaultPageSize > config.MaxPageSize {
@@ -178,6 +1181,10 @@ func validateResourceConfig(config *ResourcesConfig) error {
 		return fmt.Errorf("request_timeout must be between 1 and 300 seconds")
 	}
 
+	if config.EntityCacheTTL < 0 || config.EntityCacheTTL > 300 {
+		return fmt.Errorf("entity_cache_ttl must be between 0 and 300 seconds")
+	}
+
 	if config.RetryAttempts < 0 || config.RetryAttempts > 10 {
 		return fmt.Errorf("retry_attempts must be between 0 and 10")
 	}
@@ -192,3 +1199,69 @@ func validateResourceConfig(config *ResourcesConfig) error {
 
 	return nil
 }
+
+// redactedValue replaces secret configuration values in summaries
+const redactedValue = "[REDACTED]"
+
+// isSecretKey reports whether a configuration key holds a secret, such as
+// auth_secret, auth_key, bearer_token, password or signing_key
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	return key == "secret" || key == "password" ||
+		strings.HasSuffix(key, "_secret") || strings.HasSuffix(key, "_key") ||
+		strings.HasSuffix(key, "_token") || strings.HasSuffix(key, "_password")
+}
+
+// isSecretMapKey reports whether every value of a configuration map holds a
+// secret, as the tracing headers may carry API keys and bearer tokens under
+// any name
+func isSecretMapKey(key string) bool {
+	return strings.ToLower(key) == "headers"
+}
+
+// RedactedYAML renders the configuration as YAML with every non-empty secret
+// replaced, so it can be shared in support bundles
+func (c *Config) RedactedYAML() (string, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	var tree yaml.MapSlice
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return "", err
+	}
+	data, err = yaml.Marshal(redactSecrets(tree))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// redactSecrets replaces the values of secret keys at any depth
+func redactSecrets(value interface{}) interface{} {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		for i, item := range v {
+			if key, ok := item.Key.(string); ok && isSecretKey(key) {
+				if secret, ok := item.Value.(string); ok && secret != "" {
+					v[i].Value = redactedValue
+				}
+				continue
+			}
+			if key, ok := item.Key.(string); ok && isSecretMapKey(key) {
+				if values, ok := item.Value.(yaml.MapSlice); ok {
+					for j := range values {
+						values[j].Value = redactedValue
+					}
+				}
+				continue
+			}
+			v[i].Value = redactSecrets(item.Value)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactSecrets(item)
+		}
+	}
+	return value
+}
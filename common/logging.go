@@ -6,7 +6,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -53,6 +55,11 @@ type CustomLogger struct {
 	fileLogger *log.Logger
 	mu         sync.Mutex
 	file       *os.File
+	// labels is the rendered telemetry label set prefixed to each line
+	labels string
+	// module names the part of the server logging through this logger, whose
+	// level can be changed at runtime with SetModuleLevel
+	module string
 }
 
 var (
@@ -62,6 +69,160 @@ var (
 	once sync.Once
 )
 
+// recentLogLineCount is how many of the latest log lines are kept in memory
+// for crash snapshots
+const recentLogLineCount = 200
+
+var (
+	recentMu    sync.Mutex
+	recentLines [recentLogLineCount]string
+	recentNext  int
+	recentFull  bool
+
+	fatalHookMu sync.Mutex
+	fatalHook   func(message string)
+)
+
+// rememberLogLine keeps a log line among the recent ones
+func rememberLogLine(line string) {
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	recentLines[recentNext] = line
+	recentNext = (recentNext + 1) % recentLogLineCount
+	if recentNext == 0 {
+		recentFull = true
+	}
+}
+
+// RecentLogLines returns the latest log lines written by any logger, oldest first
+func RecentLogLines() []string {
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	if !recentFull {
+		return append([]string(nil), recentLines[:recentNext]...)
+	}
+	return append(append([]string(nil), recentLines[recentNext:]...), recentLines[:recentNext]...)
+}
+
+// SetFatalHook registers a function that Fatal calls with the message before
+// the process exits, e.g. to write a crash snapshot
+func SetFatalHook(hook func(message string)) {
+	fatalHookMu.Lock()
+	defer fatalHookMu.Unlock()
+	fatalHook = hook
+}
+
+// Log modules whose level can be changed at runtime. Each tool logs as
+// tools.<tool>, e.g. tools.resources; a level set for tools applies to every
+// tool without a level of its own.
+const (
+	// ModuleClient is the OpsRamp API client
+	ModuleClient = "client"
+	// ModuleTools is the parent of the tool modules
+	ModuleTools = "tools"
+	// ModuleTransport is the MCP transports and HTTP handlers
+	ModuleTransport = "transport"
+)
+
+// logModulePattern matches the module names accepted by SetModuleLevel
+var logModulePattern = regexp.MustCompile(`^(client|transport|tools(\.[a-z][A-Za-z0-9]*)?)$`)
+
+var (
+	moduleLevelsMu sync.RWMutex
+	moduleLevels   = make(map[string]LogLevel)
+)
+
+// ParseLogLevel parses a level name such as debug or WARN
+func ParseLogLevel(name string) (LogLevel, error) {
+	for level := DEBUG; level <= FATAL; level++ {
+		if strings.EqualFold(strings.TrimSpace(name), level.String()) {
+			return level, nil
+		}
+	}
+	return INFO, fmt.Errorf("unknown log level %q: use DEBUG, INFO, WARN, ERROR or FATAL", name)
+}
+
+// SetModuleLevel sets the level of every logger of a module, and of its
+// submodules without a level of their own
+func SetModuleLevel(module string, level LogLevel) error {
+	if !logModulePattern.MatchString(module) {
+		return fmt.Errorf("unknown log module %q: use client, transport, tools or tools.<tool>", module)
+	}
+	moduleLevelsMu.Lock()
+	defer moduleLevelsMu.Unlock()
+	moduleLevels[module] = level
+	return nil
+}
+
+// ResetModuleLevel returns a module's loggers to their own level
+func ResetModuleLevel(module string) {
+	moduleLevelsMu.Lock()
+	defer moduleLevelsMu.Unlock()
+	delete(moduleLevels, module)
+}
+
+// ModuleLevels returns the levels set by module
+func ModuleLevels() map[string]string {
+	moduleLevelsMu.RLock()
+	defer moduleLevelsMu.RUnlock()
+	levels := make(map[string]string, len(moduleLevels))
+	for module, level := range moduleLevels {
+		levels[module] = level.String()
+	}
+	return levels
+}
+
+// SetModuleLevels applies a comma-separated list of module=level settings,
+// e.g. "client=debug,transport=warn", as read from the LOG_LEVELS
+// environment variable. Nothing is applied unless every setting is valid.
+func SetModuleLevels(spec string) error {
+	levels := make(map[string]LogLevel)
+	for _, setting := range strings.Split(spec, ",") {
+		if strings.TrimSpace(setting) == "" {
+			continue
+		}
+		module, name, ok := strings.Cut(setting, "=")
+		if !ok {
+			return fmt.Errorf("invalid log level setting %q: use module=level", setting)
+		}
+		module = strings.TrimSpace(module)
+		if !logModulePattern.MatchString(module) {
+			return fmt.Errorf("unknown log module %q: use client, transport, tools or tools.<tool>", module)
+		}
+		level, err := ParseLogLevel(name)
+		if err != nil {
+			return err
+		}
+		levels[module] = level
+	}
+
+	moduleLevelsMu.Lock()
+	defer moduleLevelsMu.Unlock()
+	for module, level := range levels {
+		moduleLevels[module] = level
+	}
+	return nil
+}
+
+// moduleLevel returns the level set for a module or its closest parent
+func moduleLevel(module string) (LogLevel, bool) {
+	if module == "" {
+		return INFO, false
+	}
+	moduleLevelsMu.RLock()
+	defer moduleLevelsMu.RUnlock()
+	for {
+		if level, ok := moduleLevels[module]; ok {
+			return level, true
+		}
+		i := strings.LastIndex(module, ".")
+		if i < 0 {
+			return INFO, false
+		}
+		module = module[:i]
+	}
+}
+
 // InitLogger initializes the global logger
 func InitLogger(level LogLevel, logDir, logFileName string) (*CustomLogger, error) {
 	var err error
@@ -131,12 +292,79 @@ func (l *CustomLogger) SetLevel(level LogLevel) {
 	l.level = level
 }
 
+// Level returns the logger's own level, which applies unless its module has a
+// level set
+func (l *CustomLogger) Level() LogLevel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level
+}
+
+// SetLabels sets the telemetry labels, such as tenant and environment,
+// attached to every line this logger writes
+func (l *CustomLogger) SetLabels(labels map[string]string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.labels = formatLabels(labels)
+}
+
+// WithLabels returns a logger writing to the same outputs at the same level
+// with the given telemetry labels in place of this logger's
+func (l *CustomLogger) WithLabels(labels map[string]string) *CustomLogger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return &CustomLogger{
+		level:      l.level,
+		stdLogger:  l.stdLogger,
+		fileLogger: l.fileLogger,
+		labels:     formatLabels(labels),
+		module:     l.module,
+	}
+}
+
+// WithModule returns a logger writing to the same outputs at the same level
+// with the same labels, whose level follows the named module's when one is
+// set
+func (l *CustomLogger) WithModule(module string) *CustomLogger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return &CustomLogger{
+		level:      l.level,
+		stdLogger:  l.stdLogger,
+		fileLogger: l.fileLogger,
+		labels:     l.labels,
+		module:     module,
+	}
+}
+
+// formatLabels renders labels as a sorted "[name=value ...]" prefix
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + labels[name]
+	}
+	return "[" + strings.Join(pairs, " ") + "] "
+}
+
 // log logs a message with the given level
 func (l *CustomLogger) log(level LogLevel, format string, args ...interface{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	if level < l.level {
+	threshold := l.level
+	if moduleThreshold, ok := moduleLevel(l.module); ok {
+		threshold = moduleThreshold
+	}
+	if level < threshold {
 		return
 	}
 
@@ -151,7 +379,8 @@ func (l *CustomLogger) log(level LogLevel, format string, args ...interface{}) {
 
 	// Format the message
 	msg := fmt.Sprintf(format, args...)
-	logMsg := fmt.Sprintf("[%s] [%s:%d] %s", level.String(), file, line, msg)
+	logMsg := fmt.Sprintf("[%s] %s[%s:%d] %s", level.String(), l.labels, file, line, msg)
+	rememberLogLine(time.Now().Format("2006/01/02 15:04:05") + " " + logMsg)
 
 	// Log to file (and stdout via multiwriter)
 	if l.fileLogger != nil {
@@ -185,6 +414,13 @@ func (l *CustomLogger) Error(format string, args ...interface{}) {
 // Fatal logs a fatal message and exits the application
 func (l *CustomLogger) Fatal(format string, args ...interface{}) {
 	l.log(FATAL, format, args...)
+
+	fatalHookMu.Lock()
+	hook := fatalHook
+	fatalHookMu.Unlock()
+	if hook != nil {
+		hook(fmt.Sprintf(format, args...))
+	}
 	os.Exit(1)
 }
 
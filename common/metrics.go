@@ -0,0 +1,303 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server metrics, exposed on /metrics in the Prometheus text format
+var (
+	// ToolCalls counts tool calls by tool, action and outcome (success or error)
+	ToolCalls = NewCounterVec("or_mcp_tool_calls_total", "Tool calls by tool, action and outcome.", "tool", "action", "outcome")
+	// ToolCallDuration measures tool calls by tool and action
+	ToolCallDuration = NewHistogramVec("or_mcp_tool_call_duration_seconds", "Duration of tool calls by tool and action.", DefaultLatencyBuckets, "tool", "action")
+	// OpsRampRequestDuration measures requests to the OpsRamp API by method,
+	// endpoint name and status code class (2xx, 4xx, 5xx, or error when no
+	// response arrived)
+	OpsRampRequestDuration = NewHistogramVec("or_mcp_opsramp_request_duration_seconds", "Duration of OpsRamp API requests by method, endpoint and status.", DefaultLatencyBuckets, "method", "endpoint", "status")
+	// TokenRefreshes counts OpsRamp auth token fetches by outcome
+	TokenRefreshes = NewCounterVec("or_mcp_token_refreshes_total", "OpsRamp auth token fetches by outcome.", "outcome")
+)
+
+// DefaultLatencyBuckets are the histogram bucket upper bounds in seconds
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// maxMetricSeries bounds the label combinations of one metric; later
+// combinations are counted under "other" so label values taken from client
+// input cannot grow memory without bound
+const maxMetricSeries = 1000
+
+// otherLabel replaces label values once a metric has maxMetricSeries series
+const otherLabel = "other"
+
+// metric is a family of series written in the Prometheus text format
+type metric interface {
+	name() string
+	write(w io.Writer)
+}
+
+// metrics holds every registered metric by name
+var metrics = struct {
+	sync.Mutex
+	byName map[string]metric
+}{byName: make(map[string]metric)}
+
+// register adds a metric, panicking when the name is taken, as a duplicate
+// is a programming error
+func register(m metric) {
+	metrics.Lock()
+	defer metrics.Unlock()
+	if _, ok := metrics.byName[m.name()]; ok {
+		panic("metric registered twice: " + m.name())
+	}
+	metrics.byName[m.name()] = m
+}
+
+// WriteMetrics writes every registered metric in the Prometheus text
+// exposition format, sorted by name
+func WriteMetrics(w io.Writer) {
+	metrics.Lock()
+	all := make([]metric, 0, len(metrics.byName))
+	for _, m := range metrics.byName {
+		all = append(all, m)
+	}
+	metrics.Unlock()
+
+	sort.Slice(all, func(i, j int) bool { return all[i].name() < all[j].name() })
+	for _, m := range all {
+		m.write(w)
+	}
+}
+
+// series holds the label names of a metric and resolves label values to a
+// bounded set of series keys
+type series struct {
+	metricName string
+	help       string
+	labels     []string
+}
+
+func (s *series) name() string { return s.metricName }
+
+// key joins label values, or returns the overflow key when count series
+// already exist and the combination is new
+func (s *series) key(values []string, count int, exists func(string) bool) string {
+	if len(values) != len(s.labels) {
+		panic(fmt.Sprintf("metric %s takes %d label values, got %d", s.metricName, len(s.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	if count >= maxMetricSeries && !exists(key) {
+		other := make([]string, len(values))
+		for i := range other {
+			other[i] = otherLabel
+		}
+		key = strings.Join(other, "\xff")
+	}
+	return key
+}
+
+// labelEscaper escapes label values as the text format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelPairs formats a series key as {name="value",...} with extra pairs appended
+func (s *series) labelPairs(key string, extra ...string) string {
+	var pairs []string
+	if len(s.labels) > 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			pairs = append(pairs, s.labels[i]+`="`+labelEscaper.Replace(value)+`"`)
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+labelEscaper.Replace(extra[i+1])+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (s *series) header(w io.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", s.metricName, s.help, s.metricName, kind)
+}
+
+// CounterVec is a counter with one series per label combination
+type CounterVec struct {
+	series
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec registers a counter with the given label names
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{series: series{metricName: name, help: help, labels: labels}, values: make(map[string]float64)}
+	register(c)
+	return c
+}
+
+// Inc adds one to the series of the given label values
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds delta, which must not be negative, to the series of the given
+// label values
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := c.key(labelValues, len(c.values), func(k string) bool { _, ok := c.values[k]; return ok })
+	c.values[key] += delta
+}
+
+// Value returns the current value of the series of the given label values
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[strings.Join(labelValues, "\xff")]
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w, "counter")
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.metricName, c.labelPairs(key), formatFloat(c.values[key]))
+	}
+}
+
+// histogram is the state of one histogram series
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec is a histogram with one series per label combination
+type HistogramVec struct {
+	series
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogram
+}
+
+// NewHistogramVec registers a histogram with the given bucket upper bounds
+// and label names
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{
+		series:  series{metricName: name, help: help, labels: labels},
+		buckets: append([]float64(nil), buckets...),
+		values:  make(map[string]*histogram),
+	}
+	sort.Float64s(h.buckets)
+	register(h)
+	return h
+}
+
+// Observe records a value in the series of the given label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := h.key(labelValues, len(h.values), func(k string) bool { _, ok := h.values[k]; return ok })
+	series, ok := h.values[key]
+	if !ok {
+		series = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = series
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			series.counts[i]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+// ObserveSince records the seconds elapsed since start
+func (h *HistogramVec) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// Count returns the number of observations in the series of the given
+// label values
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if series, ok := h.values[strings.Join(labelValues, "\xff")]; ok {
+		return series.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w, "histogram")
+	for _, key := range sortedKeys(h.values) {
+		series := h.values[key]
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labelPairs(key, "le", formatFloat(bound)), series.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labelPairs(key, "le", "+Inf"), series.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, h.labelPairs(key), formatFloat(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, h.labelPairs(key), series.count)
+	}
+}
+
+// funcMetric is a metric without labels whose value is read when written,
+// for counts the server already keeps
+type funcMetric struct {
+	series
+	kind  string
+	value func() float64
+}
+
+// NewCounterFunc registers a counter whose value is read from value
+func NewCounterFunc(name, help string, value func() float64) {
+	register(&funcMetric{series: series{metricName: name, help: help}, kind: "counter", value: value})
+}
+
+// NewGaugeFunc registers a gauge whose value is read from value
+func NewGaugeFunc(name, help string, value func() float64) {
+	register(&funcMetric{series: series{metricName: name, help: help}, kind: "gauge", value: value})
+}
+
+func (f *funcMetric) write(w io.Writer) {
+	f.header(w, f.kind)
+	fmt.Fprintf(w, "%s %s\n", f.metricName, formatFloat(f.value()))
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// StatusClass returns the class of an HTTP status code, such as 2xx, or
+// error when there was no response
+func StatusClass(statusCode int) string {
+	if statusCode < 100 || statusCode > 599 {
+		return "error"
+	}
+	return strconv.Itoa(statusCode/100) + "xx"
+}
+
+func init() {
+	NewCounterFunc("or_mcp_opsramp_credential_failovers_total", "Switches between primary and secondary OpsRamp credentials.", func() float64 {
+		return float64(CredentialFailoverCount())
+	})
+}
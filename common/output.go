@@ -0,0 +1,139 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// applyOutputDefaults derives the output locations left unset from the
+// output directory. It runs after the environment overrides, so OUTPUT_DIR
+// moves every location that is not set on its own.
+func applyOutputDefaults(config *Config) {
+	if config.Output.Dir == "" {
+		config.Output.Dir = DefaultOutputDir
+	}
+	if config.Output.LogDir == "" {
+		config.Output.LogDir = filepath.Join(config.Output.Dir, "logs")
+	}
+	if config.Output.CrashDir == "" {
+		config.Output.CrashDir = filepath.Join(config.Output.Dir, "crash")
+	}
+	if config.State.Path == "" {
+		config.State.Path = filepath.Join(config.Output.Dir, "state", "or-mcp.db")
+	}
+	if config.AccessLog.Path == "" {
+		config.AccessLog.Path = filepath.Join(config.Output.LogDir, "access.log")
+	}
+	if len(config.HTTP.TLS.ACMEDomains) > 0 && config.HTTP.TLS.ACMECacheDir == "" {
+		config.HTTP.TLS.ACMECacheDir = filepath.Join(config.Output.Dir, "acme")
+	}
+}
+
+// OutputPaths are the files and directories the server writes to
+type OutputPaths struct {
+	LogDir        string
+	CrashDir      string
+	StatePath     string
+	AccessLogPath string
+}
+
+// ResolveOutputPaths returns the output locations from the config file, or,
+// when no config file loads, from the environment and defaults
+func ResolveOutputPaths() OutputPaths {
+	config, err := LoadConfig("")
+	if err != nil {
+		config = &Config{}
+		overrideConfigFromEnv(config)
+		applyOutputDefaults(config)
+	}
+	return OutputPaths{
+		LogDir:        config.Output.LogDir,
+		CrashDir:      config.Output.CrashDir,
+		StatePath:     config.State.Path,
+		AccessLogPath: config.AccessLog.Path,
+	}
+}
+
+// ResolveHTTPConfig returns the listener configuration from the config file,
+// or, when no config file loads, from the environment and defaults
+func ResolveHTTPConfig() (HTTPConfig, error) {
+	config, err := LoadConfig("")
+	if err != nil {
+		config = &Config{}
+		overrideConfigFromEnv(config)
+		applyOutputDefaults(config)
+	}
+	// The environment may override a validated file, so validate again
+	if err := validateHTTPConfig(&config.HTTP); err != nil {
+		return HTTPConfig{}, fmt.Errorf("http configuration validation failed: %w", err)
+	}
+	return config.HTTP, nil
+}
+
+// ResolveAuthConfig returns the HTTP authentication configuration from the
+// config file, or, when no config file loads, from the environment. A config
+// file whose auth section is invalid is an error, so a mistake there cannot
+// leave the endpoints open.
+func ResolveAuthConfig() (AuthConfig, error) {
+	config, err := LoadConfig("")
+	if errors.Is(err, errInvalidAuthConfig) {
+		return AuthConfig{}, err
+	}
+	if err != nil {
+		config = &Config{}
+		overrideConfigFromEnv(config)
+	}
+	// The environment may override a validated file, so validate again
+	if err := validateAuthConfig(&config.Auth); err != nil {
+		return AuthConfig{}, fmt.Errorf("%w: %w", errInvalidAuthConfig, err)
+	}
+	return config.Auth, nil
+}
+
+// ResolveTracingConfig returns the trace export configuration from the
+// config file, or, when no config file loads, from the environment
+func ResolveTracingConfig() (TracingConfig, error) {
+	config, err := LoadConfig("")
+	if err != nil {
+		config = &Config{}
+		overrideConfigFromEnv(config)
+	}
+	// The environment may override a validated file, so validate again
+	if err := validateTracingConfig(&config.Tracing); err != nil {
+		return TracingConfig{}, fmt.Errorf("tracing configuration validation failed: %w", err)
+	}
+	return config.Tracing, nil
+}
+
+// CheckWritable creates every output directory that is missing and verifies
+// the server can write to it, so a read-only filesystem fails at startup
+// instead of on first write. The error names each unwritable location.
+func (p OutputPaths) CheckWritable() error {
+	var errs []error
+	for _, location := range []struct{ name, dir, env string }{
+		{"log directory", p.LogDir, "LOG_DIR"},
+		{"crash directory", p.CrashDir, "CRASH_DIR"},
+		{"state store", filepath.Dir(p.StatePath), "STATE_STORE_PATH"},
+		{"access log", filepath.Dir(p.AccessLogPath), "ACCESS_LOG_PATH"},
+	} {
+		if err := checkWritableDir(location.dir); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s is not writable (set %s or OUTPUT_DIR): %w", location.name, location.dir, location.env, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkWritableDir creates dir if needed and writes and removes a probe file in it
+func checkWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	probe, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
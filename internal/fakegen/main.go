@@ -0,0 +1,465 @@
+// Command fakegen generates test fakes for Go interfaces. For each named
+// interface it writes a Fake<Name> struct in the style of counterfeiter: every
+// method records its arguments and either calls a <Method>Stub or returns the
+// values set with <Method>Returns. Fakes are written into the interface's own
+// package, typically as a _test.go file, so they need no import of it.
+//
+// Usage (from go:generate in the package directory):
+//
+//	go run ../../internal/fakegen -out fakes_test.go ResourcesAPI IntegrationsAPI
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package declaring the interfaces")
+	out := flag.String("out", "fakes_test.go", "output file, relative to dir")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: fakegen [-dir dir] [-out file] Interface...")
+		os.Exit(2)
+	}
+
+	source, err := generate(*dir, flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fakegen: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, *out), source, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "fakegen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// pkgInfo is the parsed source of the package declaring the interfaces
+type pkgInfo struct {
+	name       string
+	fset       *token.FileSet
+	interfaces map[string]*ast.InterfaceType
+	// imports maps each interface to the imports of its declaring file
+	imports map[string]map[string]string
+}
+
+// fake describes one fake to generate
+type fake struct {
+	Interface string
+	Methods   []method
+}
+
+// method describes one interface method
+type method struct {
+	Name     string
+	Field    string
+	Params   []param
+	Results  []string
+	Variadic bool
+}
+
+// param is one method parameter
+type param struct {
+	Name string
+	Type string
+}
+
+// generate returns the formatted source of the fakes for names
+func generate(dir string, names []string) ([]byte, error) {
+	pkg, err := parsePackage(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := map[string]string{"sync": "sync"}
+	fakes := make([]fake, 0, len(names))
+	for _, name := range names {
+		f, used, err := pkg.fake(name)
+		if err != nil {
+			return nil, err
+		}
+		for alias, path := range used {
+			imports[alias] = path
+		}
+		fakes = append(fakes, f)
+	}
+
+	std, other := groupImports(imports)
+	var buf bytes.Buffer
+	err = fakeTemplate.Execute(&buf, map[string]interface{}{
+		"Package":      pkg.name,
+		"StdImports":   std,
+		"OtherImports": other,
+		"Fakes":        fakes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid source: %w\n%s", err, buf.Bytes())
+	}
+	return source, nil
+}
+
+// parsePackage parses the non-test Go files in dir
+func parsePackage(dir string) (*pkgInfo, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &pkgInfo{
+		fset:       token.NewFileSet(),
+		interfaces: make(map[string]*ast.InterfaceType),
+		imports:    make(map[string]map[string]string),
+	}
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(pkg.fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		pkg.name = file.Name.Name
+
+		fileImports := make(map[string]string)
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			alias := filepath.Base(importPath)
+			if spec.Name != nil {
+				alias = spec.Name.Name
+			}
+			fileImports[alias] = importPath
+		}
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if iface, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+					pkg.interfaces[typeSpec.Name.Name] = iface
+					pkg.imports[typeSpec.Name.Name] = fileImports
+				}
+			}
+		}
+	}
+	if pkg.name == "" {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return pkg, nil
+}
+
+// fake describes the fake of the named interface and returns the imports
+// its method signatures use
+func (p *pkgInfo) fake(name string) (fake, map[string]string, error) {
+	used := make(map[string]string)
+	methods, err := p.methods(name, used, map[string]bool{})
+	if err != nil {
+		return fake{}, nil, err
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+	return fake{Interface: name, Methods: methods}, used, nil
+}
+
+// methods returns the methods of the named interface, including those of
+// interfaces it embeds from the same package
+func (p *pkgInfo) methods(name string, used map[string]string, seen map[string]bool) ([]method, error) {
+	iface, ok := p.interfaces[name]
+	if !ok {
+		return nil, fmt.Errorf("interface %s not found in package %s", name, p.name)
+	}
+	if seen[name] {
+		return nil, nil
+	}
+	seen[name] = true
+
+	var methods []method
+	for _, field := range iface.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			embedded, ok := field.Type.(*ast.Ident)
+			if !ok {
+				return nil, fmt.Errorf("%s embeds %s, which is not an interface of package %s", name, p.expr(field.Type), p.name)
+			}
+			inherited, err := p.methods(embedded.Name, used, seen)
+			if err != nil {
+				return nil, err
+			}
+			methods = append(methods, inherited...)
+			continue
+		}
+
+		ast.Inspect(funcType, func(node ast.Node) bool {
+			if sel, ok := node.(*ast.SelectorExpr); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok {
+					if path, ok := p.imports[name][ident.Name]; ok {
+						used[ident.Name] = path
+					}
+				}
+			}
+			return true
+		})
+
+		m := method{Name: field.Names[0].Name, Field: lowerFirst(field.Names[0].Name)}
+		for _, f := range funcType.Params.List {
+			typ := p.expr(f.Type)
+			if ellipsis, ok := f.Type.(*ast.Ellipsis); ok {
+				m.Variadic = true
+				typ = "[]" + p.expr(ellipsis.Elt)
+			}
+			for i := 0; i < max(1, len(f.Names)); i++ {
+				m.Params = append(m.Params, param{Name: fmt.Sprintf("arg%d", len(m.Params)+1), Type: typ})
+			}
+		}
+		if funcType.Results != nil {
+			for _, f := range funcType.Results.List {
+				for i := 0; i < max(1, len(f.Names)); i++ {
+					m.Results = append(m.Results, p.expr(f.Type))
+				}
+			}
+		}
+		methods = append(methods, m)
+	}
+	return methods, nil
+}
+
+// expr renders a type expression as source
+func (p *pkgInfo) expr(node ast.Expr) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, p.fset, node); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
+// groupImports returns the standard library and other import lines, each
+// in path order
+func groupImports(imports map[string]string) (std, other []string) {
+	paths := make([]string, 0, len(imports))
+	for _, path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	aliases := make(map[string]string, len(imports))
+	for alias, path := range imports {
+		aliases[path] = alias
+	}
+	for _, path := range paths {
+		line := strconv.Quote(path)
+		if alias := aliases[path]; alias != filepath.Base(path) {
+			line = alias + " " + line
+		}
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			other = append(other, line)
+		} else {
+			std = append(std, line)
+		}
+	}
+	return std, other
+}
+
+// lowerFirst lower-cases the first letter of an identifier
+func lowerFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+var fakeTemplate = template.Must(template.New("fakes").Funcs(template.FuncMap{
+	"params": func(m method) string {
+		parts := make([]string, len(m.Params))
+		for i, p := range m.Params {
+			typ := p.Type
+			if m.Variadic && i == len(m.Params)-1 {
+				typ = "..." + strings.TrimPrefix(typ, "[]")
+			}
+			parts[i] = p.Name + " " + typ
+		}
+		return strings.Join(parts, ", ")
+	},
+	"paramTypes": func(m method) string {
+		parts := make([]string, len(m.Params))
+		for i, p := range m.Params {
+			typ := p.Type
+			if m.Variadic && i == len(m.Params)-1 {
+				typ = "..." + strings.TrimPrefix(typ, "[]")
+			}
+			parts[i] = typ
+		}
+		return strings.Join(parts, ", ")
+	},
+	"args": func(m method) string {
+		parts := make([]string, len(m.Params))
+		for i, p := range m.Params {
+			parts[i] = p.Name
+		}
+		call := strings.Join(parts, ", ")
+		if m.Variadic {
+			call += "..."
+		}
+		return call
+	},
+	"results": func(m method) string {
+		switch len(m.Results) {
+		case 0:
+			return ""
+		case 1:
+			return m.Results[0]
+		}
+		return "(" + strings.Join(m.Results, ", ") + ")"
+	},
+	"namedResults": func(m method) string {
+		parts := make([]string, len(m.Results))
+		for i, r := range m.Results {
+			parts[i] = fmt.Sprintf("result%d %s", i+1, r)
+		}
+		return strings.Join(parts, ", ")
+	},
+	"resultValues": func(prefix string, m method) string {
+		parts := make([]string, len(m.Results))
+		for i := range m.Results {
+			parts[i] = fmt.Sprintf("%sresult%d", prefix, i+1)
+		}
+		return strings.Join(parts, ", ")
+	},
+	"resultFields": func(m method) string {
+		parts := make([]string, len(m.Results))
+		for i, r := range m.Results {
+			parts[i] = fmt.Sprintf("result%d %s", i+1, r)
+		}
+		return strings.Join(parts, "\n")
+	},
+	"argValues": func(prefix string, m method) string {
+		parts := make([]string, len(m.Params))
+		for i, p := range m.Params {
+			parts[i] = prefix + p.Name
+		}
+		return strings.Join(parts, ", ")
+	},
+	"argTypes": func(m method) string {
+		parts := make([]string, len(m.Params))
+		for i, p := range m.Params {
+			parts[i] = p.Type
+		}
+		if len(parts) > 1 {
+			return "(" + strings.Join(parts, ", ") + ")"
+		}
+		return strings.Join(parts, ", ")
+	},
+}).Parse(`// Code generated by fakegen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .StdImports}}
+	{{.}}
+{{- end}}
+{{if .OtherImports}}
+{{- range .OtherImports}}
+	{{.}}
+{{- end}}
+{{- end}}
+)
+{{range $fake := .Fakes}}
+// Fake{{.Interface}} is a generated fake of {{.Interface}}
+type Fake{{.Interface}} struct {
+{{- range .Methods}}
+	{{.Name}}Stub func({{paramTypes .}}) {{results .}}
+	{{.Field}}Mutex sync.RWMutex
+	{{- if .Params}}
+	{{.Field}}ArgsForCall []struct {
+		{{- range .Params}}
+		{{.Name}} {{.Type}}
+		{{- end}}
+	}
+	{{- else}}
+	{{.Field}}Calls int
+	{{- end}}
+	{{- if .Results}}
+	{{.Field}}Returns struct {
+		{{resultFields .}}
+	}
+	{{- end}}
+{{- end}}
+}
+{{range .Methods}}
+// {{.Name}} records the call and returns the stubbed results
+func (fake *Fake{{$fake.Interface}}) {{.Name}}({{params .}}) {{results .}} {
+	fake.{{.Field}}Mutex.Lock()
+	{{- if .Params}}
+	fake.{{.Field}}ArgsForCall = append(fake.{{.Field}}ArgsForCall, struct {
+		{{- range .Params}}
+		{{.Name}} {{.Type}}
+		{{- end}}
+	}{ {{- argValues "" .}} })
+	{{- else}}
+	fake.{{.Field}}Calls++
+	{{- end}}
+	stub := fake.{{.Name}}Stub
+	{{- if .Results}}
+	returns := fake.{{.Field}}Returns
+	{{- end}}
+	fake.{{.Field}}Mutex.Unlock()
+	if stub != nil {
+		{{if .Results}}return {{end}}stub({{args .}})
+		{{- if not .Results}}
+		return
+		{{- end}}
+	}
+	{{- if .Results}}
+	return {{resultValues "returns." .}}
+	{{- end}}
+}
+
+// {{.Name}}CallCount returns the number of calls to {{.Name}}
+func (fake *Fake{{$fake.Interface}}) {{.Name}}CallCount() int {
+	fake.{{.Field}}Mutex.RLock()
+	defer fake.{{.Field}}Mutex.RUnlock()
+	{{- if .Params}}
+	return len(fake.{{.Field}}ArgsForCall)
+	{{- else}}
+	return fake.{{.Field}}Calls
+	{{- end}}
+}
+{{if .Params}}
+// {{.Name}}ArgsForCall returns the arguments of the i-th call to {{.Name}}
+func (fake *Fake{{$fake.Interface}}) {{.Name}}ArgsForCall(i int) {{argTypes .}} {
+	fake.{{.Field}}Mutex.RLock()
+	defer fake.{{.Field}}Mutex.RUnlock()
+	call := fake.{{.Field}}ArgsForCall[i]
+	return {{argValues "call." .}}
+}
+{{end}}
+{{- if .Results}}
+// {{.Name}}Returns sets the results of calls to {{.Name}} without a stub
+func (fake *Fake{{$fake.Interface}}) {{.Name}}Returns({{namedResults .}}) {
+	fake.{{.Field}}Mutex.Lock()
+	defer fake.{{.Field}}Mutex.Unlock()
+	fake.{{.Name}}Stub = nil
+	fake.{{.Field}}Returns = struct {
+		{{resultFields .}}
+	}{ {{- resultValues "" .}} }
+}
+{{end}}
+{{- end}}
+var _ {{.Interface}} = new(Fake{{.Interface}})
+{{end}}`))
@@ -40,6 +40,9 @@ type CallToolResult struct {
 	//
 	// If not set, this is assumed to be false (the call was successful).
 	IsError bool `json:"isError,omitempty"`
+	// Optional JSON object holding the result for clients that consume typed
+	// results. It conforms to the tool's OutputSchema, if the tool has one.
+	StructuredContent any `json:"structuredContent,omitempty"`
 }
 
 // CallToolRequest is used by the client to invoke a tool provided by the server.
@@ -480,6 +483,11 @@ type Tool struct {
 	RawInputSchema json.RawMessage `json:"-"` // Hide this from JSON marshaling
 	// Optional properties describing tool behavior
 	Annotations ToolAnnotation `json:"annotations"`
+	// Optional implementation-specific metadata, serialized as _meta
+	Meta map[string]any `json:"_meta,omitempty"`
+	// Optional JSON Schema object describing the structuredContent of the
+	// tool's successful results
+	OutputSchema map[string]any `json:"outputSchema,omitempty"`
 }
 
 // GetName returns the name of the tool.
@@ -512,6 +520,14 @@ func (t Tool) MarshalJSON() ([]byte, error) {
 
 	m["annotations"] = t.Annotations
 
+	if len(t.Meta) > 0 {
+		m["_meta"] = t.Meta
+	}
+
+	if len(t.OutputSchema) > 0 {
+		m["outputSchema"] = t.OutputSchema
+	}
+
 	return json.Marshal(m)
 }
 
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/httperr"
+)
+
+// CatalogStatus describes how a catalog pull was satisfied
+type CatalogStatus string
+
+const (
+	// CatalogFetched means the catalog was downloaded and differs from the cached copy
+	CatalogFetched CatalogStatus = "fetched"
+	// CatalogNotModified means OpsRamp answered 304 to a conditional request
+	CatalogNotModified CatalogStatus = "notModified"
+	// CatalogUnchanged means the downloaded catalog hashed the same as the cached copy
+	CatalogUnchanged CatalogStatus = "unchanged"
+	// CatalogCached means the cached copy was fresh and no request was made
+	CatalogCached CatalogStatus = "cached"
+)
+
+// CatalogEntry is the cached copy of one catalog endpoint
+type CatalogEntry struct {
+	ETag         string
+	LastModified string
+	Hash         string
+	Body         []byte
+	ValidatedAt  time.Time
+}
+
+// CatalogCache caches slow-changing catalog responses such as resource and
+// integration types. When OpsRamp returns ETag or Last-Modified, every pull is
+// revalidated with a conditional request and a 304 is served from the cache.
+// Without validators the body is content-hashed and reused for maxAge before
+// it is fetched again.
+type CatalogCache struct {
+	mu      sync.Mutex
+	entries map[string]*CatalogEntry
+	maxAge  time.Duration
+	now     func() time.Time
+}
+
+// NewCatalogCache creates a catalog cache; maxAge bounds how long entries
+// without validators are served without contacting OpsRamp
+func NewCatalogCache(maxAge time.Duration) *CatalogCache {
+	return &CatalogCache{
+		entries: make(map[string]*CatalogEntry),
+		maxAge:  maxAge,
+		now:     time.Now,
+	}
+}
+
+// Fresh returns the cached body for key if it can be served without a request
+func (c *CatalogCache) Fresh(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.ETag != "" || entry.LastModified != "" {
+		return nil, false
+	}
+	if c.now().Sub(entry.ValidatedAt) >= c.maxAge {
+		return nil, false
+	}
+	return entry.Body, true
+}
+
+// Clear drops every cached catalog, so the next pull of each downloads it
+// again, and returns how many were dropped
+func (c *CatalogCache) Clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dropped := len(c.entries)
+	c.entries = make(map[string]*CatalogEntry)
+	return dropped
+}
+
+// Prepare adds conditional request headers for key's cached validators
+func (c *CatalogCache) Prepare(key string, req *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// Resolve records a catalog response for key and returns the body to use.
+// A 304 response yields the cached body.
+func (c *CatalogCache) Resolve(key string, resp *http.Response, body []byte) ([]byte, CatalogStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, cached := c.entries[key]
+	if resp.StatusCode == http.StatusNotModified {
+		if !cached {
+			return nil, "", fmt.Errorf("received 304 for uncached catalog %s", key)
+		}
+		entry.ValidatedAt = c.now()
+		return entry.Body, CatalogNotModified, nil
+	}
+
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+
+	status := CatalogFetched
+	if cached && entry.Hash == hash {
+		status = CatalogUnchanged
+	}
+
+	c.entries[key] = &CatalogEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Hash:         hash,
+		Body:         body,
+		ValidatedAt:  c.now(),
+	}
+	return body, status, nil
+}
+
+// GetCatalog makes a cached, conditional GET request for a catalog endpoint
+func (c *OpsRampClient) GetCatalog(ctx context.Context, endpoint string, result interface{}) (CatalogStatus, error) {
+	key := c.baseURL + endpoint
+
+	if body, ok := c.catalogs.Fresh(key); ok {
+		c.logger.Debug("Serving catalog %s from cache", endpoint)
+		return CatalogCached, json.Unmarshal(body, result)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	c.catalogs.Prepare(key, req)
+
+	c.logger.Info("Sending conditional GET request to %s", req.URL.String())
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Request failed: %v", err)
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusNotModified && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		statusErr := httperr.FromResponse(resp, body)
+		c.logger.Error("%v", statusErr)
+		return "", statusErr
+	}
+
+	body, status, err := c.catalogs.Resolve(key, resp, body)
+	if err != nil {
+		return "", err
+	}
+	c.logger.Info("Catalog %s: %s", endpoint, status)
+
+	if err := json.Unmarshal(body, result); err != nil {
+		c.logger.Error("Failed to parse response: %v", err)
+		return status, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return status, nil
+}
+
+// catalogEndpoints names the registered endpoints served through the catalog cache
+var catalogEndpoints = []string{"resources.types"}
+
+// WarmCatalogs pulls every catalog into the client's cache, so the first
+// calls that need one are not kept waiting on OpsRamp. It returns the outcome
+// of each pull by endpoint name.
+func (c *OpsRampClient) WarmCatalogs(ctx context.Context) map[string]string {
+	outcomes := make(map[string]string, len(catalogEndpoints))
+	for _, name := range catalogEndpoints {
+		path, err := c.EndpointPath(name)
+		if err == nil {
+			var catalog json.RawMessage
+			var status CatalogStatus
+			if status, err = c.GetCatalog(ctx, path, &catalog); err == nil {
+				outcomes[name] = string(status)
+				continue
+			}
+		}
+		outcomes[name] = "failed: " + err.Error()
+	}
+	return outcomes
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func newCatalogTestClient(t *testing.T, handler http.HandlerFunc, cacheTTL int) *OpsRampClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/token" {
+			w.Write([]byte(`{"access_token": "token", "expires_in": 3600}`))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: server.URL,
+		AuthURL:   server.URL + "/auth/token",
+		TenantID:  "tenant-1",
+		Resources: common.ResourcesConfig{CacheTTL: cacheTTL},
+	}})
+}
+
+func TestGetCatalogWithETag(t *testing.T) {
+	fullResponses := 0
+	c := newCatalogTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"resourceTypes": [{"name": "server"}]}`))
+	}, 300)
+
+	for i, want := range []CatalogStatus{CatalogFetched, CatalogNotModified, CatalogNotModified} {
+		var result map[string]interface{}
+		status, err := c.GetCatalog(context.Background(), "/api/v2/tenants/tenant-1/resources/types", &result)
+		if err != nil {
+			t.Fatalf("Pull %d failed: %v", i, err)
+		}
+		if status != want {
+			t.Errorf("Pull %d: expected %s, got %s", i, want, status)
+		}
+		if len(result["resourceTypes"].([]interface{})) != 1 {
+			t.Errorf("Pull %d: expected cached catalog body, got %v", i, result)
+		}
+	}
+	if fullResponses != 1 {
+		t.Errorf("Expected a single full response, got %d", fullResponses)
+	}
+}
+
+func TestGetCatalogWithoutValidators(t *testing.T) {
+	requests := 0
+	c := newCatalogTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"resourceTypes": []}`))
+	}, 60)
+
+	now := time.Now()
+	c.catalogs.now = func() time.Time { return now }
+
+	endpoint := "/api/v2/tenants/tenant-1/resources/types"
+	var result map[string]interface{}
+	for i, want := range []CatalogStatus{CatalogFetched, CatalogCached} {
+		status, err := c.GetCatalog(context.Background(), endpoint, &result)
+		if err != nil || status != want {
+			t.Fatalf("Pull %d: expected %s, got %s (%v)", i, want, status, err)
+		}
+	}
+
+	now = now.Add(2 * time.Minute)
+	status, err := c.GetCatalog(context.Background(), endpoint, &result)
+	if err != nil || status != CatalogUnchanged {
+		t.Fatalf("Expected stale entry to be refetched and hash as unchanged, got %s (%v)", status, err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
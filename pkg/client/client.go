@@ -7,21 +7,60 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-"strings"
 	"net/url"
 	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/httperr"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // OpsRampClient is the client for the OpsRamp API
 type OpsRampClient struct {
 	baseURL    string
 	tenantID   string
+	partnerID  string
 	authClient *common.AuthClient
 	httpClient *http.Client
+	catalogs   *CatalogCache
+	entities   *EntityCache
+	responses  *EntityCache
+	reads      *ReadGroup
+	pools      *RequestPools
 	logger     *common.CustomLogger
+
+	// rejectionsMu guards rejections, the recent times OpsRamp rejected a
+	// token before it expired
+	rejectionsMu sync.Mutex
+	rejections   []time.Time
+}
+
+const (
+	// tokenRejectionAlertCount rejected tokens within tokenRejectionWindow
+	// raise an error log: OpsRamp keeps invalidating tokens, e.g. because
+	// the credentials were rotated or revoked
+	tokenRejectionAlertCount = 3
+	tokenRejectionWindow     = 10 * time.Minute
+)
+
+// tokenRejections counts the requests retried with a new token after
+// OpsRamp rejected the cached one
+var tokenRejections atomic.Int64
+
+// TokenRejectionCount returns the number of requests retried since startup
+// because OpsRamp rejected an auth token before it expired
+func TokenRejectionCount() int64 {
+	return tokenRejections.Load()
 }
 
 // NewOpsRampClient creates a new OpsRamp API client
@@ -31,21 +70,50 @@ func NewOpsRampClient(config *common.Config) *OpsRampClient {
 		ClientID:     config.OpsRamp.AuthKey,
 		ClientSecret: config.OpsRamp.AuthSecret,
 		TokenURL:     config.OpsRamp.AuthURL,
+
+		SecondaryClientID:     config.OpsRamp.SecondaryAuthKey,
+		SecondaryClientSecret: config.OpsRamp.SecondaryAuthSecret,
 	}
 	authClient := common.NewAuthClient(authConfig)
 
-	// Get the logger
-	logger := common.GetLogger()
+	// Label the logger with the primary tenant; the tenant registry relabels
+	// clients of additional tenants
+	logger := common.GetLogger().WithLabels(config.TelemetryLabels(DefaultTenant)).WithModule(common.ModuleClient)
 
 	return &OpsRampClient{
 		baseURL:    config.OpsRamp.TenantURL,
 		tenantID:   config.OpsRamp.TenantID,
+		partnerID:  config.OpsRamp.PartnerID,
 		authClient: authClient,
-		httpClient: &http.Client{Timeout: 60 * time.Second},
-		logger:     logger,
+		httpClient: &http.Client{
+			Timeout:   60 * time.Second,
+			Transport: NewEgressPolicy(config).Transport(nil),
+		},
+		catalogs:  NewCatalogCache(time.Duration(config.OpsRamp.Resources.CacheTTL) * time.Second),
+		entities:  SharedEntityCache(config.OpsRamp),
+		responses: SharedResponseCache(config.OpsRamp),
+		reads:     SharedReadGroup(config.OpsRamp),
+		pools:     SharedRequestPools(config.OpsRamp, config.Concurrency),
+		logger:    logger,
 	}
 }
 
+// sharedClients holds the client of each tenant and credential
+var sharedClients sync.Map
+
+// SharedClient returns the client shared by all tools of the tenant in
+// config that use the same credentials, creating it on first use. Sharing
+// it lets the tools reuse one access token and one pool of connections
+// instead of each authenticating and connecting on its own.
+func SharedClient(config *common.Config) *OpsRampClient {
+	key := config.OpsRamp.TenantURL + "|" + config.OpsRamp.TenantID + "|" + config.OpsRamp.AuthKey
+	if c, ok := sharedClients.Load(key); ok {
+		return c.(*OpsRampClient)
+	}
+	c, _ := sharedClients.LoadOrStore(key, NewOpsRampClient(config))
+	return c.(*OpsRampClient)
+}
+
 // Request makes an authenticated request to the OpsRamp API
 func (c *OpsRampClient) Request(ctx context.Context, method, endpoint string, body interface{}, result interface{}) error {
 	_, err := c.RequestWithStatusCode(ctx, method, endpoint, body, result)
@@ -53,78 +121,148 @@ func (c *OpsRampClient) Request(ctx context.Context, method, endpoint string, bo
 }
 
 // RequestWithStatusCode makes an authenticated request to the OpsRamp API and returns the status code
-func (c *OpsRampClient) RequestWithStatusCode(ctx context.Context, method, endpoint string, body interface{}, result interface{}) (int, error) {
+func (c *OpsRampClient) RequestWithStatusCode(ctx context.Context, method, endpoint string, body interface{}, result interface{}) (statusCode int, err error) {
 	// Log the request
 	c.logger.Debug("API Request: %s %s", method, endpoint)
 
-	// Build the full URL
-// Build the full URL
-u, err := url.Parse(c.baseURL)
-if err != nil {
-c.logger.Error("Invalid base URL: %v", err)
-return 0, fmt.Errorf("invalid base URL: %w", err)
-}
-
-// Check if endpoint contains query parameters
-endpointParts := strings.SplitN(endpoint, "?", 2)
-u.Path = path.Join(u.Path, endpointParts[0])
+	// Trace the request, including its retries, as a child of the tool call
+	endpointName := EndpointNameForRequest(method, endpoint)
+	ctx, span := tracing.Tracer().Start(ctx, "OpsRamp "+method+" "+endpointName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("opsramp.endpoint", endpointName),
+			attribute.String("opsramp.tenant_id", c.tenantID),
+		))
+	defer func() {
+		if statusCode != 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", statusCode))
+		}
+		tracing.End(span, err)
+	}()
+
+	// Identical concurrent GETs share one OpsRamp call
+	var respBody []byte
+	if method == http.MethodGet {
+		var shared bool
+		statusCode, respBody, shared, err = c.reads.Do(ctx, endpoint, func(ctx context.Context) (int, []byte, error) {
+			return c.send(ctx, method, endpoint, nil)
+		})
+		if shared {
+			c.logger.Debug("Shared in-flight response for GET %s", endpoint)
+			span.SetAttributes(attribute.Bool("opsramp.shared_response", true))
+		}
+	} else {
+		statusCode, respBody, err = c.send(ctx, method, endpoint, body)
+	}
+	if err != nil {
+		recordUpstreamFailure(ctx, method, endpoint, statusCode, err)
+		return statusCode, err
+	}
 
-// If there are query parameters, add them to the URL
-if len(endpointParts) > 1 {
-u.RawQuery = endpointParts[1]
-}
+	// Parse the response if a result container was provided
+	if result != nil {
+		// Log the response body (truncated if too large)
+		respBodyStr := string(respBody)
+		if len(respBodyStr) > 1000 {
+			c.logger.Debug("Response Body (truncated): %s...", respBodyStr[:1000])
+		} else {
+			c.logger.Debug("Response Body: %s", respBodyStr)
+		}
 
-// Log the full URL
-c.logger.Debug("Full URL: %s", u.String())
-	// Prepare request body if provided
-	var reqBody io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			c.logger.Error("Failed to marshal request body: %v", err)
-			return 0, fmt.Errorf("failed to marshal request body: %w", err)
+		// Parse the response
+		if err := json.Unmarshal(respBody, result); err != nil {
+			c.logger.Error("Failed to parse response: %v", err)
+			return statusCode, fmt.Errorf("failed to parse response: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
-		c.logger.Debug("Request Body: %s", string(jsonBody))
+
+		c.logger.Debug("Response successfully parsed")
 	}
 
-	// Create the request
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	c.logger.Info("Request completed successfully")
+	return statusCode, nil
+}
+
+// send makes a request to the OpsRamp API and returns the status code and
+// the body of a successful response. OpsRamp invalidates tokens before they
+// expire when credentials are rotated, so a request rejected with 401 is
+// retried once with a new token. When OpsRamp rejects the new token too and
+// standby credentials are configured, the client switches to them and
+// retries once more. The request first waits for a slot in the pool of its
+// priority.
+func (c *OpsRampClient) send(ctx context.Context, method, endpoint string, body interface{}) (int, []byte, error) {
+	release, err := c.pools.Acquire(ctx)
 	if err != nil {
-		c.logger.Error("Failed to create request: %v", err)
-		return 0, fmt.Errorf("failed to create request: %w", err)
+		return 0, nil, fmt.Errorf("request not sent: %w", err)
 	}
+	defer release()
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	statusCode, respBody, token, err := c.sendOnce(ctx, method, endpoint, body)
+	if statusCode != http.StatusUnauthorized {
+		return statusCode, respBody, err
+	}
 
-	// Get and set the auth token
-	token, err := c.authClient.GetToken()
-	if err != nil {
-		c.logger.Error("Failed to get auth token: %v", err)
-		return 0, fmt.Errorf("failed to get auth token: %w", err)
+	c.authClient.Invalidate(token)
+	c.recordTokenRejection()
+	c.logger.Warn("OpsRamp rejected the auth token for %s %s; retrying with a new token", method, endpoint)
+	statusCode, respBody, token, err = c.sendOnce(ctx, method, endpoint, body)
+	if statusCode != http.StatusUnauthorized || !c.authClient.FailOver(token) {
+		return statusCode, respBody, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
-	c.logger.Debug("Auth token obtained and set")
 
-	// Set tenant ID if provided
-	if c.tenantID != "" {
-		req.Header.Set("X-Tenant-ID", c.tenantID)
-		c.logger.Debug("Tenant ID set: %s", c.tenantID)
+	c.logger.Warn("OpsRamp rejected a new auth token for %s %s; retrying with the standby credentials", method, endpoint)
+	statusCode, respBody, _, err = c.sendOnce(ctx, method, endpoint, body)
+	return statusCode, respBody, err
+}
+
+// recordTokenRejection counts a rejected token, logging an error when
+// OpsRamp rejects tokens repeatedly
+func (c *OpsRampClient) recordTokenRejection() {
+	tokenRejections.Add(1)
+
+	c.rejectionsMu.Lock()
+	defer c.rejectionsMu.Unlock()
+	now := time.Now()
+	recent := c.rejections[:0]
+	for _, at := range c.rejections {
+		if now.Sub(at) < tokenRejectionWindow {
+			recent = append(recent, at)
+		}
+	}
+	c.rejections = append(recent, now)
+	if len(c.rejections) >= tokenRejectionAlertCount {
+		c.logger.Error("OpsRamp rejected auth tokens %d times in the last %s; check whether the API credentials were rotated or revoked",
+			len(c.rejections), tokenRejectionWindow)
+		c.rejections = nil
+	}
+}
+
+// sendOnce makes one request to the OpsRamp API and returns the status code,
+// the body of a successful response and the auth token it was sent with
+func (c *OpsRampClient) sendOnce(ctx context.Context, method, endpoint string, body interface{}) (int, []byte, string, error) {
+	req, err := c.newRequest(ctx, method, endpoint, body)
+	if err != nil {
+		return 0, nil, "", err
 	}
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
 
 	// Log request details
-	c.logger.Info("Sending %s request to %s", method, u.String())
+	c.logger.Info("Sending %s request to %s", method, req.URL.String())
 
 	// Send the request
 	startTime := time.Now()
 	resp, err := c.httpClient.Do(req)
 	duration := time.Since(startTime)
 
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	common.OpsRampRequestDuration.Observe(duration.Seconds(), method, EndpointNameForRequest(method, endpoint), common.StatusClass(statusCode))
+
 	if err != nil {
 		c.logger.Error("Request failed: %v", err)
-		return 0, fmt.Errorf("request failed: %w", err)
+		return 0, nil, token, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -135,39 +273,91 @@ c.logger.Debug("Full URL: %s", u.String())
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		// Try to read error response
 		errorBody, _ := io.ReadAll(resp.Body)
-		errorMsg := fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(errorBody))
-		c.logger.Error(errorMsg)
-		return resp.StatusCode, fmt.Errorf(errorMsg)
+		statusErr := httperr.FromResponse(resp, errorBody)
+		c.logger.Error("%v", statusErr)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			session.Log(ctx, mcp.LoggingLevelWarning, "opsramp", map[string]interface{}{
+				"event":      "rateLimited",
+				"method":     method,
+				"endpoint":   endpoint,
+				"retryAfter": resp.Header.Get("Retry-After"),
+			})
+		}
+		return resp.StatusCode, nil, token, statusErr
 	}
 
-	// Parse the response if a result container was provided
-	if result != nil {
-		// Read the response body
-		respBody, err := io.ReadAll(resp.Body)
+	// Read the response body
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body: %v", err)
+		return resp.StatusCode, nil, token, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return resp.StatusCode, respBody, token, nil
+}
+
+// newRequest builds an authenticated request for an OpsRamp API endpoint
+func (c *OpsRampClient) newRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Request, error) {
+	// Build the full URL
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		c.logger.Error("Invalid base URL: %v", err)
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	// Check if endpoint contains query parameters
+	endpointParts := strings.SplitN(endpoint, "?", 2)
+	u.Path = path.Join(u.Path, endpointParts[0])
+
+	// If there are query parameters, add them to the URL
+	if len(endpointParts) > 1 {
+		u.RawQuery = endpointParts[1]
+	}
+
+	// Log the full URL
+	c.logger.Debug("Full URL: %s", u.String())
+
+	// Prepare request body if provided
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
 		if err != nil {
-			c.logger.Error("Failed to read response body: %v", err)
-			return resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
+			c.logger.Error("Failed to marshal request body: %v", err)
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
+		reqBody = bytes.NewBuffer(jsonBody)
+		c.logger.Debug("Request Body: %s", string(jsonBody))
+	}
 
-		// Log the response body (truncated if too large)
-		respBodyStr := string(respBody)
-		if len(respBodyStr) > 1000 {
-			c.logger.Debug("Response Body (truncated): %s...", respBodyStr[:1000])
-		} else {
-			c.logger.Debug("Response Body: %s", respBodyStr)
-		}
+	// Create the request
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		c.logger.Error("Failed to create request: %v", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
-		// Parse the response
-		if err := json.Unmarshal(respBody, result); err != nil {
-			c.logger.Error("Failed to parse response: %v", err)
-			return resp.StatusCode, fmt.Errorf("failed to parse response: %w", err)
-		}
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
 
-		c.logger.Debug("Response successfully parsed")
+	// Get and set the auth token
+	token, err := c.authClient.GetToken()
+	if err != nil {
+		c.logger.Error("Failed to get auth token: %v", err)
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
 	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	c.logger.Debug("Auth token obtained and set")
 
-	c.logger.Info("Request completed successfully")
-	return resp.StatusCode, nil
+	// Propagate the trace so OpsRamp-side logs can be correlated
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	// Set tenant ID if provided; partner-scoped endpoints identify the partner tenant
+	if tenantID := c.headerTenantID(endpointParts[0]); tenantID != "" {
+		req.Header.Set("X-Tenant-ID", tenantID)
+		c.logger.Debug("Tenant ID set: %s", tenantID)
+	}
+
+	return req, nil
 }
 
 // Get makes a GET request to the OpsRamp API
@@ -205,11 +395,50 @@ func (c *OpsRampClient) Patch(ctx context.Context, endpoint string, body interfa
 	return c.Request(ctx, http.MethodPatch, endpoint, body, result)
 }
 
+// Logger returns the logger for calls through this client, labelled with the
+// client's tenant
+func (c *OpsRampClient) Logger() *common.CustomLogger {
+	if c == nil || c.logger == nil {
+		return common.GetLogger()
+	}
+	return c.logger
+}
+
+// Entities returns the cache of entities fetched by ID for the client's tenant
+func (c *OpsRampClient) Entities() *EntityCache {
+	if c == nil {
+		return nil
+	}
+	return c.entities
+}
+
+// Responses returns the cache of list and search responses for the
+// client's tenant
+func (c *OpsRampClient) Responses() *EntityCache {
+	if c == nil {
+		return nil
+	}
+	return c.responses
+}
+
+// Catalogs returns the client's catalog cache
+func (c *OpsRampClient) Catalogs() *CatalogCache {
+	if c == nil {
+		return nil
+	}
+	return c.catalogs
+}
+
 // GetTenantID returns the tenant ID
 func (c *OpsRampClient) GetTenantID() string {
 	return c.tenantID
 }
 
+// GetPartnerID returns the MSP partner tenant ID, if configured
+func (c *OpsRampClient) GetPartnerID() string {
+	return c.partnerID
+}
+
 // Global client instance
 var globalClient *OpsRampClient
 var clientInitialized bool
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -67,3 +68,137 @@ func TestOpsRampClient(t *testing.T) {
 		}
 	})
 }
+
+func TestRejectedTokenIsReplacedAndRetried(t *testing.T) {
+	issued, valid := 0, "token-1"
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/token" {
+			issued++
+			fmt.Fprintf(w, `{"access_token": "token-%d", "expires_in": 3600}`, issued)
+			return
+		}
+		calls = append(calls, r.Method+" "+r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer "+valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id": "r1"}`))
+	}))
+	defer server.Close()
+
+	c := NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: server.URL,
+		AuthURL:   server.URL + "/auth/token",
+		TenantID:  "tenant-1",
+	}})
+	var result map[string]interface{}
+	if err := c.Post(context.Background(), "/api/v2/tenants/tenant-1/resources", map[string]string{"name": "r1"}, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	// OpsRamp invalidates the token early, e.g. after a credential rotation
+	valid = "token-2"
+	before := TokenRejectionCount()
+	if err := c.Post(context.Background(), "/api/v2/tenants/tenant-1/resources", map[string]string{"name": "r1"}, &result); err != nil {
+		t.Fatalf("Expected the request to be retried with a new token, got %v", err)
+	}
+	if issued != 2 || result["id"] != "r1" || TokenRejectionCount() != before+1 {
+		t.Errorf("Expected one new token and one counted rejection, got %d tokens and %d rejections", issued, TokenRejectionCount()-before)
+	}
+	want := []string{"POST Bearer token-1", "POST Bearer token-1", "POST Bearer token-2"}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+
+	// Credentials that no longer work are retried only once
+	valid = "revoked"
+	calls = nil
+	if err := c.Get(context.Background(), "/api/v2/tenants/tenant-1/resources/r1", &result); err == nil {
+		t.Error("Expected the 401 to be returned after one retry")
+	}
+	if len(calls) != 2 {
+		t.Errorf("Expected one retry, got %d calls", len(calls))
+	}
+}
+
+func TestClientFailsOverToSecondaryCredentials(t *testing.T) {
+	// The token endpoint accepts key-1 and key-2, and OpsRamp rejects the
+	// tokens in revokedKeys
+	revokedKeys := map[string]bool{}
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/token" {
+			key := r.FormValue("client_id")
+			if key != "key-1" && key != "key-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, `{"access_token": "token-%s", "expires_in": 3600}`, key)
+			return
+		}
+		calls = append(calls, r.Header.Get("Authorization"))
+		if revokedKeys[r.Header.Get("Authorization")] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id": "r1"}`))
+	}))
+	defer server.Close()
+
+	newClient := func(primary, secondary string) *OpsRampClient {
+		return NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{
+			TenantURL: server.URL, AuthURL: server.URL + "/auth/token", TenantID: "tenant-1",
+			AuthKey: primary, AuthSecret: "secret", SecondaryAuthKey: secondary, SecondaryAuthSecret: "secret",
+		}})
+	}
+	var result map[string]interface{}
+
+	// The token endpoint refuses the primary credentials
+	before := common.CredentialFailoverCount()
+	c := newClient("key-old", "key-2")
+	if err := c.Get(context.Background(), "/api/v2/tenants/tenant-1/resources/r1", &result); err != nil {
+		t.Fatalf("Expected the secondary credentials to be used, got %v", err)
+	}
+	if !c.authClient.UsingSecondary() || common.CredentialFailoverCount() != before+1 {
+		t.Errorf("Expected one counted switch to the secondary credentials")
+	}
+
+	// OpsRamp stops accepting tokens of the primary credentials
+	c = newClient("key-1", "key-2")
+	if err := c.Get(context.Background(), "/api/v2/tenants/tenant-1/resources/r1", &result); err != nil || c.authClient.UsingSecondary() {
+		t.Fatalf("Expected the primary credentials while they work, got %v", err)
+	}
+	revokedKeys["Bearer token-key-1"] = true
+	calls = nil
+	if err := c.Get(context.Background(), "/api/v2/tenants/tenant-1/resources/r1", &result); err != nil {
+		t.Fatalf("Expected the request to succeed with the secondary credentials, got %v", err)
+	}
+	want := []string{"Bearer token-key-1", "Bearer token-key-1", "Bearer token-key-2"}
+	if fmt.Sprint(calls) != fmt.Sprint(want) || !c.authClient.UsingSecondary() {
+		t.Errorf("Expected calls %v ending on the secondary credentials, got %v", want, calls)
+	}
+
+	// Without standby credentials there is nothing to fail over to
+	c = newClient("key-1", "")
+	if err := c.Get(context.Background(), "/api/v2/tenants/tenant-1/resources/r1", &result); err == nil {
+		t.Error("Expected the 401 without standby credentials")
+	}
+}
+
+func TestSharedClientIsReusedPerCredential(t *testing.T) {
+	config := &common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: "https://shared.example.com", AuthURL: "https://shared.example.com/auth/oauth/token",
+		AuthKey: "key", AuthSecret: "secret", TenantID: "tenant",
+	}}
+	first := SharedClient(config)
+	if SharedClient(config) != first {
+		t.Error("Expected the same tenant and credentials to share one client")
+	}
+
+	other := *config
+	other.OpsRamp.AuthKey = "other-key"
+	if SharedClient(&other) == first {
+		t.Error("Expected other credentials to get a client of their own")
+	}
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// ReadGroup coalesces identical concurrent GET requests, so when several
+// sessions read the same endpoint at once, e.g. agents reacting to the same
+// alert, only one OpsRamp call is made and every caller shares its response.
+// Responses are not cached: a request made after the shared call returns
+// makes a new one.
+type ReadGroup struct {
+	mu    sync.Mutex
+	calls map[string]*readCall
+}
+
+// readCall is an in-flight GET and, once done is closed, its response
+type readCall struct {
+	done    chan struct{}
+	callers int
+	status  int
+	body    []byte
+	err     error
+}
+
+// NewReadGroup creates an empty read group
+func NewReadGroup() *ReadGroup {
+	return &ReadGroup{calls: make(map[string]*readCall)}
+}
+
+// readGroups holds the read group of each tenant and credential
+var readGroups sync.Map
+
+// SharedReadGroup returns the read group shared by all clients of the tenant
+// in config that use the same credentials, creating it on first use.
+// Clients with different credentials may see different data, so they never
+// share responses.
+func SharedReadGroup(config common.OpsRampConfig) *ReadGroup {
+	key := config.TenantURL + "|" + config.TenantID + "|" + config.AuthKey
+	if group, ok := readGroups.Load(key); ok {
+		return group.(*ReadGroup)
+	}
+	group, _ := readGroups.LoadOrStore(key, NewReadGroup())
+	return group.(*ReadGroup)
+}
+
+// Do returns the response of fn for key, joining a call already in flight
+// for the same key instead of starting another. shared reports whether the
+// response went to more than one caller. The call runs detached from any
+// one caller's cancellation, so a caller that gives up does not fail the
+// others; each caller stops waiting when its own context is done. A nil
+// group calls fn directly.
+func (g *ReadGroup) Do(ctx context.Context, key string, fn func(ctx context.Context) (int, []byte, error)) (status int, body []byte, shared bool, err error) {
+	if g == nil {
+		status, body, err = fn(ctx)
+		return status, body, false, err
+	}
+
+	g.mu.Lock()
+	call, ok := g.calls[key]
+	if ok {
+		call.callers++
+	} else {
+		call = &readCall{done: make(chan struct{}), callers: 1}
+		g.calls[key] = call
+		go g.run(context.WithoutCancel(ctx), key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return 0, nil, false, ctx.Err()
+	}
+
+	g.mu.Lock()
+	shared = call.callers > 1
+	g.mu.Unlock()
+	return call.status, call.body, shared, call.err
+}
+
+// run makes the shared call and releases its waiting callers
+func (g *ReadGroup) run(ctx context.Context, key string, call *readCall, fn func(ctx context.Context) (int, []byte, error)) {
+	call.status, call.body, call.err = fn(ctx)
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(call.done)
+}
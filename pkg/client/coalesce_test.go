@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForCallers waits until n callers have joined the in-flight call for key
+func waitForCallers(t *testing.T, g *ReadGroup, key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		call, ok := g.calls[key]
+		joined := ok && call.callers >= n
+		g.mu.Unlock()
+		if joined {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d callers of %s", n, key)
+}
+
+func TestConcurrentGetsAreCoalesced(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	c := newCatalogTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		w.Write([]byte(`{"id": "r1", "name": "web-01"}`))
+	}, 0)
+
+	const callers = 5
+	endpoint := "/api/v2/tenants/tenant-1/resources/r1"
+	results := make([]map[string]interface{}, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.Get(context.Background(), endpoint, &results[i])
+		}()
+	}
+	waitForCallers(t, c.reads, endpoint, callers)
+	close(release)
+	wg.Wait()
+
+	if hits.Load() != 1 {
+		t.Errorf("Expected one OpsRamp call, got %d", hits.Load())
+	}
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil || results[i]["name"] != "web-01" {
+			t.Errorf("Caller %d got %v %v", i, results[i], errs[i])
+		}
+	}
+
+	// Once the shared call returns, the next GET makes a new one
+	var result map[string]interface{}
+	if err := c.Get(context.Background(), endpoint, &result); err != nil || hits.Load() != 2 {
+		t.Errorf("Expected a new call, got %d calls and %v", hits.Load(), err)
+	}
+}
+
+func TestCoalescedCallerCancellation(t *testing.T) {
+	release := make(chan struct{})
+	c := newCatalogTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"id": "r2"}`))
+	}, 0)
+	endpoint := "/api/v2/tenants/tenant-1/resources/r2"
+
+	// The first caller starts the call and gives up while it is in flight
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() { first <- c.Get(ctx, endpoint, nil) }()
+	waitForCallers(t, c.reads, endpoint, 1)
+
+	second := make(chan error, 1)
+	var result map[string]interface{}
+	go func() { second <- c.Get(context.Background(), endpoint, &result) }()
+	waitForCallers(t, c.reads, endpoint, 2)
+
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Errorf("Expected the first caller to be cancelled, got %v", err)
+	}
+	close(release)
+	if err := <-second; err != nil || result["id"] != "r2" {
+		t.Errorf("Expected the second caller to get the response, got %v %v", result, err)
+	}
+}
@@ -0,0 +1,306 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// MaxClockSkew is the largest difference from OpsRamp's clock tolerated before
+// token validation failures are attributed to clock skew
+const MaxClockSkew = 5 * time.Minute
+
+// CredentialFailure classifies why a tenant's credentials could not be validated
+type CredentialFailure string
+
+const (
+	// CredentialsMissing means a required setting (URL, key, secret, tenant ID) is empty
+	CredentialsMissing CredentialFailure = "missing_config"
+	// CredentialsBadURL means the tenant or auth URL is malformed, unreachable or not an OpsRamp endpoint
+	CredentialsBadURL CredentialFailure = "bad_url"
+	// CredentialsBadKey means the authorization server does not recognize the auth key
+	CredentialsBadKey CredentialFailure = "bad_key"
+	// CredentialsBadSecret means the auth key is known but the secret was rejected
+	CredentialsBadSecret CredentialFailure = "bad_secret"
+	// CredentialsWrongTenant means a token was issued but it cannot access the configured tenant ID
+	CredentialsWrongTenant CredentialFailure = "wrong_tenant"
+	// CredentialsClockSkew means the local clock differs from OpsRamp's by more than MaxClockSkew
+	CredentialsClockSkew CredentialFailure = "clock_skew"
+	// CredentialsUnknown is any other failure; the message has the details
+	CredentialsUnknown CredentialFailure = "unknown"
+)
+
+// CredentialCheck is the result of validating one tenant's credentials
+type CredentialCheck struct {
+	Tenant  string            `json:"tenant"`
+	Valid   bool              `json:"valid"`
+	Failure CredentialFailure `json:"failure,omitempty"`
+	// Step is where validation stopped: config, oauth or tenant
+	Step      string `json:"step,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Hint      string `json:"hint,omitempty"`
+	ClockSkew string `json:"clockSkew,omitempty"`
+	Duration  string `json:"duration"`
+}
+
+// credentialHints tell the operator what to fix for each failure
+var credentialHints = map[CredentialFailure]string{
+	CredentialsMissing:     "Set tenant_url, auth_url, auth_key, auth_secret and tenant_id for this tenant",
+	CredentialsBadURL:      "Check tenant_url and auth_url: they must be https URLs of your OpsRamp instance, e.g. https://<instance>.opsramp.com and https://<instance>.opsramp.com/tenancy/auth/oauth/token",
+	CredentialsBadKey:      "Check auth_key: it must be the key of an API integration in this OpsRamp instance",
+	CredentialsBadSecret:   "Check auth_secret: it must be the secret issued with auth_key",
+	CredentialsWrongTenant: "Check tenant_id: the key and secret must belong to an integration installed on this tenant (client or partner ID)",
+	CredentialsClockSkew:   "Synchronize the server clock (e.g. enable NTP); OpsRamp rejects tokens when clocks drift",
+	CredentialsUnknown:     "See the message for details",
+}
+
+// CredentialValidator synchronously checks tenant credentials by performing
+// the OAuth exchange and a scoped test call, classifying any failure
+type CredentialValidator struct {
+	httpClient *http.Client
+	egress     common.EgressConfig
+	now        func() time.Time
+}
+
+// NewCredentialValidator creates a validator whose requests obey the egress policy
+func NewCredentialValidator(egress common.EgressConfig) *CredentialValidator {
+	return &CredentialValidator{
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		egress:     egress,
+		now:        time.Now,
+	}
+}
+
+// Validate checks one tenant's credentials
+func (v *CredentialValidator) Validate(ctx context.Context, name string, config common.OpsRampConfig) CredentialCheck {
+	start := v.now()
+	check := v.validate(ctx, config)
+	check.Tenant = name
+	check.Valid = check.Failure == ""
+	if !check.Valid {
+		check.Hint = credentialHints[check.Failure]
+	}
+	check.Duration = v.now().Sub(start).Round(time.Millisecond).String()
+	return check
+}
+
+func (v *CredentialValidator) validate(ctx context.Context, config common.OpsRampConfig) CredentialCheck {
+	fail := func(step string, failure CredentialFailure, format string, args ...interface{}) CredentialCheck {
+		return CredentialCheck{Step: step, Failure: failure, Message: fmt.Sprintf(format, args...)}
+	}
+
+	var missing []string
+	for setting, value := range map[string]string{
+		"tenant_url": config.TenantURL, "auth_url": config.AuthURL, "auth_key": config.AuthKey,
+		"auth_secret": config.AuthSecret, "tenant_id": config.TenantID,
+	} {
+		if strings.TrimSpace(value) == "" {
+			missing = append(missing, setting)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fail("config", CredentialsMissing, "missing %s", strings.Join(missing, ", "))
+	}
+	for setting, value := range map[string]string{"tenant_url": config.TenantURL, "auth_url": config.AuthURL} {
+		parsed, err := url.Parse(value)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+			return fail("config", CredentialsBadURL, "%s %q is not an absolute http(s) URL", setting, value)
+		}
+	}
+	if err := ValidateID("tenant", config.TenantID); err != nil {
+		return fail("config", CredentialsWrongTenant, "%v", err)
+	}
+
+	egress := NewEgressPolicy(&common.Config{OpsRamp: config, Egress: v.egress})
+	client := *v.httpClient
+	client.Transport = egress.Transport(v.httpClient.Transport)
+
+	// Step 1: OAuth client credentials exchange
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", config.AuthKey)
+	form.Set("client_secret", config.AuthSecret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.AuthURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fail("oauth", CredentialsBadURL, "invalid auth_url: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, body, err := v.do(&client, req)
+	if err != nil {
+		return fail("oauth", CredentialsBadURL, "auth_url unreachable: %v", err)
+	}
+	skew := v.clockSkew(resp)
+
+	var token common.TokenResponse
+	var oauthErr struct {
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		if json.Unmarshal(body, &token) != nil || token.AccessToken == "" {
+			return fail("oauth", CredentialsBadURL, "auth_url did not return an OAuth token (is it the token endpoint?)")
+		}
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed || json.Unmarshal(body, &oauthErr) != nil:
+		return fail("oauth", CredentialsBadURL, "auth_url returned HTTP %d, not an OAuth error (is it the token endpoint?)", resp.StatusCode)
+	case skew > MaxClockSkew || skew < -MaxClockSkew:
+		check := fail("oauth", CredentialsClockSkew, "token request rejected (HTTP %d) and the local clock is %s off OpsRamp's", resp.StatusCode, skew.Round(time.Second))
+		check.ClockSkew = skew.Round(time.Second).String()
+		return check
+	default:
+		return fail("oauth", classifyOAuthError(oauthErr.Error, oauthErr.Description),
+			"token request rejected (HTTP %d): %s", resp.StatusCode, strings.TrimSpace(oauthErr.Error+" "+oauthErr.Description))
+	}
+
+	// Step 2: a minimal read scoped to the configured tenant
+	endpoint := strings.TrimRight(config.TenantURL, "/") +
+		fmt.Sprintf("/api/v2/tenants/%s/resources/search?pageNo=1&pageSize=1", url.PathEscape(config.TenantID))
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fail("tenant", CredentialsBadURL, "invalid tenant_url: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("Accept", "application/json")
+	resp, body, err = v.do(&client, req)
+	if err != nil {
+		return fail("tenant", CredentialsBadURL, "tenant_url unreachable: %v", err)
+	}
+	if apiSkew := v.clockSkew(resp); apiSkew != 0 {
+		skew = apiSkew
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		check := CredentialCheck{}
+		if skew > MaxClockSkew || skew < -MaxClockSkew {
+			check.ClockSkew = skew.Round(time.Second).String()
+			check.Message = fmt.Sprintf("credentials are valid, but the local clock is %s off OpsRamp's", check.ClockSkew)
+		}
+		return check
+	case resp.StatusCode == http.StatusUnauthorized && (skew > MaxClockSkew || skew < -MaxClockSkew):
+		check := fail("tenant", CredentialsClockSkew, "token rejected by tenant_url and the local clock is %s off OpsRamp's", skew.Round(time.Second))
+		check.ClockSkew = skew.Round(time.Second).String()
+		return check
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fail("tenant", CredentialsWrongTenant, "token was issued but cannot access tenant %s (HTTP %d)", config.TenantID, resp.StatusCode)
+	case resp.StatusCode == http.StatusNotFound && json.Valid(body):
+		return fail("tenant", CredentialsWrongTenant, "tenant %s was not found (HTTP 404)", config.TenantID)
+	case resp.StatusCode == http.StatusNotFound || !json.Valid(body):
+		return fail("tenant", CredentialsBadURL, "tenant_url returned HTTP %d without an OpsRamp API response (is it the API URL of the instance that issued the token?)", resp.StatusCode)
+	default:
+		return fail("tenant", CredentialsUnknown, "test call failed with HTTP %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+}
+
+// do sends a request and reads its body
+func (v *CredentialValidator) do(client *http.Client, req *http.Request) (*http.Response, []byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, describeNetworkError(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
+}
+
+// clockSkew compares the server's Date header to the local clock; positive
+// means the local clock is ahead. It returns 0 when the header is missing.
+func (v *CredentialValidator) clockSkew(resp *http.Response) time.Duration {
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0
+	}
+	skew := v.now().Sub(serverTime)
+	// Date has one second resolution
+	if skew > -2*time.Second && skew < 2*time.Second {
+		return 0
+	}
+	return skew
+}
+
+// classifyOAuthError distinguishes an unknown key from a wrong secret using
+// the OAuth error response
+func classifyOAuthError(code, description string) CredentialFailure {
+	text := strings.ToLower(code + " " + description)
+	switch {
+	case strings.Contains(text, "secret"), strings.Contains(text, "bad client credentials"), strings.Contains(text, "bad credentials"):
+		return CredentialsBadSecret
+	case strings.Contains(text, "client"), strings.Contains(text, "not found"), strings.Contains(text, "unknown"):
+		return CredentialsBadKey
+	case strings.Contains(text, "expired"), strings.Contains(text, "not yet valid"), strings.Contains(text, "clock"):
+		return CredentialsClockSkew
+	default:
+		return CredentialsUnknown
+	}
+}
+
+// describeNetworkError makes common connection failures readable
+func describeNetworkError(err error) error {
+	var dnsErr *net.DNSError
+	var egressErr *EgressError
+	switch {
+	case errors.As(err, &egressErr):
+		return fmt.Errorf("%v (check the egress allowlist)", egressErr)
+	case errors.As(err, &dnsErr):
+		return fmt.Errorf("host %s not found", dnsErr.Name)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("timed out")
+	default:
+		return err
+	}
+}
+
+// truncate shortens s to at most n bytes
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+// ValidateCredentials checks the credentials of the named tenants, or of
+// every tenant when none are named, concurrently. Results are sorted by tenant.
+func (r *TenantRegistry) ValidateCredentials(ctx context.Context, names ...string) ([]CredentialCheck, error) {
+	if len(names) == 0 {
+		names = r.Names()
+	}
+	tenants := make([]*Tenant, 0, len(names))
+	for _, name := range names {
+		tenant, ok := r.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown tenant: %s (configured tenants: %v)", name, r.Names())
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	validator := NewCredentialValidator(r.egress)
+	checks := make([]CredentialCheck, len(tenants))
+	var wg sync.WaitGroup
+	for i, tenant := range tenants {
+		wg.Add(1)
+		go func(i int, t *Tenant) {
+			defer wg.Done()
+			checks[i] = validator.Validate(ctx, t.Name, t.Config)
+		}(i, tenant)
+	}
+	wg.Wait()
+
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Tenant < checks[j].Tenant })
+	return checks, nil
+}
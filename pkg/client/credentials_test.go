@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// newCredentialsTestServer fakes an OpsRamp instance that knows one key,
+// secret and tenant
+func newCredentialsTestServer(t *testing.T, serverTime time.Time) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverTime.UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.FormValue("client_id") != "key-1":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client","error_description":"Client not found: ` + r.FormValue("client_id") + `"}`))
+		case r.FormValue("client_secret") != "secret-1":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client","error_description":"Bad client credentials"}`))
+		default:
+			w.Write([]byte(`{"access_token":"token-1","token_type":"bearer","expires_in":3600}`))
+		}
+	})
+	mux.HandleFunc("/api/v2/tenants/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverTime.UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/v2/tenants/client_1/resources/search" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"code":"0003","message":"Access denied"}`))
+			return
+		}
+		w.Write([]byte(`{"results":[],"totalResults":0}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCredentialValidatorClassifiesFailures(t *testing.T) {
+	now := time.Now()
+	server := newCredentialsTestServer(t, now)
+	valid := common.OpsRampConfig{
+		TenantURL:  server.URL,
+		AuthURL:    server.URL + "/auth/oauth/token",
+		AuthKey:    "key-1",
+		AuthSecret: "secret-1",
+		TenantID:   "client_1",
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *common.OpsRampConfig)
+		want   CredentialFailure
+		step   string
+	}{
+		{name: "valid", modify: func(c *common.OpsRampConfig) {}},
+		{name: "missing secret", modify: func(c *common.OpsRampConfig) { c.AuthSecret = "" }, want: CredentialsMissing, step: "config"},
+		{name: "malformed url", modify: func(c *common.OpsRampConfig) { c.TenantURL = "acme.opsramp.com" }, want: CredentialsBadURL, step: "config"},
+		{name: "auth url is not the token endpoint", modify: func(c *common.OpsRampConfig) { c.AuthURL = server.URL + "/login" }, want: CredentialsBadURL, step: "oauth"},
+		{name: "bad key", modify: func(c *common.OpsRampConfig) { c.AuthKey = "key-2" }, want: CredentialsBadKey, step: "oauth"},
+		{name: "bad secret", modify: func(c *common.OpsRampConfig) { c.AuthSecret = "secret-2" }, want: CredentialsBadSecret, step: "oauth"},
+		{name: "wrong tenant", modify: func(c *common.OpsRampConfig) { c.TenantID = "client_2" }, want: CredentialsWrongTenant, step: "tenant"},
+	}
+
+	validator := NewCredentialValidator(common.EgressConfig{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.modify(&config)
+			check := validator.Validate(context.Background(), "acme", config)
+			if check.Failure != tt.want || check.Step != tt.step {
+				t.Fatalf("Expected failure %q at %q, got %+v", tt.want, tt.step, check)
+			}
+			if check.Valid != (tt.want == "") {
+				t.Errorf("Expected valid=%v, got %+v", tt.want == "", check)
+			}
+			if !check.Valid && check.Hint == "" {
+				t.Errorf("Expected a hint for %s", check.Failure)
+			}
+		})
+	}
+}
+
+func TestCredentialValidatorDetectsClockSkew(t *testing.T) {
+	server := newCredentialsTestServer(t, time.Now().Add(-20*time.Minute))
+	validator := NewCredentialValidator(common.EgressConfig{})
+
+	check := validator.Validate(context.Background(), "acme", common.OpsRampConfig{
+		TenantURL:  server.URL,
+		AuthURL:    server.URL + "/auth/oauth/token",
+		AuthKey:    "key-1",
+		AuthSecret: "secret-2",
+		TenantID:   "client_1",
+	})
+	if check.Failure != CredentialsClockSkew || check.ClockSkew == "" {
+		t.Fatalf("Expected clock skew to explain the rejection, got %+v", check)
+	}
+
+	// Valid credentials still succeed, with the skew reported as a warning
+	check = validator.Validate(context.Background(), "acme", common.OpsRampConfig{
+		TenantURL:  server.URL,
+		AuthURL:    server.URL + "/auth/oauth/token",
+		AuthKey:    "key-1",
+		AuthSecret: "secret-1",
+		TenantID:   "client_1",
+	})
+	if !check.Valid || check.ClockSkew == "" {
+		t.Fatalf("Expected valid credentials with a skew warning, got %+v", check)
+	}
+}
+
+func TestRegistryValidateCredentials(t *testing.T) {
+	server := newCredentialsTestServer(t, time.Now())
+	config := &common.Config{
+		OpsRamp: common.OpsRampConfig{
+			TenantURL: server.URL, AuthURL: server.URL + "/auth/oauth/token",
+			AuthKey: "key-1", AuthSecret: "secret-1", TenantID: "client_1",
+		},
+		Tenants: []common.TenantConfig{{Name: "emea", OpsRampConfig: common.OpsRampConfig{
+			TenantURL: server.URL, AuthURL: server.URL + "/auth/oauth/token",
+			AuthKey: "key-1", AuthSecret: "secret-1", TenantID: "client_9",
+		}}},
+	}
+	registry := NewTenantRegistry(config)
+
+	checks, err := registry.ValidateCredentials(context.Background())
+	if err != nil {
+		t.Fatalf("ValidateCredentials failed: %v", err)
+	}
+	if len(checks) != 2 || checks[0].Tenant != DefaultTenant || !checks[0].Valid ||
+		checks[1].Tenant != "emea" || checks[1].Failure != CredentialsWrongTenant {
+		t.Errorf("Unexpected checks: %+v", checks)
+	}
+
+	if _, err := registry.ValidateCredentials(context.Background(), "apac"); err == nil {
+		t.Error("Expected an error for an unknown tenant")
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// EgressError is returned when a request targets a host outside the egress allowlist
+type EgressError struct {
+	Host string
+}
+
+func (e *EgressError) Error() string {
+	return fmt.Sprintf("egress to host %q is not allowed", e.Host)
+}
+
+// EgressPolicy is the set of hosts the server may call. It guards against
+// SSRF-style abuse should a tool argument ever end up in a request URL.
+type EgressPolicy struct {
+	hosts    map[string]bool
+	suffixes []string
+}
+
+// NewEgressPolicy builds the allowlist from the tenant, auth and remote_write
+// URLs plus the configured extra hosts
+func NewEgressPolicy(config *common.Config) *EgressPolicy {
+	p := &EgressPolicy{hosts: make(map[string]bool)}
+
+	p.allowURL(config.OpsRamp.TenantURL)
+	p.allowURL(config.OpsRamp.AuthURL)
+	for _, tenant := range config.Tenants {
+		p.allowURL(tenant.TenantURL)
+		p.allowURL(tenant.AuthURL)
+	}
+	p.allowURL(config.RemoteWrite.URL)
+
+	for _, host := range config.Egress.AllowedHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if suffix := strings.TrimPrefix(host, "*"); suffix != host {
+			p.suffixes = append(p.suffixes, suffix)
+			continue
+		}
+		if host != "" {
+			p.hosts[host] = true
+		}
+	}
+
+	return p
+}
+
+// allowURL adds the host of a configured URL
+func (p *EgressPolicy) allowURL(raw string) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return
+	}
+	p.hosts[strings.ToLower(u.Hostname())] = true
+}
+
+// Allows reports whether host may be called
+func (p *EgressPolicy) Allows(host string) bool {
+	host = strings.ToLower(host)
+	if p.hosts[host] {
+		return true
+	}
+	for _, suffix := range p.suffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an EgressError if u targets a host outside the allowlist
+func (p *EgressPolicy) Check(u *url.URL) error {
+	if !p.Allows(u.Hostname()) {
+		return &EgressError{Host: u.Hostname()}
+	}
+	return nil
+}
+
+// Hosts returns the explicitly allowed hosts and wildcard patterns
+func (p *EgressPolicy) Hosts() []string {
+	hosts := make([]string, 0, len(p.hosts)+len(p.suffixes))
+	for host := range p.hosts {
+		hosts = append(hosts, host)
+	}
+	for _, suffix := range p.suffixes {
+		hosts = append(hosts, "*"+suffix)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// Transport wraps next so that every request, including followed redirects,
+// is checked against the policy before it leaves the process
+func (p *EgressPolicy) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &egressTransport{policy: p, next: next}
+}
+
+// outboundRequests counts the requests sent through egress transports
+var outboundRequests atomic.Int64
+
+// OutboundRequestCount returns the number of requests sent to OpsRamp since
+// startup, for measuring how many API calls each tool call costs
+func OutboundRequestCount() int64 {
+	return outboundRequests.Load()
+}
+
+// egressTransport enforces an EgressPolicy on outbound requests
+type egressTransport struct {
+	policy *EgressPolicy
+	next   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *egressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.policy.Check(req.URL); err != nil {
+		common.GetLogger().Warn("Blocked outbound request to %s: %v", req.URL.Redacted(), err)
+		return nil, err
+	}
+	outboundRequests.Add(1)
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestEgressPolicyAllows(t *testing.T) {
+	policy := NewEgressPolicy(&common.Config{
+		OpsRamp: common.OpsRampConfig{
+			TenantURL: "https://acme.api.opsramp.com",
+			AuthURL:   "https://auth.opsramp.com:8443/tenancy/auth/oauth/token",
+		},
+		Tenants:     []common.TenantConfig{{Name: "emea", OpsRampConfig: common.OpsRampConfig{TenantURL: "https://emea.opsramp.com"}}},
+		Egress:      common.EgressConfig{AllowedHosts: []string{"hooks.slack.com", "*.webhooks.example.com"}},
+		RemoteWrite: common.RemoteWriteConfig{URL: "https://tsdb.example.com/api/v1/write"},
+	})
+
+	for host, want := range map[string]bool{
+		"acme.api.opsramp.com":       true,
+		"ACME.api.opsramp.com":       true,
+		"auth.opsramp.com":           true,
+		"emea.opsramp.com":           true,
+		"hooks.slack.com":            true,
+		"a.webhooks.example.com":     true,
+		"tsdb.example.com":           true,
+		"webhooks.example.com":       false,
+		"169.254.169.254":            false,
+		"evil-acme.api.opsramp.com":  false,
+		"hooks.slack.com.attacker.x": false,
+	} {
+		if got := policy.Allows(host); got != want {
+			t.Errorf("Allows(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestClientRefusesRedirectOutsideAllowlist(t *testing.T) {
+	outside := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Request reached a host outside the allowlist")
+	}))
+	defer outside.Close()
+
+	// The tenant is reached via 127.0.0.1 and redirects to localhost, which is not allowlisted
+	redirectTo := "http://localhost" + outside.URL[len("http://127.0.0.1"):]
+	c := newCatalogTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, redirectTo+r.URL.Path, http.StatusFound)
+	}, 300)
+
+	var result map[string]interface{}
+	err := c.Get(context.Background(), "/api/v2/tenants/tenant-1/resources", &result)
+	var egressErr *EgressError
+	if !errors.As(err, &egressErr) || egressErr.Host != "localhost" {
+		t.Fatalf("Expected an egress error for localhost, got %v", err)
+	}
+}
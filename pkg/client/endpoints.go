@@ -0,0 +1,288 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Scope identifies which OpsRamp tenant an endpoint is addressed to
+type Scope string
+
+const (
+	// ScopeClient addresses the configured client tenant (tenant_id)
+	ScopeClient Scope = "client"
+	// ScopePartner addresses the MSP partner tenant (partner_id)
+	ScopePartner Scope = "partner"
+)
+
+// apiPrefix is the common prefix of tenant-scoped OpsRamp v2 endpoints
+const apiPrefix = "/api/v2/tenants/"
+
+// Endpoint is a named OpsRamp API endpoint and the scope it must be called in.
+// Path is relative to the tenant and may contain fmt verbs for path parameters.
+type Endpoint struct {
+	Name   string
+	Scope  Scope
+	Method string
+	Path   string
+	// Params names the path parameters in order; unnamed parameters are "id"
+	Params []string
+	// Tool is the MCP tool that owns the endpoint
+	Tool string
+	// Actions lists the tool actions that call the endpoint, as tool/action
+	Actions []string
+	// Mutation marks endpoints that change OpsRamp state
+	Mutation bool
+}
+
+// Template returns the full path of the endpoint with its tenant and path
+// parameters as {name} placeholders
+func (e Endpoint) Template() string {
+	tenant := "{tenantId}"
+	if e.Scope == ScopePartner {
+		tenant = "{partnerId}"
+	}
+
+	var b strings.Builder
+	b.WriteString(apiPrefix + tenant + "/")
+	param := 0
+	for i := 0; i < len(e.Path); i++ {
+		if e.Path[i] != '%' || i+1 == len(e.Path) {
+			b.WriteByte(e.Path[i])
+			continue
+		}
+		i++
+		name := "id"
+		if param < len(e.Params) {
+			name = e.Params[param]
+		}
+		param++
+		b.WriteString("{" + name + "}")
+	}
+	return b.String()
+}
+
+// EndpointInfo describes a registered endpoint for security reviews
+type EndpointInfo struct {
+	Name     string   `json:"name"`
+	Method   string   `json:"method"`
+	Path     string   `json:"path"`
+	Scope    Scope    `json:"scope"`
+	Tool     string   `json:"tool"`
+	Actions  []string `json:"actions"`
+	Mutation bool     `json:"mutation"`
+}
+
+// ScopeError is returned when an endpoint's scope cannot be satisfied by the
+// client configuration, so calls fail fast instead of with a 403 from OpsRamp
+type ScopeError struct {
+	Scope  Scope
+	Reason string
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("%s scope unavailable: %s", e.Scope, e.Reason)
+}
+
+// endpoints lists every OpsRamp endpoint the server calls. Endpoints called
+// outside EndpointPath are registered too, so the registry stays a complete
+// record of what the server can reach.
+var (
+	endpointsMu sync.RWMutex
+	endpoints   = map[string]Endpoint{}
+)
+
+func init() {
+	for _, endpoint := range []Endpoint{
+		{Name: "clients.search", Scope: ScopePartner, Method: "GET", Path: "clients/search", Tool: "clients", Actions: []string{"clients/list"}},
+		{Name: "clients.get", Scope: ScopePartner, Method: "GET", Path: "clients/%s", Params: []string{"clientId"}, Tool: "clients", Actions: []string{"clients/get"}},
+
+		{Name: "monitors.search", Scope: ScopeClient, Method: "GET", Path: "monitoring/scripts/search", Tool: "monitors", Actions: []string{"monitors/list"}},
+		{Name: "monitors.create", Scope: ScopeClient, Method: "POST", Path: "monitoring/scripts", Tool: "monitors", Actions: []string{"monitors/create"}, Mutation: true},
+		{Name: "monitors.get", Scope: ScopeClient, Method: "GET", Path: "monitoring/scripts/%s", Params: []string{"monitorId"}, Tool: "monitors", Actions: []string{"monitors/get"}},
+		{Name: "monitors.update", Scope: ScopeClient, Method: "PUT", Path: "monitoring/scripts/%s", Params: []string{"monitorId"}, Tool: "monitors", Actions: []string{"monitors/update"}, Mutation: true},
+		{Name: "monitors.assign", Scope: ScopeClient, Method: "POST", Path: "monitoring/scripts/%s/assign", Params: []string{"monitorId"}, Tool: "monitors", Actions: []string{"monitors/assign"}, Mutation: true},
+		{Name: "monitors.unassign", Scope: ScopeClient, Method: "POST", Path: "monitoring/scripts/%s/unassign", Params: []string{"monitorId"}, Tool: "monitors", Actions: []string{"monitors/unassign"}, Mutation: true},
+		{Name: "monitors.assignments", Scope: ScopeClient, Method: "GET", Path: "monitoring/scripts/%s/resources", Params: []string{"monitorId"}, Tool: "monitors", Actions: []string{"monitors/listAssignments"}},
+
+		{Name: "metrics.search", Scope: ScopeClient, Method: "GET", Path: "metrics/search", Tool: "metrics", Actions: []string{"metrics/search"}},
+		{Name: "metrics.resource", Scope: ScopeClient, Method: "GET", Path: "resources/%s/metrics", Params: []string{"resourceId"}, Tool: "metrics", Actions: []string{"metrics/listResourceMetrics"}},
+		{Name: "metrics.query", Scope: ScopeClient, Method: "POST", Path: "metrics/query", Tool: "metrics", Actions: []string{"metrics/query"}},
+
+		{Name: "resources.search", Scope: ScopeClient, Method: "GET", Path: "resources/search", Tool: "resources",
+			Actions: []string{"resources/list", "resources/search", "resources/createOrUpdate", "resources/bulkUpdate", "resources/bulkDelete", "resources/planDelete", "resources/onboard", "resources/timelineReport",
+				"resources/createDynamicGroup", "resources/updateDynamicGroup", "resources/previewDynamicGroup", "resources/refreshDynamicGroup", "resources/inferPropertySchema", "resources/getMetricCatalog", "resources/resolveId", "integrations/findOrphanedIntegrations"}},
+		{Name: "resources.get", Scope: ScopeClient, Method: "GET", Path: "resources/%s", Params: []string{"resourceId"}, Tool: "resources",
+			Actions: []string{"resources/get", "resources/getDetailed", "resources/getMinimal", "resources/planDelete", "resources/impactAnalysis", "resources/scorecard", "resources/timelineReport", "resources/resolveId", "resources/getMetricCatalog"}},
+		{Name: "resources.create", Scope: ScopeClient, Method: "POST", Path: "resources", Tool: "resources", Actions: []string{"resources/create", "resources/createOrUpdate", "resources/createFromTemplate"}, Mutation: true},
+		{Name: "resources.update", Scope: ScopeClient, Method: "POST", Path: "resources/%s", Params: []string{"resourceId"}, Tool: "resources", Actions: []string{"resources/update", "resources/createOrUpdate"}, Mutation: true},
+		{Name: "resources.delete", Scope: ScopeClient, Method: "DELETE", Path: "resources/%s", Params: []string{"resourceId"}, Tool: "resources", Actions: []string{"resources/delete"}, Mutation: true},
+		{Name: "resources.bulkUpdate", Scope: ScopeClient, Method: "POST", Path: "resources/bulk-update", Tool: "resources", Actions: []string{"resources/bulkUpdate"}, Mutation: true},
+		{Name: "resources.bulkDelete", Scope: ScopeClient, Method: "POST", Path: "resources/bulk-delete", Tool: "resources", Actions: []string{"resources/bulkDelete"}, Mutation: true},
+		{Name: "resources.types", Scope: ScopeClient, Method: "GET", Path: "resources/types", Tool: "resources", Actions: []string{"resources/getResourceTypes"}},
+		{Name: "resources.relationships", Scope: ScopeClient, Method: "GET", Path: "resources/%s/relationships", Params: []string{"resourceId"}, Tool: "resources", Actions: []string{"resources/getDetailed", "resources/impactAnalysis"}},
+		{Name: "resources.serviceGroups", Scope: ScopeClient, Method: "GET", Path: "resources/%s/serviceGroups", Params: []string{"resourceId"}, Tool: "resources", Actions: []string{"resources/getDetailed", "resources/impactAnalysis"}},
+		{Name: "resources.state", Scope: ScopeClient, Method: "POST", Path: "resources/%s/state", Params: []string{"resourceId"}, Tool: "resources", Actions: []string{"resources/onboard", "resources/changeState"}, Mutation: true},
+		{Name: "resources.templates", Scope: ScopeClient, Method: "POST", Path: "resources/%s/templates", Params: []string{"resourceId"}, Tool: "resources", Actions: []string{"resources/onboard"}, Mutation: true},
+		{Name: "resources.tags", Scope: ScopeClient, Method: "GET", Path: "resources/%s/tags", Params: []string{"resourceId"}, Tool: "resources", Actions: []string{"resources/getDetailed", "resources/getTags", "resources/addTag", "resources/removeTag"}},
+		{Name: "resources.updateTags", Scope: ScopeClient, Method: "POST", Path: "resources/%s/tags", Params: []string{"resourceId"}, Tool: "resources", Actions: []string{"resources/onboard", "resources/updateTags", "resources/addTag", "resources/removeTag"}, Mutation: true},
+		{Name: "resources.patches", Scope: ScopeClient, Method: "GET", Path: "resources/%s/patches", Params: []string{"resourceId"}, Tool: "resources", Actions: []string{"resources/getDetailed", "resources/scorecard"}},
+		{Name: "resources.metrics", Scope: ScopeClient, Method: "POST", Path: "resources/%s/metrics", Params: []string{"resourceId"}, Tool: "resources", Actions: []string{"resources/getMetrics", "resources/scorecard", "resources/timelineReport", "resources/getMetricCatalog"}},
+		{Name: "resources.metricInstances", Scope: ScopeClient, Method: "GET", Path: "resources/%s/metrics/%s/instances", Params: []string{"resourceId", "metric"}, Tool: "resources", Actions: []string{"resources/listMetricInstances"}},
+		{Name: "resources.availability", Scope: ScopeClient, Method: "GET", Path: "resources/%s/availability", Params: []string{"resourceId"}, Tool: "resources", Actions: []string{"resources/getAvailability"}},
+		{Name: "maintenance.search", Scope: ScopeClient, Method: "GET", Path: "scheduleMaintenances/search", Tool: "resources", Actions: []string{"resources/getDetailed", "resources/timelineReport"}},
+		{Name: "deviceGroups.list", Scope: ScopeClient, Method: "GET", Path: "deviceGroups/minimal", Tool: "resources", Actions: []string{"resources/snapshotGroups", "resources/listDeviceGroups"}},
+		{Name: "deviceGroups.get", Scope: ScopeClient, Method: "GET", Path: "deviceGroups/%s", Params: []string{"groupId"}, Tool: "resources", Actions: []string{"resources/getDeviceGroup", "resources/updateDeviceGroup"}},
+		{Name: "deviceGroups.create", Scope: ScopeClient, Method: "POST", Path: "deviceGroups", Tool: "resources", Actions: []string{"resources/createDynamicGroup", "resources/createDeviceGroup"}, Mutation: true},
+		{Name: "deviceGroups.update", Scope: ScopeClient, Method: "POST", Path: "deviceGroups/%s", Params: []string{"groupId"}, Tool: "resources", Actions: []string{"resources/updateDynamicGroup", "resources/updateDeviceGroup"}, Mutation: true},
+		{Name: "deviceGroups.delete", Scope: ScopeClient, Method: "DELETE", Path: "deviceGroups/%s", Params: []string{"groupId"}, Tool: "resources", Actions: []string{"resources/deleteDeviceGroup"}, Mutation: true},
+		{Name: "deviceGroups.members", Scope: ScopeClient, Method: "GET", Path: "deviceGroups/%s/childs", Params: []string{"groupId"}, Tool: "resources",
+			Actions: []string{"resources/createDynamicGroup", "resources/updateDynamicGroup", "resources/previewDynamicGroup", "resources/refreshDynamicGroup", "resources/snapshotGroups"}},
+		{Name: "deviceGroups.assign", Scope: ScopeClient, Method: "POST", Path: "deviceGroups/%s/childs", Params: []string{"groupId"}, Tool: "resources",
+			Actions: []string{"resources/onboard", "resources/createDynamicGroup", "resources/updateDynamicGroup", "resources/refreshDynamicGroup"}, Mutation: true},
+		{Name: "deviceGroups.unassign", Scope: ScopeClient, Method: "DELETE", Path: "deviceGroups/%s/childs", Params: []string{"groupId"}, Tool: "resources",
+			Actions: []string{"resources/updateDynamicGroup", "resources/refreshDynamicGroup"}, Mutation: true},
+		{Name: "sites.search", Scope: ScopeClient, Method: "GET", Path: "sites/search", Tool: "resources", Actions: []string{"resources/snapshotGroups", "resources/listSites"}},
+		{Name: "sites.get", Scope: ScopeClient, Method: "GET", Path: "sites/%s", Params: []string{"siteId"}, Tool: "resources", Actions: []string{"resources/getSite", "resources/updateSite"}},
+		{Name: "sites.create", Scope: ScopeClient, Method: "POST", Path: "sites", Tool: "resources", Actions: []string{"resources/createSite"}, Mutation: true},
+		{Name: "sites.update", Scope: ScopeClient, Method: "POST", Path: "sites/%s", Params: []string{"siteId"}, Tool: "resources", Actions: []string{"resources/updateSite"}, Mutation: true},
+		{Name: "sites.delete", Scope: ScopeClient, Method: "DELETE", Path: "sites/%s", Params: []string{"siteId"}, Tool: "resources", Actions: []string{"resources/deleteSite"}, Mutation: true},
+		{Name: "sites.assign", Scope: ScopeClient, Method: "POST", Path: "sites/%s/resources", Params: []string{"siteId"}, Tool: "resources", Actions: []string{"resources/onboard"}, Mutation: true},
+		{Name: "serviceGroups.search", Scope: ScopeClient, Method: "GET", Path: "serviceGroups/search", Tool: "resources", Actions: []string{"resources/listServiceGroups"}},
+		{Name: "serviceGroups.get", Scope: ScopeClient, Method: "GET", Path: "serviceGroups/%s", Params: []string{"serviceGroupId"}, Tool: "resources", Actions: []string{"resources/getServiceGroup", "resources/updateServiceGroup"}},
+		{Name: "serviceGroups.create", Scope: ScopeClient, Method: "POST", Path: "serviceGroups", Tool: "resources", Actions: []string{"resources/createServiceGroup"}, Mutation: true},
+		{Name: "serviceGroups.update", Scope: ScopeClient, Method: "POST", Path: "serviceGroups/%s", Params: []string{"serviceGroupId"}, Tool: "resources", Actions: []string{"resources/updateServiceGroup"}, Mutation: true},
+		{Name: "serviceGroups.delete", Scope: ScopeClient, Method: "DELETE", Path: "serviceGroups/%s", Params: []string{"serviceGroupId"}, Tool: "resources", Actions: []string{"resources/deleteServiceGroup"}, Mutation: true},
+
+		{Name: "alerts.search", Scope: ScopeClient, Method: "GET", Path: "alerts/search", Tool: "alerts",
+			Actions: []string{"alerts/search", "alerts/previewRule", "alerts/acknowledge", "alerts/close", "resources/impactAnalysis", "resources/scorecard", "resources/timelineReport", "integrations/findOrphanedIntegrations"}},
+		{Name: "alerts.get", Scope: ScopeClient, Method: "GET", Path: "alerts/%s", Params: []string{"alertId"}, Tool: "alerts", Actions: []string{"alerts/get", "alerts/previewRouting"}},
+		{Name: "alerts.comments", Scope: ScopeClient, Method: "GET", Path: "alerts/%s/comments", Params: []string{"alertId"}, Tool: "alerts", Actions: []string{"alerts/getNotes", "alerts/getThread"}},
+		{Name: "alerts.addComment", Scope: ScopeClient, Method: "POST", Path: "alerts/%s/comments", Params: []string{"alertId"}, Tool: "alerts", Actions: []string{"alerts/addNote"}, Mutation: true},
+		{Name: "alerts.action", Scope: ScopeClient, Method: "POST", Path: "alerts/action/%s", Params: []string{"action"}, Tool: "alerts", Actions: []string{"alerts/acknowledge", "alerts/close"}, Mutation: true},
+		{Name: "alerts.attachments", Scope: ScopeClient, Method: "GET", Path: "alerts/%s/attachments", Params: []string{"alertId"}, Tool: "alerts", Actions: []string{"alerts/getAttachments", "alerts/getThread"}},
+
+		{Name: "escalations.search", Scope: ScopeClient, Method: "GET", Path: "escalations/search", Tool: "alerts", Actions: []string{"alerts/previewRouting"}},
+		{Name: "integrations.search", Scope: ScopeClient, Method: "GET", Path: "integrations/installed/search", Tool: "integrations", Actions: []string{"integrations/list", "integrations/findOrphanedIntegrations"}},
+		{Name: "integrations.get", Scope: ScopeClient, Method: "GET", Path: "integrations/installed/%s", Params: []string{"integrationId"}, Tool: "integrations",
+			Actions: []string{"integrations/get", "integrations/getDetailed", "integrations/findOrphanedIntegrations", "integrations/clone"}},
+		{Name: "integrations.install", Scope: ScopeClient, Method: "POST", Path: "integrations/install/%s", Params: []string{"uniqueName"}, Tool: "integrations", Actions: []string{"integrations/create", "integrations/clone"}, Mutation: true},
+		{Name: "integrations.update", Scope: ScopeClient, Method: "POST", Path: "integrations/installed/%s", Params: []string{"integrationId"}, Tool: "integrations", Actions: []string{"integrations/update"}, Mutation: true},
+		{Name: "integrations.delete", Scope: ScopeClient, Method: "DELETE", Path: "integrations/installed/%s", Params: []string{"integrationId"}, Tool: "integrations", Actions: []string{"integrations/delete"}, Mutation: true},
+		{Name: "integrations.enable", Scope: ScopeClient, Method: "POST", Path: "integrations/installed/%s/enable", Params: []string{"integrationId"}, Tool: "integrations", Actions: []string{"integrations/enable", "integrations/scheduleEnable", "integrations/scheduleDisable"}, Mutation: true},
+		{Name: "integrations.disable", Scope: ScopeClient, Method: "POST", Path: "integrations/installed/%s/disable", Params: []string{"integrationId"}, Tool: "integrations", Actions: []string{"integrations/disable", "integrations/scheduleEnable", "integrations/scheduleDisable"}, Mutation: true},
+		{Name: "integrations.types", Scope: ScopeClient, Method: "GET", Path: "integrations/available/search", Tool: "integrations", Actions: []string{"integrations/listTypes", "integrations/getType"}},
+
+		{Name: "usage.licenses", Scope: ScopeClient, Method: "GET", Path: "licenses/usage", Tool: "usage", Actions: []string{"usage/summary", "usage/licenses", "usage/resourceCounts"}},
+		{Name: "usage.api", Scope: ScopeClient, Method: "GET", Path: "apiUsage", Tool: "usage", Actions: []string{"usage/apiUsage"}},
+	} {
+		endpoints[endpoint.Name] = endpoint
+	}
+}
+
+// RegisterEndpoint adds or replaces a named endpoint in the registry
+func RegisterEndpoint(endpoint Endpoint) {
+	endpointsMu.Lock()
+	defer endpointsMu.Unlock()
+	endpoints[endpoint.Name] = endpoint
+}
+
+// LookupEndpoint returns the named endpoint from the registry
+func LookupEndpoint(name string) (Endpoint, bool) {
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	endpoint, ok := endpoints[name]
+	return endpoint, ok
+}
+
+// EndpointNames returns the registered endpoint names in sorted order
+func EndpointNames() []string {
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	names := make([]string, 0, len(endpoints))
+	for name := range endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DescribeEndpoints returns every registered endpoint, sorted by name
+func DescribeEndpoints() []EndpointInfo {
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	infos := make([]EndpointInfo, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		infos = append(infos, EndpointInfo{
+			Name:     endpoint.Name,
+			Method:   endpoint.Method,
+			Path:     endpoint.Template(),
+			Scope:    endpoint.Scope,
+			Tool:     endpoint.Tool,
+			Actions:  append([]string{}, endpoint.Actions...),
+			Mutation: endpoint.Mutation,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// ScopeID returns the tenant ID that requests in the given scope are addressed to
+func (c *OpsRampClient) ScopeID(scope Scope) (string, error) {
+	switch scope {
+	case ScopeClient, "":
+		if c.tenantID == "" {
+			return "", &ScopeError{Scope: ScopeClient, Reason: "tenant_id is not configured"}
+		}
+		return c.tenantID, nil
+	case ScopePartner:
+		if c.partnerID == "" {
+			return "", &ScopeError{Scope: ScopePartner, Reason: "partner_id is not configured"}
+		}
+		return c.partnerID, nil
+	default:
+		return "", &ScopeError{Scope: scope, Reason: "unknown scope"}
+	}
+}
+
+// ScopedPath builds a tenant endpoint path in the given scope
+func (c *OpsRampClient) ScopedPath(scope Scope, format string, args ...interface{}) (string, error) {
+	id, err := c.ScopeID(scope)
+	if err != nil {
+		return "", err
+	}
+	return apiPrefix + id + "/" + fmt.Sprintf(format, args...), nil
+}
+
+// EndpointPath builds the path of a registered endpoint in its declared scope.
+// String arguments are path parameters: they are validated with ValidateID
+// and escaped so they cannot alter the request path.
+func (c *OpsRampClient) EndpointPath(name string, args ...interface{}) (string, error) {
+	endpoint, ok := LookupEndpoint(name)
+	if !ok {
+		return "", fmt.Errorf("unknown endpoint: %s", name)
+	}
+	params := make([]interface{}, len(args))
+	for i, arg := range args {
+		params[i] = arg
+		if s, ok := arg.(string); ok {
+			escaped, err := PathID(name, s)
+			if err != nil {
+				return "", err
+			}
+			params[i] = escaped
+		}
+	}
+	return c.ScopedPath(endpoint.Scope, endpoint.Path, params...)
+}
+
+// headerTenantID returns the X-Tenant-ID for a request path, so the header
+// always names the same tenant as the URL
+func (c *OpsRampClient) headerTenantID(endpointPath string) string {
+	if c.partnerID != "" && strings.HasPrefix(endpointPath, apiPrefix+c.partnerID+"/") {
+		return c.partnerID
+	}
+	return c.tenantID
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestEndpointPathScopes(t *testing.T) {
+	var gotPath, gotTenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/token" {
+			w.Write([]byte(`{"access_token": "token", "expires_in": 3600}`))
+			return
+		}
+		gotPath, gotTenant = r.URL.Path, r.Header.Get("X-Tenant-ID")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	partner := NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: server.URL,
+		AuthURL:   server.URL + "/auth/token",
+		TenantID:  "client-1",
+		PartnerID: "msp-1",
+	}})
+
+	endpoint, err := partner.EndpointPath("clients.get", "client-9")
+	if err != nil {
+		t.Fatalf("EndpointPath failed: %v", err)
+	}
+	if endpoint != "/api/v2/tenants/msp-1/clients/client-9" {
+		t.Errorf("Unexpected partner endpoint: %s", endpoint)
+	}
+
+	if err := partner.Get(context.Background(), endpoint, &map[string]interface{}{}); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if gotPath != endpoint || gotTenant != "msp-1" {
+		t.Errorf("Expected partner request with partner tenant header, got %s (X-Tenant-ID %s)", gotPath, gotTenant)
+	}
+
+	clientPath, _ := partner.ScopedPath(ScopeClient, "resources/%s", "r1")
+	if err := partner.Get(context.Background(), clientPath, &map[string]interface{}{}); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if gotTenant != "client-1" {
+		t.Errorf("Expected client tenant header for client-scoped call, got %s", gotTenant)
+	}
+}
+
+func TestEndpointPathWithoutPartner(t *testing.T) {
+	c := NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{TenantID: "client-1"}})
+
+	_, err := c.EndpointPath("clients.search")
+	var scopeErr *ScopeError
+	if !errors.As(err, &scopeErr) || scopeErr.Scope != ScopePartner {
+		t.Fatalf("Expected partner ScopeError, got %v", err)
+	}
+
+	if _, err := c.EndpointPath("no.such.endpoint"); err == nil {
+		t.Error("Expected error for unknown endpoint")
+	}
+}
+
+func TestEndpointPathValidatesIDs(t *testing.T) {
+	c := NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{TenantID: "client-1", PartnerID: "msp-1"}})
+
+	path, err := c.EndpointPath("clients.get", "client_42")
+	if err != nil || path != "/api/v2/tenants/msp-1/clients/client_42" {
+		t.Fatalf("Unexpected path %s (%v)", path, err)
+	}
+
+	for _, id := range []string{"", "..", "a/../../admin", "42?x=1", "42%2F", "id with space", string(make([]byte, MaxIDLength+1))} {
+		_, err := c.EndpointPath("clients.get", id)
+		var idErr *InvalidIDError
+		if !errors.As(err, &idErr) {
+			t.Errorf("Expected InvalidIDError for %q, got %v", id, err)
+		}
+	}
+}
+
+func TestValidateID(t *testing.T) {
+	for _, id := range []string{"42", "a1b2-c3d4", "client_1", "res.01", "urn:opsramp:1"} {
+		if err := ValidateID("resource", id); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", id, err)
+		}
+	}
+	if err := ValidateID("resource", "../x"); err == nil || err.Error() != `invalid resource ID "../x": contains disallowed character '/'` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestEndpointTemplate(t *testing.T) {
+	tests := []struct {
+		endpoint Endpoint
+		want     string
+	}{
+		{Endpoint{Scope: ScopeClient, Path: "resources/search"}, "/api/v2/tenants/{tenantId}/resources/search"},
+		{Endpoint{Scope: ScopePartner, Path: "clients/%s", Params: []string{"clientId"}}, "/api/v2/tenants/{partnerId}/clients/{clientId}"},
+		{Endpoint{Scope: ScopeClient, Path: "resources/%s/metrics/%s/instances", Params: []string{"resourceId"}}, "/api/v2/tenants/{tenantId}/resources/{resourceId}/metrics/{id}/instances"},
+	}
+	for _, tt := range tests {
+		if got := tt.endpoint.Template(); got != tt.want {
+			t.Errorf("Template() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestDescribeEndpoints(t *testing.T) {
+	infos := DescribeEndpoints()
+	if len(infos) != len(EndpointNames()) {
+		t.Fatalf("Expected one description per endpoint, got %d", len(infos))
+	}
+	for i, info := range infos {
+		if i > 0 && infos[i-1].Name >= info.Name {
+			t.Errorf("Endpoints are not sorted: %s before %s", infos[i-1].Name, info.Name)
+		}
+		if info.Method == "" || info.Tool == "" || len(info.Actions) == 0 {
+			t.Errorf("%s: method, tool and calling actions are required, got %+v", info.Name, info)
+		}
+		if info.Method != "GET" && info.Method != "POST" && !info.Mutation {
+			t.Errorf("%s: %s endpoints change state and must be marked as mutations", info.Name, info.Method)
+		}
+		if info.Method == "GET" && info.Mutation {
+			t.Errorf("%s: GET endpoints must not be marked as mutations", info.Name)
+		}
+		if strings.Contains(info.Path, "%") {
+			t.Errorf("%s: template %s has an unnamed parameter", info.Name, info.Path)
+		}
+	}
+}
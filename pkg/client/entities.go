@@ -0,0 +1,145 @@
+package client
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// maxEntityCacheEntries bounds the number of entities cached per tenant
+const maxEntityCacheEntries = 1000
+
+// entityCaches holds the entity cache of each tenant, keyed by tenant URL
+// and ID, so every client of a tenant sees the invalidations of the others
+var entityCaches sync.Map
+
+// EntityCache is a short-lived read-through cache of single entities fetched
+// by ID, such as a resource or an integration. Mutations made through this
+// server invalidate the entity, so callers always see their own writes;
+// changes made elsewhere become visible when the entry expires.
+//
+// A read records the cache generation before fetching and passes it to Put,
+// which drops the value if anything was invalidated in the meantime. This
+// keeps a slow read from caching an entity a concurrent mutation changed.
+type EntityCache struct {
+	mu         sync.Mutex
+	entries    map[string]entityCacheEntry
+	generation uint64
+	ttl        time.Duration
+	now        func() time.Time
+}
+
+// entityCacheEntry is one cached entity
+type entityCacheEntry struct {
+	value    interface{}
+	storedAt time.Time
+}
+
+// NewEntityCache creates an entity cache; a ttl of zero disables caching
+func NewEntityCache(ttl time.Duration) *EntityCache {
+	return &EntityCache{
+		entries: make(map[string]entityCacheEntry),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+// SharedEntityCache returns the entity cache shared by all clients of the
+// tenant in config, creating it on first use
+func SharedEntityCache(config common.OpsRampConfig) *EntityCache {
+	key := config.TenantURL + "|" + config.TenantID
+	if cache, ok := entityCaches.Load(key); ok {
+		return cache.(*EntityCache)
+	}
+	ttl := time.Duration(config.Resources.EntityCacheTTL) * time.Second
+	cache, _ := entityCaches.LoadOrStore(key, NewEntityCache(ttl))
+	return cache.(*EntityCache)
+}
+
+// Get returns the cached entity of kind with id, if fresh, and the current
+// generation to pass to Put after fetching it
+func (c *EntityCache) Get(kind, id string) (interface{}, uint64, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := kind + "/" + id
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, c.generation, false
+	}
+	if c.now().Sub(entry.storedAt) >= c.ttl {
+		delete(c.entries, key)
+		return nil, c.generation, false
+	}
+	return entry.value, c.generation, true
+}
+
+// Put caches an entity fetched at generation, unless an invalidation has
+// happened since
+func (c *EntityCache) Put(kind, id string, value interface{}, generation uint64) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	if len(c.entries) >= maxEntityCacheEntries {
+		c.pruneLocked()
+		if len(c.entries) >= maxEntityCacheEntries {
+			return
+		}
+	}
+	c.entries[kind+"/"+id] = entityCacheEntry{value: value, storedAt: c.now()}
+}
+
+// Invalidate drops the cached entities of kind with the given IDs
+func (c *EntityCache) Invalidate(kind string, ids ...string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, id := range ids {
+		delete(c.entries, kind+"/"+id)
+	}
+}
+
+// InvalidateKind drops every cached entity of kind and returns how many
+// were dropped
+func (c *EntityCache) InvalidateKind(kind string) int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	dropped := 0
+	for key := range c.entries {
+		if strings.HasPrefix(key, kind+"/") {
+			delete(c.entries, key)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// pruneLocked drops expired entries; the caller holds mu
+func (c *EntityCache) pruneLocked() {
+	now := c.now()
+	for key, entry := range c.entries {
+		if now.Sub(entry.storedAt) >= c.ttl {
+			delete(c.entries, key)
+		}
+	}
+}
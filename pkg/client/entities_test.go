@@ -0,0 +1,101 @@
+package client
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestEntityCacheReadThrough(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cache := NewEntityCache(30 * time.Second)
+	cache.now = func() time.Time { return now }
+
+	_, generation, ok := cache.Get("resource", "r1")
+	if ok {
+		t.Fatal("Expected a miss on an empty cache")
+	}
+	cache.Put("resource", "r1", "v1", generation)
+
+	if value, _, ok := cache.Get("resource", "r1"); !ok || value != "v1" {
+		t.Errorf("Expected a hit, got %v, %v", value, ok)
+	}
+	if _, _, ok := cache.Get("integration", "r1"); ok {
+		t.Error("Expected kinds to be cached separately")
+	}
+
+	now = now.Add(30 * time.Second)
+	if _, _, ok := cache.Get("resource", "r1"); ok {
+		t.Error("Expected the entry to expire after the TTL")
+	}
+}
+
+func TestEntityCacheInvalidation(t *testing.T) {
+	cache := NewEntityCache(time.Minute)
+
+	_, generation, _ := cache.Get("resource", "r1")
+	cache.Put("resource", "r1", "v1", generation)
+	cache.Invalidate("resource", "r1")
+	if _, _, ok := cache.Get("resource", "r1"); ok {
+		t.Error("Expected the entry to be invalidated")
+	}
+
+	// A read that started before a mutation must not cache its result
+	_, generation, _ = cache.Get("resource", "r2")
+	cache.Invalidate("resource", "r2")
+	cache.Put("resource", "r2", "stale", generation)
+	if _, _, ok := cache.Get("resource", "r2"); ok {
+		t.Error("Expected a read racing a mutation not to be cached")
+	}
+}
+
+func TestEntityCacheInvalidateKind(t *testing.T) {
+	cache := NewEntityCache(time.Minute)
+	for _, key := range []string{"resources.search/a", "resources.search/b", "integrations.list/a"} {
+		kind, id, _ := strings.Cut(key, "/")
+		_, generation, _ := cache.Get(kind, id)
+		cache.Put(kind, id, "v", generation)
+	}
+
+	if dropped := cache.InvalidateKind("resources.search"); dropped != 2 {
+		t.Errorf("Expected 2 searches dropped, got %d", dropped)
+	}
+	if _, _, ok := cache.Get("resources.search", "a"); ok {
+		t.Error("Expected every entry of the kind to be dropped")
+	}
+	if _, _, ok := cache.Get("integrations.list", "a"); !ok {
+		t.Error("Expected other kinds to be kept")
+	}
+}
+
+func TestEntityCacheDisabled(t *testing.T) {
+	cache := NewEntityCache(0)
+	_, generation, _ := cache.Get("resource", "r1")
+	cache.Put("resource", "r1", "v1", generation)
+	if _, _, ok := cache.Get("resource", "r1"); ok {
+		t.Error("Expected a zero TTL to disable caching")
+	}
+
+	var nilCache *EntityCache
+	nilCache.Put("resource", "r1", "v1", 0)
+	nilCache.Invalidate("resource", "r1")
+	if _, _, ok := nilCache.Get("resource", "r1"); ok {
+		t.Error("Expected a nil cache to miss")
+	}
+}
+
+func TestSharedEntityCache(t *testing.T) {
+	tenant := common.OpsRampConfig{TenantURL: "https://shared.example.com", TenantID: "t1",
+		Resources: common.ResourcesConfig{EntityCacheTTL: 30}}
+	other := tenant
+	other.TenantID = "t2"
+
+	if SharedEntityCache(tenant) != SharedEntityCache(tenant) {
+		t.Error("Expected clients of a tenant to share a cache")
+	}
+	if SharedEntityCache(tenant) == SharedEntityCache(other) {
+		t.Error("Expected tenants to have separate caches")
+	}
+}
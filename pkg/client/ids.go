@@ -0,0 +1,57 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// MaxIDLength is the longest ID accepted as a URL path parameter
+const MaxIDLength = 128
+
+// InvalidIDError is a validation error for an ID that cannot safely be used
+// as a URL path parameter
+type InvalidIDError struct {
+	Kind   string
+	ID     string
+	Reason string
+}
+
+func (e *InvalidIDError) Error() string {
+	id := e.ID
+	if len(id) > 64 {
+		id = id[:64] + "..."
+	}
+	return fmt.Sprintf("invalid %s ID %q: %s", e.Kind, id, e.Reason)
+}
+
+// ValidateID checks that an ID taken from tool input is a canonical OpsRamp
+// ID: 1 to MaxIDLength letters, digits, '-', '_', '.' or ':', and not a
+// relative path element
+func ValidateID(kind, id string) error {
+	if id == "" {
+		return &InvalidIDError{Kind: kind, ID: id, Reason: "must not be empty"}
+	}
+	if len(id) > MaxIDLength {
+		return &InvalidIDError{Kind: kind, ID: id, Reason: fmt.Sprintf("must be at most %d characters", MaxIDLength)}
+	}
+	if id == "." || id == ".." {
+		return &InvalidIDError{Kind: kind, ID: id, Reason: "must not be a relative path element"}
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return &InvalidIDError{Kind: kind, ID: id, Reason: fmt.Sprintf("contains disallowed character %q", r)}
+		}
+	}
+	return nil
+}
+
+// PathID validates an ID and escapes it for use as a single URL path segment
+func PathID(kind, id string) (string, error) {
+	if err := ValidateID(kind, id); err != nil {
+		return "", err
+	}
+	return url.PathEscape(id), nil
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func init() {
+	common.NewCounterFunc("or_mcp_opsramp_token_rejections_total", "OpsRamp requests retried because OpsRamp rejected an auth token before it expired.", func() float64 {
+		return float64(TokenRejectionCount())
+	})
+	common.NewGaugeFunc("or_mcp_opsramp_queued_requests", "OpsRamp requests waiting for a slot in a priority pool.", func() float64 {
+		var queued int64
+		for _, count := range QueuedRequestCounts() {
+			queued += count
+		}
+		return float64(queued)
+	})
+}
+
+// EndpointNameForRequest returns the name of the registered endpoint a
+// request path belongs to, such as resources.get, so metrics group requests
+// by endpoint rather than by resource ID. Paths that match no endpoint are
+// "other".
+func EndpointNameForRequest(method, requestPath string) string {
+	if parsed, err := url.Parse(requestPath); err == nil {
+		requestPath = parsed.Path
+	}
+	rest, ok := strings.CutPrefix(requestPath, apiPrefix)
+	if !ok {
+		return "other"
+	}
+	// Drop the tenant segment; the scope does not tell endpoints apart
+	_, rest, _ = strings.Cut(rest, "/")
+	segments := strings.Split(strings.Trim(rest, "/"), "/")
+
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	best, bestLiterals := "other", -1
+	for name, endpoint := range endpoints {
+		if endpoint.Method != "" && !strings.EqualFold(endpoint.Method, method) {
+			continue
+		}
+		literals, ok := matchEndpointPath(endpoint.Path, segments)
+		// Prefer the most literal match, then the first name, so
+		// resources/search is not taken for resources/{id}
+		if ok && (literals > bestLiterals || (literals == bestLiterals && name < best)) {
+			best, bestLiterals = name, literals
+		}
+	}
+	return best
+}
+
+// matchEndpointPath reports whether segments match an endpoint path, where a
+// segment holding a fmt verb matches any value, and how many segments
+// matched literally
+func matchEndpointPath(endpointPath string, segments []string) (int, bool) {
+	parts := strings.Split(strings.Trim(endpointPath, "/"), "/")
+	if len(parts) != len(segments) {
+		return 0, false
+	}
+	literals := 0
+	for i, part := range parts {
+		if strings.Contains(part, "%") {
+			continue
+		}
+		if part != segments[i] {
+			return 0, false
+		}
+		literals++
+	}
+	return literals, true
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestEndpointNameForRequest(t *testing.T) {
+	for path, want := range map[string]string{
+		"GET /api/v2/tenants/t-1/resources/search?pageNo=2": "resources.search",
+		"GET /api/v2/tenants/t-1/resources/r-9":             "resources.get",
+		"GET /api/v2/tenants/t-1/resources/r-9/tags":        "resources.tags",
+		"POST /api/v2/tenants/t-1/unknown/thing":            "other",
+		"GET /auth/token":                                   "other",
+	} {
+		method, requestPath, _ := strings.Cut(path, " ")
+		if got := EndpointNameForRequest(method, requestPath); got != want {
+			t.Errorf("%s: expected %s, got %s", path, want, got)
+		}
+	}
+}
+
+func TestRequestsAreMeasured(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/token" {
+			w.Write([]byte(`{"access_token": "token", "expires_in": 3600}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: server.URL, AuthURL: server.URL + "/auth/token", TenantID: "t-1",
+		AuthKey: "key", AuthSecret: "secret",
+	}})
+	before := common.OpsRampRequestDuration.Count("GET", "resources.get", "4xx")
+	refreshes := common.TokenRefreshes.Value("success")
+	c.Get(context.Background(), "/api/v2/tenants/t-1/resources/r-1", nil)
+
+	if got := common.OpsRampRequestDuration.Count("GET", "resources.get", "4xx"); got != before+1 {
+		t.Errorf("Expected the request to be measured, got %d observations", got-before)
+	}
+	if common.TokenRefreshes.Value("success") <= refreshes {
+		t.Error("Expected the token fetch to be counted")
+	}
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// maxPaginatedPages bounds the pages Paginate fetches, so an endpoint that
+// keeps reporting another page cannot be followed forever
+const maxPaginatedPages = 1000
+
+// defaultPaginatedPageSize is the page size Paginate asks for when params
+// set none
+const defaultPaginatedPageSize = 100
+
+// ErrStopPagination is returned by a Paginate callback to stop after the
+// current page without error
+var ErrStopPagination = errors.New("stop pagination")
+
+// Page is one page of a search endpoint paged by page number
+type Page struct {
+	// Results is the page's results array, for the callback to decode
+	Results json.RawMessage
+	// Info is the page's pagination metadata, normalized as
+	// types.NormalizePage does for single pages
+	Info types.PageInfo
+}
+
+// Decode decodes the page's results into v, typically a pointer to a slice
+func (p *Page) Decode(v interface{}) error {
+	if len(p.Results) == 0 {
+		return nil
+	}
+	return json.Unmarshal(p.Results, v)
+}
+
+// searchPage holds the fields of a paged search response used by Paginate
+type searchPage struct {
+	Results      json.RawMessage `json:"results"`
+	PageNo       int             `json:"pageNo"`
+	PageSize     int             `json:"pageSize"`
+	TotalResults int             `json:"totalResults"`
+	TotalPages   int             `json:"totalPages"`
+	NextPage     bool            `json:"nextPage"`
+}
+
+// Paginate fetches the pages of a GET search endpoint paged by pageNo and
+// pageSize, calling fn with each page in order. It starts at the pageNo in
+// params, or the first page, and follows nextPage and totalPages until a
+// short or empty page, fn returns ErrStopPagination, or maxPaginatedPages
+// pages. Another error from fn stops pagination and is returned. Pages after
+// the first are fetched in the background pool, so a long scan does not hold
+// up interactive calls.
+func (c *OpsRampClient) Paginate(ctx context.Context, endpoint string, params url.Values, fn func(page *Page) error) error {
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	pageNo, _ := strconv.Atoi(query.Get("pageNo"))
+	pageNo = max(pageNo, 1)
+	pageSize, _ := strconv.Atoi(query.Get("pageSize"))
+	if pageSize <= 0 {
+		pageSize = defaultPaginatedPageSize
+		query.Set("pageSize", strconv.Itoa(pageSize))
+	}
+
+	for fetched := 0; fetched < maxPaginatedPages; fetched++ {
+		if fetched > 0 {
+			ctx = WithPriority(ctx, PriorityBackground)
+		}
+		query.Set("pageNo", strconv.Itoa(pageNo))
+
+		var response searchPage
+		if err := c.Get(ctx, endpoint+"?"+query.Encode(), &response); err != nil {
+			return fmt.Errorf("failed to fetch page %d: %w", pageNo, err)
+		}
+		var results []json.RawMessage
+		if len(response.Results) > 0 {
+			if err := json.Unmarshal(response.Results, &results); err != nil {
+				return fmt.Errorf("failed to parse page %d: %w", pageNo, err)
+			}
+		}
+
+		page := &Page{
+			Results: response.Results,
+			Info: types.NormalizePage(pageNo, pageSize, len(results), types.PageCounts{
+				PageNo: response.PageNo, PageSize: response.PageSize, TotalResults: response.TotalResults,
+				TotalPages: response.TotalPages, NextPage: response.NextPage,
+			}),
+		}
+		if err := fn(page); err != nil {
+			if errors.Is(err, ErrStopPagination) {
+				return nil
+			}
+			return err
+		}
+		if !page.Info.HasMore {
+			return nil
+		}
+		pageNo++
+	}
+
+	c.logger.Warn("Stopped paging %s after %d pages", endpoint, maxPaginatedPages)
+	return nil
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestPaginate(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/token" {
+			w.Write([]byte(`{"access_token": "token", "expires_in": 3600}`))
+			return
+		}
+		requested = append(requested, r.URL.Query().Get("pageNo")+"/"+r.URL.Query().Get("pageSize"))
+		// Seven results in pages of three; the last page is short and
+		// page two omits nextPage, leaving totalPages to tell
+		pageNo, _ := strconv.Atoi(r.URL.Query().Get("pageNo"))
+		ids := []string{}
+		for i := (pageNo-1)*3 + 1; i <= min(pageNo*3, 7); i++ {
+			ids = append(ids, fmt.Sprintf(`{"id":"r%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"results":[%s],"pageNo":%d,"pageSize":3,"totalPages":3,"nextPage":%t}`, strings.Join(ids, ","), pageNo, pageNo == 1)
+	}))
+	defer server.Close()
+
+	c := NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: server.URL, AuthURL: server.URL + "/auth/token", TenantID: "t-1",
+		AuthKey: "key", AuthSecret: "secret",
+	}})
+	ctx := context.Background()
+
+	var ids []string
+	var last Page
+	err := c.Paginate(ctx, "/api/v2/search", map[string][]string{"pageSize": {"3"}, "state": {"active"}}, func(page *Page) error {
+		var results []struct{ ID string }
+		if err := page.Decode(&results); err != nil {
+			return err
+		}
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		last = *page
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if strings.Join(ids, ",") != "r1,r2,r3,r4,r5,r6,r7" || strings.Join(requested, ",") != "1/3,2/3,3/3" {
+		t.Errorf("Expected every page to be followed, got %v from %v", ids, requested)
+	}
+	if last.Info.PageNo != 3 || last.Info.HasMore || !last.Info.TotalExact || last.Info.EstimatedTotal != 7 {
+		t.Errorf("Expected the last page to report an exact total of 7, got %+v", last.Info)
+	}
+
+	requested = nil
+	pages := 0
+	err = c.Paginate(ctx, "/api/v2/search", map[string][]string{"pageNo": {"2"}}, func(page *Page) error {
+		pages++
+		return ErrStopPagination
+	})
+	if err != nil || pages != 1 || strings.Join(requested, ",") != "2/100" {
+		t.Errorf("Expected ErrStopPagination to stop after the starting page, got %d pages from %v (%v)", pages, requested, err)
+	}
+
+	failure := errors.New("callback failed")
+	if err := c.Paginate(ctx, "/api/v2/search", nil, func(page *Page) error { return failure }); !errors.Is(err, failure) {
+		t.Errorf("Expected the callback's error, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// PermissionCheck is the outcome of one read made by the permission self-check
+type PermissionCheck struct {
+	Endpoint string `json:"endpoint"`
+	Tool     string `json:"tool"`
+	Allowed  bool   `json:"allowed"`
+	Status   int    `json:"status,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// CheckPermissions makes one minimal read through every registered GET
+// endpoint without path parameters and reports which of them the client's
+// credentials may call. A 401 or 403 means the credentials lack the
+// permission; endpoints in a scope the client cannot address are reported
+// without a request.
+func (c *OpsRampClient) CheckPermissions(ctx context.Context) []PermissionCheck {
+	checks := make([]PermissionCheck, 0)
+	for _, name := range EndpointNames() {
+		endpoint, _ := LookupEndpoint(name)
+		if endpoint.Method != http.MethodGet || endpoint.Mutation || strings.Contains(endpoint.Path, "%") {
+			continue
+		}
+		check := PermissionCheck{Endpoint: name, Tool: endpoint.Tool}
+		path, err := c.EndpointPath(name)
+		if err != nil {
+			check.Error = err.Error()
+			checks = append(checks, check)
+			continue
+		}
+
+		check.Status, err = c.GetWithStatusCode(ctx, path+"?pageNo=1&pageSize=1", nil)
+		switch {
+		case err == nil:
+			check.Allowed = true
+		case check.Status == http.StatusUnauthorized || check.Status == http.StatusForbidden:
+			check.Error = "permission denied"
+		default:
+			check.Error = err.Error()
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
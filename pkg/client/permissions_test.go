@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	c := newCatalogTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Query().Get("pageSize") != "1" {
+			t.Errorf("Expected a minimal GET, got %s %s", r.Method, r.URL)
+		}
+		if strings.HasSuffix(r.URL.Path, "/alerts/search") {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"Access denied"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}, 0)
+
+	checks := map[string]PermissionCheck{}
+	for _, check := range c.CheckPermissions(context.Background()) {
+		checks[check.Endpoint] = check
+	}
+
+	if check := checks["resources.search"]; !check.Allowed {
+		t.Errorf("Expected resources.search to be allowed, got %+v", check)
+	}
+	if check := checks["alerts.search"]; check.Allowed || check.Status != http.StatusForbidden || check.Error != "permission denied" {
+		t.Errorf("Expected alerts.search to be denied, got %+v", check)
+	}
+	// Partner-scoped endpoints cannot be called without a partner ID
+	if check := checks["clients.search"]; check.Allowed || check.Status != 0 || check.Error == "" {
+		t.Errorf("Expected clients.search to be skipped, got %+v", check)
+	}
+	for _, name := range []string{"resources.get", "resources.create", "resources.metrics"} {
+		if _, ok := checks[name]; ok {
+			t.Errorf("Expected %s to be left out of the self-check", name)
+		}
+	}
+}
+
+func TestWarmCatalogs(t *testing.T) {
+	requests := 0
+	c := newCatalogTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"resourceTypes": [{"name": "server"}]}`))
+	}, 300)
+
+	outcomes := c.WarmCatalogs(context.Background())
+	if outcomes["resources.types"] != string(CatalogFetched) {
+		t.Fatalf("Expected resources.types to be fetched, got %v", outcomes)
+	}
+
+	var result map[string]interface{}
+	status, err := c.GetCatalog(context.Background(), "/api/v2/tenants/tenant-1/resources/types", &result)
+	if err != nil || status != CatalogCached {
+		t.Errorf("Expected the warmed catalog to be cached, got %s, %v", status, err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single catalog request, got %d", requests)
+	}
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// Priority is the priority of an OpsRamp request
+type Priority int
+
+const (
+	// PriorityInteractive requests serve a tool call a client is waiting on
+	PriorityInteractive Priority = iota
+	// PriorityBackground requests serve long-running work such as bulk
+	// actions, paging scans, scheduled actions and exporters
+	PriorityBackground
+)
+
+// String returns the name of the priority
+func (p Priority) String() string {
+	if p == PriorityBackground {
+		return "background"
+	}
+	return "interactive"
+}
+
+// priorityKey is the context key of the request priority
+type priorityKey struct{}
+
+// WithPriority returns a context whose OpsRamp requests have priority p
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority of the context's OpsRamp
+// requests, interactive unless set otherwise
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, _ := ctx.Value(priorityKey{}).(Priority); p == PriorityBackground {
+		return p
+	}
+	return PriorityInteractive
+}
+
+// queuedRequests counts the requests of each priority waiting for a slot
+var queuedRequests [2]atomic.Int64
+
+// QueuedRequestCounts returns the number of OpsRamp requests waiting for a
+// slot in their pool, by priority
+func QueuedRequestCounts() map[string]int64 {
+	return map[string]int64{
+		PriorityInteractive.String(): queuedRequests[PriorityInteractive].Load(),
+		PriorityBackground.String():  queuedRequests[PriorityBackground].Load(),
+	}
+}
+
+// RequestPools bounds the concurrent requests to a tenant with a separate
+// pool per priority, so background work that fills its pool never delays
+// interactive requests
+type RequestPools struct {
+	slots [2]chan struct{}
+}
+
+// NewRequestPools creates pools of the given sizes; a size below 1 takes the default
+func NewRequestPools(interactive, background int) *RequestPools {
+	if interactive < 1 {
+		interactive = common.DefaultInteractiveConcurrency
+	}
+	if background < 1 {
+		background = common.DefaultBackgroundConcurrency
+	}
+	return &RequestPools{slots: [2]chan struct{}{make(chan struct{}, interactive), make(chan struct{}, background)}}
+}
+
+// requestPools holds the request pools of each tenant and credential
+var requestPools sync.Map
+
+// SharedRequestPools returns the request pools shared by all clients of the
+// tenant in config that use the same credentials, creating them on first
+// use with the sizes in limits
+func SharedRequestPools(config common.OpsRampConfig, limits common.ConcurrencyConfig) *RequestPools {
+	key := config.TenantURL + "|" + config.TenantID + "|" + config.AuthKey
+	if pools, ok := requestPools.Load(key); ok {
+		return pools.(*RequestPools)
+	}
+	pools, _ := requestPools.LoadOrStore(key, NewRequestPools(limits.Interactive, limits.Background))
+	return pools.(*RequestPools)
+}
+
+// Acquire waits for a slot in the pool of the context's priority and
+// returns the function that releases it. It fails when ctx is done first.
+// Nil pools do not limit requests.
+func (p *RequestPools) Acquire(ctx context.Context) (func(), error) {
+	if p == nil {
+		return func() {}, nil
+	}
+	priority := PriorityFromContext(ctx)
+	slots := p.slots[priority]
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	default:
+	}
+
+	queuedRequests[priority].Add(1)
+	defer queuedRequests[priority].Add(-1)
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
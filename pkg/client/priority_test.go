@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestBackgroundRequestsDoNotDelayInteractiveOnes(t *testing.T) {
+	release := make(chan struct{})
+	c := newCatalogTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/bulk") {
+			<-release
+		}
+		w.Write([]byte(`{}`))
+	}, 0)
+
+	// Fill the background pool and queue one more background request
+	background := WithPriority(context.Background(), PriorityBackground)
+	var wg sync.WaitGroup
+	for i := 0; i < common.DefaultBackgroundConcurrency+1; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Request(background, http.MethodPost, "/api/v2/tenants/tenant-1/resources/bulk", nil, nil)
+		}()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for QueuedRequestCounts()["background"] < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for a queued background request")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Request(context.Background(), http.MethodPost, "/api/v2/tenants/tenant-1/resources", nil, nil)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected the interactive request to succeed, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the interactive request not to wait for background requests")
+	}
+
+	close(release)
+	wg.Wait()
+	if queued := QueuedRequestCounts()["background"]; queued != 0 {
+		t.Errorf("Expected no queued background requests, got %d", queued)
+	}
+}
+
+func TestRequestPoolsAcquireHonorsContext(t *testing.T) {
+	pools := NewRequestPools(1, 1)
+	release, err := pools.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := pools.Acquire(ctx); err == nil {
+		t.Error("Expected a full pool to fail when the context is done")
+	}
+	if PriorityFromContext(context.Background()) != PriorityInteractive {
+		t.Error("Expected requests to be interactive by default")
+	}
+}
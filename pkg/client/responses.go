@@ -0,0 +1,27 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// responseCaches holds the response cache of each tenant, keyed by tenant
+// URL and ID, so every client of a tenant sees the invalidations of the others
+var responseCaches sync.Map
+
+// SharedResponseCache returns the cache of list and search responses shared
+// by all clients of the tenant in config, creating it on first use. Entries
+// are keyed by the kind of query and its endpoint with parameters, and are
+// reused for cache_ttl seconds. Writes through this server invalidate the
+// kinds they can change, with the same generation check as the entity cache.
+func SharedResponseCache(config common.OpsRampConfig) *EntityCache {
+	key := config.TenantURL + "|" + config.TenantID
+	if cache, ok := responseCaches.Load(key); ok {
+		return cache.(*EntityCache)
+	}
+	ttl := time.Duration(config.Resources.CacheTTL) * time.Second
+	cache, _ := responseCaches.LoadOrStore(key, NewEntityCache(ttl))
+	return cache.(*EntityCache)
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// SnapshotTransport replays recorded OpsRamp responses instead of calling the
+// API. A snapshot file is a JSON object mapping "METHOD /path" (optionally
+// with a query string) to the recorded response body, e.g.
+//
+//	{"GET /api/v2/tenants/client_1/alerts/42": {"id": "42", "subject": "CPU high"}}
+//
+// Requests without a recording get a 404.
+type SnapshotTransport struct {
+	path      string
+	responses map[string]json.RawMessage
+}
+
+// LoadSnapshot reads a snapshot file
+func LoadSnapshot(path string) (*SnapshotTransport, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+	}
+
+	var responses map[string]json.RawMessage
+	if err := json.Unmarshal(data, &responses); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+
+	return &SnapshotTransport{path: path, responses: responses}, nil
+}
+
+// RoundTrip implements http.RoundTripper. The recording for the exact query
+// string wins over one recorded for the bare path.
+func (t *SnapshotTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.Path
+
+	body, ok := t.responses[key+"?"+req.URL.RawQuery]
+	if !ok {
+		body, ok = t.responses[key]
+	}
+
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusNotFound
+		body, _ = json.Marshal(map[string]string{
+			"error": fmt.Sprintf("no response recorded for %s in snapshot %s", key, t.path),
+		})
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// NewSnapshotClient creates a client that serves every request from a snapshot
+// file. It never authenticates or contacts OpsRamp.
+func NewSnapshotClient(config *common.Config, path string) (*OpsRampClient, error) {
+	transport, err := LoadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewOpsRampClient(config)
+	c.authClient = common.NewStaticAuthClient("snapshot")
+	c.httpClient = &http.Client{Transport: transport}
+	c.entities = NewEntityCache(time.Duration(config.OpsRamp.Resources.EntityCacheTTL) * time.Second)
+	c.responses = NewEntityCache(time.Duration(config.OpsRamp.Resources.CacheTTL) * time.Second)
+	c.reads = NewReadGroup()
+	if !strings.HasPrefix(c.baseURL, "http") {
+		c.baseURL = "http://snapshot"
+	}
+
+	c.logger.Info("Replaying OpsRamp responses from snapshot %s (%d recordings)", path, len(transport.responses))
+	return c, nil
+}
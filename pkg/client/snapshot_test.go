@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestSnapshotClientReplaysRecordings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	recordings := `{
+		"GET /api/v2/tenants/tenant-1/alerts/42": {"id": "42", "subject": "CPU high"},
+		"GET /api/v2/tenants/tenant-1/alerts/search": {"totalResults": 0},
+		"GET /api/v2/tenants/tenant-1/alerts/search?queryString=state:CRITICAL": {"totalResults": 3}
+	}`
+	if err := os.WriteFile(path, []byte(recordings), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewSnapshotClient(&common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: "https://tenant.example.com",
+		AuthURL:   "https://unreachable.invalid/token",
+		TenantID:  "tenant-1",
+	}}, path)
+	if err != nil {
+		t.Fatalf("Failed to load snapshot: %v", err)
+	}
+
+	var alert map[string]interface{}
+	if err := c.Get(context.Background(), "/api/v2/tenants/tenant-1/alerts/42", &alert); err != nil {
+		t.Fatalf("Expected recorded alert, got error: %v", err)
+	}
+	if alert["subject"] != "CPU high" {
+		t.Errorf("Unexpected alert: %v", alert)
+	}
+
+	var search map[string]interface{}
+	if err := c.Get(context.Background(), "/api/v2/tenants/tenant-1/alerts/search?queryString=state:CRITICAL", &search); err != nil {
+		t.Fatal(err)
+	}
+	if search["totalResults"] != float64(3) {
+		t.Errorf("Expected the exact query recording to win, got %v", search)
+	}
+	if err := c.Get(context.Background(), "/api/v2/tenants/tenant-1/alerts/search?pageNo=2", &search); err != nil {
+		t.Fatal(err)
+	}
+	if search["totalResults"] != float64(0) {
+		t.Errorf("Expected fallback to the bare path recording, got %v", search)
+	}
+
+	status, err := c.GetWithStatusCode(context.Background(), "/api/v2/tenants/tenant-1/alerts/43", &alert)
+	if status != 404 || err == nil || !strings.Contains(err.Error(), "no response recorded") {
+		t.Errorf("Expected 404 for unrecorded request, got %d (%v)", status, err)
+	}
+}
+
+func TestLoadSnapshotRejectsInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, []byte(`[1, 2]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSnapshot(path); err == nil {
+		t.Error("Expected an error for a snapshot that is not an object")
+	}
+	if _, err := LoadSnapshot(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing snapshot")
+	}
+}
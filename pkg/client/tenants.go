@@ -0,0 +1,468 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+// DefaultTenant is the name of the tenant configured in the opsramp section
+const DefaultTenant = "default"
+
+// DefaultProbeInterval is how often tenant health probes run in the background
+const DefaultProbeInterval = 60 * time.Second
+
+// TenantStatus represents the health of a tenant as seen by this server
+type TenantStatus string
+
+const (
+	// TenantHealthy means the last probe succeeded (or no probe has run yet)
+	TenantHealthy TenantStatus = "healthy"
+	// TenantDegraded means the tenant is unreachable and calls targeting it fail fast
+	TenantDegraded TenantStatus = "degraded"
+)
+
+// StartupCheckStatus is the outcome of the startup health check
+type StartupCheckStatus string
+
+const (
+	// StartupCheckPending means the startup check has not finished yet
+	StartupCheckPending StartupCheckStatus = "pending"
+	// StartupCheckPassed means every tenant was reachable
+	StartupCheckPassed StartupCheckStatus = "passed"
+	// StartupCheckDegraded means some tenants were still unreachable after the last attempt
+	StartupCheckDegraded StartupCheckStatus = "degraded"
+	// StartupCheckFailed means no tenant was reachable after the last attempt
+	StartupCheckFailed StartupCheckStatus = "failed"
+)
+
+// StartupProbeTimeout bounds each attempt of the startup health check
+const StartupProbeTimeout = 30 * time.Second
+
+// MaxStartupBackoff caps the delay between startup health check attempts
+const MaxStartupBackoff = 30 * time.Second
+
+// StartupCheck is a snapshot of the startup health check
+type StartupCheck struct {
+	Status      StartupCheckStatus `json:"status"`
+	Attempts    int                `json:"attempts"`
+	Unreachable []string           `json:"unreachable,omitempty"`
+	StartedAt   *time.Time         `json:"startedAt,omitempty"`
+	CompletedAt *time.Time         `json:"completedAt,omitempty"`
+}
+
+// TenantHealth is a snapshot of a tenant's health state
+type TenantHealth struct {
+	Status              TenantStatus `json:"status"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	LastError           string       `json:"lastError,omitempty"`
+	LastChecked         time.Time    `json:"lastChecked,omitempty"`
+	DegradedSince       *time.Time   `json:"degradedSince,omitempty"`
+}
+
+// TenantUnavailableError is returned for calls targeting a degraded tenant
+type TenantUnavailableError struct {
+	Tenant    string
+	Since     time.Time
+	LastError string
+}
+
+// Error implements the error interface
+func (e *TenantUnavailableError) Error() string {
+	return fmt.Sprintf("tenant %s is unavailable (degraded since %s): %s; calls to other tenants are unaffected",
+		e.Tenant, e.Since.Format(time.RFC3339), e.LastError)
+}
+
+// Tenant is a single OpsRamp tenant served by this server
+type Tenant struct {
+	Name   string
+	Config common.OpsRampConfig
+	Client *OpsRampClient
+
+	mu     sync.RWMutex
+	health TenantHealth
+}
+
+// Health returns a snapshot of the tenant's health
+func (t *Tenant) Health() TenantHealth {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.health
+}
+
+// ProbeFunc checks whether a tenant is reachable
+type ProbeFunc func(ctx context.Context, t *Tenant) error
+
+// TenantRegistry tracks every configured tenant and isolates their health so
+// that one unreachable tenant does not affect calls to the others
+type TenantRegistry struct {
+	mu      sync.RWMutex
+	tenants map[string]*Tenant
+	probe   ProbeFunc
+	egress  common.EgressConfig
+	labels  map[string]map[string]string
+	logger  *common.CustomLogger
+	startup StartupCheck
+	// probeInterval is how often tenants are probed, which is when clients
+	// may retry after the server was announced degraded
+	probeInterval time.Duration
+}
+
+// NewTenantRegistry creates a registry containing the default tenant and any
+// additional tenants listed in the configuration
+func NewTenantRegistry(config *common.Config) *TenantRegistry {
+	r := &TenantRegistry{
+		tenants: make(map[string]*Tenant),
+		probe:   defaultProbe,
+		egress:  config.Egress,
+		labels:  map[string]map[string]string{DefaultTenant: config.TelemetryLabels(DefaultTenant)},
+		logger:  common.GetLogger(),
+		startup: StartupCheck{Status: StartupCheckPending},
+
+		probeInterval: DefaultProbeInterval,
+	}
+	for _, tenant := range config.Tenants {
+		r.labels[tenant.Name] = config.TelemetryLabels(tenant.Name)
+	}
+
+	r.Register(DefaultTenant, config.OpsRamp)
+	for _, tenant := range config.Tenants {
+		r.Register(tenant.Name, tenant.OpsRampConfig)
+	}
+
+	return r
+}
+
+// SetProbe replaces the function used to probe tenant health
+func (r *TenantRegistry) SetProbe(probe ProbeFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.probe = probe
+}
+
+// Register adds (or replaces) a tenant in the registry
+func (r *TenantRegistry) Register(name string, config common.OpsRampConfig) *Tenant {
+	tenant := &Tenant{
+		Name:   name,
+		Config: config,
+		Client: NewOpsRampClient(&common.Config{OpsRamp: config, Egress: r.egress}),
+		health: TenantHealth{Status: TenantHealthy},
+	}
+	r.mu.Lock()
+	labels, ok := r.labels[name]
+	if !ok {
+		labels = map[string]string{common.TelemetryLabelTenant: name}
+	}
+	tenant.Client.logger = r.logger.WithLabels(labels).WithModule(common.ModuleClient)
+	r.tenants[name] = tenant
+	r.mu.Unlock()
+
+	r.logger.Info("Registered tenant %s (%s)", name, config.TenantURL)
+	return tenant
+}
+
+// AddTenant registers a tenant onboarded at runtime, labelled like a tenant
+// from the config file: a tenant without an environment inherits the default
+// tenant's
+func (r *TenantRegistry) AddTenant(name string, config common.OpsRampConfig) *Tenant {
+	r.mu.Lock()
+	if config.Environment == "" {
+		config.Environment = r.labels[DefaultTenant][common.TelemetryLabelEnvironment]
+	}
+	r.labels[name] = (&common.Config{OpsRamp: config}).TelemetryLabels(name)
+	r.mu.Unlock()
+	return r.Register(name, config)
+}
+
+// Names returns the registered tenant names in sorted order
+func (r *TenantRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.tenants))
+	for name := range r.tenants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the named tenant regardless of its health
+func (r *TenantRegistry) Lookup(name string) (*Tenant, bool) {
+	if name == "" {
+		name = DefaultTenant
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tenant, ok := r.tenants[name]
+	return tenant, ok
+}
+
+// Tenant returns the named tenant for a call, failing fast with a
+// TenantUnavailableError if the tenant is currently degraded
+func (r *TenantRegistry) Tenant(name string) (*Tenant, error) {
+	tenant, ok := r.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown tenant: %s (configured tenants: %v)", name, r.Names())
+	}
+
+	health := tenant.Health()
+	if health.Status == TenantDegraded {
+		since := time.Now()
+		if health.DegradedSince != nil {
+			since = *health.DegradedSince
+		}
+		return nil, &TenantUnavailableError{Tenant: tenant.Name, Since: since, LastError: health.LastError}
+	}
+
+	return tenant, nil
+}
+
+// Health returns the health of every registered tenant
+func (r *TenantRegistry) Health() map[string]TenantHealth {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	health := make(map[string]TenantHealth, len(r.tenants))
+	for name, tenant := range r.tenants {
+		health[name] = tenant.Health()
+	}
+	return health
+}
+
+// ProbeAll probes every tenant concurrently and updates their health, returning
+// the probe error for each tenant that failed
+func (r *TenantRegistry) ProbeAll(ctx context.Context) map[string]error {
+	r.mu.RLock()
+	tenants := make([]*Tenant, 0, len(r.tenants))
+	for _, tenant := range r.tenants {
+		tenants = append(tenants, tenant)
+	}
+	probe := r.probe
+	r.mu.RUnlock()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failures := make(map[string]error)
+
+	for _, tenant := range tenants {
+		wg.Add(1)
+		go func(t *Tenant) {
+			defer wg.Done()
+			err := probe(ctx, t)
+			r.recordProbe(t, err)
+			if err != nil {
+				mu.Lock()
+				failures[t.Name] = err
+				mu.Unlock()
+			}
+		}(tenant)
+	}
+	wg.Wait()
+
+	r.announceServerStatus()
+	return failures
+}
+
+// announceServerStatus tells connected clients the server is degraded when
+// no tenant is reachable, and ready again once one is, so they can pause
+// their work instead of seeing every tool call fail. A shutdown already
+// announced is left in place.
+func (r *TenantRegistry) announceServerStatus() {
+	health := r.Health()
+	degraded := 0
+	for _, tenant := range health {
+		if tenant.Status == TenantDegraded {
+			degraded++
+		}
+	}
+
+	r.mu.RLock()
+	retryAfter := int(r.probeInterval / time.Second)
+	r.mu.RUnlock()
+
+	switch current := session.CurrentStatus().Status; {
+	case current == session.StatusReady && len(health) > 0 && degraded == len(health):
+		r.logger.Warn("No tenant is reachable; announcing degraded mode to connected clients")
+		session.BroadcastStatus(session.StatusNotice{
+			Status:            session.StatusDegraded,
+			Reason:            fmt.Sprintf("No OpsRamp tenant is reachable (%d of %d degraded); tool calls will fail until one recovers", degraded, len(health)),
+			RetryAfterSeconds: retryAfter,
+		})
+	case current == session.StatusDegraded && degraded < len(health):
+		r.logger.Info("A tenant is reachable again; announcing readiness to connected clients")
+		session.BroadcastStatus(session.StatusNotice{
+			Status: session.StatusReady,
+			Reason: fmt.Sprintf("OpsRamp is reachable again (%d of %d tenants healthy)", len(health)-degraded, len(health)),
+		})
+	}
+}
+
+// CheckStartup probes every tenant, retrying with exponential backoff from
+// the given delay until all tenants are reachable or attempts run out. It
+// returns the failures of the last attempt; progress is available from
+// StartupStatus while it runs.
+func (r *TenantRegistry) CheckStartup(ctx context.Context, attempts int, backoff time.Duration) map[string]error {
+	started := time.Now()
+	r.mu.Lock()
+	r.startup = StartupCheck{Status: StartupCheckPending, StartedAt: &started}
+	total := len(r.tenants)
+	r.mu.Unlock()
+
+	var failures map[string]error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		probeCtx, cancel := context.WithTimeout(ctx, StartupProbeTimeout)
+		failures = r.ProbeAll(probeCtx)
+		cancel()
+
+		r.mu.Lock()
+		r.startup.Attempts = attempt
+		r.startup.Unreachable = failedTenants(failures)
+		r.mu.Unlock()
+
+		if len(failures) == 0 || attempt == attempts {
+			break
+		}
+		r.logger.Info("Startup health check attempt %d of %d: %d of %d tenants unreachable; retrying in %s",
+			attempt, attempts, len(failures), total, backoff)
+		select {
+		case <-ctx.Done():
+			attempts = attempt
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, MaxStartupBackoff)
+	}
+
+	completed := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.startup.CompletedAt = &completed
+	switch {
+	case len(failures) == 0:
+		r.startup.Status = StartupCheckPassed
+	case len(failures) == total:
+		r.startup.Status = StartupCheckFailed
+	default:
+		r.startup.Status = StartupCheckDegraded
+	}
+	return failures
+}
+
+// StartupStatus returns a snapshot of the startup health check
+func (r *TenantRegistry) StartupStatus() StartupCheck {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	status := r.startup
+	status.Unreachable = append([]string(nil), r.startup.Unreachable...)
+	return status
+}
+
+// failedTenants returns the sorted names of the tenants in failures
+func failedTenants(failures map[string]error) []string {
+	names := make([]string, 0, len(failures))
+	for name := range failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartProbing probes all tenants on the given interval until ctx is
+// cancelled. Probes are background requests.
+func (r *TenantRegistry) StartProbing(ctx context.Context, interval time.Duration) {
+	r.mu.Lock()
+	r.probeInterval = interval
+	r.mu.Unlock()
+
+	ctx = WithPriority(ctx, PriorityBackground)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				probeCtx, cancel := context.WithTimeout(ctx, interval/2)
+				r.ProbeAll(probeCtx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// recordProbe updates a tenant's health from a probe result. Only state
+// transitions are logged above debug level so an outage does not flood the logs.
+func (r *TenantRegistry) recordProbe(t *Tenant, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.health.LastChecked = now
+
+	if err == nil {
+		if t.health.Status == TenantDegraded {
+			r.logger.Info("Tenant %s recovered after %d failed probes", t.Name, t.health.ConsecutiveFailures)
+			session.Broadcast(mcp.LoggingLevelNotice, "tenants", map[string]interface{}{
+				"event":  "tenantRecovered",
+				"tenant": t.Name,
+			})
+		}
+		t.health.Status = TenantHealthy
+		t.health.ConsecutiveFailures = 0
+		t.health.LastError = ""
+		t.health.DegradedSince = nil
+		return
+	}
+
+	t.health.ConsecutiveFailures++
+	t.health.LastError = err.Error()
+
+	if t.health.Status != TenantDegraded {
+		r.logger.Warn("Tenant %s marked degraded: %v", t.Name, err)
+		t.health.Status = TenantDegraded
+		t.health.DegradedSince = &now
+		session.Broadcast(mcp.LoggingLevelWarning, "tenants", map[string]interface{}{
+			"event":  "tenantDegraded",
+			"tenant": t.Name,
+			"error":  err.Error(),
+		})
+	} else {
+		r.logger.Debug("Tenant %s still degraded (%d consecutive failures): %v", t.Name, t.health.ConsecutiveFailures, err)
+	}
+}
+
+// defaultProbe performs a minimal authenticated read against the tenant
+func defaultProbe(ctx context.Context, t *Tenant) error {
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/search?pageNo=1&pageSize=1", t.Client.GetTenantID())
+	var response interface{}
+	return t.Client.Get(ctx, endpoint, &response)
+}
+
+// Global tenant registry instance
+var (
+	globalRegistry   *TenantRegistry
+	globalRegistryMu sync.RWMutex
+)
+
+// SetTenantRegistry sets the global tenant registry
+func SetTenantRegistry(registry *TenantRegistry) {
+	globalRegistryMu.Lock()
+	defer globalRegistryMu.Unlock()
+	globalRegistry = registry
+}
+
+// GetTenantRegistry returns the global tenant registry, or nil in single-tenant setups
+func GetTenantRegistry() *TenantRegistry {
+	globalRegistryMu.RLock()
+	defer globalRegistryMu.RUnlock()
+	return globalRegistry
+}
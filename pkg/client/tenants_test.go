@@ -0,0 +1,181 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+func TestTenantRegistryDegradesAndRecovers(t *testing.T) {
+	config := &common.Config{
+		OpsRamp: common.OpsRampConfig{TenantURL: "https://primary.example.com", TenantID: "primary"},
+		Tenants: []common.TenantConfig{
+			{Name: "emea", OpsRampConfig: common.OpsRampConfig{TenantURL: "https://emea.example.com", TenantID: "emea"}},
+		},
+	}
+	registry := NewTenantRegistry(config)
+
+	var mu sync.Mutex
+	unreachable := map[string]bool{"emea": true}
+	registry.SetProbe(func(ctx context.Context, tenant *Tenant) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if unreachable[tenant.Name] {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	failures := registry.ProbeAll(context.Background())
+	if len(failures) != 1 || failures["emea"] == nil {
+		t.Fatalf("Expected only emea to fail, got %v", failures)
+	}
+
+	// Calls to the healthy tenant are unaffected
+	if _, err := registry.Tenant(""); err != nil {
+		t.Errorf("Expected default tenant to be available, got %v", err)
+	}
+
+	// Calls to the degraded tenant fail fast with a clear error
+	_, err := registry.Tenant("emea")
+	var unavailable *TenantUnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("Expected TenantUnavailableError, got %v", err)
+	}
+	if unavailable.Tenant != "emea" || unavailable.LastError != "connection refused" {
+		t.Errorf("Unexpected error details: %+v", unavailable)
+	}
+
+	// Further failures keep the tenant degraded and count up
+	registry.ProbeAll(context.Background())
+	if health := registry.Health()["emea"]; health.ConsecutiveFailures != 2 || health.Status != TenantDegraded {
+		t.Errorf("Expected 2 consecutive failures while degraded, got %+v", health)
+	}
+
+	// A successful probe recovers the tenant
+	mu.Lock()
+	unreachable["emea"] = false
+	mu.Unlock()
+	registry.ProbeAll(context.Background())
+
+	if _, err := registry.Tenant("emea"); err != nil {
+		t.Errorf("Expected emea to recover, got %v", err)
+	}
+	if health := registry.Health()["emea"]; health.Status != TenantHealthy || health.DegradedSince != nil {
+		t.Errorf("Expected healthy state after recovery, got %+v", health)
+	}
+}
+
+func TestTenantRegistryAnnouncesServerStatus(t *testing.T) {
+	config := &common.Config{
+		OpsRamp: common.OpsRampConfig{TenantURL: "https://primary.example.com", TenantID: "primary"},
+		Tenants: []common.TenantConfig{
+			{Name: "emea", OpsRampConfig: common.OpsRampConfig{TenantURL: "https://emea.example.com", TenantID: "emea"}},
+		},
+	}
+	registry := NewTenantRegistry(config)
+	var unreachable map[string]bool
+	registry.SetProbe(func(ctx context.Context, tenant *Tenant) error {
+		if unreachable[tenant.Name] {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	registry.ProbeAll(context.Background())
+	if status := session.CurrentStatus(); status.Status != session.StatusReady {
+		t.Fatalf("Expected the server to be ready, got %+v", status)
+	}
+
+	// One tenant down leaves the server ready for the others
+	unreachable = map[string]bool{"emea": true}
+	registry.ProbeAll(context.Background())
+	if status := session.CurrentStatus(); status.Status != session.StatusReady {
+		t.Errorf("Expected one degraded tenant not to degrade the server, got %+v", status)
+	}
+
+	unreachable = map[string]bool{"emea": true, DefaultTenant: true}
+	registry.ProbeAll(context.Background())
+	status := session.CurrentStatus()
+	if status.Status != session.StatusDegraded || status.RetryAfterSeconds != int(DefaultProbeInterval/time.Second) {
+		t.Errorf("Expected the server to be announced degraded, got %+v", status)
+	}
+
+	unreachable = map[string]bool{"emea": true}
+	registry.ProbeAll(context.Background())
+	if status := session.CurrentStatus(); status.Status != session.StatusReady {
+		t.Errorf("Expected readiness once a tenant recovers, got %+v", status)
+	}
+}
+
+func TestTenantRegistryUnknownTenant(t *testing.T) {
+	registry := NewTenantRegistry(&common.Config{})
+
+	if _, err := registry.Tenant("missing"); err == nil {
+		t.Error("Expected error for unknown tenant")
+	}
+	if names := registry.Names(); len(names) != 1 || names[0] != DefaultTenant {
+		t.Errorf("Expected only the default tenant, got %v", names)
+	}
+}
+
+func TestTenantRegistryCheckStartupRetries(t *testing.T) {
+	config := &common.Config{
+		OpsRamp: common.OpsRampConfig{TenantURL: "https://primary.example.com", TenantID: "primary"},
+		Tenants: []common.TenantConfig{
+			{Name: "emea", OpsRampConfig: common.OpsRampConfig{TenantURL: "https://emea.example.com", TenantID: "emea"}},
+		},
+	}
+	registry := NewTenantRegistry(config)
+	if status := registry.StartupStatus(); status.Status != StartupCheckPending || status.StartedAt != nil {
+		t.Fatalf("Expected a pending startup check before it runs, got %+v", status)
+	}
+
+	// emea answers on the third attempt
+	var mu sync.Mutex
+	probes := 0
+	registry.SetProbe(func(ctx context.Context, tenant *Tenant) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if tenant.Name != "emea" {
+			return nil
+		}
+		probes++
+		if probes < 3 {
+			return errors.New("timeout")
+		}
+		return nil
+	})
+
+	failures := registry.CheckStartup(context.Background(), 5, time.Millisecond)
+	if len(failures) != 0 {
+		t.Fatalf("Expected no failures, got %v", failures)
+	}
+	status := registry.StartupStatus()
+	if status.Status != StartupCheckPassed || status.Attempts != 3 || len(status.Unreachable) != 0 || status.CompletedAt == nil {
+		t.Errorf("Expected the check to pass on the third attempt, got %+v", status)
+	}
+
+	// Attempts are bounded and the outcome reflects how many tenants failed
+	registry.SetProbe(func(ctx context.Context, tenant *Tenant) error {
+		if tenant.Name == "emea" {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	registry.CheckStartup(context.Background(), 2, time.Millisecond)
+	status = registry.StartupStatus()
+	if status.Status != StartupCheckDegraded || status.Attempts != 2 || len(status.Unreachable) != 1 || status.Unreachable[0] != "emea" {
+		t.Errorf("Expected a degraded check after 2 attempts, got %+v", status)
+	}
+
+	registry.SetProbe(func(ctx context.Context, tenant *Tenant) error { return errors.New("connection refused") })
+	registry.CheckStartup(context.Background(), 1, time.Millisecond)
+	if status := registry.StartupStatus(); status.Status != StartupCheckFailed || status.Attempts != 1 {
+		t.Errorf("Expected a failed check when no tenant is reachable, got %+v", status)
+	}
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRequestsAreTraced(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/token" {
+			w.Write([]byte(`{"access_token": "token", "expires_in": 3600}`))
+			return
+		}
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: server.URL, AuthURL: server.URL + "/auth/token", TenantID: "t-1",
+		AuthKey: "key", AuthSecret: "secret",
+	}})
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "tools/call resources")
+	c.Get(ctx, "/api/v2/tenants/t-1/resources/r-1", nil)
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected the request and its parent to be traced, got %d spans", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "OpsRamp GET resources.get" || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Expected a child span for the request, got %s", span.Name())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("Expected the 404 to mark the span as failed, got %v", span.Status())
+	}
+	found := false
+	for _, kv := range span.Attributes() {
+		found = found || kv == attribute.Int("http.response.status_code", http.StatusNotFound)
+	}
+	if !found {
+		t.Errorf("Expected the status code attribute, got %v", span.Attributes())
+	}
+	if want := span.SpanContext().TraceID().String(); len(traceparent) < 36 || traceparent[3:35] != want {
+		t.Errorf("Expected the trace to be propagated to OpsRamp, got traceparent %q", traceparent)
+	}
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// UpstreamFailures records the OpsRamp requests of one tool call that failed
+// on OpsRamp's side: unreachable, rate limited or answering with a server
+// error. Requests OpsRamp refused as invalid or unauthorized are not
+// recorded, since retrying them later would fail the same way.
+type UpstreamFailures struct {
+	mu    sync.Mutex
+	count int
+	last  string
+}
+
+// upstreamFailuresKey is the context key of a call's upstream failures
+type upstreamFailuresKey struct{}
+
+// WithUpstreamFailures returns a context whose failed OpsRamp requests are
+// recorded in the returned UpstreamFailures
+func WithUpstreamFailures(ctx context.Context) (context.Context, *UpstreamFailures) {
+	failures := &UpstreamFailures{}
+	return context.WithValue(ctx, upstreamFailuresKey{}, failures), failures
+}
+
+// Count returns the number of failed requests recorded
+func (f *UpstreamFailures) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.count
+}
+
+// Last describes the last failed request recorded
+func (f *UpstreamFailures) Last() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.last
+}
+
+// isUpstreamFailure reports whether a request failed on OpsRamp's side. A
+// status of 0 with an error means the request got no response.
+func isUpstreamFailure(ctx context.Context, statusCode int, err error) bool {
+	switch {
+	case statusCode == 0:
+		return err != nil && ctx.Err() == nil
+	case statusCode == http.StatusTooManyRequests:
+		return true
+	default:
+		return statusCode >= http.StatusInternalServerError
+	}
+}
+
+// recordUpstreamFailure records a failed request in the context's
+// UpstreamFailures, if it has one and the failure was on OpsRamp's side
+func recordUpstreamFailure(ctx context.Context, method, endpoint string, statusCode int, err error) {
+	failures, ok := ctx.Value(upstreamFailuresKey{}).(*UpstreamFailures)
+	if !ok || !isUpstreamFailure(ctx, statusCode, err) {
+		return
+	}
+	failures.mu.Lock()
+	defer failures.mu.Unlock()
+	failures.count++
+	failures.last = fmt.Sprintf("%s %s: %v", method, endpoint, err)
+}
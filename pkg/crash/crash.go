@@ -0,0 +1,246 @@
+// Package crash writes a health snapshot when the server panics or exits on a
+// fatal error, so crashes reported from customer environments can be
+// diagnosed from the bundle the customer sends back.
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+// maxBundles is how many crash bundles are kept; older ones are removed
+const maxBundles = 10
+
+// runtimeCrashFile receives the Go runtime's own report of crashes the
+// reporter cannot recover, such as panics in other goroutines
+const runtimeCrashFile = "runtime-crash.txt"
+
+// Snapshot is the health of the server at the time of a crash. It is written
+// as snapshot.json next to the goroutine dump, log tail and redacted config.
+type Snapshot struct {
+	Time       string            `json:"time"`
+	Reason     string            `json:"reason"`
+	PID        int               `json:"pid"`
+	GoVersion  string            `json:"goVersion"`
+	Build      string            `json:"build,omitempty"`
+	Uptime     string            `json:"uptime"`
+	Goroutines int               `json:"goroutines"`
+	Stack      string            `json:"stack,omitempty"`
+	Sessions   []SessionSummary  `json:"sessions"`
+	InFlight   []session.Call    `json:"inFlight"`
+	Files      map[string]string `json:"files"`
+}
+
+// SessionSummary describes an MCP session without its stored values
+type SessionSummary struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Connected bool      `json:"connected"`
+	Keys      []string  `json:"keys,omitempty"`
+}
+
+// Reporter writes crash bundles to a directory
+type Reporter struct {
+	dir     string
+	config  *common.Config
+	started time.Time
+	now     func() time.Time
+	logger  *common.CustomLogger
+}
+
+// NewReporter creates a reporter writing bundles to dir. The config, which
+// may be nil, is included with its secrets redacted.
+func NewReporter(dir string, config *common.Config) *Reporter {
+	return &Reporter{
+		dir:     dir,
+		config:  config,
+		started: time.Now(),
+		now:     time.Now,
+		logger:  common.GetLogger(),
+	}
+}
+
+// Install writes a bundle whenever the logger's Fatal exits the process, and
+// directs the Go runtime's report of unrecovered crashes to the crash directory
+func (r *Reporter) Install() error {
+	if err := os.MkdirAll(r.dir, 0750); err != nil {
+		return fmt.Errorf("failed to create crash directory: %w", err)
+	}
+	common.SetFatalHook(func(message string) {
+		r.Write("fatal: "+message, nil)
+	})
+
+	// #nosec G304 - the crash directory comes from the server configuration
+	file, err := os.OpenFile(filepath.Join(r.dir, runtimeCrashFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open runtime crash output: %w", err)
+	}
+	defer file.Close()
+	if err := debug.SetCrashOutput(file, debug.CrashOptions{}); err != nil {
+		return fmt.Errorf("failed to set runtime crash output: %w", err)
+	}
+	return nil
+}
+
+// Recover writes a bundle for a panic in progress and panics again. Defer it
+// at the top of a goroutine; it does nothing when there is no panic.
+func (r *Reporter) Recover() {
+	if value := recover(); value != nil {
+		r.Write(fmt.Sprintf("panic: %v", value), debug.Stack())
+		panic(value)
+	}
+}
+
+// Recover is Reporter.Recover for the default reporter. Without one, the
+// panic continues unreported.
+func Recover() {
+	if value := recover(); value != nil {
+		if r := Default(); r != nil {
+			r.Write(fmt.Sprintf("panic: %v", value), debug.Stack())
+		}
+		panic(value)
+	}
+}
+
+// Write writes a crash bundle and returns its directory, or "" when it
+// could not be written
+func (r *Reporter) Write(reason string, stack []byte) string {
+	bundle, err := r.write(reason, stack)
+	if err != nil {
+		r.logger.Error("Failed to write crash snapshot: %v", err)
+		return ""
+	}
+	r.logger.Error("Wrote crash snapshot to %s", bundle)
+	return bundle
+}
+
+// write writes the bundle files, continuing past files that fail so the
+// bundle holds as much as could be collected
+func (r *Reporter) write(reason string, stack []byte) (string, error) {
+	now := r.now().UTC()
+	bundle := filepath.Join(r.dir, "crash-"+now.Format("20060102T150405Z"))
+	if err := os.MkdirAll(bundle, 0750); err != nil {
+		return "", err
+	}
+
+	snapshot := Snapshot{
+		Time:       now.Format(time.RFC3339),
+		Reason:     reason,
+		PID:        os.Getpid(),
+		GoVersion:  runtime.Version(),
+		Uptime:     now.Sub(r.started).Round(time.Second).String(),
+		Goroutines: runtime.NumGoroutine(),
+		Stack:      string(stack),
+		Sessions:   sessionSummaries(),
+		InFlight:   session.InFlightCalls(),
+		Files:      make(map[string]string),
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		snapshot.Build = info.Main.Version
+	}
+
+	files := map[string][]byte{
+		"goroutines.txt": goroutineDump(),
+		"log-tail.txt":   []byte(strings.Join(common.RecentLogLines(), "\n") + "\n"),
+	}
+	if r.config != nil {
+		if config, err := r.config.RedactedYAML(); err == nil {
+			files["config.yaml"] = []byte(config)
+		} else {
+			snapshot.Files["config.yaml"] = "failed: " + err.Error()
+		}
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(bundle, name), data, 0600); err != nil {
+			snapshot.Files[name] = "failed: " + err.Error()
+			continue
+		}
+		snapshot.Files[name] = "written"
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return bundle, err
+	}
+	if err := os.WriteFile(filepath.Join(bundle, "snapshot.json"), data, 0600); err != nil {
+		return bundle, err
+	}
+
+	r.prune()
+	return bundle, nil
+}
+
+// prune removes the oldest bundles beyond maxBundles
+func (r *Reporter) prune() {
+	bundles, err := filepath.Glob(filepath.Join(r.dir, "crash-*"))
+	if err != nil || len(bundles) <= maxBundles {
+		return
+	}
+	// Bundle names sort by time
+	sort.Strings(bundles)
+	for _, bundle := range bundles[:len(bundles)-maxBundles] {
+		os.RemoveAll(bundle)
+	}
+}
+
+// goroutineDump returns the stacks of every goroutine, growing the buffer
+// until the dump fits
+func goroutineDump() []byte {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= 64<<20 {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// sessionSummaries describes the known MCP sessions, most recently seen first
+func sessionSummaries() []SessionSummary {
+	sessions := session.All()
+	summaries := make([]SessionSummary, 0, len(sessions))
+	for _, s := range sessions {
+		summaries = append(summaries, SessionSummary{
+			ID:        s.ID,
+			CreatedAt: s.CreatedAt,
+			LastSeen:  s.LastSeen(),
+			Connected: session.IsConnected(s.ID),
+			Keys:      s.Keys(),
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].LastSeen.After(summaries[j].LastSeen) })
+	return summaries
+}
+
+// Default reporter
+var (
+	defaultMu       sync.RWMutex
+	defaultReporter *Reporter
+)
+
+// SetDefault sets the process-wide crash reporter
+func SetDefault(r *Reporter) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultReporter = r
+}
+
+// Default returns the process-wide crash reporter, or nil if none is installed
+func Default() *Reporter {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultReporter
+}
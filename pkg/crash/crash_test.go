@@ -0,0 +1,96 @@
+package crash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+func TestReporterWritesSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	config := &common.Config{
+		OpsRamp: common.OpsRampConfig{TenantID: "client_1", AuthKey: "key-123", AuthSecret: "secret-456"},
+		Tracing: common.TracingConfig{Headers: map[string]string{"Authorization": "Bearer otlp-789", "x-honeycomb-team": "team-012"}},
+	}
+	reporter := NewReporter(dir, config)
+	reporter.now = func() time.Time { return time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC) }
+
+	done := session.BeginCall(context.Background(), "resources", "search")
+	defer done()
+	common.GetLogger().Info("Searching resources before the crash")
+
+	bundle := reporter.Write("panic: boom", []byte("goroutine 1 [running]"))
+	if bundle != filepath.Join(dir, "crash-20261016T093000Z") {
+		t.Fatalf("Unexpected bundle %q", bundle)
+	}
+
+	var snapshot Snapshot
+	data, err := os.ReadFile(filepath.Join(bundle, "snapshot.json"))
+	if err != nil {
+		t.Fatalf("Failed to read snapshot: %v", err)
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("Failed to decode snapshot: %v", err)
+	}
+	if snapshot.Reason != "panic: boom" || snapshot.Stack == "" || snapshot.Goroutines == 0 {
+		t.Errorf("Unexpected snapshot %+v", snapshot)
+	}
+	if len(snapshot.InFlight) != 1 || snapshot.InFlight[0].Tool != "resources" || snapshot.InFlight[0].Action != "search" {
+		t.Errorf("Expected the in-flight search, got %+v", snapshot.InFlight)
+	}
+	for _, name := range []string{"goroutines.txt", "log-tail.txt", "config.yaml"} {
+		if snapshot.Files[name] != "written" {
+			t.Errorf("Expected %s to be written, got %q", name, snapshot.Files[name])
+		}
+	}
+
+	configYAML, _ := os.ReadFile(filepath.Join(bundle, "config.yaml"))
+	if strings.Contains(string(configYAML), "key-123") || strings.Contains(string(configYAML), "secret-456") ||
+		strings.Contains(string(configYAML), "otlp-789") || strings.Contains(string(configYAML), "team-012") ||
+		!strings.Contains(string(configYAML), "client_1") || !strings.Contains(string(configYAML), "x-honeycomb-team") {
+		t.Errorf("Expected secrets to be redacted:\n%s", configYAML)
+	}
+	logTail, _ := os.ReadFile(filepath.Join(bundle, "log-tail.txt"))
+	if !strings.Contains(string(logTail), "Searching resources before the crash") {
+		t.Errorf("Expected the log tail to include recent lines:\n%s", logTail)
+	}
+}
+
+func TestReporterPrunesOldBundles(t *testing.T) {
+	dir := t.TempDir()
+	reporter := NewReporter(dir, nil)
+	start := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < maxBundles+2; i++ {
+		reporter.now = func() time.Time { return start.Add(time.Duration(i) * time.Minute) }
+		reporter.Write(fmt.Sprintf("panic: %d", i), nil)
+	}
+
+	bundles, _ := filepath.Glob(filepath.Join(dir, "crash-*"))
+	if len(bundles) != maxBundles || filepath.Base(bundles[0]) != "crash-20261016T000200Z" {
+		t.Errorf("Expected the %d newest bundles, got %v", maxBundles, bundles)
+	}
+}
+
+func TestRecoverRepanics(t *testing.T) {
+	reporter := NewReporter(t.TempDir(), nil)
+	defer func() {
+		if value := recover(); value != "boom" {
+			t.Errorf("Expected the panic to continue, got %v", value)
+		}
+		if bundles, _ := filepath.Glob(filepath.Join(reporter.dir, "crash-*")); len(bundles) != 1 {
+			t.Errorf("Expected one bundle, got %v", bundles)
+		}
+	}()
+	func() {
+		defer reporter.Recover()
+		panic("boom")
+	}()
+}
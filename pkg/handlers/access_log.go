@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccessLogEntry is one structured access log line
+type AccessLogEntry struct {
+	Time       string  `json:"time"`
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	Status     int     `json:"status"`
+	Bytes      int64   `json:"bytes"`
+	DurationMs float64 `json:"durationMs"`
+	Session    string  `json:"session,omitempty"`
+	APIKeyID   string  `json:"apiKeyId,omitempty"`
+	Remote     string  `json:"remote,omitempty"`
+	SampleRate float64 `json:"sampleRate"`
+	// Labels are the server's tenant and environment telemetry labels
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// AccessLogger writes one line per HTTP request to a dedicated access log.
+// Sampling rates are keyed by path prefix; the longest matching prefix wins and
+// paths without a rule are always logged. Error responses bypass sampling so
+// failures are never dropped from the log.
+type AccessLogger struct {
+	mu       sync.Mutex
+	out      io.Writer
+	closer   io.Closer
+	sampling map[string]float64
+	sample   func() float64
+	labels   map[string]string
+}
+
+// NewAccessLogger creates an access logger writing to out
+func NewAccessLogger(out io.Writer, sampling map[string]float64) *AccessLogger {
+	return &AccessLogger{
+		out:      out,
+		sampling: sampling,
+		sample:   rand.Float64,
+	}
+}
+
+// OpenAccessLog creates an access logger appending to the file at path
+func OpenAccessLog(path string, sampling map[string]float64) (*AccessLogger, error) {
+	cleanPath := filepath.Clean(path)
+	if strings.Contains(cleanPath, "..") {
+		return nil, fmt.Errorf("invalid access log path: %s", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(cleanPath), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create access log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(cleanPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open access log: %w", err)
+	}
+
+	logger := NewAccessLogger(file, sampling)
+	logger.closer = file
+	return logger, nil
+}
+
+// SetLabels sets the telemetry labels attached to every entry
+func (a *AccessLogger) SetLabels(labels map[string]string) {
+	a.labels = labels
+}
+
+// Close closes the underlying access log file, if any
+func (a *AccessLogger) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// Middleware wraps next so that every request is considered for the access log
+func (a *AccessLogger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		rate := a.sampleRate(r.URL.Path)
+		if recorder.status < http.StatusBadRequest && (rate <= 0 || a.sample() >= rate) {
+			return
+		}
+		if recorder.status >= http.StatusBadRequest {
+			rate = 1
+		}
+
+		a.write(AccessLogEntry{
+			Time:       start.UTC().Format(time.RFC3339Nano),
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Status:     recorder.status,
+			Bytes:      recorder.bytes,
+			DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+			Session:    sessionIDFromRequest(r),
+			APIKeyID:   APIKeyIDFromRequest(r),
+			Remote:     r.RemoteAddr,
+			SampleRate: rate,
+			Labels:     a.labels,
+		})
+	})
+}
+
+// sampleRate returns the configured rate for the longest matching path prefix
+func (a *AccessLogger) sampleRate(path string) float64 {
+	rate, matched := 1.0, -1
+	for prefix, prefixRate := range a.sampling {
+		if strings.HasPrefix(path, prefix) && len(prefix) > matched {
+			rate, matched = prefixRate, len(prefix)
+		}
+	}
+	return rate
+}
+
+func (a *AccessLogger) write(entry AccessLogEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, _ = a.out.Write(append(line, '\n'))
+}
+
+// sessionIDFromRequest returns the MCP session a request belongs to, if any
+func sessionIDFromRequest(r *http.Request) string {
+	if id := r.URL.Query().Get("sessionId"); id != "" {
+		return id
+	}
+	return r.Header.Get("Mcp-Session-Id")
+}
+
+// APIKeyIDFromRequest returns a stable, non-secret identifier for the credential
+// presented with a request. The credential itself is never logged.
+func APIKeyIDFromRequest(r *http.Request) string {
+	credential := r.Header.Get("X-API-Key")
+	if credential == "" {
+		credential = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	if credential == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(credential))
+	return "key-" + hex.EncodeToString(sum[:])[:12]
+}
+
+// statusRecorder captures the status code and body size of a response while
+// preserving the streaming and hijacking capabilities SSE depends on
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if !s.wroteHeader {
+		s.status = status
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
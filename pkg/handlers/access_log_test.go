@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAccessLogMiddleware(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewAccessLogger(&out, nil)
+	logger.SetLabels(map[string]string{"environment": "production"})
+
+	handler := logger.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp-message?sessionId=abc", nil)
+	req.Header.Set("X-API-Key", "super-secret")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry AccessLogEntry
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON line, got %q: %v", out.String(), err)
+	}
+	if entry.Method != http.MethodPost || entry.Path != "/mcp-message" || entry.Status != http.StatusAccepted || entry.Bytes != 5 {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if entry.Labels["environment"] != "production" {
+		t.Errorf("Expected the environment label, got %v", entry.Labels)
+	}
+	if entry.Session != "abc" {
+		t.Errorf("Expected session abc, got %q", entry.Session)
+	}
+	if entry.APIKeyID == "" || strings.Contains(out.String(), "super-secret") {
+		t.Errorf("Expected a fingerprint of the API key and never the key itself, got %q", out.String())
+	}
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewAccessLogger(&out, map[string]float64{"/": 1, "/sse": 0})
+	logger.sample = func() float64 { return 0.5 }
+
+	status := http.StatusOK
+	handler := logger.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/sse", nil))
+	if out.Len() != 0 {
+		t.Fatalf("Expected /sse to be sampled out, got %q", out.String())
+	}
+
+	status = http.StatusInternalServerError
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/sse", nil))
+	if !strings.Contains(out.String(), `"status":500`) {
+		t.Errorf("Expected errors to bypass sampling, got %q", out.String())
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// maxAlertWebhookBytes bounds the size of an alert webhook body
+const maxAlertWebhookBytes = 1 << 20
+
+// AlertWebhookHandler receives OpsRamp alert webhooks and forwards each alert
+// to the sessions watching the alerted resource, instead of broadcasting it
+type AlertWebhookHandler struct {
+	secret       string
+	linkTemplate string
+	tenantURL    string
+	logger       *common.CustomLogger
+}
+
+// AlertNotification is the summary of an alert sent to a watching session
+type AlertNotification struct {
+	Event        string `json:"event"`
+	AlertID      string `json:"alertId"`
+	Subject      string `json:"subject"`
+	State        string `json:"state"`
+	Priority     string `json:"priority,omitempty"`
+	ResourceID   string `json:"resourceId"`
+	ResourceName string `json:"resourceName,omitempty"`
+	Link         string `json:"link"`
+}
+
+// NewAlertWebhookHandler creates the alert webhook handler, or returns nil
+// when no webhook secret is configured
+func NewAlertWebhookHandler(config *common.Config, logger *common.CustomLogger) *AlertWebhookHandler {
+	if config.Webhooks.Secret == "" {
+		return nil
+	}
+	return &AlertWebhookHandler{
+		secret:       config.Webhooks.Secret,
+		linkTemplate: config.Webhooks.AlertLinkTemplate,
+		tenantURL:    strings.TrimSuffix(config.OpsRamp.TenantURL, "/"),
+		logger:       logger,
+	}
+}
+
+// ServeHTTP handles POST /webhooks/alerts
+func (h *AlertWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Webhook-Secret")), []byte(h.secret)) != 1 {
+		h.logger.Warn("Rejected alert webhook from %s: invalid secret", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var alert types.AlertDetails
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAlertWebhookBytes)).Decode(&alert); err != nil {
+		h.logger.Warn("Rejected alert webhook from %s: %v", r.RemoteAddr, err)
+		http.Error(w, "Invalid alert payload", http.StatusBadRequest)
+		return
+	}
+	if alert.ID == "" || alert.Resource == nil || alert.Resource.ID == "" {
+		http.Error(w, "Alert id and resource.id are required", http.StatusBadRequest)
+		return
+	}
+
+	notification := AlertNotification{
+		Event:        "alert",
+		AlertID:      alert.ID,
+		Subject:      alert.Subject,
+		State:        alert.CurrentState,
+		Priority:     alert.Priority,
+		ResourceID:   alert.Resource.ID,
+		ResourceName: alert.Resource.Name,
+		Link:         h.alertLink(alert.ID),
+	}
+
+	watchers := session.Watchers("resource", alert.Resource.ID)
+	delivered := 0
+	for _, sessionID := range watchers {
+		if session.Notify(sessionID, alertLogLevel(alert.CurrentState), "alerts", notification) {
+			delivered++
+		}
+	}
+	h.logger.Info("Alert %s on resource %s sent to %d of %d watching session(s)", alert.ID, alert.Resource.ID, delivered, len(watchers))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{
+		"watchers":  len(watchers),
+		"delivered": delivered,
+	})
+}
+
+// alertLink returns the deep link to an alert in the OpsRamp portal
+func (h *AlertWebhookHandler) alertLink(id string) string {
+	return strings.NewReplacer("{tenant_url}", h.tenantURL, "{id}", id).Replace(h.linkTemplate)
+}
+
+// alertLogLevel maps an alert state to the level of its notification
+func alertLogLevel(state string) mcp.LoggingLevel {
+	switch strings.ToUpper(state) {
+	case "CRITICAL":
+		return mcp.LoggingLevelCritical
+	case "WARNING":
+		return mcp.LoggingLevelWarning
+	case "OK":
+		return mcp.LoggingLevelNotice
+	default:
+		return mcp.LoggingLevelInfo
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+// watchingClientSession is a client session that receives log notifications
+type watchingClientSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *watchingClientSession) Initialize()                        {}
+func (s *watchingClientSession) Initialized() bool                  { return true }
+func (s *watchingClientSession) SessionID() string                  { return s.id }
+func (s *watchingClientSession) SetLogLevel(level mcp.LoggingLevel) {}
+func (s *watchingClientSession) GetLogLevel() mcp.LoggingLevel      { return mcp.LoggingLevelInfo }
+func (s *watchingClientSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+const testWebhookSecret = "0123456789abcdef"
+
+func newTestAlertWebhook() *AlertWebhookHandler {
+	config := &common.Config{
+		OpsRamp:  common.OpsRampConfig{TenantURL: "https://acme.opsramp.com/"},
+		Webhooks: common.WebhooksConfig{Secret: testWebhookSecret, AlertLinkTemplate: common.DefaultAlertLinkTemplate},
+	}
+	return NewAlertWebhookHandler(config, common.GetLogger())
+}
+
+func postAlert(handler http.Handler, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/alerts", strings.NewReader(body))
+	req.Header.Set("X-Webhook-Secret", secret)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAlertWebhookNotifiesWatchers(t *testing.T) {
+	watcher := &watchingClientSession{id: "webhook-watcher", notifications: make(chan mcp.JSONRPCNotification, 1)}
+	bystander := &watchingClientSession{id: "webhook-bystander", notifications: make(chan mcp.JSONRPCNotification, 1)}
+	for _, s := range []*watchingClientSession{watcher, bystander} {
+		session.TrackClient(s)
+		defer session.Remove(s.id)
+	}
+	if err := session.Watch(watcher.id, "resource", "res-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := postAlert(newTestAlertWebhook(), testWebhookSecret,
+		`{"id": "a-42", "subject": "CPU high", "currentState": "CRITICAL", "resource": {"id": "res-1", "name": "web-01"}}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"delivered":1`) {
+		t.Fatalf("Unexpected response %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(bystander.notifications) != 0 {
+		t.Error("Expected sessions not watching the resource to get nothing")
+	}
+
+	notification := <-watcher.notifications
+	params := notification.Params.AdditionalFields
+	alert, _ := params["data"].(AlertNotification)
+	if params["level"] != mcp.LoggingLevelCritical || params["logger"] != "alerts" || alert.AlertID != "a-42" {
+		t.Errorf("Unexpected notification: %+v", params)
+	}
+	if alert.Link != "https://acme.opsramp.com/portal/alert-browser/alert-details/a-42" {
+		t.Errorf("Unexpected link %q", alert.Link)
+	}
+}
+
+func TestAlertWebhookRejectsInvalidRequests(t *testing.T) {
+	handler := newTestAlertWebhook()
+	valid := `{"id": "a-1", "resource": {"id": "res-1"}}`
+
+	if rec := postAlert(handler, "wrong-secret", valid); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong secret, got %d", rec.Code)
+	}
+	if rec := postAlert(handler, testWebhookSecret, `{"id": "a-1"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an alert without a resource, got %d", rec.Code)
+	}
+	if rec := postAlert(handler, testWebhookSecret, `not json`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid JSON, got %d", rec.Code)
+	}
+
+	rec := postAlert(handler, testWebhookSecret, valid)
+	var counts map[string]int
+	if err := json.Unmarshal(rec.Body.Bytes(), &counts); err != nil || counts["watchers"] != 0 {
+		t.Errorf("Expected an unwatched alert to be accepted with no watchers, got %s", rec.Body.String())
+	}
+
+	if NewAlertWebhookHandler(&common.Config{}, common.GetLogger()) != nil {
+		t.Error("Expected no handler without a secret")
+	}
+}
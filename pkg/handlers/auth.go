@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+const (
+	// introspectionTimeout bounds a call to the OAuth2 introspection endpoint
+	introspectionTimeout = 10 * time.Second
+	// maxCachedTokens bounds the introspection cache; it is cleared when full
+	maxCachedTokens = 10000
+)
+
+// defaultAuthEndpoints are the paths served without authentication unless
+// configured otherwise: probes, and the alert webhook, which checks its own
+// secret
+var defaultAuthEndpoints = map[string]string{
+	"/health":          common.AuthNone,
+	"/readiness":       common.AuthNone,
+	"/webhooks/alerts": common.AuthNone,
+}
+
+// errAuthUnavailable marks a credential that could not be checked, as
+// opposed to one that was refused
+var errAuthUnavailable = errors.New("authentication unavailable")
+
+// namedSecret is a static credential, kept only as a digest so comparisons
+// take the same time whatever the secret's length
+type namedSecret struct {
+	name   string
+	digest [sha256.Size]byte
+}
+
+// Authenticator requires clients of the HTTP endpoints to authenticate, as
+// configured in the auth section of config.yaml. Rejected requests are
+// answered 401, or 503 when the introspection endpoint cannot be reached,
+// and logged for audit with the client address and a non-secret identifier
+// of the credential presented.
+type Authenticator struct {
+	apiKeys       []namedSecret
+	bearerTokens  []namedSecret
+	introspection *tokenIntrospector
+	endpoints     map[string]string
+	logger        *common.CustomLogger
+}
+
+// NewAuthenticator creates the authentication middleware, or returns nil
+// when no credentials or introspection are configured
+func NewAuthenticator(config common.AuthConfig, logger *common.CustomLogger) *Authenticator {
+	if !config.Enabled() {
+		return nil
+	}
+
+	a := &Authenticator{
+		endpoints: make(map[string]string, len(defaultAuthEndpoints)+len(config.Endpoints)),
+		logger:    logger,
+	}
+	for _, credential := range config.APIKeys {
+		a.apiKeys = append(a.apiKeys, namedSecret{name: credential.Name, digest: sha256.Sum256([]byte(credential.Secret))})
+	}
+	for _, credential := range config.BearerTokens {
+		a.bearerTokens = append(a.bearerTokens, namedSecret{name: credential.Name, digest: sha256.Sum256([]byte(credential.Secret))})
+	}
+	if config.Introspection.URL != "" {
+		a.introspection = &tokenIntrospector{
+			config: config.Introspection,
+			client: &http.Client{Timeout: introspectionTimeout},
+			now:    time.Now,
+			cache:  make(map[[sha256.Size]byte]introspectedToken),
+		}
+	}
+	for endpoint, mode := range defaultAuthEndpoints {
+		a.endpoints[endpoint] = mode
+	}
+	for endpoint, mode := range config.Endpoints {
+		a.endpoints[path.Clean(endpoint)] = mode
+	}
+	return a
+}
+
+// Middleware wraps next so that requests to endpoints requiring
+// authentication are served only with a valid credential
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.mode(r.URL.Path) == common.AuthNone {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, err := a.authenticate(r)
+		if err != nil {
+			a.reject(w, r, err)
+			return
+		}
+		a.logger.Debug("Authenticated %s %s as %s", r.Method, r.URL.Path, principal)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// mode returns the authentication mode of the longest configured path that
+// is urlPath or one of its parents
+func (a *Authenticator) mode(urlPath string) string {
+	for p := path.Clean("/" + urlPath); ; p = path.Dir(p) {
+		if mode, ok := a.endpoints[p]; ok {
+			return mode
+		}
+		if p == "/" {
+			return common.AuthRequired
+		}
+	}
+}
+
+// authenticate returns the name of the caller presenting the request's
+// credential
+func (a *Authenticator) authenticate(r *http.Request) (string, error) {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		if name, ok := matchSecret(a.apiKeys, key); ok {
+			return name, nil
+		}
+		return "", errors.New("invalid API key")
+	}
+
+	authorization := r.Header.Get("Authorization")
+	if authorization == "" {
+		return "", errors.New("missing credentials")
+	}
+	scheme, token, _ := strings.Cut(authorization, " ")
+	if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", errors.New("unsupported authorization scheme")
+	}
+	token = strings.TrimSpace(token)
+	if name, ok := matchSecret(a.bearerTokens, token); ok {
+		return name, nil
+	}
+	if a.introspection != nil {
+		return a.introspection.check(r.Context(), token)
+	}
+	return "", errors.New("invalid bearer token")
+}
+
+// reject answers a request that failed authentication and logs it
+func (a *Authenticator) reject(w http.ResponseWriter, r *http.Request, err error) {
+	credential := APIKeyIDFromRequest(r)
+	if credential == "" {
+		credential = "none"
+	}
+	a.logger.Warn("Rejected %s %s from %s: %v (credential %s)", r.Method, r.URL.Path, r.RemoteAddr, err, credential)
+
+	status, message := http.StatusUnauthorized, "Unauthorized"
+	if errors.Is(err, errAuthUnavailable) {
+		status, message = http.StatusServiceUnavailable, "Authentication unavailable"
+	} else {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="or-mcp"`)
+	}
+	http.Error(w, message, status)
+}
+
+// matchSecret returns the name of the credential whose secret is given,
+// comparing against every credential
+func matchSecret(secrets []namedSecret, given string) (string, bool) {
+	digest := sha256.Sum256([]byte(given))
+	name, found := "", false
+	for _, secret := range secrets {
+		if subtle.ConstantTimeCompare(digest[:], secret.digest[:]) == 1 && !found {
+			name, found = secret.name, true
+		}
+	}
+	return name, found
+}
+
+// introspectedToken is a cached active token
+type introspectedToken struct {
+	principal string
+	expires   time.Time
+}
+
+// tokenIntrospector checks bearer tokens with an OAuth2 introspection
+// endpoint (RFC 7662) and caches the active ones
+type tokenIntrospector struct {
+	config common.IntrospectionConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]introspectedToken
+}
+
+// introspectionResponse holds the introspection response fields used here
+type introspectionResponse struct {
+	Active   bool   `json:"active"`
+	Scope    string `json:"scope"`
+	ClientID string `json:"client_id"`
+	Username string `json:"username"`
+	Subject  string `json:"sub"`
+	Exp      int64  `json:"exp"`
+}
+
+// check returns the caller an active token belongs to
+func (t *tokenIntrospector) check(ctx context.Context, token string) (string, error) {
+	key := sha256.Sum256([]byte(token))
+	now := t.now()
+
+	t.mu.Lock()
+	cached, ok := t.cache[key]
+	if ok && now.After(cached.expires) {
+		delete(t.cache, key)
+		ok = false
+	}
+	t.mu.Unlock()
+	if ok {
+		return cached.principal, nil
+	}
+
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errAuthUnavailable, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(t.config.ClientID), url.QueryEscape(t.config.ClientSecret))
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: introspection failed: %v", errAuthUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: introspection returned status %d", errAuthUnavailable, resp.StatusCode)
+	}
+	var result introspectionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("%w: invalid introspection response: %v", errAuthUnavailable, err)
+	}
+
+	if !result.Active {
+		return "", errors.New("inactive bearer token")
+	}
+	if scope := t.config.RequiredScope; scope != "" && !containsField(result.Scope, scope) {
+		return "", fmt.Errorf("bearer token lacks scope %s", scope)
+	}
+
+	principal := result.Subject
+	for _, candidate := range []string{result.ClientID, result.Username, "oauth"} {
+		if principal == "" {
+			principal = candidate
+		}
+	}
+	expires := now.Add(time.Duration(t.config.CacheSeconds) * time.Second)
+	if result.Exp > 0 {
+		if exp := time.Unix(result.Exp, 0); exp.Before(expires) {
+			expires = exp
+		}
+	}
+
+	t.mu.Lock()
+	if len(t.cache) >= maxCachedTokens {
+		t.cache = make(map[[sha256.Size]byte]introspectedToken)
+	}
+	t.cache[key] = introspectedToken{principal: principal, expires: expires}
+	t.mu.Unlock()
+	return principal, nil
+}
+
+// containsField reports whether the space-separated list holds field
+func containsField(list, field string) bool {
+	for _, candidate := range strings.Fields(list) {
+		if candidate == field {
+			return true
+		}
+	}
+	return false
+}
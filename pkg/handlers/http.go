@@ -3,12 +3,25 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/locks"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
 )
 
+func init() {
+	common.NewGaugeFunc("or_mcp_sessions", "MCP client sessions known to the server.", func() float64 {
+		return float64(len(session.All()))
+	})
+	common.NewGaugeFunc("or_mcp_tool_calls_in_flight", "Tool calls currently running.", func() float64 {
+		return float64(len(session.InFlightCalls()))
+	})
+}
+
 // HTTPHandlers contains all HTTP endpoint handlers
 type HTTPHandlers struct {
 	mcpServer       *server.MCPServer
@@ -16,6 +29,9 @@ type HTTPHandlers struct {
 	logger          *common.CustomLogger
 	startTime       time.Time
 	registeredTools []string
+	strictProtocol  bool
+	// streamableHTTP serves the MCP Streamable HTTP transport on /mcp
+	streamableHTTP http.Handler
 }
 
 // NewHTTPHandlers creates a new HTTP handlers instance
@@ -26,9 +42,16 @@ func NewHTTPHandlers(mcpServer *server.MCPServer, sseServer *server.SSEServer, l
 		logger:          logger,
 		startTime:       startTime,
 		registeredTools: registeredTools,
+		strictProtocol:  common.StrictProtocol(),
 	}
 }
 
+// SetStreamableHTTP routes Streamable HTTP transport requests on /mcp to
+// handler; without it /mcp only answers sessionless JSON requests
+func (h *HTTPHandlers) SetStreamableHTTP(handler http.Handler) {
+	h.streamableHTTP = handler
+}
+
 // HealthHandler provides a simple health check endpoint
 func (h *HTTPHandlers) HealthHandler(w http.ResponseWriter, r *http.Request) {
 	uptime := time.Since(h.startTime).String()
@@ -73,6 +96,24 @@ func (h *HTTPHandlers) ReadinessHandler(w http.ResponseWriter, r *http.Request)
 		response["checks"].(map[string]interface{})["server"] = "not initialized"
 	}
 
+	// A server announced to be shutting down takes no new clients; a degraded
+	// one still serves the tenants that recover
+	status := session.CurrentStatus()
+	response["status"] = status
+	if status.Status == session.StatusShuttingDown {
+		response["ready"] = false
+		response["checks"].(map[string]interface{})["server"] = "shutting down"
+	}
+
+	// Report per-tenant health and the startup health check, which runs in the
+	// background; neither makes the server unready
+	if registry := client.GetTenantRegistry(); registry != nil {
+		startup := registry.StartupStatus()
+		response["checks"].(map[string]interface{})["opsramp"] = startup.Status
+		response["startupCheck"] = startup
+		response["tenants"] = registry.Health()
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	if response["ready"].(bool) {
 		w.WriteHeader(http.StatusOK)
@@ -89,6 +130,16 @@ func (h *HTTPHandlers) DebugHandler(w http.ResponseWriter, r *http.Request) {
 		"timestamp": time.Now().Format(time.RFC3339),
 		"uptime":    time.Since(h.startTime).String(),
 		"tools":     h.registeredTools,
+		// Lets load tests measure OpsRamp call amplification
+		"opsrampRequests": client.OutboundRequestCount(),
+		// Requests retried because OpsRamp rejected a token before it expired
+		"opsrampTokenRejections": client.TokenRejectionCount(),
+		// Switches to standby credentials because the ones in use were rejected
+		"opsrampCredentialFailovers": common.CredentialFailoverCount(),
+		// OpsRamp requests waiting for a slot in their priority's pool
+		"opsrampQueuedRequests": client.QueuedRequestCounts(),
+		// Advisory locks held by bulk mutations, and how many callers wait for them
+		"locks": locks.Default().Status(),
 		"server": map[string]interface{}{
 			"name":    "HPE OpsRamp MCP",
 			"version": "1.0.0",
@@ -103,8 +154,10 @@ func (h *HTTPHandlers) DebugHandler(w http.ResponseWriter, r *http.Request) {
 			"exists": false, // Assume false until proven otherwise
 		}
 
-		// Accept any session ID in debug mode for testing
-		w.Header().Set("X-Accept-Any-Session", "true")
+		// Accept any session ID in debug mode for testing, unless strict protocol is enabled
+		if !h.strictProtocol {
+			w.Header().Set("X-Accept-Any-Session", "true")
+		}
 		h.logger.Info("Debug endpoint accessed with session ID: %s", sessionID)
 	}
 
@@ -112,8 +165,37 @@ func (h *HTTPHandlers) DebugHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(debugInfo)
 }
 
-// MCPHandler provides direct access to the MCP server for simple JSON requests
+// MetricsHandler serves the server metrics in the Prometheus text format
+func (h *HTTPHandlers) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	common.WriteMetrics(w)
+}
+
+// wantsStreamableHTTP reports whether a request to /mcp comes from a
+// Streamable HTTP client. Those accept an event stream in reply to a POST,
+// name their session in the Mcp-Session-Id header, open the server stream
+// with GET and end the session with DELETE. Plain JSON POSTs keep the
+// sessionless behavior.
+func wantsStreamableHTTP(r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodDelete {
+		return true
+	}
+	return r.Header.Get("Mcp-Session-Id") != "" || strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// MCPHandler provides direct access to the MCP server for simple JSON
+// requests, and serves Streamable HTTP clients when that transport is set
 func (h *HTTPHandlers) MCPHandler(w http.ResponseWriter, r *http.Request) {
+	if h.streamableHTTP != nil && wantsStreamableHTTP(r) {
+		h.logger.Debug("Streamable HTTP %s request from %s", r.Method, r.RemoteAddr)
+		h.streamableHTTP.ServeHTTP(w, r)
+		return
+	}
+
 	if r.Method != http.MethodPost {
 		h.logger.Warn("Received non-POST request to /mcp endpoint: %s", r.Method)
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestMCPHandlerRoutesStreamableHTTP(t *testing.T) {
+	var streamed []string
+	h := NewHTTPHandlers(server.NewMCPServer("test", "1.0.0"), nil, common.GetLogger(), time.Now(), nil)
+	h.SetStreamableHTTP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		streamed = append(streamed, r.Method)
+		w.Header().Set("X-Transport", "streamable")
+	}))
+
+	ping := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+	for name, test := range map[string]struct {
+		method   string
+		headers  map[string]string
+		streamed bool
+	}{
+		"stream accepted":  {http.MethodPost, map[string]string{"Accept": "application/json, text/event-stream"}, true},
+		"session header":   {http.MethodPost, map[string]string{"Mcp-Session-Id": "s-1"}, true},
+		"server stream":    {http.MethodGet, nil, true},
+		"session end":      {http.MethodDelete, map[string]string{"Mcp-Session-Id": "s-1"}, true},
+		"plain JSON":       {http.MethodPost, map[string]string{"Accept": "application/json"}, false},
+		"no accept header": {http.MethodPost, nil, false},
+	} {
+		streamed = nil
+		req := httptest.NewRequest(test.method, "/mcp", strings.NewReader(ping))
+		for key, value := range test.headers {
+			req.Header.Set(key, value)
+		}
+		recorder := httptest.NewRecorder()
+		h.MCPHandler(recorder, req)
+
+		if got := recorder.Header().Get("X-Transport") == "streamable"; got != test.streamed || len(streamed) > 1 {
+			t.Errorf("%s: expected streamable %v, got %v (%v)", name, test.streamed, got, streamed)
+		}
+	}
+
+	// Without the transport set, only POST is served
+	h.SetStreamableHTTP(nil)
+	recorder := httptest.NewRecorder()
+	h.MCPHandler(recorder, httptest.NewRequest(http.MethodGet, "/mcp", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected GET to be refused without Streamable HTTP, got %d", recorder.Code)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	common.ToolCalls.Inc("metrics-test", "list", "success")
+	common.OpsRampRequestDuration.Observe(0.3, "GET", "resources.get", "2xx")
+
+	h := NewHTTPHandlers(server.NewMCPServer("test", "1.0.0"), nil, common.GetLogger(), time.Now(), nil)
+	recorder := httptest.NewRecorder()
+	h.MetricsHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := recorder.Body.String()
+	for _, want := range []string{
+		"# TYPE or_mcp_tool_calls_total counter",
+		`or_mcp_tool_calls_total{tool="metrics-test",action="list",outcome="success"} 1`,
+		`or_mcp_opsramp_request_duration_seconds_bucket{method="GET",endpoint="resources.get",status="2xx",le="0.5"} 1`,
+		`or_mcp_opsramp_request_duration_seconds_bucket{method="GET",endpoint="resources.get",status="2xx",le="0.25"} 0`,
+		"or_mcp_sessions ",
+		"or_mcp_opsramp_credential_failovers_total 0",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in the metrics, got:\n%s", want, body)
+		}
+	}
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("Expected the Prometheus text format, got %s", recorder.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// securityHeaders are sent on every response. The server only serves JSON and
+// event streams, so nothing may be framed, sniffed or loaded by a page.
+var securityHeaders = map[string]string{
+	"X-Content-Type-Options":  "nosniff",
+	"X-Frame-Options":         "DENY",
+	"Referrer-Policy":         "no-referrer",
+	"Content-Security-Policy": "default-src 'none'; frame-ancestors 'none'",
+}
+
+// SecurityHeaders wraps next so that every response carries the standard
+// security headers, and responses over TLS carry Strict-Transport-Security
+// for hstsMaxAge seconds unless it is negative
+func SecurityHeaders(next http.Handler, hstsMaxAge int) http.Handler {
+	hsts := "max-age=" + strconv.Itoa(hstsMaxAge)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		for name, value := range securityHeaders {
+			header.Set(name, value)
+		}
+		if r.TLS != nil && hstsMaxAge >= 0 {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ServerTLSConfig builds the listener's TLS configuration, or returns nil
+// when TLS is not enabled. A certificate file is loaded now so a bad one
+// fails at startup; ACME certificates are obtained on the first handshake
+// for each domain and renewed before they expire.
+func ServerTLSConfig(config common.TLSConfig) (*tls.Config, error) {
+	switch {
+	case config.CertFile != "":
+		certificate, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the TLS certificate: %w", err)
+		}
+		return &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{certificate},
+		}, nil
+	case len(config.ACMEDomains) > 0:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(config.ACMECacheDir),
+			HostPolicy: autocert.HostWhitelist(config.ACMEDomains...),
+			Email:      config.ACMEEmail,
+		}
+		if config.ACMEDirectoryURL != "" {
+			manager.Client = &acme.Client{DirectoryURL: config.ACMEDirectoryURL}
+		}
+		tlsConfig := manager.TLSConfig()
+		tlsConfig.MinVersion = tls.VersionTLS12
+		return tlsConfig, nil
+	default:
+		return nil, nil
+	}
+}
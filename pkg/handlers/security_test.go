@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	handler := SecurityHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}), 600)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if recorder.Header().Get("X-Content-Type-Options") != "nosniff" || recorder.Header().Get("X-Frame-Options") != "DENY" {
+		t.Errorf("Expected the security headers, got %v", recorder.Header())
+	}
+	if recorder.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("Expected no HSTS over plain HTTP")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.TLS = &tls.ConnectionState{}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if got := recorder.Header().Get("Strict-Transport-Security"); got != "max-age=600" {
+		t.Errorf("Expected HSTS over TLS, got %q", got)
+	}
+
+	recorder = httptest.NewRecorder()
+	SecurityHeaders(http.NotFoundHandler(), -1).ServeHTTP(recorder, req)
+	if recorder.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("Expected a negative max-age to leave HSTS out")
+	}
+}
+
+// writeTestCertificate writes a self-signed certificate and its key to dir
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mcp.example.com"},
+		DNSNames:     []string{"mcp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	if config, err := ServerTLSConfig(common.TLSConfig{}); config != nil || err != nil {
+		t.Errorf("Expected no TLS without a certificate or ACME domains, got %v, %v", config, err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir)
+	config, err := ServerTLSConfig(common.TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	if err != nil || len(config.Certificates) != 1 || config.MinVersion != tls.VersionTLS12 {
+		t.Fatalf("Expected the certificate to be loaded, got %+v, %v", config, err)
+	}
+
+	if _, err := ServerTLSConfig(common.TLSConfig{CertFile: certFile, KeyFile: certFile}); err == nil {
+		t.Error("Expected a bad key to fail at startup")
+	}
+
+	config, err = ServerTLSConfig(common.TLSConfig{ACMEDomains: []string{"mcp.example.com"}, ACMECacheDir: filepath.Join(dir, "acme")})
+	if err != nil || config.GetCertificate == nil || config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("Expected ACME certificates to be obtained on handshake, got %+v, %v", config, err)
+	}
+}
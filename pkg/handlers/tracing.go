@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/opsramp/or-mcp-v2/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// tracedMessagePaths are the endpoints that receive JSON-RPC messages by
+// POST, by transport. WebSocket messages are traced by the WebSocket
+// handler, as one connection carries them all.
+var tracedMessagePaths = map[string]string{
+	"/mcp":          "http",
+	"/mcp-message":  "sse",
+	"/message":      "inspector",
+	"/poll/message": "longpoll",
+}
+
+// Tracing wraps next so that each JSON-RPC message posted to a message
+// endpoint is traced as a server span, continuing the trace of a client that
+// sends a traceparent header. The span is the parent of the tool call and
+// OpsRamp request spans; on SSE and long-poll, where the response is sent on
+// another connection, it ends when the message is accepted.
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		transport, ok := tracedMessagePaths[r.URL.Path]
+		if !ok || r.Method != http.MethodPost || r.Body == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Peek at the message to name the span, then restore the body
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.StartMessage(ctx, transport, body)
+		defer span.End()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
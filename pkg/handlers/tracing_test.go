@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingContinuesClientTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	message := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"alerts"}}`
+	var received string
+	var inner trace.SpanContext
+	handler := Tracing(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		inner = trace.SpanContextFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp-message?sessionId=s-1", strings.NewReader(message))
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if received != message {
+		t.Errorf("Expected the handler to receive the whole body, got %q", received)
+	}
+	messageContext := inner
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected only the message to be traced, got %d spans", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "tools/call" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("Expected a server span named after the method, got %s %v", span.Name(), span.SpanKind())
+	}
+	if span.SpanContext().TraceID().String() != "0af7651916cd43dd8448eb211c80319c" || span.Parent().SpanID().String() != "b7ad6b7169203331" {
+		t.Errorf("Expected the client's trace to be continued, got %v", span.SpanContext().TraceID())
+	}
+	if messageContext.SpanID() != span.SpanContext().SpanID() {
+		t.Error("Expected the handler to run in the message span")
+	}
+}
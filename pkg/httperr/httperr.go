@@ -0,0 +1,159 @@
+// Package httperr classifies errors from OpsRamp API calls by HTTP status
+// code and transport failure instead of by matching error text.
+package httperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Kind is the class of a failed API call
+type Kind string
+
+const (
+	KindNone         Kind = ""
+	KindValidation   Kind = "validation"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindRateLimited  Kind = "rate_limited"
+	KindTimeout      Kind = "timeout"
+	KindUnavailable  Kind = "unavailable"
+	KindNetwork      Kind = "network"
+	KindCanceled     Kind = "canceled"
+	KindServer       Kind = "server"
+	KindUnknown      Kind = "unknown"
+)
+
+// StatusError is returned for an API response with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Body       string
+	RetryAfter string
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// FromResponse builds a StatusError for a response and its already read body
+func FromResponse(resp *http.Response, body []byte) *StatusError {
+	return &StatusError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: resp.Header.Get("Retry-After"),
+	}
+}
+
+// StatusCode returns the HTTP status code carried by err, or 0 if err is
+// not an API response error
+func StatusCode(err error) int {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	return 0
+}
+
+// Classify returns the kind of a failed API call. Response errors are
+// classified by status code; other errors by their transport failure.
+func Classify(err error) Kind {
+	if err == nil {
+		return KindNone
+	}
+	if status := StatusCode(err); status != 0 {
+		return classifyStatus(status)
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return KindCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return KindTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return KindTimeout
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsTemporary {
+		return KindNetwork
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return KindNetwork
+	}
+	return KindUnknown
+}
+
+// classifyStatus maps an HTTP status code to its kind
+func classifyStatus(status int) Kind {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return KindValidation
+	case http.StatusUnauthorized:
+		return KindUnauthorized
+	case http.StatusForbidden:
+		return KindForbidden
+	case http.StatusNotFound, http.StatusGone:
+		return KindNotFound
+	case http.StatusConflict:
+		return KindConflict
+	case http.StatusTooManyRequests:
+		return KindRateLimited
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return KindTimeout
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return KindUnavailable
+	}
+	if status >= 500 {
+		return KindServer
+	}
+	return KindUnknown
+}
+
+// IsRetryable reports whether a failed call may succeed when repeated:
+// rate limiting, timeouts, unavailable gateways and connection failures
+func IsRetryable(err error) bool {
+	switch Classify(err) {
+	case KindRateLimited, KindTimeout, KindUnavailable, KindNetwork:
+		return true
+	}
+	return false
+}
+
+// IsRateLimited reports whether err is an HTTP 429 response
+func IsRateLimited(err error) bool {
+	return Classify(err) == KindRateLimited
+}
+
+// IsNotFound reports whether err is an HTTP 404 or 410 response
+func IsNotFound(err error) bool {
+	return Classify(err) == KindNotFound
+}
+
+// RetryAfterDelay returns how long the Retry-After header of a response
+// error asks the caller to wait, or 0 if err carries none. Both the
+// delay-seconds and HTTP-date forms are accepted.
+func RetryAfterDelay(err error) time.Duration {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.RetryAfter == "" {
+		return 0
+	}
+	if seconds, parseErr := strconv.Atoi(statusErr.RetryAfter); parseErr == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, parseErr := http.ParseTime(statusErr.RetryAfter); parseErr == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
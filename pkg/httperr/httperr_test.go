@@ -0,0 +1,112 @@
+package httperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// timeoutError is a net.Error that timed out
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func status(code int) error {
+	return fmt.Errorf("failed to get resource r1: %w", &StatusError{StatusCode: code, Body: "{}"})
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		kind      Kind
+		retryable bool
+	}{
+		{"nil", nil, KindNone, false},
+		{"400", status(http.StatusBadRequest), KindValidation, false},
+		{"422", status(http.StatusUnprocessableEntity), KindValidation, false},
+		{"401", status(http.StatusUnauthorized), KindUnauthorized, false},
+		{"403", status(http.StatusForbidden), KindForbidden, false},
+		{"404", status(http.StatusNotFound), KindNotFound, false},
+		{"410", status(http.StatusGone), KindNotFound, false},
+		{"409", status(http.StatusConflict), KindConflict, false},
+		{"429", status(http.StatusTooManyRequests), KindRateLimited, true},
+		{"408", status(http.StatusRequestTimeout), KindTimeout, true},
+		{"504", status(http.StatusGatewayTimeout), KindTimeout, true},
+		{"502", status(http.StatusBadGateway), KindUnavailable, true},
+		{"503", status(http.StatusServiceUnavailable), KindUnavailable, true},
+		{"500", status(http.StatusInternalServerError), KindServer, false},
+		{"other 4xx", status(http.StatusMethodNotAllowed), KindUnknown, false},
+		{"canceled", fmt.Errorf("request failed: %w", context.Canceled), KindCanceled, false},
+		{"deadline", fmt.Errorf("request failed: %w", context.DeadlineExceeded), KindTimeout, true},
+		{"net timeout", &net.OpError{Op: "read", Err: timeoutError{}}, KindTimeout, true},
+		{"temporary dns", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, KindNetwork, true},
+		{"permanent dns", &net.DNSError{Err: "no such host", IsNotFound: true}, KindUnknown, false},
+		{"refused", &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, KindNetwork, true},
+		{"reset", &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, KindNetwork, true},
+		{"broken pipe", &net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)}, KindNetwork, true},
+		{"unexpected eof", fmt.Errorf("request failed: %w", io.ErrUnexpectedEOF), KindNetwork, true},
+		// Error text is not inspected
+		{"text only", errors.New("API request failed with status 503: timeout"), KindUnknown, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.kind {
+				t.Errorf("Classify = %q, want %q", got, tt.kind)
+			}
+			if got := IsRetryable(tt.err); got != tt.retryable {
+				t.Errorf("IsRetryable = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestHelpers(t *testing.T) {
+	if !IsRateLimited(status(http.StatusTooManyRequests)) || IsRateLimited(status(http.StatusServiceUnavailable)) {
+		t.Error("IsRateLimited should match only 429")
+	}
+	if !IsNotFound(status(http.StatusNotFound)) || IsNotFound(status(http.StatusBadRequest)) {
+		t.Error("IsNotFound should match only 404 and 410")
+	}
+	if StatusCode(errors.New("plain")) != 0 || StatusCode(status(http.StatusConflict)) != http.StatusConflict {
+		t.Error("StatusCode should unwrap response errors only")
+	}
+}
+
+func TestFromResponse(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"30"}}}
+	err := FromResponse(resp, []byte(`{"error":"slow down"}`))
+	if err.RetryAfter != "30" {
+		t.Errorf("RetryAfter = %q", err.RetryAfter)
+	}
+	if want := `API request failed with status 429: {"error":"slow down"}`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestRetryAfterDelay(t *testing.T) {
+	retryAfter := func(value string) error {
+		return &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: value}
+	}
+	if got := RetryAfterDelay(retryAfter("30")); got != 30*time.Second {
+		t.Errorf("RetryAfterDelay(30) = %v", got)
+	}
+	if got := RetryAfterDelay(retryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))); got <= 50*time.Second || got > time.Minute {
+		t.Errorf("RetryAfterDelay(date) = %v, want about a minute", got)
+	}
+	for _, err := range []error{retryAfter(""), retryAfter("soon"), retryAfter("-5"), errors.New("plain")} {
+		if got := RetryAfterDelay(err); got != 0 {
+			t.Errorf("RetryAfterDelay(%v) = %v, want 0", err, got)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package locks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// Lock contention modes
+const (
+	// ModeQueue waits for a held lock to be released, up to the wait timeout
+	ModeQueue = "queue"
+	// ModeFail fails immediately when the lock is held
+	ModeFail = "fail"
+)
+
+// DefaultWaitTimeout is how long a queued acquire waits when none is configured
+const DefaultWaitTimeout = 2 * time.Minute
+
+// BusyError is returned when a lock is held by another caller and the
+// acquire fails fast or times out in the queue
+type BusyError struct {
+	Name    string
+	Holder  string
+	Purpose string
+	Since   time.Time
+}
+
+// Error implements the error interface
+func (e *BusyError) Error() string {
+	return fmt.Sprintf("%s is locked by %s for %s since %s; retry once it finishes",
+		e.Name, e.Holder, e.Purpose, e.Since.UTC().Format(time.RFC3339))
+}
+
+// Status describes a lock that is held or has callers waiting for it
+type Status struct {
+	Name       string    `json:"name"`
+	Holder     string    `json:"holder"`
+	Purpose    string    `json:"purpose"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	HeldFor    string    `json:"heldFor"`
+	Waiting    int       `json:"waiting"`
+}
+
+// lock is one named advisory lock; token holds a value while the lock is free
+type lock struct {
+	token      chan struct{}
+	holder     string
+	purpose    string
+	acquiredAt time.Time
+	waiting    int
+	held       bool
+}
+
+// Manager hands out named advisory locks. Locks only coordinate callers in
+// this process; they do not stop changes made outside the server.
+type Manager struct {
+	mode        string
+	waitTimeout time.Duration
+	logger      *common.CustomLogger
+
+	mu    sync.Mutex
+	locks map[string]*lock
+}
+
+// NewManager creates a lock manager from the configuration
+func NewManager(config common.LocksConfig) *Manager {
+	mode := config.Mode
+	if mode != ModeFail {
+		mode = ModeQueue
+	}
+	waitTimeout := time.Duration(config.WaitSeconds) * time.Second
+	if waitTimeout <= 0 {
+		waitTimeout = DefaultWaitTimeout
+	}
+	return &Manager{
+		mode:        mode,
+		waitTimeout: waitTimeout,
+		logger:      common.GetLogger(),
+		locks:       make(map[string]*lock),
+	}
+}
+
+// Acquire takes the named lock for holder, waiting or failing fast when it is
+// held according to the manager's mode. The returned function releases the lock.
+func (m *Manager) Acquire(ctx context.Context, name, holder, purpose string) (func(), error) {
+	m.mu.Lock()
+	l, ok := m.locks[name]
+	if !ok {
+		l = &lock{token: make(chan struct{}, 1)}
+		l.token <- struct{}{}
+		m.locks[name] = l
+	}
+	l.waiting++
+	holding, holdingPurpose := l.holder, l.purpose
+	m.mu.Unlock()
+
+	acquired := false
+	select {
+	case <-l.token:
+		acquired = true
+	default:
+	}
+
+	if !acquired && m.mode == ModeQueue {
+		m.logger.Info("Waiting for lock %s held by %s (%s)", name, holding, holdingPurpose)
+		timer := time.NewTimer(m.waitTimeout)
+		select {
+		case <-l.token:
+			acquired = true
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			m.done(name, l, false, "", "")
+			return nil, ctx.Err()
+		}
+		timer.Stop()
+	}
+
+	if busy := m.done(name, l, acquired, holder, purpose); busy != nil {
+		return nil, busy
+	}
+
+	m.logger.Info("Acquired lock %s for %s (%s)", name, holder, purpose)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			l.held = false
+			l.holder, l.purpose = "", ""
+			m.mu.Unlock()
+			l.token <- struct{}{}
+			m.logger.Info("Released lock %s", name)
+		})
+	}, nil
+}
+
+// done records the outcome of an acquire attempt, returning who holds the
+// lock when it was not acquired
+func (m *Manager) done(name string, l *lock, acquired bool, holder, purpose string) *BusyError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l.waiting--
+	if !acquired {
+		return &BusyError{Name: name, Holder: l.holder, Purpose: l.purpose, Since: l.acquiredAt}
+	}
+	l.held = true
+	l.holder = holder
+	l.purpose = purpose
+	l.acquiredAt = time.Now()
+	return nil
+}
+
+// Status lists the locks that are held or have waiters, by name
+func (m *Manager) Status() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]Status, 0, len(m.locks))
+	for name, l := range m.locks {
+		if !l.held && l.waiting == 0 {
+			continue
+		}
+		status := Status{Name: name, Waiting: l.waiting}
+		if l.held {
+			status.Holder = l.holder
+			status.Purpose = l.purpose
+			status.AcquiredAt = l.acquiredAt
+			status.HeldFor = time.Since(l.acquiredAt).Round(time.Second).String()
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Process-wide lock manager
+var (
+	defaultManager = NewManager(common.LocksConfig{})
+	defaultMu      sync.RWMutex
+)
+
+// SetDefault sets the process-wide lock manager
+func SetDefault(m *Manager) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultManager = m
+}
+
+// Default returns the process-wide lock manager
+func Default() *Manager {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultManager
+}
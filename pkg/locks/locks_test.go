@@ -0,0 +1,108 @@
+package locks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestAcquireFailsFastWhenHeld(t *testing.T) {
+	m := NewManager(common.LocksConfig{Mode: ModeFail})
+
+	release, err := m.Acquire(context.Background(), "bulk:resources:default", "session a", "resources bulkDelete")
+	if err != nil {
+		t.Fatalf("First acquire failed: %v", err)
+	}
+
+	_, err = m.Acquire(context.Background(), "bulk:resources:default", "session b", "resources bulkUpdate")
+	var busy *BusyError
+	if !errors.As(err, &busy) {
+		t.Fatalf("Expected BusyError, got %v", err)
+	}
+	if busy.Holder != "session a" || busy.Purpose != "resources bulkDelete" {
+		t.Errorf("Unexpected holder in %+v", busy)
+	}
+
+	// Other tenants are not fenced by this lock
+	other, err := m.Acquire(context.Background(), "bulk:resources:acme", "session b", "resources bulkUpdate")
+	if err != nil {
+		t.Fatalf("Acquire on another tenant failed: %v", err)
+	}
+	other()
+
+	release()
+	release()
+	again, err := m.Acquire(context.Background(), "bulk:resources:default", "session b", "resources bulkUpdate")
+	if err != nil {
+		t.Fatalf("Acquire after release failed: %v", err)
+	}
+	again()
+}
+
+func TestAcquireQueuesUntilReleased(t *testing.T) {
+	m := NewManager(common.LocksConfig{Mode: ModeQueue, WaitSeconds: 5})
+
+	release, err := m.Acquire(context.Background(), "bulk:resources:default", "session a", "resources onboard")
+	if err != nil {
+		t.Fatalf("First acquire failed: %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		next, err := m.Acquire(context.Background(), "bulk:resources:default", "session b", "resources bulkDelete")
+		if err == nil {
+			next()
+		}
+		acquired <- err
+	}()
+
+	// Wait for the second caller to queue
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		statuses := m.Status()
+		if len(statuses) == 1 && statuses[0].Waiting == 1 {
+			if statuses[0].Holder != "session a" {
+				t.Errorf("Expected session a to hold the lock, got %+v", statuses[0])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Second caller never queued: %+v", statuses)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	release()
+	if err := <-acquired; err != nil {
+		t.Fatalf("Queued acquire failed: %v", err)
+	}
+	if statuses := m.Status(); len(statuses) != 0 {
+		t.Errorf("Expected no held locks, got %+v", statuses)
+	}
+}
+
+func TestAcquireQueueTimesOut(t *testing.T) {
+	m := NewManager(common.LocksConfig{Mode: ModeQueue})
+	m.waitTimeout = 20 * time.Millisecond
+
+	release, err := m.Acquire(context.Background(), "bulk:resources:default", "session a", "resources bulkUpdate")
+	if err != nil {
+		t.Fatalf("First acquire failed: %v", err)
+	}
+	defer release()
+
+	_, err = m.Acquire(context.Background(), "bulk:resources:default", "session b", "resources bulkUpdate")
+	var busy *BusyError
+	if !errors.As(err, &busy) {
+		t.Fatalf("Expected BusyError after timeout, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := m.Acquire(ctx, "bulk:resources:default", "session c", "resources bulkUpdate"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -9,12 +9,17 @@ import (
 
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
 )
 
 // InspectorHandler handles MCP Inspector compatibility requirements
 type InspectorHandler struct {
 	mcpServer *server.MCPServer
 	logger    *common.CustomLogger
+	// strict disables every compatibility shim (see common.StrictProtocol)
+	strict bool
+	// instructions is the initialize instructions text, matching the MCP server's
+	instructions string
 }
 
 // NewInspectorHandler creates a new MCP Inspector compatibility handler
@@ -22,9 +27,19 @@ func NewInspectorHandler(mcpServer *server.MCPServer, logger *common.CustomLogge
 	return &InspectorHandler{
 		mcpServer: mcpServer,
 		logger:    logger,
+		strict:    common.StrictProtocol(),
 	}
 }
 
+// defaultInstructions is sent in the initialize response when no
+// deployment-specific instructions are set
+const defaultInstructions = "HPE OpsRamp MCP Server providing access to OpsRamp integrations and resources. Use the 'integrations' tool to manage integrations and the 'resources' tool to access OpsRamp resources."
+
+// SetInstructions sets the instructions sent in the initialize response
+func (h *InspectorHandler) SetInstructions(instructions string) {
+	h.instructions = instructions
+}
+
 // jsonRpcRequest represents a JSON-RPC 2.0 request
 type jsonRpcRequest struct {
 	JsonRpc string                 `json:"jsonrpc"`
@@ -57,6 +72,12 @@ func (h *InspectorHandler) HandleMessage(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Strict mode bypasses every compatibility shim below
+	if h.strict {
+		h.handleStrictMessage(w, r)
+		return
+	}
+
 	// Validate session and handle MCP Inspector compatibility
 	if !h.validateSessionAndRoute(w, r) {
 		return
@@ -92,6 +113,54 @@ func (h *InspectorHandler) HandleMessage(w http.ResponseWriter, r *http.Request)
 	h.jsonError(w, "Method not found", http.StatusNotFound, rpcRequest.Id)
 }
 
+// handleStrictMessage processes a message with standard MCP semantics only: the
+// session must be registered with the transport, every message is handled by
+// the MCP server, and notifications and client responses are acknowledged with
+// 202 Accepted and no body
+func (h *InspectorHandler) handleStrictMessage(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.URL.Query().Get("sessionId")
+	if sessionID == "" {
+		h.logger.Warn("Strict protocol: missing sessionId parameter in request")
+		http.Error(w, "Missing sessionId parameter", http.StatusBadRequest)
+		return
+	}
+	if !session.IsConnected(sessionID) {
+		h.logger.Warn("Strict protocol: rejecting unknown session ID: %s", sessionID)
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.logger.Error("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	// Error responses from the client answer server-initiated requests and get no reply
+	var envelope struct {
+		Method string          `json:"method"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Method == "" && envelope.Error != nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	response := h.mcpServer.HandleMessage(r.Context(), body)
+	if response == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("Failed to encode JSON response: %v", err)
+	}
+}
+
 // validateSessionAndRoute validates session and routes MCP Inspector requests
 func (h *InspectorHandler) validateSessionAndRoute(w http.ResponseWriter, r *http.Request) bool {
 	sessionID := r.URL.Query().Get("sessionId")
@@ -300,6 +369,11 @@ func (h *InspectorHandler) handleInitializeMethod(w http.ResponseWriter, r *http
 		h.logger.Info("MCP Inspector requested protocol version: %s", requestedVersion)
 	}
 
+	instructions := h.instructions
+	if instructions == "" {
+		instructions = defaultInstructions
+	}
+
 	// Create a manual initialize response that matches MCP Inspector's expectations
 	initResponse := jsonRpcResponse{
 		JsonRpc: "2.0",
@@ -320,7 +394,7 @@ func (h *InspectorHandler) handleInitializeMethod(w http.ResponseWriter, r *http
 				"name":    "HPE OpsRamp MCP",
 				"version": "1.0.0",
 			},
-			"instructions": "HPE OpsRamp MCP Server providing access to OpsRamp integrations and resources. Use the 'integrations' tool to manage integrations and the 'resources' tool to access OpsRamp resources.",
+			"instructions": instructions,
 		},
 	}
 
@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+func newStrictHandler() *InspectorHandler {
+	return &InspectorHandler{
+		mcpServer: server.NewMCPServer("strict-test", "1.0.0"),
+		logger:    common.GetLogger(),
+		strict:    true,
+	}
+}
+
+func postMessage(h *InspectorHandler, sessionID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/message?sessionId="+sessionID, strings.NewReader(body))
+	req.Header.Set("Accept", "text/event-stream")
+	rec := httptest.NewRecorder()
+	h.HandleMessage(rec, req)
+	return rec
+}
+
+func TestStrictProtocolRejectsUnknownSession(t *testing.T) {
+	rec := postMessage(newStrictHandler(), "not-registered", `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected unknown session to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestStrictProtocolUsesServerSemantics(t *testing.T) {
+	h := newStrictHandler()
+	session.MarkConnected("strict-session")
+	defer session.Remove("strict-session")
+
+	// initialize is answered by the MCP server, not the manual compatibility response
+	rec := postMessage(h, "strict-session", `{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"t","version":"1"}}}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "strict-test") {
+		t.Errorf("Expected server initialize response, got %d %s", rec.Code, rec.Body.String())
+	}
+	if strings.HasPrefix(rec.Body.String(), "event:") {
+		t.Error("Expected plain JSON response without SSE framing")
+	}
+
+	// A client acknowledgment with id=1 gets no synthesized 'initialized' notification
+	rec = postMessage(h, "strict-session", `{"jsonrpc":"2.0","id":1,"result":{}}`)
+	if rec.Code != http.StatusAccepted || rec.Body.Len() != 0 {
+		t.Errorf("Expected 202 with empty body, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	// The non-standard callTool alias is not accepted
+	rec = postMessage(h, "strict-session", `{"jsonrpc":"2.0","id":2,"method":"callTool","params":{"name":"x"}}`)
+	if !strings.Contains(rec.Body.String(), `"error"`) {
+		t.Errorf("Expected callTool to be rejected, got %s", rec.Body.String())
+	}
+}
+
+func newCompatHandler() *InspectorHandler {
+	mcpServer := server.NewMCPServer("compat-test", "1.0.0")
+	mcpServer.AddTool(mcpgo.Tool{Name: "echo", InputSchema: mcpgo.ToolInputSchema{Type: "object"}},
+		func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+			return mcpgo.NewToolResultText("echo:" + req.GetString("text", "")), nil
+		})
+	return &InspectorHandler{mcpServer: mcpServer, logger: common.GetLogger()}
+}
+
+func postJSON(h *InspectorHandler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/message?sessionId=any", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.HandleMessage(rec, req)
+	return rec
+}
+
+func TestInspectorInitializeIsAnsweredManually(t *testing.T) {
+	rec := postJSON(newCompatHandler(), `{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, `"protocolVersion":"2025-03-26"`) || !strings.Contains(body, "HPE OpsRamp MCP") {
+		t.Errorf("Expected manual initialize response echoing the protocol version, got %d %s", rec.Code, body)
+	}
+}
+
+func TestInspectorRoutesToolCalls(t *testing.T) {
+	h := newCompatHandler()
+	for _, method := range []string{"tools/call", "callTool"} {
+		rec := postJSON(h, `{"jsonrpc":"2.0","id":3,"method":"`+method+`","params":{"name":"echo","arguments":{"text":"hi"}}}`)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "echo:hi") {
+			t.Errorf("%s: expected tool result, got %d %s", method, rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := postJSON(h, `{"jsonrpc":"2.0","id":4,"method":"tools/list"}`)
+	if !strings.Contains(rec.Body.String(), `"echo"`) {
+		t.Errorf("Expected tools/list to be routed to the server, got %s", rec.Body.String())
+	}
+}
+
+func TestInspectorResponsesAndUnknownMethods(t *testing.T) {
+	h := newCompatHandler()
+
+	// The acknowledgment with id=1 completes the handshake with an 'initialized' notification
+	rec := postJSON(h, `{"jsonrpc":"2.0","id":1,"result":{}}`)
+	if !strings.Contains(rec.Body.String(), `"method":"initialized"`) {
+		t.Errorf("Expected initialized notification, got %s", rec.Body.String())
+	}
+
+	// Unsupported methods are rejected with HTTP 404
+	rec = postJSON(h, `{"jsonrpc":"2.0","id":5,"method":"resources/unknown"}`)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown method, got %d", rec.Code)
+	}
+
+	// SSE clients receive event-stream framing
+	req := httptest.NewRequest(http.MethodPost, "/message?sessionId=any", strings.NewReader(`{"jsonrpc":"2.0","id":6,"method":"tools/list"}`))
+	req.Header.Set("Accept", "text/event-stream")
+	sse := httptest.NewRecorder()
+	h.HandleMessage(sse, req)
+	if !strings.HasPrefix(sse.Body.String(), "event: message\ndata: ") {
+		t.Errorf("Expected SSE framing, got %q", sse.Body.String())
+	}
+}
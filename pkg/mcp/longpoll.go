@@ -0,0 +1,376 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+const (
+	// DefaultPollWait is how long a poll waits for messages when no timeout is requested
+	DefaultPollWait = 25 * time.Second
+	// MaxPollWait caps the requested poll timeout, keeping it well under the HTTP write timeout
+	MaxPollWait = 60 * time.Second
+	// PollSessionIdleTimeout is how long a long-poll session survives without a poll
+	PollSessionIdleTimeout = 2 * time.Minute
+	// maxQueuedMessages bounds each session's undelivered message queue
+	maxQueuedMessages = 1000
+)
+
+// PollMessage is a queued server-to-client message. Cursors increase by one
+// per message within a session.
+type PollMessage struct {
+	Cursor  int64           `json:"cursor"`
+	Message json.RawMessage `json:"message"`
+}
+
+// PollResponse is the body returned by a poll
+type PollResponse struct {
+	Messages []PollMessage `json:"messages"`
+	// Cursor is the cursor to send with the next poll
+	Cursor int64 `json:"cursor"`
+	// Missed counts messages dropped because the queue overflowed before they were polled
+	Missed int64 `json:"missed,omitempty"`
+}
+
+// pollSession is an MCP client session whose messages are queued until polled
+type pollSession struct {
+	id            string
+	notifications chan mcpgo.JSONRPCNotification
+	initialized   bool
+	logLevel      mcpgo.LoggingLevel
+
+	mu       sync.Mutex
+	queue    []PollMessage
+	next     int64
+	wake     chan struct{}
+	lastPoll time.Time
+	done     chan struct{}
+}
+
+func (s *pollSession) SessionID() string { return s.id }
+
+func (s *pollSession) NotificationChannel() chan<- mcpgo.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *pollSession) Initialize() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initialized = true
+}
+
+func (s *pollSession) Initialized() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.initialized
+}
+
+// SetLogLevel sets the minimum level of log notifications sent to the client
+func (s *pollSession) SetLogLevel(level mcpgo.LoggingLevel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logLevel = level
+}
+
+// GetLogLevel returns the minimum log notification level, error until the client sets one
+func (s *pollSession) GetLogLevel() mcpgo.LoggingLevel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.logLevel == "" {
+		return mcpgo.LoggingLevelError
+	}
+	return s.logLevel
+}
+
+// enqueue appends a message, dropping the oldest once the queue is full, and
+// wakes any waiting poll. A full queue means the client stopped polling, so
+// it counts as a failed delivery in the session's health.
+func (s *pollSession) enqueue(message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	s.queue = append(s.queue, PollMessage{Cursor: s.next, Message: data})
+	if len(s.queue) > maxQueuedMessages {
+		s.queue = s.queue[len(s.queue)-maxQueuedMessages:]
+		session.RecordDelivery(s.id, false)
+	}
+	close(s.wake)
+	s.wake = make(chan struct{})
+	return nil
+}
+
+// after acknowledges every message up to cursor and returns the rest, along
+// with a channel closed when more arrive
+func (s *pollSession) after(cursor int64, now time.Time) (PollResponse, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastPoll = now
+
+	response := PollResponse{Messages: []PollMessage{}, Cursor: cursor}
+	drop := 0
+	for drop < len(s.queue) && s.queue[drop].Cursor <= cursor {
+		drop++
+	}
+	s.queue = s.queue[drop:]
+
+	if len(s.queue) > 0 {
+		if first := s.queue[0].Cursor; first > cursor+1 {
+			response.Missed = first - cursor - 1
+		}
+		response.Messages = append(response.Messages, s.queue...)
+		response.Cursor = s.queue[len(s.queue)-1].Cursor
+	}
+	return response, s.wake
+}
+
+// forwardNotifications queues notifications sent by the MCP server until the session closes
+func (s *pollSession) forwardNotifications(logger *common.CustomLogger) {
+	for {
+		select {
+		case notification := <-s.notifications:
+			if err := s.enqueue(notification); err != nil {
+				logger.Error("Failed to queue notification for poll session %s: %v", s.id, err)
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// LongPollHandler is a fallback transport for clients behind proxies that
+// strip SSE. A client connects with POST /poll/connect, sends JSON-RPC
+// messages with POST /poll/message?sessionId=..., and retrieves responses and
+// notifications in order with GET /poll?sessionId=...&cursor=...; a poll
+// waits up to timeout seconds for new messages. DELETE /poll?sessionId=...
+// ends the session, and sessions that stop polling expire.
+type LongPollHandler struct {
+	mcpServer *server.MCPServer
+	logger    *common.CustomLogger
+
+	mu       sync.Mutex
+	sessions map[string]*pollSession
+	now      func() time.Time
+}
+
+// NewLongPollHandler creates a long-poll transport for the MCP server
+func NewLongPollHandler(mcpServer *server.MCPServer, logger *common.CustomLogger) *LongPollHandler {
+	return &LongPollHandler{
+		mcpServer: mcpServer,
+		logger:    logger,
+		sessions:  make(map[string]*pollSession),
+		now:       time.Now,
+	}
+}
+
+// Connect creates a long-poll session and registers it with the MCP server
+func (h *LongPollHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.expireIdleSessions()
+
+	sess := &pollSession{
+		id:            uuid.New().String(),
+		notifications: make(chan mcpgo.JSONRPCNotification, 100),
+		wake:          make(chan struct{}),
+		lastPoll:      h.now(),
+		done:          make(chan struct{}),
+	}
+	if err := h.mcpServer.RegisterSession(r.Context(), sess); err != nil {
+		h.logger.Error("Failed to register poll session: %v", err)
+		http.Error(w, "Failed to create session", http.StatusInternalServerError)
+		return
+	}
+	go sess.forwardNotifications(h.logger)
+
+	h.mu.Lock()
+	h.sessions[sess.id] = sess
+	h.mu.Unlock()
+
+	h.logger.Info("Long-poll session connected: %s", sess.id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"sessionId":       sess.id,
+		"messageEndpoint": "/poll/message?sessionId=" + sess.id,
+		"pollEndpoint":    "/poll?sessionId=" + sess.id,
+		"cursor":          0,
+	})
+}
+
+// Message handles a JSON-RPC message from a long-poll client. The response,
+// if any, is queued for the next poll so it is delivered in order with
+// notifications.
+func (h *LongPollHandler) Message(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	sess, ok := h.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil || !json.Valid(body) {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	ctx := h.mcpServer.WithContext(context.WithoutCancel(r.Context()), sess)
+	if response := h.mcpServer.HandleMessage(ctx, body); response != nil {
+		if err := sess.enqueue(response); err != nil {
+			h.logger.Error("Failed to queue response for poll session %s: %v", sess.id, err)
+			http.Error(w, "Failed to queue response", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Poll returns queued messages after the given cursor, waiting for new ones
+// when none are queued. DELETE ends the session.
+func (h *LongPollHandler) Poll(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodDelete:
+		if sess, ok := h.lookup(w, r); ok {
+			h.disconnect(sess)
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.expireIdleSessions()
+	sess, ok := h.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	var cursor int64
+	if raw := query.Get("cursor"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		cursor = parsed
+	}
+	wait := DefaultPollWait
+	if raw := query.Get("timeout"); raw != "" {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil || seconds < 0 {
+			http.Error(w, "Invalid timeout", http.StatusBadRequest)
+			return
+		}
+		wait = time.Duration(seconds) * time.Second
+		if wait > MaxPollWait {
+			wait = MaxPollWait
+		}
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		response, wake := sess.after(cursor, h.now())
+		if len(response.Messages) > 0 || wait == 0 {
+			writeJSON(w, http.StatusOK, response)
+			return
+		}
+		select {
+		case <-wake:
+		case <-timer.C:
+			writeJSON(w, http.StatusOK, response)
+			return
+		case <-sess.done:
+			http.Error(w, "Session closed", http.StatusGone)
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// lookup resolves the sessionId query parameter, writing an error response if it is unknown
+func (h *LongPollHandler) lookup(w http.ResponseWriter, r *http.Request) (*pollSession, bool) {
+	sessionID := r.URL.Query().Get("sessionId")
+	if sessionID == "" {
+		http.Error(w, "Missing sessionId parameter", http.StatusBadRequest)
+		return nil, false
+	}
+
+	h.mu.Lock()
+	sess, ok := h.sessions[sessionID]
+	h.mu.Unlock()
+	if !ok {
+		h.logger.Warn("Long-poll request for unknown session: %s", sessionID)
+		http.Error(w, "Invalid session ID", http.StatusNotFound)
+		return nil, false
+	}
+	return sess, true
+}
+
+// disconnect unregisters a session from the MCP server and wakes its pollers
+func (h *LongPollHandler) disconnect(sess *pollSession) {
+	h.mu.Lock()
+	_, ok := h.sessions[sess.id]
+	delete(h.sessions, sess.id)
+	h.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	close(sess.done)
+	h.mcpServer.UnregisterSession(context.Background(), sess.id)
+	h.logger.Info("Long-poll session disconnected: %s", sess.id)
+}
+
+// expireIdleSessions disconnects sessions that have not polled within PollSessionIdleTimeout
+func (h *LongPollHandler) expireIdleSessions() {
+	cutoff := h.now().Add(-PollSessionIdleTimeout)
+
+	h.mu.Lock()
+	var idle []*pollSession
+	for _, sess := range h.sessions {
+		sess.mu.Lock()
+		if sess.lastPoll.Before(cutoff) {
+			idle = append(idle, sess)
+		}
+		sess.mu.Unlock()
+	}
+	h.mu.Unlock()
+
+	for _, sess := range idle {
+		h.logger.Info("Expiring idle long-poll session: %s", sess.id)
+		h.disconnect(sess)
+	}
+}
+
+// writeJSON writes a JSON response body
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		common.GetLogger().Error("Failed to encode JSON response: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func connectPollSession(t *testing.T, h *LongPollHandler) string {
+	rec := httptest.NewRecorder()
+	h.Connect(rec, httptest.NewRequest(http.MethodPost, "/poll/connect", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Connect failed: %d %s", rec.Code, rec.Body.String())
+	}
+	var connected struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &connected); err != nil || connected.SessionID == "" {
+		t.Fatalf("Unexpected connect response: %s", rec.Body.String())
+	}
+	return connected.SessionID
+}
+
+func poll(t *testing.T, h *LongPollHandler, sessionID, query string) PollResponse {
+	rec := httptest.NewRecorder()
+	h.Poll(rec, httptest.NewRequest(http.MethodGet, "/poll?sessionId="+sessionID+"&"+query, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Poll failed: %d %s", rec.Code, rec.Body.String())
+	}
+	var response PollResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid poll response: %v", err)
+	}
+	return response
+}
+
+func TestLongPollDeliversResponsesInOrder(t *testing.T) {
+	h := NewLongPollHandler(server.NewMCPServer("poll-test", "1.0.0"), common.GetLogger())
+	sessionID := connectPollSession(t, h)
+
+	for _, body := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"t","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+	} {
+		rec := httptest.NewRecorder()
+		h.Message(rec, httptest.NewRequest(http.MethodPost, "/poll/message?sessionId="+sessionID, strings.NewReader(body)))
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("Expected 202, got %d %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	response := poll(t, h, sessionID, "cursor=0")
+	if len(response.Messages) != 2 || response.Cursor != 2 {
+		t.Fatalf("Expected initialize and ping responses, got %+v", response)
+	}
+	if !strings.Contains(string(response.Messages[0].Message), "poll-test") || !strings.Contains(string(response.Messages[1].Message), `"id":2`) {
+		t.Errorf("Messages out of order: %s, %s", response.Messages[0].Message, response.Messages[1].Message)
+	}
+
+	// Polling with the returned cursor acknowledges what was delivered
+	if response = poll(t, h, sessionID, "cursor=2&timeout=0"); len(response.Messages) != 0 || response.Cursor != 2 {
+		t.Errorf("Expected no new messages, got %+v", response)
+	}
+}
+
+func TestLongPollWaitsForNotifications(t *testing.T) {
+	mcpServer := server.NewMCPServer("poll-test", "1.0.0")
+	h := NewLongPollHandler(mcpServer, common.GetLogger())
+	sessionID := connectPollSession(t, h)
+
+	h.mu.Lock()
+	sess := h.sessions[sessionID]
+	h.mu.Unlock()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		sess.NotificationChannel() <- mcpgo.JSONRPCNotification{
+			JSONRPC:      mcpgo.JSONRPC_VERSION,
+			Notification: mcpgo.Notification{Method: "notifications/message"},
+		}
+	}()
+
+	start := time.Now()
+	response := poll(t, h, sessionID, "timeout=5")
+	if len(response.Messages) != 1 || !strings.Contains(string(response.Messages[0].Message), "notifications/message") {
+		t.Fatalf("Expected the notification, got %+v", response)
+	}
+	if time.Since(start) > 4*time.Second {
+		t.Error("Poll did not wake when the notification arrived")
+	}
+}
+
+func TestLongPollSessionLifecycle(t *testing.T) {
+	h := NewLongPollHandler(server.NewMCPServer("poll-test", "1.0.0"), common.GetLogger())
+	now := time.Now()
+	h.now = func() time.Time { return now }
+
+	rec := httptest.NewRecorder()
+	h.Poll(rec, httptest.NewRequest(http.MethodGet, "/poll?sessionId=unknown", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown session, got %d", rec.Code)
+	}
+
+	idle := connectPollSession(t, h)
+	now = now.Add(PollSessionIdleTimeout + time.Second)
+	active := connectPollSession(t, h)
+
+	rec = httptest.NewRecorder()
+	h.Poll(rec, httptest.NewRequest(http.MethodGet, "/poll?sessionId="+idle+"&timeout=0", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected idle session to expire, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.Poll(rec, httptest.NewRequest(http.MethodDelete, "/poll?sessionId="+active, nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 on disconnect, got %d", rec.Code)
+	}
+}
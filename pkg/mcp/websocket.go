@@ -0,0 +1,235 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/tracing"
+)
+
+const (
+	// WebSocketPingInterval is how often the server pings a WebSocket client
+	WebSocketPingInterval = 30 * time.Second
+	// WebSocketPongWait is how long a WebSocket connection survives without
+	// a pong or message from the client
+	WebSocketPongWait = 2 * WebSocketPingInterval
+	// webSocketWriteWait bounds each write to a WebSocket connection
+	webSocketWriteWait = 10 * time.Second
+	// maxWebSocketMessageBytes bounds a single JSON-RPC message from a client
+	maxWebSocketMessageBytes = 4 << 20
+	// maxQueuedWebSocketMessages bounds each connection's unsent messages
+	maxQueuedWebSocketMessages = 100
+)
+
+// wsSession is an MCP client session bound to one WebSocket connection. Its
+// state lives as long as the connection.
+type wsSession struct {
+	id            string
+	notifications chan mcpgo.JSONRPCNotification
+	outgoing      chan interface{}
+	done          chan struct{}
+	closeOnce     sync.Once
+
+	mu          sync.Mutex
+	initialized bool
+	logLevel    mcpgo.LoggingLevel
+}
+
+func (s *wsSession) SessionID() string { return s.id }
+
+func (s *wsSession) NotificationChannel() chan<- mcpgo.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *wsSession) Initialize() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initialized = true
+}
+
+func (s *wsSession) Initialized() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.initialized
+}
+
+// SetLogLevel sets the minimum level of log notifications sent to the client
+func (s *wsSession) SetLogLevel(level mcpgo.LoggingLevel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logLevel = level
+}
+
+// GetLogLevel returns the minimum log notification level, error until the client sets one
+func (s *wsSession) GetLogLevel() mcpgo.LoggingLevel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.logLevel == "" {
+		return mcpgo.LoggingLevelError
+	}
+	return s.logLevel
+}
+
+// send queues a message for the connection's writer. It gives up once the
+// connection closes.
+func (s *wsSession) send(message interface{}) bool {
+	select {
+	case s.outgoing <- message:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// close marks the connection as finished, stopping its writer
+func (s *wsSession) close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// WebSocketHandler carries MCP JSON-RPC traffic over WebSocket for clients
+// behind proxies that buffer SSE streams. Each text message is one JSON-RPC
+// message in either direction, and every connection is its own MCP session.
+// The server pings every WebSocketPingInterval and drops connections that
+// stay silent for WebSocketPongWait.
+type WebSocketHandler struct {
+	mcpServer *server.MCPServer
+	logger    *common.CustomLogger
+	upgrader  websocket.Upgrader
+
+	pingInterval time.Duration
+	pongWait     time.Duration
+}
+
+// NewWebSocketHandler creates a WebSocket transport for the MCP server
+func NewWebSocketHandler(mcpServer *server.MCPServer, logger *common.CustomLogger) *WebSocketHandler {
+	return &WebSocketHandler{
+		mcpServer:    mcpServer,
+		logger:       logger,
+		pingInterval: WebSocketPingInterval,
+		pongWait:     WebSocketPongWait,
+	}
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and serves the
+// MCP session on it until either side closes it
+func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already written the error response
+		h.logger.Warn("WebSocket upgrade from %s failed: %v", r.RemoteAddr, err)
+		return
+	}
+	defer conn.Close()
+
+	sess := &wsSession{
+		id:            uuid.New().String(),
+		notifications: make(chan mcpgo.JSONRPCNotification, 100),
+		outgoing:      make(chan interface{}, maxQueuedWebSocketMessages),
+		done:          make(chan struct{}),
+	}
+	// The request context ends with the upgrade, so the session gets its own
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	defer cancel()
+	if err := h.mcpServer.RegisterSession(ctx, sess); err != nil {
+		h.logger.Error("Failed to register WebSocket session: %v", err)
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "failed to create session"), time.Now().Add(webSocketWriteWait))
+		return
+	}
+	h.logger.Info("WebSocket session connected: %s", sess.id)
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		h.writeMessages(conn, sess)
+	}()
+
+	h.readMessages(h.mcpServer.WithContext(ctx, sess), conn, sess)
+
+	sess.close()
+	cancel()
+	<-writerDone
+	h.mcpServer.UnregisterSession(context.Background(), sess.id)
+	h.logger.Info("WebSocket session disconnected: %s", sess.id)
+}
+
+// readMessages handles JSON-RPC messages from the client until the
+// connection fails or goes silent. Each message is handled on its own so a
+// long tool call neither delays other requests nor the reading of pongs.
+func (h *WebSocketHandler) readMessages(ctx context.Context, conn *websocket.Conn, sess *wsSession) {
+	conn.SetReadLimit(maxWebSocketMessageBytes)
+	conn.SetReadDeadline(time.Now().Add(h.pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(h.pongWait))
+	})
+
+	for {
+		messageType, body, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				h.logger.Warn("WebSocket session %s closed: %v", sess.id, err)
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(h.pongWait))
+
+		if messageType != websocket.TextMessage || !json.Valid(body) {
+			sess.send(mcpgo.NewJSONRPCError(mcpgo.NewRequestId(nil), mcpgo.PARSE_ERROR, "Invalid JSON", nil))
+			continue
+		}
+		go func() {
+			ctx, span := tracing.StartMessage(ctx, "websocket", body)
+			defer span.End()
+			if response := h.mcpServer.HandleMessage(ctx, body); response != nil {
+				sess.send(response)
+			}
+		}()
+	}
+}
+
+// writeMessages is the connection's only writer. It sends responses and
+// notifications in the order they are queued, and pings the client.
+func (h *WebSocketHandler) writeMessages(conn *websocket.Conn, sess *wsSession) {
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+
+	write := func(message interface{}) bool {
+		conn.SetWriteDeadline(time.Now().Add(webSocketWriteWait))
+		if err := conn.WriteJSON(message); err != nil {
+			h.logger.Warn("Failed to write to WebSocket session %s: %v", sess.id, err)
+			return false
+		}
+		return true
+	}
+
+	for {
+		select {
+		case message := <-sess.outgoing:
+			if !write(message) {
+				conn.Close()
+				return
+			}
+		case notification := <-sess.notifications:
+			if !write(notification) {
+				conn.Close()
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(webSocketWriteWait)); err != nil {
+				h.logger.Warn("Failed to ping WebSocket session %s: %v", sess.id, err)
+				conn.Close()
+				return
+			}
+		case <-sess.done:
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(webSocketWriteWait))
+			return
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func dialWebSocket(t *testing.T, h *WebSocketHandler) *websocket.Conn {
+	t.Helper()
+	httpServer := httptest.NewServer(h)
+	t.Cleanup(httpServer.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestWebSocketCarriesJSONRPC(t *testing.T) {
+	conn := dialWebSocket(t, NewWebSocketHandler(server.NewMCPServer("ws-test", "1.0.0"), common.GetLogger()))
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":7,"method":"ping"}`)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	var response struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := conn.ReadJSON(&response); err != nil || response.ID != 7 || response.Result == nil {
+		t.Fatalf("Expected the ping response, got %+v %v", response, err)
+	}
+
+	conn.WriteMessage(websocket.TextMessage, []byte(`{not json`))
+	var parseError struct {
+		Error struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	if err := conn.ReadJSON(&parseError); err != nil || parseError.Error.Code != -32700 {
+		t.Errorf("Expected a parse error, got %+v %v", parseError, err)
+	}
+}
+
+func TestWebSocketDropsSilentClients(t *testing.T) {
+	h := NewWebSocketHandler(server.NewMCPServer("ws-test", "1.0.0"), common.GetLogger())
+	h.pingInterval = 20 * time.Millisecond
+	h.pongWait = 100 * time.Millisecond
+
+	// A client that reads answers pings and stays connected
+	conn := dialWebSocket(t, h)
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	}()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := conn.ReadMessage(); err != nil {
+		t.Fatalf("Expected a responsive client to stay connected, got %v", err)
+	}
+
+	// A client that stops reading sends no pongs and is disconnected
+	silent := dialWebSocket(t, h)
+	time.Sleep(300 * time.Millisecond)
+	silent.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, _, err := silent.ReadMessage(); err != nil {
+			if _, ok := err.(*websocket.CloseError); !ok && strings.Contains(err.Error(), "timeout") {
+				t.Errorf("Expected the server to close the connection, got %v", err)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+)
+
+// Delivery defaults
+const (
+	// DefaultMaxAttempts is how many deliveries are tried before a notification is dead-lettered
+	DefaultMaxAttempts = 8
+	// DefaultBaseDelay is the wait before the first retry; each later retry doubles it
+	DefaultBaseDelay = 5 * time.Second
+	// DefaultMaxDelay caps the wait between retries
+	DefaultMaxDelay = 15 * time.Minute
+	// DefaultPollInterval is how often the worker looks for notifications that are due
+	DefaultPollInterval = 5 * time.Second
+)
+
+// Notification is an outbound message to a Slack incoming webhook or a
+// generic webhook, posted as JSON
+type Notification struct {
+	ID          string          `json:"id"`
+	Target      string          `json:"target"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	NextAttempt time.Time       `json:"nextAttempt"`
+	LastError   string          `json:"lastError,omitempty"`
+	DeadAt      *time.Time      `json:"deadAt,omitempty"`
+}
+
+// Queue persists outbound notifications in the state store and delivers them
+// with exponential backoff, so a target that is briefly down does not lose
+// messages. Notifications that exhaust their attempts, or that the target
+// rejects outright, are moved to a dead-letter bucket for inspection and retry.
+type Queue struct {
+	store       *store.Store
+	httpClient  *http.Client
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	now         func() time.Time
+	wake        chan struct{}
+	mu          sync.Mutex
+	logger      *common.CustomLogger
+}
+
+// NewQueue creates a queue backed by the given store. Deliveries obey the
+// configured egress allowlist.
+func NewQueue(s *store.Store, config *common.Config) *Queue {
+	return &Queue{
+		store: s,
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: client.NewEgressPolicy(config).Transport(nil),
+		},
+		maxAttempts: DefaultMaxAttempts,
+		baseDelay:   DefaultBaseDelay,
+		maxDelay:    DefaultMaxDelay,
+		now:         time.Now,
+		wake:        make(chan struct{}, 1),
+		logger:      common.GetLogger(),
+	}
+}
+
+// Enqueue persists a notification for delivery to target. payload is sent as
+// JSON; a Slack incoming webhook expects e.g. {"text": "..."}.
+func (q *Queue) Enqueue(target string, payload interface{}) (*Notification, error) {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil, fmt.Errorf("invalid notification target %q: must be an absolute http(s) URL", target)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notification payload: %w", err)
+	}
+
+	now := q.now().UTC()
+	n := &Notification{
+		ID:          newNotificationID(now),
+		Target:      target,
+		Payload:     body,
+		CreatedAt:   now,
+		NextAttempt: now,
+	}
+	if err := q.store.Put(store.BucketNotifications, n.ID, n); err != nil {
+		return nil, fmt.Errorf("failed to persist notification: %w", err)
+	}
+
+	q.logger.Info("Queued notification %s for %s", n.ID, u.Host)
+	q.signal()
+	return n, nil
+}
+
+// Run delivers due notifications until ctx is cancelled, checking every
+// interval and immediately after each Enqueue or Retry
+func (q *Queue) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		q.ProcessDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// ProcessDue attempts every notification whose next attempt is due and
+// returns how many were delivered
+func (q *Queue) ProcessDue(ctx context.Context) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending, err := q.list(store.BucketNotifications)
+	if err != nil {
+		q.logger.Error("Failed to load notification queue: %v", err)
+		return 0
+	}
+
+	delivered := 0
+	for _, n := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		if n.NextAttempt.After(q.now()) {
+			continue
+		}
+		if q.attempt(ctx, n) {
+			delivered++
+		}
+	}
+	return delivered
+}
+
+// attempt delivers one notification and records the outcome
+func (q *Queue) attempt(ctx context.Context, n Notification) bool {
+	n.Attempts++
+	err := q.deliver(ctx, n)
+	if err == nil {
+		if err := q.store.Delete(store.BucketNotifications, n.ID); err != nil {
+			q.logger.Error("Failed to remove delivered notification %s: %v", n.ID, err)
+		}
+		q.logger.Info("Delivered notification %s after %d attempt(s)", n.ID, n.Attempts)
+		return true
+	}
+
+	n.LastError = err.Error()
+	var permanent *permanentError
+	if errors.As(err, &permanent) || n.Attempts >= q.maxAttempts {
+		dead := q.now().UTC()
+		n.DeadAt = &dead
+		if err := q.store.Move(store.BucketNotifications, store.BucketDeadLetters, n.ID, n); err != nil {
+			q.logger.Error("Failed to dead-letter notification %s: %v", n.ID, err)
+			return false
+		}
+		q.logger.Error("Notification %s dead-lettered after %d attempt(s): %v", n.ID, n.Attempts, err)
+		return false
+	}
+
+	n.NextAttempt = q.now().UTC().Add(q.backoff(n.Attempts))
+	if err := q.store.Put(store.BucketNotifications, n.ID, n); err != nil {
+		q.logger.Error("Failed to reschedule notification %s: %v", n.ID, err)
+		return false
+	}
+	q.logger.Warn("Notification %s attempt %d failed, retrying at %s: %v", n.ID, n.Attempts, n.NextAttempt.Format(time.RFC3339), err)
+	return false
+}
+
+// permanentError is a delivery failure that retrying cannot fix
+type permanentError struct {
+	reason string
+}
+
+// Error implements the error interface
+func (e *permanentError) Error() string {
+	return e.reason
+}
+
+// deliver posts the notification payload to its target
+func (q *Queue) deliver(ctx context.Context, n Notification) error {
+	ctx, cancel := context.WithTimeout(ctx, q.httpClient.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.Target, bytes.NewReader(n.Payload))
+	if err != nil {
+		return &permanentError{reason: fmt.Sprintf("invalid notification request: %v", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := q.httpClient.Do(req)
+	if err != nil {
+		var egressErr *client.EgressError
+		if errors.As(err, &egressErr) {
+			return &permanentError{reason: fmt.Sprintf("%v (add the host to egress.allowed_hosts)", egressErr)}
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("target returned HTTP %d", resp.StatusCode)
+	default:
+		return &permanentError{reason: fmt.Sprintf("target rejected the notification with HTTP %d", resp.StatusCode)}
+	}
+}
+
+// backoff returns the wait after the given number of failed attempts
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.baseDelay
+	for i := 1; i < attempts && delay < q.maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, q.maxDelay)
+}
+
+// Pending returns the notifications awaiting delivery, oldest first
+func (q *Queue) Pending() ([]Notification, error) {
+	return q.list(store.BucketNotifications)
+}
+
+// DeadLetters returns the notifications that could not be delivered, oldest first
+func (q *Queue) DeadLetters() ([]Notification, error) {
+	return q.list(store.BucketDeadLetters)
+}
+
+// Retry moves a dead-lettered notification back onto the queue with a fresh
+// set of attempts and schedules it for immediate delivery
+func (q *Queue) Retry(id string) (*Notification, error) {
+	var n Notification
+	if err := q.store.Get(store.BucketDeadLetters, id, &n); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("no dead-lettered notification with id %s", id)
+		}
+		return nil, fmt.Errorf("failed to load notification %s: %w", id, err)
+	}
+
+	n.Attempts = 0
+	n.DeadAt = nil
+	n.NextAttempt = q.now().UTC()
+	if err := q.store.Move(store.BucketDeadLetters, store.BucketNotifications, id, n); err != nil {
+		return nil, fmt.Errorf("failed to requeue notification %s: %w", id, err)
+	}
+
+	q.logger.Info("Requeued dead-lettered notification %s", id)
+	q.signal()
+	return &n, nil
+}
+
+// list decodes every notification in a bucket
+func (q *Queue) list(bucket string) ([]Notification, error) {
+	notifications := make([]Notification, 0)
+	err := q.store.ForEach(bucket, func(key string, raw []byte) error {
+		var n Notification
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return fmt.Errorf("failed to decode notification %s: %w", key, err)
+		}
+		notifications = append(notifications, n)
+		return nil
+	})
+	return notifications, err
+}
+
+// signal wakes the worker without blocking
+func (q *Queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// newNotificationID returns an ID that sorts by creation time, so store key
+// order is delivery order
+func newNotificationID(now time.Time) string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("n-%s-%s", now.Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+}
+
+// Global queue instance
+var (
+	defaultQueue *Queue
+	defaultMu    sync.RWMutex
+)
+
+// SetDefault sets the process-wide notification queue
+func SetDefault(q *Queue) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultQueue = q
+}
+
+// Default returns the process-wide notification queue, or nil if persistence is unavailable
+func Default() *Queue {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultQueue
+}
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+)
+
+// newTestQueue opens a queue over a fresh store that may post to target
+func newTestQueue(t *testing.T, path, target string) (*Queue, *time.Time) {
+	t.Helper()
+	s, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	u, _ := url.Parse(target)
+	q := NewQueue(s, &common.Config{Egress: common.EgressConfig{AllowedHosts: []string{u.Hostname()}}})
+	now := time.Now()
+	q.now = func() time.Time { return now }
+	q.maxAttempts = 3
+	return q, &now
+}
+
+func TestQueueRetriesWithBackoff(t *testing.T) {
+	var calls atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	q, now := newTestQueue(t, filepath.Join(t.TempDir(), "state.db"), target.URL)
+	if _, err := q.Enqueue(target.URL, map[string]string{"text": "disk full on web-01"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	if q.ProcessDue(context.Background()) != 0 {
+		t.Fatal("Expected the first attempt to fail")
+	}
+	pending, _ := q.Pending()
+	if len(pending) != 1 || pending[0].Attempts != 1 || !pending[0].NextAttempt.Equal(now.UTC().Add(DefaultBaseDelay)) {
+		t.Fatalf("Expected a retry after %s, got %+v", DefaultBaseDelay, pending)
+	}
+
+	// Not due yet
+	q.ProcessDue(context.Background())
+	if calls.Load() != 1 {
+		t.Fatalf("Expected no attempt before the retry is due, got %d calls", calls.Load())
+	}
+
+	*now = now.Add(DefaultBaseDelay)
+	q.ProcessDue(context.Background())
+	pending, _ = q.Pending()
+	if len(pending) != 1 || !pending[0].NextAttempt.Equal(now.UTC().Add(2*DefaultBaseDelay)) {
+		t.Fatalf("Expected the retry delay to double, got %+v", pending)
+	}
+
+	*now = now.Add(2 * DefaultBaseDelay)
+	if q.ProcessDue(context.Background()) != 1 {
+		t.Fatal("Expected the third attempt to deliver")
+	}
+	if pending, _ = q.Pending(); len(pending) != 0 {
+		t.Errorf("Expected an empty queue, got %+v", pending)
+	}
+}
+
+func TestQueueDeadLettersAndRetry(t *testing.T) {
+	var healthy atomic.Bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	path := filepath.Join(t.TempDir(), "state.db")
+	q, _ := newTestQueue(t, path, target.URL)
+	n, err := q.Enqueue(target.URL+"/hooks/ops", map[string]string{"text": "hello"})
+	if err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	// A 404 cannot succeed on retry, so it is dead-lettered immediately
+	q.ProcessDue(context.Background())
+	dead, _ := q.DeadLetters()
+	if len(dead) != 1 || dead[0].ID != n.ID || dead[0].Attempts != 1 || dead[0].DeadAt == nil {
+		t.Fatalf("Expected the notification to be dead-lettered, got %+v", dead)
+	}
+
+	if _, err := q.Retry("n-missing"); err == nil {
+		t.Error("Expected an error retrying an unknown notification")
+	}
+
+	healthy.Store(true)
+	if _, err := q.Retry(n.ID); err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+	if dead, _ = q.DeadLetters(); len(dead) != 0 {
+		t.Errorf("Expected the dead-letter list to be empty after retry, got %+v", dead)
+	}
+	if q.ProcessDue(context.Background()) != 1 {
+		t.Error("Expected the requeued notification to be delivered")
+	}
+}
+
+func TestQueueSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	s, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	q := NewQueue(s, &common.Config{})
+	if _, err := q.Enqueue("https://hooks.slack.com/services/T0/B0/x", map[string]string{"text": "hello"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	s.Close()
+
+	s, err = store.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer s.Close()
+	pending, err := NewQueue(s, &common.Config{}).Pending()
+	if err != nil || len(pending) != 1 {
+		t.Errorf("Expected the queued notification to survive a restart, got %+v (%v)", pending, err)
+	}
+}
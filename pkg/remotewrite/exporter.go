@@ -0,0 +1,270 @@
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// metricPrefix namespaces exported OpsRamp metrics in Prometheus
+	metricPrefix = "opsramp_"
+	// maxBackfillIntervals bounds how far back a pull reaches after failed writes
+	maxBackfillIntervals = 10
+)
+
+// invalidMetricChars matches characters not allowed in Prometheus metric names
+var invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+// MetricsSource fetches metric data points for a resource; the resources
+// tool's OpsRamp API implements it
+type MetricsSource interface {
+	GetMetrics(ctx context.Context, id string, request types.ResourceMetricsRequest) (*types.ResourceMetricsResponse, error)
+}
+
+// Exporter periodically pulls the configured metrics for the configured
+// resources and forwards new samples to a Prometheus remote_write endpoint.
+// Each interval covers the time since the previous pull, and samples older
+// than the last one exported for their series are dropped, since remote_write
+// receivers reject out-of-order samples.
+type Exporter struct {
+	config     common.RemoteWriteConfig
+	source     MetricsSource
+	httpClient *http.Client
+	interval   time.Duration
+	now        func() time.Time
+	logger     *common.CustomLogger
+	// staticLabels are the tenant and environment labels plus the configured
+	// labels, which take precedence
+	staticLabels map[string]string
+
+	mu         sync.Mutex
+	lastPull   time.Time
+	lastSample map[string]int64
+}
+
+// NewExporter creates an exporter for the configuration. Writes obey the
+// egress allowlist, which always includes the remote_write host.
+func NewExporter(config *common.Config, source MetricsSource) *Exporter {
+	interval := time.Duration(config.RemoteWrite.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = common.DefaultRemoteWriteInterval * time.Second
+	}
+	staticLabels := config.TelemetryLabels("default")
+	for name, value := range config.RemoteWrite.Labels {
+		staticLabels[name] = value
+	}
+	return &Exporter{
+		config: config.RemoteWrite,
+		source: source,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: client.NewEgressPolicy(config).Transport(nil),
+		},
+		interval:     interval,
+		now:          time.Now,
+		logger:       common.GetLogger(),
+		lastSample:   make(map[string]int64),
+		staticLabels: staticLabels,
+	}
+}
+
+// Run exports once per interval until ctx is cancelled
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Export(ctx); err != nil {
+			e.logger.Warn("Remote write export failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Export pulls the metrics recorded since the previous pull and writes the new
+// samples. A resource whose metrics cannot be fetched is skipped and retried
+// on the next pull; a failed write is reported and its samples are retried
+// on the next pull.
+func (e *Exporter) Export(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	end := e.now().UTC()
+	start := e.lastPull
+	if start.IsZero() {
+		start = end.Add(-e.interval)
+	}
+	if earliest := end.Add(-maxBackfillIntervals * e.interval); start.Before(earliest) {
+		start = earliest
+	}
+
+	series := make([]Series, 0)
+	pending := make(map[string]int64)
+	for _, resourceID := range e.config.Resources {
+		response, err := e.source.GetMetrics(ctx, resourceID, types.ResourceMetricsRequest{
+			MetricNames: e.config.Metrics,
+			StartTime:   start.Format(time.RFC3339),
+			EndTime:     end.Format(time.RFC3339),
+		})
+		if err != nil {
+			e.logger.Warn("Remote write: skipping metrics of resource %s: %v", resourceID, err)
+			continue
+		}
+
+		for _, s := range e.toSeries(resourceID, response.Metrics) {
+			key := seriesKey(s.Labels)
+			samples := s.Samples[:0]
+			for _, sample := range s.Samples {
+				if sample.Timestamp > e.lastSample[key] {
+					samples = append(samples, sample)
+				}
+			}
+			if len(samples) == 0 {
+				continue
+			}
+			s.Samples = samples
+			series = append(series, s)
+			pending[key] = samples[len(samples)-1].Timestamp
+		}
+	}
+
+	if len(series) > 0 {
+		if err := e.write(ctx, series); err != nil {
+			return err
+		}
+	}
+
+	for key, timestamp := range pending {
+		e.lastSample[key] = timestamp
+	}
+	e.lastPull = end
+	e.logger.Debug("Remote write exported %d series from %d resources", len(series), len(e.config.Resources))
+	return nil
+}
+
+// toSeries groups a resource's data points into one series per metric and
+// instance, labelled with the resource, tenant, environment and configured labels
+func (e *Exporter) toSeries(resourceID string, points []types.ResourceMetricDataPoint) []Series {
+	byName := make(map[string]*Series)
+	names := make([]string, 0)
+	for _, point := range points {
+		timestamp, ok := parseTimestamp(point.Timestamp)
+		if !ok {
+			e.logger.Debug("Remote write: dropping %s sample with timestamp %q", point.Name, point.Timestamp)
+			continue
+		}
+		// Each instance of a dimensioned metric is its own series
+		name := point.Name + "\x00" + point.Instance
+		s, ok := byName[name]
+		if !ok {
+			s = &Series{Labels: e.labels(point.Name, resourceID, point.Instance)}
+			byName[name] = s
+			names = append(names, name)
+		}
+		s.Samples = append(s.Samples, Sample{Value: point.Value, Timestamp: timestamp})
+	}
+
+	series := make([]Series, 0, len(names))
+	for _, name := range names {
+		s := byName[name]
+		sort.Slice(s.Samples, func(i, j int) bool { return s.Samples[i].Timestamp < s.Samples[j].Timestamp })
+		series = append(series, *s)
+	}
+	return series
+}
+
+// labels returns the label set of a resource's metric, labelled with the
+// metric instance when the metric is dimensioned
+func (e *Exporter) labels(metric, resourceID, instance string) []Label {
+	labels := make([]Label, 0, len(e.staticLabels)+3)
+	for name, value := range e.staticLabels {
+		if name != "__name__" && name != "resource_id" && (name != "instance" || instance == "") {
+			labels = append(labels, Label{Name: name, Value: value})
+		}
+	}
+	labels = append(labels,
+		Label{Name: "__name__", Value: MetricName(metric)},
+		Label{Name: "resource_id", Value: resourceID},
+	)
+	if instance != "" {
+		labels = append(labels, Label{Name: "instance", Value: instance})
+	}
+	return labels
+}
+
+// write posts the series to the remote_write endpoint
+func (e *Exporter) write(ctx context.Context, series []Series) error {
+	body := snappyEncode(encodeWriteRequest(series))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build remote write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	req.Header.Set("User-Agent", "or-mcp-remote-write")
+	if e.config.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+e.config.BearerToken)
+	} else if e.config.Username != "" {
+		req.SetBasicAuth(e.config.Username, e.config.Password)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote write failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("remote write rejected with status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
+
+// MetricName converts an OpsRamp metric name such as system.cpu.utilization
+// to a Prometheus metric name such as opsramp_system_cpu_utilization
+func MetricName(name string) string {
+	return metricPrefix + invalidMetricChars.ReplaceAllString(name, "_")
+}
+
+// parseTimestamp parses an RFC3339 or Unix-seconds data point timestamp into
+// Unix milliseconds
+func parseTimestamp(value string) (int64, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UnixMilli(), true
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return int64(seconds * 1000), true
+	}
+	return 0, false
+}
+
+// seriesKey identifies a series by its label values
+func seriesKey(labels []Label) string {
+	sorted := append([]Label(nil), labels...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	var key bytes.Buffer
+	for _, label := range sorted {
+		key.WriteString(label.Name)
+		key.WriteByte('=')
+		key.WriteString(label.Value)
+		key.WriteByte(0)
+	}
+	return key.String()
+}
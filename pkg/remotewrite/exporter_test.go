@@ -0,0 +1,195 @@
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// fakeMetricsSource serves fixed data points per resource
+type fakeMetricsSource struct {
+	points   map[string][]types.ResourceMetricDataPoint
+	requests []types.ResourceMetricsRequest
+}
+
+func (f *fakeMetricsSource) GetMetrics(ctx context.Context, id string, request types.ResourceMetricsRequest) (*types.ResourceMetricsResponse, error) {
+	f.requests = append(f.requests, request)
+	points, ok := f.points[id]
+	if !ok {
+		return nil, fmt.Errorf("resource %s not found", id)
+	}
+	return &types.ResourceMetricsResponse{ResourceID: id, Metrics: points}, nil
+}
+
+// snappyDecode decodes the literal-only snappy blocks written by snappyEncode
+func snappyDecode(t *testing.T, data []byte) []byte {
+	t.Helper()
+	length, n := binary.Uvarint(data)
+	data = data[n:]
+	var out []byte
+	for len(data) > 0 {
+		tag := data[0]
+		if tag&3 != 0 {
+			t.Fatalf("Unexpected non-literal snappy element %#x", tag)
+		}
+		size := int(tag >> 2)
+		data = data[1:]
+		switch size {
+		case 60:
+			size = int(data[0])
+			data = data[1:]
+		case 61:
+			size = int(data[0]) | int(data[1])<<8
+			data = data[2:]
+		}
+		size++
+		out = append(out, data[:size]...)
+		data = data[size:]
+	}
+	if uint64(len(out)) != length {
+		t.Fatalf("Decoded %d bytes, header says %d", len(out), length)
+	}
+	return out
+}
+
+func TestSnappyEncodeRoundTrip(t *testing.T) {
+	for _, size := range []int{0, 1, 60, 61, 300, maxSnappyLiteral + 5} {
+		data := bytes.Repeat([]byte{'x'}, size)
+		if got := snappyDecode(t, snappyEncode(data)); !bytes.Equal(got, data) {
+			t.Errorf("Round trip of %d bytes failed", size)
+		}
+	}
+}
+
+func TestEncodeWriteRequest(t *testing.T) {
+	got := encodeWriteRequest([]Series{{
+		Labels:  []Label{{Name: "job", Value: "a"}, {Name: "__name__", Value: "m"}},
+		Samples: []Sample{{Value: 1, Timestamp: 1}},
+	}})
+	want := []byte{
+		0x0a, 0x26, // timeseries, 38 bytes
+		0x0a, 0x0d, 0x0a, 0x08, '_', '_', 'n', 'a', 'm', 'e', '_', '_', 0x12, 0x01, 'm', // __name__="m"
+		0x0a, 0x08, 0x0a, 0x03, 'j', 'o', 'b', 0x12, 0x01, 'a', // job="a"
+		0x12, 0x0b, 0x09, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 0x10, 0x01, // value 1.0, timestamp 1
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("encodeWriteRequest = % x\nwant                 % x", got, want)
+	}
+}
+
+func TestExporterWritesNewSamples(t *testing.T) {
+	var bodies [][]byte
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "snappy" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Unexpected headers: %v", r.Header)
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, snappyDecode(t, body))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	source := &fakeMetricsSource{points: map[string][]types.ResourceMetricDataPoint{
+		"r1": {
+			{Name: "system.cpu.utilization", Timestamp: "2026-10-16T11:59:30Z", Value: 40},
+			{Name: "system.cpu.utilization", Timestamp: "2026-10-16T11:59:00Z", Value: 20},
+			{Name: "system.memory.utilization", Timestamp: "1792152000", Value: 75},
+		},
+	}}
+	exporter := NewExporter(&common.Config{OpsRamp: common.OpsRampConfig{Environment: "production"}, RemoteWrite: common.RemoteWriteConfig{
+		URL:         server.URL,
+		Resources:   []string{"r1", "missing"},
+		Metrics:     []string{"system.cpu.utilization", "system.memory.utilization"},
+		Labels:      map[string]string{"source": "opsramp"},
+		BearerToken: "secret",
+	}}, source)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	exporter.now = func() time.Time { return now }
+
+	if err := exporter.Export(context.Background()); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("Expected one write, got %d", len(bodies))
+	}
+	for _, want := range []string{"opsramp_system_cpu_utilization", "opsramp_system_memory_utilization", "resource_id", "source", "tenant", "production"} {
+		if !bytes.Contains(bodies[0], []byte(want)) {
+			t.Errorf("Expected write to contain %q", want)
+		}
+	}
+	if source.requests[0].StartTime != "2026-10-16T11:59:00Z" || source.requests[0].EndTime != "2026-10-16T12:00:00Z" {
+		t.Errorf("Unexpected first window %s..%s", source.requests[0].StartTime, source.requests[0].EndTime)
+	}
+
+	// The same samples are not written twice
+	now = now.Add(time.Minute)
+	if err := exporter.Export(context.Background()); err != nil {
+		t.Fatalf("Second export failed: %v", err)
+	}
+	if len(bodies) != 1 {
+		t.Errorf("Expected no write without new samples, got %d writes", len(bodies))
+	}
+	if last := source.requests[len(source.requests)-1]; last.StartTime != "2026-10-16T12:00:00Z" {
+		t.Errorf("Expected the next window to start at the previous pull, got %s", last.StartTime)
+	}
+
+	// Rejected writes are reported and retried with the next pull
+	source.points["r1"] = append(source.points["r1"], types.ResourceMetricDataPoint{
+		Name: "system.cpu.utilization", Timestamp: "2026-10-16T12:01:30Z", Value: 50,
+	})
+	status = http.StatusBadRequest
+	now = now.Add(time.Minute)
+	if err := exporter.Export(context.Background()); err == nil {
+		t.Fatal("Expected rejected write to fail")
+	}
+	status = http.StatusNoContent
+	now = now.Add(time.Minute)
+	if err := exporter.Export(context.Background()); err != nil {
+		t.Fatalf("Retry export failed: %v", err)
+	}
+	if len(bodies) != 3 {
+		t.Errorf("Expected the rejected samples to be written again, got %d writes", len(bodies))
+	}
+}
+
+func TestExporterSeriesPerInstance(t *testing.T) {
+	exporter := NewExporter(&common.Config{}, &fakeMetricsSource{})
+	series := exporter.toSeries("r1", []types.ResourceMetricDataPoint{
+		{Name: "system.disk.usage", Instance: "/", Timestamp: "2026-10-16T11:59:00Z", Value: 40},
+		{Name: "system.disk.usage", Instance: "/var", Timestamp: "2026-10-16T11:59:00Z", Value: 90},
+		{Name: "system.disk.usage", Instance: "/", Timestamp: "2026-10-16T11:59:30Z", Value: 41},
+	})
+	if len(series) != 2 {
+		t.Fatalf("Expected one series per disk, got %d", len(series))
+	}
+	for i, want := range []string{"/", "/var"} {
+		instance := ""
+		for _, label := range series[i].Labels {
+			if label.Name == "instance" {
+				instance = label.Value
+			}
+		}
+		if instance != want {
+			t.Errorf("Expected series %d to be labelled instance=%s, got %q", i, want, instance)
+		}
+	}
+	if len(series[0].Samples) != 2 || len(series[1].Samples) != 1 {
+		t.Errorf("Unexpected samples per instance: %d and %d", len(series[0].Samples), len(series[1].Samples))
+	}
+}
+
+func TestMetricName(t *testing.T) {
+	if got := MetricName("system.disk.utilization-pct"); got != "opsramp_system_disk_utilization_pct" {
+		t.Errorf("MetricName = %s", got)
+	}
+}
@@ -0,0 +1,108 @@
+package remotewrite
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// Label is a Prometheus series label
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is one value of a series at a Unix timestamp in milliseconds
+type Sample struct {
+	Value     float64
+	Timestamp int64
+}
+
+// Series is a labelled series of samples in timestamp order
+type Series struct {
+	Labels  []Label
+	Samples []Sample
+}
+
+// The remote_write protocol (v1) posts a snappy-compressed protobuf
+// prometheus.WriteRequest. The messages involved are small enough to encode
+// by hand:
+//
+//	WriteRequest { repeated TimeSeries timeseries = 1; }
+//	TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	Label        { string name = 1; string value = 2; }
+//	Sample       { double value = 1; int64 timestamp = 2; }
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// encodeWriteRequest encodes series as a protobuf WriteRequest. Labels are
+// sorted by name, as the protocol requires.
+func encodeWriteRequest(series []Series) []byte {
+	var request []byte
+	for _, s := range series {
+		labels := append([]Label(nil), s.Labels...)
+		sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+
+		var ts []byte
+		for _, label := range labels {
+			var l []byte
+			l = appendBytesField(l, 1, []byte(label.Name))
+			l = appendBytesField(l, 2, []byte(label.Value))
+			ts = appendBytesField(ts, 1, l)
+		}
+		for _, sample := range s.Samples {
+			var m []byte
+			m = appendTag(m, 1, wireFixed64)
+			m = binary.LittleEndian.AppendUint64(m, math.Float64bits(sample.Value))
+			m = appendTag(m, 2, wireVarint)
+			m = binary.AppendUvarint(m, uint64(sample.Timestamp))
+			ts = appendBytesField(ts, 2, m)
+		}
+		request = appendBytesField(request, 1, ts)
+	}
+	return request
+}
+
+// appendTag appends a protobuf field key
+func appendTag(b []byte, field int, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(field<<3|wireType))
+}
+
+// appendBytesField appends a length-delimited protobuf field
+func appendBytesField(b []byte, field int, value []byte) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+// maxSnappyLiteral bounds the length of one snappy literal element
+const maxSnappyLiteral = 1 << 16
+
+// snappyEncode frames data in the snappy block format using literal elements
+// only. The output is valid snappy that any decoder accepts; payloads are a
+// few kilobytes per interval, so compressing them is not worth a dependency.
+func snappyEncode(data []byte) []byte {
+	out := binary.AppendUvarint(nil, uint64(len(data)))
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > maxSnappyLiteral {
+			chunk = chunk[:maxSnappyLiteral]
+		}
+		data = data[len(chunk):]
+
+		n := len(chunk) - 1
+		switch {
+		case n < 60:
+			out = append(out, byte(n)<<2)
+		case n < 1<<8:
+			out = append(out, 60<<2, byte(n))
+		default:
+			out = append(out, 61<<2, byte(n), byte(n>>8))
+		}
+		out = append(out, chunk...)
+	}
+	return out
+}
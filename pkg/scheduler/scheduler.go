@@ -0,0 +1,291 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+)
+
+// Scheduling defaults
+const (
+	// DefaultPollInterval is how often the worker looks for entries that are due
+	DefaultPollInterval = 15 * time.Second
+	// DefaultMaxAttempts is how many times a due entry is tried before it is marked failed
+	DefaultMaxAttempts = 3
+	// DefaultRetryDelay is the wait between attempts of a failing entry
+	DefaultRetryDelay = time.Minute
+	// MaxScheduleAhead bounds how far in the future an entry may be scheduled
+	MaxScheduleAhead = 30 * 24 * time.Hour
+	// executionTimeout bounds a single attempt
+	executionTimeout = time.Minute
+)
+
+// Status is the state of a schedule entry
+type Status string
+
+const (
+	// StatusPending means the entry has not run yet, or is waiting for a retry
+	StatusPending Status = "pending"
+	// StatusFailed means every attempt failed; the entry stays listed until cancelled
+	StatusFailed Status = "failed"
+)
+
+// Entry is an action scheduled to run at a future time. Entries are removed
+// once they run successfully.
+type Entry struct {
+	ID     string `json:"id"`
+	Action string `json:"action"`
+	// Target identifies the object the action applies to, e.g. an integration ID
+	Target    string     `json:"target"`
+	Tenant    string     `json:"tenant,omitempty"`
+	RunAt     time.Time  `json:"runAt"`
+	Note      string     `json:"note,omitempty"`
+	CreatedBy string     `json:"createdBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Status    Status     `json:"status"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"lastError,omitempty"`
+	FailedAt  *time.Time `json:"failedAt,omitempty"`
+}
+
+// Executor performs a scheduled action
+type Executor func(ctx context.Context, entry Entry) error
+
+var (
+	executors   = map[string]Executor{}
+	executorsMu sync.RWMutex
+)
+
+// RegisterExecutor sets the function that performs the named action. Tools
+// register their executors when they are created, so entries persisted by a
+// previous run execute once the owning tool is available again.
+func RegisterExecutor(action string, executor Executor) {
+	executorsMu.Lock()
+	defer executorsMu.Unlock()
+	executors[action] = executor
+}
+
+// lookupExecutor returns the executor registered for an action
+func lookupExecutor(action string) (Executor, bool) {
+	executorsMu.RLock()
+	defer executorsMu.RUnlock()
+	executor, ok := executors[action]
+	return executor, ok
+}
+
+// Scheduler persists schedule entries in the state store and runs them when
+// they are due, so schedules survive restarts. An entry missed while the
+// server was down runs as soon as the server is back.
+type Scheduler struct {
+	store       *store.Store
+	maxAttempts int
+	retryDelay  time.Duration
+	now         func() time.Time
+	wake        chan struct{}
+	mu          sync.Mutex
+	logger      *common.CustomLogger
+}
+
+// NewScheduler creates a scheduler backed by the given store
+func NewScheduler(s *store.Store) *Scheduler {
+	return &Scheduler{
+		store:       s,
+		maxAttempts: DefaultMaxAttempts,
+		retryDelay:  DefaultRetryDelay,
+		now:         time.Now,
+		wake:        make(chan struct{}, 1),
+		logger:      common.GetLogger(),
+	}
+}
+
+// Schedule persists an entry to run entry.Action on entry.Target at entry.RunAt
+func (s *Scheduler) Schedule(entry Entry) (*Entry, error) {
+	if _, ok := lookupExecutor(entry.Action); !ok {
+		return nil, fmt.Errorf("unknown scheduled action %q", entry.Action)
+	}
+	if entry.Target == "" {
+		return nil, fmt.Errorf("a target is required to schedule %s", entry.Action)
+	}
+	now := s.now().UTC()
+	if !entry.RunAt.After(now) {
+		return nil, fmt.Errorf("scheduled time %s is not in the future", entry.RunAt.UTC().Format(time.RFC3339))
+	}
+	if entry.RunAt.Sub(now) > MaxScheduleAhead {
+		return nil, fmt.Errorf("scheduled time %s is more than %d days ahead", entry.RunAt.UTC().Format(time.RFC3339), int(MaxScheduleAhead.Hours()/24))
+	}
+
+	entry.ID = newEntryID(now)
+	entry.RunAt = entry.RunAt.UTC()
+	entry.CreatedAt = now
+	entry.Status = StatusPending
+	entry.Attempts = 0
+	entry.LastError = ""
+	entry.FailedAt = nil
+	if err := s.store.Put(store.BucketSchedules, entry.ID, entry); err != nil {
+		return nil, fmt.Errorf("failed to persist schedule entry: %w", err)
+	}
+
+	s.logger.Info("Scheduled %s of %s at %s (%s)", entry.Action, entry.Target, entry.RunAt.Format(time.RFC3339), entry.ID)
+	s.signal()
+	return &entry, nil
+}
+
+// Run executes due entries until ctx is cancelled, checking every interval
+// and immediately after each Schedule
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.ProcessDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// ProcessDue runs every pending entry that is due and returns how many succeeded
+func (s *Scheduler) ProcessDue(ctx context.Context) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.List()
+	if err != nil {
+		s.logger.Error("Failed to load schedule entries: %v", err)
+		return 0
+	}
+
+	succeeded := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		if entry.Status != StatusPending || entry.RunAt.After(s.now()) {
+			continue
+		}
+		if s.execute(ctx, entry) {
+			succeeded++
+		}
+	}
+	return succeeded
+}
+
+// execute runs one due entry and records the outcome
+func (s *Scheduler) execute(ctx context.Context, entry Entry) bool {
+	executor, ok := lookupExecutor(entry.Action)
+	if !ok {
+		// The owning tool has not registered yet; try again on the next pass
+		s.logger.Debug("No executor for %s yet; deferring schedule entry %s", entry.Action, entry.ID)
+		return false
+	}
+
+	entry.Attempts++
+	runCtx, cancel := context.WithTimeout(ctx, executionTimeout)
+	err := executor(runCtx, entry)
+	cancel()
+
+	if err == nil {
+		if err := s.store.Delete(store.BucketSchedules, entry.ID); err != nil {
+			s.logger.Error("Failed to remove completed schedule entry %s: %v", entry.ID, err)
+		}
+		s.logger.Info("Ran scheduled %s of %s (%s), due at %s", entry.Action, entry.Target, entry.ID, entry.RunAt.Format(time.RFC3339))
+		return true
+	}
+
+	entry.LastError = err.Error()
+	if entry.Attempts >= s.maxAttempts {
+		failed := s.now().UTC()
+		entry.Status = StatusFailed
+		entry.FailedAt = &failed
+		s.logger.Error("Scheduled %s of %s (%s) failed after %d attempt(s): %v", entry.Action, entry.Target, entry.ID, entry.Attempts, err)
+	} else {
+		entry.RunAt = s.now().UTC().Add(s.retryDelay)
+		s.logger.Warn("Scheduled %s of %s (%s) attempt %d failed, retrying at %s: %v", entry.Action, entry.Target, entry.ID, entry.Attempts, entry.RunAt.Format(time.RFC3339), err)
+	}
+	if err := s.store.Put(store.BucketSchedules, entry.ID, entry); err != nil {
+		s.logger.Error("Failed to update schedule entry %s: %v", entry.ID, err)
+	}
+	return false
+}
+
+// List returns every pending and failed entry, soonest first
+func (s *Scheduler) List() ([]Entry, error) {
+	entries := make([]Entry, 0)
+	err := s.store.ForEach(store.BucketSchedules, func(key string, raw []byte) error {
+		var entry Entry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return fmt.Errorf("failed to decode schedule entry %s: %w", key, err)
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].RunAt.Before(entries[j].RunAt) })
+	return entries, err
+}
+
+// Cancel removes a pending or failed entry and returns it
+func (s *Scheduler) Cancel(id string) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entry Entry
+	if err := s.store.Get(store.BucketSchedules, id, &entry); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("no schedule entry with id %s; it may already have run", id)
+		}
+		return nil, fmt.Errorf("failed to load schedule entry %s: %w", id, err)
+	}
+	if err := s.store.Delete(store.BucketSchedules, id); err != nil {
+		return nil, fmt.Errorf("failed to cancel schedule entry %s: %w", id, err)
+	}
+
+	s.logger.Info("Cancelled scheduled %s of %s (%s)", entry.Action, entry.Target, id)
+	return &entry, nil
+}
+
+// signal wakes the worker without blocking
+func (s *Scheduler) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// newEntryID returns a unique schedule entry ID
+func newEntryID(now time.Time) string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("s-%s-%s", now.Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+}
+
+// Global scheduler instance
+var (
+	defaultScheduler *Scheduler
+	defaultMu        sync.RWMutex
+)
+
+// SetDefault sets the process-wide scheduler
+func SetDefault(s *Scheduler) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultScheduler = s
+}
+
+// Default returns the process-wide scheduler, or nil if persistence is unavailable
+func Default() *Scheduler {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultScheduler
+}
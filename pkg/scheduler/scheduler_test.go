@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+)
+
+// newTestScheduler opens a scheduler over a fresh store with a controllable clock
+func newTestScheduler(t *testing.T) (*Scheduler, *time.Time) {
+	t.Helper()
+	s, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	scheduler := NewScheduler(s)
+	now := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+	scheduler.now = func() time.Time { return now }
+	return scheduler, &now
+}
+
+func TestSchedulerRunsDueEntries(t *testing.T) {
+	var ran []string
+	RegisterExecutor("test.record", func(ctx context.Context, entry Entry) error {
+		ran = append(ran, entry.Target)
+		return nil
+	})
+
+	scheduler, now := newTestScheduler(t)
+	first, err := scheduler.Schedule(Entry{Action: "test.record", Target: "int-1", RunAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	if _, err := scheduler.Schedule(Entry{Action: "test.record", Target: "int-2", RunAt: now.Add(30 * time.Minute)}); err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+
+	entries, _ := scheduler.List()
+	if len(entries) != 2 || entries[0].Target != "int-2" || entries[1].ID != first.ID || entries[1].Status != StatusPending {
+		t.Fatalf("Expected two pending entries, soonest first, got %+v", entries)
+	}
+
+	if scheduler.ProcessDue(context.Background()) != 0 || len(ran) != 0 {
+		t.Fatal("Expected nothing to run before its time")
+	}
+
+	*now = now.Add(45 * time.Minute)
+	if scheduler.ProcessDue(context.Background()) != 1 || len(ran) != 1 || ran[0] != "int-2" {
+		t.Fatalf("Expected only int-2 to run, got %v", ran)
+	}
+	if entries, _ := scheduler.List(); len(entries) != 1 || entries[0].ID != first.ID {
+		t.Errorf("Expected the completed entry to be removed, got %+v", entries)
+	}
+}
+
+func TestSchedulerRetriesThenFails(t *testing.T) {
+	attempts := 0
+	RegisterExecutor("test.fail", func(ctx context.Context, entry Entry) error {
+		attempts++
+		return errors.New("integration not found")
+	})
+
+	scheduler, now := newTestScheduler(t)
+	entry, _ := scheduler.Schedule(Entry{Action: "test.fail", Target: "int-1", RunAt: now.Add(time.Minute)})
+
+	for i := 0; i < DefaultMaxAttempts; i++ {
+		*now = now.Add(DefaultRetryDelay + time.Minute)
+		scheduler.ProcessDue(context.Background())
+	}
+	entries, _ := scheduler.List()
+	if attempts != DefaultMaxAttempts || len(entries) != 1 || entries[0].Status != StatusFailed || entries[0].LastError != "integration not found" {
+		t.Fatalf("Expected a failed entry after %d attempts, got %d attempts and %+v", DefaultMaxAttempts, attempts, entries)
+	}
+
+	// Failed entries are not retried again, and can be cancelled
+	*now = now.Add(time.Hour)
+	scheduler.ProcessDue(context.Background())
+	if attempts != DefaultMaxAttempts {
+		t.Errorf("Expected no further attempts, got %d", attempts)
+	}
+	if _, err := scheduler.Cancel(entry.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if _, err := scheduler.Cancel(entry.ID); err == nil {
+		t.Error("Expected cancelling a removed entry to fail")
+	}
+}
+
+func TestSchedulerRejectsInvalidEntries(t *testing.T) {
+	RegisterExecutor("test.noop", func(ctx context.Context, entry Entry) error { return nil })
+	scheduler, now := newTestScheduler(t)
+
+	for name, entry := range map[string]Entry{
+		"unknown action": {Action: "test.unknown", Target: "int-1", RunAt: now.Add(time.Hour)},
+		"missing target": {Action: "test.noop", RunAt: now.Add(time.Hour)},
+		"in the past":    {Action: "test.noop", Target: "int-1", RunAt: now.Add(-time.Minute)},
+		"too far ahead":  {Action: "test.noop", Target: "int-1", RunAt: now.Add(MaxScheduleAhead + time.Hour)},
+	} {
+		if _, err := scheduler.Schedule(entry); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
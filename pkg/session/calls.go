@@ -0,0 +1,52 @@
+package session
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Call is a tool call that has not returned yet
+type Call struct {
+	Tool      string    `json:"tool"`
+	Action    string    `json:"action,omitempty"`
+	SessionID string    `json:"sessionId,omitempty"`
+	Started   time.Time `json:"started"`
+}
+
+// In-flight call registry
+var (
+	callsMu  sync.Mutex
+	calls    = make(map[uint64]Call)
+	nextCall uint64
+)
+
+// BeginCall records a tool call as in flight and returns the function that
+// marks it done
+func BeginCall(ctx context.Context, tool, action string) func() {
+	callsMu.Lock()
+	nextCall++
+	id := nextCall
+	calls[id] = Call{Tool: tool, Action: action, SessionID: IDFromContext(ctx), Started: time.Now()}
+	callsMu.Unlock()
+
+	return func() {
+		callsMu.Lock()
+		delete(calls, id)
+		callsMu.Unlock()
+	}
+}
+
+// InFlightCalls returns the tool calls currently running, oldest first
+func InFlightCalls() []Call {
+	callsMu.Lock()
+	inFlight := make([]Call, 0, len(calls))
+	for _, call := range calls {
+		inFlight = append(inFlight, call)
+	}
+	callsMu.Unlock()
+
+	sort.Slice(inFlight, func(i, j int) bool { return inFlight[i].Started.Before(inFlight[j].Started) })
+	return inFlight
+}
@@ -0,0 +1,215 @@
+package session
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// deliverySmoothing is the weight of the latest attempt in a session's
+	// smoothed delivery rate
+	deliverySmoothing = 0.3
+
+	// staleDeliveryRate is the smoothed delivery rate below which a session
+	// is considered stale
+	staleDeliveryRate = 0.5
+
+	// staleFailures is how many deliveries in a row may fail before a session
+	// is considered stale, and how many attempts a session needs before it
+	// is judged at all
+	staleFailures = 3
+)
+
+// DeliveryHealth is the record of notifications delivered to one client
+// session. Rate is an exponentially smoothed delivery success rate between 0
+// and 1, so a client that stops reading drops below the stale threshold
+// after a few heartbeats regardless of how long it was healthy before.
+type DeliveryHealth struct {
+	SessionID           string     `json:"sessionId"`
+	Rate                float64    `json:"deliveryRate"`
+	Attempts            int        `json:"attempts"`
+	Failures            int        `json:"failures"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastDelivered       *time.Time `json:"lastDelivered,omitempty"`
+	LastFailed          *time.Time `json:"lastFailed,omitempty"`
+	Connected           bool       `json:"connected"`
+	Stale               bool       `json:"stale"`
+}
+
+// stale reports whether the session's client appears to have stopped reading
+func (h *DeliveryHealth) stale() bool {
+	if h.Attempts < staleFailures {
+		return false
+	}
+	return h.Rate < staleDeliveryRate || h.ConsecutiveFailures >= staleFailures
+}
+
+// Delivery health registry
+var (
+	healthMu sync.Mutex
+	health   = make(map[string]*DeliveryHealth)
+)
+
+// RecordDelivery records whether a notification reached a session's
+// notification channel. A full channel means the client is not reading.
+func RecordDelivery(id string, delivered bool) {
+	now := time.Now()
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	h, ok := health[id]
+	if !ok {
+		h = &DeliveryHealth{SessionID: id, Rate: 1}
+		health[id] = h
+	}
+	sample := 0.0
+	if delivered {
+		sample = 1
+	}
+	h.Rate = deliverySmoothing*sample + (1-deliverySmoothing)*h.Rate
+	h.Attempts++
+	if delivered {
+		h.ConsecutiveFailures = 0
+		h.LastDelivered = &now
+		return
+	}
+	h.Failures++
+	h.ConsecutiveFailures++
+	h.LastFailed = &now
+}
+
+// Health returns a session's delivery health, or false when nothing has been
+// sent to it yet
+func Health(id string) (DeliveryHealth, bool) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	h, ok := health[id]
+	if !ok {
+		return DeliveryHealth{}, false
+	}
+	return snapshotHealth(h), true
+}
+
+// StaleSessions returns the sessions whose clients appear to have stopped
+// reading, lowest delivery rate first
+func StaleSessions() []DeliveryHealth {
+	healthMu.Lock()
+	stale := make([]DeliveryHealth, 0)
+	for _, h := range health {
+		if h.stale() {
+			stale = append(stale, snapshotHealth(h))
+		}
+	}
+	healthMu.Unlock()
+
+	sort.Slice(stale, func(i, j int) bool {
+		if stale[i].Rate != stale[j].Rate {
+			return stale[i].Rate < stale[j].Rate
+		}
+		return stale[i].SessionID < stale[j].SessionID
+	})
+	return stale
+}
+
+// snapshotHealth copies a health record for callers; healthMu must be held
+func snapshotHealth(h *DeliveryHealth) DeliveryHealth {
+	snapshot := *h
+	snapshot.Connected = IsConnected(h.SessionID)
+	snapshot.Stale = h.stale()
+	return snapshot
+}
+
+// forgetHealth discards the delivery health of a removed session
+func forgetHealth(id string) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	delete(health, id)
+}
+
+// Unregister hook for disconnecting stale sessions
+var (
+	unregisterMu   sync.RWMutex
+	unregisterFunc func(id string)
+)
+
+// SetUnregisterFunc sets how CleanupStale disconnects a session from the MCP
+// server. Without one, cleanup only discards the session's state here.
+func SetUnregisterFunc(fn func(id string)) {
+	unregisterMu.Lock()
+	defer unregisterMu.Unlock()
+	unregisterFunc = fn
+}
+
+// CleanupStale disconnects every stale session and discards its state,
+// returning the IDs of the sessions removed
+func CleanupStale() []string {
+	unregisterMu.RLock()
+	unregister := unregisterFunc
+	unregisterMu.RUnlock()
+
+	stale := StaleSessions()
+	ids := make([]string, 0, len(stale))
+	for _, h := range stale {
+		if unregister != nil {
+			unregister(h.SessionID)
+		}
+		// The unregister hook normally removes the session too; removing it
+		// here covers servers without one
+		Remove(h.SessionID)
+		ids = append(ids, h.SessionID)
+	}
+	return ids
+}
+
+// heartbeatMethod is the notification sent to probe whether a client is
+// still reading. Clients ignore notifications they do not know, and the
+// write keeps idle connections open through proxies.
+const heartbeatMethod = "notifications/heartbeat"
+
+// SendHeartbeats sends a heartbeat to every tracked, initialized client
+// session without blocking, recording whether each was delivered
+func SendHeartbeats() {
+	notification := mcp.JSONRPCNotification{
+		JSONRPC:      mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{Method: heartbeatMethod},
+	}
+	clients.Range(func(key, value interface{}) bool {
+		clientSession := value.(server.ClientSession)
+		if !clientSession.Initialized() {
+			return true
+		}
+		select {
+		case clientSession.NotificationChannel() <- notification:
+			RecordDelivery(key.(string), true)
+		default:
+			RecordDelivery(key.(string), false)
+		}
+		return true
+	})
+}
+
+// RunHeartbeats sends heartbeats at the given interval until the context is
+// done. With cleanup set, sessions that become stale are disconnected.
+func RunHeartbeats(ctx context.Context, interval time.Duration, cleanup bool, onCleanup func(ids []string)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			SendHeartbeats()
+			if !cleanup {
+				continue
+			}
+			if ids := CleanupStale(); len(ids) > 0 && onCleanup != nil {
+				onCleanup(ids)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHeartbeatsDetectStaleSessions(t *testing.T) {
+	reading := newLoggingClientSession("heartbeat-reading", mcp.LoggingLevelDebug)
+	stuck := newLoggingClientSession("heartbeat-stuck", mcp.LoggingLevelDebug)
+	stuck.notifications = make(chan mcp.JSONRPCNotification, 1)
+	TrackClient(reading)
+	TrackClient(stuck)
+	defer Remove(reading.id)
+	defer Remove(stuck.id)
+
+	for i := 0; i < 6; i++ {
+		SendHeartbeats()
+		<-reading.notifications
+	}
+
+	health, ok := Health(stuck.id)
+	if !ok || health.Attempts != 6 || health.ConsecutiveFailures != 5 || !health.Stale {
+		t.Fatalf("Expected the stuck session to be stale, got %+v", health)
+	}
+	if health, _ := Health(reading.id); health.Stale || health.Rate != 1 {
+		t.Errorf("Expected the reading session to be healthy, got %+v", health)
+	}
+	stale := StaleSessions()
+	if len(stale) != 1 || stale[0].SessionID != stuck.id {
+		t.Fatalf("Expected only the stuck session to be stale, got %+v", stale)
+	}
+
+	// One successful delivery does not clear a low smoothed rate
+	<-stuck.notifications
+	SendHeartbeats()
+	<-reading.notifications
+	if health, _ := Health(stuck.id); !health.Stale || health.ConsecutiveFailures != 0 {
+		t.Errorf("Expected the recovering session to stay stale, got %+v", health)
+	}
+
+	var unregistered []string
+	SetUnregisterFunc(func(id string) { unregistered = append(unregistered, id) })
+	defer SetUnregisterFunc(nil)
+	if removed := CleanupStale(); len(removed) != 1 || len(unregistered) != 1 || removed[0] != stuck.id {
+		t.Fatalf("Expected the stuck session to be cleaned up, got %v %v", removed, unregistered)
+	}
+	if _, ok := Health(stuck.id); ok || len(StaleSessions()) != 0 {
+		t.Error("Expected cleanup to discard the stale session's state")
+	}
+}
@@ -0,0 +1,95 @@
+package session
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// logLevelSeverity orders the MCP logging levels from least to most severe
+var logLevelSeverity = map[mcp.LoggingLevel]int{
+	mcp.LoggingLevelDebug:     0,
+	mcp.LoggingLevelInfo:      1,
+	mcp.LoggingLevelNotice:    2,
+	mcp.LoggingLevelWarning:   3,
+	mcp.LoggingLevelError:     4,
+	mcp.LoggingLevelCritical:  5,
+	mcp.LoggingLevelAlert:     6,
+	mcp.LoggingLevelEmergency: 7,
+}
+
+// clients holds the registered client sessions that can receive log notifications
+var clients sync.Map
+
+// TrackClient registers a client session to receive broadcast log notifications.
+// Untracking happens in Remove.
+func TrackClient(clientSession server.ClientSession) {
+	clients.Store(clientSession.SessionID(), clientSession)
+}
+
+// Log sends a notifications/message entry to the client session behind a
+// tool call, if the level is at or above the level the client selected with
+// logging/setLevel. Calls without a session are ignored.
+func Log(ctx context.Context, level mcp.LoggingLevel, logger string, data interface{}) {
+	if clientSession := server.ClientSessionFromContext(ctx); clientSession != nil {
+		sendLog(clientSession, level, logger, data)
+	}
+}
+
+// Broadcast sends a notifications/message entry to every tracked client
+// session whose selected level admits it
+func Broadcast(level mcp.LoggingLevel, logger string, data interface{}) {
+	clients.Range(func(_, value interface{}) bool {
+		sendLog(value.(server.ClientSession), level, logger, data)
+		return true
+	})
+}
+
+// Notify sends a notifications/message entry to one tracked client session,
+// if its selected level admits it, and reports whether it was sent
+func Notify(sessionID string, level mcp.LoggingLevel, logger string, data interface{}) bool {
+	value, ok := clients.Load(sessionID)
+	if !ok {
+		return false
+	}
+	return sendLog(value.(server.ClientSession), level, logger, data)
+}
+
+// sendLog delivers one log notification without blocking and reports whether
+// it was sent; a message is dropped when the session does not support
+// logging, has not finished initializing, filters the level, or has a full
+// notification channel
+func sendLog(clientSession server.ClientSession, level mcp.LoggingLevel, logger string, data interface{}) bool {
+	logging, ok := clientSession.(server.SessionWithLogging)
+	if !ok || !clientSession.Initialized() {
+		return false
+	}
+	if logLevelSeverity[level] < logLevelSeverity[logging.GetLogLevel()] {
+		return false
+	}
+
+	notification := mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: "notifications/message",
+			Params: mcp.NotificationParams{
+				AdditionalFields: map[string]interface{}{
+					"level":  level,
+					"logger": logger,
+					"data":   data,
+				},
+			},
+		},
+	}
+
+	select {
+	case clientSession.NotificationChannel() <- notification:
+		RecordDelivery(clientSession.SessionID(), true)
+		return true
+	default:
+		RecordDelivery(clientSession.SessionID(), false)
+		return false
+	}
+}
@@ -0,0 +1,69 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// loggingClientSession is a client session that supports logging/setLevel
+type loggingClientSession struct {
+	id            string
+	level         mcp.LoggingLevel
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *loggingClientSession) Initialize()       {}
+func (s *loggingClientSession) Initialized() bool { return true }
+func (s *loggingClientSession) SessionID() string { return s.id }
+func (s *loggingClientSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *loggingClientSession) SetLogLevel(level mcp.LoggingLevel) { s.level = level }
+func (s *loggingClientSession) GetLogLevel() mcp.LoggingLevel      { return s.level }
+
+func newLoggingClientSession(id string, level mcp.LoggingLevel) *loggingClientSession {
+	return &loggingClientSession{id: id, level: level, notifications: make(chan mcp.JSONRPCNotification, 10)}
+}
+
+func TestLogRespectsClientLevel(t *testing.T) {
+	clientSession := newLoggingClientSession("log-session", mcp.LoggingLevelWarning)
+	ctx := server.NewMCPServer("test", "1.0.0").WithContext(context.Background(), clientSession)
+
+	Log(ctx, mcp.LoggingLevelInfo, "tools", "filtered")
+	Log(ctx, mcp.LoggingLevelError, "tools", map[string]interface{}{"event": "toolError"})
+
+	if len(clientSession.notifications) != 1 {
+		t.Fatalf("Expected only the error to be sent, got %d notifications", len(clientSession.notifications))
+	}
+	notification := <-clientSession.notifications
+	params := notification.Params.AdditionalFields
+	if notification.Method != "notifications/message" || params["level"] != mcp.LoggingLevelError || params["logger"] != "tools" {
+		t.Errorf("Unexpected notification: %+v", notification)
+	}
+
+	// Calls without a session are ignored
+	Log(context.Background(), mcp.LoggingLevelEmergency, "tools", "no session")
+}
+
+func TestBroadcastReachesTrackedClients(t *testing.T) {
+	debug := newLoggingClientSession("broadcast-debug", mcp.LoggingLevelDebug)
+	errorsOnly := newLoggingClientSession("broadcast-error", mcp.LoggingLevelError)
+	TrackClient(debug)
+	TrackClient(errorsOnly)
+	defer Remove(errorsOnly.id)
+
+	Broadcast(mcp.LoggingLevelWarning, "tenants", map[string]interface{}{"event": "tenantDegraded"})
+	if len(debug.notifications) != 1 || len(errorsOnly.notifications) != 0 {
+		t.Errorf("Expected the warning to reach only the debug client, got %d and %d", len(debug.notifications), len(errorsOnly.notifications))
+	}
+
+	// Removed sessions no longer receive broadcasts
+	Remove(debug.id)
+	Broadcast(mcp.LoggingLevelCritical, "tenants", "after remove")
+	if len(debug.notifications) != 1 {
+		t.Errorf("Expected no broadcast after Remove, got %d notifications", len(debug.notifications))
+	}
+}
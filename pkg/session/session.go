@@ -0,0 +1,145 @@
+package session
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Session holds per-client state that persists across tool calls within one MCP session
+type Session struct {
+	ID        string
+	CreatedAt time.Time
+
+	mu       sync.RWMutex
+	lastSeen time.Time
+	values   map[string]interface{}
+}
+
+// Get returns the value stored under key
+func (s *Session) Get(key string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set stores value under key
+func (s *Session) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// Delete removes key from the session
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// Keys returns the keys stored in the session in sorted order
+func (s *Session) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LastSeen returns when the session was last used by a tool call
+func (s *Session) LastSeen() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastSeen
+}
+
+// touch records activity on the session
+func (s *Session) touch() {
+	s.mu.Lock()
+	s.lastSeen = time.Now()
+	s.mu.Unlock()
+}
+
+// Session registry
+var (
+	sessions   = make(map[string]*Session)
+	sessionsMu sync.Mutex
+)
+
+// IDFromContext returns the MCP client session ID for a tool call, or "" when
+// the call is not associated with a session
+func IDFromContext(ctx context.Context) string {
+	if clientSession := server.ClientSessionFromContext(ctx); clientSession != nil {
+		return clientSession.SessionID()
+	}
+	return ""
+}
+
+// FromContext returns the session for a tool call, creating it on first use.
+// It returns nil when the call is not associated with a session.
+func FromContext(ctx context.Context) *Session {
+	id := IDFromContext(ctx)
+	if id == "" {
+		return nil
+	}
+	return Lookup(id)
+}
+
+// Lookup returns the session with the given ID, creating it on first use
+func Lookup(id string) *Session {
+	sessionsMu.Lock()
+	s, ok := sessions[id]
+	if !ok {
+		now := time.Now()
+		s = &Session{ID: id, CreatedAt: now, lastSeen: now, values: make(map[string]interface{})}
+		sessions[id] = s
+	}
+	sessionsMu.Unlock()
+
+	s.touch()
+	return s
+}
+
+// Remove discards the state held for a session
+func Remove(id string) {
+	connected.Delete(id)
+	clients.Delete(id)
+	forgetHealth(id)
+	unwatchAll(id)
+
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	delete(sessions, id)
+}
+
+// connected holds the IDs of sessions registered by the MCP transport
+var connected sync.Map
+
+// MarkConnected records that the transport registered a client session
+func MarkConnected(id string) {
+	connected.Store(id, true)
+}
+
+// IsConnected reports whether the transport has a live session with the given ID
+func IsConnected(id string) bool {
+	_, ok := connected.Load(id)
+	return ok
+}
+
+// All returns every known session
+func All() []*Session {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	all := make([]*Session, 0, len(sessions))
+	for _, s := range sessions {
+		all = append(all, s)
+	}
+	return all
+}
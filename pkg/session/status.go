@@ -0,0 +1,108 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// statusMethod is the notification announcing a change in the server's
+// ability to serve tool calls. Unlike notifications/message it is sent
+// whatever logging level a client selected, so agent frameworks can rely on
+// it to pause work and reconnect.
+const statusMethod = "notifications/server/status"
+
+// ServerStatus is the state announced by a status notification
+type ServerStatus string
+
+const (
+	// StatusReady means tool calls are served normally
+	StatusReady ServerStatus = "ready"
+	// StatusDegraded means the server is up but tool calls are expected to
+	// fail, e.g. because no OpsRamp tenant is reachable
+	StatusDegraded ServerStatus = "degraded"
+	// StatusShuttingDown means the server is about to close every connection
+	StatusShuttingDown ServerStatus = "shuttingDown"
+)
+
+// StatusNotice is the payload of a status notification. The durations are
+// zero when unknown.
+type StatusNotice struct {
+	Status ServerStatus `json:"status"`
+	Reason string       `json:"reason"`
+	// ExpectedDowntimeSeconds estimates how long the server will be unable
+	// to serve tool calls
+	ExpectedDowntimeSeconds int `json:"expectedDowntimeSeconds,omitempty"`
+	// RetryAfterSeconds is how long clients should wait before reconnecting
+	// or retrying tool calls
+	RetryAfterSeconds int       `json:"retryAfterSeconds,omitempty"`
+	Since             time.Time `json:"since"`
+}
+
+// Current server status, ready until announced otherwise
+var (
+	statusMu sync.RWMutex
+	status   = StatusNotice{Status: StatusReady, Since: time.Now()}
+)
+
+// CurrentStatus returns the last status announced with BroadcastStatus
+func CurrentStatus() StatusNotice {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+	return status
+}
+
+// BroadcastStatus records the server status and sends it to every tracked,
+// initialized client session without blocking. Each status is sent as a
+// notifications/server/status notification and, for clients that only show
+// log messages, as a notifications/message entry from the server logger.
+// It returns the number of sessions the status notification reached.
+func BroadcastStatus(notice StatusNotice) int {
+	if notice.Since.IsZero() {
+		notice.Since = time.Now()
+	}
+	statusMu.Lock()
+	status = notice
+	statusMu.Unlock()
+
+	notification := mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: statusMethod,
+			Params: mcp.NotificationParams{
+				AdditionalFields: map[string]interface{}{
+					"status":                  notice.Status,
+					"reason":                  notice.Reason,
+					"expectedDowntimeSeconds": notice.ExpectedDowntimeSeconds,
+					"retryAfterSeconds":       notice.RetryAfterSeconds,
+					"since":                   notice.Since.Format(time.RFC3339),
+				},
+			},
+		},
+	}
+
+	delivered := 0
+	clients.Range(func(key, value interface{}) bool {
+		clientSession := value.(server.ClientSession)
+		if !clientSession.Initialized() {
+			return true
+		}
+		select {
+		case clientSession.NotificationChannel() <- notification:
+			RecordDelivery(key.(string), true)
+			delivered++
+		default:
+			RecordDelivery(key.(string), false)
+		}
+		return true
+	})
+
+	level := mcp.LoggingLevelWarning
+	if notice.Status == StatusReady {
+		level = mcp.LoggingLevelNotice
+	}
+	Broadcast(level, "server", notice)
+	return delivered
+}
@@ -0,0 +1,40 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBroadcastStatusIgnoresLogLevel(t *testing.T) {
+	errorsOnly := newLoggingClientSession("status-error", mcp.LoggingLevelError)
+	TrackClient(errorsOnly)
+	defer Remove(errorsOnly.id)
+	defer BroadcastStatus(StatusNotice{Status: StatusReady})
+
+	delivered := BroadcastStatus(StatusNotice{Status: StatusShuttingDown, Reason: "restart", ExpectedDowntimeSeconds: 30, RetryAfterSeconds: 30})
+	if delivered != 1 || len(errorsOnly.notifications) != 1 {
+		t.Fatalf("Expected only the status notification to reach an errors-only client, got %d sent and %d queued", delivered, len(errorsOnly.notifications))
+	}
+	notification := <-errorsOnly.notifications
+	params := notification.Params.AdditionalFields
+	if notification.Method != statusMethod || params["status"] != StatusShuttingDown || params["retryAfterSeconds"] != 30 {
+		t.Errorf("Unexpected notification: %+v", notification)
+	}
+	if current := CurrentStatus(); current.Status != StatusShuttingDown || current.Since.IsZero() {
+		t.Errorf("Expected the status to be recorded, got %+v", current)
+	}
+
+	// Clients following warnings also get it as a log message
+	debug := newLoggingClientSession("status-debug", mcp.LoggingLevelWarning)
+	TrackClient(debug)
+	defer Remove(debug.id)
+	BroadcastStatus(StatusNotice{Status: StatusDegraded, Reason: "no tenant reachable"})
+	if len(debug.notifications) != 2 {
+		t.Fatalf("Expected a status notification and a log message, got %d", len(debug.notifications))
+	}
+	<-debug.notifications
+	if message := <-debug.notifications; message.Method != "notifications/message" || message.Params.AdditionalFields["logger"] != "server" {
+		t.Errorf("Unexpected log message: %+v", message)
+	}
+}
@@ -0,0 +1,109 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MaxWatchesPerSession bounds how many entities one session can watch
+const MaxWatchesPerSession = 100
+
+// Watch registry: watchers maps an entity key ("kind/id") to the IDs of the
+// sessions watching it, and watched maps each session to its entity keys
+var (
+	watchers  = make(map[string]map[string]bool)
+	watched   = make(map[string]map[string]bool)
+	watchesMu sync.Mutex
+)
+
+// Watch subscribes a session to events about the entity of kind with id,
+// such as alerts raised on a resource. Watching an entity twice is a no-op.
+func Watch(sessionID, kind, id string) error {
+	watchesMu.Lock()
+	defer watchesMu.Unlock()
+
+	key := kind + "/" + id
+	if watched[sessionID][key] {
+		return nil
+	}
+	if len(watched[sessionID]) >= MaxWatchesPerSession {
+		return fmt.Errorf("a session can watch at most %d entities; unwatch some first", MaxWatchesPerSession)
+	}
+
+	if watchers[key] == nil {
+		watchers[key] = make(map[string]bool)
+	}
+	watchers[key][sessionID] = true
+	if watched[sessionID] == nil {
+		watched[sessionID] = make(map[string]bool)
+	}
+	watched[sessionID][key] = true
+	return nil
+}
+
+// Unwatch removes a session's subscription to an entity and reports whether
+// it was watching it
+func Unwatch(sessionID, kind, id string) bool {
+	watchesMu.Lock()
+	defer watchesMu.Unlock()
+
+	key := kind + "/" + id
+	if !watched[sessionID][key] {
+		return false
+	}
+	unwatchLocked(sessionID, key)
+	return true
+}
+
+// Watchers returns the IDs of the sessions watching an entity, sorted
+func Watchers(kind, id string) []string {
+	watchesMu.Lock()
+	defer watchesMu.Unlock()
+
+	ids := make([]string, 0, len(watchers[kind+"/"+id]))
+	for sessionID := range watchers[kind+"/"+id] {
+		ids = append(ids, sessionID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Watched returns the IDs of the entities of kind a session watches, sorted
+func Watched(sessionID, kind string) []string {
+	watchesMu.Lock()
+	defer watchesMu.Unlock()
+
+	prefix := kind + "/"
+	ids := make([]string, 0, len(watched[sessionID]))
+	for key := range watched[sessionID] {
+		if id, ok := strings.CutPrefix(key, prefix); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// unwatchAll drops every subscription of a session
+func unwatchAll(sessionID string) {
+	watchesMu.Lock()
+	defer watchesMu.Unlock()
+
+	for key := range watched[sessionID] {
+		unwatchLocked(sessionID, key)
+	}
+}
+
+// unwatchLocked removes one subscription; the caller holds watchesMu
+func unwatchLocked(sessionID, key string) {
+	delete(watchers[key], sessionID)
+	if len(watchers[key]) == 0 {
+		delete(watchers, key)
+	}
+	delete(watched[sessionID], key)
+	if len(watched[sessionID]) == 0 {
+		delete(watched, sessionID)
+	}
+}
@@ -0,0 +1,74 @@
+package session
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestWatchRegistry(t *testing.T) {
+	defer Remove("watch-a")
+	defer Remove("watch-b")
+
+	for _, w := range []struct{ session, id string }{{"watch-a", "r1"}, {"watch-a", "r2"}, {"watch-b", "r1"}, {"watch-a", "r1"}} {
+		if err := Watch(w.session, "resource", w.id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := Watchers("resource", "r1"); !reflect.DeepEqual(got, []string{"watch-a", "watch-b"}) {
+		t.Errorf("Watchers(r1) = %v", got)
+	}
+	if got := Watched("watch-a", "resource"); !reflect.DeepEqual(got, []string{"r1", "r2"}) {
+		t.Errorf("Watched(watch-a) = %v", got)
+	}
+	if got := Watchers("integration", "r1"); len(got) != 0 {
+		t.Errorf("Expected kinds to be watched separately, got %v", got)
+	}
+
+	if !Unwatch("watch-b", "resource", "r1") || Unwatch("watch-b", "resource", "r1") {
+		t.Error("Expected Unwatch to report whether the session was watching")
+	}
+	if got := Watchers("resource", "r1"); !reflect.DeepEqual(got, []string{"watch-a"}) {
+		t.Errorf("Watchers(r1) after unwatch = %v", got)
+	}
+
+	// Removing a session drops its subscriptions
+	Remove("watch-a")
+	if got := Watchers("resource", "r2"); len(got) != 0 {
+		t.Errorf("Expected no watchers after Remove, got %v", got)
+	}
+}
+
+func TestWatchLimit(t *testing.T) {
+	defer Remove("watch-limit")
+
+	for i := 0; i < MaxWatchesPerSession; i++ {
+		if err := Watch("watch-limit", "resource", fmt.Sprintf("r%d", i)); err != nil {
+			t.Fatalf("Watch %d failed: %v", i, err)
+		}
+	}
+	if err := Watch("watch-limit", "resource", "one-too-many"); err == nil {
+		t.Error("Expected the watch limit to be enforced")
+	}
+}
+
+func TestNotifyTargetsOneSession(t *testing.T) {
+	target := newLoggingClientSession("notify-target", mcp.LoggingLevelInfo)
+	other := newLoggingClientSession("notify-other", mcp.LoggingLevelInfo)
+	TrackClient(target)
+	TrackClient(other)
+	defer Remove(target.id)
+	defer Remove(other.id)
+
+	if !Notify(target.id, mcp.LoggingLevelWarning, "alerts", "disk full") {
+		t.Error("Expected the notification to be sent")
+	}
+	if len(target.notifications) != 1 || len(other.notifications) != 0 {
+		t.Errorf("Expected only the target to be notified, got %d and %d", len(target.notifications), len(other.notifications))
+	}
+	if Notify("notify-unknown", mcp.LoggingLevelWarning, "alerts", "disk full") {
+		t.Error("Expected untracked sessions not to be notified")
+	}
+}
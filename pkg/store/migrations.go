@@ -0,0 +1,181 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Bucket names used by server subsystems
+const (
+	// BucketMeta holds store bookkeeping such as the schema version
+	BucketMeta = "meta"
+	// BucketAudit holds audit trail entries
+	BucketAudit = "audit"
+	// BucketSavedSearches holds named saved searches
+	BucketSavedSearches = "saved_searches"
+	// BucketIdempotency holds idempotency keys for mutating calls
+	BucketIdempotency = "idempotency"
+	// BucketUsage holds usage accounting counters
+	BucketUsage = "usage"
+	// BucketSnapshots holds point-in-time snapshots
+	BucketSnapshots = "snapshots"
+	// BucketNotifications holds outbound notifications awaiting delivery
+	BucketNotifications = "notifications"
+	// BucketDeadLetters holds notifications that exhausted their delivery attempts
+	BucketDeadLetters = "notification_dead_letters"
+	// BucketResourceTemplates holds resource creation templates saved at runtime
+	BucketResourceTemplates = "resource_templates"
+	// BucketAlertRules holds custom alert dedup and mute rules
+	BucketAlertRules = "alert_rules"
+	// BucketSchedules holds actions scheduled to run at a future time
+	BucketSchedules = "schedules"
+	// BucketDynamicGroups holds the selectors of query-based device groups
+	BucketDynamicGroups = "dynamic_groups"
+	// BucketTenants holds tenants onboarded at runtime
+	BucketTenants = "tenants"
+	// BucketWorkspaces holds saved investigation workspaces
+	BucketWorkspaces = "workspaces"
+	// BucketGroupSnapshots holds daily snapshots of device group and site definitions
+	BucketGroupSnapshots = "group_snapshots"
+	// BucketToolDeadLetters holds tool calls that failed on OpsRamp's side, for replay
+	BucketToolDeadLetters = "tool_dead_letters"
+)
+
+// schemaVersionKey is the meta key holding the applied schema version
+const schemaVersionKey = "schema_version"
+
+// migration is a single, ordered schema change
+type migration struct {
+	version     int
+	description string
+	apply       func(tx *bolt.Tx) error
+}
+
+// migrations lists every schema change in order. Append new entries with the
+// next version number; never modify or reorder applied migrations.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create core subsystem buckets",
+		apply:       createBuckets(BucketAudit, BucketSavedSearches, BucketIdempotency, BucketUsage, BucketSnapshots),
+	},
+	{
+		version:     2,
+		description: "create outbound notification queue buckets",
+		apply:       createBuckets(BucketNotifications, BucketDeadLetters),
+	},
+	{
+		version:     3,
+		description: "create resource templates bucket",
+		apply:       createBuckets(BucketResourceTemplates),
+	},
+	{
+		version:     4,
+		description: "create alert rules bucket",
+		apply:       createBuckets(BucketAlertRules),
+	},
+	{
+		version:     5,
+		description: "create schedules bucket",
+		apply:       createBuckets(BucketSchedules),
+	},
+	{
+		version:     6,
+		description: "create dynamic groups bucket",
+		apply:       createBuckets(BucketDynamicGroups),
+	},
+	{
+		version:     7,
+		description: "create onboarded tenants bucket",
+		apply:       createBuckets(BucketTenants),
+	},
+	{
+		version:     8,
+		description: "create workspaces bucket",
+		apply:       createBuckets(BucketWorkspaces),
+	},
+	{
+		version:     9,
+		description: "create group snapshots bucket",
+		apply:       createBuckets(BucketGroupSnapshots),
+	},
+	{
+		version:     10,
+		description: "create tool call dead letters bucket",
+		apply:       createBuckets(BucketToolDeadLetters),
+	},
+}
+
+// createBuckets returns a migration step that creates the named buckets
+func createBuckets(names ...string) func(tx *bolt.Tx) error {
+	return func(tx *bolt.Tx) error {
+		for _, name := range names {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	}
+}
+
+// migrate applies all pending migrations, each in its own transaction
+func (s *Store) migrate() error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketMeta))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create meta bucket: %w", err)
+	}
+
+	current, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		s.logger.Info("Applying state store migration %d: %s", m.version, m.description)
+		err = s.db.Update(func(tx *bolt.Tx) error {
+			if err := m.apply(tx); err != nil {
+				return err
+			}
+			meta := tx.Bucket([]byte(BucketMeta))
+			return meta.Put([]byte(schemaVersionKey), encodeVersion(m.version))
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.description, err)
+		}
+		current = m.version
+	}
+
+	return nil
+}
+
+// SchemaVersion returns the currently applied schema version (0 for a new store)
+func (s *Store) SchemaVersion() (int, error) {
+	version := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket([]byte(BucketMeta))
+		if meta == nil {
+			return nil
+		}
+		if data := meta.Get([]byte(schemaVersionKey)); len(data) == 8 {
+			version = int(binary.BigEndian.Uint64(data))
+		}
+		return nil
+	})
+	return version, err
+}
+
+// encodeVersion encodes a schema version for storage
+func encodeVersion(version int) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(version))
+	return buf
+}
@@ -0,0 +1,224 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrNotFound is returned when a key does not exist in a bucket
+var ErrNotFound = errors.New("key not found")
+
+// Store is a durable, concurrency-safe key/value store shared by server
+// subsystems (audit, saved searches, idempotency, usage accounting, snapshots,
+// notifications). Values are stored as JSON documents grouped into named buckets.
+type Store struct {
+	db     *bolt.DB
+	path   string
+	logger *common.CustomLogger
+}
+
+// Open opens (or creates) the state store at the given path and applies any
+// pending schema migrations
+func Open(path string) (*Store, error) {
+	if path == "" {
+		return nil, fmt.Errorf("state store path cannot be empty")
+	}
+
+	// Sanitize the path for security
+	cleanPath := filepath.Clean(path)
+	if strings.Contains(cleanPath, "..") {
+		return nil, fmt.Errorf("invalid state store path: %s", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cleanPath), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create state store directory: %w", err)
+	}
+
+	db, err := bolt.Open(cleanPath, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store: %w", err)
+	}
+
+	s := &Store{
+		db:     db,
+		path:   cleanPath,
+		logger: common.GetLogger(),
+	}
+
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate state store: %w", err)
+	}
+
+	s.logger.Info("State store opened at %s", cleanPath)
+	return s, nil
+}
+
+// Close closes the underlying database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Path returns the location of the database file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Put stores value as JSON under key in the given bucket
+func (s *Store) Put(bucket, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value for %s/%s: %w", bucket, key, err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := existingBucket(tx, bucket)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), data)
+	})
+}
+
+// Get loads the JSON value stored under key into value, returning ErrNotFound
+// if the key does not exist
+func (s *Store) Get(bucket, key string, value interface{}) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		b, err := existingBucket(tx, bucket)
+		if err != nil {
+			return err
+		}
+		data := b.Get([]byte(key))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, value)
+	})
+}
+
+// Delete removes key from the given bucket. Deleting a missing key is not an error.
+func (s *Store) Delete(bucket, key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := existingBucket(tx, bucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete([]byte(key))
+	})
+}
+
+// Modify performs an atomic read-modify-write of the value stored under key.
+// The current value (if any) is decoded into value, fn is called with whether
+// the key existed, and value is written back unless fn returns an error.
+func (s *Store) Modify(bucket, key string, value interface{}, fn func(exists bool) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := existingBucket(tx, bucket)
+		if err != nil {
+			return err
+		}
+
+		exists := false
+		if data := b.Get([]byte(key)); data != nil {
+			if err := json.Unmarshal(data, value); err != nil {
+				return fmt.Errorf("failed to unmarshal value for %s/%s: %w", bucket, key, err)
+			}
+			exists = true
+		}
+
+		if err := fn(exists); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal value for %s/%s: %w", bucket, key, err)
+		}
+		return b.Put([]byte(key), data)
+	})
+}
+
+// Move atomically stores value under key in the destination bucket and
+// removes key from the source bucket
+func (s *Store) Move(fromBucket, toBucket, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value for %s/%s: %w", toBucket, key, err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		from, err := existingBucket(tx, fromBucket)
+		if err != nil {
+			return err
+		}
+		to, err := existingBucket(tx, toBucket)
+		if err != nil {
+			return err
+		}
+		if err := to.Put([]byte(key), data); err != nil {
+			return err
+		}
+		return from.Delete([]byte(key))
+	})
+}
+
+// ForEach calls fn for every key in the bucket in key order. The raw JSON
+// value is only valid for the duration of the call.
+func (s *Store) ForEach(bucket string, fn func(key string, raw []byte) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		b, err := existingBucket(tx, bucket)
+		if err != nil {
+			return err
+		}
+		return b.ForEach(func(k, v []byte) error {
+			return fn(string(k), v)
+		})
+	})
+}
+
+// Keys returns all keys in the bucket in key order
+func (s *Store) Keys(bucket string) ([]string, error) {
+	keys := make([]string, 0)
+	err := s.ForEach(bucket, func(key string, _ []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+	return keys, err
+}
+
+// existingBucket returns the named bucket, which must have been created by a migration
+func existingBucket(tx *bolt.Tx, name string) (*bolt.Bucket, error) {
+	b := tx.Bucket([]byte(name))
+	if b == nil {
+		return nil, fmt.Errorf("unknown state store bucket: %s", name)
+	}
+	return b, nil
+}
+
+// Global store instance
+var (
+	defaultStore *Store
+	defaultMu    sync.RWMutex
+)
+
+// SetDefault sets the process-wide state store used by server subsystems
+func SetDefault(s *Store) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultStore = s
+}
+
+// Default returns the process-wide state store, or nil if persistence is unavailable
+func Default() *Store {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultStore
+}
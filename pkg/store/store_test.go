@@ -0,0 +1,118 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+type testRecord struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestStoreMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	version, err := s.SchemaVersion()
+	if err != nil {
+		t.Fatalf("Failed to read schema version: %v", err)
+	}
+	if want := migrations[len(migrations)-1].version; version != want {
+		t.Errorf("Expected schema version %d, got %d", want, version)
+	}
+	if err := s.Put(BucketAudit, "entry-1", testRecord{Name: "first"}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	s.Close()
+
+	// Reopening must be a no-op for migrations and keep existing data
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer s.Close()
+
+	var rec testRecord
+	if err := s.Get(BucketAudit, "entry-1", &rec); err != nil {
+		t.Fatalf("Get after reopen failed: %v", err)
+	}
+	if rec.Name != "first" {
+		t.Errorf("Expected name 'first', got '%s'", rec.Name)
+	}
+}
+
+func TestStorePutGetDelete(t *testing.T) {
+	s := openTestStore(t)
+
+	if err := s.Put(BucketSavedSearches, "b", testRecord{Name: "b", Count: 2}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := s.Put(BucketSavedSearches, "a", testRecord{Name: "a", Count: 1}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	keys, err := s.Keys(BucketSavedSearches)
+	if err != nil {
+		t.Fatalf("Keys failed: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Errorf("Expected ordered keys [a b], got %v", keys)
+	}
+
+	if err := s.Delete(BucketSavedSearches, "a"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	var rec testRecord
+	if err := s.Get(BucketSavedSearches, "a", &rec); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+
+	if err := s.Put("no-such-bucket", "k", rec); err == nil {
+		t.Errorf("Expected error for unknown bucket")
+	}
+}
+
+func TestStoreModifyIsAtomic(t *testing.T) {
+	s := openTestStore(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var rec testRecord
+			err := s.Modify(BucketUsage, "counter", &rec, func(exists bool) error {
+				rec.Count++
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Modify failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var rec testRecord
+	if err := s.Get(BucketUsage, "counter", &rec); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if rec.Count != 20 {
+		t.Errorf("Expected count 20, got %d", rec.Count)
+	}
+}
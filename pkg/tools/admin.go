@@ -0,0 +1,360 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/notify"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+// CredentialReport is the result of the validateCredentials admin action
+type CredentialReport struct {
+	Valid   bool                     `json:"valid"`
+	Checked int                      `json:"checked"`
+	Failed  int                      `json:"failed"`
+	Tenants []client.CredentialCheck `json:"tenants"`
+}
+
+// EndpointReport is the result of the listEndpoints admin action
+type EndpointReport struct {
+	Count     int                   `json:"count"`
+	Mutations int                   `json:"mutations"`
+	Endpoints []client.EndpointInfo `json:"endpoints"`
+}
+
+// StaleSessionReport is the result of the staleSessions admin action
+type StaleSessionReport struct {
+	Count    int                      `json:"count"`
+	Sessions []session.DeliveryHealth `json:"sessions"`
+	// Removed lists the sessions disconnected when cleanup was requested
+	Removed []string `json:"removed,omitempty"`
+}
+
+// NewAdminMcpTool returns the MCP tool definition and handler for server administration
+func NewAdminMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "admin",
+		Description: "Server administration. validateCredentials performs the OAuth exchange and a scoped test call for each tenant and reports exactly what is wrong (bad key, bad secret, wrong tenant ID, wrong URL, clock skew) with a suggested fix. listNotifications shows outbound Slack/webhook notifications that are pending or dead-lettered; retryNotification requeues a dead-lettered notification. listEndpoints enumerates every OpsRamp endpoint the server can call, with its method, path, scope, owning tool and actions, and whether it changes OpsRamp state. staleSessions lists client sessions that stopped reading heartbeats and notifications, with their smoothed delivery rate; pass cleanup to disconnect them. onboardTenant adds a tenant without a restart: it validates the credentials, reports which endpoints they may call, registers the tenant, saves it to be restored on restart and warms its catalogs. logLevels shows the log level of each module; setLogLevel changes the level of one module (client, transport, tools or tools.<tool>) until restart, e.g. DEBUG for only the OpsRamp client during an incident. selfTest calls every registered tool with a safe read-only request (a list or search of one entry where the action allows), checks each result against the tool's output schema and returns a pass/fail matrix with latencies, to verify the server after a deployment. Tool calls that fail because OpsRamp was unreachable, rate limiting or answering with server errors are kept as dead letters with their arguments, credentials redacted: listDeadLetters lists them, getDeadLetter shows one, and replayDeadLetter calls the tool again once OpsRamp recovers, removing the dead letter when the call succeeds.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"action": map[string]interface{}{
+					"type":        "string",
+					"description": "Action to perform: validateCredentials, listNotifications, retryNotification, listEndpoints, staleSessions, onboardTenant, logLevels, setLogLevel, selfTest, listDeadLetters, getDeadLetter, replayDeadLetter",
+				},
+				"id": map[string]interface{}{
+					"type":        "string",
+					"description": "Notification ID (required for retryNotification) or dead letter ID (required for getDeadLetter and replayDeadLetter)",
+				},
+				"status": map[string]interface{}{
+					"type":        "string",
+					"description": "Notifications to list: dead (default) or pending",
+				},
+				"tenant": map[string]interface{}{
+					"type":        "string",
+					"description": "Tenant to validate (defaults to every configured tenant), or the name of the tenant to onboard",
+				},
+				"tenantUrl": map[string]interface{}{
+					"type":        "string",
+					"description": "OpsRamp API URL of the tenant to onboard, e.g. https://acme.api.opsramp.com",
+				},
+				"authUrl": map[string]interface{}{
+					"type":        "string",
+					"description": "OAuth token URL of the tenant to onboard",
+				},
+				"authKey": map[string]interface{}{
+					"type":        "string",
+					"description": "API key of the tenant to onboard",
+				},
+				"authSecret": map[string]interface{}{
+					"type":        "string",
+					"description": "API secret of the tenant to onboard",
+				},
+				"tenantId": map[string]interface{}{
+					"type":        "string",
+					"description": "Client ID of the tenant to onboard",
+				},
+				"partnerId": map[string]interface{}{
+					"type":        "string",
+					"description": "Partner ID of the tenant to onboard, for partner-scoped calls",
+				},
+				"environment": map[string]interface{}{
+					"type":        "string",
+					"description": "Environment label of the tenant to onboard, e.g. production (defaults to the primary tenant's)",
+				},
+				"tool": map[string]interface{}{
+					"type":        "string",
+					"description": "Only list endpoints called by this tool (for listEndpoints), or the dead letters of this tool (for listDeadLetters)",
+				},
+				"mutationsOnly": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only list endpoints that change OpsRamp state (for listEndpoints)",
+				},
+				"module": map[string]interface{}{
+					"type":        "string",
+					"description": "Log module: client, transport, tools, or tools.<tool> such as tools.resources (required for setLogLevel)",
+				},
+				"level": map[string]interface{}{
+					"type":        "string",
+					"description": "Log level: DEBUG, INFO, WARN, ERROR, or default to clear the module's level (required for setLogLevel)",
+				},
+				"cleanup": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Disconnect the stale sessions listed (for staleSessions)",
+				},
+				"llmCompact": llmCompactArgument,
+				"transform":  transformArgument,
+				"render":     renderArgument,
+				"columns":    columnsArgument,
+				"maxRows":    maxRowsArgument,
+			},
+			Required: []string{"action"},
+		},
+	}, AdminToolHandler
+}
+
+// AdminToolHandler routes requests to the correct method
+// Exported for testing purposes
+func AdminToolHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	action := req.GetString("action", "")
+
+	// Log the tool execution without the credentials of a tenant being onboarded
+	logger := toolLogger("admin")
+	logger.LogToolExecution("admin", action, redactArguments(req.GetArguments(), "authKey", "authSecret"))
+
+	var err error
+	var result interface{}
+
+	switch action {
+	case "validateCredentials":
+		logger.Info("Executing credential validation")
+		result, err = validateCredentials(ctx, req.GetString("tenant", ""))
+	case "listNotifications":
+		logger.Info("Executing notification listing")
+		result, err = listNotifications(req.GetString("status", "dead"))
+	case "retryNotification":
+		id := req.GetString("id", "")
+		if id == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Notification ID is required for retryNotification action"}},
+			}, nil
+		}
+		logger.Info("Executing notification retry for ID: %s", id)
+		result, err = retryNotification(id)
+	case "listEndpoints":
+		logger.Info("Executing endpoint listing")
+		result = listEndpoints(req.GetString("tool", ""), req.GetBool("mutationsOnly", false))
+	case "staleSessions":
+		logger.Info("Executing stale session listing")
+		result = staleSessions(req.GetBool("cleanup", false))
+	case "logLevels":
+		logger.Info("Executing log level listing")
+		result = logLevels()
+	case "setLogLevel":
+		module, level := req.GetString("module", ""), req.GetString("level", "")
+		if module == "" || level == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Module and level are required for setLogLevel action"}},
+			}, nil
+		}
+		logger.Info("Executing log level change: %s=%s", module, level)
+		result, err = setLogLevel(module, level)
+	case "onboardTenant":
+		logger.Info("Executing tenant onboarding for: %s", req.GetString("tenant", ""))
+		result, err = onboardTenant(ctx, req)
+	case "selfTest":
+		logger.Info("Executing self-test of the registered tools")
+		result = selfTest(ctx)
+	case "listDeadLetters":
+		logger.Info("Executing dead letter listing")
+		result, err = NewDeadLetters().List(req.GetString("tool", ""))
+	case "getDeadLetter", "replayDeadLetter":
+		id := req.GetString("id", "")
+		if id == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Dead letter ID is required for %s action", action)}},
+			}, nil
+		}
+		if action == "getDeadLetter" {
+			logger.Info("Executing dead letter lookup for ID: %s", id)
+			result, err = NewDeadLetters().Get(id)
+			break
+		}
+		logger.Info("Executing dead letter replay for ID: %s", id)
+		result, err = NewDeadLetters().Replay(ctx, id)
+	default:
+		logger.Error("Unknown action: %s", action)
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Unknown action: %s", action)}},
+		}, nil
+	}
+
+	// Log the result
+	logger.LogToolResult("admin", action, result, err)
+
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+
+	return respondJSON(ctx, req, result)
+}
+
+// onboardTenant onboards the tenant described by the request's arguments
+func onboardTenant(ctx context.Context, req mcp.CallToolRequest) (*TenantOnboarding, error) {
+	onboarder, err := NewTenantOnboarder()
+	if err != nil {
+		return nil, err
+	}
+	return onboarder.Onboard(ctx, common.TenantConfig{
+		Name: req.GetString("tenant", ""),
+		OpsRampConfig: common.OpsRampConfig{
+			TenantURL:   req.GetString("tenantUrl", ""),
+			AuthURL:     req.GetString("authUrl", ""),
+			AuthKey:     req.GetString("authKey", ""),
+			AuthSecret:  req.GetString("authSecret", ""),
+			TenantID:    req.GetString("tenantId", ""),
+			PartnerID:   req.GetString("partnerId", ""),
+			Environment: req.GetString("environment", ""),
+		},
+	})
+}
+
+// redactArguments returns a copy of args with the named secret arguments
+// masked, for logging
+func redactArguments(args map[string]interface{}, names ...string) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		redacted[k] = v
+	}
+	for _, name := range names {
+		if _, ok := redacted[name]; ok {
+			redacted[name] = "[REDACTED]"
+		}
+	}
+	return redacted
+}
+
+// validateCredentials checks the credentials of one tenant, or of every
+// tenant when name is empty
+func validateCredentials(ctx context.Context, name string) (*CredentialReport, error) {
+	registry := client.GetTenantRegistry()
+	if registry == nil {
+		// Servers without a startup health check have no registry yet
+		config, err := common.LoadConfig("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		registry = client.NewTenantRegistry(config)
+	}
+
+	var names []string
+	if name != "" {
+		names = append(names, name)
+	}
+	checks, err := registry.ValidateCredentials(ctx, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CredentialReport{Valid: true, Checked: len(checks), Tenants: checks}
+	for _, check := range checks {
+		if !check.Valid {
+			report.Valid = false
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+// notificationQueue returns the process notification queue
+func notificationQueue() (*notify.Queue, error) {
+	queue := notify.Default()
+	if queue == nil {
+		return nil, fmt.Errorf("the notification queue is not available: it requires the state store")
+	}
+	return queue, nil
+}
+
+// listNotifications returns the pending or dead-lettered notifications
+func listNotifications(status string) ([]notify.Notification, error) {
+	queue, err := notificationQueue()
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case "dead":
+		return queue.DeadLetters()
+	case "pending":
+		return queue.Pending()
+	default:
+		return nil, fmt.Errorf("invalid status %q: must be dead or pending", status)
+	}
+}
+
+// retryNotification requeues a dead-lettered notification for immediate delivery
+func retryNotification(id string) (*notify.Notification, error) {
+	queue, err := notificationQueue()
+	if err != nil {
+		return nil, err
+	}
+	return queue.Retry(id)
+}
+
+// listEndpoints reports the registered OpsRamp endpoints, optionally only
+// those called by one tool or those that change OpsRamp state
+func listEndpoints(tool string, mutationsOnly bool) *EndpointReport {
+	report := &EndpointReport{Endpoints: []client.EndpointInfo{}}
+	for _, endpoint := range client.DescribeEndpoints() {
+		if mutationsOnly && !endpoint.Mutation {
+			continue
+		}
+		if tool != "" && !endpointCalledBy(endpoint, tool) {
+			continue
+		}
+		report.Endpoints = append(report.Endpoints, endpoint)
+		if endpoint.Mutation {
+			report.Mutations++
+		}
+	}
+	report.Count = len(report.Endpoints)
+	return report
+}
+
+// endpointCalledBy reports whether the named tool owns or calls an endpoint
+func endpointCalledBy(endpoint client.EndpointInfo, tool string) bool {
+	if endpoint.Tool == tool {
+		return true
+	}
+	for _, action := range endpoint.Actions {
+		if strings.HasPrefix(action, tool+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// staleSessions lists the sessions whose clients stopped reading, and
+// disconnects them when cleanup is set
+func staleSessions(cleanup bool) *StaleSessionReport {
+	report := &StaleSessionReport{Sessions: session.StaleSessions()}
+	report.Count = len(report.Sessions)
+	if cleanup {
+		report.Removed = session.CleanupStale()
+		common.GetLogger().Warn("Disconnected %d stale sessions: %v", len(report.Removed), report.Removed)
+	}
+	return report
+}
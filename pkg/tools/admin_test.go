@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+func TestAdminListEndpoints(t *testing.T) {
+	result, err := AdminToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action":        "listEndpoints",
+		"tool":          "alerts",
+		"mutationsOnly": true,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("Expected an endpoint report, got %v", err)
+	}
+
+	var report EndpointReport
+	if err := json.Unmarshal([]byte(resultText(result)), &report); err != nil {
+		t.Fatalf("Invalid report: %v", err)
+	}
+	if report.Count != 2 || report.Mutations != 2 || report.Endpoints[0].Name != "alerts.action" || report.Endpoints[1].Name != "alerts.addComment" {
+		t.Fatalf("Expected only the alert action and comment endpoints, got %+v", report)
+	}
+	if endpoint := report.Endpoints[1]; endpoint.Method != "POST" || endpoint.Path != "/api/v2/tenants/{tenantId}/alerts/{alertId}/comments" {
+		t.Errorf("Unexpected endpoint %+v", endpoint)
+	}
+
+	// Endpoints owned by another tool are listed for the tools that call them
+	report = *listEndpoints("integrations", false)
+	found := false
+	for _, endpoint := range report.Endpoints {
+		found = found || endpoint.Name == "alerts.search"
+	}
+	if !found {
+		t.Error("Expected alerts.search among the endpoints called by integrations")
+	}
+}
+
+// TestEndpointRegistryCoversAPIPaths keeps the registry a complete record of
+// the OpsRamp paths the API implementations build
+func TestEndpointRegistryCoversAPIPaths(t *testing.T) {
+	registered := map[string]bool{}
+	for _, name := range client.EndpointNames() {
+		endpoint, _ := client.LookupEndpoint(name)
+		registered[endpoint.Path] = true
+	}
+
+	files, _ := filepath.Glob("*_api.go")
+	literal := regexp.MustCompile(`"/api/v2/tenants/%s/([^"?]+)`)
+	for _, file := range files {
+		source, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		for _, match := range literal.FindAllStringSubmatch(string(source), -1) {
+			if !registered[match[1]] {
+				t.Errorf("%s calls %s, which is not in the endpoint registry", file, match[1])
+			}
+		}
+	}
+}
+
+// TestEndpointActionsExist checks that registered endpoints name real tool actions
+func TestEndpointActionsExist(t *testing.T) {
+	tools := map[string]mcp.Tool{}
+	for _, tool := range ToolDefinitions() {
+		tools[tool.Name] = tool
+	}
+
+	for _, endpoint := range client.DescribeEndpoints() {
+		for _, action := range endpoint.Actions {
+			toolName, actionName, _ := strings.Cut(action, "/")
+			tool, ok := tools[toolName]
+			if !ok {
+				t.Errorf("%s: unknown tool in %s", endpoint.Name, action)
+				continue
+			}
+			if !slices.Contains(toolActions(tool), actionName) {
+				t.Errorf("%s: %s is not an action of the %s tool", endpoint.Name, actionName, toolName)
+			}
+		}
+	}
+}
+
+func TestAdminStaleSessions(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		session.RecordDelivery("admin-stale", false)
+	}
+	defer session.Remove("admin-stale")
+
+	result, err := AdminToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action":  "staleSessions",
+		"cleanup": true,
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("staleSessions failed: %v %s", err, resultText(result))
+	}
+	var report StaleSessionReport
+	if err := json.Unmarshal([]byte(resultText(result)), &report); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	if report.Count != 1 || report.Sessions[0].SessionID != "admin-stale" || len(report.Removed) != 1 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if len(session.StaleSessions()) != 0 {
+		t.Error("Expected cleanup to remove the stale session")
+	}
+}
+
+func TestAdminSetLogLevel(t *testing.T) {
+	defer common.ResetModuleLevel("tools.resources")
+	defer common.ResetModuleLevel("transport")
+
+	loggedLine := func(marker string) bool {
+		for _, line := range common.RecentLogLines() {
+			if strings.Contains(line, marker) {
+				return true
+			}
+		}
+		return false
+	}
+
+	toolLogger("resources").Debug("resources debug before")
+	if loggedLine("resources debug before") {
+		t.Fatal("Expected DEBUG lines to be dropped at the default level")
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"module": "tools.resources", "level": "debug"},
+		{"module": "transport", "level": "WARN"},
+	} {
+		args["action"] = "setLogLevel"
+		result, err := AdminToolHandler(context.Background(), createTestRequest(args))
+		if err != nil || result.IsError {
+			t.Fatalf("setLogLevel %v failed: %v %s", args, err, resultText(result))
+		}
+	}
+
+	toolLogger("resources").Debug("resources debug after")
+	toolLogger("alerts").Debug("alerts debug after")
+	common.GetLogger().WithModule(common.ModuleTransport).Info("transport info after")
+	if !loggedLine("resources debug after") {
+		t.Error("Expected tools.resources to log at DEBUG")
+	}
+	if loggedLine("alerts debug after") || loggedLine("transport info after") {
+		t.Error("Expected other modules to keep their levels")
+	}
+
+	result, err := AdminToolHandler(context.Background(), createTestRequest(map[string]interface{}{"action": "logLevels"}))
+	if err != nil || result.IsError {
+		t.Fatalf("logLevels failed: %v %s", err, resultText(result))
+	}
+	var report LogLevelReport
+	if err := json.Unmarshal([]byte(resultText(result)), &report); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	if report.Modules["tools.resources"] != "DEBUG" || report.Modules["transport"] != "WARN" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"action": "setLogLevel", "module": "client"},
+		{"action": "setLogLevel", "module": "database", "level": "debug"},
+		{"action": "setLogLevel", "module": "client", "level": "verbose"},
+	} {
+		result, err := AdminToolHandler(context.Background(), createTestRequest(args))
+		if err != nil || !result.IsError {
+			t.Errorf("Expected %v to be rejected, got %s", args, resultText(result))
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/httperr"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// maxAlertBatch bounds the alerts one acknowledge or close may change
+	maxAlertBatch = 1000
+	// alertActionChunkSize is the number of alerts sent per action request
+	alertActionChunkSize = 100
+	// alertBatchPageSize is the page size used when resolving a query
+	alertBatchPageSize = 100
+)
+
+// Per-alert outcomes of a batch action
+const (
+	alertActionSucceeded = "succeeded"
+	alertActionFailed    = "failed"
+)
+
+// AlertBatch acknowledges or closes alerts in chunks, reporting the outcome
+// for each alert. Alerts selected by a query are previewed until confirmed.
+type AlertBatch struct {
+	api    AlertsAPI
+	logger *common.CustomLogger
+}
+
+// NewAlertBatch creates a new AlertBatch
+func NewAlertBatch(api AlertsAPI) *AlertBatch {
+	return &AlertBatch{
+		api:    api,
+		logger: common.GetLogger(),
+	}
+}
+
+// Resolve returns the IDs of the alerts matching a query string, failing if
+// there are more than maxAlertBatch
+func (b *AlertBatch) Resolve(ctx context.Context, query string) ([]string, error) {
+	ids := make([]string, 0)
+	it := SearchAlertsIter(ctx, b.api, types.AlertSearchParams{QueryString: query, PageSize: alertBatchPageSize})
+	for it.Next() {
+		if len(ids) == maxAlertBatch {
+			return nil, fmt.Errorf("query matches more than %d alerts; narrow it down", maxAlertBatch)
+		}
+		ids = append(ids, it.Value().ID)
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("failed to resolve alert query: %w", err)
+	}
+	b.logger.Info("Alert query resolved to %d alerts", len(ids))
+	return ids, nil
+}
+
+// RunQuery resolves a query and, when confirmed, applies the action to the
+// matching alerts. expectedCount must equal the number of matches, so alerts
+// raised since the preview are never changed without review.
+func (b *AlertBatch) RunQuery(ctx context.Context, action, query, description string, confirm bool, expectedCount int) (*types.AlertBatchResult, error) {
+	ids, err := b.Resolve(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(ids) == 0:
+		return &types.AlertBatchResult{Action: action, Query: query, Message: "No alerts match the query; nothing to do"}, nil
+	case !confirm:
+		return &types.AlertBatchResult{
+			Action:  action,
+			Query:   query,
+			Count:   len(ids),
+			Targets: ids,
+			Message: fmt.Sprintf("Preview only: %d alerts would be affected by %s. Re-run with confirm: true and expectedCount: %d to apply.",
+				len(ids), action, len(ids)),
+		}, nil
+	case expectedCount != len(ids):
+		return nil, fmt.Errorf("query now matches %d alerts, not the expected %d; review the new preview before confirming", len(ids), expectedCount)
+	}
+
+	result := b.Run(ctx, action, ids, description)
+	result.Query = query
+	return result, nil
+}
+
+// Run applies the action to the alerts in chunks of alertActionChunkSize.
+// When OpsRamp rejects a chunk, its alerts are retried one at a time so a
+// single bad ID does not fail the others.
+func (b *AlertBatch) Run(ctx context.Context, action string, ids []string, description string) *types.AlertBatchResult {
+	ids = uniqueIDs(ids)
+	result := &types.AlertBatchResult{
+		Action:   action,
+		Count:    len(ids),
+		Executed: true,
+		Results:  make([]types.AlertActionOutcome, 0, len(ids)),
+	}
+
+	for start := 0; start < len(ids); start += alertActionChunkSize {
+		chunk := ids[start:min(start+alertActionChunkSize, len(ids))]
+		err := ctx.Err()
+		if err == nil {
+			err = b.apply(ctx, action, chunk, description)
+		}
+		if err != nil && len(chunk) > 1 && isAlertRejection(err) {
+			b.logger.Warn("%s of %d alerts was rejected, retrying them one at a time: %v", action, len(chunk), err)
+			for _, id := range chunk {
+				recordAlertOutcome(result, id, b.apply(ctx, action, []string{id}, description))
+			}
+			continue
+		}
+		for _, id := range chunk {
+			recordAlertOutcome(result, id, err)
+		}
+	}
+
+	result.Message = fmt.Sprintf("Applied %s to %d of %d alerts", action, result.Succeeded, result.Count)
+	if result.Failed > 0 {
+		result.Message += fmt.Sprintf("; %d failed", result.Failed)
+	}
+	b.logger.Info("%s", result.Message)
+	return result
+}
+
+// apply sends one action request for a chunk of alerts
+func (b *AlertBatch) apply(ctx context.Context, action string, ids []string, description string) error {
+	request := types.AlertActionRequest{Description: description, Alerts: make([]types.AlertActionRef, len(ids))}
+	for i, id := range ids {
+		request.Alerts[i] = types.AlertActionRef{ID: id}
+	}
+	return b.api.ApplyAction(ctx, action, request)
+}
+
+// recordAlertOutcome adds the outcome of the action for one alert to a result
+func recordAlertOutcome(result *types.AlertBatchResult, id string, err error) {
+	if err != nil {
+		result.Failed++
+		result.Results = append(result.Results, types.AlertActionOutcome{ID: id, Status: alertActionFailed, Error: err.Error()})
+		return
+	}
+	result.Succeeded++
+	result.Results = append(result.Results, types.AlertActionOutcome{ID: id, Status: alertActionSucceeded})
+}
+
+// isAlertRejection reports whether OpsRamp rejected an action request for
+// the alerts in it, such as an unknown or already closed alert, rather than
+// failing to process it
+func isAlertRejection(err error) bool {
+	switch httperr.Classify(err) {
+	case httperr.KindValidation, httperr.KindNotFound, httperr.KindConflict:
+		return true
+	}
+	return false
+}
+
+// uniqueIDs returns ids without repeats, in their original order
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/httperr"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestAlertBatchChunksAndIsolatesRejectedAlerts(t *testing.T) {
+	api := &FakeAlertsAPI{}
+	api.ApplyActionStub = func(ctx context.Context, action string, request types.AlertActionRequest) error {
+		for _, ref := range request.Alerts {
+			if ref.ID == "a150" {
+				return &httperr.StatusError{StatusCode: http.StatusNotFound, Body: "alert a150 not found"}
+			}
+		}
+		return nil
+	}
+
+	ids := make([]string, 0, 251)
+	for i := 0; i < 250; i++ {
+		ids = append(ids, fmt.Sprintf("a%d", i))
+	}
+	ids = append(ids, "a0")
+	result := NewAlertBatch(api).Run(context.Background(), "close", ids, "storm resolved")
+
+	if result.Count != 250 || result.Succeeded != 249 || result.Failed != 1 {
+		t.Fatalf("Unexpected result %+v", result.Message)
+	}
+	failed := slices.IndexFunc(result.Results, func(o types.AlertActionOutcome) bool { return o.Status == alertActionFailed })
+	if result.Results[failed].ID != "a150" {
+		t.Errorf("Expected only a150 to fail, got %+v", result.Results[failed])
+	}
+	// Three chunks, the second retried one alert at a time
+	if api.ApplyActionCallCount() != 3+100 {
+		t.Errorf("Expected 103 action requests, got %d", api.ApplyActionCallCount())
+	}
+	if _, action, request := api.ApplyActionArgsForCall(0); action != "close" || len(request.Alerts) != 100 || request.Description != "storm resolved" {
+		t.Errorf("Unexpected first request %s %+v", action, request)
+	}
+}
+
+func TestAlertsToolBatchQueryPreviewsUntilConfirmed(t *testing.T) {
+	api := &FakeAlertsAPI{}
+	api.SearchReturns(&types.AlertSearchResponse{Results: []types.AlertDetails{{ID: "a1"}, {ID: "a2"}}}, nil)
+
+	preview, _ := AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "acknowledge", "query": "currentState:CRITICAL",
+	}), api, "svc")
+	var result types.AlertBatchResult
+	if err := json.Unmarshal([]byte(resultText(preview)), &result); err != nil {
+		t.Fatalf("Expected a preview, got %s", resultText(preview))
+	}
+	if result.Executed || result.Count != 2 || api.ApplyActionCallCount() != 0 {
+		t.Errorf("Expected a preview of two alerts, got %+v", result)
+	}
+
+	mismatch, _ := AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "acknowledge", "query": "currentState:CRITICAL", "confirm": true, "expectedCount": 3,
+	}), api, "svc")
+	if !mismatch.IsError {
+		t.Errorf("Expected a changed match count to be rejected, got %s", resultText(mismatch))
+	}
+
+	applied, _ := AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "acknowledge", "query": "currentState:CRITICAL", "confirm": true, "expectedCount": 2,
+	}), api, "svc")
+	if err := json.Unmarshal([]byte(resultText(applied)), &result); err != nil || !result.Executed || result.Succeeded != 2 {
+		t.Errorf("Expected both alerts acknowledged, got %s", resultText(applied))
+	}
+
+	both, _ := AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "close", "query": "currentState:CRITICAL", "ids": []interface{}{"a1"},
+	}), api, "svc")
+	if !both.IsError {
+		t.Error("Expected ids and query together to be rejected")
+	}
+}
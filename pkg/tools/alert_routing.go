@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// maxRoutingNotifications bounds the notifications listed by a routing preview
+const maxRoutingNotifications = 100
+
+// escalationModeOff marks a disabled escalation policy
+const escalationModeOff = "OFF"
+
+// alertSeverities are the states an alert can be raised in
+var alertSeverities = map[string]bool{
+	"CRITICAL": true,
+	"WARNING":  true,
+	"INFO":     true,
+	"OK":       true,
+	"OBSERVED": true,
+}
+
+// routingQueryForAlert describes an existing alert for a routing preview
+func routingQueryForAlert(alert types.AlertDetails) types.AlertRoutingQuery {
+	query := types.AlertRoutingQuery{
+		AlertID:   alert.ID,
+		Metric:    alert.Metric,
+		Component: alert.Component,
+		Severity:  strings.ToUpper(alert.CurrentState),
+	}
+	if alert.Resource != nil {
+		query.ResourceID = alert.Resource.ID
+		query.ResourceType = alert.Resource.ResourceType
+	}
+	return query
+}
+
+// decodeRoutingQuery converts an alert argument into a routing query
+func decodeRoutingQuery(value interface{}) (types.AlertRoutingQuery, error) {
+	var query types.AlertRoutingQuery
+	payload, err := json.Marshal(coerceArgument("alerts", "alert", value, &query))
+	if err != nil {
+		return query, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&query); err != nil {
+		return query, fmt.Errorf("invalid alert definition: %w", err)
+	}
+
+	query.AlertID = ""
+	query.Severity = strings.ToUpper(query.Severity)
+	if !alertSeverities[query.Severity] {
+		return query, fmt.Errorf("invalid alert definition: severity must be one of CRITICAL, WARNING, INFO, OK or OBSERVED, got %q", query.Severity)
+	}
+	return query, nil
+}
+
+// previewAlertRouting reports who the tenant's escalation policies would
+// notify about the alert, and when
+func previewAlertRouting(ctx context.Context, api AlertsAPI, query types.AlertRoutingQuery) (*types.AlertRoutingPreview, error) {
+	policies, err := api.EscalationPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return routeAlert(policies, query), nil
+}
+
+// routeAlert evaluates escalation policies against an alert that stays open.
+// Every applicable policy escalates independently, so the notifications of
+// all of them are merged into one timeline.
+func routeAlert(policies []types.EscalationPolicy, query types.AlertRoutingQuery) *types.AlertRoutingPreview {
+	preview := &types.AlertRoutingPreview{
+		Alert:         query,
+		Policies:      []string{},
+		Notifications: []types.AlertNotification{},
+		Teams:         []string{},
+		Channels:      []string{},
+	}
+
+	sorted := append([]types.EscalationPolicy(nil), policies...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	teams, channels := map[string]bool{}, map[string]bool{}
+	for _, policy := range sorted {
+		if !escalationFilterMatches(policy.Filter, query) {
+			continue
+		}
+		if strings.EqualFold(policy.Mode, escalationModeOff) {
+			preview.Disabled = append(preview.Disabled, policy.Name)
+			continue
+		}
+		preview.Policies = append(preview.Policies, policy.Name)
+
+		for i, level := range policy.Levels {
+			notification := types.AlertNotification{
+				AfterMinutes: level.DelayMinutes,
+				Policy:       policy.Name,
+				Level:        i + 1,
+				Teams:        level.Teams,
+				Users:        level.Users,
+				Channels:     level.Channels,
+			}
+			preview.Notifications = append(preview.Notifications, notification)
+			if level.RepeatMinutes > 0 {
+				for repeat := 1; repeat <= level.RepeatCount; repeat++ {
+					notification.AfterMinutes = level.DelayMinutes + repeat*level.RepeatMinutes
+					notification.Repeat = true
+					preview.Notifications = append(preview.Notifications, notification)
+				}
+			}
+			for _, team := range level.Teams {
+				teams[team] = true
+			}
+			for _, channel := range level.Channels {
+				channels[strings.ToUpper(channel)] = true
+			}
+		}
+	}
+
+	sort.SliceStable(preview.Notifications, func(i, j int) bool {
+		return preview.Notifications[i].AfterMinutes < preview.Notifications[j].AfterMinutes
+	})
+	if len(preview.Notifications) > maxRoutingNotifications {
+		preview.Notifications = preview.Notifications[:maxRoutingNotifications]
+		preview.Truncated = true
+	}
+	for team := range teams {
+		preview.Teams = append(preview.Teams, team)
+	}
+	for channel := range channels {
+		preview.Channels = append(preview.Channels, channel)
+	}
+	sort.Strings(preview.Teams)
+	sort.Strings(preview.Channels)
+	preview.Unrouted = len(preview.Policies) == 0
+	return preview
+}
+
+// escalationFilterMatches reports whether an escalation policy applies to an
+// alert. A hypothetical alert that leaves a field out does not match a
+// policy restricted on that field.
+func escalationFilterMatches(filter types.EscalationFilter, query types.AlertRoutingQuery) bool {
+	return filterValueMatches(filter.ResourceIDs, query.ResourceID, false) &&
+		filterValueMatches(filter.ResourceTypes, query.ResourceType, false) &&
+		filterValueMatches(filter.Metrics, query.Metric, true) &&
+		filterValueMatches(filter.Components, query.Component, true) &&
+		filterValueMatches(filter.States, query.Severity, false)
+}
+
+// filterValueMatches reports whether value is one of the filter's values,
+// ignoring case; an empty filter matches any value
+func filterValueMatches(values []string, value string, wildcards bool) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, candidate := range values {
+		if wildcards && globMatch(candidate, value) || strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
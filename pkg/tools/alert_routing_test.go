@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// routingTestPolicies page the database team for critical database alerts
+// and everyone on the infrastructure team for any critical server alert
+var routingTestPolicies = []types.EscalationPolicy{
+	{
+		Name:   "servers-critical",
+		Mode:   "ON",
+		Filter: types.EscalationFilter{ResourceTypes: []string{"server"}, States: []string{"CRITICAL"}},
+		Levels: []types.EscalationLevel{
+			{DelayMinutes: 0, Teams: []string{"infra-oncall"}, Channels: []string{"sms"}, RepeatMinutes: 10, RepeatCount: 2},
+			{DelayMinutes: 30, Teams: []string{"infra-leads"}, Channels: []string{"VOICE"}},
+		},
+	},
+	{
+		Name:   "databases",
+		Mode:   "ON",
+		Filter: types.EscalationFilter{Metrics: []string{"mysql.*"}, States: []string{"CRITICAL", "WARNING"}},
+		Levels: []types.EscalationLevel{{DelayMinutes: 5, Teams: []string{"dba"}, Channels: []string{"EMAIL"}}},
+	},
+	{
+		Name:   "legacy-servers",
+		Mode:   "OFF",
+		Filter: types.EscalationFilter{ResourceTypes: []string{"server"}},
+		Levels: []types.EscalationLevel{{Teams: []string{"legacy"}}},
+	},
+}
+
+func TestRouteAlert(t *testing.T) {
+	preview := routeAlert(routingTestPolicies, types.AlertRoutingQuery{
+		ResourceType: "server", Metric: "mysql.connections", Severity: "CRITICAL",
+	})
+
+	if !reflect.DeepEqual(preview.Policies, []string{"databases", "servers-critical"}) || preview.Unrouted {
+		t.Errorf("Expected both active policies to apply, got %+v", preview)
+	}
+	if !reflect.DeepEqual(preview.Disabled, []string{"legacy-servers"}) {
+		t.Errorf("Expected the disabled policy to be reported, got %v", preview.Disabled)
+	}
+	if !reflect.DeepEqual(preview.Teams, []string{"dba", "infra-leads", "infra-oncall"}) {
+		t.Errorf("Unexpected teams %v", preview.Teams)
+	}
+	if !reflect.DeepEqual(preview.Channels, []string{"EMAIL", "SMS", "VOICE"}) {
+		t.Errorf("Unexpected channels %v", preview.Channels)
+	}
+
+	var timeline []string
+	for _, n := range preview.Notifications {
+		timeline = append(timeline, fmt.Sprintf("%s@%d", strings.Join(n.Teams, ","), n.AfterMinutes))
+	}
+	want := []string{"infra-oncall@0", "dba@5", "infra-oncall@10", "infra-oncall@20", "infra-leads@30"}
+	if !reflect.DeepEqual(timeline, want) {
+		t.Errorf("Expected timeline %v, got %v", want, timeline)
+	}
+	if !preview.Notifications[2].Repeat || preview.Notifications[4].Level != 2 {
+		t.Errorf("Expected repeats and levels to be marked, got %+v", preview.Notifications)
+	}
+}
+
+func TestRouteAlertUnrouted(t *testing.T) {
+	preview := routeAlert(routingTestPolicies, types.AlertRoutingQuery{ResourceType: "switch", Severity: "WARNING"})
+	if !preview.Unrouted || len(preview.Notifications) != 0 || len(preview.Policies) != 0 {
+		t.Errorf("Expected a warning on a switch to page nobody, got %+v", preview)
+	}
+}
+
+func TestAlertsTool_PreviewRouting(t *testing.T) {
+	api := &mockAlertsAPI{policies: routingTestPolicies}
+
+	res, err := AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "previewRouting",
+		"alert":  map[string]interface{}{"metric": "mysql.replication", "severity": "warning"},
+	}), api, "or-mcp-service")
+	if err != nil || res.IsError {
+		t.Fatalf("previewRouting failed: %v %s", err, resultText(res))
+	}
+	var preview types.AlertRoutingPreview
+	if err := json.Unmarshal([]byte(resultText(res)), &preview); err != nil {
+		t.Fatalf("Failed to decode preview: %v", err)
+	}
+	if preview.Alert.Severity != "WARNING" || !reflect.DeepEqual(preview.Policies, []string{"databases"}) {
+		t.Errorf("Unexpected preview %+v", preview)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"action": "previewRouting"},
+		{"action": "previewRouting", "alert": map[string]interface{}{"metric": "cpu"}},
+		{"action": "previewRouting", "alert": map[string]interface{}{"severity": "CRITICAL", "team": "dba"}},
+	} {
+		res, err := AlertsToolHandler(context.Background(), createTestRequest(args), api, "or-mcp-service")
+		if err != nil || !res.IsError {
+			t.Errorf("Expected %v to be rejected, got %s", args, resultText(res))
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// Alert rule actions
+const (
+	alertRuleMute  = "mute"
+	alertRuleDedup = "dedup"
+)
+
+const (
+	// defaultDedupWindowMinutes is the dedup window when a rule sets none
+	defaultDedupWindowMinutes = 60
+	// maxDedupWindowMinutes caps the dedup window at a week
+	maxDedupWindowMinutes = 7 * 24 * 60
+	// alertRulePreviewPageSize is the page size used to scan historical alerts
+	alertRulePreviewPageSize = 100
+	// maxAlertRulePreviewScan bounds the alerts scanned by a rule preview
+	maxAlertRulePreviewScan = 2000
+)
+
+// alertRuleNamePattern restricts alert rule names
+var alertRuleNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// alertDedupKey returns the key OpsRamp deduplicates an alert on
+func alertDedupKey(alert types.AlertDetails) string {
+	resourceID := ""
+	if alert.Resource != nil {
+		resourceID = alert.Resource.ID
+	}
+	return resourceID + "/" + alert.Metric + "/" + alert.Component
+}
+
+// withDedupKeys fills in the dedup key of each alert
+func withDedupKeys(alerts []types.AlertDetails) {
+	for i := range alerts {
+		alerts[i].DedupKey = alertDedupKey(alerts[i])
+	}
+}
+
+// AlertRules stores custom alert dedup and mute rules in the state store
+type AlertRules struct {
+	store *store.Store
+}
+
+// NewAlertRules creates a rule set over the default state store, which may be unavailable
+func NewAlertRules() *AlertRules {
+	return &AlertRules{store: store.Default()}
+}
+
+// List returns the saved rules by name; without a state store there are none
+func (r *AlertRules) List() ([]types.AlertRule, error) {
+	rules := make([]types.AlertRule, 0)
+	if r.store == nil {
+		return rules, nil
+	}
+	err := r.store.ForEach(store.BucketAlertRules, func(name string, raw []byte) error {
+		var rule types.AlertRule
+		if err := json.Unmarshal(raw, &rule); err != nil {
+			return fmt.Errorf("failed to decode alert rule %s: %w", name, err)
+		}
+		rules = append(rules, rule)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	return rules, nil
+}
+
+// Get returns the named rule
+func (r *AlertRules) Get(name string) (types.AlertRule, error) {
+	var rule types.AlertRule
+	if r.store != nil {
+		err := r.store.Get(store.BucketAlertRules, name, &rule)
+		if err == nil {
+			return rule, nil
+		}
+		if !errors.Is(err, store.ErrNotFound) {
+			return rule, err
+		}
+	}
+	return rule, fmt.Errorf("unknown alert rule: %s (use listRules to see saved rules)", name)
+}
+
+// Save validates a rule and stores it under its name, replacing any rule of that name
+func (r *AlertRules) Save(rule types.AlertRule, now time.Time) (types.AlertRule, error) {
+	if r.store == nil {
+		return rule, fmt.Errorf("saving alert rules requires the state store")
+	}
+	if err := validateAlertRule(&rule); err != nil {
+		return rule, err
+	}
+	rule.CreatedAt = now.UTC().Format(time.RFC3339)
+	return rule, r.store.Put(store.BucketAlertRules, rule.Name, rule)
+}
+
+// Delete removes the named rule
+func (r *AlertRules) Delete(name string) error {
+	if _, err := r.Get(name); err != nil {
+		return err
+	}
+	return r.store.Delete(store.BucketAlertRules, name)
+}
+
+// validateAlertRule checks a rule and applies the default dedup window
+func validateAlertRule(rule *types.AlertRule) error {
+	if !alertRuleNamePattern.MatchString(rule.Name) {
+		return fmt.Errorf("invalid alert rule name %q: use up to 64 letters, digits, dots, dashes and underscores", rule.Name)
+	}
+	if rule.ResourceID == "" && rule.Metric == "" && rule.Component == "" {
+		return fmt.Errorf("alert rule %s must match on at least one of resourceId, metric and component", rule.Name)
+	}
+	for _, pattern := range []string{rule.Metric, rule.Component} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("alert rule %s has an invalid pattern %q", rule.Name, pattern)
+		}
+	}
+
+	switch rule.Action {
+	case alertRuleMute:
+		if rule.WindowMinutes != 0 {
+			return fmt.Errorf("windowMinutes only applies to dedup rules")
+		}
+	case alertRuleDedup:
+		if rule.WindowMinutes == 0 {
+			rule.WindowMinutes = defaultDedupWindowMinutes
+		}
+		if rule.WindowMinutes < 1 || rule.WindowMinutes > maxDedupWindowMinutes {
+			return fmt.Errorf("windowMinutes must be between 1 and %d", maxDedupWindowMinutes)
+		}
+	default:
+		return fmt.Errorf("alert rule action must be %s or %s, got %q", alertRuleMute, alertRuleDedup, rule.Action)
+	}
+	return nil
+}
+
+// alertRuleMatches reports whether a rule applies to an alert
+func alertRuleMatches(rule types.AlertRule, alert types.AlertDetails) bool {
+	if rule.ResourceID != "" && (alert.Resource == nil || alert.Resource.ID != rule.ResourceID) {
+		return false
+	}
+	if rule.Metric != "" && !globMatch(rule.Metric, alert.Metric) {
+		return false
+	}
+	return rule.Component == "" || globMatch(rule.Component, alert.Component)
+}
+
+// applyAlertRules returns the alerts the rules leave visible, in their
+// original order, and how many were muted and collapsed. Mute rules take
+// precedence; otherwise the first matching dedup rule by name applies. A
+// collapsed alert adds its occurrences to the RepeatCount of the alert it
+// was collapsed into.
+func applyAlertRules(rules []types.AlertRule, alerts []types.AlertDetails) ([]types.AlertDetails, int, int) {
+	type window struct {
+		index int
+		start time.Time
+	}
+
+	kept := make([]types.AlertDetails, 0, len(alerts))
+	windows := map[string]*window{}
+	muted, collapsed := 0, 0
+	for _, i := range alertsByCreation(alerts) {
+		alert := alerts[i]
+		rule, ok := matchingAlertRule(rules, alert)
+		if ok && rule.Action == alertRuleMute {
+			muted++
+			continue
+		}
+		if ok && rule.Action == alertRuleDedup {
+			created, _ := parseAlertTime(alert.CreatedDate)
+			key := rule.Name + "|" + alertDedupKey(alert)
+			w := windows[key]
+			if w != nil && created.Sub(w.start) < time.Duration(rule.WindowMinutes)*time.Minute {
+				kept[w.index].RepeatCount += max(alert.RepeatCount, 1)
+				collapsed++
+				continue
+			}
+			windows[key] = &window{index: len(kept), start: created}
+		}
+		kept = append(kept, alert)
+	}
+
+	// Restore the original order of the visible alerts
+	order := make(map[string]int, len(alerts))
+	for i, alert := range alerts {
+		order[alert.ID] = i
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return order[kept[i].ID] < order[kept[j].ID] })
+	return kept, muted, collapsed
+}
+
+// matchingAlertRule returns the rule that applies to an alert; rules are sorted by name
+func matchingAlertRule(rules []types.AlertRule, alert types.AlertDetails) (types.AlertRule, bool) {
+	var dedup *types.AlertRule
+	for i, rule := range rules {
+		if !alertRuleMatches(rule, alert) {
+			continue
+		}
+		if rule.Action == alertRuleMute {
+			return rule, true
+		}
+		if dedup == nil {
+			dedup = &rules[i]
+		}
+	}
+	if dedup == nil {
+		return types.AlertRule{}, false
+	}
+	return *dedup, true
+}
+
+// alertsByCreation returns the indexes of alerts ordered by creation time;
+// alerts with unparseable times keep their relative order at the start
+func alertsByCreation(alerts []types.AlertDetails) []int {
+	indexes := make([]int, len(alerts))
+	created := make([]time.Time, len(alerts))
+	for i, alert := range alerts {
+		indexes[i] = i
+		created[i], _ = parseAlertTime(alert.CreatedDate)
+	}
+	sort.SliceStable(indexes, func(a, b int) bool { return created[indexes[a]].Before(created[indexes[b]]) })
+	return indexes
+}
+
+// parseAlertTime parses an OpsRamp alert timestamp
+func parseAlertTime(value string) (time.Time, error) {
+	for _, layout := range compactTimestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised alert time %q", value)
+}
+
+// previewAlertRule scans the alerts raised within the time range and reports
+// how many the rule would have muted or collapsed
+func previewAlertRule(ctx context.Context, api AlertsAPI, rule types.AlertRule, timeRange TimeRange) (*types.AlertRulePreview, error) {
+	if err := validateAlertRule(&rule); err != nil {
+		return nil, err
+	}
+
+	preview := &types.AlertRulePreview{Rule: rule}
+	matched := []types.AlertDetails{}
+	params := types.AlertSearchParams{
+		QueryString: alertSearchQuery("", timeRange),
+		PageSize:    alertRulePreviewPageSize,
+	}
+	for params.PageNo = 1; ; params.PageNo++ {
+		page, err := api.Search(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alerts: %w", err)
+		}
+		for _, alert := range page.Results {
+			preview.Scanned++
+			if alertRuleMatches(rule, alert) {
+				matched = append(matched, alert)
+			}
+		}
+		if !page.NextPage || len(page.Results) == 0 {
+			break
+		}
+		if preview.Scanned >= maxAlertRulePreviewScan {
+			preview.Truncated = true
+			break
+		}
+	}
+
+	kept, muted, collapsed := applyAlertRules([]types.AlertRule{rule}, matched)
+	preview.Matched = len(matched)
+	preview.Muted = muted
+	preview.Collapsed = collapsed
+	preview.Remaining = len(kept)
+	return preview, nil
+}
+
+// decodeAlertRule converts a rule argument into an AlertRule
+func decodeAlertRule(value interface{}) (types.AlertRule, error) {
+	var rule types.AlertRule
+	payload, err := json.Marshal(coerceArgument("alerts", "rule", value, &rule))
+	if err != nil {
+		return rule, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rule); err != nil {
+		return rule, fmt.Errorf("invalid alert rule: %w", err)
+	}
+	return rule, nil
+}
@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// testAlert returns an alert on a resource raised at minute offset from a fixed time
+func testAlert(id, resourceID, metric, component string, minute int) types.AlertDetails {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).Add(time.Duration(minute) * time.Minute)
+	return types.AlertDetails{
+		ID:          id,
+		Metric:      metric,
+		Component:   component,
+		CreatedDate: created.Format(time.RFC3339),
+		Resource:    &types.AlertResource{ID: resourceID},
+	}
+}
+
+// useTestStore makes a temporary store the default for the test
+func useTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	s, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	store.SetDefault(s)
+	t.Cleanup(func() {
+		store.SetDefault(nil)
+		s.Close()
+	})
+	return s
+}
+
+func TestAlertDedupKey(t *testing.T) {
+	alert := testAlert("a1", "r1", "system.disk.usage", "/var", 0)
+	if key := alertDedupKey(alert); key != "r1/system.disk.usage//var" {
+		t.Errorf("Unexpected dedup key %q", key)
+	}
+	if key := alertDedupKey(types.AlertDetails{Metric: "ping"}); key != "/ping/" {
+		t.Errorf("Unexpected dedup key without a resource %q", key)
+	}
+}
+
+func TestValidateAlertRule(t *testing.T) {
+	tests := []struct {
+		name string
+		rule types.AlertRule
+		want string
+	}{
+		{"bad name", types.AlertRule{Name: "no spaces", Action: alertRuleMute, Metric: "cpu"}, "invalid alert rule name"},
+		{"no match fields", types.AlertRule{Name: "all", Action: alertRuleMute}, "at least one of"},
+		{"bad pattern", types.AlertRule{Name: "p", Action: alertRuleMute, Metric: "cpu["}, "invalid pattern"},
+		{"bad action", types.AlertRule{Name: "a", Action: "drop", Metric: "cpu"}, "must be mute or dedup"},
+		{"mute window", types.AlertRule{Name: "m", Action: alertRuleMute, Metric: "cpu", WindowMinutes: 5}, "only applies to dedup"},
+		{"long window", types.AlertRule{Name: "d", Action: alertRuleDedup, Metric: "cpu", WindowMinutes: maxDedupWindowMinutes + 1}, "windowMinutes must be between"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAlertRule(&tt.rule)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	rule := types.AlertRule{Name: "disk", Action: alertRuleDedup, Metric: "system.disk.*"}
+	if err := validateAlertRule(&rule); err != nil || rule.WindowMinutes != defaultDedupWindowMinutes {
+		t.Errorf("Expected the default window, got %d (%v)", rule.WindowMinutes, err)
+	}
+}
+
+func TestApplyAlertRules(t *testing.T) {
+	alerts := []types.AlertDetails{
+		testAlert("a4", "r1", "system.disk.usage", "/var", 90),
+		testAlert("a3", "r1", "system.disk.usage", "/var", 30),
+		testAlert("a2", "r1", "system.disk.usage", "/var", 10),
+		testAlert("a1", "r1", "system.disk.usage", "/var", 0),
+		testAlert("b1", "r1", "system.disk.usage", "/home", 5),
+		testAlert("c1", "r2", "system.ping", "", 5),
+	}
+	rules := []types.AlertRule{
+		{Name: "disk", Action: alertRuleDedup, Metric: "system.disk.*", WindowMinutes: 60},
+		{Name: "ping", Action: alertRuleMute, Metric: "system.ping"},
+	}
+
+	kept, muted, collapsed := applyAlertRules(rules, alerts)
+	if muted != 1 || collapsed != 2 {
+		t.Errorf("Expected 1 muted and 2 collapsed, got %d and %d", muted, collapsed)
+	}
+	ids := []string{}
+	for _, alert := range kept {
+		ids = append(ids, alert.ID)
+	}
+	// a2 and a3 fold into a1; a4 falls outside a1's window and opens a new one
+	if strings.Join(ids, ",") != "a4,a1,b1" {
+		t.Fatalf("Expected a4,a1,b1 in the original order, got %v", ids)
+	}
+	if kept[1].RepeatCount != 2 {
+		t.Errorf("Expected a1 to count the collapsed repeats, got %d", kept[1].RepeatCount)
+	}
+
+	// Mute rules win over dedup rules matching the same alert
+	rules = append(rules, types.AlertRule{Name: "var", Action: alertRuleMute, Component: "/var"})
+	if _, muted, collapsed = applyAlertRules(rules, alerts); muted != 5 || collapsed != 0 {
+		t.Errorf("Expected mute to take precedence, got %d muted and %d collapsed", muted, collapsed)
+	}
+}
+
+func TestAlertRulesStore(t *testing.T) {
+	rules := &AlertRules{store: useTestStore(t)}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	saved, err := rules.Save(types.AlertRule{Name: "ping", Action: alertRuleMute, ResourceID: "r2", Metric: "system.ping"}, now)
+	if err != nil {
+		t.Fatalf("Failed to save rule: %v", err)
+	}
+	if saved.CreatedAt != "2024-05-01T12:00:00Z" {
+		t.Errorf("Unexpected createdAt %q", saved.CreatedAt)
+	}
+	if _, err := rules.Save(types.AlertRule{Name: "disk", Action: alertRuleDedup, Metric: "system.disk.*"}, now); err != nil {
+		t.Fatalf("Failed to save rule: %v", err)
+	}
+
+	list, err := rules.List()
+	if err != nil || len(list) != 2 || list[0].Name != "disk" || list[1].Name != "ping" {
+		t.Fatalf("Expected both rules by name, got %+v (%v)", list, err)
+	}
+	if err := rules.Delete("ping"); err != nil {
+		t.Fatalf("Failed to delete rule: %v", err)
+	}
+	if err := rules.Delete("ping"); err == nil || !strings.Contains(err.Error(), "unknown alert rule") {
+		t.Errorf("Expected deleting a missing rule to fail, got %v", err)
+	}
+
+	if _, err := (&AlertRules{}).Save(saved, now); err == nil {
+		t.Error("Expected saving without a state store to fail")
+	}
+	if list, err := (&AlertRules{}).List(); err != nil || len(list) != 0 {
+		t.Errorf("Expected no rules without a state store, got %v (%v)", list, err)
+	}
+}
+
+func TestPreviewAlertRule(t *testing.T) {
+	api := &FakeAlertsAPI{}
+	api.SearchStub = func(ctx context.Context, params types.AlertSearchParams) (*types.AlertSearchResponse, error) {
+		if params.PageNo == 1 {
+			return &types.AlertSearchResponse{Results: []types.AlertDetails{
+				testAlert("a1", "r1", "system.disk.usage", "/var", 0),
+				testAlert("a2", "r1", "system.disk.usage", "/var", 10),
+			}, NextPage: true}, nil
+		}
+		return &types.AlertSearchResponse{Results: []types.AlertDetails{
+			testAlert("a3", "r1", "system.disk.usage", "/var", 20),
+			testAlert("c1", "r2", "system.ping", "", 5),
+		}}, nil
+	}
+
+	rule := types.AlertRule{Name: "disk", Action: alertRuleDedup, Metric: "system.disk.*"}
+	timeRange := TimeRange{Start: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)}
+	preview, err := previewAlertRule(context.Background(), api, rule, timeRange)
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	if preview.Scanned != 4 || preview.Matched != 3 || preview.Collapsed != 2 || preview.Remaining != 1 || preview.Truncated {
+		t.Errorf("Unexpected preview %+v", preview)
+	}
+	if api.SearchCallCount() != 2 {
+		t.Errorf("Expected two pages to be scanned, got %d", api.SearchCallCount())
+	}
+	if _, params := api.SearchArgsForCall(0); !strings.Contains(params.QueryString, "startDate:2024-05-01T00:00:00") {
+		t.Errorf("Expected the scan to cover the time range, got %q", params.QueryString)
+	}
+}
+
+func TestAlertsToolHandlerRules(t *testing.T) {
+	useTestStore(t)
+	api := &FakeAlertsAPI{}
+	api.SearchStub = func(ctx context.Context, params types.AlertSearchParams) (*types.AlertSearchResponse, error) {
+		return &types.AlertSearchResponse{Results: []types.AlertDetails{
+			testAlert("a2", "r1", "system.disk.usage", "/var", 10),
+			testAlert("a1", "r1", "system.disk.usage", "/var", 0),
+		}}, nil
+	}
+
+	result, err := AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "saveRule",
+		"rule":   map[string]interface{}{"name": "disk", "action": "dedup", "metric": "system.disk.*", "windowMinutes": 30},
+	}), api, "svc")
+	if err != nil || result.IsError {
+		t.Fatalf("Expected saveRule to succeed, got %s", resultText(result))
+	}
+
+	result, _ = AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{"action": "search"}), api, "svc")
+	if text := resultText(result); !strings.Contains(text, `"collapsed": 1`) || !strings.Contains(text, `"dedupKey": "r1/system.disk.usage//var"`) {
+		t.Errorf("Expected search to apply the rule and show dedup keys, got %s", text)
+	}
+	result, _ = AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{"action": "search", "applyRules": false}), api, "svc")
+	if text := resultText(result); strings.Contains(text, `"collapsed"`) || !strings.Contains(text, `"a2"`) {
+		t.Errorf("Expected applyRules false to return every alert, got %s", text)
+	}
+
+	result, _ = AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{"action": "previewRule", "name": "disk"}), api, "svc")
+	if text := resultText(result); result.IsError || !strings.Contains(text, `"collapsed": 1`) {
+		t.Errorf("Expected a preview of the saved rule, got %s", text)
+	}
+
+	result, _ = AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{"action": "deleteRule", "name": "disk"}), api, "svc")
+	if result.IsError {
+		t.Errorf("Expected deleteRule to succeed, got %s", resultText(result))
+	}
+	result, _ = AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{"action": "listRules"}), api, "svc")
+	if text := resultText(result); result.IsError || strings.TrimSpace(text) != "[]" {
+		t.Errorf("Expected no rules after delete, got %s", text)
+	}
+
+	result, _ = AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "saveRule",
+		"rule":   map[string]interface{}{"name": "x", "action": "mute", "metric": "cpu", "severity": "low"},
+	}), api, "svc")
+	if !result.IsError || !strings.Contains(resultText(result), "unknown field") {
+		t.Errorf("Expected unknown rule fields to be rejected, got %s", resultText(result))
+	}
+}
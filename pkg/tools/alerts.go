@@ -0,0 +1,459 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// NewAlertsMcpTool returns the MCP tool definition and handler for alerts
+func NewAlertsMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
+	// Get the logger
+	logger := common.GetLogger()
+
+	// Load configuration
+	config, err := common.LoadConfig("")
+	if err != nil {
+		logger.Error("Failed to load config for OpsRamp Alerts API: %v", err)
+		return mcp.Tool{}, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Configuration error: %v", err)}},
+			}, nil
+		}
+	}
+
+	// Create and initialize the API implementation for the configured backend
+	backend := config.ToolBackend("alerts").Backend
+	var api AlertsAPI
+	if backend == common.BackendMock {
+		fixtures, err := mockFixtures(config)
+		if err != nil {
+			logger.Error("Failed to load fixtures for OpsRamp Alerts API: %v", err)
+			tool, _ := createAlertsTool(nil, config.OpsRamp.ServiceAccount)
+			return withBackend(tool, backend), backendErrorHandler(err)
+		}
+		api = &MockAlertsAPI{}
+		if fixtures != nil {
+			api = NewFixtureAlertsAPI(fixtures)
+		}
+	} else {
+		opsRampClient, err := newBackendClient(config, "alerts")
+		if err != nil {
+			logger.Error("Failed to initialize %s backend for OpsRamp Alerts API: %v", backend, err)
+			tool, _ := createAlertsTool(nil, config.OpsRamp.ServiceAccount)
+			return withBackend(tool, backend), backendErrorHandler(err)
+		}
+		api = NewOpsRampAlertsAPI(opsRampClient)
+	}
+
+	logger.Info("Successfully initialized OpsRamp Alerts API (%s backend)", backend)
+	tool, handler := createAlertsTool(api, config.OpsRamp.ServiceAccount)
+	return withBackend(tool, backend), handler
+}
+
+// createAlertsTool creates the MCP tool with the given API implementation
+func createAlertsTool(api AlertsAPI, serviceAccount string) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "alerts",
+		Description: "Work with HPE OpsRamp alerts and their collaboration threads (notes and attachments). Alerts carry their dedupKey (resourceId/metric/component); custom rules can mute noisy checks or collapse repeats of a dedup key within a window, and search applies them unless applyRules is false. previewRouting evaluates the escalation policies against an alert, or a hypothetical one, and reports which teams and channels would be notified and when. acknowledge and close act on up to 1000 alerts at once, given by ids or a query, and report the outcome for each alert; a query is previewed until confirmed.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"action": map[string]interface{}{
+					"type":        "string",
+					"description": "Action to perform: search, get, getThread, getNotes, getAttachments, addNote, listRules, saveRule, deleteRule, previewRule, previewRouting, acknowledge, close",
+				},
+				"id": map[string]interface{}{
+					"type":        "string",
+					"description": "Alert ID (required for get, getThread, getNotes, getAttachments and addNote; for previewRouting instead of alert)",
+				},
+				"ids": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Alert IDs (for acknowledge and close instead of query, up to 1000)",
+				},
+				"note": map[string]interface{}{
+					"type":        "string",
+					"description": "Note text (for addNote; for acknowledge and close, recorded as the reason for the action)",
+				},
+				"requestedBy": map[string]interface{}{
+					"type":        "string",
+					"description": "Person on whose behalf the note is posted; recorded in the note metadata (for addNote)",
+				},
+				"metadata": map[string]interface{}{
+					"type":        "object",
+					"description": "Additional string metadata to attach to the note (for addNote)",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "OpsRamp alert query string, e.g. currentState:CRITICAL (for search; for acknowledge and close instead of ids, within timeRange)",
+				},
+				"applyRules": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Apply the saved mute and dedup rules to the results (for search, default true)",
+				},
+				"rule": map[string]interface{}{
+					"type":        "object",
+					"description": "Alert rule {name, action: mute|dedup, resourceId, metric, component, windowMinutes}; metric and component may use * wildcards and windowMinutes defaults to 60 (for saveRule, or previewRule of an unsaved rule)",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Alert rule name (for deleteRule and previewRule)",
+				},
+				"confirm": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Apply acknowledge or close to the alerts matching query; without it the matches are only previewed",
+				},
+				"expectedCount": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of alerts the preview matched; required with confirm so new matches are never changed without review",
+				},
+				"alert": map[string]interface{}{
+					"type":        "object",
+					"description": "Hypothetical alert {resourceId, resourceType, metric, component, severity}; severity is required and is CRITICAL, WARNING, INFO, OK or OBSERVED (for previewRouting instead of id)",
+				},
+				"timeRange":      timeRangeArgument,
+				"tenant":         tenantArgument,
+				"tenants":        tenantsArgument,
+				"llmCompact":     llmCompactArgument,
+				"transform":      transformArgument,
+				"render":         renderArgument,
+				"columns":        columnsArgument,
+				"maxRows":        maxRowsArgument,
+				"example":        exampleArgument,
+				"suggestActions": suggestActionsArgument,
+			},
+			Required: []string{"action"},
+		},
+		OutputSchema: outputSchema(
+			types.AlertSearchResponse{}, types.AlertDetails{}, types.AlertThread{}, types.AlertNote{},
+			types.AlertRule{}, types.AlertRulePreview{}, types.AlertRoutingPreview{}, types.AlertBatchResult{},
+			types.TenantFanOutResult{},
+		),
+	}, withTenantFanOut("alerts", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tenant, err := resolveTenant(req)
+		if err != nil {
+			return tenantErrorResult(err), nil
+		}
+		if tenant != nil {
+			return AlertsToolHandler(ctx, req, NewOpsRampAlertsAPI(tenant.Client), tenant.Config.ServiceAccount)
+		}
+		return AlertsToolHandler(ctx, req, api, serviceAccount)
+	})
+}
+
+// AlertsToolHandler routes requests to the correct method
+// Exported for testing purposes
+func AlertsToolHandler(ctx context.Context, req mcp.CallToolRequest, api AlertsAPI, serviceAccount string) (*mcp.CallToolResult, error) {
+	action := req.GetString("action", "")
+	id := req.GetString("id", "")
+	args := req.GetArguments()
+
+	// Log the tool execution
+	logger := toolLogger("alerts")
+	logger.LogToolExecution("alerts", action, args)
+
+	if id == "" && !alertActionsWithoutID[action] {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Alert ID is required for %s action", action)}},
+		}, nil
+	}
+
+	var err error
+	var result interface{}
+
+	switch action {
+	case "search":
+		timeRange, rangeErr := timeRangeFromRequest(req, "last24h")
+		if rangeErr != nil {
+			return timeRangeErrorResult(rangeErr), nil
+		}
+		query := alertSearchQuery(req.GetString("query", ""), timeRange)
+		logger.Info("Executing Search alerts with query: %s", query)
+		result, err = searchAlerts(ctx, api, types.AlertSearchParams{
+			QueryString: query,
+			PageNo:      1,
+			PageSize:    100,
+		}, req.GetBool("applyRules", true))
+	case "get":
+		logger.Info("Executing Get alert with ID: %s", id)
+		var alert *types.AlertDetails
+		if alert, err = api.Get(ctx, id); err == nil {
+			alert.DedupKey = alertDedupKey(*alert)
+			result = alert
+		}
+	case "getThread":
+		logger.Info("Executing GetThread for alert: %s", id)
+		result, err = getAlertThread(ctx, api, id)
+	case "getNotes":
+		logger.Info("Executing GetNotes for alert: %s", id)
+		result, err = api.GetNotes(ctx, id)
+	case "getAttachments":
+		logger.Info("Executing GetAttachments for alert: %s", id)
+		result, err = api.GetAttachments(ctx, id)
+	case "addNote":
+		logger.Info("Executing AddNote for alert: %s", id)
+		text := req.GetString("note", "")
+		if text == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Note text is required for addNote action"}},
+			}, nil
+		}
+		result, err = api.AddNote(ctx, id, buildAlertNote(ctx, req, text, serviceAccount))
+	case "listRules":
+		logger.Info("Executing ListRules")
+		result, err = NewAlertRules().List()
+	case "saveRule":
+		logger.Info("Executing SaveRule")
+		if args["rule"] == nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Rule is required for saveRule action"}},
+			}, nil
+		}
+		rule, decodeErr := decodeAlertRule(args["rule"])
+		if decodeErr != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: decodeErr.Error()}},
+			}, nil
+		}
+		result, err = NewAlertRules().Save(rule, time.Now())
+	case "deleteRule":
+		name := req.GetString("name", "")
+		logger.Info("Executing DeleteRule: %s", name)
+		if name == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Rule name is required for deleteRule action"}},
+			}, nil
+		}
+		if err = NewAlertRules().Delete(name); err == nil {
+			result = map[string]string{"deleted": name}
+		}
+	case "previewRule":
+		timeRange, rangeErr := timeRangeFromRequest(req, "last7d")
+		if rangeErr != nil {
+			return timeRangeErrorResult(rangeErr), nil
+		}
+		var rule types.AlertRule
+		switch name := req.GetString("name", ""); {
+		case name != "":
+			rule, err = NewAlertRules().Get(name)
+		case args["rule"] != nil:
+			rule, err = decodeAlertRule(args["rule"])
+		default:
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "A rule name or rule is required for previewRule action"}},
+			}, nil
+		}
+		logger.Info("Executing PreviewRule: %s", rule.Name)
+		if err == nil {
+			result, err = previewAlertRule(ctx, api, rule, timeRange)
+		}
+	case "previewRouting":
+		var query types.AlertRoutingQuery
+		switch {
+		case id != "":
+			var alert *types.AlertDetails
+			if alert, err = api.Get(ctx, id); err == nil {
+				query = routingQueryForAlert(*alert)
+			}
+		case args["alert"] != nil:
+			var decodeErr error
+			if query, decodeErr = decodeRoutingQuery(args["alert"]); decodeErr != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{mcp.TextContent{Type: "text", Text: decodeErr.Error()}},
+				}, nil
+			}
+		default:
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "An alert ID or alert definition is required for previewRouting action"}},
+			}, nil
+		}
+		logger.Info("Executing PreviewRouting for %s alert", query.Severity)
+		if err == nil {
+			result, err = previewAlertRouting(ctx, api, query)
+		}
+	case "acknowledge", "close":
+		ids := req.GetStringSlice("ids", nil)
+		query := req.GetString("query", "")
+		if (len(ids) == 0) == (query == "") {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Exactly one of ids or query is required for %s action", action)}},
+			}, nil
+		}
+		if len(ids) > maxAlertBatch {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("At most %d alert IDs can be given for %s action", maxAlertBatch, action)}},
+			}, nil
+		}
+		// Batches run in the background pool, so they cannot delay other calls
+		ctx = client.WithPriority(ctx, client.PriorityBackground)
+		confirm := len(ids) > 0 || req.GetBool("confirm", false)
+		if confirm {
+			release, lockErr := acquireBulkLock(ctx, req, "alerts")
+			if lockErr != nil {
+				err = lockErr
+				break
+			}
+			defer release()
+		}
+		batch := NewAlertBatch(api)
+		note := req.GetString("note", "")
+		if len(ids) > 0 {
+			logger.Info("Executing %s for %d alerts", action, len(ids))
+			result = batch.Run(ctx, action, ids, note)
+			break
+		}
+		timeRange, rangeErr := timeRangeFromRequest(req, "last24h")
+		if rangeErr != nil {
+			return timeRangeErrorResult(rangeErr), nil
+		}
+		logger.Info("Executing %s for alerts matching: %s", action, query)
+		result, err = batch.RunQuery(ctx, action, alertSearchQuery(query, timeRange), note, confirm, req.GetInt("expectedCount", -1))
+	default:
+		logger.Error("Unknown action: %s", action)
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Unknown action: %s", action)}},
+		}, nil
+	}
+
+	// Log the result
+	logger.LogToolResult("alerts", action, result, err)
+
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+
+	toolResult, err := respondJSON(ctx, req, result)
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+	return toolResult, nil
+}
+
+// alertActionsWithoutID are the actions that do not act on a single alert
+var alertActionsWithoutID = map[string]bool{
+	"search":      true,
+	"listRules":   true,
+	"saveRule":    true,
+	"deleteRule":  true,
+	"previewRule": true,
+	// previewRouting takes either an alert ID or a hypothetical alert
+	"previewRouting": true,
+	"acknowledge":    true,
+	"close":          true,
+}
+
+// searchAlerts searches alerts, fills in their dedup keys and, unless
+// applyRules is false, applies the saved alert rules to the results
+func searchAlerts(ctx context.Context, api AlertsAPI, params types.AlertSearchParams, applyRules bool) (*types.AlertSearchResponse, error) {
+	response, err := api.Search(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	withDedupKeys(response.Results)
+	if !applyRules {
+		return response, nil
+	}
+
+	rules, err := NewAlertRules().List()
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) > 0 {
+		response.Results, response.Muted, response.Collapsed = applyAlertRules(rules, response.Results)
+	}
+	return response, nil
+}
+
+// alertSearchDateFormat is the timestamp layout accepted by alert query strings
+const alertSearchDateFormat = "2006-01-02T15:04:05"
+
+// alertSearchQuery restricts an alert query string to alerts raised within the time range
+func alertSearchQuery(query string, timeRange TimeRange) string {
+	dates := fmt.Sprintf("startDate:%s+endDate:%s",
+		timeRange.Start.Format(alertSearchDateFormat), timeRange.End.Format(alertSearchDateFormat))
+	if query == "" {
+		return dates
+	}
+	return query + "+" + dates
+}
+
+// getAlertThread fetches the notes and attachment metadata for an alert. A
+// section that cannot be retrieved is reported in the thread's Errors; the
+// thread fails only when neither can be.
+func getAlertThread(ctx context.Context, api AlertsAPI, id string) (*types.AlertThread, error) {
+	thread := &types.AlertThread{AlertID: id}
+	var notesErr error
+	thread.Errors = fetchSections(ctx, toolLogger("alerts"), "Thread of alert "+id, map[string]sectionFetch{
+		"notes": func(ctx context.Context) error {
+			thread.Notes, notesErr = api.GetNotes(ctx, id)
+			return notesErr
+		},
+		"attachments": func(ctx context.Context) (err error) {
+			thread.Attachments, err = api.GetAttachments(ctx, id)
+			return err
+		},
+	})
+	if len(thread.Errors) == 2 {
+		return nil, notesErr
+	}
+	return thread, nil
+}
+
+// buildAlertNote builds a structured note authored by the service account,
+// capturing the original requester in the note metadata
+func buildAlertNote(ctx context.Context, req mcp.CallToolRequest, text, serviceAccount string) types.AlertNoteRequest {
+	metadata := map[string]string{"source": "or-mcp"}
+	if extra, ok := req.GetArguments()["metadata"].(map[string]interface{}); ok {
+		for key, value := range extra {
+			metadata[key] = fmt.Sprintf("%v", value)
+		}
+	}
+
+	sessionID := session.IDFromContext(ctx)
+	if sessionID != "" {
+		metadata["sessionId"] = sessionID
+	}
+
+	// The requester is never the author: the note is always posted as the service account
+	requestedBy := req.GetString("requestedBy", "")
+	switch {
+	case requestedBy != "":
+		metadata["requestedBy"] = requestedBy
+	case sessionID != "":
+		metadata["requestedBy"] = "session:" + sessionID
+	default:
+		metadata["requestedBy"] = "unknown"
+	}
+
+	return types.AlertNoteRequest{
+		Text:     text,
+		Author:   serviceAccount,
+		Metadata: metadata,
+	}
+}
@@ -0,0 +1,293 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// AlertsAPI defines the contract for alert operations
+type AlertsAPI interface {
+	// Get retrieves a specific alert by ID
+	Get(ctx context.Context, id string) (*types.AlertDetails, error)
+
+	// GetNotes retrieves the full note/comment thread for an alert
+	GetNotes(ctx context.Context, id string) ([]types.AlertNote, error)
+
+	// GetAttachments retrieves attachment metadata for an alert
+	GetAttachments(ctx context.Context, id string) ([]types.AlertAttachment, error)
+
+	// AddNote posts a structured note on an alert
+	AddNote(ctx context.Context, id string, note types.AlertNoteRequest) (*types.AlertNote, error)
+
+	// Search searches for alerts matching the provided parameters
+	Search(ctx context.Context, params types.AlertSearchParams) (*types.AlertSearchResponse, error)
+
+	// EscalationPolicies lists the tenant's escalation policies
+	EscalationPolicies(ctx context.Context) ([]types.EscalationPolicy, error)
+
+	// ApplyAction acknowledges or closes a batch of alerts
+	ApplyAction(ctx context.Context, action string, request types.AlertActionRequest) error
+}
+
+// escalationPolicyPageSize is the page size used to list escalation policies
+const escalationPolicyPageSize = 100
+
+// OpsRampAlertsAPI implements the AlertsAPI interface for OpsRamp
+type OpsRampAlertsAPI struct {
+	client *client.OpsRampClient
+	logger *common.CustomLogger
+}
+
+// NewOpsRampAlertsAPI creates a new OpsRamp alerts API client
+func NewOpsRampAlertsAPI(client *client.OpsRampClient) *OpsRampAlertsAPI {
+	return &OpsRampAlertsAPI{
+		client: client,
+		logger: client.Logger().WithModule(toolModule("alerts")),
+	}
+}
+
+// Get retrieves a specific alert by ID
+func (api *OpsRampAlertsAPI) Get(ctx context.Context, id string) (*types.AlertDetails, error) {
+	api.logger.Info("Getting alert with ID: %s", id)
+
+	pathID, err := client.PathID("alert", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/alerts/%s", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var alert types.AlertDetails
+	if err := api.client.Get(ctx, endpoint, &alert); err != nil {
+		api.logger.Error("Failed to get alert %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get alert %s: %w", id, err)
+	}
+
+	return &alert, nil
+}
+
+// GetNotes retrieves the full note/comment thread for an alert
+func (api *OpsRampAlertsAPI) GetNotes(ctx context.Context, id string) ([]types.AlertNote, error) {
+	api.logger.Info("Getting notes for alert: %s", id)
+
+	pathID, err := client.PathID("alert", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/alerts/%s/comments", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var notes types.AlertNoteList
+	if err := api.client.Get(ctx, endpoint, &notes); err != nil {
+		api.logger.Error("Failed to get notes for alert %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get notes for alert %s: %w", id, err)
+	}
+
+	api.logger.Info("Retrieved %d notes for alert %s", len(notes.Results), id)
+	return notes.Results, nil
+}
+
+// GetAttachments retrieves attachment metadata for an alert
+func (api *OpsRampAlertsAPI) GetAttachments(ctx context.Context, id string) ([]types.AlertAttachment, error) {
+	api.logger.Info("Getting attachments for alert: %s", id)
+
+	pathID, err := client.PathID("alert", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/alerts/%s/attachments", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var attachments types.AlertAttachmentList
+	if err := api.client.Get(ctx, endpoint, &attachments); err != nil {
+		api.logger.Error("Failed to get attachments for alert %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get attachments for alert %s: %w", id, err)
+	}
+
+	api.logger.Info("Retrieved %d attachments for alert %s", len(attachments.Results), id)
+	return attachments.Results, nil
+}
+
+// AddNote posts a structured note on an alert
+func (api *OpsRampAlertsAPI) AddNote(ctx context.Context, id string, note types.AlertNoteRequest) (*types.AlertNote, error) {
+	api.logger.Info("Adding note to alert %s as %s", id, note.Author)
+
+	pathID, err := client.PathID("alert", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/alerts/%s/comments", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var created types.AlertNote
+	if err := api.client.Post(ctx, endpoint, note, &created); err != nil {
+		api.logger.Error("Failed to add note to alert %s: %v", id, err)
+		return nil, fmt.Errorf("failed to add note to alert %s: %w", id, err)
+	}
+
+	// Fill in fields the API does not echo back
+	if created.Text == "" {
+		created.Text = note.Text
+	}
+	if created.Author == "" {
+		created.Author = note.Author
+	}
+	if created.Metadata == nil {
+		created.Metadata = note.Metadata
+	}
+
+	return &created, nil
+}
+
+// Search searches for alerts matching the provided parameters
+func (api *OpsRampAlertsAPI) Search(ctx context.Context, params types.AlertSearchParams) (*types.AlertSearchResponse, error) {
+	api.logger.Info("Searching alerts with query: %s", params.QueryString)
+
+	query := url.Values{}
+	if params.QueryString != "" {
+		query.Set("queryString", params.QueryString)
+	}
+	if params.PageNo > 0 {
+		query.Set("pageNo", strconv.Itoa(params.PageNo))
+	}
+	if params.PageSize > 0 {
+		query.Set("pageSize", strconv.Itoa(params.PageSize))
+	}
+	if params.SortName != "" {
+		query.Set("sortName", params.SortName)
+	}
+	if params.SortType != "" {
+		query.Set("sortType", params.SortType)
+	}
+
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/alerts/search", api.client.GetTenantID())
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var response types.AlertSearchResponse
+	if err := api.client.Get(ctx, endpoint, &response); err != nil {
+		api.logger.Error("Failed to search alerts: %v", err)
+		return nil, fmt.Errorf("failed to search alerts: %w", err)
+	}
+
+	response.Normalize(params.PageNo, params.PageSize)
+	api.logger.Info("Found %d alerts (total: %d)", len(response.Results), response.TotalResults)
+	return &response, nil
+}
+
+// EscalationPolicies lists the tenant's escalation policies, reading every page
+func (api *OpsRampAlertsAPI) EscalationPolicies(ctx context.Context) ([]types.EscalationPolicy, error) {
+	api.logger.Info("Listing escalation policies")
+
+	policies := make([]types.EscalationPolicy, 0)
+	for pageNo := 1; ; pageNo++ {
+		endpoint := fmt.Sprintf("/api/v2/tenants/%s/escalations/search?pageNo=%d&pageSize=%d",
+			api.client.GetTenantID(), pageNo, escalationPolicyPageSize)
+		api.logger.Debug("Using endpoint: %s", endpoint)
+
+		var page types.EscalationPolicyList
+		if err := api.client.Get(ctx, endpoint, &page); err != nil {
+			api.logger.Error("Failed to list escalation policies: %v", err)
+			return nil, fmt.Errorf("failed to list escalation policies: %w", err)
+		}
+		policies = append(policies, page.Results...)
+		if len(page.Results) < escalationPolicyPageSize || len(policies) >= page.TotalResults {
+			break
+		}
+	}
+
+	api.logger.Info("Found %d escalation policies", len(policies))
+	return policies, nil
+}
+
+// ApplyAction acknowledges or closes a batch of alerts
+func (api *OpsRampAlertsAPI) ApplyAction(ctx context.Context, action string, request types.AlertActionRequest) error {
+	api.logger.Info("Applying %s to %d alerts", action, len(request.Alerts))
+
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/alerts/action/%s", api.client.GetTenantID(), url.PathEscape(action))
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	if err := api.client.Post(ctx, endpoint, request, nil); err != nil {
+		api.logger.Error("Failed to %s %d alerts: %v", action, len(request.Alerts), err)
+		return fmt.Errorf("failed to %s alerts: %w", action, err)
+	}
+	return nil
+}
+
+// MockAlertsAPI is a simple mock implementation of AlertsAPI used by the mock backend
+type MockAlertsAPI struct{}
+
+func (m *MockAlertsAPI) Get(ctx context.Context, id string) (*types.AlertDetails, error) {
+	return &types.AlertDetails{
+		ID:           id,
+		Subject:      "Mock Alert",
+		CurrentState: "CRITICAL",
+		Status:       "Open",
+		Priority:     "P2",
+		Component:    "cpu",
+		Resource:     &types.AlertResource{ID: "res-001", Name: "mock-server-01", ResourceType: "server"},
+		CreatedDate:  "2024-01-01T00:00:00+0000",
+	}, nil
+}
+
+func (m *MockAlertsAPI) GetNotes(ctx context.Context, id string) ([]types.AlertNote, error) {
+	return []types.AlertNote{
+		{
+			ID:          "note-001",
+			Text:        "Mock note",
+			Author:      "mock-user",
+			CreatedDate: "2024-01-01T00:05:00+0000",
+		},
+	}, nil
+}
+
+func (m *MockAlertsAPI) GetAttachments(ctx context.Context, id string) ([]types.AlertAttachment, error) {
+	return []types.AlertAttachment{}, nil
+}
+
+func (m *MockAlertsAPI) AddNote(ctx context.Context, id string, note types.AlertNoteRequest) (*types.AlertNote, error) {
+	return &types.AlertNote{
+		ID:       "new-note-001",
+		Text:     note.Text,
+		Author:   note.Author,
+		Metadata: note.Metadata,
+	}, nil
+}
+
+func (m *MockAlertsAPI) Search(ctx context.Context, params types.AlertSearchParams) (*types.AlertSearchResponse, error) {
+	alert, _ := m.Get(ctx, "alert-001")
+	return &types.AlertSearchResponse{
+		Results:      []types.AlertDetails{*alert},
+		TotalResults: 1,
+		PageNo:       1,
+		PageSize:     1,
+		TotalPages:   1,
+	}, nil
+}
+
+func (m *MockAlertsAPI) EscalationPolicies(ctx context.Context) ([]types.EscalationPolicy, error) {
+	return []types.EscalationPolicy{
+		{
+			ID:     "esc-001",
+			Name:   "Mock Critical Servers",
+			Mode:   "ON",
+			Filter: types.EscalationFilter{ResourceTypes: []string{"server"}, States: []string{"CRITICAL"}},
+			Levels: []types.EscalationLevel{
+				{DelayMinutes: 0, Teams: []string{"mock-oncall"}, Channels: []string{"SMS", "VOICE"}, RepeatMinutes: 15, RepeatCount: 2},
+				{DelayMinutes: 30, Teams: []string{"mock-managers"}, Channels: []string{"EMAIL"}},
+			},
+		},
+	}, nil
+}
+
+func (m *MockAlertsAPI) ApplyAction(ctx context.Context, action string, request types.AlertActionRequest) error {
+	return nil
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// mockAlertsAPI is an in-memory AlertsAPI for handler tests
+type mockAlertsAPI struct {
+	notes       []types.AlertNote
+	attachments []types.AlertAttachment
+	posted      []types.AlertNoteRequest
+	open        []types.AlertDetails
+	policies    []types.EscalationPolicy
+}
+
+func (m *mockAlertsAPI) Get(ctx context.Context, id string) (*types.AlertDetails, error) {
+	return &types.AlertDetails{ID: id, Subject: "CPU high", CurrentState: "CRITICAL"}, nil
+}
+
+func (m *mockAlertsAPI) GetNotes(ctx context.Context, id string) ([]types.AlertNote, error) {
+	return m.notes, nil
+}
+
+func (m *mockAlertsAPI) GetAttachments(ctx context.Context, id string) ([]types.AlertAttachment, error) {
+	return m.attachments, nil
+}
+
+func (m *mockAlertsAPI) AddNote(ctx context.Context, id string, note types.AlertNoteRequest) (*types.AlertNote, error) {
+	m.posted = append(m.posted, note)
+	return &types.AlertNote{ID: "n-new", Text: note.Text, Author: note.Author, Metadata: note.Metadata}, nil
+}
+
+func (m *mockAlertsAPI) Search(ctx context.Context, params types.AlertSearchParams) (*types.AlertSearchResponse, error) {
+	return &types.AlertSearchResponse{Results: m.open, TotalResults: len(m.open)}, nil
+}
+
+func (m *mockAlertsAPI) EscalationPolicies(ctx context.Context) ([]types.EscalationPolicy, error) {
+	return m.policies, nil
+}
+
+func (m *mockAlertsAPI) ApplyAction(ctx context.Context, action string, request types.AlertActionRequest) error {
+	return nil
+}
+
+func TestAlertsTool_GetThread(t *testing.T) {
+	api := &mockAlertsAPI{
+		notes:       []types.AlertNote{{ID: "n1", Text: "Investigating", Author: "alice"}},
+		attachments: []types.AlertAttachment{{ID: "a1", FileName: "trace.log", Size: 2048}},
+	}
+
+	res, err := AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "getThread",
+		"id":     "alert-1",
+	}), api, "or-mcp-service")
+	if err != nil || res.IsError {
+		t.Fatalf("Expected thread result, got %v / %v", res, err)
+	}
+
+	var thread types.AlertThread
+	if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &thread); err != nil {
+		t.Fatalf("Failed to decode thread: %v", err)
+	}
+	if thread.AlertID != "alert-1" || len(thread.Notes) != 1 || len(thread.Attachments) != 1 {
+		t.Errorf("Unexpected thread: %+v", thread)
+	}
+}
+
+func TestAlertsTool_GetThreadPartialFailure(t *testing.T) {
+	api := &FakeAlertsAPI{}
+	api.GetNotesReturns([]types.AlertNote{{ID: "n1", Text: "Investigating"}}, nil)
+	api.GetAttachmentsReturns(nil, fmt.Errorf("attachment service down"))
+
+	res, err := AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "getThread",
+		"id":     "alert-1",
+	}), api, "or-mcp-service")
+	if err != nil || res.IsError {
+		t.Fatalf("Expected the notes despite failed attachments, got %v / %v", res, err)
+	}
+	var thread types.AlertThread
+	if err := json.Unmarshal([]byte(resultText(res)), &thread); err != nil {
+		t.Fatalf("Failed to decode thread: %v", err)
+	}
+	if len(thread.Notes) != 1 || len(thread.Errors) != 1 || thread.Errors["attachments"] != "attachment service down" {
+		t.Errorf("Expected the notes and an attachments error, got %+v", thread)
+	}
+
+	api.GetNotesReturns(nil, fmt.Errorf("alert not found"))
+	res, _ = AlertsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "getThread",
+		"id":     "alert-1",
+	}), api, "or-mcp-service")
+	if !res.IsError {
+		t.Errorf("Expected a thread with no section to fail, got %s", resultText(res))
+	}
+}
+
+func TestAlertsTool_AddNoteAttribution(t *testing.T) {
+	api := &mockAlertsAPI{}
+
+	res, err := AlertsToolHandler(contextWithSession("sess-42"), createTestRequest(map[string]interface{}{
+		"action":      "addNote",
+		"id":          "alert-1",
+		"note":        "Restarted the service",
+		"requestedBy": "bob@example.com",
+		"metadata":    map[string]interface{}{"ticket": "INC-7"},
+	}), api, "or-mcp-service")
+	if err != nil || res.IsError {
+		t.Fatalf("Expected note to be posted, got %v / %v", res, err)
+	}
+
+	if len(api.posted) != 1 {
+		t.Fatalf("Expected one posted note, got %d", len(api.posted))
+	}
+	note := api.posted[0]
+	if note.Author != "or-mcp-service" {
+		t.Errorf("Expected service account author, got %s", note.Author)
+	}
+	if note.Metadata["requestedBy"] != "bob@example.com" || note.Metadata["sessionId"] != "sess-42" || note.Metadata["ticket"] != "INC-7" {
+		t.Errorf("Unexpected note metadata: %v", note.Metadata)
+	}
+}
+
+func TestAlertsTool_MissingParameters(t *testing.T) {
+	api := &mockAlertsAPI{}
+
+	for _, args := range []map[string]interface{}{
+		{"action": "get"},
+		{"action": "addNote", "id": "alert-1"},
+		{"action": "bogus", "id": "alert-1"},
+	} {
+		res, err := AlertsToolHandler(context.Background(), createTestRequest(args), api, "or-mcp-service")
+		if err != nil || !res.IsError {
+			t.Errorf("Expected tool error for %v, got %v / %v", args, res, err)
+		}
+	}
+}
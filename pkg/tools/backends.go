@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+)
+
+// toolBackendMetaKey is the tools/list _meta key reporting which backend serves a tool
+const toolBackendMetaKey = "or-mcp/backend"
+
+// newBackendClient returns the OpsRamp client for a tool's configured backend:
+// the live API, whose client all tools share, or a replay of a recorded
+// snapshot. Tools that support the mock backend handle it before calling this.
+func newBackendClient(config *common.Config, name string) (*client.OpsRampClient, error) {
+	backend := config.ToolBackend(name)
+	switch backend.Backend {
+	case common.BackendLive:
+		return client.SharedClient(config), nil
+	case common.BackendSnapshot:
+		return client.NewSnapshotClient(config, backend.Snapshot)
+	default:
+		return nil, fmt.Errorf("the %s backend is not available for the %s tool", backend.Backend, name)
+	}
+}
+
+// withBackend reports the backend serving a tool in its tools/list metadata
+func withBackend(tool mcp.Tool, backend string) mcp.Tool {
+	meta := make(map[string]any, len(tool.Meta)+1)
+	for key, value := range tool.Meta {
+		meta[key] = value
+	}
+	meta[toolBackendMetaKey] = backend
+	tool.Meta = meta
+	return tool
+}
+
+// backendErrorHandler returns a handler that reports why a tool's backend could not be initialized
+func backendErrorHandler(err error) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Backend error: %v", err)}},
+		}, nil
+	}
+}
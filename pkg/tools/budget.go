@@ -0,0 +1,572 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+// budgetWindow is the period call budgets apply to
+const budgetWindow = time.Hour
+
+// impactFanOut is the dependents per resource assumed when estimating an
+// impact analysis
+const impactFanOut = 3
+
+// CallEstimate is the predicted number of OpsRamp API calls of a tool call.
+// Estimates are upper bounds: scans stop early when they run out of results.
+type CallEstimate struct {
+	Tool   string `json:"tool"`
+	Action string `json:"action,omitempty"`
+	Calls  int    `json:"calls"`
+	// Basis explains how the calls were counted
+	Basis string `json:"basis"`
+	// Tenants is the number of tenants of a fan-out, each making the calls
+	// of the basis
+	Tenants int `json:"tenants,omitempty"`
+	// Trimmable is the argument that can be lowered to make fewer calls
+	Trimmable string `json:"trimmable,omitempty"`
+}
+
+// BudgetStatus is the result of the session tool's budget action
+type BudgetStatus struct {
+	Mode                string        `json:"mode"`
+	SessionCallsPerHour int           `json:"sessionCallsPerHour,omitempty"`
+	SessionCallsUsed    int           `json:"sessionCallsUsed"`
+	CallsPerHour        int           `json:"callsPerHour,omitempty"`
+	CallsUsed           int           `json:"callsUsed"`
+	Remaining           *int          `json:"remaining,omitempty"`
+	Estimate            *CallEstimate `json:"estimate,omitempty"`
+	WithinBudget        *bool         `json:"withinBudget,omitempty"`
+}
+
+// callTrim is the argument bounding the calls of an action, with the calls
+// made for each value of it
+type callTrim struct {
+	// arg is the argument's name; config.x names a field of config
+	arg   string
+	value int
+	min   int
+	calls func(value int) int
+}
+
+// callCost is the estimate of one action on one tenant
+type callCost struct {
+	calls int
+	basis string
+	trim  *callTrim
+}
+
+// callEstimator predicts the calls of an action from its arguments
+type callEstimator func(req mcp.CallToolRequest) callCost
+
+// fixedCalls estimates an action that always makes the same calls
+func fixedCalls(calls int, basis string) callEstimator {
+	return func(mcp.CallToolRequest) callCost { return callCost{calls: calls, basis: basis} }
+}
+
+// noCalls estimates an action served from server state
+var noCalls = fixedCalls(0, "served by the server without OpsRamp calls")
+
+// localTools are the tools that make no OpsRamp calls unless listed in
+// callEstimators
+var localTools = map[string]bool{"session": true, "utility": true, "admin": true}
+
+// callEstimators holds the estimators of the actions that do not make
+// exactly one call; other actions of the OpsRamp tools make one
+var callEstimators = map[string]map[string]callEstimator{
+	"resources": {
+		"list":                estimateResourceSearch,
+		"search":              estimateResourceSearch,
+		"listTemplates":       noCalls,
+		"saveTemplate":        noCalls,
+		"listDynamicGroups":   noCalls,
+		"watch":               noCalls,
+		"unwatch":             noCalls,
+		"listWatches":         noCalls,
+		"invalidateCache":     noCalls,
+		"getGroupHistory":     noCalls,
+		"diffGroups":          noCalls,
+		"snapshotGroups":      estimateGroupSnapshot,
+		"resolveId":           fixedCalls(len(identifierKinds), "one lookup per identifier kind"),
+		"createOrUpdate":      fixedCalls(len(upsertKeys)+1, "a lookup per matching key, then the create or update"),
+		"addTag":              fixedCalls(2, "read the tags, then write them back"),
+		"removeTag":           fixedCalls(2, "read the tags, then write them back"),
+		"updateDeviceGroup":   fixedCalls(2, "read the group, then write the changes"),
+		"updateSite":          fixedCalls(2, "read the site, then write the changes"),
+		"updateServiceGroup":  fixedCalls(2, "read the group, then write the changes"),
+		"scorecard":           fixedCalls(3, "resource details, metrics and open alerts"),
+		"getDetailed":         estimateDetailedResource,
+		"bulkUpdate":          estimateBulkAction,
+		"bulkDelete":          estimateBulkAction,
+		"planDelete":          estimatePlanDelete,
+		"inferPropertySchema": estimateSchemaSample,
+		"getMetricCatalog":    estimateMetricCatalog,
+		"onboard":             estimateOnboarding,
+		"impactAnalysis":      estimateImpactAnalysis,
+		"timelineReport":      estimateTimeline,
+	},
+	"alerts": {
+		"listRules":      noCalls,
+		"saveRule":       noCalls,
+		"deleteRule":     noCalls,
+		"getThread":      fixedCalls(2, "notes and attachments"),
+		"previewRouting": fixedCalls(2, "the alert and the escalation policies"),
+		"acknowledge":    estimateAlertBatch,
+		"close":          estimateAlertBatch,
+	},
+	"integrations": {
+		"scheduleEnable":  noCalls,
+		"scheduleDisable": noCalls,
+		"listSchedules":   noCalls,
+		"cancelSchedule":  noCalls,
+		"invalidateCache": noCalls,
+		"findOrphanedIntegrations": fixedCalls(1+maxOrphanResourceScan/orphanPageSize+maxOrphanEventScan/orphanPageSize,
+			"the integrations, then up to a full scan of resources and recent alerts"),
+	},
+	"admin": {
+		"selfTest":            fixedCalls(len(selfTestProbes), "one probe per tool"),
+		"validateCredentials": fixedCalls(2, "a token exchange and a test call per tenant"),
+		"onboardTenant":       fixedCalls(3, "credential validation and catalog warm-up"),
+	},
+}
+
+// estimateBulkAction counts the calls of a bulk update or delete: one, after
+// a scan of up to maxSelectorScan resources to resolve a selector
+func estimateBulkAction(req mcp.CallToolRequest) callCost {
+	if _, ok := req.GetArguments()["selector"].(map[string]interface{}); ok {
+		return callCost{calls: 1 + maxSelectorScan/selectorPageSize, basis: "a selector scan of up to 5000 resources and the bulk request"}
+	}
+	return callCost{calls: 1, basis: "one bulk request"}
+}
+
+// estimateDetailedResource counts the resource details call and one call per
+// included section
+func estimateDetailedResource(req mcp.CallToolRequest) callCost {
+	include := len(req.GetStringSlice("include", nil))
+	if include == 0 {
+		return callCost{calls: 1, basis: "the resource details"}
+	}
+	return callCost{calls: 1 + include, basis: "the resource details and one call per included section"}
+}
+
+// estimateAlertBatch counts the action requests of a batch acknowledge or
+// close, one per chunk, after the search pages of a query. Chunks OpsRamp
+// rejects are retried one alert at a time, which is not counted.
+func estimateAlertBatch(req mcp.CallToolRequest) callCost {
+	if ids := len(req.GetStringSlice("ids", nil)); ids > 0 {
+		return callCost{calls: (ids + alertActionChunkSize - 1) / alertActionChunkSize, basis: "one action request per 100 alerts"}
+	}
+	pages := maxAlertBatch / alertBatchPageSize
+	if !req.GetBool("confirm", false) {
+		return callCost{calls: pages, basis: "the search pages of up to 1000 matching alerts"}
+	}
+	return callCost{calls: pages + maxAlertBatch/alertActionChunkSize, basis: "the search pages of up to 1000 matching alerts and one action request per 100"}
+}
+
+// estimateGroupSnapshot counts the device group and site listings of a group
+// snapshot and a members lookup per device group, as many as in the tenant's
+// last snapshot
+func estimateGroupSnapshot(req mcp.CallToolRequest) callCost {
+	groups := groupSnapshotEstimate
+	if latest, err := NewGroupHistory(nil, requestTenant(req)).Latest(); err == nil && latest != nil {
+		groups = len(latest.DeviceGroups)
+	}
+	return callCost{calls: 2 + groups, basis: fmt.Sprintf("the device group and site listings and a members lookup for each of about %d device groups", groups)}
+}
+
+// estimateResourceSearch counts the pages of a list or search, which reads
+// every page up to maxAllPagesResources resources with allPages and one
+// page otherwise
+func estimateResourceSearch(req mcp.CallToolRequest) callCost {
+	if !req.GetBool("allPages", false) {
+		return callCost{calls: 1, basis: "one page of results"}
+	}
+	pageSize := allPagesPageSize
+	if params, ok := req.GetArguments()["params"].(map[string]interface{}); ok {
+		if size, ok := params["pageSize"].(float64); ok && size > 0 {
+			pageSize = int(size)
+		}
+	}
+	return callCost{
+		calls: (maxAllPagesResources + pageSize - 1) / pageSize,
+		basis: fmt.Sprintf("every page of %d resources, up to %d resources", pageSize, maxAllPagesResources),
+	}
+}
+
+// estimatePlanDelete counts the calls of planDelete: a lookup per ID, or a
+// selector scan
+func estimatePlanDelete(req mcp.CallToolRequest) callCost {
+	if _, ok := req.GetArguments()["selector"].(map[string]interface{}); ok {
+		return callCost{calls: maxSelectorScan / selectorPageSize, basis: "a selector scan of up to 5000 resources"}
+	}
+	ids := len(req.GetStringSlice("ids", nil))
+	return callCost{calls: max(ids, 1), basis: "one lookup per ID"}
+}
+
+// estimateSchemaSample counts the pages of a property schema sample
+func estimateSchemaSample(req mcp.CallToolRequest) callCost {
+	sample := req.GetInt("sampleSize", defaultSchemaSample)
+	if sample <= 0 {
+		sample = defaultSchemaSample
+	}
+	pages := func(sample int) int { return (min(sample, maxSchemaSample) + schemaPageSize - 1) / schemaPageSize }
+	return callCost{
+		calls: pages(sample),
+		basis: fmt.Sprintf("one page of %d resources per %d sampled", schemaPageSize, schemaPageSize),
+		trim:  &callTrim{arg: "sampleSize", value: min(sample, maxSchemaSample), min: 1, calls: pages},
+	}
+}
+
+// estimateMetricCatalog counts the search pages, one detail call per
+// sampled resource and the metric reads of a catalog that is not remembered
+func estimateMetricCatalog(req mcp.CallToolRequest) callCost {
+	sample := req.GetInt("sampleSize", defaultMetricCatalogSample)
+	if sample <= 0 {
+		sample = defaultMetricCatalogSample
+	}
+	calls := func(sample int) int {
+		sample = min(sample, maxMetricCatalogSample)
+		return (sample+schemaPageSize-1)/schemaPageSize + sample + min(sample, metricRangeSample)
+	}
+	return callCost{
+		calls: calls(sample),
+		basis: "the search, one detail call per sampled resource and a day of metrics on a few of them, unless a recent catalog is reused",
+		trim:  &callTrim{arg: "sampleSize", value: min(sample, maxMetricCatalogSample), min: 1, calls: calls},
+	}
+}
+
+// estimateOnboarding counts a candidate search and, unless it is a dry run,
+// the steps applied to each resource
+func estimateOnboarding(req mcp.CallToolRequest) callCost {
+	config, _ := req.GetArguments()["config"].(map[string]interface{})
+	if dryRun, _ := config["dryRun"].(bool); dryRun {
+		return callCost{calls: 1, basis: "the candidate search of a dry run"}
+	}
+	limit := defaultOnboardingLimit
+	if value, ok := config["limit"].(float64); ok && value > 0 {
+		limit = min(int(value), maxOnboardingLimit)
+	}
+	steps := 1
+	for _, field := range []string{"deviceGroupId", "siteId", "templateIds", "tags"} {
+		if value, ok := config[field]; ok && value != nil && value != "" {
+			steps++
+		}
+	}
+	calls := func(limit int) int { return 1 + limit*steps }
+	return callCost{
+		calls: calls(limit),
+		basis: fmt.Sprintf("the candidate search and %d steps per onboarded resource", steps),
+		trim:  &callTrim{arg: "config.limit", value: limit, min: 1, calls: calls},
+	}
+}
+
+// estimateImpactAnalysis counts the relationship and service group lookups
+// of each resource visited, assuming impactFanOut dependents per resource,
+// and the open-alert queries
+func estimateImpactAnalysis(req mcp.CallToolRequest) callCost {
+	depth := req.GetInt("depth", defaultImpactDepth)
+	if depth <= 0 {
+		depth = defaultImpactDepth
+	}
+	calls := func(depth int) int {
+		visited, level := 1, 1
+		for i := 0; i < min(depth, maxImpactDepth); i++ {
+			level *= impactFanOut
+			visited += level
+		}
+		visited = min(visited, maxImpactedResources+1)
+		return 1 + 2*visited + (visited+impactAlertBatchSize-1)/impactAlertBatchSize
+	}
+	return callCost{
+		calls: calls(depth),
+		basis: fmt.Sprintf("two lookups per resource visited, assuming %d dependents per resource, and the open-alert queries", impactFanOut),
+		trim:  &callTrim{arg: "depth", value: min(depth, maxImpactDepth), min: 1, calls: calls},
+	}
+}
+
+// estimateTimeline counts the calls of a timeline: the resource's details,
+// metrics and alert pages, for each member of a service group
+func estimateTimeline(req mcp.CallToolRequest) callCost {
+	alertPages := maxTimelineAlerts / timelinePageSize
+	if req.GetString("serviceGroup", "") != "" {
+		return callCost{
+			calls: 1 + 2*maxTimelineResources + alertPages,
+			basis: fmt.Sprintf("the members, details and metrics of up to %d members, and the alert pages", maxTimelineResources),
+		}
+	}
+	return callCost{calls: 2 + alertPages, basis: "the resource's details and metrics, and the alert pages"}
+}
+
+// EstimateCalls predicts the OpsRamp API calls a tool call will make
+func EstimateCalls(req mcp.CallToolRequest) CallEstimate {
+	estimate, _ := estimateCalls(req)
+	return estimate
+}
+
+// estimateCalls predicts the calls of a tool call and returns the argument
+// bounding them, if there is one. A fan-out multiplies the calls by its
+// tenants.
+func estimateCalls(req mcp.CallToolRequest) (CallEstimate, *callTrim) {
+	tool, action := req.Params.Name, req.GetString("action", "")
+	cost := callCost{calls: 1, basis: "one API request"}
+	if localTools[tool] {
+		cost = noCalls(req)
+	}
+	if estimator, ok := callEstimators[tool][action]; ok {
+		cost = estimator(req)
+	}
+
+	estimate := CallEstimate{Tool: tool, Action: action, Calls: cost.calls, Basis: cost.basis}
+	if names := req.GetStringSlice("tenants", nil); len(names) > 0 && fanOutActions[tool][action] {
+		if tenants, err := fanOutTenants(names); err == nil && len(tenants) > 0 {
+			estimate.Tenants = len(tenants)
+			estimate.Calls *= len(tenants)
+		}
+	}
+	if cost.trim != nil {
+		estimate.Trimmable = cost.trim.arg
+	}
+	return estimate, cost.trim
+}
+
+// budgetCharge is the calls charged to a budget at a time
+type budgetCharge struct {
+	at    time.Time
+	calls int
+}
+
+// CallBudget tracks the estimated OpsRamp calls of each session and of all
+// sessions over the last hour against the configured budgets
+type CallBudget struct {
+	mu       sync.Mutex
+	config   common.BudgetsConfig
+	sessions map[string][]budgetCharge
+	global   []budgetCharge
+	now      func() time.Time
+}
+
+// NewCallBudget creates a budget tracker for the configured budgets
+func NewCallBudget(config common.BudgetsConfig) *CallBudget {
+	if config.Mode == "" {
+		config.Mode = common.BudgetModeTrim
+	}
+	return &CallBudget{config: config, sessions: map[string][]budgetCharge{}, now: time.Now}
+}
+
+var (
+	callBudgetOnce sync.Once
+	callBudget     *CallBudget
+)
+
+// getCallBudget returns the budget tracker of the configured budgets
+func getCallBudget() *CallBudget {
+	callBudgetOnce.Do(func() {
+		budgets := common.BudgetsConfig{}
+		if config, err := common.LoadConfig(""); err == nil {
+			budgets = config.Budgets
+		}
+		callBudget = NewCallBudget(budgets)
+	})
+	return callBudget
+}
+
+// enabled reports whether any budget is set
+func (b *CallBudget) enabled() bool {
+	return b.config.SessionCallsPerHour > 0 || b.config.CallsPerHour > 0
+}
+
+// used drops the charges older than the budget window and returns the
+// calls of the rest; the caller holds the lock
+func (b *CallBudget) used(charges []budgetCharge) ([]budgetCharge, int) {
+	cutoff := b.now().Add(-budgetWindow)
+	kept := charges[:0]
+	total := 0
+	for _, charge := range charges {
+		if charge.at.After(cutoff) {
+			kept = append(kept, charge)
+			total += charge.calls
+		}
+	}
+	return kept, total
+}
+
+// sessionUsed returns the calls charged to a session in the budget window,
+// forgetting the session once it has none; the caller holds the lock
+func (b *CallBudget) sessionUsed(sessionID string) int {
+	charges, used := b.used(b.sessions[sessionID])
+	if len(charges) == 0 {
+		delete(b.sessions, sessionID)
+	} else {
+		b.sessions[sessionID] = charges
+	}
+	return used
+}
+
+// globalUsed returns the calls charged to all sessions in the budget window;
+// the caller holds the lock
+func (b *CallBudget) globalUsed() int {
+	var used int
+	b.global, used = b.used(b.global)
+	return used
+}
+
+// remaining returns the calls left to a session under the tightest budget,
+// or -1 when neither budget is set, with a description of that budget; the
+// caller holds the lock
+func (b *CallBudget) remaining(sessionID string) (int, string) {
+	left, limit := -1, ""
+	if b.config.SessionCallsPerHour > 0 {
+		left = max(b.config.SessionCallsPerHour-b.sessionUsed(sessionID), 0)
+		limit = fmt.Sprintf("this session's budget of %d OpsRamp calls per hour", b.config.SessionCallsPerHour)
+	}
+	if b.config.CallsPerHour > 0 {
+		if globalLeft := max(b.config.CallsPerHour-b.globalUsed(), 0); left < 0 || globalLeft < left {
+			left = globalLeft
+			limit = fmt.Sprintf("the server's budget of %d OpsRamp calls per hour", b.config.CallsPerHour)
+		}
+	}
+	return left, limit
+}
+
+// Reserve charges a call's estimate to the budgets. When it does not fit and
+// the budget mode is trim, the largest value of the trimmed argument that
+// fits is charged and returned instead; otherwise the error explains which
+// budget the call exceeds.
+func (b *CallBudget) Reserve(sessionID string, estimate CallEstimate, trim *callTrim) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	left, limit := b.remaining(sessionID)
+	calls, trimmed := estimate.Calls, 0
+	if left >= 0 && calls > left {
+		call := estimate.Tool
+		if estimate.Action != "" {
+			call += " " + estimate.Action
+		}
+		if trim == nil || b.config.Mode != common.BudgetModeTrim {
+			return 0, fmt.Errorf("%s would make about %d OpsRamp calls (%s), but %s has %d left; narrow the request or try again later",
+				call, calls, estimate.Basis, limit, left)
+		}
+		tenants := max(estimate.Tenants, 1)
+		for value := trim.value - 1; value >= trim.min; value-- {
+			if trim.calls(value)*tenants <= left {
+				calls, trimmed = trim.calls(value)*tenants, value
+				break
+			}
+		}
+		if trimmed == 0 {
+			return 0, fmt.Errorf("%s would make about %d OpsRamp calls (%s), and even %s %d needs %d, but %s has %d left; try again later",
+				call, calls, estimate.Basis, trim.arg, trim.min, trim.calls(trim.min)*tenants, limit, left)
+		}
+	}
+
+	charge := budgetCharge{at: b.now(), calls: calls}
+	if b.config.SessionCallsPerHour > 0 {
+		b.sessions[sessionID] = append(b.sessions[sessionID], charge)
+	}
+	if b.config.CallsPerHour > 0 {
+		b.global = append(b.global, charge)
+	}
+	return trimmed, nil
+}
+
+// Status reports a session's use of the budgets
+func (b *CallBudget) Status(sessionID string) BudgetStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := BudgetStatus{
+		Mode:                b.config.Mode,
+		SessionCallsPerHour: b.config.SessionCallsPerHour,
+		CallsPerHour:        b.config.CallsPerHour,
+	}
+	status.SessionCallsUsed = b.sessionUsed(sessionID)
+	status.CallsUsed = b.globalUsed()
+	if left, _ := b.remaining(sessionID); left >= 0 {
+		status.Remaining = &left
+	}
+	return status
+}
+
+// Plan reports a session's use of the budgets and, given a planned call,
+// its estimate and whether it fits in what is left
+func (b *CallBudget) Plan(sessionID string, planned *mcp.CallToolRequest) BudgetStatus {
+	status := b.Status(sessionID)
+	if planned == nil {
+		return status
+	}
+	estimate := EstimateCalls(*planned)
+	status.Estimate = &estimate
+	within := status.Remaining == nil || estimate.Calls <= *status.Remaining
+	status.WithinBudget = &within
+	return status
+}
+
+// withTrimmedArgument returns a copy of a request with the trimmed argument
+// set to value
+func withTrimmedArgument(req mcp.CallToolRequest, trim *callTrim, value int) mcp.CallToolRequest {
+	args := make(map[string]interface{}, len(req.GetArguments()))
+	for name, arg := range req.GetArguments() {
+		args[name] = arg
+	}
+	if field, ok := strings.CutPrefix(trim.arg, "config."); ok {
+		config := map[string]interface{}{}
+		if existing, ok := args["config"].(map[string]interface{}); ok {
+			for name, arg := range existing {
+				config[name] = arg
+			}
+		}
+		config[field] = float64(value)
+		args["config"] = config
+	} else {
+		args[trim.arg] = float64(value)
+	}
+	req.Params.Arguments = args
+	return req
+}
+
+// BudgetMiddleware charges the estimated OpsRamp calls of each tool call to
+// the configured budgets before it runs. A call over budget is trimmed to
+// fit when it can be, with a note added to its result, and rejected with an
+// explanation otherwise. It is registered after ExamplesMiddleware, so calls
+// answered from examples are not charged.
+func BudgetMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		budget := getCallBudget()
+		if !budget.enabled() {
+			return next(ctx, req)
+		}
+		estimate, trim := estimateCalls(req)
+		if estimate.Calls == 0 {
+			return next(ctx, req)
+		}
+
+		trimmed, err := budget.Reserve(session.IDFromContext(ctx), estimate, trim)
+		if err != nil {
+			common.GetLogger().Warn("Call budget rejected %s %s: %v", estimate.Tool, estimate.Action, err)
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Call budget exceeded: " + err.Error()}},
+			}, nil
+		}
+		if trimmed == 0 {
+			return next(ctx, req)
+		}
+
+		result, err := next(ctx, withTrimmedArgument(req, trim, trimmed))
+		if err == nil && result != nil && !result.IsError {
+			result.Content = append(result.Content, mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("Note: %s was lowered from %d to %d to stay within the OpsRamp call budget; the result may be incomplete.", trim.arg, trim.value, trimmed),
+			})
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func budgetRequest(tool string, args map[string]interface{}) (CallEstimate, *callTrim) {
+	req := createTestRequest(args)
+	req.Params.Name = tool
+	return estimateCalls(req)
+}
+
+func TestEstimateCalls(t *testing.T) {
+	tests := []struct {
+		tool  string
+		args  map[string]interface{}
+		calls int
+	}{
+		{"resources", map[string]interface{}{"action": "get", "id": "r1"}, 1},
+		{"resources", map[string]interface{}{"action": "listTemplates"}, 0},
+		{"session", map[string]interface{}{"action": "getContext"}, 0},
+		{"resources", map[string]interface{}{"action": "inferPropertySchema", "sampleSize": float64(450)}, 5},
+		{"resources", map[string]interface{}{"action": "bulkDelete", "selector": map[string]interface{}{"state": "inactive"}}, 51},
+		{"resources", map[string]interface{}{"action": "planDelete", "ids": []interface{}{"a", "b", "c"}}, 3},
+		{"resources", map[string]interface{}{"action": "onboard", "config": map[string]interface{}{"limit": float64(10), "siteId": "s1"}}, 21},
+		{"resources", map[string]interface{}{"action": "onboard", "config": map[string]interface{}{"dryRun": true}}, 1},
+		// 1 + 3 + 9 = 13 resources visited: 26 lookups, an alert query and the root
+		{"resources", map[string]interface{}{"action": "impactAnalysis", "id": "r1", "depth": float64(2)}, 28},
+		{"admin", map[string]interface{}{"action": "selfTest"}, len(selfTestProbes)},
+	}
+	for _, tt := range tests {
+		estimate, _ := budgetRequest(tt.tool, tt.args)
+		if estimate.Calls != tt.calls {
+			t.Errorf("%s %v: expected %d calls, got %d (%s)", tt.tool, tt.args["action"], tt.calls, estimate.Calls, estimate.Basis)
+		}
+	}
+}
+
+func TestCallBudgetTrimsAndRejects(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	budget := NewCallBudget(common.BudgetsConfig{SessionCallsPerHour: 5, CallsPerHour: 100})
+	budget.now = func() time.Time { return now }
+
+	estimate, trim := budgetRequest("resources", map[string]interface{}{"action": "inferPropertySchema", "sampleSize": float64(1000)})
+	trimmed, err := budget.Reserve("s1", estimate, trim)
+	if err != nil || trimmed != 500 {
+		t.Fatalf("Expected the sample trimmed to fit 5 pages, got %d (%v)", trimmed, err)
+	}
+
+	// The session's budget is spent; an untrimmable call is rejected
+	estimate, trim = budgetRequest("resources", map[string]interface{}{"action": "get", "id": "r1"})
+	if _, err := budget.Reserve("s1", estimate, trim); err == nil || !strings.Contains(err.Error(), "this session's budget of 5 OpsRamp calls per hour has 0 left") {
+		t.Errorf("Expected the call to be rejected with the session budget, got %v", err)
+	}
+	if _, err := budget.Reserve("s2", estimate, trim); err != nil {
+		t.Errorf("Expected another session to have its own budget, got %v", err)
+	}
+
+	status := budget.Status("s1")
+	if status.SessionCallsUsed != 5 || status.CallsUsed != 6 || status.Remaining == nil || *status.Remaining != 0 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+
+	// Charges leave the budget an hour after they were made
+	now = now.Add(budgetWindow)
+	if _, err := budget.Reserve("s1", estimate, trim); err != nil {
+		t.Errorf("Expected the budget to recover after an hour, got %v", err)
+	}
+
+	reject := NewCallBudget(common.BudgetsConfig{SessionCallsPerHour: 3, Mode: common.BudgetModeReject})
+	estimate, trim = budgetRequest("resources", map[string]interface{}{"action": "impactAnalysis", "id": "r1"})
+	if _, err := reject.Reserve("s1", estimate, trim); err == nil || !strings.Contains(err.Error(), "resources impactAnalysis would make about") {
+		t.Errorf("Expected reject mode not to trim, got %v", err)
+	}
+}
+
+func TestWithTrimmedArgumentCopiesArguments(t *testing.T) {
+	config := map[string]interface{}{"limit": float64(50), "siteId": "s1"}
+	req := createTestRequest(map[string]interface{}{"action": "onboard", "config": config})
+	req.Params.Name = "resources"
+	_, trim := estimateCalls(req)
+
+	trimmed := withTrimmedArgument(req, trim, 5)
+	if got := trimmed.GetArguments()["config"].(map[string]interface{})["limit"]; got != float64(5) {
+		t.Errorf("Expected config.limit to be trimmed, got %v", got)
+	}
+	if config["limit"] != float64(50) {
+		t.Error("Expected the original arguments to be left unchanged")
+	}
+}
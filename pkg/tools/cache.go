@@ -0,0 +1,20 @@
+package tools
+
+import "github.com/opsramp/or-mcp-v2/pkg/types"
+
+// CacheInvalidator is implemented by APIs that cache OpsRamp responses.
+// InvalidateCache drops the cached responses of the API's tenant, so the
+// next reads fetch them from OpsRamp, and reports how many were dropped.
+type CacheInvalidator interface {
+	InvalidateCache() types.CacheInvalidation
+}
+
+// invalidateCache drops the cached responses of api; an API without a cache
+// reports that nothing was dropped
+func invalidateCache(api interface{}) *types.CacheInvalidation {
+	var result types.CacheInvalidation
+	if invalidator, ok := api.(CacheInvalidator); ok {
+		result = invalidator.InvalidateCache()
+	}
+	return &result
+}
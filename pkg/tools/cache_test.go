@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestResourceSearchCache(t *testing.T) {
+	searches := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token":"token-1","token_type":"bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("/api/v2/tenants/client_1/resources/search", func(w http.ResponseWriter, r *http.Request) {
+		searches++
+		w.Write([]byte(`{"results":[{"id":"r1","name":"web-1"}],"totalResults":1}`))
+	})
+	mux.HandleFunc("/api/v2/tenants/client_1/resources/r1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	api := NewOpsRampResourcesAPI(client.NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: server.URL, AuthURL: server.URL + "/auth/oauth/token", AuthKey: "key-1", AuthSecret: "secret-1", TenantID: "client_1",
+		Resources: common.ResourcesConfig{CacheTTL: 300, EntityCacheTTL: 30},
+	}}))
+	ctx := context.Background()
+	search := func() *types.ResourceSearchResponse {
+		t.Helper()
+		response, err := api.Search(ctx, types.ResourceSearchParams{PageNo: 1, PageSize: 10, State: "active"})
+		if err != nil || len(response.Results) != 1 {
+			t.Fatalf("Expected one result, got %v (%v)", response, err)
+		}
+		return response
+	}
+
+	search().Results[0].Name = "changed by the caller"
+	if got := search(); searches != 1 || got.Results[0].Name != "web-1" {
+		t.Errorf("Expected a repeated search to be served from an unshared cached copy, got %d searches and %q", searches, got.Results[0].Name)
+	}
+	if _, err := api.Search(ctx, types.ResourceSearchParams{PageNo: 2, PageSize: 10}); err != nil || searches != 2 {
+		t.Errorf("Expected a different search to be fetched, got %d searches (%v)", searches, err)
+	}
+
+	if err := api.Delete(ctx, "r1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	search()
+	if searches != 3 {
+		t.Errorf("Expected a write to invalidate cached searches, got %d searches", searches)
+	}
+
+	result, _ := ResourcesToolHandler(ctx, createTestRequest(map[string]interface{}{"action": "invalidateCache"}), api)
+	if result.IsError || !strings.Contains(resultText(result), `"responses": 1`) {
+		t.Errorf("Expected invalidateCache to report the dropped search, got %s", resultText(result))
+	}
+	search()
+	if searches != 4 {
+		t.Errorf("Expected invalidateCache to drop cached searches, got %d searches", searches)
+	}
+}
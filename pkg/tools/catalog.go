@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// tools/list _meta keys describing each tool, alongside toolBackendMetaKey
+const (
+	toolCategoryMetaKey = "or-mcp/category"
+	toolRiskMetaKey     = "or-mcp/risk"
+	toolScopesMetaKey   = "or-mcp/scopes"
+	toolVersionMetaKey  = "or-mcp/version"
+)
+
+// Risk levels of a tool: the riskiest change its actions can make in OpsRamp
+const (
+	riskRead        = "read"
+	riskWrite       = "write"
+	riskDestructive = "destructive"
+)
+
+// catalogEntry describes a tool in the tools/list catalog. Version changes
+// when the tool's actions or arguments change incompatibly.
+type catalogEntry struct {
+	category string
+	risk     string
+	version  string
+	// local tools make no OpsRamp calls and so need no scope
+	local bool
+}
+
+// toolCatalog holds the catalog entry of each registered tool
+var toolCatalog = map[string]catalogEntry{
+	"resources":    {category: "inventory", risk: riskDestructive, version: "1.0.0"},
+	"integrations": {category: "integrations", risk: riskDestructive, version: "1.0.0"},
+	"monitors":     {category: "monitoring", risk: riskWrite, version: "1.0.0"},
+	"alerts":       {category: "monitoring", risk: riskWrite, version: "1.0.0"},
+	"metrics":      {category: "monitoring", risk: riskRead, version: "1.0.0"},
+	"usage":        {category: "billing", risk: riskRead, version: "1.0.0"},
+	"clients":      {category: "tenancy", risk: riskRead, version: "1.0.0"},
+	"admin":        {category: "administration", risk: riskWrite, version: "1.0.0"},
+	"session":      {category: "session", risk: riskRead, version: "1.0.0", local: true},
+	"utility":      {category: "session", risk: riskRead, version: "1.0.0", local: true},
+}
+
+// withCatalogMetadata adds a tool's catalog entry to its tools/list metadata.
+// Tools missing from toolCatalog are listed without it.
+func withCatalogMetadata(tool mcp.Tool) mcp.Tool {
+	entry, ok := toolCatalog[tool.Name]
+	if !ok {
+		return tool
+	}
+	meta := make(map[string]any, len(tool.Meta)+4)
+	for key, value := range tool.Meta {
+		meta[key] = value
+	}
+	meta[toolCategoryMetaKey] = entry.category
+	meta[toolRiskMetaKey] = entry.risk
+	meta[toolVersionMetaKey] = entry.version
+	scopes := []string{}
+	if !entry.local {
+		scopes = append(scopes, string(ToolScope(tool.Name)))
+	}
+	meta[toolScopesMetaKey] = scopes
+	tool.Meta = meta
+	return tool
+}
+
+// toolCursor returns the opaque cursor of the page after a tool. It encodes
+// the tool's name the way mcp-go does, so a cursor is understood whether or
+// not the server has already skipped the earlier tools.
+func toolCursor(name string) mcp.Cursor {
+	return mcp.Cursor(base64.StdEncoding.EncodeToString([]byte(name)))
+}
+
+// paginateTools returns the page of tools after a cursor, sorted by name, and
+// the cursor of the next page, or "" on the last page. An unreadable cursor
+// starts from the first tool.
+func paginateTools(tools []mcp.Tool, cursor mcp.Cursor, pageSize int) ([]mcp.Tool, mcp.Cursor) {
+	sorted := make([]mcp.Tool, len(tools))
+	copy(sorted, tools)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	start := 0
+	if after, err := base64.StdEncoding.DecodeString(string(cursor)); err == nil && cursor != "" {
+		start = sort.Search(len(sorted), func(i int) bool { return sorted[i].Name > string(after) })
+	}
+	end := len(sorted)
+	if pageSize > 0 && start+pageSize < end {
+		end = start + pageSize
+	}
+	page := sorted[start:end]
+	if end == len(sorted) || len(page) == 0 {
+		return page, ""
+	}
+	return page, toolCursor(page[len(page)-1].Name)
+}
+
+var (
+	catalogPageSizeOnce sync.Once
+	catalogPageSize     int
+)
+
+// getCatalogPageSize returns the configured tools/list page size
+func getCatalogPageSize() int {
+	catalogPageSizeOnce.Do(func() {
+		catalogPageSize = common.DefaultCatalogPageSize
+		if config, err := common.LoadConfig(""); err == nil && config.Catalog.PageSize > 0 {
+			catalogPageSize = config.Catalog.PageSize
+		}
+	})
+	return catalogPageSize
+}
+
+// CatalogListToolsHook is registered as a tools/list after-hook. It adds each
+// tool's catalog metadata and pages the catalog by the configured page size.
+func CatalogListToolsHook(ctx context.Context, id any, request *mcp.ListToolsRequest, result *mcp.ListToolsResult) {
+	if result == nil {
+		return
+	}
+	var cursor mcp.Cursor
+	if request != nil {
+		cursor = request.Params.Cursor
+	}
+	tools, next := paginateTools(result.Tools, cursor, getCatalogPageSize())
+	for i := range tools {
+		tools[i] = withCatalogMetadata(tools[i])
+	}
+	result.Tools = tools
+	result.NextCursor = next
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPaginateTools(t *testing.T) {
+	var tools []mcp.Tool
+	for i := 4; i >= 0; i-- {
+		tools = append(tools, mcp.Tool{Name: fmt.Sprintf("tool-%d", i)})
+	}
+
+	var names []string
+	var cursor mcp.Cursor
+	for pages := 0; pages < 5; pages++ {
+		var page []mcp.Tool
+		page, cursor = paginateTools(tools, cursor, 2)
+		for _, tool := range page {
+			names = append(names, tool.Name)
+		}
+		if cursor == "" {
+			break
+		}
+	}
+	if fmt.Sprint(names) != "[tool-0 tool-1 tool-2 tool-3 tool-4]" {
+		t.Errorf("Expected every tool once in name order, got %v", names)
+	}
+
+	// A page the server already started after the cursor is not skipped again
+	page, next := paginateTools(tools[:3], toolCursor("tool-1"), 2)
+	if len(page) != 2 || page[0].Name != "tool-2" || next != toolCursor("tool-3") {
+		t.Errorf("Expected tool-2 and tool-3 followed by a cursor, got %v (%q)", page, next)
+	}
+}
+
+func TestCatalogListToolsHookAddsMetadata(t *testing.T) {
+	result := &mcp.ListToolsResult{Tools: []mcp.Tool{
+		withBackend(mcp.Tool{Name: "clients"}, "live"),
+		{Name: "session"},
+		{Name: "unlisted"},
+	}}
+	CatalogListToolsHook(context.Background(), 1, &mcp.ListToolsRequest{}, result)
+
+	clients := result.Tools[0].Meta
+	if clients[toolRiskMetaKey] != riskRead || clients[toolBackendMetaKey] != "live" || fmt.Sprint(clients[toolScopesMetaKey]) != "[partner]" {
+		t.Errorf("Unexpected clients metadata %v", clients)
+	}
+	if scopes := result.Tools[1].Meta[toolScopesMetaKey].([]string); len(scopes) != 0 {
+		t.Errorf("Expected the session tool to need no scope, got %v", scopes)
+	}
+	if result.Tools[2].Meta != nil || result.NextCursor != "" {
+		t.Errorf("Expected an unlisted tool without metadata on a single page, got %v", result.Tools[2].Meta)
+	}
+}
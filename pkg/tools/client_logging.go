@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+// ClientLoggingMiddleware reports failed tool calls to the calling client as
+// MCP log notifications, so clients see errors in their log view even when the
+// model does not surface the tool result
+func ClientLoggingMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, req)
+
+		data := map[string]interface{}{
+			"event":  "toolError",
+			"tool":   req.Params.Name,
+			"action": req.GetString("action", ""),
+		}
+		switch {
+		case err != nil:
+			data["error"] = err.Error()
+		case result != nil && result.IsError:
+			data["error"] = resultText(result)
+		default:
+			return result, err
+		}
+		session.Log(ctx, mcp.LoggingLevelError, "tools", data)
+		return result, err
+	}
+}
+
+// resultText returns the text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// NewClientsMcpTool returns the MCP tool definition and handler for partner client tenants
+func NewClientsMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
+	// Get the logger
+	logger := common.GetLogger()
+
+	// Load configuration
+	config, err := common.LoadConfig("")
+	if err != nil {
+		logger.Error("Failed to load config for OpsRamp Clients API: %v", err)
+		return mcp.Tool{}, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Configuration error: %v", err)}},
+			}, nil
+		}
+	}
+
+	backend := config.ToolBackend("clients").Backend
+	opsRampClient, err := newBackendClient(config, "clients")
+	if err != nil {
+		logger.Error("Failed to initialize %s backend for OpsRamp Clients API: %v", backend, err)
+		tool, _ := createClientsTool(client.SharedClient(config), nil)
+		return withBackend(tool, backend), backendErrorHandler(err)
+	}
+	if err := requireToolScope(opsRampClient, "clients"); err != nil {
+		logger.Warn("Clients tool registered without partner access: %v", err)
+	}
+
+	logger.Info("Successfully initialized OpsRamp Clients API (%s backend)", backend)
+	tool, handler := createClientsTool(opsRampClient, NewOpsRampClientsAPI(opsRampClient))
+	return withBackend(tool, backend), handler
+}
+
+// createClientsTool creates the MCP tool with the given API implementation.
+// The tool is partner-scoped: calls are rejected up front when the targeted
+// client has no partner_id configured.
+func createClientsTool(defaultClient *client.OpsRampClient, api ClientsAPI) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "clients",
+		Description: "List and inspect client tenants under an HPE OpsRamp MSP partner. Requires partner_id to be configured.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"action": map[string]interface{}{
+					"type":        "string",
+					"description": "Action to perform: list, get",
+				},
+				"id": map[string]interface{}{
+					"type":        "string",
+					"description": "Client unique ID (for get)",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "OpsRamp queryString filter (for list)",
+				},
+				"pageNo": map[string]interface{}{
+					"type":        "number",
+					"description": "Page number (for list)",
+				},
+				"pageSize": map[string]interface{}{
+					"type":        "number",
+					"description": "Page size (for list)",
+				},
+				"tenant":     tenantArgument,
+				"llmCompact": llmCompactArgument,
+				"transform":  transformArgument,
+				"render":     renderArgument,
+				"columns":    columnsArgument,
+				"maxRows":    maxRowsArgument,
+			},
+			Required: []string{"action"},
+		},
+		OutputSchema: outputSchema(types.PartnerClientSearchResponse{}, types.PartnerClient{}),
+	}, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tenant, err := resolveTenant(req)
+		if err != nil {
+			return tenantErrorResult(err), nil
+		}
+
+		scopedClient, scopedAPI := defaultClient, api
+		if tenant != nil {
+			scopedClient, scopedAPI = tenant.Client, NewOpsRampClientsAPI(tenant.Client)
+		}
+		if scopedClient != nil {
+			if err := requireToolScope(scopedClient, "clients"); err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+				}, nil
+			}
+		}
+		return ClientsToolHandler(ctx, req, scopedAPI)
+	}
+}
+
+// ClientsToolHandler routes requests to the correct method
+// Exported for testing purposes
+func ClientsToolHandler(ctx context.Context, req mcp.CallToolRequest, api ClientsAPI) (*mcp.CallToolResult, error) {
+	action := req.GetString("action", "")
+	id := req.GetString("id", "")
+
+	// Log the tool execution
+	logger := toolLogger("clients")
+	logger.LogToolExecution("clients", action, req.GetArguments())
+
+	var err error
+	var result interface{}
+
+	switch action {
+	case "list":
+		logger.Info("Executing List partner clients")
+		result, err = api.Search(ctx, req.GetString("query", ""), req.GetInt("pageNo", 0), req.GetInt("pageSize", 0))
+	case "get":
+		if id == "" {
+			err = fmt.Errorf("id is required for get")
+			break
+		}
+		logger.Info("Executing Get partner client: %s", id)
+		result, err = api.Get(ctx, id)
+	default:
+		logger.Error("Unknown action: %s", action)
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Unknown action: %s", action)}},
+		}, nil
+	}
+
+	// Log the result
+	logger.LogToolResult("clients", action, result, err)
+
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+
+	toolResult, err := respondJSON(ctx, req, result)
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+	return toolResult, nil
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// ClientsAPI defines the contract for partner-scoped client tenant operations
+type ClientsAPI interface {
+	// Search lists the client tenants under the partner
+	Search(ctx context.Context, queryString string, pageNo, pageSize int) (*types.PartnerClientSearchResponse, error)
+
+	// Get retrieves a client tenant by its unique ID
+	Get(ctx context.Context, id string) (*types.PartnerClient, error)
+}
+
+// OpsRampClientsAPI implements the ClientsAPI interface for OpsRamp
+type OpsRampClientsAPI struct {
+	client *client.OpsRampClient
+	logger *common.CustomLogger
+}
+
+// NewOpsRampClientsAPI creates a new OpsRamp clients API client
+func NewOpsRampClientsAPI(client *client.OpsRampClient) *OpsRampClientsAPI {
+	return &OpsRampClientsAPI{
+		client: client,
+		logger: client.Logger().WithModule(toolModule("clients")),
+	}
+}
+
+// Search lists the client tenants under the partner
+func (api *OpsRampClientsAPI) Search(ctx context.Context, queryString string, pageNo, pageSize int) (*types.PartnerClientSearchResponse, error) {
+	api.logger.Info("Searching partner clients with query: %s", queryString)
+
+	endpoint, err := api.client.EndpointPath("clients.search")
+	if err != nil {
+		return nil, fmt.Errorf("failed to search clients: %w", err)
+	}
+
+	query := url.Values{}
+	if queryString != "" {
+		query.Set("queryString", queryString)
+	}
+	if pageNo > 0 {
+		query.Set("pageNo", strconv.Itoa(pageNo))
+	}
+	if pageSize > 0 {
+		query.Set("pageSize", strconv.Itoa(pageSize))
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var response types.PartnerClientSearchResponse
+	if err := api.client.Get(ctx, endpoint, &response); err != nil {
+		api.logger.Error("Failed to search clients: %v", err)
+		return nil, fmt.Errorf("failed to search clients: %w", err)
+	}
+
+	response.Normalize(pageNo, pageSize)
+	api.logger.Info("Found %d clients (total: %d)", len(response.Results), response.TotalResults)
+	return &response, nil
+}
+
+// Get retrieves a client tenant by its unique ID
+func (api *OpsRampClientsAPI) Get(ctx context.Context, id string) (*types.PartnerClient, error) {
+	api.logger.Info("Getting partner client: %s", id)
+
+	endpoint, err := api.client.EndpointPath("clients.get", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var partnerClient types.PartnerClient
+	if err := api.client.Get(ctx, endpoint, &partnerClient); err != nil {
+		api.logger.Error("Failed to get client %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	return &partnerClient, nil
+}
@@ -0,0 +1,268 @@
+package tools
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolProperties records the input schema properties each registered tool
+// declares, so arguments can be coerced to their declared types
+var toolProperties sync.Map
+
+// coercion is a string argument converted to the type its schema or target
+// struct declares
+type coercion struct {
+	path string
+	from string
+	to   interface{}
+}
+
+// coerceScalar converts a numeric or boolean string to the JSON kind wanted:
+// "integer", "number" or "boolean". Other values are returned unchanged.
+func coerceScalar(value interface{}, kind string) (interface{}, bool) {
+	text, ok := value.(string)
+	if !ok {
+		return value, false
+	}
+	trimmed := strings.TrimSpace(text)
+	switch kind {
+	case "integer":
+		if n, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return float64(n), true
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(trimmed, 64); err == nil {
+			return n, true
+		}
+	case "boolean":
+		switch strings.ToLower(trimmed) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return value, false
+}
+
+// coerceBySchema converts the numeric and boolean strings of a value to the
+// types its JSON schema declares, following object properties and array
+// items. Maps and slices are copied rather than changed in place.
+func coerceBySchema(schema map[string]interface{}, value interface{}, path string, coerced *[]coercion) interface{} {
+	kind, _ := schema["type"].(string)
+	switch kind {
+	case "integer", "number", "boolean":
+		converted, ok := coerceScalar(value, kind)
+		if ok {
+			*coerced = append(*coerced, coercion{path: path, from: value.(string), to: converted})
+		}
+		return converted
+	case "object":
+		properties, _ := schema["properties"].(map[string]interface{})
+		object, ok := value.(map[string]interface{})
+		if !ok || len(properties) == 0 {
+			return value
+		}
+		copied := make(map[string]interface{}, len(object))
+		for name, field := range object {
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				field = coerceBySchema(property, field, path+"."+name, coerced)
+			}
+			copied[name] = field
+		}
+		return copied
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		list, ok := value.([]interface{})
+		if !ok || items == nil {
+			return value
+		}
+		copied := make([]interface{}, len(list))
+		for i, item := range list {
+			copied[i] = coerceBySchema(items, item, fmt.Sprintf("%s[%d]", path, i), coerced)
+		}
+		return copied
+	}
+	return value
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// coerceByType converts the numeric and boolean strings of a decoded JSON
+// value to the kinds of the Go type it will be unmarshaled into, matching
+// object keys to fields by their JSON names without case, as encoding/json
+// does. Types with their own unmarshaling are left to it.
+func coerceByType(t reflect.Type, value interface{}, path string, coerced *[]coercion) interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return value
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return coerceScalarAt(value, "integer", path, coerced)
+	case reflect.Float32, reflect.Float64:
+		return coerceScalarAt(value, "number", path, coerced)
+	case reflect.Bool:
+		return coerceScalarAt(value, "boolean", path, coerced)
+	case reflect.Slice, reflect.Array:
+		list, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		copied := make([]interface{}, len(list))
+		for i, item := range list {
+			copied[i] = coerceByType(t.Elem(), item, fmt.Sprintf("%s[%d]", path, i), coerced)
+		}
+		return copied
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		copied := make(map[string]interface{}, len(object))
+		for name, field := range object {
+			copied[name] = coerceByType(t.Elem(), field, path+"."+name, coerced)
+		}
+		return copied
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		fields := jsonFields(t)
+		copied := make(map[string]interface{}, len(object))
+		for name, field := range object {
+			if fieldType, ok := lookupJSONField(fields, name); ok {
+				field = coerceByType(fieldType, field, path+"."+name, coerced)
+			}
+			copied[name] = field
+		}
+		return copied
+	}
+	return value
+}
+
+// coerceScalarAt converts a scalar with coerceScalar and records the coercion
+func coerceScalarAt(value interface{}, kind, path string, coerced *[]coercion) interface{} {
+	converted, ok := coerceScalar(value, kind)
+	if ok {
+		*coerced = append(*coerced, coercion{path: path, from: value.(string), to: converted})
+	}
+	return converted
+}
+
+// jsonFields returns the types of a struct's fields by JSON name, including
+// the fields of embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for inner, innerType := range jsonFields(embedded) {
+					if _, ok := fields[inner]; !ok {
+						fields[inner] = innerType
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// lookupJSONField returns the type of the field with a JSON name, matched
+// without case when there is no exact match
+func lookupJSONField(fields map[string]reflect.Type, name string) (reflect.Type, bool) {
+	if t, ok := fields[name]; ok {
+		return t, true
+	}
+	for field, t := range fields {
+		if strings.EqualFold(field, name) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// logCoercions logs each argument a tool call had coerced
+func logCoercions(tool string, coerced []coercion) {
+	logger := toolLogger(tool)
+	for _, c := range coerced {
+		logger.Info("Coerced %s argument %s from string %q to %v", tool, strings.TrimPrefix(c.path, "."), c.from, c.to)
+	}
+}
+
+// coerceArgument converts the numeric and boolean strings of an argument to
+// the field types of target, the pointer it will be decoded into
+func coerceArgument(tool, name string, value, target interface{}) interface{} {
+	var coerced []coercion
+	value = coerceByType(reflect.TypeOf(target), value, name, &coerced)
+	logCoercions(tool, coerced)
+	return value
+}
+
+// decodeArgument decodes an object argument into target, a pointer to the
+// request type it describes, after coercing numeric and boolean strings to
+// the types of its fields
+func decodeArgument(tool, name string, value, target interface{}) error {
+	payload, err := json.Marshal(coerceArgument(tool, name, value, target))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, target)
+}
+
+// ArgumentCoercionMiddleware converts string arguments holding numbers or
+// booleans to the types the tool's input schema declares, such as "5" for
+// an integer or "true" for a boolean, and logs each conversion. Strings
+// that do not parse are left for the tool to reject.
+func ArgumentCoercionMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		properties, ok := toolProperties.Load(req.Params.Name)
+		args := req.GetArguments()
+		if !ok || len(args) == 0 {
+			return next(ctx, req)
+		}
+
+		var coerced []coercion
+		schema := map[string]interface{}{"type": "object", "properties": properties}
+		converted := coerceBySchema(schema, args, "", &coerced)
+		if len(coerced) > 0 {
+			logCoercions(req.Params.Name, coerced)
+			req.Params.Arguments = converted
+		}
+		return next(ctx, req)
+	}
+}
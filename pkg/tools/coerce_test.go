@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestArgumentCoercionMiddleware(t *testing.T) {
+	RegisterToolSchema(mcp.Tool{
+		Name: "coercion-test",
+		InputSchema: mcp.ToolInputSchema{Properties: map[string]interface{}{
+			"depth":   map[string]interface{}{"type": "integer"},
+			"confirm": map[string]interface{}{"type": "boolean"},
+			"name":    map[string]interface{}{"type": "string"},
+			"limits": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "number"},
+			},
+		}},
+	})
+
+	var seen map[string]interface{}
+	handler := ArgumentCoercionMiddleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = req.GetArguments()
+		return &mcp.CallToolResult{}, nil
+	})
+
+	args := map[string]interface{}{"depth": "5", "confirm": "TRUE", "name": "42", "limits": []interface{}{"0.5", 2.0, "x"}}
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "coercion-test"
+	req.Params.Arguments = args
+	handler(context.Background(), req)
+
+	if seen["depth"] != float64(5) || seen["confirm"] != true || seen["name"] != "42" {
+		t.Errorf("Expected depth and confirm coerced and name kept, got %v", seen)
+	}
+	if limits := seen["limits"].([]interface{}); limits[0] != 0.5 || limits[1] != 2.0 || limits[2] != "x" {
+		t.Errorf("Expected numeric array items coerced and others kept, got %v", limits)
+	}
+	if args["depth"] != "5" {
+		t.Error("Expected the caller's arguments to be left unchanged")
+	}
+}
+
+func TestDecodeArgumentCoercesStructFields(t *testing.T) {
+	var params types.ResourceSearchParams
+	err := decodeArgument("resources", "params", map[string]interface{}{
+		"pageNo": "2", "PAGESIZE": " 50 ", "isDescendingOrder": "true", "queryString": "12",
+	}, &params)
+	if err != nil {
+		t.Fatalf("Expected string numbers and booleans to decode, got %v", err)
+	}
+	if params.PageNo != 2 || params.PageSize != 50 || !params.IsDescendingOrder || params.QueryString != "12" {
+		t.Errorf("Unexpected search params %+v", params)
+	}
+
+	var request types.OnboardingRequest
+	if err := decodeArgument("resources", "config", map[string]interface{}{
+		"limit": "10", "dryRun": "false", "filter": map[string]interface{}{"pageSize": "5"},
+	}, &request); err != nil || request.Limit != 10 || request.Filter.PageSize != 5 {
+		t.Errorf("Expected nested fields coerced, got %+v (%v)", request, err)
+	}
+
+	if err := decodeArgument("resources", "params", map[string]interface{}{"pageNo": "two"}, &params); err == nil {
+		t.Error("Expected a string that is not a number to be rejected")
+	}
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"strings"
+	"time"
+)
+
+// LLM compact mode
+//
+// When llmCompact is enabled, tool results are pruned before being returned so
+// that LLM clients spend fewer tokens on fields that rarely matter. The mapping
+// applied to every object in the result is:
+//
+//	consoles, properties, links, _links  -> removed (verbose blobs)
+//	null values                          -> removed
+//	empty strings, empty arrays/objects  -> removed
+//	timestamps (RFC 3339 / OpsRamp)      -> "2006-01-02T15:04Z" (UTC, minute precision)
+//
+// Identifiers, names, states and all non-empty scalar fields are kept unchanged.
+
+// compactDroppedFields lists keys that are removed entirely in compact mode
+var compactDroppedFields = map[string]bool{
+	"consoles":   true,
+	"properties": true,
+	"links":      true,
+	"_links":     true,
+}
+
+// compactTimestampLayouts are the timestamp formats shortened in compact mode
+var compactTimestampLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02 15:04:05",
+}
+
+// compactTimestampFormat is the shortened timestamp format
+const compactTimestampFormat = "2006-01-02T15:04Z"
+
+// compactValue prunes a decoded JSON value according to the compact mapping.
+// It returns false when the value itself should be dropped.
+func compactValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+	case string:
+		if v == "" {
+			return nil, false
+		}
+		return shortenTimestamp(v), true
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, field := range v {
+			if compactDroppedFields[key] {
+				continue
+			}
+			if pruned, keep := compactValue(field); keep {
+				out[key] = pruned
+			}
+		}
+		return out, len(out) > 0
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if pruned, keep := compactValue(item); keep {
+				out = append(out, pruned)
+			}
+		}
+		return out, len(out) > 0
+	default:
+		return v, true
+	}
+}
+
+// shortenTimestamp rewrites recognised timestamps in the compact format
+func shortenTimestamp(s string) string {
+	// Cheap pre-check: timestamps start with a four digit year and a dash
+	if len(s) < 16 || s[4] != '-' || !strings.ContainsAny(s[10:11], "T ") {
+		return s
+	}
+	for _, layout := range compactTimestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC().Format(compactTimestampFormat)
+		}
+	}
+	return s
+}
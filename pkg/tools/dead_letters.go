@@ -0,0 +1,298 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+)
+
+const (
+	// deadLetterRetention is how long failed tool calls are kept for replay
+	deadLetterRetention = 14 * 24 * time.Hour
+	// maxDeadLetters bounds the failed tool calls kept; the oldest go first
+	maxDeadLetters = 1000
+	// redactedValue replaces credential arguments of a dead-lettered call
+	redactedValue = "[REDACTED]"
+)
+
+// deadLetterExcludedTools are not captured: admin calls are run by an
+// operator who sees the failure, and session calls never reach OpsRamp
+var deadLetterExcludedTools = map[string]bool{"admin": true, "session": true}
+
+// ToolDeadLetter is a tool call that failed on OpsRamp's side, kept with its
+// arguments so it can be replayed once OpsRamp recovers. Credential
+// arguments are redacted.
+type ToolDeadLetter struct {
+	ID        string                 `json:"id"`
+	Tool      string                 `json:"tool"`
+	Action    string                 `json:"action,omitempty"`
+	Tenant    string                 `json:"tenant,omitempty"`
+	SessionID string                 `json:"sessionId,omitempty"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Redacted  []string               `json:"redacted,omitempty"`
+	Error     string                 `json:"error"`
+	// Upstream describes the last failed OpsRamp request of the call, and
+	// UpstreamFailures counts them
+	Upstream         string `json:"upstream"`
+	UpstreamFailures int    `json:"upstreamFailures"`
+	FailedAt         string `json:"failedAt"`
+	Replays          int    `json:"replays,omitempty"`
+	LastReplayAt     string `json:"lastReplayAt,omitempty"`
+	LastReplayError  string `json:"lastReplayError,omitempty"`
+}
+
+// DeadLetterReplay is the result of the replayDeadLetter admin action. A
+// call that succeeds is removed from the dead letters; one that fails again
+// is kept with the new error.
+type DeadLetterReplay struct {
+	ID         string          `json:"id"`
+	Succeeded  bool            `json:"succeeded"`
+	Result     string          `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	DeadLetter *ToolDeadLetter `json:"deadLetter,omitempty"`
+}
+
+// DeadLetters keeps failed tool calls in the state store
+type DeadLetters struct {
+	store *store.Store
+	now   func() time.Time
+}
+
+// NewDeadLetters creates the dead letters over the default state store,
+// which may be unavailable
+func NewDeadLetters() *DeadLetters {
+	return &DeadLetters{store: store.Default(), now: time.Now}
+}
+
+// ready reports why dead letters cannot be kept or read, if they cannot
+func (d *DeadLetters) ready() error {
+	if d.store == nil {
+		return errors.New("dead-lettered tool calls require the state store")
+	}
+	return nil
+}
+
+// newDeadLetterID returns an ID that sorts by failure time
+func newDeadLetterID(now time.Time) string {
+	var suffix [4]byte
+	_, _ = rand.Read(suffix[:])
+	return fmt.Sprintf("dl-%s-%s", now.UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix[:]))
+}
+
+// redactCallArguments returns a copy of args with credential values masked
+// at any depth, and the paths it masked
+func redactCallArguments(args map[string]interface{}) (map[string]interface{}, []string) {
+	var redacted []string
+	var redact func(prefix string, value interface{}) interface{}
+	redact = func(prefix string, value interface{}) interface{} {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			copied := make(map[string]interface{}, len(v))
+			for key, item := range v {
+				path := prefix + key
+				if isCredentialField(key) || strings.EqualFold(key, "authKey") {
+					copied[key] = redactedValue
+					redacted = append(redacted, path)
+					continue
+				}
+				copied[key] = redact(path+".", item)
+			}
+			return copied
+		case []interface{}:
+			copied := make([]interface{}, len(v))
+			for i, item := range v {
+				copied[i] = redact(fmt.Sprintf("%s%d.", prefix, i), item)
+			}
+			return copied
+		default:
+			return value
+		}
+	}
+	copied, _ := redact("", args).(map[string]interface{})
+	sort.Strings(redacted)
+	return copied, redacted
+}
+
+// Record keeps a failed tool call, then drops dead letters past the
+// retention period or over the cap
+func (d *DeadLetters) Record(ctx context.Context, req mcp.CallToolRequest, callErr string, failures *client.UpstreamFailures) (*ToolDeadLetter, error) {
+	if err := d.ready(); err != nil {
+		return nil, err
+	}
+	now := d.now()
+	arguments, redacted := redactCallArguments(req.GetArguments())
+	letter := &ToolDeadLetter{
+		ID:               newDeadLetterID(now),
+		Tool:             req.Params.Name,
+		Action:           req.GetString("action", ""),
+		Tenant:           req.GetString("tenant", ""),
+		SessionID:        session.IDFromContext(ctx),
+		Arguments:        arguments,
+		Redacted:         redacted,
+		Error:            callErr,
+		Upstream:         failures.Last(),
+		UpstreamFailures: failures.Count(),
+		FailedAt:         now.UTC().Format(time.RFC3339),
+	}
+	if err := d.store.Put(store.BucketToolDeadLetters, letter.ID, letter); err != nil {
+		return nil, fmt.Errorf("failed to save dead-lettered tool call: %w", err)
+	}
+	d.prune(now)
+	return letter, nil
+}
+
+// prune drops dead letters that failed before the retention period, and the
+// oldest ones over maxDeadLetters
+func (d *DeadLetters) prune(now time.Time) {
+	keys, err := d.store.Keys(store.BucketToolDeadLetters)
+	if err != nil {
+		common.GetLogger().Warn("Failed to list dead-lettered tool calls for pruning: %v", err)
+		return
+	}
+	cutoff := "dl-" + now.Add(-deadLetterRetention).UTC().Format("20060102T150405.000000000")
+	for i, key := range keys {
+		if key >= cutoff && len(keys)-i <= maxDeadLetters {
+			break
+		}
+		if err := d.store.Delete(store.BucketToolDeadLetters, key); err != nil {
+			common.GetLogger().Warn("Failed to remove dead-lettered tool call %s: %v", key, err)
+		}
+	}
+}
+
+// List returns the dead-lettered calls, oldest first, optionally only those
+// of one tool
+func (d *DeadLetters) List(tool string) ([]ToolDeadLetter, error) {
+	if err := d.ready(); err != nil {
+		return nil, err
+	}
+	letters := []ToolDeadLetter{}
+	keys, err := d.store.Keys(store.BucketToolDeadLetters)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		var letter ToolDeadLetter
+		if err := d.store.Get(store.BucketToolDeadLetters, key, &letter); err != nil {
+			return nil, err
+		}
+		if tool == "" || letter.Tool == tool {
+			letters = append(letters, letter)
+		}
+	}
+	return letters, nil
+}
+
+// Get returns one dead-lettered call
+func (d *DeadLetters) Get(id string) (*ToolDeadLetter, error) {
+	if err := d.ready(); err != nil {
+		return nil, err
+	}
+	var letter ToolDeadLetter
+	if err := d.store.Get(store.BucketToolDeadLetters, id, &letter); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("no dead-lettered tool call %s", id)
+		}
+		return nil, err
+	}
+	return &letter, nil
+}
+
+// Replay calls the tool of a dead-lettered call again with its arguments.
+// Calls whose credential arguments were redacted cannot be replayed, since
+// the credentials were never stored.
+func (d *DeadLetters) Replay(ctx context.Context, id string) (*DeadLetterReplay, error) {
+	letter, err := d.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(letter.Redacted) > 0 {
+		return nil, fmt.Errorf("dead-lettered tool call %s carried credentials (%s), which are not stored; make the call again instead",
+			id, strings.Join(letter.Redacted, ", "))
+	}
+	registered, ok := toolHandlers.Load(letter.Tool)
+	if !ok {
+		return nil, fmt.Errorf("tool %s of dead-lettered call %s is not registered", letter.Tool, id)
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = letter.Tool
+	req.Params.Arguments = letter.Arguments
+	result, callErr := registered.(registeredTool).handler(ctx, req)
+
+	replay := &DeadLetterReplay{ID: id}
+	switch {
+	case callErr != nil:
+		replay.Error = callErr.Error()
+	case result == nil:
+		replay.Error = "the tool returned no result"
+	case result.IsError:
+		replay.Error = resultText(result)
+	default:
+		replay.Succeeded = true
+		replay.Result = resultText(result)
+		if err := d.store.Delete(store.BucketToolDeadLetters, id); err != nil {
+			return nil, fmt.Errorf("replayed %s but failed to remove it: %w", id, err)
+		}
+		common.GetLogger().Info("Replayed dead-lettered %s call %s", letter.Tool, id)
+		return replay, nil
+	}
+
+	letter.Replays++
+	letter.LastReplayAt = d.now().UTC().Format(time.RFC3339)
+	letter.LastReplayError = replay.Error
+	if err := d.store.Put(store.BucketToolDeadLetters, id, letter); err != nil {
+		return nil, fmt.Errorf("failed to update dead-lettered tool call %s: %w", id, err)
+	}
+	replay.DeadLetter = letter
+	return replay, nil
+}
+
+// DeadLetterMiddleware keeps tool calls that fail while OpsRamp is
+// unreachable, rate limiting or answering with server errors, so they can be
+// replayed after the outage. Calls that fail for any other reason, such as
+// invalid arguments, are not kept: replaying them would fail again.
+func DeadLetterMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if deadLetterExcludedTools[req.Params.Name] {
+			return next(ctx, req)
+		}
+		ctx, failures := client.WithUpstreamFailures(ctx)
+		result, err := next(ctx, req)
+		if failures.Count() == 0 || (err == nil && (result == nil || !result.IsError)) {
+			return result, err
+		}
+
+		callErr := ""
+		if err != nil {
+			callErr = err.Error()
+		} else {
+			callErr = resultText(result)
+		}
+		letter, recordErr := NewDeadLetters().Record(ctx, req, callErr, failures)
+		if recordErr != nil {
+			common.GetLogger().Debug("Tool call failure not dead-lettered: %v", recordErr)
+			return result, err
+		}
+		common.GetLogger().Warn("Dead-lettered %s call as %s after OpsRamp failed: %s", req.Params.Name, letter.ID, letter.Upstream)
+		if err == nil {
+			result.Content = append(result.Content, mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("OpsRamp failed this call; it was kept as dead letter %s and can be replayed with the admin tool's replayDeadLetter action once OpsRamp recovers.", letter.ID),
+			})
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+)
+
+func TestDeadLetterCaptureAndReplay(t *testing.T) {
+	useTestStore(t)
+	var down atomic.Bool
+	down.Store(true)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token-1","token_type":"bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("/api/v2/probe", func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	})
+	mux.HandleFunc("/api/v2/invalid", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad query", http.StatusBadRequest)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	c := client.NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: server.URL, AuthURL: server.URL + "/auth/oauth/token", AuthKey: "key-1", AuthSecret: "secret-1", TenantID: "client_1",
+	}})
+
+	handler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := c.Request(ctx, http.MethodGet, req.GetString("path", ""), nil, nil); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText("done"), nil
+	}
+	RegisterToolHandler(mcp.Tool{Name: "probe"}, handler)
+	defer toolHandlers.Delete("probe")
+	call := func(args map[string]interface{}) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "probe"
+		req.Params.Arguments = args
+		result, _ := DeadLetterMiddleware(handler)(context.Background(), req)
+		return result
+	}
+
+	result := call(map[string]interface{}{"action": "get", "path": "/api/v2/probe"})
+	if !result.IsError || !strings.Contains(result.Content[len(result.Content)-1].(mcp.TextContent).Text, "kept as dead letter dl-") {
+		t.Fatalf("Expected the outage to be dead-lettered, got %+v", result.Content)
+	}
+	call(map[string]interface{}{"action": "get", "path": "/api/v2/invalid"})
+
+	letters, err := NewDeadLetters().List("")
+	if err != nil || len(letters) != 1 {
+		t.Fatalf("Expected only the call OpsRamp failed to be kept, got %+v, %v", letters, err)
+	}
+	letter := letters[0]
+	if letter.Tool != "probe" || letter.Action != "get" || letter.UpstreamFailures != 1 || !strings.Contains(letter.Upstream, "/api/v2/probe") {
+		t.Errorf("Unexpected dead letter: %+v", letter)
+	}
+
+	replay, err := NewDeadLetters().Replay(context.Background(), letter.ID)
+	if err != nil || replay.Succeeded || replay.DeadLetter == nil || replay.DeadLetter.Replays != 1 {
+		t.Fatalf("Expected a replay during the outage to fail and be kept, got %+v, %v", replay, err)
+	}
+
+	down.Store(false)
+	replay, err = NewDeadLetters().Replay(context.Background(), letter.ID)
+	if err != nil || !replay.Succeeded || replay.Result != "done" {
+		t.Fatalf("Expected the replay to succeed after the outage, got %+v, %v", replay, err)
+	}
+	if letters, _ := NewDeadLetters().List(""); len(letters) != 0 {
+		t.Errorf("Expected a replayed call to be removed, got %+v", letters)
+	}
+}
+
+func TestDeadLetterRedactsCredentials(t *testing.T) {
+	useTestStore(t)
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "integrations"
+	req.Params.Arguments = map[string]interface{}{
+		"action": "create",
+		"config": map[string]interface{}{"name": "vcenter", "credentials": map[string]interface{}{"password": "s3cret"}, "apiKey": "k"},
+	}
+	failures := &client.UpstreamFailures{}
+	letter, err := NewDeadLetters().Record(context.Background(), req, "request failed", failures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := letter.Arguments["config"].(map[string]interface{})
+	if config["credentials"] != redactedValue || config["apiKey"] != redactedValue || config["name"] != "vcenter" {
+		t.Errorf("Expected credentials to be redacted, got %+v", config)
+	}
+	if _, err := NewDeadLetters().Replay(context.Background(), letter.ID); err == nil || !strings.Contains(err.Error(), "config.apiKey, config.credentials") {
+		t.Errorf("Expected a call with redacted credentials not to be replayed, got %v", err)
+	}
+}
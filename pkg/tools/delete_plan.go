@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// deleteConfirmation signs and verifies the tokens that bulk deletes of more
+// than threshold resources must present
+type deleteConfirmation struct {
+	threshold int
+	ttl       time.Duration
+	key       []byte
+	now       func() time.Time
+}
+
+var (
+	deleteDefaults     *deleteConfirmation
+	deleteDefaultsOnce sync.Once
+)
+
+// getDeleteConfirmation returns the configured delete confirmation, loading it on first use
+func getDeleteConfirmation() *deleteConfirmation {
+	deleteDefaultsOnce.Do(func() {
+		deletes := common.DeletesConfig{}
+		if config, err := common.LoadConfig(""); err == nil {
+			deletes = config.Deletes
+		}
+		deleteDefaults = newDeleteConfirmation(deletes)
+	})
+	return deleteDefaults
+}
+
+// newDeleteConfirmation creates a delete confirmation from configuration,
+// generating a random signing key when none is configured
+func newDeleteConfirmation(config common.DeletesConfig) *deleteConfirmation {
+	d := &deleteConfirmation{
+		threshold: config.ConfirmationThreshold,
+		ttl:       time.Duration(config.TokenTTLSeconds) * time.Second,
+		key:       []byte(config.SigningKey),
+		now:       time.Now,
+	}
+	if d.threshold <= 0 {
+		d.threshold = common.DefaultDeleteConfirmationThreshold
+	}
+	if d.ttl <= 0 {
+		d.ttl = common.DefaultDeleteTokenTTL * time.Second
+	}
+	if len(d.key) == 0 {
+		d.key = make([]byte, 32)
+		rand.Read(d.key)
+	}
+	return d
+}
+
+// deleteClaims is the signed content of a confirmation token
+type deleteClaims struct {
+	Tenant  string `json:"t"`
+	IDs     string `json:"h"`
+	Count   int    `json:"n"`
+	Expires int64  `json:"e"`
+}
+
+// deleteIDsDigest hashes a set of resource IDs independently of their order
+// and of duplicates
+func deleteIDsDigest(ids []string) (string, int) {
+	unique := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+	sorted := make([]string, 0, len(unique))
+	for id := range unique {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(sum[:]), len(sorted)
+}
+
+// Sign returns a token confirming the deletion of exactly ids on tenant
+func (d *deleteConfirmation) Sign(tenant string, ids []string) (string, time.Time) {
+	expires := d.now().Add(d.ttl).Truncate(time.Second)
+	digest, count := deleteIDsDigest(ids)
+	payload, _ := json.Marshal(deleteClaims{Tenant: tenant, IDs: digest, Count: count, Expires: expires.Unix()})
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + d.signature(encoded), expires
+}
+
+// Verify checks that token confirms the deletion of exactly ids on tenant
+// and has not expired
+func (d *deleteConfirmation) Verify(token, tenant string, ids []string) error {
+	if token == "" {
+		return fmt.Errorf("deleting %d resources requires a confirmation token: call planDelete with the same ids or selector, review the targets, then pass its confirmationToken", len(ids))
+	}
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(d.signature(encoded))) {
+		return fmt.Errorf("invalid confirmation token: call planDelete again")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("invalid confirmation token: call planDelete again")
+	}
+	var claims deleteClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return fmt.Errorf("invalid confirmation token: call planDelete again")
+	}
+
+	if d.now().Unix() > claims.Expires {
+		return fmt.Errorf("confirmation token expired at %s: call planDelete again", time.Unix(claims.Expires, 0).UTC().Format(time.RFC3339))
+	}
+	digest, count := deleteIDsDigest(ids)
+	if claims.Tenant != tenant || claims.IDs != digest {
+		return fmt.Errorf("confirmation token was issued for a different set of %d resources, not these %d: call planDelete for the resources you intend to delete", claims.Count, count)
+	}
+	return nil
+}
+
+// signature returns the HMAC of an encoded payload
+func (d *deleteConfirmation) signature(encoded string) string {
+	mac := hmac.New(sha256.New, d.key)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Requires reports whether deleting count resources needs a confirmation token
+func (d *deleteConfirmation) Requires(count int) bool {
+	return count > d.threshold
+}
+
+// planDelete resolves the resources a bulk delete would remove and returns
+// them with a confirmation token. Every ID must exist, so a mistyped or
+// invented ID list fails here instead of deleting something else.
+func planDelete(ctx context.Context, api ResourcesAPI, confirmation *deleteConfirmation, tenant string, ids []string, selector *types.ResourceSelector) (*types.DeletePlan, error) {
+	var targets []types.BulkTarget
+	if selector != nil {
+		resources, err := NewBulkSelector(api).Resolve(ctx, *selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range resources {
+			targets = append(targets, bulkTarget(resource))
+		}
+	} else {
+		if _, count := deleteIDsDigest(ids); count > maxBulkTargets {
+			return nil, fmt.Errorf("cannot plan deleting %d resources; at most %d are allowed", count, maxBulkTargets)
+		}
+		var missing []string
+		seen := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			resource, err := api.GetMinimal(ctx, id)
+			if err != nil {
+				missing = append(missing, id)
+				continue
+			}
+			targets = append(targets, types.BulkTarget{
+				ID:        resource.ID,
+				Name:      displayName(resource.Name, resource.ResourceName),
+				HostName:  resource.HostName,
+				IPAddress: resource.IPAddress,
+				Type:      resource.Type,
+			})
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("%d of %d resources could not be found, so no delete was planned: %s", len(missing), len(seen), strings.Join(missing, ", "))
+		}
+	}
+
+	plan := &types.DeletePlan{Count: len(targets), Targets: make([]types.BulkTarget, 0, len(targets))}
+	if plan.Count == 0 {
+		plan.Message = "No resources match; nothing to delete"
+		return plan, nil
+	}
+	planned := make([]string, 0, len(targets))
+	for _, target := range targets {
+		planned = append(planned, target.ID)
+		plan.Targets = append(plan.Targets, target)
+	}
+	plan.ConfirmationToken, plan.ExpiresAt = confirmation.Sign(tenant, planned)
+	plan.Message = fmt.Sprintf("Review the %d targets, then run bulkDelete with the same ids or selector and this confirmationToken before %s. The token is valid only for exactly these resources.",
+		plan.Count, plan.ExpiresAt.UTC().Format(time.RFC3339))
+	return plan, nil
+}
@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// planResourcesAPI adds lookups by ID to the selector inventory
+type planResourcesAPI struct {
+	*selectorResourcesAPI
+}
+
+func (m *planResourcesAPI) GetMinimal(ctx context.Context, id string) (*types.ResourceMinimal, error) {
+	for _, resource := range m.resources {
+		if resource.ID == id {
+			return &types.ResourceMinimal{ID: resource.ID, HostName: resource.HostName, Type: resource.Type}, nil
+		}
+	}
+	return nil, fmt.Errorf("resource %s not found", id)
+}
+
+func TestDeleteConfirmationTokens(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	confirmation := newDeleteConfirmation(common.DeletesConfig{ConfirmationThreshold: 2, TokenTTLSeconds: 60, SigningKey: "0123456789abcdef"})
+	confirmation.now = func() time.Time { return now }
+
+	if confirmation.Requires(2) || !confirmation.Requires(3) {
+		t.Error("Expected tokens to be required above the threshold only")
+	}
+
+	token, expires := confirmation.Sign("default", []string{"r1", "r2", "r3"})
+	if !expires.Equal(now.Add(time.Minute)) {
+		t.Errorf("Expected the token to expire in a minute, got %s", expires)
+	}
+	if err := confirmation.Verify(token, "default", []string{"r3", "r1", "r2", "r1"}); err != nil {
+		t.Errorf("Expected the token to confirm the same IDs in any order, got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		tenant  string
+		ids     []string
+		wantErr string
+	}{
+		{"missing", "", "default", []string{"r1", "r2", "r3"}, "call planDelete"},
+		{"other ids", token, "default", []string{"r1", "r2", "r4"}, "different set of 3 resources"},
+		{"extra id", token, "default", []string{"r1", "r2", "r3", "r4"}, "different set"},
+		{"other tenant", token, "emea", []string{"r1", "r2", "r3"}, "different set"},
+		{"tampered", strings.Replace(token, ".", "x.", 1), "default", []string{"r1", "r2", "r3"}, "invalid confirmation token"},
+		{"other key", func() string {
+			token, _ := newDeleteConfirmation(common.DeletesConfig{}).Sign("default", []string{"r1", "r2", "r3"})
+			return token
+		}(), "default", []string{"r1", "r2", "r3"}, "invalid confirmation token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := confirmation.Verify(tt.token, tt.tenant, tt.ids)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := confirmation.Verify(token, "default", []string{"r1", "r2", "r3"}); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Expected an expired token to be refused, got %v", err)
+	}
+}
+
+func TestResourcesToolPlanDeleteThenBulkDelete(t *testing.T) {
+	threshold := getDeleteConfirmation().threshold
+	inventory := &selectorResourcesAPI{}
+	ids := make([]string, 0, threshold+1)
+	for i := 0; i <= threshold; i++ {
+		id := fmt.Sprintf("r%d", i)
+		ids = append(ids, id)
+		inventory.resources = append(inventory.resources, types.Resource{ID: id, HostName: "old-" + id, Type: "Server"})
+	}
+	api := &planResourcesAPI{inventory}
+
+	// Deleting more than the threshold without a token is refused
+	result, _ := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "bulkDelete",
+		"ids":    ids,
+	}), api)
+	if !result.IsError || !strings.Contains(resultText(result), "planDelete") || inventory.deleted != nil {
+		t.Fatalf("Expected the delete to require a token, got %s", resultText(result))
+	}
+
+	// Unknown IDs fail the plan
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "planDelete",
+		"ids":    append([]string{"invented-1"}, ids...),
+	}), api)
+	if !result.IsError || !strings.Contains(resultText(result), "invented-1") {
+		t.Fatalf("Expected the unknown ID to fail the plan, got %s", resultText(result))
+	}
+
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "planDelete",
+		"ids":    ids,
+	}), api)
+	var plan types.DeletePlan
+	if result.IsError || json.Unmarshal([]byte(resultText(result)), &plan) != nil {
+		t.Fatalf("Expected a delete plan, got %s", resultText(result))
+	}
+	if plan.Count != len(ids) || plan.Targets[0].HostName != "old-r0" || plan.ConfirmationToken == "" {
+		t.Fatalf("Unexpected plan %+v", plan)
+	}
+
+	// The token does not cover a different ID list
+	swapped := append([]string{"r-other"}, ids[1:]...)
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action":            "bulkDelete",
+		"ids":               swapped,
+		"confirmationToken": plan.ConfirmationToken,
+	}), api)
+	if !result.IsError || inventory.deleted != nil {
+		t.Fatalf("Expected the token to be refused for other IDs, got %s", resultText(result))
+	}
+
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action":            "bulkDelete",
+		"ids":               ids,
+		"confirmationToken": plan.ConfirmationToken,
+	}), api)
+	if result.IsError || inventory.deleted == nil || len(inventory.deleted.ResourceIDs) != len(ids) {
+		t.Fatalf("Expected the planned delete to run, got %s", resultText(result))
+	}
+}
+
+func TestBulkSelectorDeleteRequiresToken(t *testing.T) {
+	api := newSelectorInventory()
+	confirmation := newDeleteConfirmation(common.DeletesConfig{ConfirmationThreshold: 2})
+	selector := types.ResourceSelector{HostName: "web-*"}
+
+	preview, err := NewBulkSelector(api).WithDeleteConfirmation(confirmation, "default", "").Run(context.Background(), "bulkDelete", selector, nil, false, -1)
+	if err != nil || !strings.Contains(preview.Message, "planDelete") {
+		t.Fatalf("Expected the preview to point at planDelete, got %+v (%v)", preview, err)
+	}
+	if _, err := NewBulkSelector(api).WithDeleteConfirmation(confirmation, "default", "").Run(context.Background(), "bulkDelete", selector, nil, true, 3); err == nil || api.deleted != nil {
+		t.Fatal("Expected a confirmed delete of 3 resources without a token to be refused")
+	}
+
+	plan, err := planDelete(context.Background(), api, confirmation, "default", nil, &selector)
+	if err != nil || plan.Count != 3 {
+		t.Fatalf("Expected a 3-target plan, got %+v (%v)", plan, err)
+	}
+	if _, err := NewBulkSelector(api).WithDeleteConfirmation(confirmation, "default", plan.ConfirmationToken).Run(context.Background(), "bulkDelete", selector, nil, true, 3); err != nil || api.deleted == nil {
+		t.Errorf("Expected the planned delete to run, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// diffKeyCandidates are the fields tried, in order, to match entries between results
+var diffKeyCandidates = []string{"id", "uniqueId", "resourceId", "alertId", "name"}
+
+// diffResults compares two results. Lists (or objects wrapping a results list)
+// are matched entry by entry using keyField, detected automatically when empty;
+// any other values are compared as a single entry.
+func diffResults(before, after interface{}, keyField string) (*types.ResultDiff, error) {
+	before, err := normalizeJSON(before)
+	if err != nil {
+		return nil, fmt.Errorf("invalid before value: %w", err)
+	}
+	after, err = normalizeJSON(after)
+	if err != nil {
+		return nil, fmt.Errorf("invalid after value: %w", err)
+	}
+
+	diff := &types.ResultDiff{Added: []interface{}{}, Removed: []interface{}{}, Changed: []types.ChangedEntry{}}
+
+	beforeEntries, beforeIsList := diffEntries(before)
+	afterEntries, afterIsList := diffEntries(after)
+	if !beforeIsList || !afterIsList {
+		if changes := compareValues("", before, after, nil); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, types.ChangedEntry{Key: "$", Changes: changes})
+		} else {
+			diff.Unchanged = 1
+		}
+		diff.Summary = fmt.Sprintf("%d changed", len(diff.Changed))
+		return diff, nil
+	}
+
+	if keyField == "" {
+		keyField = detectKeyField(beforeEntries, afterEntries)
+	}
+	diff.KeyField = keyField
+
+	beforeByKey, beforeOrder := indexEntries(beforeEntries, keyField)
+	afterByKey, afterOrder := indexEntries(afterEntries, keyField)
+
+	for _, key := range beforeOrder {
+		afterEntry, ok := afterByKey[key]
+		if !ok {
+			diff.Removed = append(diff.Removed, beforeByKey[key])
+			continue
+		}
+		if changes := compareValues("", beforeByKey[key], afterEntry, nil); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, types.ChangedEntry{Key: key, Changes: changes})
+		} else {
+			diff.Unchanged++
+		}
+	}
+	for _, key := range afterOrder {
+		if _, ok := beforeByKey[key]; !ok {
+			diff.Added = append(diff.Added, afterByKey[key])
+		}
+	}
+
+	diff.Summary = fmt.Sprintf("%d added, %d removed, %d changed, %d unchanged (keyed by %s)",
+		len(diff.Added), len(diff.Removed), len(diff.Changed), diff.Unchanged, keyField)
+	return diff, nil
+}
+
+// normalizeJSON converts any value to its generic JSON form
+func normalizeJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// diffEntries returns the list of entries in a result, unwrapping a results field
+func diffEntries(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case map[string]interface{}:
+		if results, ok := v["results"].([]interface{}); ok {
+			return results, true
+		}
+	}
+	return nil, false
+}
+
+// detectKeyField returns the first candidate key present in every entry, or ""
+// to match entries by position
+func detectKeyField(entryLists ...[]interface{}) string {
+	for _, candidate := range diffKeyCandidates {
+		present := true
+		for _, entries := range entryLists {
+			for _, entry := range entries {
+				fields, ok := entry.(map[string]interface{})
+				if !ok || fields[candidate] == nil {
+					present = false
+					break
+				}
+			}
+		}
+		if present {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// indexEntries keys entries by keyField, falling back to their position
+func indexEntries(entries []interface{}, keyField string) (map[string]interface{}, []string) {
+	byKey := make(map[string]interface{}, len(entries))
+	order := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		key := fmt.Sprintf("#%d", i)
+		if fields, ok := entry.(map[string]interface{}); ok && keyField != "" && fields[keyField] != nil {
+			key = fmt.Sprintf("%v", fields[keyField])
+		}
+		if _, duplicate := byKey[key]; !duplicate {
+			order = append(order, key)
+		}
+		byKey[key] = entry
+	}
+	return byKey, order
+}
+
+// compareValues appends the differences between two generic JSON values.
+// Objects are compared field by field; arrays and scalars as a whole.
+func compareValues(path string, before, after interface{}, changes []types.FieldChange) []types.FieldChange {
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if !beforeIsMap || !afterIsMap {
+		if !reflect.DeepEqual(before, after) {
+			if path == "" {
+				path = "."
+			}
+			changes = append(changes, types.FieldChange{Path: path, Before: before, After: after})
+		}
+		return changes
+	}
+
+	fields := make([]string, 0, len(beforeMap)+len(afterMap))
+	for field := range beforeMap {
+		fields = append(fields, field)
+	}
+	for field := range afterMap {
+		if _, ok := beforeMap[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		fieldPath := field
+		if path != "" {
+			fieldPath = path + "." + field
+		}
+		changes = compareValues(fieldPath, beforeMap[field], afterMap[field], changes)
+	}
+	return changes
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestDiffResultsKeyedByID(t *testing.T) {
+	before := map[string]interface{}{"results": []interface{}{
+		map[string]interface{}{"id": "r1", "state": "active", "tags": []interface{}{"a"}},
+		map[string]interface{}{"id": "r2", "state": "active"},
+	}}
+	after := map[string]interface{}{"results": []interface{}{
+		map[string]interface{}{"id": "r1", "state": "inactive", "tags": []interface{}{"a"}},
+		map[string]interface{}{"id": "r3", "state": "active"},
+	}}
+
+	diff, err := diffResults(before, after, "")
+	if err != nil {
+		t.Fatalf("diffResults failed: %v", err)
+	}
+	if diff.KeyField != "id" || len(diff.Added) != 1 || len(diff.Removed) != 1 || len(diff.Changed) != 1 || diff.Unchanged != 0 {
+		t.Fatalf("Unexpected diff: %+v", diff)
+	}
+	change := diff.Changed[0]
+	if change.Key != "r1" || len(change.Changes) != 1 || change.Changes[0].Path != "state" {
+		t.Errorf("Expected only r1.state to change, got %+v", change)
+	}
+}
+
+func TestDiffWithResultHandles(t *testing.T) {
+	ctx := contextWithSession("diff-session")
+
+	handleOf := func(result interface{}) string {
+		res, err := respondJSON(ctx, mcp.CallToolRequest{}, result)
+		if err != nil || len(res.Content) != 2 {
+			t.Fatalf("Expected result plus handle, got %+v (%v)", res, err)
+		}
+		text := res.Content[1].(mcp.TextContent).Text
+		return strings.Fields(strings.TrimPrefix(text, "resultHandle: "))[0]
+	}
+
+	before := handleOf([]map[string]string{{"uniqueId": "c1", "name": "acme"}})
+	after := handleOf([]map[string]string{{"uniqueId": "c1", "name": "acme"}, {"uniqueId": "c2", "name": "globex"}})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"action": "diff", "before": before, "after": after}
+	res, err := UtilityToolHandler(ctx, req)
+	if err != nil || res.IsError {
+		t.Fatalf("diff failed: %v %+v", err, res)
+	}
+
+	var diff types.ResultDiff
+	if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &diff); err != nil {
+		t.Fatalf("Invalid diff output: %v", err)
+	}
+	if diff.KeyField != "uniqueId" || len(diff.Added) != 1 || diff.Unchanged != 1 {
+		t.Errorf("Unexpected diff: %+v", diff)
+	}
+
+	// Handles are private to the session that created them
+	other := contextWithSession("other-session")
+	if res, _ := UtilityToolHandler(other, req); !res.IsError {
+		t.Error("Expected handles from another session to be rejected")
+	}
+}
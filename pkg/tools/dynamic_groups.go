@@ -0,0 +1,266 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// DeviceGroupProvider is implemented by resources APIs that can create
+// device groups and manage their members
+type DeviceGroupProvider interface {
+	CreateDeviceGroup(ctx context.Context, name, description string) (*types.DeviceGroup, error)
+	UpdateDeviceGroup(ctx context.Context, groupID, name, description string) error
+	GetDeviceGroupMembers(ctx context.Context, groupID string) ([]string, error)
+	RemoveFromDeviceGroup(ctx context.Context, groupID string, resourceIDs []string) error
+}
+
+// DynamicGroups manages query-based device groups: device groups whose
+// members are the resources matching a selector. The selector of each group
+// is kept in the state store, and refreshing a group adds the resources that
+// now match and removes those that no longer do.
+type DynamicGroups struct {
+	api    ResourcesAPI
+	groups DeviceGroupProvider
+	store  *store.Store
+	tenant string
+	now    func() time.Time
+	logger *common.CustomLogger
+}
+
+// NewDynamicGroups creates a dynamic group manager for the tenant over the
+// default state store, which may be unavailable
+func NewDynamicGroups(api ResourcesAPI, tenant string) *DynamicGroups {
+	d := &DynamicGroups{
+		api:    api,
+		store:  store.Default(),
+		tenant: tenant,
+		now:    time.Now,
+		logger: common.GetLogger(),
+	}
+	if provider, ok := api.(DeviceGroupProvider); ok {
+		d.groups = provider
+	}
+	return d
+}
+
+// ready reports why dynamic groups cannot be managed, if they cannot
+func (d *DynamicGroups) ready() error {
+	if d.groups == nil {
+		return fmt.Errorf("device groups are not supported by this resources backend")
+	}
+	if d.store == nil {
+		return fmt.Errorf("dynamic groups require the state store")
+	}
+	return nil
+}
+
+// key returns the store key of a tenant's group
+func (d *DynamicGroups) key(id string) string {
+	return d.tenant + "/" + id
+}
+
+// List returns the tenant's dynamic groups by name
+func (d *DynamicGroups) List() ([]types.DynamicGroup, error) {
+	if d.store == nil {
+		return nil, fmt.Errorf("dynamic groups require the state store")
+	}
+	groups := make([]types.DynamicGroup, 0)
+	err := d.store.ForEach(store.BucketDynamicGroups, func(key string, raw []byte) error {
+		if !strings.HasPrefix(key, d.tenant+"/") {
+			return nil
+		}
+		var group types.DynamicGroup
+		if err := json.Unmarshal(raw, &group); err != nil {
+			return fmt.Errorf("failed to decode dynamic group %s: %w", key, err)
+		}
+		groups = append(groups, group)
+		return nil
+	})
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, err
+}
+
+// Get returns a dynamic group by device group ID
+func (d *DynamicGroups) Get(id string) (*types.DynamicGroup, error) {
+	if err := d.ready(); err != nil {
+		return nil, err
+	}
+	var group types.DynamicGroup
+	if err := d.store.Get(store.BucketDynamicGroups, d.key(id), &group); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("device group %s is not a dynamic group (use listDynamicGroups to see them)", id)
+		}
+		return nil, err
+	}
+	return &group, nil
+}
+
+// Preview returns the resources a new group with the selector would contain
+func (d *DynamicGroups) Preview(ctx context.Context, selector types.ResourceSelector) (*types.DynamicGroupChange, error) {
+	resources, err := NewBulkSelector(d.api).Resolve(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	change := &types.DynamicGroupChange{Selector: selector, Matched: len(resources), Added: make([]types.BulkTarget, 0, len(resources)), Removed: []string{}}
+	for _, resource := range resources {
+		change.Added = append(change.Added, bulkTarget(resource))
+	}
+	change.Message = fmt.Sprintf("Preview only: a group with this selector would contain %d resources", change.Matched)
+	return change, nil
+}
+
+// Create creates a device group with the resources matching the selector
+// and records its selector for later refreshes
+func (d *DynamicGroups) Create(ctx context.Context, name, description string, selector types.ResourceSelector) (*types.DynamicGroupChange, error) {
+	if err := d.ready(); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("a name is required to create a dynamic group")
+	}
+	// Resolve first so an invalid or oversized selector creates nothing
+	if _, err := NewBulkSelector(d.api).Resolve(ctx, selector); err != nil {
+		return nil, err
+	}
+
+	created, err := d.groups.CreateDeviceGroup(ctx, name, description)
+	if err != nil {
+		return nil, err
+	}
+	now := d.now().UTC().Format(time.RFC3339)
+	group := &types.DynamicGroup{
+		ID:          created.ID,
+		Name:        name,
+		Description: description,
+		Tenant:      d.tenant,
+		Selector:    selector,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := d.store.Put(store.BucketDynamicGroups, d.key(group.ID), group); err != nil {
+		return nil, fmt.Errorf("device group %s was created but its selector could not be saved: %w", group.ID, err)
+	}
+	d.logger.Info("Created dynamic group %s (%s)", name, group.ID)
+
+	change, err := d.refresh(ctx, group, true)
+	if err != nil {
+		return nil, fmt.Errorf("dynamic group %s was created but adding its members failed; run refreshDynamicGroup to retry: %w", group.ID, err)
+	}
+	return change, nil
+}
+
+// Update changes a dynamic group's name, description or selector, then
+// refreshes its membership. Empty values keep the current ones.
+func (d *DynamicGroups) Update(ctx context.Context, id, name, description string, selector *types.ResourceSelector) (*types.DynamicGroupChange, error) {
+	group, err := d.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if selector != nil {
+		if _, err := compileSelector(*selector); err != nil {
+			return nil, err
+		}
+		group.Selector = *selector
+	}
+	if (name != "" && name != group.Name) || (description != "" && description != group.Description) {
+		group.Name = defaultString(name, group.Name)
+		group.Description = defaultString(description, group.Description)
+		if err := d.groups.UpdateDeviceGroup(ctx, id, group.Name, group.Description); err != nil {
+			return nil, err
+		}
+	}
+	group.UpdatedAt = d.now().UTC().Format(time.RFC3339)
+
+	// The updated definition is saved once the membership matches it
+	change, err := d.refresh(ctx, group, true)
+	if err != nil {
+		return nil, fmt.Errorf("dynamic group %s was not updated: %w", id, err)
+	}
+	return change, nil
+}
+
+// Refresh recomputes a dynamic group's members from its selector. Without
+// apply it only reports the change.
+func (d *DynamicGroups) Refresh(ctx context.Context, id string, apply bool) (*types.DynamicGroupChange, error) {
+	group, err := d.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return d.refresh(ctx, group, apply)
+}
+
+// refresh compares the resources matching the group's selector with its
+// current members and, when apply is set, adds and removes the difference
+func (d *DynamicGroups) refresh(ctx context.Context, group *types.DynamicGroup, apply bool) (*types.DynamicGroupChange, error) {
+	resources, err := NewBulkSelector(d.api).Resolve(ctx, group.Selector)
+	if err != nil {
+		return nil, err
+	}
+	members, err := d.groups.GetDeviceGroupMembers(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]bool, len(members))
+	for _, id := range members {
+		current[id] = true
+	}
+	change := &types.DynamicGroupChange{
+		Group:    group,
+		Selector: group.Selector,
+		Matched:  len(resources),
+		Added:    []types.BulkTarget{},
+		Removed:  []string{},
+	}
+	var added []string
+	for _, resource := range resources {
+		if current[resource.ID] {
+			delete(current, resource.ID)
+			continue
+		}
+		added = append(added, resource.ID)
+		change.Added = append(change.Added, bulkTarget(resource))
+	}
+	for id := range current {
+		change.Removed = append(change.Removed, id)
+	}
+	sort.Strings(change.Removed)
+
+	if !apply {
+		change.Message = fmt.Sprintf("Preview only: refreshing would add %d and remove %d resources, leaving %d members",
+			len(change.Added), len(change.Removed), change.Matched)
+		return change, nil
+	}
+
+	if len(added) > 0 {
+		if err := d.api.AssignToDeviceGroup(ctx, group.ID, added); err != nil {
+			return nil, err
+		}
+	}
+	if len(change.Removed) > 0 {
+		if err := d.groups.RemoveFromDeviceGroup(ctx, group.ID, change.Removed); err != nil {
+			return nil, fmt.Errorf("added %d resources but removing %d failed: %w", len(added), len(change.Removed), err)
+		}
+	}
+	group.RefreshedAt = d.now().UTC().Format(time.RFC3339)
+	group.MemberCount = change.Matched
+	if err := d.store.Put(store.BucketDynamicGroups, d.key(group.ID), group); err != nil {
+		d.logger.Warn("Failed to record refresh of dynamic group %s: %v", group.ID, err)
+	}
+
+	change.Applied = true
+	change.Message = fmt.Sprintf("Added %d and removed %d resources; the group has %d members",
+		len(change.Added), len(change.Removed), change.Matched)
+	d.logger.Info("Refreshed dynamic group %s (%s): +%d -%d", group.Name, group.ID, len(change.Added), len(change.Removed))
+	return change, nil
+}
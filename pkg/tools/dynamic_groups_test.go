@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// groupResourcesAPI adds device groups to the selector inventory, keeping
+// each group's members in memory
+type groupResourcesAPI struct {
+	*selectorResourcesAPI
+	members map[string]map[string]bool
+	created int
+}
+
+func newGroupInventory() *groupResourcesAPI {
+	return &groupResourcesAPI{selectorResourcesAPI: newSelectorInventory(), members: map[string]map[string]bool{}}
+}
+
+func (m *groupResourcesAPI) CreateDeviceGroup(ctx context.Context, name, description string) (*types.DeviceGroup, error) {
+	m.created++
+	id := "dg" + string(rune('0'+m.created))
+	m.members[id] = map[string]bool{}
+	return &types.DeviceGroup{ID: id, Name: name, Description: description}, nil
+}
+
+func (m *groupResourcesAPI) UpdateDeviceGroup(ctx context.Context, groupID, name, description string) error {
+	return nil
+}
+
+func (m *groupResourcesAPI) AssignToDeviceGroup(ctx context.Context, groupID string, resourceIDs []string) error {
+	for _, id := range resourceIDs {
+		m.members[groupID][id] = true
+	}
+	return nil
+}
+
+func (m *groupResourcesAPI) GetDeviceGroupMembers(ctx context.Context, groupID string) ([]string, error) {
+	ids := make([]string, 0)
+	for id := range m.members[groupID] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (m *groupResourcesAPI) RemoveFromDeviceGroup(ctx context.Context, groupID string, resourceIDs []string) error {
+	for _, id := range resourceIDs {
+		delete(m.members[groupID], id)
+	}
+	return nil
+}
+
+func TestDynamicGroupLifecycle(t *testing.T) {
+	useTestStore(t)
+	api := newGroupInventory()
+	groups := NewDynamicGroups(api, "client_1")
+	ctx := context.Background()
+
+	preview, err := groups.Preview(ctx, types.ResourceSelector{HostName: "web-*"})
+	if err != nil || preview.Matched != 3 || api.created != 0 {
+		t.Fatalf("Expected a preview of 3 resources creating nothing, got %+v (%v)", preview, err)
+	}
+
+	change, err := groups.Create(ctx, "Web servers", "", types.ResourceSelector{HostName: "web-*"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !change.Applied || len(change.Added) != 3 || change.Group.ID != "dg1" || change.Group.MemberCount != 3 {
+		t.Fatalf("Unexpected create result %+v", change)
+	}
+
+	// Narrowing the selector drops the production server
+	change, err = groups.Update(ctx, "dg1", "", "", &types.ResourceSelector{HostName: "web-*", Tag: "env=dev"})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if strings.Join(change.Removed, ",") != "r2" || len(change.Added) != 0 || api.members["dg1"]["r2"] {
+		t.Errorf("Expected r2 to be removed, got %+v", change)
+	}
+
+	// A refresh preview reports drift without changing the group
+	api.members["dg1"]["r3"] = true
+	change, err = groups.Refresh(ctx, "dg1", false)
+	if err != nil || change.Applied || strings.Join(change.Removed, ",") != "r3" || !api.members["dg1"]["r3"] {
+		t.Errorf("Expected a preview removing r3, got %+v (%v)", change, err)
+	}
+
+	list, err := groups.List()
+	if err != nil || len(list) != 1 || list[0].Selector.Tag != "env=dev" {
+		t.Errorf("Expected the updated group to be listed, got %+v (%v)", list, err)
+	}
+	if list, _ := NewDynamicGroups(api, "client_2").List(); len(list) != 0 {
+		t.Errorf("Expected other tenants not to see the group, got %+v", list)
+	}
+	if _, err := groups.Refresh(ctx, "dg9", true); err == nil {
+		t.Error("Expected refreshing an unknown group to fail")
+	}
+}
+
+func TestDynamicGroupCreateRejectsInvalidSelector(t *testing.T) {
+	useTestStore(t)
+	api := newGroupInventory()
+	if _, err := NewDynamicGroups(api, "client_1").Create(context.Background(), "Bad", "", types.ResourceSelector{}); err == nil || api.created != 0 {
+		t.Errorf("Expected an empty selector to be refused before creating the group (%v)", err)
+	}
+}
+
+func TestResourcesToolDynamicGroupArguments(t *testing.T) {
+	for _, args := range []map[string]interface{}{
+		{"action": "previewDynamicGroup"},
+		{"action": "createDynamicGroup", "selector": map[string]interface{}{"hostName": "web-*"}},
+		{"action": "refreshDynamicGroup"},
+	} {
+		result, _ := ResourcesToolHandler(context.Background(), createTestRequest(args), newGroupInventory())
+		if !result.IsError {
+			t.Errorf("Expected %v to be refused", args)
+		}
+	}
+}
@@ -29,6 +29,7 @@ func NewEventsMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
 					"type":        "object",
 					"description": "Event configuration (for create and update)",
 				},
+				"timeRange": timeRangeArgument,
 			},
 			Required: []string{"action"},
 		},
@@ -47,7 +48,11 @@ func eventsToolHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 
 	switch action {
 	case "list":
-		result, err = tool.List(ctx)
+		timeRange, rangeErr := timeRangeFromRequest(req, "last24h")
+		if rangeErr != nil {
+			return timeRangeErrorResult(rangeErr), nil
+		}
+		result, err = tool.List(ctx, timeRange)
 	case "get":
 		result, err = tool.Get(ctx, id)
 	case "create":
@@ -87,7 +92,7 @@ func eventsToolHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 }
 
 // Implementation stubs for actual OpsRamp logic
-func (et *EventsTool) List(ctx context.Context) ([]interface{}, error) {
+func (et *EventsTool) List(ctx context.Context, timeRange TimeRange) ([]interface{}, error) {
 	// TODO: Implement list events
 	return []interface{}{}, nil
 }
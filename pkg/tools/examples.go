@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// exampleFixtures holds curated sample payloads, one file per tool, keyed by action
+//
+//go:embed examples/*.json
+var exampleFixtures embed.FS
+
+// exampleArgument is the tool input schema entry for requesting a sample payload
+var exampleArgument = map[string]interface{}{
+	"type":        "boolean",
+	"description": "Return a sample request and response for the given action instead of calling OpsRamp",
+}
+
+// ActionExample is a curated sample request and response for a tool action
+type ActionExample struct {
+	Tool        string                 `json:"tool"`
+	Action      string                 `json:"action"`
+	Description string                 `json:"description"`
+	Request     map[string]interface{} `json:"request"`
+	Response    json.RawMessage        `json:"response"`
+}
+
+var (
+	examplesOnce sync.Once
+	examples     map[string]map[string]ActionExample
+	examplesErr  error
+)
+
+// loadExamples parses the embedded fixtures once
+func loadExamples() (map[string]map[string]ActionExample, error) {
+	examplesOnce.Do(func() {
+		examples = make(map[string]map[string]ActionExample)
+		files, err := exampleFixtures.ReadDir("examples")
+		if err != nil {
+			examplesErr = err
+			return
+		}
+		for _, file := range files {
+			data, err := exampleFixtures.ReadFile(path.Join("examples", file.Name()))
+			if err != nil {
+				examplesErr = err
+				return
+			}
+			var actions map[string]ActionExample
+			if err := json.Unmarshal(data, &actions); err != nil {
+				examplesErr = fmt.Errorf("failed to parse example fixture %s: %w", file.Name(), err)
+				return
+			}
+			tool := strings.TrimSuffix(file.Name(), ".json")
+			for action, example := range actions {
+				example.Tool = tool
+				example.Action = action
+				actions[action] = example
+			}
+			examples[tool] = actions
+		}
+	})
+	return examples, examplesErr
+}
+
+// GetExample returns the curated example for a tool action
+func GetExample(tool, action string) (*ActionExample, error) {
+	all, err := loadExamples()
+	if err != nil {
+		return nil, err
+	}
+	actions, ok := all[tool]
+	if !ok {
+		return nil, fmt.Errorf("no examples available for tool %s", tool)
+	}
+	example, ok := actions[action]
+	if !ok {
+		return nil, fmt.Errorf("no example available for %s action %q; examples exist for: %s",
+			tool, action, strings.Join(exampleActions(actions), ", "))
+	}
+	return &example, nil
+}
+
+// exampleActions lists the actions that have examples, sorted
+func exampleActions(actions map[string]ActionExample) []string {
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExamplesMiddleware answers calls with example set to true from the curated
+// fixtures, without invoking the tool handler
+func ExamplesMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if !req.GetBool("example", false) {
+			return next(ctx, req)
+		}
+
+		action := req.GetString("action", "")
+		common.GetLogger().Debug("Serving example for %s action %s", req.Params.Name, action)
+		example, err := GetExample(req.Params.Name, action)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+			}, nil
+		}
+
+		text, err := renderResult(example, false)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{mcp.TextContent{Type: "text", Text: text}},
+			StructuredContent: structuredContent(text, false),
+		}, nil
+	}
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestExamplesMiddleware(t *testing.T) {
+	called := false
+	handler := ExamplesMiddleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("live"), nil
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "resources"
+	req.Params.Arguments = map[string]interface{}{"action": "scorecard", "example": true}
+	result, err := handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Expected an example, got %+v, %v", result, err)
+	}
+	if called {
+		t.Error("The tool handler must not run for example requests")
+	}
+
+	var example ActionExample
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &example); err != nil {
+		t.Fatalf("Invalid example: %v", err)
+	}
+	if example.Tool != "resources" || example.Action != "scorecard" || example.Request["action"] != "scorecard" {
+		t.Errorf("Unexpected example: %+v", example)
+	}
+
+	req.Params.Arguments = map[string]interface{}{"action": "delete", "example": true}
+	result, _ = handler(context.Background(), req)
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "examples exist for: get, getAvailability") {
+		t.Errorf("Expected a missing example error listing available actions, got %+v", result)
+	}
+
+	req.Params.Arguments = map[string]interface{}{"action": "get"}
+	handler(context.Background(), req)
+	if !called {
+		t.Error("Calls without example must reach the tool handler")
+	}
+}
+
+// TestExampleFixturesMatchTypes keeps the fixtures in step with the response types
+func TestExampleFixturesMatchTypes(t *testing.T) {
+	responseTypes := map[string]func() interface{}{
+		"resources/get":                 func() interface{} { return &types.Resource{} },
+		"resources/search":              func() interface{} { return &types.ResourceSearchResponse{} },
+		"resources/getMetrics":          func() interface{} { return &types.ResourceMetricsResponse{} },
+		"resources/getAvailability":     func() interface{} { return &types.ResourceAvailability{} },
+		"resources/listMetricInstances": func() interface{} { return &types.ResourceMetricInstances{} },
+		"resources/scorecard":           func() interface{} { return &types.ResourceScorecard{} },
+		"alerts/search":                 func() interface{} { return &types.AlertSearchResponse{} },
+		"alerts/get":                    func() interface{} { return &types.AlertDetails{} },
+		"alerts/getThread":              func() interface{} { return &types.AlertThread{} },
+		"alerts/addNote":                func() interface{} { return &types.AlertNote{} },
+		"usage/summary":                 func() interface{} { return &types.UsageSummary{} },
+		"metrics/query":                 func() interface{} { return &types.MetricQueryResult{} },
+		"metrics/listResourceMetrics":   func() interface{} { return &types.ResourceMetricNames{} },
+	}
+
+	all, err := loadExamples()
+	if err != nil {
+		t.Fatalf("Failed to load examples: %v", err)
+	}
+	for tool, actions := range all {
+		for action, example := range actions {
+			key := tool + "/" + action
+			newValue, ok := responseTypes[key]
+			if !ok {
+				t.Errorf("%s: no response type registered in this test", key)
+				continue
+			}
+			if example.Request["action"] != action {
+				t.Errorf("%s: request action is %v", key, example.Request["action"])
+			}
+			decoder := json.NewDecoder(strings.NewReader(string(example.Response)))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(newValue()); err != nil {
+				t.Errorf("%s: response does not match its type: %v", key, err)
+			}
+		}
+	}
+}
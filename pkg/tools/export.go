@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// exportFormats are the formats supported by the export utility action
+var exportFormats = []string{"csv", "json"}
+
+// exportResult renders a result as CSV or JSON text. CSV needs a list (or an
+// object wrapping a results list); each entry becomes a row with one column
+// per top-level field, nested values are written as JSON.
+func exportResult(value interface{}, format string) (string, error) {
+	switch format {
+	case "", "json":
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal result: %w", err)
+		}
+		return string(data), nil
+	case "csv":
+		generic, err := normalizeJSON(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode result: %w", err)
+		}
+		entries, ok := diffEntries(generic)
+		if !ok {
+			return "", fmt.Errorf("csv export needs a list result; use transform to select a list first")
+		}
+		return entriesToCSV(entries)
+	default:
+		return "", fmt.Errorf("unsupported export format %q: must be one of %v", format, exportFormats)
+	}
+}
+
+// entriesToCSV writes list entries as CSV with a header of all their fields
+func entriesToCSV(entries []interface{}) (string, error) {
+	columnSet := map[string]bool{}
+	for _, entry := range entries {
+		if fields, ok := entry.(map[string]interface{}); ok {
+			for key := range fields {
+				columnSet[key] = true
+			}
+		} else {
+			columnSet["value"] = true
+		}
+	}
+	columns := make([]string, 0, len(columnSet))
+	for column := range columnSet {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(columns); err != nil {
+		return "", fmt.Errorf("failed to write csv: %w", err)
+	}
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			fields = map[string]interface{}{"value": entry}
+		}
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = csvCell(fields[column])
+		}
+		if err := writer.Write(row); err != nil {
+			return "", fmt.Errorf("failed to write csv: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to write csv: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// csvCell formats a decoded JSON value as a CSV cell
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
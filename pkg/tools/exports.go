@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// exportURIPrefix is the MCP resource URI of exported results, followed by the export ID
+	exportURIPrefix = "opsramp://exports/"
+	// exportTTL is how long an export stays readable
+	exportTTL = time.Hour
+	// maxExportsPerSession bounds the exports kept for one session; the oldest is evicted first
+	maxExportsPerSession = 20
+)
+
+// exportMIMETypes maps export formats to the MIME type of their resource
+var exportMIMETypes = map[string]string{
+	"":     "application/json",
+	"json": "application/json",
+	"csv":  "text/csv",
+}
+
+// exportArtifact is an exported result kept for resources/read
+type exportArtifact struct {
+	ID        string
+	SessionID string
+	Name      string
+	MIMEType  string
+	Text      string
+	CreatedAt time.Time
+}
+
+var (
+	exportsMu sync.Mutex
+	exports   = make(map[string]*exportArtifact)
+	exportNow = time.Now
+)
+
+// storeExport keeps an exported result for the session and describes the
+// resource it can be read from
+func storeExport(sessionID, handle, format, text string, entries int) *types.ExportArtifact {
+	var raw [8]byte
+	_, _ = rand.Read(raw[:])
+	id := "ex-" + hex.EncodeToString(raw[:])
+	if format == "" {
+		format = "json"
+	}
+
+	exportsMu.Lock()
+	defer exportsMu.Unlock()
+
+	now := exportNow()
+	var owned []*exportArtifact
+	for key, artifact := range exports {
+		if now.Sub(artifact.CreatedAt) >= exportTTL {
+			delete(exports, key)
+			continue
+		}
+		if artifact.SessionID == sessionID {
+			owned = append(owned, artifact)
+		}
+	}
+	if excess := len(owned) - maxExportsPerSession + 1; excess > 0 {
+		sort.Slice(owned, func(i, j int) bool { return owned[i].CreatedAt.Before(owned[j].CreatedAt) })
+		for _, artifact := range owned[:excess] {
+			delete(exports, artifact.ID)
+		}
+	}
+
+	artifact := &exportArtifact{
+		ID:        id,
+		SessionID: sessionID,
+		Name:      fmt.Sprintf("%s.%s", handle, format),
+		MIMEType:  exportMIMETypes[format],
+		Text:      text,
+		CreatedAt: now,
+	}
+	exports[id] = artifact
+
+	return &types.ExportArtifact{
+		URI:       exportURIPrefix + id,
+		Name:      artifact.Name,
+		MIMEType:  artifact.MIMEType,
+		Size:      len(text),
+		Entries:   entries,
+		ExpiresAt: now.Add(exportTTL).UTC().Format(time.RFC3339),
+		Hint:      "Read the export with resources/read on its uri; it stays available to this session for an hour",
+	}
+}
+
+// exportResourceResult returns the tool result for an export: its
+// description, and a link to the resource holding its content
+func exportResourceResult(artifact *types.ExportArtifact) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(artifact, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export: %w", err)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{Type: "text", Text: string(data)},
+			mcp.NewResourceLink(artifact.URI, artifact.Name, "Exported result", artifact.MIMEType),
+		},
+	}, nil
+}
+
+// exportEntryCount returns the number of entries in a list result, or zero
+// for other results
+func exportEntryCount(value interface{}) int {
+	generic, err := normalizeJSON(value)
+	if err != nil {
+		return 0
+	}
+	if entries, ok := diffEntries(generic); ok {
+		return len(entries)
+	}
+	return 0
+}
+
+// NewExportResourceTemplate returns the MCP resource template and handler
+// serving exported results at opsramp://exports/{id}
+func NewExportResourceTemplate() (mcp.ResourceTemplate, server.ResourceTemplateHandlerFunc) {
+	return mcp.NewResourceTemplate(exportURIPrefix+"{id}", "Exported results",
+		mcp.WithTemplateDescription("Results exported by the utility tool's export action, readable by the session that exported them for an hour"),
+	), ReadExportResource
+}
+
+// ReadExportResource returns the content of an exported result to the
+// session that exported it
+// Exported for testing purposes
+func ReadExportResource(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	id := strings.TrimPrefix(req.Params.URI, exportURIPrefix)
+	sessionID := session.IDFromContext(ctx)
+
+	exportsMu.Lock()
+	defer exportsMu.Unlock()
+
+	artifact, ok := exports[id]
+	if !ok || id == req.Params.URI || artifact.SessionID != sessionID {
+		return nil, fmt.Errorf("unknown export: %s", req.Params.URI)
+	}
+	if exportNow().Sub(artifact.CreatedAt) >= exportTTL {
+		delete(exports, id)
+		return nil, fmt.Errorf("export %s has expired", req.Params.URI)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      req.Params.URI,
+		MIMEType: artifact.MIMEType,
+		Text:     artifact.Text,
+	}}, nil
+}
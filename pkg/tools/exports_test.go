@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestExportIsReadableAsResource(t *testing.T) {
+	ctx := contextWithSession("export-session")
+	handle := storeResultHandle("export-session", "resources", []map[string]interface{}{
+		{"id": "r1", "hostName": "web-01"},
+		{"id": "r2", "hostName": "web-02"},
+	}, 100)
+
+	res, _ := UtilityToolHandler(ctx, createTestRequest(map[string]interface{}{"action": "export", "handle": handle, "format": "csv"}))
+	if res.IsError || len(res.Content) != 2 {
+		t.Fatalf("export failed: %+v", res)
+	}
+	var artifact types.ExportArtifact
+	if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &artifact); err != nil {
+		t.Fatalf("Invalid export description: %v", err)
+	}
+	if !strings.HasPrefix(artifact.URI, "opsramp://exports/ex-") || artifact.MIMEType != "text/csv" ||
+		artifact.Entries != 2 || artifact.Name != handle+".csv" {
+		t.Errorf("Unexpected export %+v", artifact)
+	}
+	if link := res.Content[1].(mcp.ResourceLink); link.URI != artifact.URI {
+		t.Errorf("Expected a link to %s, got %+v", artifact.URI, link)
+	}
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = artifact.URI
+	contents, err := ReadExportResource(ctx, req)
+	if err != nil {
+		t.Fatalf("resources/read failed: %v", err)
+	}
+	text := contents[0].(mcp.TextResourceContents)
+	if text.MIMEType != "text/csv" || len(text.Text) != artifact.Size || !strings.HasPrefix(text.Text, "hostName,id\n") {
+		t.Errorf("Unexpected export content %+v", text)
+	}
+
+	// Other sessions cannot read it, and it expires
+	if _, err := ReadExportResource(contextWithSession("other-session"), req); err == nil {
+		t.Error("Expected another session's read to fail")
+	}
+	exportNow = func() time.Time { return time.Now().Add(exportTTL) }
+	defer func() { exportNow = time.Now }()
+	if _, err := ReadExportResource(ctx, req); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Expected the export to expire, got %v", err)
+	}
+}
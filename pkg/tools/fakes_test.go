@@ -0,0 +1,2484 @@
+// Code generated by fakegen. DO NOT EDIT.
+
+package tools
+
+import (
+	"context"
+	"sync"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// FakeResourcesAPI is a generated fake of ResourcesAPI
+type FakeResourcesAPI struct {
+	ApplyTemplatesStub        func(context.Context, string, []string) error
+	applyTemplatesMutex       sync.RWMutex
+	applyTemplatesArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+	}
+	applyTemplatesReturns struct {
+		result1 error
+	}
+	AssignToDeviceGroupStub        func(context.Context, string, []string) error
+	assignToDeviceGroupMutex       sync.RWMutex
+	assignToDeviceGroupArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+	}
+	assignToDeviceGroupReturns struct {
+		result1 error
+	}
+	AssignToSiteStub        func(context.Context, string, []string) error
+	assignToSiteMutex       sync.RWMutex
+	assignToSiteArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+	}
+	assignToSiteReturns struct {
+		result1 error
+	}
+	BulkDeleteStub        func(context.Context, types.ResourceBulkDeleteRequest) error
+	bulkDeleteMutex       sync.RWMutex
+	bulkDeleteArgsForCall []struct {
+		arg1 context.Context
+		arg2 types.ResourceBulkDeleteRequest
+	}
+	bulkDeleteReturns struct {
+		result1 error
+	}
+	BulkUpdateStub        func(context.Context, types.ResourceBulkUpdateRequest) error
+	bulkUpdateMutex       sync.RWMutex
+	bulkUpdateArgsForCall []struct {
+		arg1 context.Context
+		arg2 types.ResourceBulkUpdateRequest
+	}
+	bulkUpdateReturns struct {
+		result1 error
+	}
+	ChangeStateStub        func(context.Context, string, types.ResourceStateChangeRequest) error
+	changeStateMutex       sync.RWMutex
+	changeStateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.ResourceStateChangeRequest
+	}
+	changeStateReturns struct {
+		result1 error
+	}
+	CreateStub        func(context.Context, types.ResourceCreateRequest) (*types.Resource, error)
+	createMutex       sync.RWMutex
+	createArgsForCall []struct {
+		arg1 context.Context
+		arg2 types.ResourceCreateRequest
+	}
+	createReturns struct {
+		result1 *types.Resource
+		result2 error
+	}
+	DeleteStub        func(context.Context, string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	GetStub        func(context.Context, string) (*types.Resource, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getReturns struct {
+		result1 *types.Resource
+		result2 error
+	}
+	GetDetailedStub        func(context.Context, string) (*types.DetailedResource, error)
+	getDetailedMutex       sync.RWMutex
+	getDetailedArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getDetailedReturns struct {
+		result1 *types.DetailedResource
+		result2 error
+	}
+	GetMetricsStub        func(context.Context, string, types.ResourceMetricsRequest) (*types.ResourceMetricsResponse, error)
+	getMetricsMutex       sync.RWMutex
+	getMetricsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.ResourceMetricsRequest
+	}
+	getMetricsReturns struct {
+		result1 *types.ResourceMetricsResponse
+		result2 error
+	}
+	GetMinimalStub        func(context.Context, string) (*types.ResourceMinimal, error)
+	getMinimalMutex       sync.RWMutex
+	getMinimalArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getMinimalReturns struct {
+		result1 *types.ResourceMinimal
+		result2 error
+	}
+	GetRelationshipsStub        func(context.Context, string) ([]types.ResourceRelationship, error)
+	getRelationshipsMutex       sync.RWMutex
+	getRelationshipsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getRelationshipsReturns struct {
+		result1 []types.ResourceRelationship
+		result2 error
+	}
+	GetResourceTypesStub        func(context.Context) ([]types.ResourceTypeInfo, error)
+	getResourceTypesMutex       sync.RWMutex
+	getResourceTypesArgsForCall []struct {
+		arg1 context.Context
+	}
+	getResourceTypesReturns struct {
+		result1 []types.ResourceTypeInfo
+		result2 error
+	}
+	GetServiceGroupsStub        func(context.Context, string) ([]types.ServiceGroup, error)
+	getServiceGroupsMutex       sync.RWMutex
+	getServiceGroupsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getServiceGroupsReturns struct {
+		result1 []types.ServiceGroup
+		result2 error
+	}
+	GetTagsStub        func(context.Context, string) ([]types.Tag, error)
+	getTagsMutex       sync.RWMutex
+	getTagsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getTagsReturns struct {
+		result1 []types.Tag
+		result2 error
+	}
+	SearchStub        func(context.Context, types.ResourceSearchParams) (*types.ResourceSearchResponse, error)
+	searchMutex       sync.RWMutex
+	searchArgsForCall []struct {
+		arg1 context.Context
+		arg2 types.ResourceSearchParams
+	}
+	searchReturns struct {
+		result1 *types.ResourceSearchResponse
+		result2 error
+	}
+	UpdateStub        func(context.Context, string, types.ResourceUpdateRequest) (*types.Resource, error)
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.ResourceUpdateRequest
+	}
+	updateReturns struct {
+		result1 *types.Resource
+		result2 error
+	}
+	UpdateTagsStub        func(context.Context, string, []types.Tag) error
+	updateTagsMutex       sync.RWMutex
+	updateTagsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []types.Tag
+	}
+	updateTagsReturns struct {
+		result1 error
+	}
+}
+
+// ApplyTemplates records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) ApplyTemplates(arg1 context.Context, arg2 string, arg3 []string) error {
+	fake.applyTemplatesMutex.Lock()
+	fake.applyTemplatesArgsForCall = append(fake.applyTemplatesArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+	}{arg1, arg2, arg3})
+	stub := fake.ApplyTemplatesStub
+	returns := fake.applyTemplatesReturns
+	fake.applyTemplatesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1
+}
+
+// ApplyTemplatesCallCount returns the number of calls to ApplyTemplates
+func (fake *FakeResourcesAPI) ApplyTemplatesCallCount() int {
+	fake.applyTemplatesMutex.RLock()
+	defer fake.applyTemplatesMutex.RUnlock()
+	return len(fake.applyTemplatesArgsForCall)
+}
+
+// ApplyTemplatesArgsForCall returns the arguments of the i-th call to ApplyTemplates
+func (fake *FakeResourcesAPI) ApplyTemplatesArgsForCall(i int) (context.Context, string, []string) {
+	fake.applyTemplatesMutex.RLock()
+	defer fake.applyTemplatesMutex.RUnlock()
+	call := fake.applyTemplatesArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// ApplyTemplatesReturns sets the results of calls to ApplyTemplates without a stub
+func (fake *FakeResourcesAPI) ApplyTemplatesReturns(result1 error) {
+	fake.applyTemplatesMutex.Lock()
+	defer fake.applyTemplatesMutex.Unlock()
+	fake.ApplyTemplatesStub = nil
+	fake.applyTemplatesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// AssignToDeviceGroup records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) AssignToDeviceGroup(arg1 context.Context, arg2 string, arg3 []string) error {
+	fake.assignToDeviceGroupMutex.Lock()
+	fake.assignToDeviceGroupArgsForCall = append(fake.assignToDeviceGroupArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+	}{arg1, arg2, arg3})
+	stub := fake.AssignToDeviceGroupStub
+	returns := fake.assignToDeviceGroupReturns
+	fake.assignToDeviceGroupMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1
+}
+
+// AssignToDeviceGroupCallCount returns the number of calls to AssignToDeviceGroup
+func (fake *FakeResourcesAPI) AssignToDeviceGroupCallCount() int {
+	fake.assignToDeviceGroupMutex.RLock()
+	defer fake.assignToDeviceGroupMutex.RUnlock()
+	return len(fake.assignToDeviceGroupArgsForCall)
+}
+
+// AssignToDeviceGroupArgsForCall returns the arguments of the i-th call to AssignToDeviceGroup
+func (fake *FakeResourcesAPI) AssignToDeviceGroupArgsForCall(i int) (context.Context, string, []string) {
+	fake.assignToDeviceGroupMutex.RLock()
+	defer fake.assignToDeviceGroupMutex.RUnlock()
+	call := fake.assignToDeviceGroupArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// AssignToDeviceGroupReturns sets the results of calls to AssignToDeviceGroup without a stub
+func (fake *FakeResourcesAPI) AssignToDeviceGroupReturns(result1 error) {
+	fake.assignToDeviceGroupMutex.Lock()
+	defer fake.assignToDeviceGroupMutex.Unlock()
+	fake.AssignToDeviceGroupStub = nil
+	fake.assignToDeviceGroupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// AssignToSite records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) AssignToSite(arg1 context.Context, arg2 string, arg3 []string) error {
+	fake.assignToSiteMutex.Lock()
+	fake.assignToSiteArgsForCall = append(fake.assignToSiteArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+	}{arg1, arg2, arg3})
+	stub := fake.AssignToSiteStub
+	returns := fake.assignToSiteReturns
+	fake.assignToSiteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1
+}
+
+// AssignToSiteCallCount returns the number of calls to AssignToSite
+func (fake *FakeResourcesAPI) AssignToSiteCallCount() int {
+	fake.assignToSiteMutex.RLock()
+	defer fake.assignToSiteMutex.RUnlock()
+	return len(fake.assignToSiteArgsForCall)
+}
+
+// AssignToSiteArgsForCall returns the arguments of the i-th call to AssignToSite
+func (fake *FakeResourcesAPI) AssignToSiteArgsForCall(i int) (context.Context, string, []string) {
+	fake.assignToSiteMutex.RLock()
+	defer fake.assignToSiteMutex.RUnlock()
+	call := fake.assignToSiteArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// AssignToSiteReturns sets the results of calls to AssignToSite without a stub
+func (fake *FakeResourcesAPI) AssignToSiteReturns(result1 error) {
+	fake.assignToSiteMutex.Lock()
+	defer fake.assignToSiteMutex.Unlock()
+	fake.AssignToSiteStub = nil
+	fake.assignToSiteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// BulkDelete records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) BulkDelete(arg1 context.Context, arg2 types.ResourceBulkDeleteRequest) error {
+	fake.bulkDeleteMutex.Lock()
+	fake.bulkDeleteArgsForCall = append(fake.bulkDeleteArgsForCall, struct {
+		arg1 context.Context
+		arg2 types.ResourceBulkDeleteRequest
+	}{arg1, arg2})
+	stub := fake.BulkDeleteStub
+	returns := fake.bulkDeleteReturns
+	fake.bulkDeleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1
+}
+
+// BulkDeleteCallCount returns the number of calls to BulkDelete
+func (fake *FakeResourcesAPI) BulkDeleteCallCount() int {
+	fake.bulkDeleteMutex.RLock()
+	defer fake.bulkDeleteMutex.RUnlock()
+	return len(fake.bulkDeleteArgsForCall)
+}
+
+// BulkDeleteArgsForCall returns the arguments of the i-th call to BulkDelete
+func (fake *FakeResourcesAPI) BulkDeleteArgsForCall(i int) (context.Context, types.ResourceBulkDeleteRequest) {
+	fake.bulkDeleteMutex.RLock()
+	defer fake.bulkDeleteMutex.RUnlock()
+	call := fake.bulkDeleteArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// BulkDeleteReturns sets the results of calls to BulkDelete without a stub
+func (fake *FakeResourcesAPI) BulkDeleteReturns(result1 error) {
+	fake.bulkDeleteMutex.Lock()
+	defer fake.bulkDeleteMutex.Unlock()
+	fake.BulkDeleteStub = nil
+	fake.bulkDeleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// BulkUpdate records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) BulkUpdate(arg1 context.Context, arg2 types.ResourceBulkUpdateRequest) error {
+	fake.bulkUpdateMutex.Lock()
+	fake.bulkUpdateArgsForCall = append(fake.bulkUpdateArgsForCall, struct {
+		arg1 context.Context
+		arg2 types.ResourceBulkUpdateRequest
+	}{arg1, arg2})
+	stub := fake.BulkUpdateStub
+	returns := fake.bulkUpdateReturns
+	fake.bulkUpdateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1
+}
+
+// BulkUpdateCallCount returns the number of calls to BulkUpdate
+func (fake *FakeResourcesAPI) BulkUpdateCallCount() int {
+	fake.bulkUpdateMutex.RLock()
+	defer fake.bulkUpdateMutex.RUnlock()
+	return len(fake.bulkUpdateArgsForCall)
+}
+
+// BulkUpdateArgsForCall returns the arguments of the i-th call to BulkUpdate
+func (fake *FakeResourcesAPI) BulkUpdateArgsForCall(i int) (context.Context, types.ResourceBulkUpdateRequest) {
+	fake.bulkUpdateMutex.RLock()
+	defer fake.bulkUpdateMutex.RUnlock()
+	call := fake.bulkUpdateArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// BulkUpdateReturns sets the results of calls to BulkUpdate without a stub
+func (fake *FakeResourcesAPI) BulkUpdateReturns(result1 error) {
+	fake.bulkUpdateMutex.Lock()
+	defer fake.bulkUpdateMutex.Unlock()
+	fake.BulkUpdateStub = nil
+	fake.bulkUpdateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// ChangeState records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) ChangeState(arg1 context.Context, arg2 string, arg3 types.ResourceStateChangeRequest) error {
+	fake.changeStateMutex.Lock()
+	fake.changeStateArgsForCall = append(fake.changeStateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.ResourceStateChangeRequest
+	}{arg1, arg2, arg3})
+	stub := fake.ChangeStateStub
+	returns := fake.changeStateReturns
+	fake.changeStateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1
+}
+
+// ChangeStateCallCount returns the number of calls to ChangeState
+func (fake *FakeResourcesAPI) ChangeStateCallCount() int {
+	fake.changeStateMutex.RLock()
+	defer fake.changeStateMutex.RUnlock()
+	return len(fake.changeStateArgsForCall)
+}
+
+// ChangeStateArgsForCall returns the arguments of the i-th call to ChangeState
+func (fake *FakeResourcesAPI) ChangeStateArgsForCall(i int) (context.Context, string, types.ResourceStateChangeRequest) {
+	fake.changeStateMutex.RLock()
+	defer fake.changeStateMutex.RUnlock()
+	call := fake.changeStateArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// ChangeStateReturns sets the results of calls to ChangeState without a stub
+func (fake *FakeResourcesAPI) ChangeStateReturns(result1 error) {
+	fake.changeStateMutex.Lock()
+	defer fake.changeStateMutex.Unlock()
+	fake.ChangeStateStub = nil
+	fake.changeStateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// Create records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) Create(arg1 context.Context, arg2 types.ResourceCreateRequest) (*types.Resource, error) {
+	fake.createMutex.Lock()
+	fake.createArgsForCall = append(fake.createArgsForCall, struct {
+		arg1 context.Context
+		arg2 types.ResourceCreateRequest
+	}{arg1, arg2})
+	stub := fake.CreateStub
+	returns := fake.createReturns
+	fake.createMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// CreateCallCount returns the number of calls to Create
+func (fake *FakeResourcesAPI) CreateCallCount() int {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	return len(fake.createArgsForCall)
+}
+
+// CreateArgsForCall returns the arguments of the i-th call to Create
+func (fake *FakeResourcesAPI) CreateArgsForCall(i int) (context.Context, types.ResourceCreateRequest) {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	call := fake.createArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// CreateReturns sets the results of calls to Create without a stub
+func (fake *FakeResourcesAPI) CreateReturns(result1 *types.Resource, result2 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = nil
+	fake.createReturns = struct {
+		result1 *types.Resource
+		result2 error
+	}{result1, result2}
+}
+
+// Delete records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) Delete(arg1 context.Context, arg2 string) error {
+	fake.deleteMutex.Lock()
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeleteStub
+	returns := fake.deleteReturns
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1
+}
+
+// DeleteCallCount returns the number of calls to Delete
+func (fake *FakeResourcesAPI) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+// DeleteArgsForCall returns the arguments of the i-th call to Delete
+func (fake *FakeResourcesAPI) DeleteArgsForCall(i int) (context.Context, string) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	call := fake.deleteArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// DeleteReturns sets the results of calls to Delete without a stub
+func (fake *FakeResourcesAPI) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// Get records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) Get(arg1 context.Context, arg2 string) (*types.Resource, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetStub
+	returns := fake.getReturns
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetCallCount returns the number of calls to Get
+func (fake *FakeResourcesAPI) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+// GetArgsForCall returns the arguments of the i-th call to Get
+func (fake *FakeResourcesAPI) GetArgsForCall(i int) (context.Context, string) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	call := fake.getArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetReturns sets the results of calls to Get without a stub
+func (fake *FakeResourcesAPI) GetReturns(result1 *types.Resource, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 *types.Resource
+		result2 error
+	}{result1, result2}
+}
+
+// GetDetailed records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) GetDetailed(arg1 context.Context, arg2 string) (*types.DetailedResource, error) {
+	fake.getDetailedMutex.Lock()
+	fake.getDetailedArgsForCall = append(fake.getDetailedArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetDetailedStub
+	returns := fake.getDetailedReturns
+	fake.getDetailedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetDetailedCallCount returns the number of calls to GetDetailed
+func (fake *FakeResourcesAPI) GetDetailedCallCount() int {
+	fake.getDetailedMutex.RLock()
+	defer fake.getDetailedMutex.RUnlock()
+	return len(fake.getDetailedArgsForCall)
+}
+
+// GetDetailedArgsForCall returns the arguments of the i-th call to GetDetailed
+func (fake *FakeResourcesAPI) GetDetailedArgsForCall(i int) (context.Context, string) {
+	fake.getDetailedMutex.RLock()
+	defer fake.getDetailedMutex.RUnlock()
+	call := fake.getDetailedArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetDetailedReturns sets the results of calls to GetDetailed without a stub
+func (fake *FakeResourcesAPI) GetDetailedReturns(result1 *types.DetailedResource, result2 error) {
+	fake.getDetailedMutex.Lock()
+	defer fake.getDetailedMutex.Unlock()
+	fake.GetDetailedStub = nil
+	fake.getDetailedReturns = struct {
+		result1 *types.DetailedResource
+		result2 error
+	}{result1, result2}
+}
+
+// GetMetrics records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) GetMetrics(arg1 context.Context, arg2 string, arg3 types.ResourceMetricsRequest) (*types.ResourceMetricsResponse, error) {
+	fake.getMetricsMutex.Lock()
+	fake.getMetricsArgsForCall = append(fake.getMetricsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.ResourceMetricsRequest
+	}{arg1, arg2, arg3})
+	stub := fake.GetMetricsStub
+	returns := fake.getMetricsReturns
+	fake.getMetricsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetMetricsCallCount returns the number of calls to GetMetrics
+func (fake *FakeResourcesAPI) GetMetricsCallCount() int {
+	fake.getMetricsMutex.RLock()
+	defer fake.getMetricsMutex.RUnlock()
+	return len(fake.getMetricsArgsForCall)
+}
+
+// GetMetricsArgsForCall returns the arguments of the i-th call to GetMetrics
+func (fake *FakeResourcesAPI) GetMetricsArgsForCall(i int) (context.Context, string, types.ResourceMetricsRequest) {
+	fake.getMetricsMutex.RLock()
+	defer fake.getMetricsMutex.RUnlock()
+	call := fake.getMetricsArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// GetMetricsReturns sets the results of calls to GetMetrics without a stub
+func (fake *FakeResourcesAPI) GetMetricsReturns(result1 *types.ResourceMetricsResponse, result2 error) {
+	fake.getMetricsMutex.Lock()
+	defer fake.getMetricsMutex.Unlock()
+	fake.GetMetricsStub = nil
+	fake.getMetricsReturns = struct {
+		result1 *types.ResourceMetricsResponse
+		result2 error
+	}{result1, result2}
+}
+
+// GetMinimal records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) GetMinimal(arg1 context.Context, arg2 string) (*types.ResourceMinimal, error) {
+	fake.getMinimalMutex.Lock()
+	fake.getMinimalArgsForCall = append(fake.getMinimalArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetMinimalStub
+	returns := fake.getMinimalReturns
+	fake.getMinimalMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetMinimalCallCount returns the number of calls to GetMinimal
+func (fake *FakeResourcesAPI) GetMinimalCallCount() int {
+	fake.getMinimalMutex.RLock()
+	defer fake.getMinimalMutex.RUnlock()
+	return len(fake.getMinimalArgsForCall)
+}
+
+// GetMinimalArgsForCall returns the arguments of the i-th call to GetMinimal
+func (fake *FakeResourcesAPI) GetMinimalArgsForCall(i int) (context.Context, string) {
+	fake.getMinimalMutex.RLock()
+	defer fake.getMinimalMutex.RUnlock()
+	call := fake.getMinimalArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetMinimalReturns sets the results of calls to GetMinimal without a stub
+func (fake *FakeResourcesAPI) GetMinimalReturns(result1 *types.ResourceMinimal, result2 error) {
+	fake.getMinimalMutex.Lock()
+	defer fake.getMinimalMutex.Unlock()
+	fake.GetMinimalStub = nil
+	fake.getMinimalReturns = struct {
+		result1 *types.ResourceMinimal
+		result2 error
+	}{result1, result2}
+}
+
+// GetRelationships records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) GetRelationships(arg1 context.Context, arg2 string) ([]types.ResourceRelationship, error) {
+	fake.getRelationshipsMutex.Lock()
+	fake.getRelationshipsArgsForCall = append(fake.getRelationshipsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetRelationshipsStub
+	returns := fake.getRelationshipsReturns
+	fake.getRelationshipsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetRelationshipsCallCount returns the number of calls to GetRelationships
+func (fake *FakeResourcesAPI) GetRelationshipsCallCount() int {
+	fake.getRelationshipsMutex.RLock()
+	defer fake.getRelationshipsMutex.RUnlock()
+	return len(fake.getRelationshipsArgsForCall)
+}
+
+// GetRelationshipsArgsForCall returns the arguments of the i-th call to GetRelationships
+func (fake *FakeResourcesAPI) GetRelationshipsArgsForCall(i int) (context.Context, string) {
+	fake.getRelationshipsMutex.RLock()
+	defer fake.getRelationshipsMutex.RUnlock()
+	call := fake.getRelationshipsArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetRelationshipsReturns sets the results of calls to GetRelationships without a stub
+func (fake *FakeResourcesAPI) GetRelationshipsReturns(result1 []types.ResourceRelationship, result2 error) {
+	fake.getRelationshipsMutex.Lock()
+	defer fake.getRelationshipsMutex.Unlock()
+	fake.GetRelationshipsStub = nil
+	fake.getRelationshipsReturns = struct {
+		result1 []types.ResourceRelationship
+		result2 error
+	}{result1, result2}
+}
+
+// GetResourceTypes records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) GetResourceTypes(arg1 context.Context) ([]types.ResourceTypeInfo, error) {
+	fake.getResourceTypesMutex.Lock()
+	fake.getResourceTypesArgsForCall = append(fake.getResourceTypesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetResourceTypesStub
+	returns := fake.getResourceTypesReturns
+	fake.getResourceTypesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetResourceTypesCallCount returns the number of calls to GetResourceTypes
+func (fake *FakeResourcesAPI) GetResourceTypesCallCount() int {
+	fake.getResourceTypesMutex.RLock()
+	defer fake.getResourceTypesMutex.RUnlock()
+	return len(fake.getResourceTypesArgsForCall)
+}
+
+// GetResourceTypesArgsForCall returns the arguments of the i-th call to GetResourceTypes
+func (fake *FakeResourcesAPI) GetResourceTypesArgsForCall(i int) context.Context {
+	fake.getResourceTypesMutex.RLock()
+	defer fake.getResourceTypesMutex.RUnlock()
+	call := fake.getResourceTypesArgsForCall[i]
+	return call.arg1
+}
+
+// GetResourceTypesReturns sets the results of calls to GetResourceTypes without a stub
+func (fake *FakeResourcesAPI) GetResourceTypesReturns(result1 []types.ResourceTypeInfo, result2 error) {
+	fake.getResourceTypesMutex.Lock()
+	defer fake.getResourceTypesMutex.Unlock()
+	fake.GetResourceTypesStub = nil
+	fake.getResourceTypesReturns = struct {
+		result1 []types.ResourceTypeInfo
+		result2 error
+	}{result1, result2}
+}
+
+// GetServiceGroups records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) GetServiceGroups(arg1 context.Context, arg2 string) ([]types.ServiceGroup, error) {
+	fake.getServiceGroupsMutex.Lock()
+	fake.getServiceGroupsArgsForCall = append(fake.getServiceGroupsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetServiceGroupsStub
+	returns := fake.getServiceGroupsReturns
+	fake.getServiceGroupsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetServiceGroupsCallCount returns the number of calls to GetServiceGroups
+func (fake *FakeResourcesAPI) GetServiceGroupsCallCount() int {
+	fake.getServiceGroupsMutex.RLock()
+	defer fake.getServiceGroupsMutex.RUnlock()
+	return len(fake.getServiceGroupsArgsForCall)
+}
+
+// GetServiceGroupsArgsForCall returns the arguments of the i-th call to GetServiceGroups
+func (fake *FakeResourcesAPI) GetServiceGroupsArgsForCall(i int) (context.Context, string) {
+	fake.getServiceGroupsMutex.RLock()
+	defer fake.getServiceGroupsMutex.RUnlock()
+	call := fake.getServiceGroupsArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetServiceGroupsReturns sets the results of calls to GetServiceGroups without a stub
+func (fake *FakeResourcesAPI) GetServiceGroupsReturns(result1 []types.ServiceGroup, result2 error) {
+	fake.getServiceGroupsMutex.Lock()
+	defer fake.getServiceGroupsMutex.Unlock()
+	fake.GetServiceGroupsStub = nil
+	fake.getServiceGroupsReturns = struct {
+		result1 []types.ServiceGroup
+		result2 error
+	}{result1, result2}
+}
+
+// GetTags records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) GetTags(arg1 context.Context, arg2 string) ([]types.Tag, error) {
+	fake.getTagsMutex.Lock()
+	fake.getTagsArgsForCall = append(fake.getTagsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetTagsStub
+	returns := fake.getTagsReturns
+	fake.getTagsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetTagsCallCount returns the number of calls to GetTags
+func (fake *FakeResourcesAPI) GetTagsCallCount() int {
+	fake.getTagsMutex.RLock()
+	defer fake.getTagsMutex.RUnlock()
+	return len(fake.getTagsArgsForCall)
+}
+
+// GetTagsArgsForCall returns the arguments of the i-th call to GetTags
+func (fake *FakeResourcesAPI) GetTagsArgsForCall(i int) (context.Context, string) {
+	fake.getTagsMutex.RLock()
+	defer fake.getTagsMutex.RUnlock()
+	call := fake.getTagsArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetTagsReturns sets the results of calls to GetTags without a stub
+func (fake *FakeResourcesAPI) GetTagsReturns(result1 []types.Tag, result2 error) {
+	fake.getTagsMutex.Lock()
+	defer fake.getTagsMutex.Unlock()
+	fake.GetTagsStub = nil
+	fake.getTagsReturns = struct {
+		result1 []types.Tag
+		result2 error
+	}{result1, result2}
+}
+
+// Search records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) Search(arg1 context.Context, arg2 types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+	fake.searchMutex.Lock()
+	fake.searchArgsForCall = append(fake.searchArgsForCall, struct {
+		arg1 context.Context
+		arg2 types.ResourceSearchParams
+	}{arg1, arg2})
+	stub := fake.SearchStub
+	returns := fake.searchReturns
+	fake.searchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// SearchCallCount returns the number of calls to Search
+func (fake *FakeResourcesAPI) SearchCallCount() int {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	return len(fake.searchArgsForCall)
+}
+
+// SearchArgsForCall returns the arguments of the i-th call to Search
+func (fake *FakeResourcesAPI) SearchArgsForCall(i int) (context.Context, types.ResourceSearchParams) {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	call := fake.searchArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// SearchReturns sets the results of calls to Search without a stub
+func (fake *FakeResourcesAPI) SearchReturns(result1 *types.ResourceSearchResponse, result2 error) {
+	fake.searchMutex.Lock()
+	defer fake.searchMutex.Unlock()
+	fake.SearchStub = nil
+	fake.searchReturns = struct {
+		result1 *types.ResourceSearchResponse
+		result2 error
+	}{result1, result2}
+}
+
+// Update records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) Update(arg1 context.Context, arg2 string, arg3 types.ResourceUpdateRequest) (*types.Resource, error) {
+	fake.updateMutex.Lock()
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.ResourceUpdateRequest
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateStub
+	returns := fake.updateReturns
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1, returns.result2
+}
+
+// UpdateCallCount returns the number of calls to Update
+func (fake *FakeResourcesAPI) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+// UpdateArgsForCall returns the arguments of the i-th call to Update
+func (fake *FakeResourcesAPI) UpdateArgsForCall(i int) (context.Context, string, types.ResourceUpdateRequest) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	call := fake.updateArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// UpdateReturns sets the results of calls to Update without a stub
+func (fake *FakeResourcesAPI) UpdateReturns(result1 *types.Resource, result2 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 *types.Resource
+		result2 error
+	}{result1, result2}
+}
+
+// UpdateTags records the call and returns the stubbed results
+func (fake *FakeResourcesAPI) UpdateTags(arg1 context.Context, arg2 string, arg3 []types.Tag) error {
+	fake.updateTagsMutex.Lock()
+	fake.updateTagsArgsForCall = append(fake.updateTagsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []types.Tag
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateTagsStub
+	returns := fake.updateTagsReturns
+	fake.updateTagsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1
+}
+
+// UpdateTagsCallCount returns the number of calls to UpdateTags
+func (fake *FakeResourcesAPI) UpdateTagsCallCount() int {
+	fake.updateTagsMutex.RLock()
+	defer fake.updateTagsMutex.RUnlock()
+	return len(fake.updateTagsArgsForCall)
+}
+
+// UpdateTagsArgsForCall returns the arguments of the i-th call to UpdateTags
+func (fake *FakeResourcesAPI) UpdateTagsArgsForCall(i int) (context.Context, string, []types.Tag) {
+	fake.updateTagsMutex.RLock()
+	defer fake.updateTagsMutex.RUnlock()
+	call := fake.updateTagsArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// UpdateTagsReturns sets the results of calls to UpdateTags without a stub
+func (fake *FakeResourcesAPI) UpdateTagsReturns(result1 error) {
+	fake.updateTagsMutex.Lock()
+	defer fake.updateTagsMutex.Unlock()
+	fake.UpdateTagsStub = nil
+	fake.updateTagsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ ResourcesAPI = new(FakeResourcesAPI)
+
+// FakeIntegrationsAPI is a generated fake of IntegrationsAPI
+type FakeIntegrationsAPI struct {
+	CreateStub        func(context.Context, map[string]interface{}) (*types.Integration, error)
+	createMutex       sync.RWMutex
+	createArgsForCall []struct {
+		arg1 context.Context
+		arg2 map[string]interface{}
+	}
+	createReturns struct {
+		result1 *types.Integration
+		result2 error
+	}
+	DeleteStub        func(context.Context, string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	DisableStub        func(context.Context, string) error
+	disableMutex       sync.RWMutex
+	disableArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	disableReturns struct {
+		result1 error
+	}
+	EnableStub        func(context.Context, string) error
+	enableMutex       sync.RWMutex
+	enableArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	enableReturns struct {
+		result1 error
+	}
+	GetStub        func(context.Context, string) (*types.Integration, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getReturns struct {
+		result1 *types.Integration
+		result2 error
+	}
+	GetDetailedStub        func(context.Context, string) (*types.DetailedIntegration, error)
+	getDetailedMutex       sync.RWMutex
+	getDetailedArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getDetailedReturns struct {
+		result1 *types.DetailedIntegration
+		result2 error
+	}
+	GetTypeStub        func(context.Context, string) (*types.IntegrationType, error)
+	getTypeMutex       sync.RWMutex
+	getTypeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getTypeReturns struct {
+		result1 *types.IntegrationType
+		result2 error
+	}
+	ListStub        func(context.Context) ([]types.Integration, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+		arg1 context.Context
+	}
+	listReturns struct {
+		result1 []types.Integration
+		result2 error
+	}
+	ListTypesStub        func(context.Context) ([]types.IntegrationType, error)
+	listTypesMutex       sync.RWMutex
+	listTypesArgsForCall []struct {
+		arg1 context.Context
+	}
+	listTypesReturns struct {
+		result1 []types.IntegrationType
+		result2 error
+	}
+	UpdateStub        func(context.Context, string, map[string]interface{}) (*types.Integration, error)
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]interface{}
+	}
+	updateReturns struct {
+		result1 *types.Integration
+		result2 error
+	}
+}
+
+// Create records the call and returns the stubbed results
+func (fake *FakeIntegrationsAPI) Create(arg1 context.Context, arg2 map[string]interface{}) (*types.Integration, error) {
+	fake.createMutex.Lock()
+	fake.createArgsForCall = append(fake.createArgsForCall, struct {
+		arg1 context.Context
+		arg2 map[string]interface{}
+	}{arg1, arg2})
+	stub := fake.CreateStub
+	returns := fake.createReturns
+	fake.createMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// CreateCallCount returns the number of calls to Create
+func (fake *FakeIntegrationsAPI) CreateCallCount() int {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	return len(fake.createArgsForCall)
+}
+
+// CreateArgsForCall returns the arguments of the i-th call to Create
+func (fake *FakeIntegrationsAPI) CreateArgsForCall(i int) (context.Context, map[string]interface{}) {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	call := fake.createArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// CreateReturns sets the results of calls to Create without a stub
+func (fake *FakeIntegrationsAPI) CreateReturns(result1 *types.Integration, result2 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = nil
+	fake.createReturns = struct {
+		result1 *types.Integration
+		result2 error
+	}{result1, result2}
+}
+
+// Delete records the call and returns the stubbed results
+func (fake *FakeIntegrationsAPI) Delete(arg1 context.Context, arg2 string) error {
+	fake.deleteMutex.Lock()
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeleteStub
+	returns := fake.deleteReturns
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1
+}
+
+// DeleteCallCount returns the number of calls to Delete
+func (fake *FakeIntegrationsAPI) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+// DeleteArgsForCall returns the arguments of the i-th call to Delete
+func (fake *FakeIntegrationsAPI) DeleteArgsForCall(i int) (context.Context, string) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	call := fake.deleteArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// DeleteReturns sets the results of calls to Delete without a stub
+func (fake *FakeIntegrationsAPI) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// Disable records the call and returns the stubbed results
+func (fake *FakeIntegrationsAPI) Disable(arg1 context.Context, arg2 string) error {
+	fake.disableMutex.Lock()
+	fake.disableArgsForCall = append(fake.disableArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DisableStub
+	returns := fake.disableReturns
+	fake.disableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1
+}
+
+// DisableCallCount returns the number of calls to Disable
+func (fake *FakeIntegrationsAPI) DisableCallCount() int {
+	fake.disableMutex.RLock()
+	defer fake.disableMutex.RUnlock()
+	return len(fake.disableArgsForCall)
+}
+
+// DisableArgsForCall returns the arguments of the i-th call to Disable
+func (fake *FakeIntegrationsAPI) DisableArgsForCall(i int) (context.Context, string) {
+	fake.disableMutex.RLock()
+	defer fake.disableMutex.RUnlock()
+	call := fake.disableArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// DisableReturns sets the results of calls to Disable without a stub
+func (fake *FakeIntegrationsAPI) DisableReturns(result1 error) {
+	fake.disableMutex.Lock()
+	defer fake.disableMutex.Unlock()
+	fake.DisableStub = nil
+	fake.disableReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// Enable records the call and returns the stubbed results
+func (fake *FakeIntegrationsAPI) Enable(arg1 context.Context, arg2 string) error {
+	fake.enableMutex.Lock()
+	fake.enableArgsForCall = append(fake.enableArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.EnableStub
+	returns := fake.enableReturns
+	fake.enableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1
+}
+
+// EnableCallCount returns the number of calls to Enable
+func (fake *FakeIntegrationsAPI) EnableCallCount() int {
+	fake.enableMutex.RLock()
+	defer fake.enableMutex.RUnlock()
+	return len(fake.enableArgsForCall)
+}
+
+// EnableArgsForCall returns the arguments of the i-th call to Enable
+func (fake *FakeIntegrationsAPI) EnableArgsForCall(i int) (context.Context, string) {
+	fake.enableMutex.RLock()
+	defer fake.enableMutex.RUnlock()
+	call := fake.enableArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// EnableReturns sets the results of calls to Enable without a stub
+func (fake *FakeIntegrationsAPI) EnableReturns(result1 error) {
+	fake.enableMutex.Lock()
+	defer fake.enableMutex.Unlock()
+	fake.EnableStub = nil
+	fake.enableReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// Get records the call and returns the stubbed results
+func (fake *FakeIntegrationsAPI) Get(arg1 context.Context, arg2 string) (*types.Integration, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetStub
+	returns := fake.getReturns
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetCallCount returns the number of calls to Get
+func (fake *FakeIntegrationsAPI) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+// GetArgsForCall returns the arguments of the i-th call to Get
+func (fake *FakeIntegrationsAPI) GetArgsForCall(i int) (context.Context, string) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	call := fake.getArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetReturns sets the results of calls to Get without a stub
+func (fake *FakeIntegrationsAPI) GetReturns(result1 *types.Integration, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 *types.Integration
+		result2 error
+	}{result1, result2}
+}
+
+// GetDetailed records the call and returns the stubbed results
+func (fake *FakeIntegrationsAPI) GetDetailed(arg1 context.Context, arg2 string) (*types.DetailedIntegration, error) {
+	fake.getDetailedMutex.Lock()
+	fake.getDetailedArgsForCall = append(fake.getDetailedArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetDetailedStub
+	returns := fake.getDetailedReturns
+	fake.getDetailedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetDetailedCallCount returns the number of calls to GetDetailed
+func (fake *FakeIntegrationsAPI) GetDetailedCallCount() int {
+	fake.getDetailedMutex.RLock()
+	defer fake.getDetailedMutex.RUnlock()
+	return len(fake.getDetailedArgsForCall)
+}
+
+// GetDetailedArgsForCall returns the arguments of the i-th call to GetDetailed
+func (fake *FakeIntegrationsAPI) GetDetailedArgsForCall(i int) (context.Context, string) {
+	fake.getDetailedMutex.RLock()
+	defer fake.getDetailedMutex.RUnlock()
+	call := fake.getDetailedArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetDetailedReturns sets the results of calls to GetDetailed without a stub
+func (fake *FakeIntegrationsAPI) GetDetailedReturns(result1 *types.DetailedIntegration, result2 error) {
+	fake.getDetailedMutex.Lock()
+	defer fake.getDetailedMutex.Unlock()
+	fake.GetDetailedStub = nil
+	fake.getDetailedReturns = struct {
+		result1 *types.DetailedIntegration
+		result2 error
+	}{result1, result2}
+}
+
+// GetType records the call and returns the stubbed results
+func (fake *FakeIntegrationsAPI) GetType(arg1 context.Context, arg2 string) (*types.IntegrationType, error) {
+	fake.getTypeMutex.Lock()
+	fake.getTypeArgsForCall = append(fake.getTypeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetTypeStub
+	returns := fake.getTypeReturns
+	fake.getTypeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetTypeCallCount returns the number of calls to GetType
+func (fake *FakeIntegrationsAPI) GetTypeCallCount() int {
+	fake.getTypeMutex.RLock()
+	defer fake.getTypeMutex.RUnlock()
+	return len(fake.getTypeArgsForCall)
+}
+
+// GetTypeArgsForCall returns the arguments of the i-th call to GetType
+func (fake *FakeIntegrationsAPI) GetTypeArgsForCall(i int) (context.Context, string) {
+	fake.getTypeMutex.RLock()
+	defer fake.getTypeMutex.RUnlock()
+	call := fake.getTypeArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetTypeReturns sets the results of calls to GetType without a stub
+func (fake *FakeIntegrationsAPI) GetTypeReturns(result1 *types.IntegrationType, result2 error) {
+	fake.getTypeMutex.Lock()
+	defer fake.getTypeMutex.Unlock()
+	fake.GetTypeStub = nil
+	fake.getTypeReturns = struct {
+		result1 *types.IntegrationType
+		result2 error
+	}{result1, result2}
+}
+
+// List records the call and returns the stubbed results
+func (fake *FakeIntegrationsAPI) List(arg1 context.Context) ([]types.Integration, error) {
+	fake.listMutex.Lock()
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListStub
+	returns := fake.listReturns
+	fake.listMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	return returns.result1, returns.result2
+}
+
+// ListCallCount returns the number of calls to List
+func (fake *FakeIntegrationsAPI) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+// ListArgsForCall returns the arguments of the i-th call to List
+func (fake *FakeIntegrationsAPI) ListArgsForCall(i int) context.Context {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	call := fake.listArgsForCall[i]
+	return call.arg1
+}
+
+// ListReturns sets the results of calls to List without a stub
+func (fake *FakeIntegrationsAPI) ListReturns(result1 []types.Integration, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []types.Integration
+		result2 error
+	}{result1, result2}
+}
+
+// ListTypes records the call and returns the stubbed results
+func (fake *FakeIntegrationsAPI) ListTypes(arg1 context.Context) ([]types.IntegrationType, error) {
+	fake.listTypesMutex.Lock()
+	fake.listTypesArgsForCall = append(fake.listTypesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListTypesStub
+	returns := fake.listTypesReturns
+	fake.listTypesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	return returns.result1, returns.result2
+}
+
+// ListTypesCallCount returns the number of calls to ListTypes
+func (fake *FakeIntegrationsAPI) ListTypesCallCount() int {
+	fake.listTypesMutex.RLock()
+	defer fake.listTypesMutex.RUnlock()
+	return len(fake.listTypesArgsForCall)
+}
+
+// ListTypesArgsForCall returns the arguments of the i-th call to ListTypes
+func (fake *FakeIntegrationsAPI) ListTypesArgsForCall(i int) context.Context {
+	fake.listTypesMutex.RLock()
+	defer fake.listTypesMutex.RUnlock()
+	call := fake.listTypesArgsForCall[i]
+	return call.arg1
+}
+
+// ListTypesReturns sets the results of calls to ListTypes without a stub
+func (fake *FakeIntegrationsAPI) ListTypesReturns(result1 []types.IntegrationType, result2 error) {
+	fake.listTypesMutex.Lock()
+	defer fake.listTypesMutex.Unlock()
+	fake.ListTypesStub = nil
+	fake.listTypesReturns = struct {
+		result1 []types.IntegrationType
+		result2 error
+	}{result1, result2}
+}
+
+// Update records the call and returns the stubbed results
+func (fake *FakeIntegrationsAPI) Update(arg1 context.Context, arg2 string, arg3 map[string]interface{}) (*types.Integration, error) {
+	fake.updateMutex.Lock()
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]interface{}
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateStub
+	returns := fake.updateReturns
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1, returns.result2
+}
+
+// UpdateCallCount returns the number of calls to Update
+func (fake *FakeIntegrationsAPI) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+// UpdateArgsForCall returns the arguments of the i-th call to Update
+func (fake *FakeIntegrationsAPI) UpdateArgsForCall(i int) (context.Context, string, map[string]interface{}) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	call := fake.updateArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// UpdateReturns sets the results of calls to Update without a stub
+func (fake *FakeIntegrationsAPI) UpdateReturns(result1 *types.Integration, result2 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 *types.Integration
+		result2 error
+	}{result1, result2}
+}
+
+var _ IntegrationsAPI = new(FakeIntegrationsAPI)
+
+// FakeAlertsAPI is a generated fake of AlertsAPI
+type FakeAlertsAPI struct {
+	AddNoteStub        func(context.Context, string, types.AlertNoteRequest) (*types.AlertNote, error)
+	addNoteMutex       sync.RWMutex
+	addNoteArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.AlertNoteRequest
+	}
+	addNoteReturns struct {
+		result1 *types.AlertNote
+		result2 error
+	}
+	ApplyActionStub        func(context.Context, string, types.AlertActionRequest) error
+	applyActionMutex       sync.RWMutex
+	applyActionArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.AlertActionRequest
+	}
+	applyActionReturns struct {
+		result1 error
+	}
+	EscalationPoliciesStub        func(context.Context) ([]types.EscalationPolicy, error)
+	escalationPoliciesMutex       sync.RWMutex
+	escalationPoliciesArgsForCall []struct {
+		arg1 context.Context
+	}
+	escalationPoliciesReturns struct {
+		result1 []types.EscalationPolicy
+		result2 error
+	}
+	GetStub        func(context.Context, string) (*types.AlertDetails, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getReturns struct {
+		result1 *types.AlertDetails
+		result2 error
+	}
+	GetAttachmentsStub        func(context.Context, string) ([]types.AlertAttachment, error)
+	getAttachmentsMutex       sync.RWMutex
+	getAttachmentsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getAttachmentsReturns struct {
+		result1 []types.AlertAttachment
+		result2 error
+	}
+	GetNotesStub        func(context.Context, string) ([]types.AlertNote, error)
+	getNotesMutex       sync.RWMutex
+	getNotesArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getNotesReturns struct {
+		result1 []types.AlertNote
+		result2 error
+	}
+	SearchStub        func(context.Context, types.AlertSearchParams) (*types.AlertSearchResponse, error)
+	searchMutex       sync.RWMutex
+	searchArgsForCall []struct {
+		arg1 context.Context
+		arg2 types.AlertSearchParams
+	}
+	searchReturns struct {
+		result1 *types.AlertSearchResponse
+		result2 error
+	}
+}
+
+// AddNote records the call and returns the stubbed results
+func (fake *FakeAlertsAPI) AddNote(arg1 context.Context, arg2 string, arg3 types.AlertNoteRequest) (*types.AlertNote, error) {
+	fake.addNoteMutex.Lock()
+	fake.addNoteArgsForCall = append(fake.addNoteArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.AlertNoteRequest
+	}{arg1, arg2, arg3})
+	stub := fake.AddNoteStub
+	returns := fake.addNoteReturns
+	fake.addNoteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1, returns.result2
+}
+
+// AddNoteCallCount returns the number of calls to AddNote
+func (fake *FakeAlertsAPI) AddNoteCallCount() int {
+	fake.addNoteMutex.RLock()
+	defer fake.addNoteMutex.RUnlock()
+	return len(fake.addNoteArgsForCall)
+}
+
+// AddNoteArgsForCall returns the arguments of the i-th call to AddNote
+func (fake *FakeAlertsAPI) AddNoteArgsForCall(i int) (context.Context, string, types.AlertNoteRequest) {
+	fake.addNoteMutex.RLock()
+	defer fake.addNoteMutex.RUnlock()
+	call := fake.addNoteArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// AddNoteReturns sets the results of calls to AddNote without a stub
+func (fake *FakeAlertsAPI) AddNoteReturns(result1 *types.AlertNote, result2 error) {
+	fake.addNoteMutex.Lock()
+	defer fake.addNoteMutex.Unlock()
+	fake.AddNoteStub = nil
+	fake.addNoteReturns = struct {
+		result1 *types.AlertNote
+		result2 error
+	}{result1, result2}
+}
+
+// ApplyAction records the call and returns the stubbed results
+func (fake *FakeAlertsAPI) ApplyAction(arg1 context.Context, arg2 string, arg3 types.AlertActionRequest) error {
+	fake.applyActionMutex.Lock()
+	fake.applyActionArgsForCall = append(fake.applyActionArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.AlertActionRequest
+	}{arg1, arg2, arg3})
+	stub := fake.ApplyActionStub
+	returns := fake.applyActionReturns
+	fake.applyActionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1
+}
+
+// ApplyActionCallCount returns the number of calls to ApplyAction
+func (fake *FakeAlertsAPI) ApplyActionCallCount() int {
+	fake.applyActionMutex.RLock()
+	defer fake.applyActionMutex.RUnlock()
+	return len(fake.applyActionArgsForCall)
+}
+
+// ApplyActionArgsForCall returns the arguments of the i-th call to ApplyAction
+func (fake *FakeAlertsAPI) ApplyActionArgsForCall(i int) (context.Context, string, types.AlertActionRequest) {
+	fake.applyActionMutex.RLock()
+	defer fake.applyActionMutex.RUnlock()
+	call := fake.applyActionArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// ApplyActionReturns sets the results of calls to ApplyAction without a stub
+func (fake *FakeAlertsAPI) ApplyActionReturns(result1 error) {
+	fake.applyActionMutex.Lock()
+	defer fake.applyActionMutex.Unlock()
+	fake.ApplyActionStub = nil
+	fake.applyActionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// EscalationPolicies records the call and returns the stubbed results
+func (fake *FakeAlertsAPI) EscalationPolicies(arg1 context.Context) ([]types.EscalationPolicy, error) {
+	fake.escalationPoliciesMutex.Lock()
+	fake.escalationPoliciesArgsForCall = append(fake.escalationPoliciesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.EscalationPoliciesStub
+	returns := fake.escalationPoliciesReturns
+	fake.escalationPoliciesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	return returns.result1, returns.result2
+}
+
+// EscalationPoliciesCallCount returns the number of calls to EscalationPolicies
+func (fake *FakeAlertsAPI) EscalationPoliciesCallCount() int {
+	fake.escalationPoliciesMutex.RLock()
+	defer fake.escalationPoliciesMutex.RUnlock()
+	return len(fake.escalationPoliciesArgsForCall)
+}
+
+// EscalationPoliciesArgsForCall returns the arguments of the i-th call to EscalationPolicies
+func (fake *FakeAlertsAPI) EscalationPoliciesArgsForCall(i int) context.Context {
+	fake.escalationPoliciesMutex.RLock()
+	defer fake.escalationPoliciesMutex.RUnlock()
+	call := fake.escalationPoliciesArgsForCall[i]
+	return call.arg1
+}
+
+// EscalationPoliciesReturns sets the results of calls to EscalationPolicies without a stub
+func (fake *FakeAlertsAPI) EscalationPoliciesReturns(result1 []types.EscalationPolicy, result2 error) {
+	fake.escalationPoliciesMutex.Lock()
+	defer fake.escalationPoliciesMutex.Unlock()
+	fake.EscalationPoliciesStub = nil
+	fake.escalationPoliciesReturns = struct {
+		result1 []types.EscalationPolicy
+		result2 error
+	}{result1, result2}
+}
+
+// Get records the call and returns the stubbed results
+func (fake *FakeAlertsAPI) Get(arg1 context.Context, arg2 string) (*types.AlertDetails, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetStub
+	returns := fake.getReturns
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetCallCount returns the number of calls to Get
+func (fake *FakeAlertsAPI) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+// GetArgsForCall returns the arguments of the i-th call to Get
+func (fake *FakeAlertsAPI) GetArgsForCall(i int) (context.Context, string) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	call := fake.getArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetReturns sets the results of calls to Get without a stub
+func (fake *FakeAlertsAPI) GetReturns(result1 *types.AlertDetails, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 *types.AlertDetails
+		result2 error
+	}{result1, result2}
+}
+
+// GetAttachments records the call and returns the stubbed results
+func (fake *FakeAlertsAPI) GetAttachments(arg1 context.Context, arg2 string) ([]types.AlertAttachment, error) {
+	fake.getAttachmentsMutex.Lock()
+	fake.getAttachmentsArgsForCall = append(fake.getAttachmentsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetAttachmentsStub
+	returns := fake.getAttachmentsReturns
+	fake.getAttachmentsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetAttachmentsCallCount returns the number of calls to GetAttachments
+func (fake *FakeAlertsAPI) GetAttachmentsCallCount() int {
+	fake.getAttachmentsMutex.RLock()
+	defer fake.getAttachmentsMutex.RUnlock()
+	return len(fake.getAttachmentsArgsForCall)
+}
+
+// GetAttachmentsArgsForCall returns the arguments of the i-th call to GetAttachments
+func (fake *FakeAlertsAPI) GetAttachmentsArgsForCall(i int) (context.Context, string) {
+	fake.getAttachmentsMutex.RLock()
+	defer fake.getAttachmentsMutex.RUnlock()
+	call := fake.getAttachmentsArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetAttachmentsReturns sets the results of calls to GetAttachments without a stub
+func (fake *FakeAlertsAPI) GetAttachmentsReturns(result1 []types.AlertAttachment, result2 error) {
+	fake.getAttachmentsMutex.Lock()
+	defer fake.getAttachmentsMutex.Unlock()
+	fake.GetAttachmentsStub = nil
+	fake.getAttachmentsReturns = struct {
+		result1 []types.AlertAttachment
+		result2 error
+	}{result1, result2}
+}
+
+// GetNotes records the call and returns the stubbed results
+func (fake *FakeAlertsAPI) GetNotes(arg1 context.Context, arg2 string) ([]types.AlertNote, error) {
+	fake.getNotesMutex.Lock()
+	fake.getNotesArgsForCall = append(fake.getNotesArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetNotesStub
+	returns := fake.getNotesReturns
+	fake.getNotesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetNotesCallCount returns the number of calls to GetNotes
+func (fake *FakeAlertsAPI) GetNotesCallCount() int {
+	fake.getNotesMutex.RLock()
+	defer fake.getNotesMutex.RUnlock()
+	return len(fake.getNotesArgsForCall)
+}
+
+// GetNotesArgsForCall returns the arguments of the i-th call to GetNotes
+func (fake *FakeAlertsAPI) GetNotesArgsForCall(i int) (context.Context, string) {
+	fake.getNotesMutex.RLock()
+	defer fake.getNotesMutex.RUnlock()
+	call := fake.getNotesArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetNotesReturns sets the results of calls to GetNotes without a stub
+func (fake *FakeAlertsAPI) GetNotesReturns(result1 []types.AlertNote, result2 error) {
+	fake.getNotesMutex.Lock()
+	defer fake.getNotesMutex.Unlock()
+	fake.GetNotesStub = nil
+	fake.getNotesReturns = struct {
+		result1 []types.AlertNote
+		result2 error
+	}{result1, result2}
+}
+
+// Search records the call and returns the stubbed results
+func (fake *FakeAlertsAPI) Search(arg1 context.Context, arg2 types.AlertSearchParams) (*types.AlertSearchResponse, error) {
+	fake.searchMutex.Lock()
+	fake.searchArgsForCall = append(fake.searchArgsForCall, struct {
+		arg1 context.Context
+		arg2 types.AlertSearchParams
+	}{arg1, arg2})
+	stub := fake.SearchStub
+	returns := fake.searchReturns
+	fake.searchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// SearchCallCount returns the number of calls to Search
+func (fake *FakeAlertsAPI) SearchCallCount() int {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	return len(fake.searchArgsForCall)
+}
+
+// SearchArgsForCall returns the arguments of the i-th call to Search
+func (fake *FakeAlertsAPI) SearchArgsForCall(i int) (context.Context, types.AlertSearchParams) {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	call := fake.searchArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// SearchReturns sets the results of calls to Search without a stub
+func (fake *FakeAlertsAPI) SearchReturns(result1 *types.AlertSearchResponse, result2 error) {
+	fake.searchMutex.Lock()
+	defer fake.searchMutex.Unlock()
+	fake.SearchStub = nil
+	fake.searchReturns = struct {
+		result1 *types.AlertSearchResponse
+		result2 error
+	}{result1, result2}
+}
+
+var _ AlertsAPI = new(FakeAlertsAPI)
+
+// FakeMonitorsAPI is a generated fake of MonitorsAPI
+type FakeMonitorsAPI struct {
+	AssignStub        func(context.Context, string, []string) error
+	assignMutex       sync.RWMutex
+	assignArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+	}
+	assignReturns struct {
+		result1 error
+	}
+	CreateStub        func(context.Context, types.CustomMonitorRequest) (*types.CustomMonitor, error)
+	createMutex       sync.RWMutex
+	createArgsForCall []struct {
+		arg1 context.Context
+		arg2 types.CustomMonitorRequest
+	}
+	createReturns struct {
+		result1 *types.CustomMonitor
+		result2 error
+	}
+	GetStub        func(context.Context, string) (*types.CustomMonitor, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getReturns struct {
+		result1 *types.CustomMonitor
+		result2 error
+	}
+	ListAssignmentsStub        func(context.Context, string) ([]types.MonitorAssignedResource, error)
+	listAssignmentsMutex       sync.RWMutex
+	listAssignmentsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	listAssignmentsReturns struct {
+		result1 []types.MonitorAssignedResource
+		result2 error
+	}
+	SearchStub        func(context.Context, string, int, int) (*types.CustomMonitorSearchResponse, error)
+	searchMutex       sync.RWMutex
+	searchArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+		arg4 int
+	}
+	searchReturns struct {
+		result1 *types.CustomMonitorSearchResponse
+		result2 error
+	}
+	UnassignStub        func(context.Context, string, []string) error
+	unassignMutex       sync.RWMutex
+	unassignArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+	}
+	unassignReturns struct {
+		result1 error
+	}
+	UpdateStub        func(context.Context, string, types.CustomMonitorRequest) (*types.CustomMonitor, error)
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.CustomMonitorRequest
+	}
+	updateReturns struct {
+		result1 *types.CustomMonitor
+		result2 error
+	}
+}
+
+// Assign records the call and returns the stubbed results
+func (fake *FakeMonitorsAPI) Assign(arg1 context.Context, arg2 string, arg3 []string) error {
+	fake.assignMutex.Lock()
+	fake.assignArgsForCall = append(fake.assignArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+	}{arg1, arg2, arg3})
+	stub := fake.AssignStub
+	returns := fake.assignReturns
+	fake.assignMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1
+}
+
+// AssignCallCount returns the number of calls to Assign
+func (fake *FakeMonitorsAPI) AssignCallCount() int {
+	fake.assignMutex.RLock()
+	defer fake.assignMutex.RUnlock()
+	return len(fake.assignArgsForCall)
+}
+
+// AssignArgsForCall returns the arguments of the i-th call to Assign
+func (fake *FakeMonitorsAPI) AssignArgsForCall(i int) (context.Context, string, []string) {
+	fake.assignMutex.RLock()
+	defer fake.assignMutex.RUnlock()
+	call := fake.assignArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// AssignReturns sets the results of calls to Assign without a stub
+func (fake *FakeMonitorsAPI) AssignReturns(result1 error) {
+	fake.assignMutex.Lock()
+	defer fake.assignMutex.Unlock()
+	fake.AssignStub = nil
+	fake.assignReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// Create records the call and returns the stubbed results
+func (fake *FakeMonitorsAPI) Create(arg1 context.Context, arg2 types.CustomMonitorRequest) (*types.CustomMonitor, error) {
+	fake.createMutex.Lock()
+	fake.createArgsForCall = append(fake.createArgsForCall, struct {
+		arg1 context.Context
+		arg2 types.CustomMonitorRequest
+	}{arg1, arg2})
+	stub := fake.CreateStub
+	returns := fake.createReturns
+	fake.createMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// CreateCallCount returns the number of calls to Create
+func (fake *FakeMonitorsAPI) CreateCallCount() int {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	return len(fake.createArgsForCall)
+}
+
+// CreateArgsForCall returns the arguments of the i-th call to Create
+func (fake *FakeMonitorsAPI) CreateArgsForCall(i int) (context.Context, types.CustomMonitorRequest) {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	call := fake.createArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// CreateReturns sets the results of calls to Create without a stub
+func (fake *FakeMonitorsAPI) CreateReturns(result1 *types.CustomMonitor, result2 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = nil
+	fake.createReturns = struct {
+		result1 *types.CustomMonitor
+		result2 error
+	}{result1, result2}
+}
+
+// Get records the call and returns the stubbed results
+func (fake *FakeMonitorsAPI) Get(arg1 context.Context, arg2 string) (*types.CustomMonitor, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetStub
+	returns := fake.getReturns
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetCallCount returns the number of calls to Get
+func (fake *FakeMonitorsAPI) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+// GetArgsForCall returns the arguments of the i-th call to Get
+func (fake *FakeMonitorsAPI) GetArgsForCall(i int) (context.Context, string) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	call := fake.getArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetReturns sets the results of calls to Get without a stub
+func (fake *FakeMonitorsAPI) GetReturns(result1 *types.CustomMonitor, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 *types.CustomMonitor
+		result2 error
+	}{result1, result2}
+}
+
+// ListAssignments records the call and returns the stubbed results
+func (fake *FakeMonitorsAPI) ListAssignments(arg1 context.Context, arg2 string) ([]types.MonitorAssignedResource, error) {
+	fake.listAssignmentsMutex.Lock()
+	fake.listAssignmentsArgsForCall = append(fake.listAssignmentsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListAssignmentsStub
+	returns := fake.listAssignmentsReturns
+	fake.listAssignmentsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// ListAssignmentsCallCount returns the number of calls to ListAssignments
+func (fake *FakeMonitorsAPI) ListAssignmentsCallCount() int {
+	fake.listAssignmentsMutex.RLock()
+	defer fake.listAssignmentsMutex.RUnlock()
+	return len(fake.listAssignmentsArgsForCall)
+}
+
+// ListAssignmentsArgsForCall returns the arguments of the i-th call to ListAssignments
+func (fake *FakeMonitorsAPI) ListAssignmentsArgsForCall(i int) (context.Context, string) {
+	fake.listAssignmentsMutex.RLock()
+	defer fake.listAssignmentsMutex.RUnlock()
+	call := fake.listAssignmentsArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// ListAssignmentsReturns sets the results of calls to ListAssignments without a stub
+func (fake *FakeMonitorsAPI) ListAssignmentsReturns(result1 []types.MonitorAssignedResource, result2 error) {
+	fake.listAssignmentsMutex.Lock()
+	defer fake.listAssignmentsMutex.Unlock()
+	fake.ListAssignmentsStub = nil
+	fake.listAssignmentsReturns = struct {
+		result1 []types.MonitorAssignedResource
+		result2 error
+	}{result1, result2}
+}
+
+// Search records the call and returns the stubbed results
+func (fake *FakeMonitorsAPI) Search(arg1 context.Context, arg2 string, arg3 int, arg4 int) (*types.CustomMonitorSearchResponse, error) {
+	fake.searchMutex.Lock()
+	fake.searchArgsForCall = append(fake.searchArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.SearchStub
+	returns := fake.searchReturns
+	fake.searchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	return returns.result1, returns.result2
+}
+
+// SearchCallCount returns the number of calls to Search
+func (fake *FakeMonitorsAPI) SearchCallCount() int {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	return len(fake.searchArgsForCall)
+}
+
+// SearchArgsForCall returns the arguments of the i-th call to Search
+func (fake *FakeMonitorsAPI) SearchArgsForCall(i int) (context.Context, string, int, int) {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	call := fake.searchArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3, call.arg4
+}
+
+// SearchReturns sets the results of calls to Search without a stub
+func (fake *FakeMonitorsAPI) SearchReturns(result1 *types.CustomMonitorSearchResponse, result2 error) {
+	fake.searchMutex.Lock()
+	defer fake.searchMutex.Unlock()
+	fake.SearchStub = nil
+	fake.searchReturns = struct {
+		result1 *types.CustomMonitorSearchResponse
+		result2 error
+	}{result1, result2}
+}
+
+// Unassign records the call and returns the stubbed results
+func (fake *FakeMonitorsAPI) Unassign(arg1 context.Context, arg2 string, arg3 []string) error {
+	fake.unassignMutex.Lock()
+	fake.unassignArgsForCall = append(fake.unassignArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+	}{arg1, arg2, arg3})
+	stub := fake.UnassignStub
+	returns := fake.unassignReturns
+	fake.unassignMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1
+}
+
+// UnassignCallCount returns the number of calls to Unassign
+func (fake *FakeMonitorsAPI) UnassignCallCount() int {
+	fake.unassignMutex.RLock()
+	defer fake.unassignMutex.RUnlock()
+	return len(fake.unassignArgsForCall)
+}
+
+// UnassignArgsForCall returns the arguments of the i-th call to Unassign
+func (fake *FakeMonitorsAPI) UnassignArgsForCall(i int) (context.Context, string, []string) {
+	fake.unassignMutex.RLock()
+	defer fake.unassignMutex.RUnlock()
+	call := fake.unassignArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// UnassignReturns sets the results of calls to Unassign without a stub
+func (fake *FakeMonitorsAPI) UnassignReturns(result1 error) {
+	fake.unassignMutex.Lock()
+	defer fake.unassignMutex.Unlock()
+	fake.UnassignStub = nil
+	fake.unassignReturns = struct {
+		result1 error
+	}{result1}
+}
+
+// Update records the call and returns the stubbed results
+func (fake *FakeMonitorsAPI) Update(arg1 context.Context, arg2 string, arg3 types.CustomMonitorRequest) (*types.CustomMonitor, error) {
+	fake.updateMutex.Lock()
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 types.CustomMonitorRequest
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateStub
+	returns := fake.updateReturns
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	return returns.result1, returns.result2
+}
+
+// UpdateCallCount returns the number of calls to Update
+func (fake *FakeMonitorsAPI) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+// UpdateArgsForCall returns the arguments of the i-th call to Update
+func (fake *FakeMonitorsAPI) UpdateArgsForCall(i int) (context.Context, string, types.CustomMonitorRequest) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	call := fake.updateArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3
+}
+
+// UpdateReturns sets the results of calls to Update without a stub
+func (fake *FakeMonitorsAPI) UpdateReturns(result1 *types.CustomMonitor, result2 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 *types.CustomMonitor
+		result2 error
+	}{result1, result2}
+}
+
+var _ MonitorsAPI = new(FakeMonitorsAPI)
+
+// FakeClientsAPI is a generated fake of ClientsAPI
+type FakeClientsAPI struct {
+	GetStub        func(context.Context, string) (*types.PartnerClient, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getReturns struct {
+		result1 *types.PartnerClient
+		result2 error
+	}
+	SearchStub        func(context.Context, string, int, int) (*types.PartnerClientSearchResponse, error)
+	searchMutex       sync.RWMutex
+	searchArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+		arg4 int
+	}
+	searchReturns struct {
+		result1 *types.PartnerClientSearchResponse
+		result2 error
+	}
+}
+
+// Get records the call and returns the stubbed results
+func (fake *FakeClientsAPI) Get(arg1 context.Context, arg2 string) (*types.PartnerClient, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetStub
+	returns := fake.getReturns
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetCallCount returns the number of calls to Get
+func (fake *FakeClientsAPI) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+// GetArgsForCall returns the arguments of the i-th call to Get
+func (fake *FakeClientsAPI) GetArgsForCall(i int) (context.Context, string) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	call := fake.getArgsForCall[i]
+	return call.arg1, call.arg2
+}
+
+// GetReturns sets the results of calls to Get without a stub
+func (fake *FakeClientsAPI) GetReturns(result1 *types.PartnerClient, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 *types.PartnerClient
+		result2 error
+	}{result1, result2}
+}
+
+// Search records the call and returns the stubbed results
+func (fake *FakeClientsAPI) Search(arg1 context.Context, arg2 string, arg3 int, arg4 int) (*types.PartnerClientSearchResponse, error) {
+	fake.searchMutex.Lock()
+	fake.searchArgsForCall = append(fake.searchArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.SearchStub
+	returns := fake.searchReturns
+	fake.searchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	return returns.result1, returns.result2
+}
+
+// SearchCallCount returns the number of calls to Search
+func (fake *FakeClientsAPI) SearchCallCount() int {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	return len(fake.searchArgsForCall)
+}
+
+// SearchArgsForCall returns the arguments of the i-th call to Search
+func (fake *FakeClientsAPI) SearchArgsForCall(i int) (context.Context, string, int, int) {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	call := fake.searchArgsForCall[i]
+	return call.arg1, call.arg2, call.arg3, call.arg4
+}
+
+// SearchReturns sets the results of calls to Search without a stub
+func (fake *FakeClientsAPI) SearchReturns(result1 *types.PartnerClientSearchResponse, result2 error) {
+	fake.searchMutex.Lock()
+	defer fake.searchMutex.Unlock()
+	fake.SearchStub = nil
+	fake.searchReturns = struct {
+		result1 *types.PartnerClientSearchResponse
+		result2 error
+	}{result1, result2}
+}
+
+var _ ClientsAPI = new(FakeClientsAPI)
+
+// FakeUsageAPI is a generated fake of UsageAPI
+type FakeUsageAPI struct {
+	GetAPIUsageStub        func(context.Context) (*types.APIUsage, error)
+	getAPIUsageMutex       sync.RWMutex
+	getAPIUsageArgsForCall []struct {
+		arg1 context.Context
+	}
+	getAPIUsageReturns struct {
+		result1 *types.APIUsage
+		result2 error
+	}
+	GetLicenseUsageStub        func(context.Context) ([]types.LicenseUsage, error)
+	getLicenseUsageMutex       sync.RWMutex
+	getLicenseUsageArgsForCall []struct {
+		arg1 context.Context
+	}
+	getLicenseUsageReturns struct {
+		result1 []types.LicenseUsage
+		result2 error
+	}
+}
+
+// GetAPIUsage records the call and returns the stubbed results
+func (fake *FakeUsageAPI) GetAPIUsage(arg1 context.Context) (*types.APIUsage, error) {
+	fake.getAPIUsageMutex.Lock()
+	fake.getAPIUsageArgsForCall = append(fake.getAPIUsageArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetAPIUsageStub
+	returns := fake.getAPIUsageReturns
+	fake.getAPIUsageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetAPIUsageCallCount returns the number of calls to GetAPIUsage
+func (fake *FakeUsageAPI) GetAPIUsageCallCount() int {
+	fake.getAPIUsageMutex.RLock()
+	defer fake.getAPIUsageMutex.RUnlock()
+	return len(fake.getAPIUsageArgsForCall)
+}
+
+// GetAPIUsageArgsForCall returns the arguments of the i-th call to GetAPIUsage
+func (fake *FakeUsageAPI) GetAPIUsageArgsForCall(i int) context.Context {
+	fake.getAPIUsageMutex.RLock()
+	defer fake.getAPIUsageMutex.RUnlock()
+	call := fake.getAPIUsageArgsForCall[i]
+	return call.arg1
+}
+
+// GetAPIUsageReturns sets the results of calls to GetAPIUsage without a stub
+func (fake *FakeUsageAPI) GetAPIUsageReturns(result1 *types.APIUsage, result2 error) {
+	fake.getAPIUsageMutex.Lock()
+	defer fake.getAPIUsageMutex.Unlock()
+	fake.GetAPIUsageStub = nil
+	fake.getAPIUsageReturns = struct {
+		result1 *types.APIUsage
+		result2 error
+	}{result1, result2}
+}
+
+// GetLicenseUsage records the call and returns the stubbed results
+func (fake *FakeUsageAPI) GetLicenseUsage(arg1 context.Context) ([]types.LicenseUsage, error) {
+	fake.getLicenseUsageMutex.Lock()
+	fake.getLicenseUsageArgsForCall = append(fake.getLicenseUsageArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetLicenseUsageStub
+	returns := fake.getLicenseUsageReturns
+	fake.getLicenseUsageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	return returns.result1, returns.result2
+}
+
+// GetLicenseUsageCallCount returns the number of calls to GetLicenseUsage
+func (fake *FakeUsageAPI) GetLicenseUsageCallCount() int {
+	fake.getLicenseUsageMutex.RLock()
+	defer fake.getLicenseUsageMutex.RUnlock()
+	return len(fake.getLicenseUsageArgsForCall)
+}
+
+// GetLicenseUsageArgsForCall returns the arguments of the i-th call to GetLicenseUsage
+func (fake *FakeUsageAPI) GetLicenseUsageArgsForCall(i int) context.Context {
+	fake.getLicenseUsageMutex.RLock()
+	defer fake.getLicenseUsageMutex.RUnlock()
+	call := fake.getLicenseUsageArgsForCall[i]
+	return call.arg1
+}
+
+// GetLicenseUsageReturns sets the results of calls to GetLicenseUsage without a stub
+func (fake *FakeUsageAPI) GetLicenseUsageReturns(result1 []types.LicenseUsage, result2 error) {
+	fake.getLicenseUsageMutex.Lock()
+	defer fake.getLicenseUsageMutex.Unlock()
+	fake.GetLicenseUsageStub = nil
+	fake.getLicenseUsageReturns = struct {
+		result1 []types.LicenseUsage
+		result2 error
+	}{result1, result2}
+}
+
+var _ UsageAPI = new(FakeUsageAPI)
@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// allTenants selects every configured tenant in the tenants argument
+const allTenants = "*"
+
+// maxFanOutConcurrency bounds the tenants queried at once by one call
+const maxFanOutConcurrency = 8
+
+// tenantsArgument is the tool input schema entry for running a read-only
+// action on several tenants at once in multi-tenant mode
+var tenantsArgument = map[string]interface{}{
+	"type":        "array",
+	"items":       map[string]interface{}{"type": "string"},
+	"description": "Run a read-only action on several tenants concurrently in multi-tenant mode: tenant names, or [\"*\"] for every configured tenant. Results are merged with a tenant field on each entry, and tenants whose call failed are listed under failures",
+}
+
+// fanOutActions lists the actions of each tool that accept the tenants
+// argument: read-only actions, and invalidateCache, which changes only the
+// server's cache
+var fanOutActions = map[string]map[string]bool{
+	"resources": {
+		"list": true, "get": true, "getDetailed": true, "getMinimal": true, "search": true,
+		"getResourceTypes": true, "getMetrics": true, "getAvailability": true, "invalidateCache": true,
+	},
+	"alerts": {"search": true, "get": true},
+	"integrations": {
+		"list": true, "get": true, "getDetailed": true, "listTypes": true, "getType": true,
+		"findOrphanedIntegrations": true, "invalidateCache": true,
+	},
+	"monitors": {"list": true, "get": true, "listAssignments": true},
+	"metrics":  {"search": true},
+	"usage":    {"summary": true, "licenses": true, "resourceCounts": true, "apiUsage": true},
+}
+
+// fanOutCaptureKey is the context key of the capture a per-tenant call
+// hands its result to
+type fanOutCaptureKey struct{}
+
+// fanOutCapture receives the result of one per-tenant call before rendering
+type fanOutCapture struct {
+	value    interface{}
+	captured bool
+}
+
+// captureFanOutResult hands a tool result to the fan-out that made the
+// call, reporting whether there was one
+func captureFanOutResult(ctx context.Context, result interface{}) bool {
+	capture, ok := ctx.Value(fanOutCaptureKey{}).(*fanOutCapture)
+	if !ok {
+		return false
+	}
+	capture.value, capture.captured = result, true
+	return true
+}
+
+// withTenantFanOut wraps a tool handler so that a request with a tenants
+// argument runs on each named tenant concurrently, returning the merged
+// results. Requests without it go straight to the handler.
+func withTenantFanOut(tool string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		names := req.GetStringSlice("tenants", nil)
+		if len(names) == 0 {
+			return handler(ctx, req)
+		}
+
+		action := req.GetString("action", "")
+		if !fanOutActions[tool][action] {
+			actions := make([]string, 0, len(fanOutActions[tool]))
+			for name := range fanOutActions[tool] {
+				actions = append(actions, name)
+			}
+			sort.Strings(actions)
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf(
+					"The tenants argument is only supported for read-only %s actions: %s", tool, strings.Join(actions, ", "))}},
+			}, nil
+		}
+		if req.GetString("tenant", "") != "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Use either tenant or tenants, not both"}},
+			}, nil
+		}
+
+		tenants, err := fanOutTenants(names)
+		if err != nil {
+			return tenantErrorResult(err), nil
+		}
+		return respondJSON(ctx, req, runTenantFanOut(ctx, req, action, tenants, handler))
+	}
+}
+
+// fanOutTenants expands the tenants argument to tenant names, "*" meaning
+// every configured tenant
+func fanOutTenants(names []string) ([]string, error) {
+	registry := client.GetTenantRegistry()
+	if registry == nil {
+		return nil, fmt.Errorf("the tenants argument requires multi-tenant mode")
+	}
+
+	seen := make(map[string]bool, len(names))
+	tenants := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == allTenants {
+			return registry.Names(), nil
+		}
+		if name != "" && !seen[name] {
+			seen[name] = true
+			tenants = append(tenants, name)
+		}
+	}
+	return tenants, nil
+}
+
+// runTenantFanOut calls the handler once per tenant and merges the results.
+// A tenant that fails, including an unknown or degraded one, is reported
+// without affecting the others.
+func runTenantFanOut(ctx context.Context, req mcp.CallToolRequest, action string, tenants []string, handler server.ToolHandlerFunc) *types.TenantFanOutResult {
+	values := make([]interface{}, len(tenants))
+	errs := make([]error, len(tenants))
+	slots := make(chan struct{}, maxFanOutConcurrency)
+
+	var wg sync.WaitGroup
+	for i, tenant := range tenants {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			values[i], errs[i] = callTenant(ctx, req, tenant, handler)
+		}()
+	}
+	wg.Wait()
+
+	merged := &types.TenantFanOutResult{Action: action, Tenants: tenants, Results: []interface{}{}}
+	for i, tenant := range tenants {
+		if errs[i] != nil {
+			merged.Failures = append(merged.Failures, types.TenantFailure{Tenant: tenant, Error: errs[i].Error()})
+			continue
+		}
+		entries, err := tenantEntries(tenant, values[i])
+		if err != nil {
+			merged.Failures = append(merged.Failures, types.TenantFailure{Tenant: tenant, Error: err.Error()})
+			continue
+		}
+		merged.Results = append(merged.Results, entries...)
+	}
+	merged.TotalResults = len(merged.Results)
+
+	common.GetLogger().Info("Ran %s on %d tenants: %d results, %d failures",
+		action, len(tenants), merged.TotalResults, len(merged.Failures))
+	return merged
+}
+
+// callTenant runs the handler for one tenant and returns its unrendered
+// result. Transforms apply to the merged results, so they are not passed on.
+func callTenant(ctx context.Context, req mcp.CallToolRequest, tenant string, handler server.ToolHandlerFunc) (interface{}, error) {
+	args := make(map[string]interface{}, len(req.GetArguments()))
+	for key, value := range req.GetArguments() {
+		if key != "tenants" && key != "transform" {
+			args[key] = value
+		}
+	}
+	args["tenant"] = tenant
+	tenantReq := req
+	tenantReq.Params.Arguments = args
+
+	capture := &fanOutCapture{}
+	result, err := handler(context.WithValue(ctx, fanOutCaptureKey{}, capture), tenantReq)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return nil, errors.New(resultText(result))
+	}
+	if capture.captured {
+		return capture.value, nil
+	}
+	return resultText(result), nil
+}
+
+// tenantEntries returns the entries of one tenant's result labelled with the
+// tenant. A list, or the results of a search, gives one entry per item; any
+// other result is a single entry.
+func tenantEntries(tenant string, value interface{}) ([]interface{}, error) {
+	generic, err := normalizeJSON(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	entries, ok := diffEntries(generic)
+	if !ok {
+		entries = []interface{}{generic}
+	}
+
+	labelled := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		if object, ok := entry.(map[string]interface{}); ok {
+			object["tenant"] = tenant
+			labelled = append(labelled, object)
+			continue
+		}
+		labelled = append(labelled, map[string]interface{}{"tenant": tenant, "value": entry})
+	}
+	return labelled, nil
+}
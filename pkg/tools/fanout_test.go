@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// useTestTenants registers the default tenant plus the named ones for the test
+func useTestTenants(t *testing.T, names ...string) {
+	t.Helper()
+	config := &common.Config{OpsRamp: common.OpsRampConfig{TenantURL: "https://primary.example.com", TenantID: "primary"}}
+	for _, name := range names {
+		config.Tenants = append(config.Tenants, common.TenantConfig{
+			Name:          name,
+			OpsRampConfig: common.OpsRampConfig{TenantURL: "https://" + name + ".example.com", TenantID: name},
+		})
+	}
+	client.SetTenantRegistry(client.NewTenantRegistry(config))
+	t.Cleanup(func() { client.SetTenantRegistry(nil) })
+}
+
+// downGatewaysHandler reports one down gateway per tenant, except apac whose
+// calls fail
+func downGatewaysHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tenant := req.GetString("tenant", "")
+	if tenant == "apac" {
+		return tenantErrorResult(&client.TenantUnavailableError{Tenant: tenant, LastError: "connection refused"}), nil
+	}
+	return respondJSON(ctx, req, types.ResourceSearchResponse{
+		TotalResults: 1,
+		Results:      []types.Resource{{ID: tenant + "-gw", Type: "Gateway", Status: "down"}},
+	})
+}
+
+func TestTenantFanOut(t *testing.T) {
+	useTestTenants(t, "emea", "apac")
+	handler := withTenantFanOut("resources", downGatewaysHandler)
+
+	result, err := handler(context.Background(), createTestRequest(map[string]interface{}{
+		"action":  "search",
+		"tenants": []interface{}{"*"},
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("Fan-out failed: %v %s", err, resultText(result))
+	}
+	var merged types.TenantFanOutResult
+	if err := json.Unmarshal([]byte(resultText(result)), &merged); err != nil {
+		t.Fatalf("Failed to decode merged result: %v", err)
+	}
+
+	if strings.Join(merged.Tenants, ",") != "apac,default,emea" || merged.TotalResults != 2 {
+		t.Fatalf("Unexpected merged result %+v", merged)
+	}
+	for i, want := range []string{"default", "emea"} {
+		entry := merged.Results[i].(map[string]interface{})
+		if entry["tenant"] != want || entry["id"] != want+"-gw" {
+			t.Errorf("Expected the %s gateway labelled with its tenant, got %v", want, entry)
+		}
+	}
+	if len(merged.Failures) != 1 || merged.Failures[0].Tenant != "apac" || !strings.Contains(merged.Failures[0].Error, "connection refused") {
+		t.Errorf("Expected apac to be reported as failed, got %+v", merged.Failures)
+	}
+
+	// A transform applies to the merged results
+	result, _ = handler(context.Background(), createTestRequest(map[string]interface{}{
+		"action":    "search",
+		"tenants":   []interface{}{"emea", "emea"},
+		"transform": ".results[].tenant",
+	}))
+	if text := resultText(result); result.IsError || strings.Count(text, "emea") != 1 {
+		t.Errorf("Expected one emea entry, got %s", text)
+	}
+}
+
+func TestTenantFanOutRefusals(t *testing.T) {
+	handler := withTenantFanOut("resources", downGatewaysHandler)
+	for _, args := range []map[string]interface{}{
+		{"action": "search", "tenants": []interface{}{"*"}},
+		{"action": "delete", "id": "r1", "tenants": []interface{}{"*"}},
+		{"action": "search", "tenant": "emea", "tenants": []interface{}{"*"}},
+	} {
+		if args["action"] == "delete" {
+			useTestTenants(t, "emea")
+		}
+		result, _ := handler(context.Background(), createTestRequest(args))
+		if !result.IsError {
+			t.Errorf("Expected %v to be refused", args)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// fieldPolicyFor returns the configured field policy of a tool: the tool's
+// own allowlist, or the "*" one when it has none, and the denylists of both.
+// It returns nil when the tool's results are not restricted.
+func fieldPolicyFor(tool string) *common.FieldPolicy {
+	policies := getResponseDefaults().Fields
+	global, hasGlobal := policies["*"]
+	own, hasOwn := policies[tool]
+	if !hasGlobal && !hasOwn {
+		return nil
+	}
+
+	policy := &common.FieldPolicy{Allow: own.Allow}
+	if len(policy.Allow) == 0 {
+		policy.Allow = global.Allow
+	}
+	policy.Deny = append(append(policy.Deny, global.Deny...), own.Deny...)
+	if len(policy.Allow) == 0 && len(policy.Deny) == 0 {
+		return nil
+	}
+	return policy
+}
+
+// applyFieldPolicy strips the fields the policy does not permit from a tool
+// result. The result is round-tripped through JSON so the policy sees the
+// field names the client would. Values that are not objects or arrays have
+// no fields and are returned unchanged.
+func applyFieldPolicy(result interface{}, policy *common.FieldPolicy) (interface{}, error) {
+	if policy == nil || result == nil {
+		return result, nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+
+	filtered, kept := filterFields(generic, policy, len(policy.Allow) == 0)
+	if !kept {
+		// Nothing was allowed, but the result keeps its shape
+		switch generic.(type) {
+		case map[string]interface{}:
+			return map[string]interface{}{}, nil
+		case []interface{}:
+			return []interface{}{}, nil
+		}
+	}
+	return filtered, nil
+}
+
+// filterFields removes denied fields at any depth and, below fields that are
+// not allowed, every value that is not itself in an allowed field. allowed
+// reports whether the value sits under an allowed field. An object or array
+// is kept when anything inside it is.
+func filterFields(value interface{}, policy *common.FieldPolicy, allowed bool) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			if matchesField(policy.Deny, key) {
+				continue
+			}
+			if filtered, kept := filterFields(child, policy, allowed || matchesField(policy.Allow, key)); kept {
+				out[key] = filtered
+			}
+		}
+		return out, allowed || len(out) > 0
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, child := range v {
+			if filtered, kept := filterFields(child, policy, allowed); kept {
+				out = append(out, filtered)
+			}
+		}
+		return out, allowed || len(out) > 0
+	default:
+		return value, allowed
+	}
+}
+
+// matchesField reports whether a field name matches any of the patterns,
+// ignoring case
+func matchesField(patterns []string, field string) bool {
+	field = strings.ToLower(field)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), field); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// useFieldPolicies configures response field policies for the test
+func useFieldPolicies(t *testing.T, policies map[string]common.FieldPolicy) {
+	t.Helper()
+	saved := getResponseDefaults()
+	responseDefaults.Fields = policies
+	t.Cleanup(func() { responseDefaults = saved })
+}
+
+func TestApplyFieldPolicyDeny(t *testing.T) {
+	result := types.ResourceSearchResponse{
+		TotalResults: 1,
+		Results: []types.Resource{{
+			ID:           "r1",
+			HostName:     "web-01",
+			IPAddress:    "10.0.1.5",
+			SerialNumber: "SN-123",
+		}},
+	}
+
+	filtered, err := applyFieldPolicy(result, &common.FieldPolicy{Deny: []string{"IP*", "serialNumber"}})
+	if err != nil {
+		t.Fatalf("applyFieldPolicy failed: %v", err)
+	}
+	resource := filtered.(map[string]interface{})["results"].([]interface{})[0].(map[string]interface{})
+	if _, ok := resource["ipAddress"]; ok {
+		t.Errorf("Expected ipAddress to be stripped, got %v", resource)
+	}
+	if _, ok := resource["serialNumber"]; ok {
+		t.Errorf("Expected serialNumber to be stripped, got %v", resource)
+	}
+	if resource["hostName"] != "web-01" {
+		t.Errorf("Expected other fields to be kept, got %v", resource)
+	}
+}
+
+func TestApplyFieldPolicyAllow(t *testing.T) {
+	result := map[string]interface{}{
+		"totalResults": 2,
+		"results": []interface{}{
+			map[string]interface{}{"id": "r1", "ipAddress": "10.0.1.5", "tags": []interface{}{map[string]interface{}{"name": "env"}}},
+			map[string]interface{}{"ipAddress": "10.0.1.6"},
+		},
+		"agent": map[string]interface{}{"id": "a1", "version": "12", "secret": "s"},
+	}
+
+	filtered, err := applyFieldPolicy(result, &common.FieldPolicy{Allow: []string{"id", "totalResults", "agent"}, Deny: []string{"secret"}})
+	if err != nil {
+		t.Fatalf("applyFieldPolicy failed: %v", err)
+	}
+	out := filtered.(map[string]interface{})
+	results := out["results"].([]interface{})
+	if len(results) != 1 || len(results[0].(map[string]interface{})) != 1 || results[0].(map[string]interface{})["id"] != "r1" {
+		t.Errorf("Expected only the id of r1 to be kept, got %v", results)
+	}
+	// An allowed object keeps its contents, except denied fields
+	agent := out["agent"].(map[string]interface{})
+	if agent["version"] != "12" || agent["secret"] != nil || out["totalResults"] != float64(2) {
+		t.Errorf("Unexpected result %v", out)
+	}
+
+	if filtered, _ := applyFieldPolicy(result, &common.FieldPolicy{Allow: []string{"missing"}}); len(filtered.(map[string]interface{})) != 0 {
+		t.Errorf("Expected an empty object, got %v", filtered)
+	}
+	if filtered, _ := applyFieldPolicy("OK", &common.FieldPolicy{Allow: []string{"id"}}); filtered != "OK" {
+		t.Errorf("Expected scalar results to pass through, got %v", filtered)
+	}
+}
+
+func TestFieldPolicyFor(t *testing.T) {
+	useFieldPolicies(t, map[string]common.FieldPolicy{
+		"*":         {Allow: []string{"id"}, Deny: []string{"ipAddress"}},
+		"resources": {Allow: []string{"id", "hostName"}, Deny: []string{"serialNumber"}},
+	})
+
+	policy := fieldPolicyFor("resources")
+	if strings.Join(policy.Allow, ",") != "id,hostName" || strings.Join(policy.Deny, ",") != "ipAddress,serialNumber" {
+		t.Errorf("Unexpected resources policy %+v", policy)
+	}
+	if policy := fieldPolicyFor("alerts"); strings.Join(policy.Allow, ",") != "id" || strings.Join(policy.Deny, ",") != "ipAddress" {
+		t.Errorf("Expected the global policy for alerts, got %+v", policy)
+	}
+
+	useFieldPolicies(t, nil)
+	if policy := fieldPolicyFor("resources"); policy != nil {
+		t.Errorf("Expected no policy, got %+v", policy)
+	}
+}
+
+func TestRespondJSONStripsDeniedFields(t *testing.T) {
+	useFieldPolicies(t, map[string]common.FieldPolicy{"resources": {Deny: []string{"ipAddress"}}})
+	resource := types.Resource{ID: "r1", IPAddress: "10.0.1.5"}
+
+	// A transform cannot reach a stripped field
+	req := createTestRequest(map[string]interface{}{"transform": ".ipAddress"})
+	req.Params.Name = "resources"
+	result, err := respondJSON(context.Background(), req, resource)
+	if err != nil {
+		t.Fatalf("respondJSON failed: %v", err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; strings.Contains(text, "10.0.1.5") {
+		t.Errorf("Expected the IP address to be stripped, got %s", text)
+	}
+
+	req.Params.Name = "alerts"
+	result, _ = respondJSON(context.Background(), req, resource)
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "10.0.1.5") {
+		t.Errorf("Expected other tools to be unaffected, got %s", text)
+	}
+}
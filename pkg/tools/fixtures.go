@@ -0,0 +1,311 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+	"gopkg.in/yaml.v2"
+)
+
+// Fixture files, each holding a list of entities and looked up in the
+// fixtures directory with a .yaml, .yml or .json extension
+const (
+	fixtureResources    = "resources"
+	fixtureDeviceGroups = "device_groups"
+	fixtureIntegrations = "integrations"
+	fixtureAlerts       = "alerts"
+)
+
+// fixtureExtensions are the extensions a fixture file may have, in the order
+// they are looked up
+var fixtureExtensions = []string{".yaml", ".yml", ".json"}
+
+// FixtureDeviceGroup is a device group fixture. Members lists the IDs of its
+// resources; its resourceCount is derived from them.
+type FixtureDeviceGroup struct {
+	types.DeviceGroup
+	Members []string `json:"members,omitempty"`
+}
+
+// FixtureIntegration is an integration fixture. Resources lists the IDs of
+// the resources it discovered.
+type FixtureIntegration struct {
+	types.Integration
+	Resources []string `json:"resources,omitempty"`
+}
+
+// FixtureAlert is an alert fixture with its notes. Its resource only needs
+// an id; the name and type are filled from the resource fixture.
+type FixtureAlert struct {
+	types.AlertDetails
+	Notes []types.AlertNote `json:"notes,omitempty"`
+}
+
+// Fixtures is a mock dataset: resources, the device groups and integrations
+// they belong to, and the alerts raised on them. It is held in memory and
+// shared by the tools on the mock backend, so changes made through one tool
+// are seen by the others until the server restarts.
+type Fixtures struct {
+	mu           sync.Mutex
+	resources    []types.Resource
+	deviceGroups []FixtureDeviceGroup
+	integrations []FixtureIntegration
+	alerts       []FixtureAlert
+	created      int
+}
+
+// LoadFixtures reads the fixture files in dir and checks that every
+// reference between them resolves. Each file is optional, but dir must hold
+// at least one.
+func LoadFixtures(dir string) (*Fixtures, error) {
+	f := &Fixtures{}
+	found := 0
+	for _, file := range []struct {
+		name   string
+		target interface{}
+	}{
+		{fixtureResources, &f.resources},
+		{fixtureDeviceGroups, &f.deviceGroups},
+		{fixtureIntegrations, &f.integrations},
+		{fixtureAlerts, &f.alerts},
+	} {
+		ok, err := readFixtureFile(dir, file.name, file.target)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			found++
+		}
+	}
+	if found == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s: expected %s, %s, %s or %s files", dir,
+			fixtureResources, fixtureDeviceGroups, fixtureIntegrations, fixtureAlerts)
+	}
+
+	if err := f.link(); err != nil {
+		return nil, fmt.Errorf("invalid fixtures in %s: %w", dir, err)
+	}
+	return f, nil
+}
+
+// readFixtureFile decodes the fixture file of a name into target, reporting
+// whether there is one. YAML is decoded with the JSON field names of the
+// types, so both formats share one shape.
+func readFixtureFile(dir, name string, target interface{}) (bool, error) {
+	for _, ext := range fixtureExtensions {
+		path := filepath.Join(dir, name+ext)
+		data, err := os.ReadFile(filepath.Clean(path))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read fixture %s: %w", path, err)
+		}
+
+		if ext != ".json" {
+			var generic interface{}
+			if err := yaml.Unmarshal(data, &generic); err != nil {
+				return false, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+			}
+			if data, err = json.Marshal(normalizeYAML(generic)); err != nil {
+				return false, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+			}
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			return false, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// link checks the IDs and references of the fixtures and fills the fields
+// derived from them. Every problem is reported, not just the first.
+func (f *Fixtures) link() error {
+	var errs []error
+	resources := make(map[string]types.Resource, len(f.resources))
+	for _, resource := range f.resources {
+		if resource.ID == "" {
+			errs = append(errs, fmt.Errorf("resource %q has no id", resource.Name))
+		} else if _, ok := resources[resource.ID]; ok {
+			errs = append(errs, fmt.Errorf("resource %s is defined twice", resource.ID))
+		}
+		resources[resource.ID] = resource
+	}
+
+	groups := make(map[string]bool, len(f.deviceGroups))
+	for _, group := range f.deviceGroups {
+		if group.ID == "" {
+			errs = append(errs, fmt.Errorf("device group %q has no id", group.Name))
+		} else if groups[group.ID] {
+			errs = append(errs, fmt.Errorf("device group %s is defined twice", group.ID))
+		}
+		groups[group.ID] = true
+	}
+	for i, group := range f.deviceGroups {
+		if group.ParentID != "" && !groups[group.ParentID] {
+			errs = append(errs, fmt.Errorf("device group %s: unknown parent device group %s", group.ID, group.ParentID))
+		}
+		for _, member := range group.Members {
+			if _, ok := resources[member]; !ok {
+				errs = append(errs, fmt.Errorf("device group %s: unknown member resource %s", group.ID, member))
+			}
+		}
+		f.deviceGroups[i].ResourceCount = len(group.Members)
+	}
+
+	integrations := make(map[string]bool, len(f.integrations))
+	for _, integration := range f.integrations {
+		if integration.ID == "" {
+			errs = append(errs, fmt.Errorf("integration %q has no id", integration.Name))
+		} else if integrations[integration.ID] {
+			errs = append(errs, fmt.Errorf("integration %s is defined twice", integration.ID))
+		}
+		integrations[integration.ID] = true
+		for _, id := range integration.Resources {
+			if _, ok := resources[id]; !ok {
+				errs = append(errs, fmt.Errorf("integration %s: unknown resource %s", integration.ID, id))
+			}
+		}
+	}
+
+	alerts := make(map[string]bool, len(f.alerts))
+	for i, alert := range f.alerts {
+		if alert.ID == "" {
+			errs = append(errs, fmt.Errorf("alert %q has no id", alert.Subject))
+		} else if alerts[alert.ID] {
+			errs = append(errs, fmt.Errorf("alert %s is defined twice", alert.ID))
+		}
+		alerts[alert.ID] = true
+		if alert.Resource == nil {
+			continue
+		}
+		resource, ok := resources[alert.Resource.ID]
+		if !ok {
+			errs = append(errs, fmt.Errorf("alert %s: unknown resource %s", alert.ID, alert.Resource.ID))
+			continue
+		}
+		if alert.Resource.Name == "" {
+			f.alerts[i].Resource.Name = resource.Name
+		}
+		if alert.Resource.ResourceType == "" {
+			f.alerts[i].Resource.ResourceType = resource.ResourceType
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newID returns an ID for an entity created through a mock tool
+func (f *Fixtures) newID(prefix string) string {
+	f.created++
+	return fmt.Sprintf("%s-fixture-%d", prefix, f.created)
+}
+
+// loadedFixtures holds the fixtures of each directory, loaded on first use
+var loadedFixtures sync.Map
+
+// sharedFixtures returns the fixtures in dir, loading them on first use so
+// that every mock tool works on the same dataset
+func sharedFixtures(dir string) (*Fixtures, error) {
+	dir = filepath.Clean(dir)
+	if f, ok := loadedFixtures.Load(dir); ok {
+		return f.(*Fixtures), nil
+	}
+	f, err := LoadFixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+	shared, _ := loadedFixtures.LoadOrStore(dir, f)
+	return shared.(*Fixtures), nil
+}
+
+// mockFixtures returns the fixtures the mock backend serves, or nil when
+// mock.fixtures is not set and the built-in sample data is served instead
+func mockFixtures(config *common.Config) (*Fixtures, error) {
+	if config.Mock.Fixtures == "" {
+		return nil, nil
+	}
+	return sharedFixtures(config.Mock.Fixtures)
+}
+
+// fixtureQueryIgnored are the query fields not matched against fixtures:
+// the time range of alert searches, so it does not hide fixed demo data
+var fixtureQueryIgnored = map[string]bool{"startdate": true, "enddate": true}
+
+// matchesFixtureQuery reports whether an entity matches an OpsRamp query
+// string: terms joined by "+", each field:value with comma-separated
+// alternatives, compared without case. A field is looked up among the
+// entity's fields and then its properties; an entity has no match for a
+// field it lacks.
+func matchesFixtureQuery(entity interface{}, query string) bool {
+	if strings.TrimSpace(query) == "" {
+		return true
+	}
+	generic, err := normalizeJSON(entity)
+	if err != nil {
+		return false
+	}
+	fields, _ := generic.(map[string]interface{})
+	properties, _ := fields["properties"].(map[string]interface{})
+
+	for _, term := range strings.Split(query, "+") {
+		key, want, ok := strings.Cut(strings.TrimSpace(term), ":")
+		if !ok || fixtureQueryIgnored[strings.ToLower(key)] {
+			continue
+		}
+		value, ok := lookupFold(fields, key)
+		if !ok {
+			if value, ok = lookupFold(properties, key); !ok {
+				return false
+			}
+		}
+		matched := false
+		for _, alternative := range strings.Split(want, ",") {
+			if strings.EqualFold(fmt.Sprint(value), strings.TrimSpace(alternative)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupFold returns the value of a map key, matched without case
+func lookupFold(fields map[string]interface{}, key string) (interface{}, bool) {
+	if value, ok := fields[key]; ok {
+		return value, true
+	}
+	for name, value := range fields {
+		if strings.EqualFold(name, key) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// fixturePage returns a page of items and the number of pages. Pages are
+// numbered from 1; a page size below 1 returns every item.
+func fixturePage[T any](items []T, pageNo, pageSize int) ([]T, int) {
+	if pageSize < 1 {
+		return items, 1
+	}
+	if pageNo < 1 {
+		pageNo = 1
+	}
+	pages := (len(items) + pageSize - 1) / pageSize
+	start := (pageNo - 1) * pageSize
+	if start >= len(items) {
+		return []T{}, pages
+	}
+	return items[start:min(start+pageSize, len(items))], pages
+}
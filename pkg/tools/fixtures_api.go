@@ -0,0 +1,756 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/httperr"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// fixtureNotFound is the error for an entity missing from the fixtures,
+// shaped like the 404 the OpsRamp API returns
+func fixtureNotFound(kind, id string) error {
+	return &httperr.StatusError{StatusCode: http.StatusNotFound, Body: fmt.Sprintf("%s %s not found in fixtures", kind, id)}
+}
+
+// fixtureTime is the timestamp recorded on entities created or changed
+// through a mock tool
+func fixtureTime() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// mergeFixture applies a partial update given as JSON fields to an entity
+func mergeFixture(entity interface{}, updates map[string]interface{}) error {
+	data, err := json.Marshal(updates)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, entity)
+}
+
+// resourceSearchFilters are the resource search parameters matched exactly,
+// without case, against the fixtures
+var resourceSearchFilters = []struct {
+	param func(p types.ResourceSearchParams) string
+	value func(r types.Resource) string
+}{
+	{func(p types.ResourceSearchParams) string { return p.ID }, func(r types.Resource) string { return r.ID }},
+	{func(p types.ResourceSearchParams) string { return p.HostName }, func(r types.Resource) string { return r.HostName }},
+	{func(p types.ResourceSearchParams) string { return p.DNSName }, func(r types.Resource) string { return r.DNSName }},
+	{func(p types.ResourceSearchParams) string { return p.ResourceName }, func(r types.Resource) string { return r.ResourceName }},
+	{func(p types.ResourceSearchParams) string { return p.AliasName }, func(r types.Resource) string { return r.AliasName }},
+	{func(p types.ResourceSearchParams) string { return p.SerialNumber }, func(r types.Resource) string { return r.SerialNumber }},
+	{func(p types.ResourceSearchParams) string { return p.IPAddress }, func(r types.Resource) string { return r.IPAddress }},
+	{func(p types.ResourceSearchParams) string { return p.SystemUID }, func(r types.Resource) string { return r.SystemUID }},
+	{func(p types.ResourceSearchParams) string { return p.ProviderUID }, func(r types.Resource) string { return r.ProviderUID }},
+	{func(p types.ResourceSearchParams) string { return p.ProviderType }, func(r types.Resource) string { return r.ProviderType }},
+	{func(p types.ResourceSearchParams) string { return p.State }, func(r types.Resource) string { return r.State }},
+	{func(p types.ResourceSearchParams) string { return p.Type }, func(r types.Resource) string { return r.Type }},
+	{func(p types.ResourceSearchParams) string { return p.ResourceType }, func(r types.Resource) string { return r.ResourceType }},
+	{func(p types.ResourceSearchParams) string { return p.Category }, func(r types.Resource) string { return r.Category }},
+	{func(p types.ResourceSearchParams) string { return p.Make }, func(r types.Resource) string { return r.Make }},
+	{func(p types.ResourceSearchParams) string { return p.Model }, func(r types.Resource) string { return r.Model }},
+}
+
+// FixtureResourcesAPI implements ResourcesAPI, DeviceGroupProvider and
+// GroupCatalogProvider over fixtures for the mock backend
+type FixtureResourcesAPI struct {
+	fixtures *Fixtures
+}
+
+// NewFixtureResourcesAPI creates a resources API serving the fixtures
+func NewFixtureResourcesAPI(fixtures *Fixtures) *FixtureResourcesAPI {
+	return &FixtureResourcesAPI{fixtures: fixtures}
+}
+
+// resource returns the index of a resource; the caller holds the lock
+func (a *FixtureResourcesAPI) resource(id string) (int, error) {
+	i := slices.IndexFunc(a.fixtures.resources, func(r types.Resource) bool { return r.ID == id })
+	if i < 0 {
+		return -1, fixtureNotFound("resource", id)
+	}
+	return i, nil
+}
+
+// deviceGroup returns the index of a device group, looked up by ID or name;
+// the caller holds the lock
+func (a *FixtureResourcesAPI) deviceGroup(id string) (int, error) {
+	i := slices.IndexFunc(a.fixtures.deviceGroups, func(g FixtureDeviceGroup) bool {
+		return g.ID == id || strings.EqualFold(g.Name, id)
+	})
+	if i < 0 {
+		return -1, fixtureNotFound("device group", id)
+	}
+	return i, nil
+}
+
+// checkResources fails unless every resource exists; the caller holds the lock
+func (a *FixtureResourcesAPI) checkResources(ids []string) error {
+	for _, id := range ids {
+		if _, err := a.resource(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Search returns the resources matching the search parameters, including
+// the members of a device group and field:value terms of the query string
+func (a *FixtureResourcesAPI) Search(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+
+	var members []string
+	if params.DeviceGroup != "" {
+		i, err := a.deviceGroup(params.DeviceGroup)
+		if err != nil {
+			return nil, err
+		}
+		members = a.fixtures.deviceGroups[i].Members
+	}
+
+	matches := []types.Resource{}
+	for _, resource := range a.fixtures.resources {
+		if params.DeviceGroup != "" && !slices.Contains(members, resource.ID) {
+			continue
+		}
+		matched := matchesFixtureQuery(resource, params.QueryString)
+		for _, filter := range resourceSearchFilters {
+			if want := filter.param(params); matched && want != "" {
+				matched = strings.EqualFold(filter.value(resource), want)
+			}
+		}
+		if matched {
+			matches = append(matches, resource)
+		}
+	}
+
+	page, pages := fixturePage(matches, params.PageNo, params.PageSize)
+	return &types.ResourceSearchResponse{
+		Results:      page,
+		TotalResults: len(matches),
+		PageNo:       max(params.PageNo, 1),
+		PageSize:     params.PageSize,
+		TotalPages:   pages,
+		NextPage:     max(params.PageNo, 1) < pages,
+	}, nil
+}
+
+// Get returns a resource
+func (a *FixtureResourcesAPI) Get(ctx context.Context, id string) (*types.Resource, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.resource(id)
+	if err != nil {
+		return nil, err
+	}
+	resource := a.fixtures.resources[i]
+	return &resource, nil
+}
+
+// GetDetailed returns a resource; fixtures carry no further detail
+func (a *FixtureResourcesAPI) GetDetailed(ctx context.Context, id string) (*types.DetailedResource, error) {
+	resource, err := a.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &types.DetailedResource{Resource: *resource}, nil
+}
+
+// Create adds a resource to the fixtures
+func (a *FixtureResourcesAPI) Create(ctx context.Context, request types.ResourceCreateRequest) (*types.Resource, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	now := fixtureTime()
+	resource := types.Resource{
+		ID:           a.fixtures.newID("res"),
+		Name:         request.HostName,
+		HostName:     request.HostName,
+		IPAddress:    request.IPAddress,
+		DNSName:      request.DNSName,
+		AliasName:    request.AliasName,
+		SerialNumber: request.SerialNumber,
+		Make:         request.Make,
+		Model:        request.Model,
+		Description:  request.Description,
+		OS:           request.OS,
+		Category:     request.Category,
+		ResourceType: request.ResourceType,
+		Tags:         request.Tags,
+		Properties:   request.Properties,
+		State:        "active",
+		CreatedDate:  now,
+		UpdatedDate:  now,
+	}
+	if resource.Name == "" {
+		resource.Name = request.AliasName
+	}
+	a.fixtures.resources = append(a.fixtures.resources, resource)
+	return &resource, nil
+}
+
+// Update changes the fields of a resource set in the request
+func (a *FixtureResourcesAPI) Update(ctx context.Context, id string, request types.ResourceUpdateRequest) (*types.Resource, error) {
+	updates, err := normalizeJSON(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.update(id, updates.(map[string]interface{})); err != nil {
+		return nil, err
+	}
+	return a.Get(ctx, id)
+}
+
+// update merges fields into a resource, ignoring empty ones
+func (a *FixtureResourcesAPI) update(id string, updates map[string]interface{}) error {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.resource(id)
+	if err != nil {
+		return err
+	}
+	fields := make(map[string]interface{}, len(updates))
+	for key, value := range updates {
+		if value != nil && value != "" {
+			fields[key] = value
+		}
+	}
+	resource := a.fixtures.resources[i]
+	if err := mergeFixture(&resource, fields); err != nil {
+		return fmt.Errorf("invalid update of resource %s: %w", id, err)
+	}
+	resource.ID = id
+	resource.UpdatedDate = fixtureTime()
+	a.fixtures.resources[i] = resource
+	return nil
+}
+
+// Delete removes a resource along with its device group memberships and
+// integration attributions
+func (a *FixtureResourcesAPI) Delete(ctx context.Context, id string) error {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.resource(id)
+	if err != nil {
+		return err
+	}
+	a.fixtures.resources = slices.Delete(a.fixtures.resources, i, i+1)
+	for j := range a.fixtures.deviceGroups {
+		group := &a.fixtures.deviceGroups[j]
+		group.Members = slices.DeleteFunc(group.Members, func(member string) bool { return member == id })
+		group.ResourceCount = len(group.Members)
+	}
+	for j := range a.fixtures.integrations {
+		integration := &a.fixtures.integrations[j]
+		integration.Resources = slices.DeleteFunc(integration.Resources, func(resource string) bool { return resource == id })
+	}
+	return nil
+}
+
+// BulkUpdate applies the same updates to each resource
+func (a *FixtureResourcesAPI) BulkUpdate(ctx context.Context, request types.ResourceBulkUpdateRequest) error {
+	for _, id := range request.ResourceIDs {
+		if err := a.update(id, request.Updates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BulkDelete removes each resource
+func (a *FixtureResourcesAPI) BulkDelete(ctx context.Context, request types.ResourceBulkDeleteRequest) error {
+	for _, id := range request.ResourceIDs {
+		if err := a.Delete(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetResourceTypes lists the resource types of the fixtures
+func (a *FixtureResourcesAPI) GetResourceTypes(ctx context.Context) ([]types.ResourceTypeInfo, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	var names []string
+	for _, resource := range a.fixtures.resources {
+		if resource.ResourceType != "" && !slices.Contains(names, resource.ResourceType) {
+			names = append(names, resource.ResourceType)
+		}
+	}
+	sort.Strings(names)
+	resourceTypes := make([]types.ResourceTypeInfo, len(names))
+	for i, name := range names {
+		resourceTypes[i] = types.ResourceTypeInfo{ID: name, Name: name}
+	}
+	return resourceTypes, nil
+}
+
+// ChangeState sets the state of a resource
+func (a *FixtureResourcesAPI) ChangeState(ctx context.Context, id string, request types.ResourceStateChangeRequest) error {
+	return a.update(id, map[string]interface{}{"state": request.State})
+}
+
+// GetMetrics returns no data points; fixtures carry no metrics
+func (a *FixtureResourcesAPI) GetMetrics(ctx context.Context, id string, request types.ResourceMetricsRequest) (*types.ResourceMetricsResponse, error) {
+	if _, err := a.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return &types.ResourceMetricsResponse{ResourceID: id, Metrics: []types.ResourceMetricDataPoint{}}, nil
+}
+
+// GetTags returns the tags of a resource
+func (a *FixtureResourcesAPI) GetTags(ctx context.Context, id string) ([]types.Tag, error) {
+	resource, err := a.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return resource.Tags, nil
+}
+
+// UpdateTags replaces the tags of a resource
+func (a *FixtureResourcesAPI) UpdateTags(ctx context.Context, id string, tags []types.Tag) error {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.resource(id)
+	if err != nil {
+		return err
+	}
+	a.fixtures.resources[i].Tags = tags
+	a.fixtures.resources[i].UpdatedDate = fixtureTime()
+	return nil
+}
+
+// GetMinimal returns the summary fields of a resource
+func (a *FixtureResourcesAPI) GetMinimal(ctx context.Context, id string) (*types.ResourceMinimal, error) {
+	resource, err := a.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ResourceMinimal{
+		ID:           resource.ID,
+		HostName:     resource.HostName,
+		IPAddress:    resource.IPAddress,
+		Name:         resource.Name,
+		ResourceName: resource.ResourceName,
+		Type:         resource.Type,
+		ResourceType: resource.ResourceType,
+		State:        resource.State,
+		Status:       resource.Status,
+		Location:     resource.Location,
+		Tags:         resource.Tags,
+		UpdatedDate:  resource.UpdatedDate,
+	}, nil
+}
+
+// GetRelationships returns no relationships; fixtures carry no topology
+func (a *FixtureResourcesAPI) GetRelationships(ctx context.Context, id string) ([]types.ResourceRelationship, error) {
+	if _, err := a.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return []types.ResourceRelationship{}, nil
+}
+
+// GetServiceGroups returns no service groups; fixtures carry none
+func (a *FixtureResourcesAPI) GetServiceGroups(ctx context.Context, id string) ([]types.ServiceGroup, error) {
+	if _, err := a.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return []types.ServiceGroup{}, nil
+}
+
+// AssignToDeviceGroup adds resources to a device group
+func (a *FixtureResourcesAPI) AssignToDeviceGroup(ctx context.Context, groupID string, resourceIDs []string) error {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.deviceGroup(groupID)
+	if err != nil {
+		return err
+	}
+	if err := a.checkResources(resourceIDs); err != nil {
+		return err
+	}
+	group := &a.fixtures.deviceGroups[i]
+	for _, id := range resourceIDs {
+		if !slices.Contains(group.Members, id) {
+			group.Members = append(group.Members, id)
+		}
+	}
+	group.ResourceCount = len(group.Members)
+	return nil
+}
+
+// AssignToSite checks the resources exist; fixtures carry no sites
+func (a *FixtureResourcesAPI) AssignToSite(ctx context.Context, siteID string, resourceIDs []string) error {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	return a.checkResources(resourceIDs)
+}
+
+// ApplyTemplates checks the resource exists; fixtures carry no templates
+func (a *FixtureResourcesAPI) ApplyTemplates(ctx context.Context, id string, templateIDs []string) error {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	return a.checkResources([]string{id})
+}
+
+// CreateDeviceGroup adds an empty device group to the fixtures
+func (a *FixtureResourcesAPI) CreateDeviceGroup(ctx context.Context, name, description string) (*types.DeviceGroup, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	now := fixtureTime()
+	group := FixtureDeviceGroup{DeviceGroup: types.DeviceGroup{
+		ID:          a.fixtures.newID("dg"),
+		Name:        name,
+		Description: description,
+		Type:        "DEVICE_GROUP",
+		CreatedDate: now,
+		UpdatedDate: now,
+	}}
+	a.fixtures.deviceGroups = append(a.fixtures.deviceGroups, group)
+	return &group.DeviceGroup, nil
+}
+
+// UpdateDeviceGroup renames a device group
+func (a *FixtureResourcesAPI) UpdateDeviceGroup(ctx context.Context, groupID, name, description string) error {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.deviceGroup(groupID)
+	if err != nil {
+		return err
+	}
+	group := &a.fixtures.deviceGroups[i]
+	group.Name = name
+	group.Description = description
+	group.UpdatedDate = fixtureTime()
+	return nil
+}
+
+// GetDeviceGroupMembers returns the IDs of a device group's resources
+func (a *FixtureResourcesAPI) GetDeviceGroupMembers(ctx context.Context, groupID string) ([]string, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.deviceGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(a.fixtures.deviceGroups[i].Members), nil
+}
+
+// ListDeviceGroups returns the device groups of the fixtures
+func (a *FixtureResourcesAPI) ListDeviceGroups(ctx context.Context) ([]types.DeviceGroup, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	groups := make([]types.DeviceGroup, len(a.fixtures.deviceGroups))
+	for i, group := range a.fixtures.deviceGroups {
+		groups[i] = group.DeviceGroup
+	}
+	return groups, nil
+}
+
+// ListSites returns no sites; fixtures carry none
+func (a *FixtureResourcesAPI) ListSites(ctx context.Context) ([]types.Site, error) {
+	return []types.Site{}, nil
+}
+
+// RemoveFromDeviceGroup removes resources from a device group
+func (a *FixtureResourcesAPI) RemoveFromDeviceGroup(ctx context.Context, groupID string, resourceIDs []string) error {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.deviceGroup(groupID)
+	if err != nil {
+		return err
+	}
+	group := &a.fixtures.deviceGroups[i]
+	group.Members = slices.DeleteFunc(group.Members, func(member string) bool { return slices.Contains(resourceIDs, member) })
+	group.ResourceCount = len(group.Members)
+	return nil
+}
+
+// FixtureAlertsAPI implements AlertsAPI over fixtures for the mock backend
+type FixtureAlertsAPI struct {
+	fixtures *Fixtures
+}
+
+// NewFixtureAlertsAPI creates an alerts API serving the fixtures
+func NewFixtureAlertsAPI(fixtures *Fixtures) *FixtureAlertsAPI {
+	return &FixtureAlertsAPI{fixtures: fixtures}
+}
+
+// alert returns the index of an alert; the caller holds the lock
+func (a *FixtureAlertsAPI) alert(id string) (int, error) {
+	i := slices.IndexFunc(a.fixtures.alerts, func(alert FixtureAlert) bool { return alert.ID == id })
+	if i < 0 {
+		return -1, fixtureNotFound("alert", id)
+	}
+	return i, nil
+}
+
+// Get returns an alert
+func (a *FixtureAlertsAPI) Get(ctx context.Context, id string) (*types.AlertDetails, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.alert(id)
+	if err != nil {
+		return nil, err
+	}
+	alert := a.fixtures.alerts[i].AlertDetails
+	return &alert, nil
+}
+
+// GetNotes returns the notes of an alert
+func (a *FixtureAlertsAPI) GetNotes(ctx context.Context, id string) ([]types.AlertNote, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.alert(id)
+	if err != nil {
+		return nil, err
+	}
+	return append([]types.AlertNote{}, a.fixtures.alerts[i].Notes...), nil
+}
+
+// GetAttachments returns no attachments; fixtures carry none
+func (a *FixtureAlertsAPI) GetAttachments(ctx context.Context, id string) ([]types.AlertAttachment, error) {
+	if _, err := a.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return []types.AlertAttachment{}, nil
+}
+
+// AddNote appends a note to an alert
+func (a *FixtureAlertsAPI) AddNote(ctx context.Context, id string, request types.AlertNoteRequest) (*types.AlertNote, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.alert(id)
+	if err != nil {
+		return nil, err
+	}
+	note := types.AlertNote{
+		ID:          a.fixtures.newID("note"),
+		Text:        request.Text,
+		Author:      request.Author,
+		CreatedDate: fixtureTime(),
+		Metadata:    request.Metadata,
+	}
+	a.fixtures.alerts[i].Notes = append(a.fixtures.alerts[i].Notes, note)
+	return &note, nil
+}
+
+// Search returns the alerts matching the field:value terms of the query
+// string
+func (a *FixtureAlertsAPI) Search(ctx context.Context, params types.AlertSearchParams) (*types.AlertSearchResponse, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	matches := []types.AlertDetails{}
+	for _, alert := range a.fixtures.alerts {
+		if matchesFixtureQuery(alert.AlertDetails, params.QueryString) {
+			matches = append(matches, alert.AlertDetails)
+		}
+	}
+
+	page, pages := fixturePage(matches, params.PageNo, params.PageSize)
+	return &types.AlertSearchResponse{
+		Results:      page,
+		TotalResults: len(matches),
+		PageNo:       max(params.PageNo, 1),
+		PageSize:     params.PageSize,
+		TotalPages:   pages,
+		NextPage:     max(params.PageNo, 1) < pages,
+	}, nil
+}
+
+// EscalationPolicies returns the built-in sample policies; fixtures carry none
+func (a *FixtureAlertsAPI) EscalationPolicies(ctx context.Context) ([]types.EscalationPolicy, error) {
+	return (&MockAlertsAPI{}).EscalationPolicies(ctx)
+}
+
+// alertActionStates are the states fixture alerts are left in by each action
+var alertActionStates = map[string]string{
+	types.AlertActionAcknowledge: "Acknowledged",
+	types.AlertActionClose:       "Closed",
+}
+
+// ApplyAction sets the status of a batch of alerts. No alert is changed
+// unless every one of them exists.
+func (a *FixtureAlertsAPI) ApplyAction(ctx context.Context, action string, request types.AlertActionRequest) error {
+	status, ok := alertActionStates[action]
+	if !ok {
+		return &httperr.StatusError{StatusCode: http.StatusBadRequest, Body: fmt.Sprintf("unknown alert action %q", action)}
+	}
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	indexes := make([]int, 0, len(request.Alerts))
+	for _, ref := range request.Alerts {
+		i, err := a.alert(ref.ID)
+		if err != nil {
+			return err
+		}
+		indexes = append(indexes, i)
+	}
+	for _, i := range indexes {
+		a.fixtures.alerts[i].Status = status
+		a.fixtures.alerts[i].UpdatedTime = fixtureTime()
+	}
+	return nil
+}
+
+// FixtureIntegrationsAPI implements IntegrationsAPI over fixtures for the
+// mock backend. Integration types come from the built-in sample data.
+type FixtureIntegrationsAPI struct {
+	MockIntegrationsAPI
+	fixtures *Fixtures
+}
+
+// NewFixtureIntegrationsAPI creates an integrations API serving the fixtures
+func NewFixtureIntegrationsAPI(fixtures *Fixtures) *FixtureIntegrationsAPI {
+	return &FixtureIntegrationsAPI{fixtures: fixtures}
+}
+
+// integration returns the index of an integration; the caller holds the lock
+func (a *FixtureIntegrationsAPI) integration(id string) (int, error) {
+	i := slices.IndexFunc(a.fixtures.integrations, func(integration FixtureIntegration) bool { return integration.ID == id })
+	if i < 0 {
+		return -1, fixtureNotFound("integration", id)
+	}
+	return i, nil
+}
+
+// List returns every integration
+func (a *FixtureIntegrationsAPI) List(ctx context.Context) ([]types.Integration, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	integrations := make([]types.Integration, len(a.fixtures.integrations))
+	for i, integration := range a.fixtures.integrations {
+		integrations[i] = integration.Integration
+	}
+	return integrations, nil
+}
+
+// Get returns an integration
+func (a *FixtureIntegrationsAPI) Get(ctx context.Context, id string) (*types.Integration, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.integration(id)
+	if err != nil {
+		return nil, err
+	}
+	integration := a.fixtures.integrations[i].Integration
+	return &integration, nil
+}
+
+// GetDetailed returns an integration with the resources it discovered
+func (a *FixtureIntegrationsAPI) GetDetailed(ctx context.Context, id string) (*types.DetailedIntegration, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.integration(id)
+	if err != nil {
+		return nil, err
+	}
+	integration := a.fixtures.integrations[i]
+	detailed := &types.DetailedIntegration{
+		ExtendedIntegration: types.ExtendedIntegration{
+			Integration:     integration.Integration,
+			DisplayName:     integration.DisplayName,
+			App:             integration.App,
+			Version:         integration.Version,
+			Category:        integration.Category,
+			State:           integration.State,
+			InstalledBy:     integration.InstalledBy,
+			InstalledTime:   integration.InstalledTime,
+			ModifiedBy:      integration.ModifiedBy,
+			ModifiedTime:    integration.ModifiedTime,
+			UpdateAvailable: integration.UpdateAvailable,
+		},
+		Resources: []types.IntegrationResource{},
+	}
+	for _, id := range integration.Resources {
+		for _, resource := range a.fixtures.resources {
+			if resource.ID == id {
+				detailed.Resources = append(detailed.Resources, types.IntegrationResource{
+					ID:     resource.ID,
+					Name:   resource.Name,
+					Type:   resource.ResourceType,
+					Status: resource.Status,
+				})
+			}
+		}
+	}
+	return detailed, nil
+}
+
+// Create installs an integration from its configuration fields
+func (a *FixtureIntegrationsAPI) Create(ctx context.Context, config map[string]interface{}) (*types.Integration, error) {
+	if name, _ := config["name"].(string); name == "" {
+		return nil, fmt.Errorf("integration name is required")
+	}
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	var integration FixtureIntegration
+	if err := mergeFixture(&integration.Integration, config); err != nil {
+		return nil, fmt.Errorf("invalid integration configuration: %w", err)
+	}
+	integration.ID = a.fixtures.newID("int")
+	integration.State = "ENABLED"
+	integration.InstalledTime = fixtureTime()
+	a.fixtures.integrations = append(a.fixtures.integrations, integration)
+	return &integration.Integration, nil
+}
+
+// Update merges configuration fields into an integration
+func (a *FixtureIntegrationsAPI) Update(ctx context.Context, id string, config map[string]interface{}) (*types.Integration, error) {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.integration(id)
+	if err != nil {
+		return nil, err
+	}
+	integration := a.fixtures.integrations[i].Integration
+	if err := mergeFixture(&integration, config); err != nil {
+		return nil, fmt.Errorf("invalid integration configuration: %w", err)
+	}
+	integration.ID = id
+	integration.ModifiedTime = fixtureTime()
+	a.fixtures.integrations[i].Integration = integration
+	return &integration, nil
+}
+
+// Delete uninstalls an integration
+func (a *FixtureIntegrationsAPI) Delete(ctx context.Context, id string) error {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.integration(id)
+	if err != nil {
+		return err
+	}
+	a.fixtures.integrations = slices.Delete(a.fixtures.integrations, i, i+1)
+	return nil
+}
+
+// Enable sets an integration's state to ENABLED
+func (a *FixtureIntegrationsAPI) Enable(ctx context.Context, id string) error {
+	return a.setState(id, "ENABLED")
+}
+
+// Disable sets an integration's state to DISABLED
+func (a *FixtureIntegrationsAPI) Disable(ctx context.Context, id string) error {
+	return a.setState(id, "DISABLED")
+}
+
+// setState sets the state of an integration
+func (a *FixtureIntegrationsAPI) setState(id, state string) error {
+	a.fixtures.mu.Lock()
+	defer a.fixtures.mu.Unlock()
+	i, err := a.integration(id)
+	if err != nil {
+		return err
+	}
+	a.fixtures.integrations[i].State = state
+	a.fixtures.integrations[i].ModifiedTime = fixtureTime()
+	return nil
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// demoFixtures is the fixture set shipped with the repository
+const demoFixtures = "../../testdata/fixtures"
+
+func TestLoadDemoFixtures(t *testing.T) {
+	fixtures, err := LoadFixtures(demoFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	alert, err := NewFixtureAlertsAPI(fixtures).Get(ctx, "1001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alert.Resource.Name != "db-01" || alert.Resource.ResourceType != "SERVER" {
+		t.Errorf("Expected the alert resource to be filled from the resource fixture, got %+v", alert.Resource)
+	}
+	notes, _ := NewFixtureAlertsAPI(fixtures).GetNotes(ctx, "1001")
+	if len(notes) != 1 {
+		t.Errorf("Expected the alert's note, got %+v", notes)
+	}
+
+	resources := NewFixtureResourcesAPI(fixtures)
+	storefront, err := resources.Search(ctx, types.ResourceSearchParams{DeviceGroup: "Storefront", QueryString: "os:Ubuntu 22.04+startDate:2024-01-01T00:00:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storefront.TotalResults != 2 || storefront.Results[0].Name != "web-01" {
+		t.Errorf("Expected the two storefront servers, got %+v", storefront.Results)
+	}
+	if byProperty, _ := resources.Search(ctx, types.ResourceSearchParams{QueryString: "deviceUniqueId:dev-web-01"}); byProperty.TotalResults != 1 {
+		t.Errorf("Expected a query on a property to match web-01, got %+v", byProperty.Results)
+	}
+
+	detailed, err := NewFixtureIntegrationsAPI(fixtures).GetDetailed(ctx, "INTG-demo-vcenter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detailed.Resources) != 3 || detailed.Resources[2].Name != "staging-web-01" {
+		t.Errorf("Expected the integration's resources, got %+v", detailed.Resources)
+	}
+
+	// Deleting a resource removes it from its groups and integrations
+	if err := resources.Delete(ctx, "9a1e0c2b-1d4f-4c55-8a01-000000000001"); err != nil {
+		t.Fatal(err)
+	}
+	if members, _ := resources.GetDeviceGroupMembers(ctx, "dg-demo-storefront"); len(members) != 1 {
+		t.Errorf("Expected one storefront member left, got %v", members)
+	}
+	if detailed, _ := NewFixtureIntegrationsAPI(fixtures).GetDetailed(ctx, "INTG-demo-vcenter"); len(detailed.Resources) != 2 {
+		t.Errorf("Expected two integration resources left, got %+v", detailed.Resources)
+	}
+	if _, err := resources.Get(ctx, "9a1e0c2b-1d4f-4c55-8a01-000000000001"); !isNotFoundError(err) {
+		t.Errorf("Expected a deleted resource not to be found, got %v", err)
+	}
+}
+
+func TestLoadFixturesReportsBrokenReferences(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"resources.json":     `[{"id": "r1", "name": "web-01"}]`,
+		"device_groups.yaml": "- {id: g1, name: Web, members: [r1, r2]}\n",
+		"alerts.yml":         "- {id: a1, subject: Down, resource: {id: r3}}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := LoadFixtures(dir)
+	if err == nil || !strings.Contains(err.Error(), "unknown member resource r2") || !strings.Contains(err.Error(), "alert a1: unknown resource r3") {
+		t.Errorf("Expected every broken reference to be reported, got %v", err)
+	}
+	if _, err := LoadFixtures(t.TempDir()); err == nil || !strings.Contains(err.Error(), "no fixtures found") {
+		t.Errorf("Expected an empty directory to be rejected, got %v", err)
+	}
+}
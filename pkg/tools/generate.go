@@ -0,0 +1,5 @@
+package tools
+
+// Test fakes of the API interfaces are generated into fakes_test.go. Add new
+// API interfaces to this list and run `make generate` after changing one.
+//go:generate go run ../../internal/fakegen -out fakes_test.go ResourcesAPI IntegrationsAPI AlertsAPI MonitorsAPI ClientsAPI UsageAPI
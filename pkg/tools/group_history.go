@@ -0,0 +1,466 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/scheduler"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// GroupCatalogProvider is implemented by resources APIs that can list the
+// tenant's device groups and sites
+type GroupCatalogProvider interface {
+	ListDeviceGroups(ctx context.Context) ([]types.DeviceGroup, error)
+	ListSites(ctx context.Context) ([]types.Site, error)
+}
+
+const (
+	// scheduledGroupSnapshot is the scheduler action snapshotting device groups and sites
+	scheduledGroupSnapshot = "resources.snapshotGroups"
+	// groupSnapshotTarget is the target of scheduled group snapshots
+	groupSnapshotTarget = "device groups and sites"
+	// groupSnapshotInterval is the time between scheduled snapshots
+	groupSnapshotInterval = 24 * time.Hour
+	// groupSnapshotRetention is how long snapshots are kept
+	groupSnapshotRetention = 90 * 24 * time.Hour
+	// groupSnapshotEstimate is the device groups assumed when estimating the
+	// calls of a tenant's first snapshot
+	groupSnapshotEstimate = 50
+	// sitePageSize is the page size used when listing sites
+	sitePageSize = 100
+)
+
+// Kinds and changes of a GroupChange
+const (
+	groupKindDeviceGroup = "deviceGroup"
+	groupKindSite        = "site"
+
+	groupAdded   = "added"
+	groupRemoved = "removed"
+	groupChanged = "changed"
+)
+
+// GroupHistory snapshots a tenant's device group and site definitions,
+// including group members and the queries of dynamic groups, and reports
+// how they changed between snapshots
+type GroupHistory struct {
+	api     ResourcesAPI
+	catalog GroupCatalogProvider
+	groups  DeviceGroupProvider
+	store   *store.Store
+	tenant  string
+	now     func() time.Time
+	logger  *common.CustomLogger
+}
+
+// NewGroupHistory creates the group history of the tenant over the default
+// state store, which may be unavailable
+func NewGroupHistory(api ResourcesAPI, tenant string) *GroupHistory {
+	h := &GroupHistory{
+		api:    api,
+		store:  store.Default(),
+		tenant: tenant,
+		now:    time.Now,
+		logger: common.GetLogger(),
+	}
+	if catalog, ok := api.(GroupCatalogProvider); ok {
+		h.catalog = catalog
+	}
+	if groups, ok := api.(DeviceGroupProvider); ok {
+		h.groups = groups
+	}
+	return h
+}
+
+// ready reports why snapshots cannot be taken or read, if they cannot
+func (h *GroupHistory) ready() error {
+	if h.store == nil {
+		return fmt.Errorf("group history requires the state store")
+	}
+	return nil
+}
+
+// key returns the store key of the tenant's snapshot taken at t. Keys sort
+// in the order the snapshots were taken.
+func (h *GroupHistory) key(t time.Time) string {
+	return h.tenant + "/" + t.UTC().Format(time.RFC3339)
+}
+
+// Snapshot records the tenant's device groups, their members and queries,
+// and its sites, then drops snapshots older than the retention period
+func (h *GroupHistory) Snapshot(ctx context.Context) (*types.GroupSnapshot, error) {
+	if err := h.ready(); err != nil {
+		return nil, err
+	}
+	if h.catalog == nil {
+		return nil, fmt.Errorf("listing device groups and sites is not supported by this resources backend")
+	}
+
+	groups, err := h.catalog.ListDeviceGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sites, err := h.catalog.ListSites(ctx)
+	if err != nil {
+		return nil, err
+	}
+	queries := map[string]types.ResourceSelector{}
+	if dynamic, err := NewDynamicGroups(h.api, h.tenant).List(); err == nil {
+		for _, group := range dynamic {
+			queries[group.ID] = group.Selector
+		}
+	}
+
+	now := h.now().UTC()
+	snapshot := &types.GroupSnapshot{
+		Tenant:       h.tenant,
+		TakenAt:      now.Format(time.RFC3339),
+		DeviceGroups: make([]types.GroupDefinition, 0, len(groups)),
+		Sites:        sites,
+	}
+	for _, group := range flattenDeviceGroups(groups) {
+		definition := types.GroupDefinition{
+			ID:          group.ID,
+			Name:        group.Name,
+			Description: group.Description,
+			Type:        group.Type,
+			ParentID:    group.ParentID,
+			UpdatedDate: group.UpdatedDate,
+			UpdatedBy:   group.UpdatedBy,
+			Members:     []string{},
+		}
+		if query, ok := queries[group.ID]; ok {
+			definition.Query = &query
+		}
+		if h.groups != nil {
+			members, err := h.groups.GetDeviceGroupMembers(ctx, group.ID)
+			if err != nil {
+				return nil, err
+			}
+			sort.Strings(members)
+			definition.Members = members
+		}
+		snapshot.DeviceGroups = append(snapshot.DeviceGroups, definition)
+	}
+	sort.Slice(snapshot.DeviceGroups, func(i, j int) bool { return snapshot.DeviceGroups[i].ID < snapshot.DeviceGroups[j].ID })
+	sort.Slice(snapshot.Sites, func(i, j int) bool { return snapshot.Sites[i].ID < snapshot.Sites[j].ID })
+
+	if err := h.store.Put(store.BucketGroupSnapshots, h.key(now), snapshot); err != nil {
+		return nil, fmt.Errorf("failed to save group snapshot: %w", err)
+	}
+	h.logger.Info("Snapshotted %d device groups and %d sites of tenant %s", len(snapshot.DeviceGroups), len(snapshot.Sites), h.tenant)
+	h.prune(now.Add(-groupSnapshotRetention))
+	return snapshot, nil
+}
+
+// flattenDeviceGroups returns device groups and their nested children, with
+// each child's parent set
+func flattenDeviceGroups(groups []types.DeviceGroup) []types.DeviceGroup {
+	flat := make([]types.DeviceGroup, 0, len(groups))
+	for _, group := range groups {
+		children := group.Children
+		group.Children = nil
+		flat = append(flat, group)
+		for _, child := range flattenDeviceGroups(children) {
+			if child.ParentID == "" {
+				child.ParentID = group.ID
+			}
+			flat = append(flat, child)
+		}
+	}
+	return flat
+}
+
+// prune removes the tenant's snapshots taken before cutoff
+func (h *GroupHistory) prune(cutoff time.Time) {
+	keys, err := h.store.Keys(store.BucketGroupSnapshots)
+	if err != nil {
+		h.logger.Warn("Failed to list group snapshots for pruning: %v", err)
+		return
+	}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, h.tenant+"/") || key >= h.key(cutoff) {
+			continue
+		}
+		if err := h.store.Delete(store.BucketGroupSnapshots, key); err != nil {
+			h.logger.Warn("Failed to remove group snapshot %s: %v", key, err)
+		}
+	}
+}
+
+// Snapshots returns the tenant's snapshots, oldest first
+func (h *GroupHistory) Snapshots() ([]types.GroupSnapshot, error) {
+	if err := h.ready(); err != nil {
+		return nil, err
+	}
+	snapshots := make([]types.GroupSnapshot, 0)
+	err := h.store.ForEach(store.BucketGroupSnapshots, func(key string, raw []byte) error {
+		if !strings.HasPrefix(key, h.tenant+"/") {
+			return nil
+		}
+		var snapshot types.GroupSnapshot
+		if err := json.Unmarshal(raw, &snapshot); err != nil {
+			return fmt.Errorf("failed to decode group snapshot %s: %w", key, err)
+		}
+		snapshots = append(snapshots, snapshot)
+		return nil
+	})
+	return snapshots, err
+}
+
+// Latest returns the tenant's most recent snapshot, or nil before the first
+func (h *GroupHistory) Latest() (*types.GroupSnapshot, error) {
+	if err := h.ready(); err != nil {
+		return nil, err
+	}
+	keys, err := h.store.Keys(store.BucketGroupSnapshots)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		if strings.HasPrefix(keys[i], h.tenant+"/") {
+			var snapshot types.GroupSnapshot
+			if err := h.store.Get(store.BucketGroupSnapshots, keys[i], &snapshot); err != nil {
+				return nil, err
+			}
+			return &snapshot, nil
+		}
+	}
+	return nil, nil
+}
+
+// snapshotsIn returns the snapshots taken in a time range, preceded by the
+// last one taken before it, which is the baseline the first change in the
+// range is measured from
+func (h *GroupHistory) snapshotsIn(r TimeRange) ([]types.GroupSnapshot, error) {
+	snapshots, err := h.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no group snapshots have been taken for tenant %s yet; one is taken daily, or take one now with snapshotGroups", h.tenant)
+	}
+	first := sort.Search(len(snapshots), func(i int) bool { return !snapshotTime(snapshots[i]).Before(r.Start) })
+	last := sort.Search(len(snapshots), func(i int) bool { return snapshotTime(snapshots[i]).After(r.End) })
+	return snapshots[max(first-1, 0):last], nil
+}
+
+// snapshotTime returns the time a snapshot was taken
+func snapshotTime(snapshot types.GroupSnapshot) time.Time {
+	t, _ := time.Parse(time.RFC3339, snapshot.TakenAt)
+	return t
+}
+
+// History returns the changes of a device group or site, by ID, between the
+// snapshots of a time range
+func (h *GroupHistory) History(id string, r TimeRange) (*types.GroupHistory, error) {
+	snapshots, err := h.snapshotsIn(r)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &types.GroupHistory{ID: id, Snapshots: len(snapshots), Changes: []types.GroupChange{}}
+	for i, snapshot := range snapshots {
+		entries := groupEntries(snapshot)
+		for _, key := range []string{groupKindDeviceGroup + "/" + id, groupKindSite + "/" + id} {
+			if entry, ok := entries[key]; ok {
+				history.Kind, history.Name = entry.kind, entry.name
+			}
+		}
+		if i > 0 {
+			changes, _ := diffGroupSnapshots(snapshots[i-1], snapshot)
+			for _, change := range changes {
+				if change.ID == id {
+					history.Changes = append(history.Changes, change)
+				}
+			}
+		}
+	}
+	if history.Kind == "" {
+		return nil, fmt.Errorf("no device group or site %s in the group snapshots of %s..%s",
+			id, r.Start.Format(time.RFC3339), r.End.Format(time.RFC3339))
+	}
+
+	history.From, history.To = snapshots[0].TakenAt, snapshots[len(snapshots)-1].TakenAt
+	history.Message = fmt.Sprintf("%d changes to %s %s across %d snapshots from %s to %s",
+		len(history.Changes), history.Kind, history.Name, len(snapshots), history.From, history.To)
+	return history, nil
+}
+
+// Diff compares the device groups and sites of the last snapshot before a
+// time range, or the first in it, with the last snapshot in it
+func (h *GroupHistory) Diff(r TimeRange) (*types.GroupDiff, error) {
+	snapshots, err := h.snapshotsIn(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) < 2 {
+		return nil, fmt.Errorf("the time range needs two group snapshots to compare, but has %d; widen it", len(snapshots))
+	}
+
+	before, after := snapshots[0], snapshots[len(snapshots)-1]
+	changes, unchanged := diffGroupSnapshots(before, after)
+	diff := &types.GroupDiff{
+		Tenant:    h.tenant,
+		From:      before.TakenAt,
+		To:        after.TakenAt,
+		Changes:   changes,
+		Unchanged: unchanged,
+	}
+	counts := map[string]int{}
+	for _, change := range changes {
+		counts[change.Change]++
+	}
+	diff.Summary = fmt.Sprintf("%d added, %d removed, %d changed, %d unchanged device groups and sites between %s and %s",
+		counts[groupAdded], counts[groupRemoved], counts[groupChanged], unchanged, diff.From, diff.To)
+	return diff, nil
+}
+
+// groupEntry is a device group or site of a snapshot in comparable form
+type groupEntry struct {
+	kind    string
+	id      string
+	name    string
+	fields  interface{}
+	members []string
+}
+
+// groupEntries returns the device groups and sites of a snapshot by kind and ID
+func groupEntries(snapshot types.GroupSnapshot) map[string]groupEntry {
+	entries := make(map[string]groupEntry, len(snapshot.DeviceGroups)+len(snapshot.Sites))
+	for _, group := range snapshot.DeviceGroups {
+		members := group.Members
+		group.Members = nil
+		fields, _ := normalizeJSON(group)
+		entries[groupKindDeviceGroup+"/"+group.ID] = groupEntry{kind: groupKindDeviceGroup, id: group.ID, name: group.Name, fields: fields, members: members}
+	}
+	for _, site := range snapshot.Sites {
+		fields, _ := normalizeJSON(site)
+		entries[groupKindSite+"/"+site.ID] = groupEntry{kind: groupKindSite, id: site.ID, name: site.Name, fields: fields}
+	}
+	return entries
+}
+
+// diffGroupSnapshots returns the device groups and sites added, removed or
+// changed from one snapshot to a later one, and how many are unchanged.
+// Membership changes are listed as the members added and removed.
+func diffGroupSnapshots(before, after types.GroupSnapshot) ([]types.GroupChange, int) {
+	beforeEntries, afterEntries := groupEntries(before), groupEntries(after)
+	keys := make([]string, 0, len(beforeEntries)+len(afterEntries))
+	for key := range beforeEntries {
+		keys = append(keys, key)
+	}
+	for key := range afterEntries {
+		if _, ok := beforeEntries[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make([]types.GroupChange, 0)
+	unchanged := 0
+	for _, key := range keys {
+		old, existed := beforeEntries[key]
+		current, exists := afterEntries[key]
+		change := types.GroupChange{Since: before.TakenAt, SeenAt: after.TakenAt}
+		switch {
+		case !existed:
+			change.Kind, change.ID, change.Name, change.Change = current.kind, current.id, current.name, groupAdded
+			change.MembersAdded = current.members
+		case !exists:
+			change.Kind, change.ID, change.Name, change.Change = old.kind, old.id, old.name, groupRemoved
+			change.MembersRemoved = old.members
+		default:
+			change.Kind, change.ID, change.Name, change.Change = current.kind, current.id, current.name, groupChanged
+			change.Fields = compareValues("", old.fields, current.fields, nil)
+			change.MembersAdded = memberDifference(current.members, old.members)
+			change.MembersRemoved = memberDifference(old.members, current.members)
+			if len(change.Fields) == 0 && len(change.MembersAdded) == 0 && len(change.MembersRemoved) == 0 {
+				unchanged++
+				continue
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, unchanged
+}
+
+// memberDifference returns the members of a that are not in b
+func memberDifference(a, b []string) []string {
+	var difference []string
+	for _, member := range a {
+		if !slices.Contains(b, member) {
+			difference = append(difference, member)
+		}
+	}
+	return difference
+}
+
+// registerGroupSnapshotExecutor lets the scheduler snapshot the device groups
+// and sites of the entry's tenant through api, or through the API of the
+// entry's tenant. Each snapshot schedules the next one.
+func registerGroupSnapshotExecutor(api ResourcesAPI) {
+	scheduler.RegisterExecutor(scheduledGroupSnapshot, func(ctx context.Context, entry scheduler.Entry) error {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]interface{}{"tenant": entry.Tenant}
+		tenantAPI, err := resourcesAPIForRequest(req, api)
+		if err != nil {
+			return err
+		}
+		if _, err := NewGroupHistory(tenantAPI, requestTenant(req)).Snapshot(ctx); err != nil {
+			return err
+		}
+		return scheduleGroupSnapshot(scheduler.Default(), entry.Tenant, time.Now().Add(groupSnapshotInterval))
+	})
+}
+
+// scheduleGroupSnapshot schedules a snapshot of a tenant's device groups and sites
+func scheduleGroupSnapshot(s *scheduler.Scheduler, tenant string, at time.Time) error {
+	if s == nil {
+		return fmt.Errorf("scheduling is not available: it requires the state store")
+	}
+	_, err := s.Schedule(scheduler.Entry{
+		Action: scheduledGroupSnapshot,
+		Target: groupSnapshotTarget,
+		Tenant: tenant,
+		RunAt:  at,
+		Note:   "daily device group and site snapshot",
+	})
+	return err
+}
+
+// startGroupSnapshots registers the snapshot executor and, unless one is
+// already pending, schedules a snapshot of the default tenant a minute from
+// now; from then on each snapshot schedules the next
+func startGroupSnapshots(api ResourcesAPI) {
+	if _, ok := api.(GroupCatalogProvider); !ok {
+		return
+	}
+	registerGroupSnapshotExecutor(api)
+	s := scheduler.Default()
+	if s == nil {
+		return
+	}
+	entries, err := s.List()
+	if err != nil {
+		common.GetLogger().Warn("Failed to list scheduled actions; group snapshots are not scheduled: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.Action == scheduledGroupSnapshot && entry.Status == scheduler.StatusPending {
+			return
+		}
+	}
+	if err := scheduleGroupSnapshot(s, "", time.Now().Add(time.Minute)); err != nil {
+		common.GetLogger().Warn("Failed to schedule group snapshots: %v", err)
+	}
+}
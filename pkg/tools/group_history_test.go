@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestGroupHistoryTracksMembershipAndQueries(t *testing.T) {
+	s := useTestStore(t)
+	fixtures, err := LoadFixtures(demoFixtures)
+	if err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+	api := NewFixtureResourcesAPI(fixtures)
+	ctx := context.Background()
+
+	now := time.Date(2026, 10, 1, 2, 0, 0, 0, time.UTC)
+	history := NewGroupHistory(api, "default")
+	history.now = func() time.Time { return now }
+	if _, err := history.Snapshot(ctx); err != nil {
+		t.Fatalf("Failed to take the first snapshot: %v", err)
+	}
+
+	// A day later a member leaves Storefront, which becomes a dynamic group
+	const member = "9a1e0c2b-1d4f-4c55-8a01-000000000002"
+	if err := api.RemoveFromDeviceGroup(ctx, "dg-demo-storefront", []string{member}); err != nil {
+		t.Fatal(err)
+	}
+	dynamic := types.DynamicGroup{ID: "dg-demo-storefront", Name: "Storefront", Tenant: "default", Selector: types.ResourceSelector{HostName: "web-*"}}
+	if err := s.Put(store.BucketDynamicGroups, "default/dg-demo-storefront", dynamic); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(groupSnapshotInterval)
+	if _, err := history.Snapshot(ctx); err != nil {
+		t.Fatalf("Failed to take the second snapshot: %v", err)
+	}
+
+	month := TimeRange{Start: now.Add(-30 * 24 * time.Hour), End: now}
+	changes, err := history.History("dg-demo-storefront", month)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if changes.Kind != groupKindDeviceGroup || changes.Snapshots != 2 || len(changes.Changes) != 1 {
+		t.Fatalf("Expected one change across two snapshots, got %+v", changes)
+	}
+	change := changes.Changes[0]
+	if change.Change != groupChanged || !slices.Equal(change.MembersRemoved, []string{member}) || len(change.MembersAdded) != 0 {
+		t.Errorf("Expected %s to be removed, got %+v", member, change)
+	}
+	if len(change.Fields) != 1 || change.Fields[0].Path != "query" || change.Since != "2026-10-01T02:00:00Z" || change.SeenAt != "2026-10-02T02:00:00Z" {
+		t.Errorf("Expected the new query between the two snapshots, got %+v", change)
+	}
+
+	diff, err := history.Diff(month)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(diff.Changes) != 1 || diff.Unchanged != len(fixtures.deviceGroups)-1 {
+		t.Errorf("Expected only Storefront to change, got %s", diff.Summary)
+	}
+
+	// A range after the last snapshot has only it as a baseline
+	if _, err := history.Diff(TimeRange{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)}); err == nil || !strings.Contains(err.Error(), "needs two group snapshots") {
+		t.Errorf("Expected a range with only a baseline to be refused, got %v", err)
+	}
+	if _, err := history.History("dg-missing", month); err == nil {
+		t.Error("Expected an unknown group to be reported")
+	}
+}
+
+func TestGroupHistoryPrunesOldSnapshots(t *testing.T) {
+	useTestStore(t)
+	fixtures, err := LoadFixtures(demoFixtures)
+	if err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	history := NewGroupHistory(NewFixtureResourcesAPI(fixtures), "default")
+	history.now = func() time.Time { return now }
+	for _, step := range []time.Duration{0, groupSnapshotRetention, time.Hour} {
+		now = now.Add(step)
+		if _, err := history.Snapshot(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshots, err := history.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[0].TakenAt != "2026-08-30T00:00:00Z" {
+		t.Errorf("Expected the first snapshot to be pruned, got %d from %s", len(snapshots), snapshots[0].TakenAt)
+	}
+}
+
+func TestResourcesToolGroupHistoryArguments(t *testing.T) {
+	useTestStore(t)
+	for _, args := range []map[string]interface{}{
+		{"action": "getGroupHistory"},
+		{"action": "diffGroups", "timeRange": "sometime"},
+		{"action": "diffGroups"},
+	} {
+		result, _ := ResourcesToolHandler(context.Background(), createTestRequest(args), newGroupInventory())
+		if !result.IsError {
+			t.Errorf("Expected %v to be refused", args)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// GroupAdminProvider is implemented by resources APIs that can manage device
+// groups, sites and service groups themselves, rather than only their members
+type GroupAdminProvider interface {
+	GroupCatalogProvider
+
+	GetDeviceGroup(ctx context.Context, groupID string) (*types.DeviceGroup, error)
+	CreateDeviceGroup(ctx context.Context, name, description string) (*types.DeviceGroup, error)
+	UpdateDeviceGroup(ctx context.Context, groupID, name, description string) error
+	DeleteDeviceGroup(ctx context.Context, groupID string) error
+
+	GetSite(ctx context.Context, siteID string) (*types.Site, error)
+	CreateSite(ctx context.Context, request types.SiteRequest) (*types.Site, error)
+	UpdateSite(ctx context.Context, siteID string, request types.SiteRequest) (*types.Site, error)
+	DeleteSite(ctx context.Context, siteID string) error
+
+	ListServiceGroups(ctx context.Context) ([]types.ServiceGroup, error)
+	GetServiceGroup(ctx context.Context, groupID string) (*types.ServiceGroup, error)
+	CreateServiceGroup(ctx context.Context, request types.ServiceGroupRequest) (*types.ServiceGroup, error)
+	UpdateServiceGroup(ctx context.Context, groupID string, request types.ServiceGroupRequest) (*types.ServiceGroup, error)
+	DeleteServiceGroup(ctx context.Context, groupID string) error
+}
+
+// groupAction performs a device group, site or service group action. The
+// group is named by the id argument; creates and updates take name and
+// description, and sites their address fields from config. Updates change
+// only the fields given and keep the others.
+func groupAction(ctx context.Context, req mcp.CallToolRequest, api ResourcesAPI, action string) (interface{}, error) {
+	groups, ok := api.(GroupAdminProvider)
+	if !ok {
+		return nil, fmt.Errorf("device groups, sites and service groups are not supported by this resources backend")
+	}
+
+	id := req.GetString("id", "")
+	switch action {
+	case "listDeviceGroups", "listSites", "listServiceGroups", "createDeviceGroup", "createSite", "createServiceGroup":
+	default:
+		if id == "" {
+			return nil, fmt.Errorf("id is required for %s action", action)
+		}
+	}
+
+	args := req.GetArguments()
+	name, hasName := args["name"].(string)
+	description, hasDescription := args["description"].(string)
+	var site types.SiteRequest
+	if config, ok := args["config"].(map[string]interface{}); ok && (action == "createSite" || action == "updateSite") {
+		if err := decodeArgument("resources", "config", config, &site); err != nil {
+			return nil, fmt.Errorf("failed to parse site config: %w", err)
+		}
+	}
+	if hasName {
+		site.Name = name
+	}
+	if hasDescription {
+		site.Description = description
+	}
+
+	switch action {
+	case "createDeviceGroup", "createSite", "createServiceGroup":
+		if name == "" && site.Name == "" {
+			return nil, fmt.Errorf("name is required for %s action", action)
+		}
+	case "updateDeviceGroup", "updateServiceGroup":
+		if !hasName && !hasDescription {
+			return nil, fmt.Errorf("name or description is required for %s action", action)
+		}
+	case "updateSite":
+		if site == (types.SiteRequest{}) {
+			return nil, fmt.Errorf("name, description or config with the site fields to change is required for updateSite action")
+		}
+	}
+
+	switch action {
+	case "listDeviceGroups":
+		return groups.ListDeviceGroups(ctx)
+	case "getDeviceGroup":
+		return groups.GetDeviceGroup(ctx, id)
+	case "createDeviceGroup":
+		return groups.CreateDeviceGroup(ctx, name, description)
+	case "updateDeviceGroup":
+		group, err := groups.GetDeviceGroup(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if hasName {
+			group.Name = name
+		}
+		if hasDescription {
+			group.Description = description
+		}
+		if err := groups.UpdateDeviceGroup(ctx, id, group.Name, group.Description); err != nil {
+			return nil, err
+		}
+		return group, nil
+	case "deleteDeviceGroup":
+		return nil, groups.DeleteDeviceGroup(ctx, id)
+
+	case "listSites":
+		return groups.ListSites(ctx)
+	case "getSite":
+		return groups.GetSite(ctx, id)
+	case "createSite":
+		return groups.CreateSite(ctx, site)
+	case "updateSite":
+		current, err := groups.GetSite(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return groups.UpdateSite(ctx, id, mergeSiteRequest(*current, site))
+	case "deleteSite":
+		return nil, groups.DeleteSite(ctx, id)
+
+	case "listServiceGroups":
+		return groups.ListServiceGroups(ctx)
+	case "getServiceGroup":
+		return groups.GetServiceGroup(ctx, id)
+	case "createServiceGroup":
+		return groups.CreateServiceGroup(ctx, types.ServiceGroupRequest{Name: name, Description: description})
+	case "updateServiceGroup":
+		current, err := groups.GetServiceGroup(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		request := types.ServiceGroupRequest{Name: current.Name, Description: current.Description}
+		if hasName {
+			request.Name = name
+		}
+		if hasDescription {
+			request.Description = description
+		}
+		return groups.UpdateServiceGroup(ctx, id, request)
+	case "deleteServiceGroup":
+		return nil, groups.DeleteServiceGroup(ctx, id)
+	}
+	return nil, fmt.Errorf("unknown group action: %s", action)
+}
+
+// mergeSiteRequest returns the fields of a site with the changes set in
+// request applied
+func mergeSiteRequest(current types.Site, request types.SiteRequest) types.SiteRequest {
+	merged := types.SiteRequest{
+		Name:        current.Name,
+		Description: current.Description,
+		Address:     current.Address,
+		City:        current.City,
+		State:       current.State,
+		Country:     current.Country,
+		ZipCode:     current.ZipCode,
+		TimeZone:    current.TimeZone,
+	}
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&merged.Name, request.Name},
+		{&merged.Description, request.Description},
+		{&merged.Address, request.Address},
+		{&merged.City, request.City},
+		{&merged.State, request.State},
+		{&merged.Country, request.Country},
+		{&merged.ZipCode, request.ZipCode},
+		{&merged.TimeZone, request.TimeZone},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+	return merged
+}
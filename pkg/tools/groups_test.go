@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// recordingGroupsAPI keeps one site and one device group and records their
+// updates; unused GroupAdminProvider methods panic
+type recordingGroupsAPI struct {
+	*FakeResourcesAPI
+	GroupAdminProvider
+	site        types.Site
+	siteUpdate  *types.SiteRequest
+	group       types.DeviceGroup
+	deletedSite string
+}
+
+func (m *recordingGroupsAPI) GetSite(ctx context.Context, siteID string) (*types.Site, error) {
+	site := m.site
+	return &site, nil
+}
+
+func (m *recordingGroupsAPI) UpdateSite(ctx context.Context, siteID string, request types.SiteRequest) (*types.Site, error) {
+	m.siteUpdate = &request
+	return &types.Site{ID: siteID, Name: request.Name, City: request.City, Country: request.Country}, nil
+}
+
+func (m *recordingGroupsAPI) DeleteSite(ctx context.Context, siteID string) error {
+	m.deletedSite = siteID
+	return nil
+}
+
+func (m *recordingGroupsAPI) GetDeviceGroup(ctx context.Context, groupID string) (*types.DeviceGroup, error) {
+	group := m.group
+	return &group, nil
+}
+
+func (m *recordingGroupsAPI) UpdateDeviceGroup(ctx context.Context, groupID, name, description string) error {
+	m.group.Name, m.group.Description = name, description
+	return nil
+}
+
+func TestResourcesToolGroupActions(t *testing.T) {
+	api := &recordingGroupsAPI{
+		FakeResourcesAPI: &FakeResourcesAPI{},
+		site:             types.Site{ID: "s-1", Name: "London", City: "London", Country: "UK", TimeZone: "Europe/London"},
+		group:            types.DeviceGroup{ID: "dg-1", Name: "web", Description: "Web servers"},
+	}
+
+	result, _ := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "updateSite", "id": "s-1", "config": map[string]interface{}{"city": "Manchester"},
+	}), api)
+	if result.IsError {
+		t.Fatalf("updateSite failed: %s", resultText(result))
+	}
+	if update := api.siteUpdate; update == nil || update.City != "Manchester" || update.Name != "London" || update.TimeZone != "Europe/London" {
+		t.Errorf("Expected only the city to change, got %+v", update)
+	}
+
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "updateDeviceGroup", "id": "dg-1", "description": "",
+	}), api)
+	var group types.DeviceGroup
+	if err := json.Unmarshal([]byte(resultText(result)), &group); err != nil || group.Name != "web" || api.group.Description != "" {
+		t.Errorf("Expected the description to be cleared and the name kept, got %+v %s", api.group, resultText(result))
+	}
+
+	if result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "deleteSite", "id": "s-1",
+	}), api); result.IsError || api.deletedSite != "s-1" {
+		t.Errorf("Expected the site to be deleted, got %s", resultText(result))
+	}
+
+	for want, args := range map[string]map[string]interface{}{
+		"id is required for getServiceGroup":    {"action": "getServiceGroup"},
+		"name is required for createSite":       {"action": "createSite", "config": map[string]interface{}{"city": "Paris"}},
+		"name or description is required":       {"action": "updateServiceGroup", "id": "sg-1"},
+		"config with the site fields to change": {"action": "updateSite", "id": "s-1"},
+	} {
+		result, _ := ResourcesToolHandler(context.Background(), createTestRequest(args), api)
+		if !result.IsError || !strings.Contains(resultText(result), want) {
+			t.Errorf("Expected %q, got %s", want, resultText(result))
+		}
+	}
+
+	// Backends without group management say so
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{"action": "listSites"}), &FakeResourcesAPI{})
+	if !result.IsError || !strings.Contains(resultText(result), "not supported") {
+		t.Errorf("Expected an unsupported backend to be reported, got %s", resultText(result))
+	}
+}
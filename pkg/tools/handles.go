@@ -0,0 +1,239 @@
+package tools
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// resultHandleTTL is how long a result stays addressable by its handle
+	resultHandleTTL = 10 * time.Minute
+	// maxHandlesPerSession bounds the results kept for one session; the least recently used is evicted first
+	maxHandlesPerSession = 20
+	// maxHandleBytes is the largest rendered result kept under a handle
+	maxHandleBytes = 4 << 20
+	// defaultHandleThreshold is the rendered size above which results are returned as a handle plus summary
+	defaultHandleThreshold = 16 << 10
+	// defaultHandlePageSize and maxHandlePageSize bound the entries returned by nextPage
+	defaultHandlePageSize = 50
+	maxHandlePageSize     = 500
+	// handlePreviewEntries is the number of entries shown in a handle summary
+	handlePreviewEntries = 3
+)
+
+// resultHandle is a tool result kept briefly so later calls can reference it
+type resultHandle struct {
+	ID        string
+	SessionID string
+	Tool      string
+	Value     interface{}
+	Bytes     int
+	CreatedAt time.Time
+	LastUsed  time.Time
+	// Cursor is the offset of the next entry returned by nextPage
+	Cursor int
+}
+
+var (
+	resultHandlesMu sync.Mutex
+	resultHandles   = make(map[string]*resultHandle)
+	handleNow       = time.Now
+)
+
+// handleThreshold returns the rendered size above which results are not returned inline
+func handleThreshold() int {
+	if threshold := getResponseDefaults().HandleThresholdBytes; threshold > 0 {
+		return threshold
+	}
+	return defaultHandleThreshold
+}
+
+// storeResultHandle keeps a result of the given rendered size for the session
+// and returns its handle
+func storeResultHandle(sessionID, tool string, value interface{}, size int) string {
+	var raw [6]byte
+	_, _ = rand.Read(raw[:])
+	id := "rh-" + hex.EncodeToString(raw[:])
+
+	resultHandlesMu.Lock()
+	defer resultHandlesMu.Unlock()
+
+	now := handleNow()
+	var owned []*resultHandle
+	for key, handle := range resultHandles {
+		if now.Sub(handle.CreatedAt) >= resultHandleTTL {
+			delete(resultHandles, key)
+			continue
+		}
+		if handle.SessionID == sessionID {
+			owned = append(owned, handle)
+		}
+	}
+
+	// Evict the session's least recently used handles to stay within the limit
+	if excess := len(owned) - maxHandlesPerSession + 1; excess > 0 {
+		sort.Slice(owned, func(i, j int) bool { return owned[i].LastUsed.Before(owned[j].LastUsed) })
+		for _, handle := range owned[:excess] {
+			delete(resultHandles, handle.ID)
+		}
+	}
+
+	resultHandles[id] = &resultHandle{ID: id, SessionID: sessionID, Tool: tool, Value: value, Bytes: size, CreatedAt: now, LastUsed: now}
+	return id
+}
+
+// sessionResultHandles returns copies of the session's live handles, the
+// most recently used first
+func sessionResultHandles(sessionID string) []resultHandle {
+	resultHandlesMu.Lock()
+	defer resultHandlesMu.Unlock()
+
+	now := handleNow()
+	var handles []resultHandle
+	for _, handle := range resultHandles {
+		if handle.SessionID == sessionID && now.Sub(handle.CreatedAt) < resultHandleTTL {
+			handles = append(handles, *handle)
+		}
+	}
+	sort.Slice(handles, func(i, j int) bool { return handles[i].LastUsed.After(handles[j].LastUsed) })
+	return handles
+}
+
+// lookupResultHandle returns the live handle stored by the session under id.
+// The caller must hold resultHandlesMu.
+func lookupResultHandle(sessionID, id string) (*resultHandle, error) {
+	handle, ok := resultHandles[id]
+	if !ok || handle.SessionID != sessionID {
+		return nil, fmt.Errorf("unknown result handle: %s", id)
+	}
+	now := handleNow()
+	if now.Sub(handle.CreatedAt) >= resultHandleTTL {
+		delete(resultHandles, id)
+		return nil, fmt.Errorf("result handle %s has expired", id)
+	}
+	handle.LastUsed = now
+	return handle, nil
+}
+
+// loadResultHandle returns a result stored by the session under id
+func loadResultHandle(sessionID, id string) (interface{}, error) {
+	resultHandlesMu.Lock()
+	defer resultHandlesMu.Unlock()
+
+	handle, err := lookupResultHandle(sessionID, id)
+	if err != nil {
+		return nil, err
+	}
+	return handle.Value, nil
+}
+
+// pageResultHandle returns up to pageSize entries of a stored list result,
+// starting at offset or, when offset is negative, where the previous page ended
+func pageResultHandle(sessionID, id string, offset, pageSize int) (*types.ResultPage, error) {
+	if pageSize <= 0 {
+		pageSize = defaultHandlePageSize
+	}
+	if pageSize > maxHandlePageSize {
+		pageSize = maxHandlePageSize
+	}
+
+	resultHandlesMu.Lock()
+	defer resultHandlesMu.Unlock()
+
+	handle, err := lookupResultHandle(sessionID, id)
+	if err != nil {
+		return nil, err
+	}
+	value, err := normalizeJSON(handle.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode result %s: %w", id, err)
+	}
+	entries, ok := diffEntries(value)
+	if !ok {
+		return nil, fmt.Errorf("result %s is not a list; use transform to select a list first", id)
+	}
+
+	if offset < 0 {
+		offset = handle.Cursor
+	}
+	if offset > len(entries) {
+		offset = len(entries)
+	}
+	end := offset + pageSize
+	if end > len(entries) {
+		end = len(entries)
+	}
+	handle.Cursor = end
+
+	page := &types.ResultPage{
+		ResultHandle: id,
+		Offset:       offset,
+		Count:        end - offset,
+		Total:        len(entries),
+		Done:         end == len(entries),
+		Entries:      entries[offset:end],
+	}
+	if !page.Done {
+		page.NextOffset = end
+	}
+	return page, nil
+}
+
+// summarizeResultHandle describes a stored result that is too large to return
+// inline: its size and shape, the fields of its entries and a short preview
+func summarizeResultHandle(id, tool string, value interface{}, size int) *types.ResultHandleSummary {
+	summary := &types.ResultHandleSummary{
+		ResultHandle: id,
+		Tool:         tool,
+		Bytes:        size,
+		ExpiresAt:    handleNow().Add(resultHandleTTL).UTC().Format(time.RFC3339),
+		Hint: fmt.Sprintf("The result is too large to return inline. Pass handle %s to the utility tool: nextPage reads its entries, "+
+			"transform selects fields, export returns CSV or JSON, and diff compares it with another result.", id),
+	}
+
+	generic, err := normalizeJSON(value)
+	if err != nil {
+		return summary
+	}
+	if fields, ok := generic.(map[string]interface{}); ok {
+		if total, ok := fields["totalResults"].(float64); ok {
+			summary.TotalResults = int(total)
+		}
+		summary.Fields = sortedKeys(fields)
+	}
+
+	entries, ok := diffEntries(generic)
+	if !ok {
+		return summary
+	}
+	summary.Entries = len(entries)
+	summary.Fields = nil
+	for i, entry := range entries {
+		if i == handlePreviewEntries {
+			break
+		}
+		if fields, ok := entry.(map[string]interface{}); ok && i == 0 {
+			summary.Fields = sortedKeys(fields)
+		}
+		if compacted, keep := compactValue(entry); keep {
+			summary.Preview = append(summary.Preview, compacted)
+		}
+	}
+	return summary
+}
+
+// sortedKeys returns the keys of a JSON object in order
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
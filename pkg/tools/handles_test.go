@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// largeResult returns a resource list well above the handle threshold
+func largeResult() map[string]interface{} {
+	results := make([]map[string]interface{}, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		results = append(results, map[string]interface{}{
+			"id":       fmt.Sprintf("r%d", i),
+			"hostName": fmt.Sprintf("web-%03d.example.com", i),
+			"state":    "active",
+		})
+	}
+	return map[string]interface{}{"results": results, "totalResults": 1000}
+}
+
+func TestLargeResultReturnedAsHandle(t *testing.T) {
+	ctx := contextWithSession("handle-session")
+	req := createTestRequest(map[string]interface{}{"action": "search"})
+	req.Params.Name = "resources"
+
+	res, err := respondJSON(ctx, req, largeResult())
+	if err != nil || len(res.Content) != 1 {
+		t.Fatalf("Expected a single summary, got %+v (%v)", res, err)
+	}
+	var summary types.ResultHandleSummary
+	if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &summary); err != nil {
+		t.Fatalf("Expected a handle summary: %v", err)
+	}
+	if !strings.HasPrefix(summary.ResultHandle, "rh-") || summary.Entries != 1000 || summary.TotalResults != 1000 || len(summary.Preview) != handlePreviewEntries {
+		t.Fatalf("Unexpected summary: %+v", summary)
+	}
+
+	// nextPage continues where the previous page ended
+	pageReq := createTestRequest(map[string]interface{}{"action": "nextPage", "handle": summary.ResultHandle, "pageSize": 400})
+	var page types.ResultPage
+	for _, wantOffset := range []int{0, 400, 800} {
+		res, err := UtilityToolHandler(ctx, pageReq)
+		if err != nil || res.IsError {
+			t.Fatalf("nextPage failed: %v %+v", err, res)
+		}
+		if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &page); err != nil {
+			t.Fatalf("Invalid page: %v", err)
+		}
+		if page.Offset != wantOffset {
+			t.Errorf("Expected page at offset %d, got %d", wantOffset, page.Offset)
+		}
+	}
+	if !page.Done || page.Count != 200 || page.Total != 1000 {
+		t.Errorf("Expected a final page of 200 entries, got %+v", page)
+	}
+
+	// export applies the transform and returns the whole result as text
+	res, _ = UtilityToolHandler(ctx, createTestRequest(map[string]interface{}{
+		"action": "export", "handle": summary.ResultHandle, "format": "csv", "transform": ".results[] | {id, hostName}", "inline": true,
+	}))
+	if res.IsError {
+		t.Fatalf("export failed: %+v", res)
+	}
+	lines := strings.Split(strings.TrimSpace(res.Content[0].(mcp.TextContent).Text), "\n")
+	if len(lines) != 1001 || lines[0] != "hostName,id" || lines[1] != "web-000.example.com,r0" {
+		t.Errorf("Unexpected csv export: %d lines, starting %q", len(lines), lines[:2])
+	}
+}
+
+func TestHandleEvictionKeepsRecentlyUsed(t *testing.T) {
+	ctx := contextWithSession("eviction-session")
+	first := storeResultHandle("eviction-session", "resources", []string{"a"}, 10)
+	for i := 0; i < maxHandlesPerSession; i++ {
+		// Reading the first handle keeps it from being evicted
+		if _, err := loadResultHandle("eviction-session", first); err != nil {
+			t.Fatalf("Expected %s to be kept after %d stores: %v", first, i, err)
+		}
+		storeResultHandle("eviction-session", "resources", []string{"b"}, 10)
+	}
+
+	res, _ := UtilityToolHandler(ctx, createTestRequest(map[string]interface{}{"action": "transform", "handle": first, "transform": ".[0]"}))
+	if res.IsError || res.Content[0].(mcp.TextContent).Text != `"a"` {
+		t.Errorf("Expected transform of the stored result, got %+v", res)
+	}
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// PreHook runs before a tool handler. It may modify the request (validation,
+// enrichment) or reject the call by returning an error.
+type PreHook func(ctx context.Context, req *mcp.CallToolRequest) error
+
+// PostHook runs after a tool handler. It may modify or replace the result
+// (redaction, summarization) or observe it (metrics). Returning an error
+// turns the call into an error result. Hooks that rewrite the text content
+// must rewrite or clear the result's StructuredContent too.
+type PostHook func(ctx context.Context, req mcp.CallToolRequest, result *mcp.CallToolResult) (*mcp.CallToolResult, error)
+
+// ToolHook is a named pair of pre and post hooks applied to tool calls. Either
+// hook may be nil. Tools limits the hook to the named tools; empty means all.
+type ToolHook struct {
+	Name  string
+	Tools []string
+	Pre   PreHook
+	Post  PostHook
+}
+
+// appliesTo reports whether the hook runs for the named tool
+func (h ToolHook) appliesTo(tool string) bool {
+	if len(h.Tools) == 0 {
+		return true
+	}
+	for _, name := range h.Tools {
+		if name == tool {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	toolHooksMu sync.RWMutex
+	toolHooks   []ToolHook
+)
+
+// RegisterToolHook adds a hook to the tool registry. Pre hooks run in
+// registration order; post hooks run in reverse, so the first hook registered
+// sees the request first and the result last.
+func RegisterToolHook(hook ToolHook) {
+	toolHooksMu.Lock()
+	defer toolHooksMu.Unlock()
+	toolHooks = append(toolHooks, hook)
+	common.GetLogger().Info("Registered tool hook: %s", hook.Name)
+}
+
+// ToolHooksMiddleware runs the registered hooks around each tool call
+func ToolHooksMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		toolHooksMu.RLock()
+		var hooks []ToolHook
+		for _, hook := range toolHooks {
+			if hook.appliesTo(req.Params.Name) {
+				hooks = append(hooks, hook)
+			}
+		}
+		toolHooksMu.RUnlock()
+
+		if len(hooks) == 0 {
+			return next(ctx, req)
+		}
+
+		for _, hook := range hooks {
+			if hook.Pre == nil {
+				continue
+			}
+			if err := hook.Pre(ctx, &req); err != nil {
+				common.GetLogger().Warn("Tool hook %s rejected %s: %v", hook.Name, req.Params.Name, err)
+				return hookErrorResult(hook.Name, err), nil
+			}
+		}
+
+		result, err := next(ctx, req)
+		if err != nil {
+			return result, err
+		}
+
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hook := hooks[i]
+			if hook.Post == nil {
+				continue
+			}
+			if result, err = hook.Post(ctx, req, result); err != nil {
+				common.GetLogger().Error("Tool hook %s failed for %s: %v", hook.Name, req.Params.Name, err)
+				return hookErrorResult(hook.Name, err), nil
+			}
+		}
+		return result, nil
+	}
+}
+
+// hookErrorResult reports a hook failure as a tool error
+func hookErrorResult(hook string, err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("%s: %v", hook, err)}},
+	}
+}
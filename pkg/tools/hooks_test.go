@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func withToolHooks(t *testing.T, hooks ...ToolHook) {
+	toolHooksMu.Lock()
+	saved := toolHooks
+	toolHooks = nil
+	toolHooksMu.Unlock()
+	t.Cleanup(func() {
+		toolHooksMu.Lock()
+		toolHooks = saved
+		toolHooksMu.Unlock()
+	})
+	for _, hook := range hooks {
+		RegisterToolHook(hook)
+	}
+}
+
+func echoHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText(fmt.Sprintf("%v", req.GetArguments()["value"])), nil
+}
+
+func callTool(name string, args map[string]interface{}) *mcp.CallToolResult {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	result, _ := ToolHooksMiddleware(echoHandler)(context.Background(), req)
+	return result
+}
+
+func TestToolHooksOrderAndScope(t *testing.T) {
+	var order []string
+	withToolHooks(t,
+		ToolHook{
+			Name: "enrich",
+			Pre: func(ctx context.Context, req *mcp.CallToolRequest) error {
+				order = append(order, "enrich.pre")
+				req.Params.Arguments = map[string]interface{}{"value": "enriched"}
+				return nil
+			},
+			Post: func(ctx context.Context, req mcp.CallToolRequest, result *mcp.CallToolResult) (*mcp.CallToolResult, error) {
+				order = append(order, "enrich.post")
+				return result, nil
+			},
+		},
+		ToolHook{
+			Name:  "redact",
+			Tools: []string{"alerts"},
+			Post: func(ctx context.Context, req mcp.CallToolRequest, result *mcp.CallToolResult) (*mcp.CallToolResult, error) {
+				order = append(order, "redact.post")
+				return mcp.NewToolResultText("[redacted]"), nil
+			},
+		},
+	)
+
+	result := callTool("alerts", map[string]interface{}{"value": "original"})
+	if text := result.Content[0].(mcp.TextContent).Text; text != "[redacted]" {
+		t.Errorf("Expected redacted result, got %q", text)
+	}
+	if got := strings.Join(order, ","); got != "enrich.pre,redact.post,enrich.post" {
+		t.Errorf("Unexpected hook order: %s", got)
+	}
+
+	result = callTool("resources", map[string]interface{}{"value": "original"})
+	if text := result.Content[0].(mcp.TextContent).Text; text != "enriched" {
+		t.Errorf("Expected the alerts-only hook to be skipped, got %q", text)
+	}
+}
+
+func TestToolHookRejection(t *testing.T) {
+	called := false
+	withToolHooks(t, ToolHook{
+		Name: "validate",
+		Pre: func(ctx context.Context, req *mcp.CallToolRequest) error {
+			if req.GetArguments()["value"] == "" {
+				return fmt.Errorf("value is required")
+			}
+			return nil
+		},
+		Post: func(ctx context.Context, req mcp.CallToolRequest, result *mcp.CallToolResult) (*mcp.CallToolResult, error) {
+			called = true
+			return result, nil
+		},
+	})
+
+	result := callTool("resources", map[string]interface{}{"value": ""})
+	if !result.IsError || result.Content[0].(mcp.TextContent).Text != "validate: value is required" {
+		t.Errorf("Expected rejection, got %+v", result)
+	}
+	if called {
+		t.Error("Post hook should not run for a rejected call")
+	}
+}
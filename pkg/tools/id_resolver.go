@@ -0,0 +1,298 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/httperr"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// resolvedIDTTL is how long a resolved identifier is remembered
+	resolvedIDTTL = 10 * time.Minute
+	// maxResolvedIDs bounds the remembered identifiers
+	maxResolvedIDs = 1000
+	// resolverPageSize is the page size of the searches that look up an
+	// identifier; more than one match is ambiguous anyway
+	resolverPageSize = 5
+)
+
+// identifierKind is one way OpsRamp APIs refer to a resource
+type identifierKind struct {
+	name string
+	// search sets the resource search parameter for an identifier of this
+	// kind; the id kind is looked up directly instead
+	search func(params *types.ResourceSearchParams, value string)
+	// value returns the resource's identifier of this kind
+	value func(resource types.Resource) string
+}
+
+// identifierKinds are the identifier kinds a resource is looked up by, in
+// the order they are tried. Searches may match loosely, so a resource only
+// matches when its identifier equals the input.
+var identifierKinds = []identifierKind{
+	{
+		name:  "id",
+		value: func(r types.Resource) string { return r.ID },
+	},
+	{
+		name:   "systemUID",
+		search: func(p *types.ResourceSearchParams, v string) { p.SystemUID = v },
+		value:  func(r types.Resource) string { return r.SystemUID },
+	},
+	{
+		name:   "providerUID",
+		search: func(p *types.ResourceSearchParams, v string) { p.ProviderUID = v },
+		value:  func(r types.Resource) string { return r.ProviderUID },
+	},
+	{
+		name:   "identity",
+		search: func(p *types.ResourceSearchParams, v string) { p.QueryString = "identity:" + v },
+		value:  func(r types.Resource) string { return r.Identity },
+	},
+	{
+		name:   "deviceUniqueId",
+		search: func(p *types.ResourceSearchParams, v string) { p.QueryString = "deviceUniqueId:" + v },
+		value: func(r types.Resource) string {
+			id, _ := r.Properties["deviceUniqueId"].(string)
+			return id
+		},
+	},
+	{
+		name:   "serialNumber",
+		search: func(p *types.ResourceSearchParams, v string) { p.SerialNumber = v },
+		value:  func(r types.Resource) string { return r.SerialNumber },
+	},
+	{
+		name:   "dnsName",
+		search: func(p *types.ResourceSearchParams, v string) { p.DNSName = v },
+		value:  func(r types.Resource) string { return r.DNSName },
+	},
+	{
+		name:   "hostName",
+		search: func(p *types.ResourceSearchParams, v string) { p.HostName = v },
+		value:  func(r types.Resource) string { return r.HostName },
+	},
+	{
+		name:   "ipAddress",
+		search: func(p *types.ResourceSearchParams, v string) { p.IPAddress = v },
+		value:  func(r types.Resource) string { return r.IPAddress },
+	},
+}
+
+// resourceIDReads are the resources actions that read the resource their id
+// argument names, retried with the resolved ID when it is not found
+var resourceIDReads = map[string]bool{
+	"get": true, "getDetailed": true, "getMinimal": true,
+	"getMetrics": true, "listMetricInstances": true, "getAvailability": true,
+	"impactAnalysis": true, "scorecard": true, "timelineReport": true, "getTags": true,
+}
+
+// resourceIDWrites are the resources actions that change the resource their
+// id argument names. They are never retried with a resolved ID, which may
+// name another resource than the caller meant; when the resource is not
+// found, the error gives the resolved ID for the caller to retry with.
+var resourceIDWrites = map[string]bool{
+	"update": true, "changeState": true, "delete": true,
+	"updateTags": true, "addTag": true, "removeTag": true,
+}
+
+// resolvedID is a remembered resolution
+type resolvedID struct {
+	resolution types.ResourceIDResolution
+	expires    time.Time
+}
+
+// IDResolver translates the identifiers OpsRamp APIs use for a resource
+// into its resource ID, remembering recent resolutions per tenant
+type IDResolver struct {
+	mu       sync.Mutex
+	resolved map[string]resolvedID
+	now      func() time.Time
+}
+
+// NewIDResolver creates an empty resolver
+func NewIDResolver() *IDResolver {
+	return &IDResolver{resolved: map[string]resolvedID{}, now: time.Now}
+}
+
+// resourceIDs is the resolver shared by the tools
+var resourceIDs = NewIDResolver()
+
+// Resolve finds the resource an identifier refers to in a tenant. With a
+// kind, only that kind of identifier is tried; otherwise every kind is tried
+// in turn.
+func (r *IDResolver) Resolve(ctx context.Context, api ResourcesAPI, tenant, value, kind string) (*types.ResourceIDResolution, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, errors.New("id is required for resolveId")
+	}
+	kinds := identifierKinds
+	if kind != "" {
+		kinds = nil
+		for _, candidate := range identifierKinds {
+			if strings.EqualFold(candidate.name, kind) {
+				kinds = []identifierKind{candidate}
+			}
+		}
+		if kinds == nil {
+			return nil, fmt.Errorf("unknown identifier kind %q: use one of %s", kind, strings.Join(identifierKindNames(), ", "))
+		}
+	}
+
+	return r.resolve(ctx, api, tenant, value, kinds)
+}
+
+// resolve tries the given kinds of identifier in turn
+func (r *IDResolver) resolve(ctx context.Context, api ResourcesAPI, tenant, value string, kinds []identifierKind) (*types.ResourceIDResolution, error) {
+	if tenant == "" {
+		tenant = client.DefaultTenant
+	}
+	key := tenant + "\x00" + value
+	r.mu.Lock()
+	entry, ok := r.resolved[key]
+	r.mu.Unlock()
+	if ok && r.now().Before(entry.expires) && slices.ContainsFunc(kinds, func(kind identifierKind) bool { return kind.name == entry.resolution.Kind }) {
+		resolution := entry.resolution
+		resolution.Cached = true
+		return &resolution, nil
+	}
+
+	for _, candidate := range kinds {
+		resource, err := lookupIdentifier(ctx, api, candidate, value)
+		if err != nil {
+			return nil, err
+		}
+		if resource == nil {
+			continue
+		}
+		resolution := types.ResourceIDResolution{
+			Input:       value,
+			Kind:        candidate.name,
+			ResourceID:  resource.ID,
+			Name:        resource.Name,
+			Identifiers: resourceIdentifiers(*resource),
+		}
+		r.remember(key, resolution)
+		return &resolution, nil
+	}
+
+	tried := make([]string, len(kinds))
+	for i, candidate := range kinds {
+		tried[i] = candidate.name
+	}
+	return nil, types.NewResourceError(types.ResourceErrorTypeNotFound, "RESOURCE_NOT_FOUND",
+		fmt.Sprintf("no resource has the identifier %q (tried %s)", value, strings.Join(tried, ", ")))
+}
+
+// remember stores a resolution, making room by dropping expired entries and
+// then arbitrary ones
+func (r *IDResolver) remember(key string, resolution types.ResourceIDResolution) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	if len(r.resolved) >= maxResolvedIDs {
+		for stale, entry := range r.resolved {
+			if !now.Before(entry.expires) || len(r.resolved) >= maxResolvedIDs {
+				delete(r.resolved, stale)
+			}
+		}
+	}
+	r.resolved[key] = resolvedID{resolution: resolution, expires: now.Add(resolvedIDTTL)}
+}
+
+// lookupIdentifier returns the resource whose identifier of a kind is
+// value, or nil if there is none. More than one match is an error.
+func lookupIdentifier(ctx context.Context, api ResourcesAPI, kind identifierKind, value string) (*types.Resource, error) {
+	if kind.search == nil {
+		if client.ValidateID("resource", value) != nil {
+			return nil, nil
+		}
+		resource, err := api.Get(ctx, value)
+		if err != nil {
+			// Other kinds of identifier are often rejected as malformed IDs
+			if isNotFoundError(err) || isValidationError(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return resource, nil
+	}
+	if kind.name == "ipAddress" && net.ParseIP(value) == nil {
+		return nil, nil
+	}
+
+	params := types.ResourceSearchParams{PageNo: 1, PageSize: resolverPageSize}
+	kind.search(&params, value)
+	response, err := api.Search(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s %s: %w", kind.name, value, err)
+	}
+	var matches []types.Resource
+	for _, resource := range response.Results {
+		if strings.EqualFold(kind.value(resource), value) {
+			matches = append(matches, resource)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+	return nil, fmt.Errorf("%s %s is ambiguous: it matches resources %s; pass one of their IDs", kind.name, value, strings.Join(ids, ", "))
+}
+
+// resourceIdentifiers returns the non-empty identifiers of a resource by kind
+func resourceIdentifiers(resource types.Resource) map[string]string {
+	identifiers := map[string]string{}
+	for _, kind := range identifierKinds {
+		if value := kind.value(resource); value != "" {
+			identifiers[kind.name] = value
+		}
+	}
+	return identifiers
+}
+
+// identifierKindNames lists the identifier kinds, sorted
+func identifierKindNames() []string {
+	names := make([]string, len(identifierKinds))
+	for i, kind := range identifierKinds {
+		names[i] = kind.name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isNotFoundError reports whether a resource API call failed because the
+// resource does not exist
+func isNotFoundError(err error) bool {
+	var resourceErr *types.ResourceError
+	if errors.As(err, &resourceErr) {
+		return resourceErr.Type == types.ResourceErrorTypeNotFound
+	}
+	return httperr.IsNotFound(err)
+}
+
+// isValidationError reports whether a resource API call was rejected as
+// malformed
+func isValidationError(err error) bool {
+	var resourceErr *types.ResourceError
+	if errors.As(err, &resourceErr) {
+		return resourceErr.Type == types.ResourceErrorTypeValidation
+	}
+	return httperr.Classify(err) == httperr.KindValidation
+}
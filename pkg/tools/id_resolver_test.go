@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// newIdentifiedResourcesAPI returns a fake holding two resources, looked up
+// by ID with Get and by any other identifier with Search
+func newIdentifiedResourcesAPI() *FakeResourcesAPI {
+	resources := []types.Resource{
+		{ID: "6f1c2a9e-0000-4000-8000-000000000001", Name: "web-01", HostName: "web-01", Identity: "i-0abc", SystemUID: "4C4C4544-0031", IPAddress: "10.0.0.1"},
+		{ID: "6f1c2a9e-0000-4000-8000-000000000002", Name: "web-02", HostName: "web-02", Identity: "i-0def", IPAddress: "10.0.0.2",
+			Properties: map[string]interface{}{"deviceUniqueId": "dev-2"}},
+	}
+	api := &FakeResourcesAPI{}
+	api.GetStub = func(ctx context.Context, id string) (*types.Resource, error) {
+		for _, resource := range resources {
+			if resource.ID == id {
+				return &resource, nil
+			}
+		}
+		return nil, types.NewResourceError(types.ResourceErrorTypeNotFound, "RESOURCE_NOT_FOUND", "resource "+id+" not found")
+	}
+	// The search matches loosely, as OpsRamp's does
+	api.SearchReturns(&types.ResourceSearchResponse{Results: resources, TotalResults: len(resources)}, nil)
+	return api
+}
+
+func TestResolveResourceID(t *testing.T) {
+	tests := []struct {
+		input, kind string
+		wantKind    string
+		wantID      string
+	}{
+		{"6f1c2a9e-0000-4000-8000-000000000002", "", "id", "6f1c2a9e-0000-4000-8000-000000000002"},
+		{"i-0abc", "", "identity", "6f1c2a9e-0000-4000-8000-000000000001"},
+		{"4c4c4544-0031", "", "systemUID", "6f1c2a9e-0000-4000-8000-000000000001"},
+		{"dev-2", "deviceUniqueId", "deviceUniqueId", "6f1c2a9e-0000-4000-8000-000000000002"},
+		{"10.0.0.2", "", "ipAddress", "6f1c2a9e-0000-4000-8000-000000000002"},
+		{"WEB-01", "hostname", "hostName", "6f1c2a9e-0000-4000-8000-000000000001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			resolution, err := NewIDResolver().Resolve(context.Background(), newIdentifiedResourcesAPI(), "", tt.input, tt.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resolution.Kind != tt.wantKind || resolution.ResourceID != tt.wantID {
+				t.Errorf("Expected %s %s, got %s %s", tt.wantKind, tt.wantID, resolution.Kind, resolution.ResourceID)
+			}
+		})
+	}
+
+	resolver := NewIDResolver()
+	api := newIdentifiedResourcesAPI()
+	resolution, err := resolver.Resolve(context.Background(), api, "", "i-0def", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolution.Identifiers["hostName"] != "web-02" || resolution.Identifiers["deviceUniqueId"] != "dev-2" || resolution.Cached {
+		t.Errorf("Expected every identifier of web-02, got %+v", resolution)
+	}
+	searches := api.SearchCallCount()
+	if resolution, err = resolver.Resolve(context.Background(), api, "", "i-0def", ""); err != nil || !resolution.Cached {
+		t.Errorf("Expected the second resolution to be remembered, got %+v, %v", resolution, err)
+	}
+	if api.SearchCallCount() != searches {
+		t.Error("Expected no lookup for a remembered identifier")
+	}
+	if _, err := resolver.Resolve(context.Background(), api, "acme", "i-0def", ""); err != nil || api.SearchCallCount() == searches {
+		t.Error("Expected identifiers to be remembered per tenant")
+	}
+
+	if _, err := resolver.Resolve(context.Background(), api, "", "nope", ""); !isNotFoundError(err) {
+		t.Errorf("Expected not found for an unknown identifier, got %v", err)
+	}
+	if _, err := resolver.Resolve(context.Background(), api, "", "web-01", "serial"); err == nil || !strings.Contains(err.Error(), "unknown identifier kind") {
+		t.Errorf("Expected an unknown kind to be rejected, got %v", err)
+	}
+}
+
+func TestResolveResourceIDAmbiguous(t *testing.T) {
+	api := &FakeResourcesAPI{}
+	api.SearchReturns(&types.ResourceSearchResponse{Results: []types.Resource{
+		{ID: "a", HostName: "db"}, {ID: "b", HostName: "db"},
+	}}, nil)
+	_, err := NewIDResolver().Resolve(context.Background(), api, "", "db", "hostName")
+	if err == nil || !strings.Contains(err.Error(), "ambiguous") || !strings.Contains(err.Error(), "a, b") {
+		t.Errorf("Expected an ambiguous host name to be rejected with its matches, got %v", err)
+	}
+}
+
+func TestResourcesActionsAcceptOtherIdentifiers(t *testing.T) {
+	api := newIdentifiedResourcesAPI()
+	result, err := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "get", "id": "i-0abc",
+	}), api)
+	if err != nil || result.IsError {
+		t.Fatalf("Expected the identity to be resolved, got %v: %s", err, resultText(result))
+	}
+	var resource types.Resource
+	if err := json.Unmarshal([]byte(resultText(result)), &resource); err != nil {
+		t.Fatal(err)
+	}
+	if resource.HostName != "web-01" {
+		t.Errorf("Expected web-01, got %+v", resource)
+	}
+	if _, id := api.GetArgsForCall(api.GetCallCount() - 1); id != "6f1c2a9e-0000-4000-8000-000000000001" {
+		t.Errorf("Expected the retry to use the resource ID, got %s", id)
+	}
+
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "get", "id": "missing",
+	}), api)
+	if !result.IsError || !strings.Contains(resultText(result), "missing not found") {
+		t.Errorf("Expected the original error for an unknown identifier, got %s", resultText(result))
+	}
+}
+
+func TestResourcesWritesAreNotRetriedWithResolvedID(t *testing.T) {
+	api := newIdentifiedResourcesAPI()
+	api.DeleteStub = func(ctx context.Context, id string) error {
+		return types.NewResourceError(types.ResourceErrorTypeNotFound, "RESOURCE_NOT_FOUND", "resource "+id+" not found")
+	}
+	result, err := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "delete", "id": "i-0abc",
+	}), api)
+	if err != nil || !result.IsError {
+		t.Fatalf("Expected delete of an unknown ID to fail, got %v: %s", err, resultText(result))
+	}
+	if api.DeleteCallCount() != 1 {
+		t.Fatalf("Expected one Delete call, got %d", api.DeleteCallCount())
+	}
+	if _, id := api.DeleteArgsForCall(0); id != "i-0abc" {
+		t.Errorf("Expected Delete to be called only with the given ID, got %s", id)
+	}
+	if text := resultText(result); !strings.Contains(text, "i-0abc not found") || !strings.Contains(text, "with id 6f1c2a9e-0000-4000-8000-000000000001") {
+		t.Errorf("Expected the not-found error to give the resolved resource ID, got %s", text)
+	}
+}
@@ -0,0 +1,265 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// defaultImpactDepth is how many topology hops downstream impact analysis follows
+	defaultImpactDepth = 3
+	// maxImpactDepth bounds the requested traversal depth
+	maxImpactDepth = 6
+	// maxImpactedResources bounds the number of resources visited during traversal
+	maxImpactedResources = 200
+	// impactAlertBatchSize is the number of resources per open-alert query
+	impactAlertBatchSize = 50
+)
+
+// severityRank orders alert states from least to most severe
+var severityRank = map[string]int{
+	"OK":       0,
+	"INFO":     1,
+	"WARNING":  2,
+	"CRITICAL": 3,
+}
+
+// AlertsProvider is implemented by resources APIs that can also query alerts
+type AlertsProvider interface {
+	Alerts() AlertsAPI
+}
+
+// ImpactAnalyzer combines topology, service group membership and open alerts
+// to determine what an outage of a resource affects
+type ImpactAnalyzer struct {
+	resources ResourcesAPI
+	alerts    AlertsAPI
+	locale    *Locale
+	logger    *common.CustomLogger
+}
+
+// NewImpactAnalyzer creates a new ImpactAnalyzer
+func NewImpactAnalyzer(resources ResourcesAPI, alerts AlertsAPI) *ImpactAnalyzer {
+	return &ImpactAnalyzer{
+		resources: resources,
+		alerts:    alerts,
+		locale:    locales[defaultLocale],
+		logger:    common.GetLogger(),
+	}
+}
+
+// Analyze lists the downstream resources and services impacted by an outage
+// of the given resource, with severity rollups of their open alerts
+func (a *ImpactAnalyzer) Analyze(ctx context.Context, resourceID string, depth int) (*types.ImpactAnalysis, error) {
+	if depth <= 0 {
+		depth = defaultImpactDepth
+	}
+	if depth > maxImpactDepth {
+		depth = maxImpactDepth
+	}
+
+	root, err := a.resources.GetMinimal(ctx, resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource %s: %w", resourceID, err)
+	}
+
+	analysis := &types.ImpactAnalysis{
+		ResourceID:        resourceID,
+		ResourceName:      root.Name,
+		ImpactedResources: []types.ImpactedResource{},
+		ImpactedServices:  []types.ImpactedService{},
+		SeverityRollup:    map[string]int{},
+	}
+
+	// Walk the topology breadth-first to find downstream dependents
+	impacted, truncated, err := a.walkDownstream(ctx, resourceID, depth)
+	if err != nil {
+		return nil, err
+	}
+	analysis.Truncated = truncated
+
+	// Attach open alert counts and severities
+	allIDs := []string{resourceID}
+	for _, r := range impacted {
+		allIDs = append(allIDs, r.ID)
+	}
+	alertsByResource, err := a.openAlertsByResource(ctx, allIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range impacted {
+		alerts := alertsByResource[impacted[i].ID]
+		impacted[i].OpenAlerts = len(alerts)
+		impacted[i].HighestSeverity = highestSeverity(alerts)
+	}
+	for _, alerts := range alertsByResource {
+		for _, alert := range alerts {
+			analysis.SeverityRollup[strings.ToUpper(alert.CurrentState)]++
+		}
+	}
+	analysis.HighestSeverity = highestSeverityOf(analysis.SeverityRollup)
+	analysis.ImpactedResources = impacted
+
+	// Resolve service groups containing the resource or any dependent
+	services, err := a.impactedServices(ctx, allIDs)
+	if err != nil {
+		return nil, err
+	}
+	analysis.ImpactedServices = services
+
+	analysis.Summary = a.locale.T("impact.summary", displayName(root.Name, resourceID), len(impacted), len(services),
+		sumCounts(analysis.SeverityRollup), defaultString(analysis.HighestSeverity, a.locale.T("none")))
+
+	return analysis, nil
+}
+
+// walkDownstream returns resources that depend on the given resource, up to depth hops away
+func (a *ImpactAnalyzer) walkDownstream(ctx context.Context, resourceID string, depth int) ([]types.ImpactedResource, bool, error) {
+	visited := map[string]bool{resourceID: true}
+	frontier := []string{resourceID}
+	impacted := make([]types.ImpactedResource, 0)
+
+	for level := 1; level <= depth && len(frontier) > 0; level++ {
+		var next []string
+		for _, id := range frontier {
+			relationships, err := a.resources.GetRelationships(ctx, id)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to get topology for resource %s: %w", id, err)
+			}
+
+			for _, rel := range relationships {
+				// Only follow edges where the current resource is the one depended upon
+				if rel.SourceID != id || visited[rel.TargetID] {
+					continue
+				}
+				if len(impacted) >= maxImpactedResources {
+					a.logger.Warn("Impact analysis for %s truncated at %d resources", resourceID, maxImpactedResources)
+					return impacted, true, nil
+				}
+				visited[rel.TargetID] = true
+				impacted = append(impacted, types.ImpactedResource{
+					ID:    rel.TargetID,
+					Name:  rel.TargetName,
+					Type:  rel.TargetType,
+					Depth: level,
+					Via:   rel.RelationshipType,
+				})
+				next = append(next, rel.TargetID)
+			}
+		}
+		frontier = next
+	}
+
+	return impacted, false, nil
+}
+
+// openAlertsByResource fetches open alerts for the given resources, grouped by resource ID
+func (a *ImpactAnalyzer) openAlertsByResource(ctx context.Context, ids []string) (map[string][]types.AlertDetails, error) {
+	grouped := make(map[string][]types.AlertDetails)
+
+	for start := 0; start < len(ids); start += impactAlertBatchSize {
+		end := min(start+impactAlertBatchSize, len(ids))
+		response, err := a.alerts.Search(ctx, types.AlertSearchParams{
+			QueryString: openAlertsQuery(ids[start:end]),
+			PageSize:    500,
+			PageNo:      1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get open alerts: %w", err)
+		}
+		for _, alert := range response.Results {
+			if alert.Resource != nil {
+				grouped[alert.Resource.ID] = append(grouped[alert.Resource.ID], alert)
+			}
+		}
+	}
+
+	return grouped, nil
+}
+
+// impactedServices returns the service groups containing any of the given resources
+func (a *ImpactAnalyzer) impactedServices(ctx context.Context, ids []string) ([]types.ImpactedService, error) {
+	byID := make(map[string]*types.ImpactedService)
+
+	for _, id := range ids {
+		groups, err := a.resources.GetServiceGroups(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service groups for resource %s: %w", id, err)
+		}
+		for _, group := range groups {
+			service, ok := byID[group.ID]
+			if !ok {
+				service = &types.ImpactedService{ID: group.ID, Name: group.Name, ImpactedResources: []string{}}
+				byID[group.ID] = service
+			}
+			service.ImpactedResources = append(service.ImpactedResources, id)
+		}
+	}
+
+	services := make([]types.ImpactedService, 0, len(byID))
+	for _, service := range byID {
+		services = append(services, *service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		if len(services[i].ImpactedResources) != len(services[j].ImpactedResources) {
+			return len(services[i].ImpactedResources) > len(services[j].ImpactedResources)
+		}
+		return services[i].Name < services[j].Name
+	})
+	return services, nil
+}
+
+// openAlertsQuery builds the alert search query for open alerts on the given resources
+func openAlertsQuery(resourceIDs []string) string {
+	return fmt.Sprintf("resources.id:%s+currentState:CRITICAL,WARNING", strings.Join(resourceIDs, ","))
+}
+
+// highestSeverity returns the most severe state among the alerts
+func highestSeverity(alerts []types.AlertDetails) string {
+	highest := ""
+	for _, alert := range alerts {
+		state := strings.ToUpper(alert.CurrentState)
+		if highest == "" || severityRank[state] > severityRank[highest] {
+			highest = state
+		}
+	}
+	return highest
+}
+
+// highestSeverityOf returns the most severe state present in a rollup
+func highestSeverityOf(rollup map[string]int) string {
+	highest := ""
+	for state, count := range rollup {
+		if count > 0 && (highest == "" || severityRank[state] > severityRank[highest]) {
+			highest = state
+		}
+	}
+	return highest
+}
+
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
+
+func displayName(name, id string) string {
+	if name != "" {
+		return name
+	}
+	return id
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
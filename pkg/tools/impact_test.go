@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// topologyResourcesAPI serves a fixed topology; unused ResourcesAPI methods panic
+type topologyResourcesAPI struct {
+	ResourcesAPI
+	edges    []types.ResourceRelationship
+	services map[string][]types.ServiceGroup
+}
+
+func (m *topologyResourcesAPI) GetMinimal(ctx context.Context, id string) (*types.ResourceMinimal, error) {
+	return &types.ResourceMinimal{ID: id, Name: "hv-" + id}, nil
+}
+
+func (m *topologyResourcesAPI) GetRelationships(ctx context.Context, id string) ([]types.ResourceRelationship, error) {
+	var out []types.ResourceRelationship
+	for _, edge := range m.edges {
+		if edge.SourceID == id || edge.TargetID == id {
+			out = append(out, edge)
+		}
+	}
+	return out, nil
+}
+
+func (m *topologyResourcesAPI) GetServiceGroups(ctx context.Context, id string) ([]types.ServiceGroup, error) {
+	return m.services[id], nil
+}
+
+func TestImpactAnalysis(t *testing.T) {
+	// hv1 hosts vm1 and vm2; vm1 runs app1; unrelated-host hosts hv1 (upstream, not impacted)
+	resources := &topologyResourcesAPI{
+		edges: []types.ResourceRelationship{
+			{SourceID: "hv1", TargetID: "vm1", TargetName: "vm-1", RelationshipType: "hosts"},
+			{SourceID: "hv1", TargetID: "vm2", TargetName: "vm-2", RelationshipType: "hosts"},
+			{SourceID: "vm1", TargetID: "app1", TargetName: "app-1", RelationshipType: "runs"},
+			{SourceID: "rack", TargetID: "hv1", RelationshipType: "contains"},
+		},
+		services: map[string][]types.ServiceGroup{
+			"vm1":  {{ID: "sg-web", Name: "Web"}},
+			"app1": {{ID: "sg-web", Name: "Web"}, {ID: "sg-pay", Name: "Payments"}},
+		},
+	}
+	alerts := &mockAlertsAPI{open: []types.AlertDetails{
+		{ID: "a1", CurrentState: "WARNING", Resource: &types.AlertResource{ID: "vm1"}},
+		{ID: "a2", CurrentState: "CRITICAL", Resource: &types.AlertResource{ID: "app1"}},
+		{ID: "a3", CurrentState: "CRITICAL", Resource: &types.AlertResource{ID: "hv1"}},
+	}}
+
+	analysis, err := NewImpactAnalyzer(resources, alerts).Analyze(context.Background(), "hv1", 0)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if len(analysis.ImpactedResources) != 3 {
+		t.Fatalf("Expected 3 downstream resources, got %+v", analysis.ImpactedResources)
+	}
+	for _, r := range analysis.ImpactedResources {
+		if r.ID == "rack" {
+			t.Error("Upstream resource must not be reported as impacted")
+		}
+		if r.ID == "app1" && (r.Depth != 2 || r.HighestSeverity != "CRITICAL") {
+			t.Errorf("Unexpected app1 impact: %+v", r)
+		}
+	}
+
+	if len(analysis.ImpactedServices) != 2 || analysis.ImpactedServices[0].ID != "sg-web" {
+		t.Errorf("Expected Web (2 resources) then Payments, got %+v", analysis.ImpactedServices)
+	}
+	if analysis.SeverityRollup["CRITICAL"] != 2 || analysis.SeverityRollup["WARNING"] != 1 {
+		t.Errorf("Unexpected severity rollup: %v", analysis.SeverityRollup)
+	}
+	if analysis.HighestSeverity != "CRITICAL" {
+		t.Errorf("Expected CRITICAL highest severity, got %s", analysis.HighestSeverity)
+	}
+
+	// Depth limits the traversal
+	shallow, err := NewImpactAnalyzer(resources, alerts).Analyze(context.Background(), "hv1", 1)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(shallow.ImpactedResources) != 2 {
+		t.Errorf("Expected 2 resources at depth 1, got %d", len(shallow.ImpactedResources))
+	}
+}
@@ -0,0 +1,23 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/pkg/crash"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+// InFlightMiddleware records each tool call while it runs, so crash
+// snapshots list the calls in progress, and writes a crash snapshot when a
+// handler panics
+func InFlightMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		done := session.BeginCall(ctx, req.Params.Name, req.GetString("action", ""))
+		defer done()
+		// Runs before done, so the panicking call is still listed
+		defer crash.Recover()
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/locks"
+)
+
+// toolSummaries is the one-line guidance given for each tool in the
+// initialize instructions
+var toolSummaries = map[string]string{
+	"integrations": "manage installed integrations and browse integration types",
+	"resources":    "search, inspect and manage monitored resources, device groups and metrics",
+	"alerts":       "search, acknowledge and resolve alerts, manage alert rules and preview who gets paged",
+	"usage":        "report license, resource count and API usage",
+	"clients":      "manage the client tenants of a partner",
+	"monitors":     "manage custom monitors and their assignments",
+	"metrics":      "find metric names and read metric time series across resources",
+	"session":      "set context variables reused by later calls in this session, and save or resume investigation workspaces",
+	"utility":      "page through, diff and export earlier results by handle",
+	"admin":        "validate credentials, onboard tenants, change log levels, inspect outbound notifications and stale sessions, and list the OpsRamp endpoints this server calls",
+}
+
+// ServerInstructions returns the initialize instructions describing what
+// this deployment can do: the registered tools and the backends serving
+// them, the guardrails applied to calls, and the configured tenants. A nil
+// config describes only the tools.
+func ServerInstructions(config *common.Config, tools []string) string {
+	lines := []string{"HPE OpsRamp MCP Server providing access to an OpsRamp tenant through these tools:"}
+	for _, name := range tools {
+		if summary, ok := toolSummaries[name]; ok {
+			lines = append(lines, fmt.Sprintf("- %s: %s", name, summary))
+		} else {
+			lines = append(lines, "- "+name)
+		}
+	}
+	if config == nil {
+		return strings.Join(lines, "\n")
+	}
+
+	if guardrails := instructionGuardrails(config, tools); len(guardrails) > 0 {
+		lines = append(lines, "", "Limits of this deployment:")
+		for _, guardrail := range guardrails {
+			lines = append(lines, "- "+guardrail)
+		}
+	}
+	lines = append(lines, "", instructionTenants(config))
+	return strings.Join(lines, "\n")
+}
+
+// instructionGuardrails describes the configured restrictions on tool calls
+// that a client should plan around
+func instructionGuardrails(config *common.Config, tools []string) []string {
+	var guardrails []string
+	for _, name := range tools {
+		switch backend := config.ToolBackend(name); backend.Backend {
+		case common.BackendMock:
+			guardrails = append(guardrails, fmt.Sprintf("The %s tool returns sample data; changes are not sent to OpsRamp.", name))
+		case common.BackendSnapshot:
+			guardrails = append(guardrails, fmt.Sprintf("The %s tool replays recorded OpsRamp responses; results may be out of date and calls that were not recorded fail.", name))
+		}
+		if ToolScope(name) == client.ScopePartner && config.OpsRamp.PartnerID == "" {
+			guardrails = append(guardrails, fmt.Sprintf("The %s tool needs partner credentials, which are not configured; its calls fail.", name))
+		}
+	}
+
+	if containsTool(tools, "resources") {
+		threshold := config.Deletes.ConfirmationThreshold
+		if threshold == 0 {
+			threshold = common.DefaultDeleteConfirmationThreshold
+		}
+		guardrails = append(guardrails, fmt.Sprintf(
+			"A resources bulkDelete of more than %d resources must present the token returned by planDelete for the same IDs.", threshold))
+	}
+	if config.Locks.Mode == locks.ModeFail {
+		guardrails = append(guardrails, "Bulk changes fail instead of waiting while another bulk change to the same objects is running.")
+	}
+
+	if policy, ok := config.Responses.Fields["*"]; ok {
+		if len(policy.Allow) > 0 {
+			guardrails = append(guardrails, "Results only include these fields: "+strings.Join(policy.Allow, ", ")+".")
+		}
+		if len(policy.Deny) > 0 {
+			guardrails = append(guardrails, "These fields are removed from results: "+strings.Join(policy.Deny, ", ")+".")
+		}
+	}
+	restricted := make([]string, 0, len(config.Responses.Fields))
+	for name := range config.Responses.Fields {
+		if name != "*" && containsTool(tools, name) {
+			restricted = append(restricted, name)
+		}
+	}
+	if len(restricted) > 0 {
+		sort.Strings(restricted)
+		guardrails = append(guardrails, "Some fields are removed from the results of: "+strings.Join(restricted, ", ")+".")
+	}
+	return guardrails
+}
+
+// instructionTenants describes the tenants calls can target, with their
+// telemetry labels such as environment and region
+func instructionTenants(config *common.Config) string {
+	if len(config.Tenants) == 0 {
+		if labels := formatLabels(config.TelemetryLabels("")); labels != "" {
+			return "All calls go to a single OpsRamp tenant (" + labels + ")."
+		}
+		return "All calls go to a single OpsRamp tenant."
+	}
+
+	names := []string{"default"}
+	for _, tenant := range config.Tenants {
+		names = append(names, tenant.Name)
+	}
+	lines := []string{"Pass tenant to target one of these tenants (default when omitted), or tenants to run a read-only action on several at once:"}
+	for _, name := range names {
+		if labels := formatLabels(config.TelemetryLabels(name)); labels != "" {
+			lines = append(lines, fmt.Sprintf("- %s (%s)", name, labels))
+		} else {
+			lines = append(lines, "- "+name)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatLabels renders labels as sorted name=value pairs, leaving out the
+// tenant label the caller already names
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		if name != common.TelemetryLabelTenant {
+			pairs = append(pairs, name+"="+value)
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// containsTool reports whether the named tool is registered
+func containsTool(tools []string, name string) bool {
+	for _, tool := range tools {
+		if tool == name {
+			return true
+		}
+	}
+	return false
+}
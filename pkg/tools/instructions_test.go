@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestServerInstructions(t *testing.T) {
+	config := &common.Config{
+		OpsRamp: common.OpsRampConfig{Environment: "prod", Labels: map[string]string{"region": "us"}},
+		Tenants: []common.TenantConfig{{Name: "emea", OpsRampConfig: common.OpsRampConfig{Labels: map[string]string{"region": "eu"}}}},
+		Tools:   map[string]common.ToolBackendConfig{"alerts": {Backend: common.BackendMock}},
+		Locks:   common.LocksConfig{Mode: "fail"},
+		Deletes: common.DeletesConfig{ConfirmationThreshold: 25},
+		Responses: common.ResponsesConfig{Fields: map[string]common.FieldPolicy{
+			"*":      {Deny: []string{"ipAddress"}},
+			"alerts": {Deny: []string{"description"}},
+		}},
+	}
+
+	instructions := ServerInstructions(config, []string{"resources", "alerts", "clients"})
+	for _, want := range []string{
+		"- resources: search, inspect",
+		"The alerts tool returns sample data",
+		"The clients tool needs partner credentials",
+		"more than 25 resources",
+		"Bulk changes fail instead of waiting",
+		"These fields are removed from results: ipAddress.",
+		"Some fields are removed from the results of: alerts.",
+		"- default (environment=prod, region=us)",
+		"- emea (environment=prod, region=eu)",
+	} {
+		if !strings.Contains(instructions, want) {
+			t.Errorf("Expected instructions to contain %q:\n%s", want, instructions)
+		}
+	}
+	if strings.Contains(instructions, "integrations") {
+		t.Errorf("Expected only registered tools to be described:\n%s", instructions)
+	}
+
+	// A single-tenant deployment without limits only names its tenant
+	instructions = ServerInstructions(&common.Config{OpsRamp: common.OpsRampConfig{Environment: "staging"}}, []string{"usage"})
+	if strings.Contains(instructions, "Limits") || !strings.HasSuffix(instructions, "single OpsRamp tenant (environment=staging).") {
+		t.Errorf("Unexpected single-tenant instructions:\n%s", instructions)
+	}
+}
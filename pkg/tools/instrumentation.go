@@ -0,0 +1,29 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// InstrumentationMiddleware counts tool calls by tool, action and outcome
+// and measures their duration for the /metrics endpoint. A call with an
+// error result counts as an error like a call that returned an error.
+func InstrumentationMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, req)
+
+		tool, action := req.Params.Name, req.GetString("action", "")
+		outcome := "success"
+		if err != nil || (result != nil && result.IsError) {
+			outcome = "error"
+		}
+		common.ToolCalls.Inc(tool, action, outcome)
+		common.ToolCallDuration.ObserveSince(start, tool, action)
+		return result, err
+	}
+}
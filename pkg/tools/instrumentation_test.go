@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+func TestInstrumentationMiddleware(t *testing.T) {
+	handler := InstrumentationMiddleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		switch req.GetString("action", "") {
+		case "fail":
+			return nil, errors.New("boom")
+		case "reject":
+			return mcp.NewToolResultError("bad input"), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	call := func(action string) {
+		req := createTestRequest(map[string]interface{}{"action": action})
+		req.Params.Name = "instrumented"
+		handler(context.Background(), req)
+	}
+	call("list")
+	call("list")
+	call("fail")
+	call("reject")
+
+	if got := common.ToolCalls.Value("instrumented", "list", "success"); got != 2 {
+		t.Errorf("Expected 2 successful list calls, got %v", got)
+	}
+	if common.ToolCalls.Value("instrumented", "fail", "error") != 1 || common.ToolCalls.Value("instrumented", "reject", "error") != 1 {
+		t.Error("Expected returned errors and error results to count as errors")
+	}
+	if got := common.ToolCallDuration.Count("instrumented", "list"); got != 2 {
+		t.Errorf("Expected 2 list durations, got %d", got)
+	}
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// defaultCloneSuffix is appended to the name of a cloned integration
+const defaultCloneSuffix = " (copy)"
+
+// cloneTargetKey is the context key of the integrations API a clone is
+// installed with when it targets another tenant
+type cloneTargetKey struct{}
+
+// withCloneTarget returns a context that installs clones with target
+func withCloneTarget(ctx context.Context, target IntegrationsAPI) context.Context {
+	return context.WithValue(ctx, cloneTargetKey{}, target)
+}
+
+// cloneTarget returns the integrations API clones are installed with,
+// defaulting to the source tenant's
+func cloneTarget(ctx context.Context, source IntegrationsAPI) IntegrationsAPI {
+	if target, ok := ctx.Value(cloneTargetKey{}).(IntegrationsAPI); ok {
+		return target
+	}
+	return source
+}
+
+// cloneIntegration installs a copy of an installed integration with target.
+// Credentials are not copied: the clone is usually rolled out to another
+// customer, so its credential fields are left blank and reported.
+func cloneIntegration(ctx context.Context, source, target IntegrationsAPI, id, suffix string) (*types.IntegrationClone, error) {
+	if id == "" {
+		return nil, errors.New("id of the integration to clone is required")
+	}
+	original, err := source.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read integration %s: %w", id, err)
+	}
+
+	payload, blanked, err := clonePayload(original, suffix)
+	if err != nil {
+		return nil, err
+	}
+	created, err := target.Create(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install clone of integration %s: %w", id, err)
+	}
+	return &types.IntegrationClone{SourceID: id, Integration: created, BlankedFields: blanked}, nil
+}
+
+// clonePayload builds the install request for a copy of an integration and
+// returns it with the credential fields it blanked
+func clonePayload(original *types.Integration, suffix string) (map[string]interface{}, []string, error) {
+	uniqueName := original.App
+	if uniqueName == "" {
+		uniqueName = original.Type
+	}
+	if uniqueName == "" {
+		return nil, nil, fmt.Errorf("integration %s does not name its app, so it cannot be cloned", original.ID)
+	}
+
+	name := original.DisplayName
+	if name == "" {
+		name = original.Name
+	}
+	if suffix == "" {
+		suffix = defaultCloneSuffix
+	}
+
+	config := original.Config
+	var blanked []string
+	if config.Username != "" {
+		config.Username = ""
+		blanked = append(blanked, "config.username")
+	}
+	if config.Password != "" {
+		config.Password = ""
+		blanked = append(blanked, "config.password")
+	}
+	config.Extra = blankCredentials(config.Extra, "config.extra", &blanked)
+	sort.Strings(blanked)
+
+	payload, err := normalizeJSON(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to copy integration %s: %w", original.ID, err)
+	}
+	return map[string]interface{}{
+		"name":        uniqueName,
+		"displayName": name + suffix,
+		"config":      payload,
+	}, blanked, nil
+}
+
+// blankCredentials returns a copy of values with credential entries blanked
+// at any depth, adding their paths to blanked
+func blankCredentials(values map[string]interface{}, path string, blanked *[]string) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		field := path + "." + key
+		if isCredentialField(key) {
+			copied[key] = ""
+			*blanked = append(*blanked, field)
+		} else if nested, ok := value.(map[string]interface{}); ok {
+			copied[key] = blankCredentials(nested, field, blanked)
+		} else {
+			copied[key] = value
+		}
+	}
+	return copied
+}
+
+// isCredentialField reports whether an integration config key holds a
+// credential, such as password, apiKey, accessToken or clientSecret
+func isCredentialField(key string) bool {
+	key = strings.ToLower(key)
+	for _, marker := range []string{"password", "secret", "token", "credential", "apikey", "accesskey", "privatekey"} {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return key == "username" || key == "user"
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestCloneIntegrationBlanksCredentials(t *testing.T) {
+	source := newFakeIntegrationsAPI()
+	source.GetReturns(&types.Integration{
+		ID: "i1", Name: "aws", DisplayName: "AWS Prod", App: "aws",
+		Config: types.IntegrationConfig{
+			Host: "aws.example.com", Username: "svc", Password: "hunter2", CollectorProfile: "gw-1",
+			Extra: map[string]interface{}{
+				"region":       "us-east-1",
+				"accessKey":    "AKIA",
+				"oauth":        map[string]interface{}{"clientId": "c1", "clientSecret": "s1"},
+				"pollInterval": float64(5),
+			},
+		},
+	}, nil)
+
+	clone, err := cloneIntegration(context.Background(), source, source, "i1", " - Acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"config.extra.accessKey", "config.extra.oauth.clientSecret", "config.password", "config.username"}
+	if !reflect.DeepEqual(clone.BlankedFields, want) {
+		t.Errorf("Expected blanked fields %v, got %v", want, clone.BlankedFields)
+	}
+
+	_, payload := source.CreateArgsForCall(0)
+	if payload["name"] != "aws" || payload["displayName"] != "AWS Prod - Acme" {
+		t.Errorf("Expected the aws app installed as 'AWS Prod - Acme', got %v", payload)
+	}
+	config := payload["config"].(map[string]interface{})
+	if _, ok := config["password"]; ok {
+		t.Errorf("Expected the password to be left out, got %v", config)
+	}
+	extra := config["extra"].(map[string]interface{})
+	oauth := extra["oauth"].(map[string]interface{})
+	if config["host"] != "aws.example.com" || extra["region"] != "us-east-1" || extra["accessKey"] != "" ||
+		oauth["clientId"] != "c1" || oauth["clientSecret"] != "" {
+		t.Errorf("Expected the configuration without credentials, got %v", config)
+	}
+}
+
+func TestCloneIntegrationTargetTenant(t *testing.T) {
+	source, target := newFakeIntegrationsAPI(), newFakeIntegrationsAPI()
+	ctx := withCloneTarget(context.Background(), target)
+
+	result, err := IntegrationsToolHandler(ctx, createTestRequest(map[string]interface{}{
+		"action": "clone", "id": "i1", "targetTenant": "acme",
+	}), source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError {
+		t.Fatalf("Expected success, got %s", resultText(result))
+	}
+	if source.CreateCallCount() != 0 || target.CreateCallCount() != 1 {
+		t.Errorf("Expected the clone installed in the target tenant only, got %d source and %d target installs",
+			source.CreateCallCount(), target.CreateCallCount())
+	}
+	_, payload := target.CreateArgsForCall(0)
+	if payload["displayName"] != "aws (copy)" {
+		t.Errorf("Expected the default suffix, got %v", payload["displayName"])
+	}
+}
+
+func TestCloneIntegrationRequiresApp(t *testing.T) {
+	api := newFakeIntegrationsAPI()
+	api.GetReturns(&types.Integration{ID: "i1", Name: "custom"}, nil)
+	if _, err := cloneIntegration(context.Background(), api, api, "i1", ""); err == nil {
+		t.Error("Expected an error for an integration without an app")
+	}
+	if api.CreateCallCount() != 0 {
+		t.Error("Expected nothing to be installed")
+	}
+}
@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/scheduler"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+// Scheduled integration actions, as registered with the scheduler
+const (
+	scheduledIntegrationEnable  = "integrations.enable"
+	scheduledIntegrationDisable = "integrations.disable"
+)
+
+// registerIntegrationExecutors lets the scheduler enable and disable
+// integrations through api, or through the API of the entry's tenant
+func registerIntegrationExecutors(api IntegrationsAPI) {
+	executor := func(enable bool) scheduler.Executor {
+		return func(ctx context.Context, entry scheduler.Entry) error {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]interface{}{"tenant": entry.Tenant}
+			tenantAPI, err := integrationsAPIForRequest(req, api)
+			if err != nil {
+				return err
+			}
+			if enable {
+				return tenantAPI.Enable(ctx, entry.Target)
+			}
+			return tenantAPI.Disable(ctx, entry.Target)
+		}
+	}
+	scheduler.RegisterExecutor(scheduledIntegrationEnable, executor(true))
+	scheduler.RegisterExecutor(scheduledIntegrationDisable, executor(false))
+}
+
+// integrationScheduler returns the process scheduler
+func integrationScheduler() (*scheduler.Scheduler, error) {
+	s := scheduler.Default()
+	if s == nil {
+		return nil, fmt.Errorf("scheduling is not available: it requires the state store")
+	}
+	return s, nil
+}
+
+// parseScheduleTime parses an RFC3339 timestamp, or a duration such as "8h"
+// or "90m" from now
+func parseScheduleTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(strings.TrimPrefix(value, "+")); err == nil {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use an RFC3339 timestamp such as 2025-03-01T22:00:00Z or a duration from now such as 8h", value)
+}
+
+// scheduleIntegration schedules an integration to be enabled or disabled at
+// the given time and, when until is set, the opposite change at that time
+func scheduleIntegration(ctx context.Context, req mcp.CallToolRequest, id string, enable bool) ([]scheduler.Entry, error) {
+	action, reverse := scheduledIntegrationDisable, scheduledIntegrationEnable
+	if enable {
+		action, reverse = reverse, action
+	}
+	if id == "" {
+		return nil, fmt.Errorf("integration ID is required to schedule %s", action)
+	}
+	at := req.GetString("at", "")
+	if at == "" {
+		return nil, fmt.Errorf("at is required to schedule %s", action)
+	}
+
+	s, err := integrationScheduler()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	runAt, err := parseScheduleTime(at, now)
+	if err != nil {
+		return nil, err
+	}
+	var reverseAt time.Time
+	if until := req.GetString("until", ""); until != "" {
+		if reverseAt, err = parseScheduleTime(until, now); err != nil {
+			return nil, err
+		}
+		if !reverseAt.After(runAt) {
+			return nil, fmt.Errorf("until must be after at")
+		}
+	}
+
+	base := scheduler.Entry{
+		Target:    id,
+		Tenant:    req.GetString("tenant", ""),
+		Note:      req.GetString("note", ""),
+		CreatedBy: session.IDFromContext(ctx),
+	}
+	first := base
+	first.Action, first.RunAt = action, runAt
+	entry, err := s.Schedule(first)
+	if err != nil {
+		return nil, err
+	}
+	entries := []scheduler.Entry{*entry}
+
+	if !reverseAt.IsZero() {
+		second := base
+		second.Action, second.RunAt = reverse, reverseAt
+		reversed, err := s.Schedule(second)
+		if err != nil {
+			// Keep the pair together: without its reversal the first change is unexpected
+			s.Cancel(entry.ID)
+			return nil, err
+		}
+		entries = append(entries, *reversed)
+	}
+	return entries, nil
+}
+
+// listIntegrationSchedules returns the pending and failed integration
+// schedule entries, optionally only those for one integration
+func listIntegrationSchedules(id string) ([]scheduler.Entry, error) {
+	s, err := integrationScheduler()
+	if err != nil {
+		return nil, err
+	}
+	all, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]scheduler.Entry, 0, len(all))
+	for _, entry := range all {
+		if !strings.HasPrefix(entry.Action, "integrations.") || (id != "" && entry.Target != id) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// cancelIntegrationSchedule removes a pending or failed integration schedule entry
+func cancelIntegrationSchedule(scheduleID string) (*scheduler.Entry, error) {
+	if scheduleID == "" {
+		return nil, fmt.Errorf("scheduleId is required for cancelSchedule")
+	}
+	s, err := integrationScheduler()
+	if err != nil {
+		return nil, err
+	}
+	return s.Cancel(scheduleID)
+}
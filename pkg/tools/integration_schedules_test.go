@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/scheduler"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+)
+
+// useTestScheduler installs a scheduler over a fresh store for the test
+func useTestScheduler(t *testing.T) *scheduler.Scheduler {
+	t.Helper()
+	s, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	sched := scheduler.NewScheduler(s)
+	scheduler.SetDefault(sched)
+	t.Cleanup(func() {
+		scheduler.SetDefault(nil)
+		s.Close()
+	})
+	return sched
+}
+
+func TestParseScheduleTime(t *testing.T) {
+	now := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2025-03-01T22:00:00Z", time.Date(2025, 3, 1, 22, 0, 0, 0, time.UTC)},
+		{"8h", now.Add(8 * time.Hour)},
+		{"+90m", now.Add(90 * time.Minute)},
+	}
+	for _, tt := range tests {
+		got, err := parseScheduleTime(tt.value, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseScheduleTime(%q) = %s, %v; want %s", tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := parseScheduleTime("tonight", now); err == nil {
+		t.Error("Expected an error for an unparseable time")
+	}
+}
+
+func TestIntegrationsToolScheduleDisableUntil(t *testing.T) {
+	sched := useTestScheduler(t)
+	api := newFakeIntegrationsAPI()
+	registerIntegrationExecutors(api)
+
+	result, err := IntegrationsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "scheduleDisable", "id": "i1", "at": "1h", "until": "9h", "note": "flapping overnight",
+	}), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []scheduler.Entry
+	if err := json.Unmarshal([]byte(resultText(result)), &entries); err != nil {
+		t.Fatalf("Expected schedule entries, got %s", resultText(result))
+	}
+	if len(entries) != 2 || entries[0].Action != scheduledIntegrationDisable || entries[1].Action != scheduledIntegrationEnable ||
+		entries[1].Note != "flapping overnight" || !entries[1].RunAt.After(entries[0].RunAt) {
+		t.Fatalf("Expected a disable then an enable, got %+v", entries)
+	}
+	if api.DisableCallCount() != 0 {
+		t.Error("Expected nothing to run before its time")
+	}
+
+	// until must follow at
+	if _, err := IntegrationsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "scheduleEnable", "id": "i1", "at": "2h", "until": "1h",
+	}), api); err == nil {
+		t.Error("Expected until before at to be refused")
+	}
+
+	result, err = IntegrationsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "listSchedules", "id": "i1",
+	}), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listed []scheduler.Entry
+	if json.Unmarshal([]byte(resultText(result)), &listed) != nil || len(listed) != 2 {
+		t.Fatalf("Expected both entries listed, got %s", resultText(result))
+	}
+
+	if _, err := IntegrationsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "cancelSchedule", "scheduleId": entries[1].ID,
+	}), api); err != nil {
+		t.Fatalf("cancelSchedule failed: %v", err)
+	}
+	remaining, _ := sched.List()
+	if len(remaining) != 1 || remaining[0].ID != entries[0].ID {
+		t.Errorf("Expected only the disable to remain, got %+v", remaining)
+	}
+}
+
+func TestIntegrationsToolScheduleRequiresScheduler(t *testing.T) {
+	scheduler.SetDefault(nil)
+	if _, err := IntegrationsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "scheduleDisable", "id": "i1", "at": "1h",
+	}), newFakeIntegrationsAPI()); err == nil {
+		t.Error("Expected scheduling without a state store to fail")
+	}
+}
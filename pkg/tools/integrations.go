@@ -2,13 +2,13 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/scheduler"
 	"github.com/opsramp/or-mcp-v2/pkg/types"
 )
 
@@ -56,25 +56,71 @@ func NewIntegrationsMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
 		logger.Error("Failed to load config for OpsRamp Integrations API: %v", err)
 		logger.Warn("Falling back to mock implementation")
 		mockAPI := &MockIntegrationsAPI{}
-		return createIntegrationsTool(mockAPI)
+		tool, handler := createIntegrationsTool(mockAPI, nil)
+		return withBackend(tool, common.BackendMock), handler
+	}
+
+	// An explicitly selected backend is honored as-is; only the implicit
+	// default keeps the historical fallback to mock data
+	_, explicit := config.Tools["integrations"]
+	backend := config.ToolBackend("integrations").Backend
+	switch backend {
+	case common.BackendMock:
+		fixtures, err := mockFixtures(config)
+		if err != nil {
+			logger.Error("Failed to load fixtures for OpsRamp Integrations API: %v", err)
+			tool, _ := createIntegrationsTool(nil, nil)
+			return withBackend(tool, backend), backendErrorHandler(err)
+		}
+		if fixtures != nil {
+			logger.Info("Using OpsRamp Integrations API fixtures from %s", config.Mock.Fixtures)
+			tool, handler := createIntegrationsTool(NewFixtureIntegrationsAPI(fixtures), NewFixtureResourcesAPI(fixtures))
+			return withBackend(tool, backend), handler
+		}
+		logger.Info("Using mock OpsRamp Integrations API")
+		tool, handler := createIntegrationsTool(&MockIntegrationsAPI{}, nil)
+		return withBackend(tool, backend), handler
+	case common.BackendSnapshot:
+		err := fmt.Errorf("the snapshot backend is not available for the integrations tool")
+		logger.Error("Failed to initialize OpsRamp Integrations API: %v", err)
+		tool, _ := createIntegrationsTool(nil, nil)
+		return withBackend(tool, backend), backendErrorHandler(err)
 	}
 
 	// Create and initialize the real API implementation
 	api, err := NewOpsRampIntegrationsAPI(&config.OpsRamp)
 	if err != nil {
 		logger.Error("Failed to initialize OpsRamp Integrations API: %v", err)
+		if explicit {
+			tool, _ := createIntegrationsTool(nil, nil)
+			return withBackend(tool, backend), backendErrorHandler(err)
+		}
 		logger.Warn("Falling back to mock implementation")
 		// Fall back to mock implementation if initialization fails
 		mockAPI := &MockIntegrationsAPI{}
-		return createIntegrationsTool(mockAPI)
+		tool, handler := createIntegrationsTool(mockAPI, nil)
+		return withBackend(tool, common.BackendMock), handler
+	}
+
+	// Orphan discovery attributes the tenant's resources to integrations
+	var resources ResourcesAPI
+	if opsRampClient, err := newBackendClient(config, "resources"); err != nil {
+		logger.Warn("Orphaned integration discovery is unavailable: %v", err)
+	} else {
+		resources = NewOpsRampResourcesAPI(opsRampClient)
 	}
 
 	logger.Info("Successfully initialized OpsRamp Integrations API")
-	return createIntegrationsTool(api)
+	tool, handler := createIntegrationsTool(api, resources)
+	return withBackend(tool, backend), handler
 }
 
-// createIntegrationsTool creates the MCP tool with the given API implementation
-func createIntegrationsTool(api IntegrationsAPI) (mcp.Tool, server.ToolHandlerFunc) {
+// createIntegrationsTool creates the MCP tool with the given API implementation.
+// resources may be nil, in which case findOrphanedIntegrations is unsupported.
+func createIntegrationsTool(api IntegrationsAPI, resources ResourcesAPI) (mcp.Tool, server.ToolHandlerFunc) {
+	if api != nil {
+		registerIntegrationExecutors(api)
+	}
 	return mcp.Tool{
 			Name:        "integrations",
 			Description: "Manage HPE OpsRamp integrations and their configurations.",
@@ -83,22 +129,77 @@ func createIntegrationsTool(api IntegrationsAPI) (mcp.Tool, server.ToolHandlerFu
 				Properties: map[string]interface{}{
 					"action": map[string]interface{}{
 						"type":        "string",
-						"description": "Action to perform: list, get, getDetailed, create, update, delete, enable, disable, listTypes, getType",
+						"description": "Action to perform: list, get, getDetailed, create, update, delete, enable, disable, scheduleEnable, scheduleDisable, listSchedules, cancelSchedule, listTypes, getType, findOrphanedIntegrations, clone, invalidateCache. The integration list is cached for the configured cache_ttl, and integrations fetched by ID and integration types for shorter; writes through this server invalidate them, and invalidateCache drops the tenant's cached integration data so the next reads see changes made elsewhere. clone installs a copy of an integration, optionally in another tenant, with its credentials left blank. scheduleDisable and scheduleEnable change the integration at a future time, e.g. to disable a flapping integration overnight",
 					},
 					"id": map[string]interface{}{
 						"type":        "string",
-						"description": "Integration ID (for get, update, delete, enable, disable, getType)",
+						"description": "Integration ID (for get, update, delete, enable, disable, scheduleEnable, scheduleDisable, getType, clone; optional filter for listSchedules)",
+					},
+					"suffix": map[string]interface{}{
+						"type":        "string",
+						"description": "Appended to the name of the cloned integration (for clone, defaults to ' (copy)')",
+					},
+					"targetTenant": map[string]interface{}{
+						"type":        "string",
+						"description": "Tenant to install the clone in (for clone, defaults to the tenant of the source integration)",
+					},
+					"at": map[string]interface{}{
+						"type":        "string",
+						"description": "When to run scheduleEnable or scheduleDisable: an RFC3339 timestamp or a duration from now such as 8h",
+					},
+					"until": map[string]interface{}{
+						"type":        "string",
+						"description": "When to undo the scheduled change, e.g. re-enable after an overnight scheduleDisable (same formats as at)",
+					},
+					"note": map[string]interface{}{
+						"type":        "string",
+						"description": "Reason for the schedule, shown by listSchedules",
+					},
+					"scheduleId": map[string]interface{}{
+						"type":        "string",
+						"description": "Schedule entry ID (for cancelSchedule)",
 					},
 					"config": map[string]interface{}{
 						"type":        "object",
 						"description": "Integration configuration (for create and update)",
 					},
+					"timeRange":      timeRangeArgument,
+					"tenant":         tenantArgument,
+					"tenants":        tenantsArgument,
+					"llmCompact":     llmCompactArgument,
+					"transform":      transformArgument,
+					"render":         renderArgument,
+					"columns":        columnsArgument,
+					"maxRows":        maxRowsArgument,
+					"suggestActions": suggestActionsArgument,
 				},
 				Required: []string{"action"},
 			},
-		}, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return IntegrationsToolHandler(ctx, req, api)
-		}
+			OutputSchema: outputSchema(
+				types.Integration{}, types.DetailedIntegration{}, types.IntegrationType{}, types.OrphanedIntegrationsReport{},
+				scheduler.Entry{}, types.TenantFanOutResult{}, types.IntegrationClone{}, types.CacheInvalidation{},
+			),
+		}, withTenantFanOut("integrations", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			tenantAPI, err := integrationsAPIForRequest(req, api)
+			if err != nil {
+				return tenantErrorResult(err), nil
+			}
+			if resources != nil {
+				tenantResources, err := resourcesAPIForRequest(req, resources)
+				if err != nil {
+					return tenantErrorResult(err), nil
+				}
+				tenantAPI = &integrationsWithResources{IntegrationsAPI: tenantAPI, resources: tenantResources}
+			}
+			if target := req.GetString("targetTenant", ""); target != "" {
+				targetAPI, err := integrationsAPIForTenant(target, api)
+				if err != nil {
+					return tenantErrorResult(err), nil
+				}
+				ctx = withCloneTarget(ctx, targetAPI)
+			}
+			return IntegrationsToolHandler(ctx, req, tenantAPI)
+		})
 }
 
 // IntegrationsToolHandler routes requests to the correct method
@@ -120,7 +221,7 @@ func IntegrationsToolHandler(ctx context.Context, req mcp.CallToolRequest, api I
 	}
 
 	// Log the tool execution
-	logger := common.GetLogger()
+	logger := toolLogger("integrations")
 	logger.LogToolExecution("integrations", action, args)
 
 	var err error
@@ -151,6 +252,16 @@ func IntegrationsToolHandler(ctx context.Context, req mcp.CallToolRequest, api I
 	case "disable":
 		logger.Info("Executing Disable integration with ID: %s", id)
 		err = api.Disable(ctx, id)
+	case "scheduleEnable", "scheduleDisable":
+		logger.Info("Executing %s for integration with ID: %s", action, id)
+		result, err = scheduleIntegration(ctx, req, id, action == "scheduleEnable")
+	case "listSchedules":
+		logger.Info("Executing List integration schedules")
+		result, err = listIntegrationSchedules(id)
+	case "cancelSchedule":
+		scheduleID := req.GetString("scheduleId", "")
+		logger.Info("Executing Cancel integration schedule: %s", scheduleID)
+		result, err = cancelIntegrationSchedule(scheduleID)
 	case "listTypes":
 		logger.Info("Executing List integration types")
 		integrationTypes, err := api.ListTypes(ctx)
@@ -179,6 +290,31 @@ func IntegrationsToolHandler(ctx context.Context, req mcp.CallToolRequest, api I
 	case "getType":
 		logger.Info("Executing Get integration type with ID: %s", id)
 		result, err = api.GetType(ctx, id)
+	case "invalidateCache":
+		logger.Info("Executing InvalidateCache")
+		result = invalidateCache(api)
+	case "findOrphanedIntegrations":
+		logger.Info("Executing FindOrphanedIntegrations")
+		provider, ok := api.(ResourcesProvider)
+		if !ok {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Orphaned integration discovery is not supported by this integrations backend"}},
+			}, nil
+		}
+		timeRange, rangeErr := timeRangeFromRequest(req, "last7d")
+		if rangeErr != nil {
+			return timeRangeErrorResult(rangeErr), nil
+		}
+		result, err = NewOrphanFinder(api, provider.Resources()).Find(ctx, timeRange)
+	case "clone":
+		logger.Info("Executing Clone integration with ID: %s", id)
+		var clone *types.IntegrationClone
+		clone, err = cloneIntegration(ctx, api, cloneTarget(ctx, api), id, req.GetString("suffix", ""))
+		if clone != nil {
+			clone.TargetTenant = req.GetString("targetTenant", "")
+		}
+		result = clone
 	default:
 		logger.Error("Unknown action: %s", action)
 		err = server.ErrToolNotFound
@@ -192,26 +328,13 @@ func IntegrationsToolHandler(ctx context.Context, req mcp.CallToolRequest, api I
 		return nil, err
 	}
 
-	// Convert the result to a JSON string
-	resultJSON, err := json.Marshal(result)
+	// Convert the result to JSON, applying the response options
+	toolResult, err := respondJSON(ctx, req, result)
 	if err != nil {
 		logger.Error("Failed to marshal result to JSON: %v", err)
 		return nil, err
 	}
 
-	// Log the JSON result
-	logger.Debug("Result JSON: %s", string(resultJSON))
-
-	// Create the MCP tool result
-	toolResult := &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(resultJSON),
-			},
-		},
-	}
-
 	return toolResult, nil
 }
 
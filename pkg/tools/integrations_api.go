@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/httperr"
 	"github.com/opsramp/or-mcp-v2/pkg/types"
 )
 
@@ -23,6 +25,9 @@ type OpsRampIntegrationsAPI struct {
 	authURL    string
 	authToken  string
 	tokenExp   time.Time
+	catalogs   *client.CatalogCache
+	entities   *client.EntityCache
+	responses  *client.EntityCache
 	logger     *common.CustomLogger
 }
 
@@ -49,11 +54,15 @@ func NewOpsRampIntegrationsAPI(config *common.OpsRampConfig) (*OpsRampIntegratio
 
 	api := &OpsRampIntegrationsAPI{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: client.NewEgressPolicy(&common.Config{OpsRamp: *config}).Transport(nil),
 		},
-		config:  config,
-		baseURL: config.TenantURL,
-		logger:  common.GetLogger(),
+		config:    config,
+		baseURL:   config.TenantURL,
+		catalogs:  client.NewCatalogCache(time.Duration(config.Resources.CacheTTL) * time.Second),
+		entities:  client.SharedEntityCache(*config),
+		responses: client.SharedResponseCache(*config),
+		logger:    common.GetLogger().WithModule(toolModule("integrations")),
 	}
 
 	// Authenticate to verify credentials immediately
@@ -120,6 +129,70 @@ func (a *OpsRampIntegrationsAPI) ensureAuth(ctx context.Context) error {
 
 // makeRequest makes an authenticated request to the OpsRamp API
 func (a *OpsRampIntegrationsAPI) makeRequest(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
+	req, err := a.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		a.logger.Error("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+		return nil, httperr.FromResponse(resp, respBody)
+	}
+
+	return respBody, nil
+}
+
+// makeCatalogRequest makes a cached, conditional GET request for a catalog path
+func (a *OpsRampIntegrationsAPI) makeCatalogRequest(ctx context.Context, path string) ([]byte, error) {
+	key := fmt.Sprintf("%s/%s/%s", a.baseURL, a.config.TenantID, path)
+	if body, ok := a.catalogs.Fresh(key); ok {
+		a.logger.Debug("Serving catalog %s from cache", path)
+		return body, nil
+	}
+
+	req, err := a.newRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.catalogs.Prepare(key, req)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusNotModified && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		a.logger.Error("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+		return nil, httperr.FromResponse(resp, respBody)
+	}
+
+	respBody, status, err := a.catalogs.Resolve(key, resp, respBody)
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Info("Catalog %s: %s", path, status)
+	return respBody, nil
+}
+
+// newRequest builds an authenticated request for an integrations API path
+func (a *OpsRampIntegrationsAPI) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
 	// Ensure we have a valid auth token
 	if err := a.ensureAuth(ctx); err != nil {
 		return nil, err
@@ -150,31 +223,25 @@ func (a *OpsRampIntegrationsAPI) makeRequest(ctx context.Context, method, path s
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.authToken))
 
-	resp, err := a.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		a.logger.Error("API request failed with status %d: %s", resp.StatusCode, string(respBody))
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
-	}
-
-	return respBody, nil
+	return req, nil
 }
 
 // List returns all integrations
 func (a *OpsRampIntegrationsAPI) List(ctx context.Context) ([]types.Integration, error) {
 	// Based on OpsRamp API docs: /api/v2/tenants/{tenantId}/integrations/installed/search
-	respBody, err := a.makeRequest(ctx, "GET", "installed/search", nil)
-	if err != nil {
-		return nil, fmt.Errorf("error listing integrations: %w", err)
+	// Repeated lists are served from the response cache
+	cached, generation, ok := a.responses.Get(responseKindIntegrationList, "installed/search")
+	var respBody []byte
+	if ok {
+		respBody = cached.([]byte)
+		a.logger.Debug("Serving integration list from cache")
+	} else {
+		var err error
+		respBody, err = a.makeRequest(ctx, "GET", "installed/search", nil)
+		if err != nil {
+			return nil, fmt.Errorf("error listing integrations: %w", err)
+		}
+		a.responses.Put(responseKindIntegrationList, "installed/search", respBody, generation)
 	}
 
 	// Log the raw response for debugging
@@ -208,10 +275,67 @@ func (a *OpsRampIntegrationsAPI) List(ctx context.Context) ([]types.Integration,
 	return directArray, nil
 }
 
-// Get returns a specific integration by ID
+// installedPath builds the path of an installed integration, validating and
+// escaping the ID so it cannot alter the request path
+func installedPath(id string, action ...string) (string, error) {
+	pathID, err := client.PathID("integration", id)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append([]string{"installed", pathID}, action...), "/"), nil
+}
+
+// entityKindIntegration keys integrations in the entity cache
+const entityKindIntegration = "integration"
+
+// responseKindIntegrationList keys integration lists in the response cache
+const responseKindIntegrationList = "integrations.list"
+
+// invalidate drops the cached integrations with the given IDs and the
+// cached integration list, which a write may change
+func (a *OpsRampIntegrationsAPI) invalidate(ids ...string) {
+	a.entities.Invalidate(entityKindIntegration, ids...)
+	a.responses.InvalidateKind(responseKindIntegrationList)
+}
+
+// InvalidateCache drops the cached integration list, integrations and
+// integration types of the API's tenant, so the next reads fetch them from
+// OpsRamp
+func (a *OpsRampIntegrationsAPI) InvalidateCache() types.CacheInvalidation {
+	return types.CacheInvalidation{
+		Responses: a.responses.InvalidateKind(responseKindIntegrationList),
+		Entities:  a.entities.InvalidateKind(entityKindIntegration),
+		Catalogs:  a.catalogs.Clear(),
+	}
+}
+
+// Get returns a specific integration by ID, serving repeated reads from the
+// entity cache
 func (a *OpsRampIntegrationsAPI) Get(ctx context.Context, id string) (*types.Integration, error) {
+	cached, generation, ok := a.entities.Get(entityKindIntegration, id)
+	if ok {
+		integration := *cached.(*types.Integration)
+		a.logger.Debug("Serving integration %s from cache", id)
+		return &integration, nil
+	}
+
+	integration, err := a.fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	stored := *integration
+	a.entities.Put(entityKindIntegration, id, &stored, generation)
+	return integration, nil
+}
+
+// fetch retrieves an integration from OpsRamp
+func (a *OpsRampIntegrationsAPI) fetch(ctx context.Context, id string) (*types.Integration, error) {
 	// Based on OpsRamp API docs: /api/v2/tenants/{tenantId}/integrations/installed/{installedIntgId}
-	respBody, err := a.makeRequest(ctx, "GET", fmt.Sprintf("installed/%s", id), nil)
+	path, err := installedPath(id)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := a.makeRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error getting integration %s: %w", id, err)
 	}
@@ -248,7 +372,11 @@ func (a *OpsRampIntegrationsAPI) Get(ctx context.Context, id string) (*types.Int
 // GetDetailed returns detailed information about an integration
 func (a *OpsRampIntegrationsAPI) GetDetailed(ctx context.Context, id string) (*types.DetailedIntegration, error) {
 	// Using same endpoint as Get with additional processing if needed
-	respBody, err := a.makeRequest(ctx, "GET", fmt.Sprintf("installed/%s", id), nil)
+	path, err := installedPath(id)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := a.makeRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error getting detailed integration %s: %w", id, err)
 	}
@@ -284,6 +412,7 @@ func (a *OpsRampIntegrationsAPI) GetDetailed(ctx context.Context, id string) (*t
 
 // Create creates a new integration
 func (a *OpsRampIntegrationsAPI) Create(ctx context.Context, config map[string]interface{}) (*types.Integration, error) {
+	defer a.invalidate()
 	// Get the integration name from the config
 	intgName, ok := config["name"].(string)
 	if !ok || intgName == "" {
@@ -291,7 +420,11 @@ func (a *OpsRampIntegrationsAPI) Create(ctx context.Context, config map[string]i
 	}
 
 	// Based on OpsRamp API docs: /api/v2/tenants/{tenantId}/integrations/install/{uniqueName}
-	respBody, err := a.makeRequest(ctx, "POST", fmt.Sprintf("install/%s", intgName), config)
+	uniqueName, err := client.PathID("integration type", intgName)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := a.makeRequest(ctx, "POST", "install/"+uniqueName, config)
 	if err != nil {
 		return nil, fmt.Errorf("error creating integration: %w", err)
 	}
@@ -306,8 +439,13 @@ func (a *OpsRampIntegrationsAPI) Create(ctx context.Context, config map[string]i
 
 // Update updates an existing integration
 func (a *OpsRampIntegrationsAPI) Update(ctx context.Context, id string, config map[string]interface{}) (*types.Integration, error) {
+	defer a.invalidate(id)
 	// Based on OpsRamp API docs: /api/v2/tenants/{tenantId}/integrations/installed/{installedIntgId}
-	respBody, err := a.makeRequest(ctx, "POST", fmt.Sprintf("installed/%s", id), config)
+	path, err := installedPath(id)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := a.makeRequest(ctx, "POST", path, config)
 	if err != nil {
 		return nil, fmt.Errorf("error updating integration %s: %w", id, err)
 	}
@@ -322,8 +460,13 @@ func (a *OpsRampIntegrationsAPI) Update(ctx context.Context, id string, config m
 
 // Delete removes an integration
 func (a *OpsRampIntegrationsAPI) Delete(ctx context.Context, id string) error {
+	defer a.invalidate(id)
 	// Based on OpsRamp API docs: /api/v2/tenants/{tenantId}/integrations/installed/{installedIntgId}
-	_, err := a.makeRequest(ctx, "DELETE", fmt.Sprintf("installed/%s", id), nil)
+	path, err := installedPath(id)
+	if err != nil {
+		return err
+	}
+	_, err = a.makeRequest(ctx, "DELETE", path, nil)
 	if err != nil {
 		return fmt.Errorf("error deleting integration %s: %w", id, err)
 	}
@@ -333,9 +476,14 @@ func (a *OpsRampIntegrationsAPI) Delete(ctx context.Context, id string) error {
 
 // Enable enables an integration
 func (a *OpsRampIntegrationsAPI) Enable(ctx context.Context, id string) error {
+	defer a.invalidate(id)
 	// Based on OpsRamp API docs: /api/v2/tenants/{tenantId}/integrations/installed/{installedIntgId}/{actions}
 	// where actions is 'enable'
-	_, err := a.makeRequest(ctx, "POST", fmt.Sprintf("installed/%s/enable", id), nil)
+	path, err := installedPath(id, "enable")
+	if err != nil {
+		return err
+	}
+	_, err = a.makeRequest(ctx, "POST", path, nil)
 	if err != nil {
 		return fmt.Errorf("error enabling integration %s: %w", id, err)
 	}
@@ -345,9 +493,14 @@ func (a *OpsRampIntegrationsAPI) Enable(ctx context.Context, id string) error {
 
 // Disable disables an integration
 func (a *OpsRampIntegrationsAPI) Disable(ctx context.Context, id string) error {
+	defer a.invalidate(id)
 	// Based on OpsRamp API docs: /api/v2/tenants/{tenantId}/integrations/installed/{installedIntgId}/{actions}
 	// where actions is 'disable'
-	_, err := a.makeRequest(ctx, "POST", fmt.Sprintf("installed/%s/disable", id), nil)
+	path, err := installedPath(id, "disable")
+	if err != nil {
+		return err
+	}
+	_, err = a.makeRequest(ctx, "POST", path, nil)
 	if err != nil {
 		return fmt.Errorf("error disabling integration %s: %w", id, err)
 	}
@@ -358,7 +511,7 @@ func (a *OpsRampIntegrationsAPI) Disable(ctx context.Context, id string) error {
 // ListTypes returns all integration types
 func (a *OpsRampIntegrationsAPI) ListTypes(ctx context.Context) ([]types.IntegrationType, error) {
 	// Based on OpsRamp API docs: /api/v2/tenants/{tenantId}/integrations/available/search
-	respBody, err := a.makeRequest(ctx, "GET", "available/search", nil)
+	respBody, err := a.makeCatalogRequest(ctx, "available/search")
 	if err != nil {
 		return nil, fmt.Errorf("error listing integration types: %w", err)
 	}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// newFakeIntegrationsAPI returns a fake whose calls succeed with sample results
+func newFakeIntegrationsAPI() *FakeIntegrationsAPI {
+	api := &FakeIntegrationsAPI{}
+	integration := &types.Integration{ID: "i1", Name: "aws", App: "aws"}
+	api.ListReturns([]types.Integration{*integration}, nil)
+	api.GetReturns(integration, nil)
+	api.GetDetailedReturns(&types.DetailedIntegration{}, nil)
+	api.CreateReturns(integration, nil)
+	api.UpdateReturns(integration, nil)
+	api.ListTypesReturns([]types.IntegrationType{{ID: "aws", Name: "AWS"}}, nil)
+	api.GetTypeReturns(&types.IntegrationType{ID: "aws", Name: "AWS"}, nil)
+	return api
+}
+
+func TestIntegrationsToolHandlerActions(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  map[string]interface{}
+		calls func(api *FakeIntegrationsAPI) int
+	}{
+		{"list", map[string]interface{}{"action": "list"}, (*FakeIntegrationsAPI).ListCallCount},
+		{"get", map[string]interface{}{"action": "get", "id": "i1"}, (*FakeIntegrationsAPI).GetCallCount},
+		{"getDetailed", map[string]interface{}{"action": "getDetailed", "id": "i1"}, (*FakeIntegrationsAPI).GetDetailedCallCount},
+		{"create", map[string]interface{}{"action": "create", "config": map[string]interface{}{"name": "aws"}}, (*FakeIntegrationsAPI).CreateCallCount},
+		{"update", map[string]interface{}{"action": "update", "id": "i1", "config": map[string]interface{}{"name": "aws"}}, (*FakeIntegrationsAPI).UpdateCallCount},
+		{"delete", map[string]interface{}{"action": "delete", "id": "i1"}, (*FakeIntegrationsAPI).DeleteCallCount},
+		{"enable", map[string]interface{}{"action": "enable", "id": "i1"}, (*FakeIntegrationsAPI).EnableCallCount},
+		{"disable", map[string]interface{}{"action": "disable", "id": "i1"}, (*FakeIntegrationsAPI).DisableCallCount},
+		{"listTypes", map[string]interface{}{"action": "listTypes"}, (*FakeIntegrationsAPI).ListTypesCallCount},
+		{"getType", map[string]interface{}{"action": "getType", "id": "aws"}, (*FakeIntegrationsAPI).GetTypeCallCount},
+		{"clone", map[string]interface{}{"action": "clone", "id": "i1"}, (*FakeIntegrationsAPI).CreateCallCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" succeeds", func(t *testing.T) {
+			api := newFakeIntegrationsAPI()
+			result, err := IntegrationsToolHandler(context.Background(), createTestRequest(tt.args), api)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.IsError {
+				t.Fatalf("Expected success, got %s", resultText(result))
+			}
+			if calls := tt.calls(api); calls != 1 {
+				t.Errorf("Expected one API call, got %d", calls)
+			}
+		})
+
+		t.Run(tt.name+" returns API errors", func(t *testing.T) {
+			api := newFakeIntegrationsAPI()
+			apiErr := errors.New("API request failed with status 404: not found")
+			api.ListReturns(nil, apiErr)
+			api.GetReturns(nil, apiErr)
+			api.GetDetailedReturns(nil, apiErr)
+			api.CreateReturns(nil, apiErr)
+			api.UpdateReturns(nil, apiErr)
+			api.DeleteReturns(apiErr)
+			api.EnableReturns(apiErr)
+			api.DisableReturns(apiErr)
+			api.ListTypesReturns(nil, apiErr)
+			api.GetTypeReturns(nil, apiErr)
+
+			result, err := IntegrationsToolHandler(context.Background(), createTestRequest(tt.args), api)
+			if !errors.Is(err, apiErr) || result != nil {
+				t.Errorf("Expected the API error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIntegrationsToolHandlerArguments(t *testing.T) {
+	api := newFakeIntegrationsAPI()
+	config := map[string]interface{}{"name": "aws", "region": "us-east-1"}
+	_, err := IntegrationsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "update", "id": "i1", "config": config,
+	}), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, id, got := api.UpdateArgsForCall(0)
+	if id != "i1" || got["region"] != "us-east-1" {
+		t.Errorf("Expected the update of i1 with its config, got %s %v", id, got)
+	}
+}
+
+func TestIntegrationsToolHandlerUnknownAction(t *testing.T) {
+	result, err := IntegrationsToolHandler(context.Background(), createTestRequest(map[string]interface{}{"action": "explode"}), newFakeIntegrationsAPI())
+	if !errors.Is(err, server.ErrToolNotFound) || result != nil {
+		t.Errorf("Expected ErrToolNotFound, got %v", err)
+	}
+}
+
+func TestIntegrationsToolHandlerOrphansUnsupported(t *testing.T) {
+	result, err := IntegrationsToolHandler(context.Background(), createTestRequest(map[string]interface{}{"action": "findOrphanedIntegrations"}), newFakeIntegrationsAPI())
+	if err != nil || !result.IsError {
+		t.Errorf("Expected a tool error for a backend without resources, got %v", err)
+	}
+}
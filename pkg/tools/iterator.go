@@ -0,0 +1,249 @@
+package tools
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/httperr"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// Iterator rate limiting defaults
+const (
+	// iteratorRateLimitRetries is how many times a rate-limited page fetch is repeated
+	iteratorRateLimitRetries = 5
+	// iteratorRateLimitDelay is the first wait after a 429 without Retry-After; it doubles per retry
+	iteratorRateLimitDelay = 2 * time.Second
+	// iteratorMaxRateLimitDelay bounds any single wait, including one asked for by Retry-After
+	iteratorMaxRateLimitDelay = time.Minute
+)
+
+// tokenPageParam is the query parameter that passes a continuation token
+// back to an endpoint paged by tokens
+const tokenPageParam = "nextPageToken"
+
+// pageFetcher returns one page of a search and whether more pages follow
+type pageFetcher[T any] func(ctx context.Context, pageNo int) ([]T, bool, error)
+
+// tokenPageFetcher returns the page of a search at a continuation token,
+// empty for the first page, and the token of the next page, empty after the
+// last one
+type tokenPageFetcher[T any] func(ctx context.Context, token string) ([]T, string, error)
+
+// cursorFetcher returns the page at a page number or continuation token,
+// whichever the search pages by, the token of the next page and whether more
+// pages follow
+type cursorFetcher[T any] func(ctx context.Context, pageNo int, token string) ([]T, string, bool, error)
+
+// Iterator walks the results of a paged OpsRamp search one item at a time,
+// fetching the next page when the current one is used up. Searches paged by
+// page number and by continuation token are walked alike. Rate-limited
+// fetches are retried after the delay OpsRamp asks for, and iteration stops
+// when the context is cancelled.
+//
+//	it := tools.SearchResourcesIter(ctx, api, types.ResourceSearchParams{PageSize: 500})
+//	for it.Next() {
+//		resource := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx            context.Context
+	fetch          cursorFetcher[T]
+	pageNo         int
+	token          string
+	page           []T
+	index          int
+	more           bool
+	err            error
+	rateLimitDelay time.Duration
+}
+
+// newIterator creates an iterator whose first fetch is page startPage
+func newIterator[T any](ctx context.Context, startPage int, fetch pageFetcher[T]) *Iterator[T] {
+	return &Iterator[T]{
+		ctx: ctx,
+		fetch: func(ctx context.Context, pageNo int, _ string) ([]T, string, bool, error) {
+			page, more, err := fetch(ctx, pageNo)
+			return page, "", more, err
+		},
+		pageNo:         max(startPage, 1) - 1,
+		index:          -1,
+		more:           true,
+		rateLimitDelay: iteratorRateLimitDelay,
+	}
+}
+
+// newTokenIterator creates an iterator whose first fetch is at startToken,
+// or the first page when it is empty
+func newTokenIterator[T any](ctx context.Context, startToken string, fetch tokenPageFetcher[T]) *Iterator[T] {
+	return &Iterator[T]{
+		ctx: ctx,
+		fetch: func(ctx context.Context, _ int, token string) ([]T, string, bool, error) {
+			page, next, err := fetch(ctx, token)
+			// A token that does not move would fetch the same page forever
+			return page, next, next != "" && next != token, err
+		},
+		token:          startToken,
+		index:          -1,
+		more:           true,
+		rateLimitDelay: iteratorRateLimitDelay,
+	}
+}
+
+// Next advances to the next result, fetching another page when needed. It
+// returns false when the results are exhausted or an error occurred; check
+// Err to tell the two apart.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.index++
+	for it.index >= len(it.page) {
+		if !it.more {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		page, next, more, err := it.fetchPage(it.pageNo+1, it.token)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.pageNo++
+		it.token = next
+		it.page, it.index = page, 0
+		// An empty page ends the search even if upstream claims there is more
+		it.more = more && len(page) > 0
+	}
+	return true
+}
+
+// Value returns the current result. It is only valid after Next returned true.
+func (it *Iterator[T]) Value() T {
+	return it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// PageNo returns the number of the page the current result came from. For a
+// search paged by token it counts the pages fetched.
+func (it *Iterator[T]) PageNo() int {
+	return it.pageNo
+}
+
+// Token returns the continuation token of the page after the current one,
+// from which a search paged by token can be resumed. It is empty for a search
+// paged by number and after the last page.
+func (it *Iterator[T]) Token() string {
+	return it.token
+}
+
+// fetchPage fetches one page, waiting out rate limiting. The first page is
+// fetched with the caller's priority and later ones in the background, so a
+// long scan does not hold up interactive calls.
+func (it *Iterator[T]) fetchPage(pageNo int, token string) ([]T, string, bool, error) {
+	ctx := it.ctx
+	if it.page != nil {
+		ctx = client.WithPriority(ctx, client.PriorityBackground)
+	}
+	for attempt := 0; ; attempt++ {
+		page, next, more, err := it.fetch(ctx, pageNo, token)
+		if err == nil || !httperr.IsRateLimited(err) || attempt >= iteratorRateLimitRetries {
+			return page, next, more, err
+		}
+
+		delay := httperr.RetryAfterDelay(err)
+		if delay <= 0 {
+			delay = it.rateLimitDelay << attempt
+		}
+		select {
+		case <-time.After(min(delay, iteratorMaxRateLimitDelay)):
+		case <-it.ctx.Done():
+			return nil, "", false, it.ctx.Err()
+		}
+	}
+}
+
+// SearchResourcesIter iterates over every resource matching params, starting
+// at params.PageNo (or the first page)
+func SearchResourcesIter(ctx context.Context, api ResourcesAPI, params types.ResourceSearchParams) *Iterator[types.Resource] {
+	return newIterator(ctx, params.PageNo, func(ctx context.Context, pageNo int) ([]types.Resource, bool, error) {
+		params.PageNo = pageNo
+		page, err := api.Search(ctx, params)
+		if err != nil {
+			return nil, false, err
+		}
+		return page.Results, page.NextPage, nil
+	})
+}
+
+// SearchAlertsIter iterates over every alert matching params, starting at
+// params.PageNo (or the first page)
+func SearchAlertsIter(ctx context.Context, api AlertsAPI, params types.AlertSearchParams) *Iterator[types.AlertDetails] {
+	return newIterator(ctx, params.PageNo, func(ctx context.Context, pageNo int) ([]types.AlertDetails, bool, error) {
+		params.PageNo = pageNo
+		page, err := api.Search(ctx, params)
+		if err != nil {
+			return nil, false, err
+		}
+		return page.Results, page.NextPage, nil
+	})
+}
+
+// SearchMonitorsIter iterates over every custom monitor matching query
+func SearchMonitorsIter(ctx context.Context, api MonitorsAPI, query string, pageSize int) *Iterator[types.CustomMonitor] {
+	return newIterator(ctx, 1, func(ctx context.Context, pageNo int) ([]types.CustomMonitor, bool, error) {
+		page, err := api.Search(ctx, query, pageNo, pageSize)
+		if err != nil {
+			return nil, false, err
+		}
+		return page.Results, page.NextPage, nil
+	})
+}
+
+// SearchClientsIter iterates over every partner client matching query
+func SearchClientsIter(ctx context.Context, api ClientsAPI, query string, pageSize int) *Iterator[types.PartnerClient] {
+	return newIterator(ctx, 1, func(ctx context.Context, pageNo int) ([]types.PartnerClient, bool, error) {
+		page, err := api.Search(ctx, query, pageNo, pageSize)
+		if err != nil {
+			return nil, false, err
+		}
+		return page.Results, page.NextPage, nil
+	})
+}
+
+// TokenPagesIter iterates over every result of a GET endpoint that pages by
+// continuation token and answers with a types.TokenPage. query holds the
+// other parameters of the endpoint; the token is passed back in
+// nextPageToken.
+func TokenPagesIter[T any](ctx context.Context, c *client.OpsRampClient, endpoint string, query url.Values) *Iterator[T] {
+	return newTokenIterator(ctx, "", func(ctx context.Context, token string) ([]T, string, error) {
+		params := url.Values{}
+		for key, values := range query {
+			params[key] = values
+		}
+		if token != "" {
+			params.Set(tokenPageParam, token)
+		}
+		path := endpoint
+		if encoded := params.Encode(); encoded != "" {
+			path += "?" + encoded
+		}
+
+		var page types.TokenPage[T]
+		if err := c.Get(ctx, path, &page); err != nil {
+			return nil, "", err
+		}
+		return page.Results, page.NextPageToken, nil
+	})
+}
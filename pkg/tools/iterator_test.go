@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/httperr"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// pagedResources returns a fake serving total resources in pages of pageSize
+func pagedResources(total, pageSize int) *FakeResourcesAPI {
+	api := &FakeResourcesAPI{}
+	api.SearchStub = func(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+		page := &types.ResourceSearchResponse{PageNo: params.PageNo}
+		for i := (params.PageNo - 1) * pageSize; i < min(params.PageNo*pageSize, total); i++ {
+			page.Results = append(page.Results, types.Resource{ID: fmt.Sprintf("r%d", i)})
+		}
+		page.NextPage = params.PageNo*pageSize < total
+		return page, nil
+	}
+	return api
+}
+
+func TestSearchResourcesIter(t *testing.T) {
+	api := pagedResources(7, 3)
+	it := SearchResourcesIter(context.Background(), api, types.ResourceSearchParams{PageSize: 3})
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	if it.Err() != nil || len(ids) != 7 || ids[0] != "r0" || ids[6] != "r6" {
+		t.Fatalf("Expected r0..r6, got %v (%v)", ids, it.Err())
+	}
+	if api.SearchCallCount() != 3 || it.PageNo() != 3 {
+		t.Errorf("Expected 3 page fetches, got %d", api.SearchCallCount())
+	}
+
+	// Starting from a later page skips the earlier ones
+	it = SearchResourcesIter(context.Background(), api, types.ResourceSearchParams{PageNo: 3, PageSize: 3})
+	if !it.Next() || it.Value().ID != "r6" || it.Next() {
+		t.Error("Expected only r6 from page 3")
+	}
+}
+
+func TestIteratorRetriesRateLimitedPages(t *testing.T) {
+	api := pagedResources(4, 2)
+	serve := api.SearchStub
+	limited := 0
+	api.SearchStub = func(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+		if params.PageNo == 2 && limited < 2 {
+			limited++
+			return nil, &httperr.StatusError{StatusCode: http.StatusTooManyRequests}
+		}
+		return serve(ctx, params)
+	}
+
+	it := SearchResourcesIter(context.Background(), api, types.ResourceSearchParams{PageSize: 2})
+	it.rateLimitDelay = time.Millisecond
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if it.Err() != nil || count != 4 || limited != 2 {
+		t.Errorf("Expected all 4 resources after 2 rate-limited fetches, got %d (%v)", count, it.Err())
+	}
+}
+
+func TestIteratorStops(t *testing.T) {
+	apiErr := &httperr.StatusError{StatusCode: http.StatusForbidden}
+	api := &FakeResourcesAPI{}
+	api.SearchReturns(nil, apiErr)
+	it := SearchResourcesIter(context.Background(), api, types.ResourceSearchParams{})
+	if it.Next() || !errors.Is(it.Err(), apiErr) || api.SearchCallCount() != 1 {
+		t.Errorf("Expected the first non-retryable error to stop iteration, got %v after %d calls", it.Err(), api.SearchCallCount())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	api = pagedResources(10, 2)
+	it = SearchResourcesIter(ctx, api, types.ResourceSearchParams{PageSize: 2})
+	it.Next()
+	it.Next()
+	cancel()
+	if it.Next() || !errors.Is(it.Err(), context.Canceled) || api.SearchCallCount() != 1 {
+		t.Errorf("Expected cancellation to stop before the next page, got %v", it.Err())
+	}
+}
+
+func TestTokenPagesIter(t *testing.T) {
+	// Three pages of tickets chained by continuation tokens
+	pages := map[string]string{
+		"":   `{"results":[{"id":"t1"},{"id":"t2"}],"nextPageToken":"p2"}`,
+		"p2": `{"results":[{"id":"t3"}],"nextPageToken":"p3"}`,
+		"p3": `{"results":[{"id":"t4"}]}`,
+	}
+	var queries []url.Values
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token-1","token_type":"bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("/api/v2/tenants/client_1/tickets/search", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pages[r.URL.Query().Get("nextPageToken")]))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := client.NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: server.URL, AuthURL: server.URL + "/auth/oauth/token", AuthKey: "key-1", AuthSecret: "secret-1", TenantID: "client_1",
+	}})
+	it := TokenPagesIter[struct{ ID string }](context.Background(), c, "/api/v2/tenants/client_1/tickets/search", url.Values{"pageSize": {"2"}})
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+		if len(ids) == 3 && it.Token() != "p3" {
+			t.Errorf("Expected to resume after the second page at p3, got %q", it.Token())
+		}
+	}
+	if it.Err() != nil || fmt.Sprint(ids) != "[t1 t2 t3 t4]" || it.PageNo() != 3 || it.Token() != "" {
+		t.Fatalf("Expected t1..t4 over 3 pages, got %v after %d pages (%v)", ids, it.PageNo(), it.Err())
+	}
+	if len(queries) != 3 || queries[0].Has("nextPageToken") || queries[2].Get("nextPageToken") != "p3" || queries[2].Get("pageSize") != "2" {
+		t.Errorf("Expected the token passed back with the other parameters, got %v", queries)
+	}
+}
+
+func TestTokenIteratorStopsOnRepeatedToken(t *testing.T) {
+	calls := 0
+	it := newTokenIterator(context.Background(), "p1", func(ctx context.Context, token string) ([]int, string, error) {
+		calls++
+		return []int{calls}, token, nil
+	})
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if it.Err() != nil || count != 1 || calls != 1 {
+		t.Errorf("Expected a token that does not move to end the search, got %d results from %d calls", count, calls)
+	}
+}
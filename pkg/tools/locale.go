@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultLocale is used when neither the request nor the configuration sets one
+const defaultLocale = "en"
+
+// localeArgument is the tool input schema entry for the language of generated summaries
+var localeArgument = map[string]interface{}{
+	"type":        "string",
+	"description": "Language and date format of generated summaries: en, es, fr, de or pt, e.g. es-MX (defaults to responses.locale in the configuration)",
+}
+
+// Locale renders the generated text of summary actions (scorecards, impact
+// analyses, usage summaries) in one language. Only generated prose is
+// translated; names, states and severities from OpsRamp are left as they are.
+type Locale struct {
+	Tag          string
+	messages     map[string]string
+	decimalComma bool
+	dateLayout   string
+}
+
+// T formats the message with the given key, falling back to English for
+// messages a locale does not translate
+func (l *Locale) T(key string, args ...interface{}) string {
+	format, ok := l.messages[key]
+	if !ok {
+		format = englishMessages[key]
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Decimal formats a number with one decimal place and the locale's separator
+func (l *Locale) Decimal(value float64) string {
+	text := strconv.FormatFloat(value, 'f', 1, 64)
+	if l.decimalComma {
+		text = strings.Replace(text, ".", ",", 1)
+	}
+	return text
+}
+
+// Date formats a timestamp in the locale's date format
+func (l *Locale) Date(t time.Time) string {
+	return t.Format(l.dateLayout)
+}
+
+// englishMessages is the reference catalog; every key must be defined here
+var englishMessages = map[string]string{
+	"none":               "none",
+	"metric.cpu":         "cpu",
+	"metric.memory":      "memory",
+	"metric.disk":        "disk",
+	"quota.ok":           "ok",
+	"quota.warning":      "warning",
+	"quota.critical":     "critical",
+	"quota.exceeded":     "exceeded",
+	"quota.unlimited":    "unlimited",
+	"scorecard.status":   "%s is %s",
+	"scorecard.metric":   "%s avg %s%% (max %s%%)",
+	"scorecard.alerts":   "%d open alerts (highest severity: %s)",
+	"scorecard.patches":  "%d missing patches (%d critical)",
+	"scorecard.noAgent":  "no agent installed",
+	"scorecard.since":    "status unchanged since %s",
+	"impact.summary":     "Outage of %s impacts %d downstream resources and %d services; %d open alerts (highest severity: %s)",
+	"usage.noLimits":     "%d managed resources; no license limits reported",
+	"usage.nearestLimit": "%d managed resources; closest to limit: %s at %s%% (%d of %d, %d remaining, %s)",
+}
+
+// locales lists the supported locales by language
+var locales = map[string]*Locale{
+	"en": {
+		Tag:        "en",
+		messages:   englishMessages,
+		dateLayout: "Jan 2, 2006 15:04 MST",
+	},
+	"es": {
+		Tag:          "es",
+		decimalComma: true,
+		dateLayout:   "02/01/2006 15:04 MST",
+		messages: map[string]string{
+			"none":               "ninguna",
+			"metric.cpu":         "CPU",
+			"metric.memory":      "memoria",
+			"metric.disk":        "disco",
+			"quota.ok":           "correcto",
+			"quota.warning":      "advertencia",
+			"quota.critical":     "crítico",
+			"quota.exceeded":     "excedido",
+			"quota.unlimited":    "ilimitado",
+			"scorecard.status":   "%s está %s",
+			"scorecard.metric":   "%s promedio %s%% (máx. %s%%)",
+			"scorecard.alerts":   "%d alertas abiertas (severidad más alta: %s)",
+			"scorecard.patches":  "%d parches pendientes (%d críticos)",
+			"scorecard.noAgent":  "sin agente instalado",
+			"scorecard.since":    "estado sin cambios desde %s",
+			"impact.summary":     "Una interrupción de %s afecta a %d recursos dependientes y %d servicios; %d alertas abiertas (severidad más alta: %s)",
+			"usage.noLimits":     "%d recursos gestionados; no se informan límites de licencia",
+			"usage.nearestLimit": "%d recursos gestionados; más cerca del límite: %s al %s%% (%d de %d, quedan %d, %s)",
+		},
+	},
+	"fr": {
+		Tag:          "fr",
+		decimalComma: true,
+		dateLayout:   "02/01/2006 15:04 MST",
+		messages: map[string]string{
+			"none":               "aucune",
+			"metric.cpu":         "CPU",
+			"metric.memory":      "mémoire",
+			"metric.disk":        "disque",
+			"quota.ok":           "correct",
+			"quota.warning":      "avertissement",
+			"quota.critical":     "critique",
+			"quota.exceeded":     "dépassé",
+			"quota.unlimited":    "illimité",
+			"scorecard.status":   "%s est %s",
+			"scorecard.metric":   "%s moyenne %s %% (max. %s %%)",
+			"scorecard.alerts":   "%d alertes ouvertes (gravité maximale : %s)",
+			"scorecard.patches":  "%d correctifs manquants (%d critiques)",
+			"scorecard.noAgent":  "aucun agent installé",
+			"scorecard.since":    "état inchangé depuis le %s",
+			"impact.summary":     "Une panne de %s impacte %d ressources en aval et %d services ; %d alertes ouvertes (gravité maximale : %s)",
+			"usage.noLimits":     "%d ressources gérées ; aucune limite de licence signalée",
+			"usage.nearestLimit": "%d ressources gérées ; la plus proche de sa limite : %s à %s %% (%d sur %d, %d restantes, %s)",
+		},
+	},
+	"de": {
+		Tag:          "de",
+		decimalComma: true,
+		dateLayout:   "02.01.2006 15:04 MST",
+		messages: map[string]string{
+			"none":               "keine",
+			"metric.cpu":         "CPU",
+			"metric.memory":      "Arbeitsspeicher",
+			"metric.disk":        "Festplatte",
+			"quota.ok":           "ok",
+			"quota.warning":      "Warnung",
+			"quota.critical":     "kritisch",
+			"quota.exceeded":     "überschritten",
+			"quota.unlimited":    "unbegrenzt",
+			"scorecard.status":   "%s ist %s",
+			"scorecard.metric":   "%s Durchschnitt %s %% (max. %s %%)",
+			"scorecard.alerts":   "%d offene Alarme (höchster Schweregrad: %s)",
+			"scorecard.patches":  "%d fehlende Patches (%d kritisch)",
+			"scorecard.noAgent":  "kein Agent installiert",
+			"scorecard.since":    "Status unverändert seit %s",
+			"impact.summary":     "Ein Ausfall von %s betrifft %d abhängige Ressourcen und %d Services; %d offene Alarme (höchster Schweregrad: %s)",
+			"usage.noLimits":     "%d verwaltete Ressourcen; keine Lizenzgrenzen gemeldet",
+			"usage.nearestLimit": "%d verwaltete Ressourcen; am nächsten an der Grenze: %s bei %s %% (%d von %d, %d verbleibend, %s)",
+		},
+	},
+	"pt": {
+		Tag:          "pt",
+		decimalComma: true,
+		dateLayout:   "02/01/2006 15:04 MST",
+		messages: map[string]string{
+			"none":               "nenhuma",
+			"metric.cpu":         "CPU",
+			"metric.memory":      "memória",
+			"metric.disk":        "disco",
+			"quota.ok":           "ok",
+			"quota.warning":      "aviso",
+			"quota.critical":     "crítico",
+			"quota.exceeded":     "excedido",
+			"quota.unlimited":    "ilimitado",
+			"scorecard.status":   "%s está %s",
+			"scorecard.metric":   "%s média %s%% (máx. %s%%)",
+			"scorecard.alerts":   "%d alertas abertos (severidade mais alta: %s)",
+			"scorecard.patches":  "%d patches pendentes (%d críticos)",
+			"scorecard.noAgent":  "nenhum agente instalado",
+			"scorecard.since":    "status inalterado desde %s",
+			"impact.summary":     "Uma interrupção de %s afeta %d recursos dependentes e %d serviços; %d alertas abertos (severidade mais alta: %s)",
+			"usage.noLimits":     "%d recursos gerenciados; nenhum limite de licença informado",
+			"usage.nearestLimit": "%d recursos gerenciados; mais próximo do limite: %s em %s%% (%d de %d, %d restantes, %s)",
+		},
+	},
+}
+
+// LookupLocale returns the locale for a tag such as "de" or "pt-BR"
+func LookupLocale(tag string) (*Locale, error) {
+	language := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	if locale, ok := locales[language]; ok {
+		return locale, nil
+	}
+
+	supported := make([]string, 0, len(locales))
+	for language := range locales {
+		supported = append(supported, language)
+	}
+	sort.Strings(supported)
+	return nil, fmt.Errorf("unsupported locale %q: supported languages are %s", tag, strings.Join(supported, ", "))
+}
+
+// localeFromRequest resolves the locale for a tool call: the locale
+// argument, then the configured default, then English
+func localeFromRequest(req mcp.CallToolRequest) (*Locale, error) {
+	tag := req.GetString("locale", "")
+	if tag == "" {
+		tag = getResponseDefaults().Locale
+	}
+	if tag == "" {
+		tag = defaultLocale
+	}
+	return LookupLocale(tag)
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestLookupLocale(t *testing.T) {
+	for tag, want := range map[string]string{"de": "de", "pt-BR": "pt", "es_MX": "es", " EN ": "en"} {
+		locale, err := LookupLocale(tag)
+		if err != nil || locale.Tag != want {
+			t.Errorf("Expected %q to resolve to %s, got %+v (%v)", tag, want, locale, err)
+		}
+	}
+
+	if _, err := LookupLocale("ja"); err == nil || !strings.Contains(err.Error(), "de, en, es, fr, pt") {
+		t.Errorf("Expected an unsupported locale error listing the supported languages, got %v", err)
+	}
+}
+
+func TestLocaleFormatting(t *testing.T) {
+	german := locales["de"]
+	if got := german.Decimal(12.34); got != "12,3" {
+		t.Errorf("Expected a decimal comma, got %s", got)
+	}
+	date := time.Date(2024, 3, 7, 14, 30, 0, 0, time.UTC)
+	if got := german.Date(date); got != "07.03.2024 14:30 UTC" {
+		t.Errorf("Unexpected German date: %s", got)
+	}
+	if got := locales[defaultLocale].Date(date); got != "Mar 7, 2024 14:30 UTC" {
+		t.Errorf("Unexpected English date: %s", got)
+	}
+}
+
+func TestLocaleCatalogsComplete(t *testing.T) {
+	for tag, locale := range locales {
+		for key := range englishMessages {
+			if _, ok := locale.messages[key]; !ok {
+				t.Errorf("Locale %s is missing message %s", tag, key)
+			}
+		}
+	}
+}
+
+func TestSummarizeUsageLocalized(t *testing.T) {
+	summary := summarizeUsage([]types.LicenseUsage{{Tier: "Standard", Limit: 200, Used: 171}}, locales["es"])
+
+	want := "171 recursos gestionados; más cerca del límite: Standard al 85,5% (171 de 200, quedan 29, advertencia)"
+	if summary.Summary != want {
+		t.Errorf("Expected %q, got %q", want, summary.Summary)
+	}
+	// Machine-readable fields stay untranslated
+	if summary.Tiers[0].Status != "warning" {
+		t.Errorf("Expected the status field to stay in English, got %s", summary.Tiers[0].Status)
+	}
+}
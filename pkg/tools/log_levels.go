@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// LogLevelReport is the result of the logLevels and setLogLevel admin actions
+type LogLevelReport struct {
+	// Default is the level of loggers whose module has no level set
+	Default string `json:"default"`
+	// Modules holds the levels set by module, e.g. client or tools.resources
+	Modules map[string]string `json:"modules"`
+}
+
+// toolModule returns the log module of a tool
+func toolModule(tool string) string {
+	return common.ModuleTools + "." + tool
+}
+
+// toolLogger returns the logger for a tool's handler
+func toolLogger(tool string) *common.CustomLogger {
+	return common.GetLogger().WithModule(toolModule(tool))
+}
+
+// logLevels reports the default log level and the levels set by module
+func logLevels() *LogLevelReport {
+	return &LogLevelReport{
+		Default: common.GetLogger().Level().String(),
+		Modules: common.ModuleLevels(),
+	}
+}
+
+// setLogLevel sets the level of a log module, or returns it to the default
+// level when level is "default"
+func setLogLevel(module, level string) (*LogLevelReport, error) {
+	if strings.EqualFold(level, "default") {
+		common.ResetModuleLevel(module)
+		common.GetLogger().Info("Log level of %s reset to the default", module)
+		return logLevels(), nil
+	}
+
+	parsed, err := common.ParseLogLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	if err := common.SetModuleLevel(module, parsed); err != nil {
+		return nil, err
+	}
+	common.GetLogger().Info("Log level of %s set to %s", module, parsed)
+	return logLevels(), nil
+}
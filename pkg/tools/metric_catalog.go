@@ -0,0 +1,249 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// defaultMetricCatalogSample and maxMetricCatalogSample bound the
+	// resources whose metric types are read to build a catalog
+	defaultMetricCatalogSample = 10
+	maxMetricCatalogSample     = 50
+	// metricRangeSample is how many of the sampled resources have their last
+	// day of data read for the typical ranges and collection intervals
+	metricRangeSample = 3
+	// metricRangeWindow is the window of data the ranges are taken from
+	metricRangeWindow = 24 * time.Hour
+	// metricCatalogTTL is how long a catalog is reused
+	metricCatalogTTL = time.Hour
+	// maxMetricCatalogs bounds the remembered catalogs
+	maxMetricCatalogs = 200
+)
+
+// cachedMetricCatalog is a remembered catalog
+type cachedMetricCatalog struct {
+	catalog types.MetricCatalog
+	expires time.Time
+}
+
+// MetricCatalogs builds the metric catalogs of resource types, remembering
+// them per tenant so agents can look up metric names without sampling again
+type MetricCatalogs struct {
+	mu       sync.Mutex
+	catalogs map[string]cachedMetricCatalog
+	now      func() time.Time
+}
+
+// NewMetricCatalogs creates an empty set of catalogs
+func NewMetricCatalogs() *MetricCatalogs {
+	return &MetricCatalogs{catalogs: map[string]cachedMetricCatalog{}, now: time.Now}
+}
+
+// metricCatalogs are the catalogs shared by the tools
+var metricCatalogs = NewMetricCatalogs()
+
+// Get returns the metric catalog of a resource type in a tenant, sampling
+// up to sampleSize resources unless a catalog built from at least as many is
+// remembered. Refresh samples again regardless.
+func (m *MetricCatalogs) Get(ctx context.Context, api ResourcesAPI, tenant, resourceType string, sampleSize int, refresh bool) (*types.MetricCatalog, error) {
+	if resourceType == "" {
+		return nil, errors.New("resourceType is required for getMetricCatalog")
+	}
+	if sampleSize <= 0 {
+		sampleSize = defaultMetricCatalogSample
+	}
+	sampleSize = min(sampleSize, maxMetricCatalogSample)
+	if tenant == "" {
+		tenant = client.DefaultTenant
+	}
+
+	key := tenant + "\x00" + strings.ToUpper(resourceType)
+	m.mu.Lock()
+	entry, ok := m.catalogs[key]
+	m.mu.Unlock()
+	if ok && !refresh && m.now().Before(entry.expires) && (entry.catalog.Sampled >= sampleSize || !entry.catalog.Truncated) {
+		catalog := entry.catalog
+		catalog.Cached = true
+		return &catalog, nil
+	}
+
+	catalog, err := buildMetricCatalog(ctx, api, resourceType, sampleSize, m.now())
+	if err != nil {
+		return nil, err
+	}
+	m.remember(key, *catalog)
+	return catalog, nil
+}
+
+// remember stores a catalog, making room by dropping expired entries and
+// then arbitrary ones
+func (m *MetricCatalogs) remember(key string, catalog types.MetricCatalog) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if len(m.catalogs) >= maxMetricCatalogs {
+		for stale, entry := range m.catalogs {
+			if !now.Before(entry.expires) || len(m.catalogs) >= maxMetricCatalogs {
+				delete(m.catalogs, stale)
+			}
+		}
+	}
+	m.catalogs[key] = cachedMetricCatalog{catalog: catalog, expires: now.Add(metricCatalogTTL)}
+}
+
+// metricObservations accumulates what was seen of one metric
+type metricObservations struct {
+	info   types.MetricCatalogInfo
+	values []float64
+	gaps   []float64
+}
+
+// buildMetricCatalog reads the metric types of up to sampleSize resources of
+// a type, then the last day of data of the first few collecting metrics
+func buildMetricCatalog(ctx context.Context, api ResourcesAPI, resourceType string, sampleSize int, now time.Time) (*types.MetricCatalog, error) {
+	catalog := &types.MetricCatalog{
+		ResourceType: resourceType,
+		Metrics:      []types.MetricCatalogInfo{},
+		BuiltAt:      now.UTC().Format(time.RFC3339),
+	}
+	observed := map[string]*metricObservations{}
+	var ranged []*types.DetailedResource
+
+	it := SearchResourcesIter(ctx, api, types.ResourceSearchParams{Type: resourceType, PageSize: min(sampleSize, schemaPageSize)})
+	for it.Next() {
+		resource := it.Value()
+		// The search may match loosely, so keep only the exact type
+		if !strings.EqualFold(resource.Type, resourceType) && !strings.EqualFold(resource.ResourceType, resourceType) {
+			continue
+		}
+		if catalog.Sampled == sampleSize {
+			catalog.Truncated = true
+			break
+		}
+		detailed, err := api.GetDetailed(ctx, resource.ID)
+		if err != nil {
+			if isNotFoundError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read the metrics of resource %s: %w", resource.ID, err)
+		}
+		catalog.Sampled++
+		seen := map[string]bool{}
+		for _, metric := range detailed.MetricTypes {
+			if metric.Name == "" || seen[metric.Name] {
+				continue
+			}
+			seen[metric.Name] = true
+			obs, ok := observed[metric.Name]
+			if !ok {
+				obs = &metricObservations{info: types.MetricCatalogInfo{Name: metric.Name}}
+				observed[metric.Name] = obs
+			}
+			obs.info.Resources++
+			if obs.info.Description == "" {
+				obs.info.Description = metric.Description
+			}
+			if obs.info.Unit == "" {
+				obs.info.Unit = metric.Unit
+			}
+		}
+		if len(seen) > 0 && len(ranged) < metricRangeSample {
+			ranged = append(ranged, detailed)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("failed to sample %s resources: %w", resourceType, err)
+	}
+
+	for _, resource := range ranged {
+		observeMetricData(ctx, api, resource, observed, now)
+	}
+
+	for _, obs := range observed {
+		info := obs.info
+		if catalog.Sampled > 0 {
+			info.Coverage = math.Round(float64(info.Resources)/float64(catalog.Sampled)*100) / 100
+		}
+		if len(obs.values) > 0 {
+			sort.Float64s(obs.values)
+			low, high := percentile(obs.values, 0.05), percentile(obs.values, 0.95)
+			info.TypicalMin, info.TypicalMax = &low, &high
+		}
+		if len(obs.gaps) > 0 {
+			sort.Float64s(obs.gaps)
+			info.IntervalSeconds = int(math.Round(percentile(obs.gaps, 0.5)))
+		}
+		catalog.Metrics = append(catalog.Metrics, info)
+	}
+	sort.Slice(catalog.Metrics, func(i, j int) bool {
+		a, b := catalog.Metrics[i], catalog.Metrics[j]
+		if a.Resources != b.Resources {
+			return a.Resources > b.Resources
+		}
+		return a.Name < b.Name
+	})
+	return catalog, nil
+}
+
+// observeMetricData reads the last day of a resource's metrics and records
+// their values and the gaps between consecutive points of each series. A
+// resource whose data cannot be read only leaves the ranges less precise.
+func observeMetricData(ctx context.Context, api ResourcesAPI, resource *types.DetailedResource, observed map[string]*metricObservations, now time.Time) {
+	names := make([]string, 0, len(resource.MetricTypes))
+	for _, metric := range resource.MetricTypes {
+		if metric.Name != "" {
+			names = append(names, metric.Name)
+		}
+	}
+	response, err := api.GetMetrics(ctx, resource.ID, types.ResourceMetricsRequest{
+		MetricNames: names,
+		StartTime:   now.Add(-metricRangeWindow).Format(time.RFC3339),
+		EndTime:     now.Format(time.RFC3339),
+	})
+	if err != nil {
+		common.GetLogger().Warn("Failed to read metric data of resource %s for the metric catalog: %v", resource.ID, err)
+		return
+	}
+
+	series := map[string][]time.Time{}
+	for _, point := range response.Metrics {
+		obs, ok := observed[point.Name]
+		if !ok {
+			continue
+		}
+		obs.values = append(obs.values, point.Value)
+		if obs.info.Unit == "" {
+			obs.info.Unit = point.Unit
+		}
+		if at, err := time.Parse(time.RFC3339, point.Timestamp); err == nil {
+			key := point.Name + "\x00" + point.Instance
+			series[key] = append(series[key], at)
+		}
+	}
+	for key, times := range series {
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		obs := observed[key[:strings.IndexByte(key, 0)]]
+		for i := 1; i < len(times); i++ {
+			if gap := times[i].Sub(times[i-1]).Seconds(); gap > 0 {
+				obs.gaps = append(obs.gaps, gap)
+			}
+		}
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
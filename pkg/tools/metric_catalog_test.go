@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestResourcesToolGetMetricCatalog(t *testing.T) {
+	metricCatalogs = NewMetricCatalogs()
+	defer func() { metricCatalogs = NewMetricCatalogs() }()
+
+	api := &FakeResourcesAPI{}
+	api.SearchReturns(&types.ResourceSearchResponse{Results: []types.Resource{
+		{ID: "r-1", Type: "SERVER"}, {ID: "r-2", Type: "SERVER"}, {ID: "r-3", Type: "SERVER_CLUSTER"},
+	}}, nil)
+	api.GetDetailedStub = func(ctx context.Context, id string) (*types.DetailedResource, error) {
+		metrics := []types.MetricType{{Name: "system.cpu.utilization", Unit: "%"}}
+		if id == "r-1" {
+			metrics = append(metrics, types.MetricType{Name: "system.disk.usage", Description: "Disk usage"})
+		}
+		return &types.DetailedResource{Resource: types.Resource{ID: id}, MetricTypes: metrics}, nil
+	}
+	api.GetMetricsStub = func(ctx context.Context, id string, request types.ResourceMetricsRequest) (*types.ResourceMetricsResponse, error) {
+		start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		response := &types.ResourceMetricsResponse{ResourceID: id}
+		for i := 0; i < 20; i++ {
+			at := start.Add(time.Duration(i) * 5 * time.Minute).Format(time.RFC3339)
+			response.Metrics = append(response.Metrics,
+				types.ResourceMetricDataPoint{Name: "system.cpu.utilization", Timestamp: at, Value: float64(i * 5)},
+				types.ResourceMetricDataPoint{Name: "system.disk.usage", Timestamp: at, Value: 40, Unit: "GB", Instance: fmt.Sprintf("disk%d", i%2)})
+		}
+		return response, nil
+	}
+
+	call := func() types.MetricCatalog {
+		t.Helper()
+		result, err := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+			"action": "getMetricCatalog", "resourceType": "server",
+		}), api)
+		if err != nil || result.IsError {
+			t.Fatalf("getMetricCatalog failed: %v %s", err, resultText(result))
+		}
+		var catalog types.MetricCatalog
+		if err := json.Unmarshal([]byte(resultText(result)), &catalog); err != nil {
+			t.Fatalf("Invalid catalog: %v", err)
+		}
+		return catalog
+	}
+
+	catalog := call()
+	if catalog.Sampled != 2 || len(catalog.Metrics) != 2 || catalog.Cached {
+		t.Fatalf("Expected the two servers to be sampled, got %+v", catalog)
+	}
+	cpu, disk := catalog.Metrics[0], catalog.Metrics[1]
+	if cpu.Name != "system.cpu.utilization" || cpu.Resources != 2 || cpu.Coverage != 1 || cpu.Unit != "%" {
+		t.Errorf("Unexpected cpu entry: %+v", cpu)
+	}
+	if cpu.TypicalMin == nil || *cpu.TypicalMin != 0 || *cpu.TypicalMax != 90 || cpu.IntervalSeconds != 300 {
+		t.Errorf("Expected the cpu range and interval from its data, got %+v", cpu)
+	}
+	if disk.Coverage != 0.5 || disk.Unit != "GB" || disk.IntervalSeconds != 600 {
+		t.Errorf("Expected the disk interval per instance, got %+v", disk)
+	}
+
+	details, metrics := api.GetDetailedCallCount(), api.GetMetricsCallCount()
+	if again := call(); !again.Cached || api.GetDetailedCallCount() != details || api.GetMetricsCallCount() != metrics {
+		t.Errorf("Expected the catalog to be reused, got %+v", again)
+	}
+
+	result, _ := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "getMetricCatalog",
+	}), api)
+	if !result.IsError || !strings.Contains(resultText(result), "resourceType is required") {
+		t.Errorf("Expected a missing resourceType to be refused, got %s", resultText(result))
+	}
+}
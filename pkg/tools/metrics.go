@@ -0,0 +1,300 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// maxMetricQueryResources bounds the resources of one time-series query
+	maxMetricQueryResources = 20
+	// maxSeriesPoints is the most points per series an automatic rollup allows
+	maxSeriesPoints = 500
+	// rawMetricRollup asks for data without a rollup
+	rawMetricRollup = "raw"
+	// maxRawMetricWindow is the longest window queried without a rollup by default
+	maxRawMetricWindow = 4 * time.Hour
+)
+
+// metricAggregations are the ways points are combined within a rollup interval
+var metricAggregations = []string{"avg", "min", "max", "sum"}
+
+var rollupPattern = regexp.MustCompile(`^(\d+)([a-z]+)$`)
+
+// NewMetricsMcpTool returns the MCP tool definition and handler for metric discovery and time-series queries
+func NewMetricsMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
+	// Get the logger
+	logger := common.GetLogger()
+
+	// Load configuration
+	config, err := common.LoadConfig("")
+	if err != nil {
+		logger.Error("Failed to load config for OpsRamp Metrics API: %v", err)
+		return mcp.Tool{}, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Configuration error: %v", err)}},
+			}, nil
+		}
+	}
+
+	// Create and initialize the API implementation for the configured backend
+	backend := config.ToolBackend("metrics").Backend
+	opsRampClient, err := newBackendClient(config, "metrics")
+	if err != nil {
+		logger.Error("Failed to initialize %s backend for OpsRamp Metrics API: %v", backend, err)
+		tool, _ := createMetricsTool(nil)
+		return withBackend(tool, backend), backendErrorHandler(err)
+	}
+	api := NewOpsRampMetricsAPI(opsRampClient)
+
+	logger.Info("Successfully initialized OpsRamp Metrics API (%s backend)", backend)
+	tool, handler := createMetricsTool(api)
+	return withBackend(tool, backend), handler
+}
+
+// createMetricsTool creates the MCP tool with the given API implementation
+func createMetricsTool(api MetricsAPI) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "metrics",
+		Description: "Discover and query HPE OpsRamp metrics: search the metrics OpsRamp collects, list the metrics of a resource, and read time series of a metric across resources with a rollup.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"action": map[string]interface{}{
+					"type":        "string",
+					"description": "Action to perform: search, listResourceMetrics, query. Use search or listResourceMetrics to find a metric's exact name before querying it",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Text matched against metric names and descriptions (for search)",
+				},
+				"resourceType": map[string]interface{}{
+					"type":        "string",
+					"description": "Resource type, e.g. SERVER (for search)",
+				},
+				"id": map[string]interface{}{
+					"type":        "string",
+					"description": "Resource ID (for listResourceMetrics)",
+				},
+				"resourceIds": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": fmt.Sprintf("Resource IDs whose series to read (for query, at most %d)", maxMetricQueryResources),
+				},
+				"metric": map[string]interface{}{
+					"type":        "string",
+					"description": "Metric name, e.g. system.cpu.utilization (for query)",
+				},
+				"instance": map[string]interface{}{
+					"type":        "string",
+					"description": "Instance of a dimensioned metric, e.g. a disk or interface (for query)",
+				},
+				"timeRange": timeRangeArgument,
+				"rollup": map[string]interface{}{
+					"type":        "string",
+					"enum":        append(append([]string{}, types.MetricRollups...), rawMetricRollup),
+					"description": fmt.Sprintf("Rollup interval of the series (for query). Defaults to the finest rollup giving at most %d points per series over the timeRange; raw returns the points as collected", maxSeriesPoints),
+				},
+				"aggregation": map[string]interface{}{
+					"type":        "string",
+					"enum":        metricAggregations,
+					"description": "How points are combined within each rollup interval (for query, default avg)",
+				},
+				"pageNo": map[string]interface{}{
+					"type":        "number",
+					"description": "Page number (for search)",
+				},
+				"pageSize": map[string]interface{}{
+					"type":        "number",
+					"description": "Page size (for search)",
+				},
+				"tenant":     tenantArgument,
+				"tenants":    tenantsArgument,
+				"llmCompact": llmCompactArgument,
+				"transform":  transformArgument,
+				"render":     renderArgument,
+				"columns":    columnsArgument,
+				"maxRows":    maxRowsArgument,
+				"example":    exampleArgument,
+			},
+			Required: []string{"action"},
+		},
+		OutputSchema: outputSchema(types.MetricSearchResponse{}, types.ResourceMetricNames{}, types.MetricQueryResult{}, types.TenantFanOutResult{}),
+	}, withTenantFanOut("metrics", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tenant, err := resolveTenant(req)
+		if err != nil {
+			return tenantErrorResult(err), nil
+		}
+		if tenant != nil {
+			return MetricsToolHandler(ctx, req, NewOpsRampMetricsAPI(tenant.Client))
+		}
+		return MetricsToolHandler(ctx, req, api)
+	})
+}
+
+// MetricsToolHandler routes requests to the correct method
+// Exported for testing purposes
+func MetricsToolHandler(ctx context.Context, req mcp.CallToolRequest, api MetricsAPI) (*mcp.CallToolResult, error) {
+	action := req.GetString("action", "")
+
+	// Log the tool execution
+	logger := toolLogger("metrics")
+	logger.LogToolExecution("metrics", action, req.GetArguments())
+
+	var err error
+	var result interface{}
+
+	switch action {
+	case "search":
+		logger.Info("Executing Search metrics")
+		result, err = api.Search(ctx, req.GetString("query", ""), req.GetString("resourceType", ""), req.GetInt("pageNo", 0), req.GetInt("pageSize", 0))
+	case "listResourceMetrics":
+		id := req.GetString("id", "")
+		if id == "" {
+			err = fmt.Errorf("id is required for listResourceMetrics")
+			break
+		}
+		logger.Info("Executing ListResourceMetrics for resource: %s", id)
+		var metrics []types.MetricDefinition
+		if metrics, err = api.ListResourceMetrics(ctx, id); err == nil {
+			result = &types.ResourceMetricNames{ResourceID: id, Metrics: metrics}
+		}
+	case "query":
+		query, message, queryErr := metricQueryFromRequest(req, time.Now())
+		if queryErr != nil {
+			err = queryErr
+			break
+		}
+		logger.Info("Executing Query of metric %s on %d resources", query.Metric, len(query.ResourceIDs))
+		var series []types.MetricSeries
+		if series, err = api.Query(ctx, query); err == nil {
+			result = metricQueryResult(query, series, message)
+		}
+	default:
+		logger.Error("Unknown action: %s", action)
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Unknown action: %s", action)}},
+		}, nil
+	}
+
+	// Log the result
+	logger.LogToolResult("metrics", action, result, err)
+
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+
+	return respondJSON(ctx, req, result)
+}
+
+// metricQueryFromRequest builds a time-series query from the tool arguments,
+// choosing a rollup when none is given. The message explains a chosen rollup.
+func metricQueryFromRequest(req mcp.CallToolRequest, now time.Time) (types.MetricQuery, string, error) {
+	query := types.MetricQuery{
+		Metric:      strings.TrimSpace(req.GetString("metric", "")),
+		ResourceIDs: req.GetStringSlice("resourceIds", nil),
+		Instance:    req.GetString("instance", ""),
+		Aggregation: req.GetString("aggregation", ""),
+	}
+	if query.Metric == "" {
+		return query, "", fmt.Errorf("metric is required for query")
+	}
+	if len(query.ResourceIDs) == 0 {
+		return query, "", fmt.Errorf("resourceIds is required for query")
+	}
+	if len(query.ResourceIDs) > maxMetricQueryResources {
+		return query, "", fmt.Errorf("query reads at most %d resources, got %d", maxMetricQueryResources, len(query.ResourceIDs))
+	}
+	if query.Aggregation != "" && !slices.Contains(metricAggregations, query.Aggregation) {
+		return query, "", fmt.Errorf("unknown aggregation %q: use one of %s", query.Aggregation, strings.Join(metricAggregations, ", "))
+	}
+
+	timeRange, err := ParseTimeRange(req.GetString("timeRange", "last24h"), now)
+	if err != nil {
+		return query, "", err
+	}
+	query.StartTime = timeRange.Start.Format(time.RFC3339)
+	query.EndTime = timeRange.End.Format(time.RFC3339)
+
+	message := ""
+	switch rollup := req.GetString("rollup", ""); rollup {
+	case rawMetricRollup:
+	case "":
+		query.Rollup = autoRollup(timeRange.End.Sub(timeRange.Start))
+		if query.Rollup != "" {
+			message = fmt.Sprintf("Rolled up to %s so each series has at most %d points; pass rollup to choose another interval", query.Rollup, maxSeriesPoints)
+		}
+	default:
+		if !slices.Contains(types.MetricRollups, rollup) {
+			return query, "", fmt.Errorf("unknown rollup %q: use one of %s or %s", rollup, strings.Join(types.MetricRollups, ", "), rawMetricRollup)
+		}
+		query.Rollup = rollup
+	}
+	if query.Rollup != "" && query.Aggregation == "" {
+		query.Aggregation = "avg"
+	}
+	return query, message, nil
+}
+
+// autoRollup returns the finest rollup giving at most maxSeriesPoints over a
+// window, or none for windows short enough to read raw
+func autoRollup(window time.Duration) string {
+	if window <= maxRawMetricWindow {
+		return ""
+	}
+	for _, rollup := range types.MetricRollups {
+		if interval, ok := rollupDuration(rollup); ok && window/interval <= maxSeriesPoints {
+			return rollup
+		}
+	}
+	return types.MetricRollups[len(types.MetricRollups)-1]
+}
+
+// rollupDuration parses a rollup such as 15m or 1d
+func rollupDuration(rollup string) (time.Duration, bool) {
+	match := rollupPattern.FindStringSubmatch(rollup)
+	if match == nil {
+		return 0, false
+	}
+	amount, err := strconv.Atoi(match[1])
+	unit, ok := relativeUnits[match[2]]
+	if err != nil || !ok || amount <= 0 {
+		return 0, false
+	}
+	return time.Duration(amount) * unit, true
+}
+
+// metricQueryResult wraps the series of a query, noting when none came back
+func metricQueryResult(query types.MetricQuery, series []types.MetricSeries, message string) *types.MetricQueryResult {
+	if series == nil {
+		series = []types.MetricSeries{}
+	}
+	result := &types.MetricQueryResult{
+		Metric:      query.Metric,
+		StartTime:   query.StartTime,
+		EndTime:     query.EndTime,
+		Rollup:      query.Rollup,
+		Aggregation: query.Aggregation,
+		Series:      series,
+		Message:     message,
+	}
+	if len(series) == 0 {
+		result.Message = fmt.Sprintf("No data for %s on these resources over the time range; check the metric name with listResourceMetrics", query.Metric)
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// MetricsAPI defines the contract for metric definition and time-series operations
+type MetricsAPI interface {
+	// Search lists the metrics OpsRamp collects, optionally for one resource type
+	Search(ctx context.Context, queryString, resourceType string, pageNo, pageSize int) (*types.MetricSearchResponse, error)
+
+	// ListResourceMetrics lists the metrics collected on a resource
+	ListResourceMetrics(ctx context.Context, resourceID string) ([]types.MetricDefinition, error)
+
+	// Query retrieves the time series of a metric on one or more resources
+	Query(ctx context.Context, query types.MetricQuery) ([]types.MetricSeries, error)
+}
+
+// OpsRampMetricsAPI implements the MetricsAPI interface for OpsRamp
+type OpsRampMetricsAPI struct {
+	client *client.OpsRampClient
+	logger *common.CustomLogger
+}
+
+// NewOpsRampMetricsAPI creates a new OpsRamp metrics API client
+func NewOpsRampMetricsAPI(client *client.OpsRampClient) *OpsRampMetricsAPI {
+	return &OpsRampMetricsAPI{
+		client: client,
+		logger: client.Logger().WithModule(toolModule("metrics")),
+	}
+}
+
+// Search lists the metrics OpsRamp collects, optionally for one resource type
+func (api *OpsRampMetricsAPI) Search(ctx context.Context, queryString, resourceType string, pageNo, pageSize int) (*types.MetricSearchResponse, error) {
+	api.logger.Info("Searching metrics with query: %s (resource type: %s)", queryString, resourceType)
+
+	endpoint, err := api.client.EndpointPath("metrics.search")
+	if err != nil {
+		return nil, fmt.Errorf("failed to search metrics: %w", err)
+	}
+
+	query := url.Values{}
+	if queryString != "" {
+		query.Set("queryString", queryString)
+	}
+	if resourceType != "" {
+		query.Set("resourceType", resourceType)
+	}
+	if pageNo > 0 {
+		query.Set("pageNo", strconv.Itoa(pageNo))
+	}
+	if pageSize > 0 {
+		query.Set("pageSize", strconv.Itoa(pageSize))
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var response types.MetricSearchResponse
+	if err := api.client.Get(ctx, endpoint, &response); err != nil {
+		api.logger.Error("Failed to search metrics: %v", err)
+		return nil, fmt.Errorf("failed to search metrics: %w", err)
+	}
+
+	response.Normalize(pageNo, pageSize)
+	api.logger.Info("Found %d metrics (total: %d)", len(response.Results), response.TotalResults)
+	return &response, nil
+}
+
+// ListResourceMetrics lists the metrics collected on a resource
+func (api *OpsRampMetricsAPI) ListResourceMetrics(ctx context.Context, resourceID string) ([]types.MetricDefinition, error) {
+	api.logger.Info("Listing metrics of resource: %s", resourceID)
+
+	endpoint, err := api.client.EndpointPath("metrics.resource", resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource metrics: %w", err)
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var response struct {
+		Metrics []types.MetricDefinition `json:"metrics"`
+	}
+	if err := api.client.Get(ctx, endpoint, &response); err != nil {
+		api.logger.Error("Failed to list metrics of resource %s: %v", resourceID, err)
+		return nil, fmt.Errorf("failed to list resource metrics: %w", err)
+	}
+
+	api.logger.Info("Resource %s collects %d metrics", resourceID, len(response.Metrics))
+	return response.Metrics, nil
+}
+
+// Query retrieves the time series of a metric on one or more resources
+func (api *OpsRampMetricsAPI) Query(ctx context.Context, query types.MetricQuery) ([]types.MetricSeries, error) {
+	api.logger.Info("Querying metric %s on %d resources (rollup: %s)", query.Metric, len(query.ResourceIDs), query.Rollup)
+
+	for _, resourceID := range query.ResourceIDs {
+		if err := client.ValidateID("resource", resourceID); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoint, err := api.client.EndpointPath("metrics.query")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metric: %w", err)
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var response struct {
+		Series []types.MetricSeries `json:"series"`
+	}
+	if err := api.client.Post(ctx, endpoint, query, &response); err != nil {
+		api.logger.Error("Failed to query metric %s: %v", query.Metric, err)
+		return nil, fmt.Errorf("failed to query metric %s: %w", query.Metric, err)
+	}
+
+	api.logger.Info("Retrieved %d series of metric %s", len(response.Series), query.Metric)
+	return response.Series, nil
+}
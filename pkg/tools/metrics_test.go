@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// recordingMetricsAPI records time-series queries; unused MetricsAPI methods panic
+type recordingMetricsAPI struct {
+	MetricsAPI
+	query  *types.MetricQuery
+	series []types.MetricSeries
+}
+
+func (m *recordingMetricsAPI) Query(ctx context.Context, query types.MetricQuery) ([]types.MetricSeries, error) {
+	m.query = &query
+	return m.series, nil
+}
+
+func TestMetricsQuery(t *testing.T) {
+	api := &recordingMetricsAPI{series: []types.MetricSeries{{
+		Metric: "system.cpu.utilization", ResourceID: "r-1", Unit: "%",
+		Points: []types.MetricPoint{{Timestamp: "2026-10-16T00:00:00Z", Value: 42}},
+	}}}
+	result, err := MetricsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "query", "metric": "system.cpu.utilization", "resourceIds": []interface{}{"r-1"}, "timeRange": "last7d",
+	}), api)
+	if err != nil || result.IsError {
+		t.Fatalf("query failed: %v %s", err, resultText(result))
+	}
+	var response types.MetricQueryResult
+	if err := json.Unmarshal([]byte(resultText(result)), &response); err != nil {
+		t.Fatalf("Invalid query result: %v", err)
+	}
+	if len(response.Series) != 1 || response.Series[0].Points[0].Value != 42 {
+		t.Errorf("Expected the series to be returned, got %+v", response)
+	}
+	// A week has 168 hours, the finest rollup under the point limit
+	if api.query.Rollup != "1h" || api.query.Aggregation != "avg" || response.Message == "" {
+		t.Errorf("Expected an hourly average rollup to be chosen, got %+v", api.query)
+	}
+
+	api.series = nil
+	result, _ = MetricsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "query", "metric": "system.cpu.utilisation", "resourceIds": []interface{}{"r-1"}, "rollup": "raw",
+	}), api)
+	if api.query.Rollup != "" || !strings.Contains(resultText(result), "check the metric name") {
+		t.Errorf("Expected raw data and a hint for an empty result, got %+v %s", api.query, resultText(result))
+	}
+
+	for want, args := range map[string]map[string]interface{}{
+		"metric is required":  {"action": "query", "resourceIds": []interface{}{"r-1"}},
+		"resourceIds is":      {"action": "query", "metric": "m"},
+		`unknown rollup "2m"`: {"action": "query", "metric": "m", "resourceIds": []interface{}{"r-1"}, "rollup": "2m"},
+	} {
+		result, _ := MetricsToolHandler(context.Background(), createTestRequest(args), api)
+		if !result.IsError || !strings.Contains(resultText(result), want) {
+			t.Errorf("Expected %q, got %s", want, resultText(result))
+		}
+	}
+}
+
+func TestAutoRollup(t *testing.T) {
+	for window, want := range map[time.Duration]string{
+		time.Hour:                "",
+		24 * time.Hour:           "5m",
+		7 * 24 * time.Hour:       "1h",
+		90 * 24 * time.Hour:      "6h",
+		3 * 365 * 24 * time.Hour: "1d",
+	} {
+		if got := autoRollup(window); got != want {
+			t.Errorf("Expected %s to roll up to %q, got %q", window, want, got)
+		}
+	}
+}
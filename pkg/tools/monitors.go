@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// NewMonitorsMcpTool returns the MCP tool definition and handler for custom script monitors
+func NewMonitorsMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
+	// Get the logger
+	logger := common.GetLogger()
+
+	// Load configuration
+	config, err := common.LoadConfig("")
+	if err != nil {
+		logger.Error("Failed to load config for OpsRamp Monitors API: %v", err)
+		return mcp.Tool{}, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Configuration error: %v", err)}},
+			}, nil
+		}
+	}
+
+	// Create and initialize the API implementation for the configured backend
+	backend := config.ToolBackend("monitors").Backend
+	opsRampClient, err := newBackendClient(config, "monitors")
+	if err != nil {
+		logger.Error("Failed to initialize %s backend for OpsRamp Monitors API: %v", backend, err)
+		tool, _ := createMonitorsTool(nil)
+		return withBackend(tool, backend), backendErrorHandler(err)
+	}
+	api := NewOpsRampMonitorsAPI(opsRampClient)
+
+	logger.Info("Successfully initialized OpsRamp Monitors API (%s backend)", backend)
+	tool, handler := createMonitorsTool(api)
+	return withBackend(tool, backend), handler
+}
+
+// createMonitorsTool creates the MCP tool with the given API implementation
+func createMonitorsTool(api MonitorsAPI) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "monitors",
+		Description: "Manage HPE OpsRamp custom script monitors (synthetic checks run by the agent): list, inspect, create and update monitors including their script, and assign them to resources.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"action": map[string]interface{}{
+					"type":        "string",
+					"description": "Action to perform: list, get, create, update, assign, unassign, listAssignments",
+				},
+				"id": map[string]interface{}{
+					"type":        "string",
+					"description": "Custom monitor ID (for get, update, assign, unassign, listAssignments)",
+				},
+				"config": map[string]interface{}{
+					"type": "object",
+					"description": fmt.Sprintf("Monitor definition (for create and update): {name, description, scriptType (%v), platform, script, scriptBase64, frequencyMinutes, timeoutSeconds, metrics: [{name, unit, warningThreshold, criticalThreshold}]}. "+
+						"Provide the script as text in script or encoded in scriptBase64; it must be UTF-8 text of at most %d KiB. On update only the given fields change.",
+						types.MonitorScriptTypes, types.MaxMonitorScriptBytes/1024),
+				},
+				"resourceIds": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Resource IDs (for assign and unassign)",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "OpsRamp queryString filter (for list)",
+				},
+				"pageNo": map[string]interface{}{
+					"type":        "number",
+					"description": "Page number (for list)",
+				},
+				"pageSize": map[string]interface{}{
+					"type":        "number",
+					"description": "Page size (for list)",
+				},
+				"tenant":     tenantArgument,
+				"tenants":    tenantsArgument,
+				"llmCompact": llmCompactArgument,
+				"transform":  transformArgument,
+				"render":     renderArgument,
+				"columns":    columnsArgument,
+				"maxRows":    maxRowsArgument,
+			},
+			Required: []string{"action"},
+		},
+		OutputSchema: outputSchema(types.CustomMonitorSearchResponse{}, types.CustomMonitor{}, types.MonitorAssignment{}, types.TenantFanOutResult{}),
+	}, withTenantFanOut("monitors", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tenant, err := resolveTenant(req)
+		if err != nil {
+			return tenantErrorResult(err), nil
+		}
+		if tenant != nil {
+			return MonitorsToolHandler(ctx, req, NewOpsRampMonitorsAPI(tenant.Client))
+		}
+		return MonitorsToolHandler(ctx, req, api)
+	})
+}
+
+// MonitorsToolHandler routes requests to the correct method
+// Exported for testing purposes
+func MonitorsToolHandler(ctx context.Context, req mcp.CallToolRequest, api MonitorsAPI) (*mcp.CallToolResult, error) {
+	action := req.GetString("action", "")
+	id := req.GetString("id", "")
+
+	// Log the tool execution without script content, which can be large
+	logger := toolLogger("monitors")
+	logger.LogToolExecution("monitors", action, map[string]interface{}{"id": id, "resourceIds": req.GetStringSlice("resourceIds", nil)})
+
+	var err error
+	var result interface{}
+
+	switch action {
+	case "list":
+		logger.Info("Executing List custom monitors")
+		result, err = api.Search(ctx, req.GetString("query", ""), req.GetInt("pageNo", 0), req.GetInt("pageSize", 0))
+	case "get":
+		if id == "" {
+			err = fmt.Errorf("id is required for get")
+			break
+		}
+		logger.Info("Executing Get custom monitor: %s", id)
+		result, err = api.Get(ctx, id)
+	case "create", "update":
+		if action == "update" && id == "" {
+			err = fmt.Errorf("id is required for update")
+			break
+		}
+		logger.Info("Executing %s custom monitor %s", action, id)
+		var request types.CustomMonitorRequest
+		request, err = monitorRequestFromArguments(req.GetArguments()["config"])
+		if err != nil {
+			break
+		}
+		if action == "create" {
+			result, err = api.Create(ctx, request)
+		} else {
+			result, err = api.Update(ctx, id, request)
+		}
+	case "assign", "unassign":
+		resourceIDs := req.GetStringSlice("resourceIds", nil)
+		if id == "" {
+			err = fmt.Errorf("id is required for %s", action)
+			break
+		}
+		if len(resourceIDs) == 0 {
+			err = fmt.Errorf("resourceIds is required for %s", action)
+			break
+		}
+		logger.Info("Executing %s custom monitor %s for %d resources", action, id, len(resourceIDs))
+		if action == "assign" {
+			err = api.Assign(ctx, id, resourceIDs)
+		} else {
+			err = api.Unassign(ctx, id, resourceIDs)
+		}
+		result = types.MonitorAssignment{MonitorID: id, Action: action, ResourceIDs: resourceIDs}
+	case "listAssignments":
+		if id == "" {
+			err = fmt.Errorf("id is required for listAssignments")
+			break
+		}
+		logger.Info("Executing ListAssignments for custom monitor: %s", id)
+		result, err = api.ListAssignments(ctx, id)
+	default:
+		logger.Error("Unknown action: %s", action)
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Unknown action: %s", action)}},
+		}, nil
+	}
+
+	// Log the result
+	logger.LogToolResult("monitors", action, result, err)
+
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+
+	return respondJSON(ctx, req, result)
+}
+
+// monitorRequestFromArguments converts the config argument to a monitor
+// request, decoding a base64 script upload
+func monitorRequestFromArguments(config interface{}) (types.CustomMonitorRequest, error) {
+	var request types.CustomMonitorRequest
+	configMap, ok := config.(map[string]interface{})
+	if !ok {
+		return request, fmt.Errorf("config is required for create and update")
+	}
+
+	if err := decodeArgument("monitors", "config", configMap, &request); err != nil {
+		return request, fmt.Errorf("failed to parse monitor config: %w", err)
+	}
+
+	encoded, _ := configMap["scriptBase64"].(string)
+	if encoded == "" {
+		return request, nil
+	}
+	if request.Script != "" {
+		return request, fmt.Errorf("provide the script in either script or scriptBase64, not both")
+	}
+	// Reject oversized uploads before decoding them
+	if base64.StdEncoding.DecodedLen(len(encoded)) > types.MaxMonitorScriptBytes+2 {
+		return request, fmt.Errorf("scriptBase64 decodes to more than the %d byte script limit", types.MaxMonitorScriptBytes)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return request, fmt.Errorf("scriptBase64 is not valid base64: %w", err)
+	}
+	request.Script = string(decoded)
+	return request, nil
+}
@@ -0,0 +1,206 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// MonitorsAPI defines the contract for custom (script) monitor operations
+type MonitorsAPI interface {
+	// Search lists custom monitors
+	Search(ctx context.Context, queryString string, pageNo, pageSize int) (*types.CustomMonitorSearchResponse, error)
+
+	// Get retrieves a custom monitor, including its script
+	Get(ctx context.Context, id string) (*types.CustomMonitor, error)
+
+	// Create creates a custom monitor
+	Create(ctx context.Context, request types.CustomMonitorRequest) (*types.CustomMonitor, error)
+
+	// Update changes the set fields of a custom monitor
+	Update(ctx context.Context, id string, request types.CustomMonitorRequest) (*types.CustomMonitor, error)
+
+	// Assign runs a custom monitor on the given resources
+	Assign(ctx context.Context, id string, resourceIDs []string) error
+
+	// Unassign stops a custom monitor on the given resources
+	Unassign(ctx context.Context, id string, resourceIDs []string) error
+
+	// ListAssignments lists the resources a custom monitor is assigned to
+	ListAssignments(ctx context.Context, id string) ([]types.MonitorAssignedResource, error)
+}
+
+// OpsRampMonitorsAPI implements the MonitorsAPI interface for OpsRamp
+type OpsRampMonitorsAPI struct {
+	client *client.OpsRampClient
+	logger *common.CustomLogger
+}
+
+// NewOpsRampMonitorsAPI creates a new OpsRamp custom monitors API client
+func NewOpsRampMonitorsAPI(client *client.OpsRampClient) *OpsRampMonitorsAPI {
+	return &OpsRampMonitorsAPI{
+		client: client,
+		logger: client.Logger().WithModule(toolModule("monitors")),
+	}
+}
+
+// Search lists custom monitors
+func (api *OpsRampMonitorsAPI) Search(ctx context.Context, queryString string, pageNo, pageSize int) (*types.CustomMonitorSearchResponse, error) {
+	api.logger.Info("Searching custom monitors with query: %s", queryString)
+
+	endpoint, err := api.client.EndpointPath("monitors.search")
+	if err != nil {
+		return nil, fmt.Errorf("failed to search custom monitors: %w", err)
+	}
+
+	query := url.Values{}
+	if queryString != "" {
+		query.Set("queryString", queryString)
+	}
+	if pageNo > 0 {
+		query.Set("pageNo", strconv.Itoa(pageNo))
+	}
+	if pageSize > 0 {
+		query.Set("pageSize", strconv.Itoa(pageSize))
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var response types.CustomMonitorSearchResponse
+	if err := api.client.Get(ctx, endpoint, &response); err != nil {
+		api.logger.Error("Failed to search custom monitors: %v", err)
+		return nil, fmt.Errorf("failed to search custom monitors: %w", err)
+	}
+
+	response.Normalize(pageNo, pageSize)
+	api.logger.Info("Found %d custom monitors (total: %d)", len(response.Results), response.TotalResults)
+	return &response, nil
+}
+
+// Get retrieves a custom monitor, including its script
+func (api *OpsRampMonitorsAPI) Get(ctx context.Context, id string) (*types.CustomMonitor, error) {
+	api.logger.Info("Getting custom monitor: %s", id)
+
+	endpoint, err := api.client.EndpointPath("monitors.get", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom monitor: %w", err)
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var monitor types.CustomMonitor
+	if err := api.client.Get(ctx, endpoint, &monitor); err != nil {
+		api.logger.Error("Failed to get custom monitor %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get custom monitor: %w", err)
+	}
+
+	return &monitor, nil
+}
+
+// Create creates a custom monitor
+func (api *OpsRampMonitorsAPI) Create(ctx context.Context, request types.CustomMonitorRequest) (*types.CustomMonitor, error) {
+	api.logger.Info("Creating custom monitor: %s (%d byte %s script)", request.Name, len(request.Script), request.ScriptType)
+
+	if err := request.Validate(true); err != nil {
+		return nil, fmt.Errorf("invalid custom monitor: %w", err)
+	}
+
+	endpoint, err := api.client.EndpointPath("monitors.create")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create custom monitor: %w", err)
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var monitor types.CustomMonitor
+	if err := api.client.Post(ctx, endpoint, request, &monitor); err != nil {
+		api.logger.Error("Failed to create custom monitor: %v", err)
+		return nil, fmt.Errorf("failed to create custom monitor: %w", err)
+	}
+
+	api.logger.Info("Successfully created custom monitor with ID: %s", monitor.ID)
+	return &monitor, nil
+}
+
+// Update changes the set fields of a custom monitor
+func (api *OpsRampMonitorsAPI) Update(ctx context.Context, id string, request types.CustomMonitorRequest) (*types.CustomMonitor, error) {
+	api.logger.Info("Updating custom monitor: %s", id)
+
+	if err := request.Validate(false); err != nil {
+		return nil, fmt.Errorf("invalid custom monitor update: %w", err)
+	}
+
+	endpoint, err := api.client.EndpointPath("monitors.update", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update custom monitor: %w", err)
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var monitor types.CustomMonitor
+	if err := api.client.Put(ctx, endpoint, request, &monitor); err != nil {
+		api.logger.Error("Failed to update custom monitor %s: %v", id, err)
+		return nil, fmt.Errorf("failed to update custom monitor: %w", err)
+	}
+
+	api.logger.Info("Successfully updated custom monitor: %s", id)
+	return &monitor, nil
+}
+
+// Assign runs a custom monitor on the given resources
+func (api *OpsRampMonitorsAPI) Assign(ctx context.Context, id string, resourceIDs []string) error {
+	return api.changeAssignment(ctx, "monitors.assign", id, resourceIDs)
+}
+
+// Unassign stops a custom monitor on the given resources
+func (api *OpsRampMonitorsAPI) Unassign(ctx context.Context, id string, resourceIDs []string) error {
+	return api.changeAssignment(ctx, "monitors.unassign", id, resourceIDs)
+}
+
+// changeAssignment posts resource IDs to an assign or unassign endpoint
+func (api *OpsRampMonitorsAPI) changeAssignment(ctx context.Context, endpointName, id string, resourceIDs []string) error {
+	api.logger.Info("Changing custom monitor %s assignment (%s) for %d resources", id, endpointName, len(resourceIDs))
+
+	for _, resourceID := range resourceIDs {
+		if err := client.ValidateID("resource", resourceID); err != nil {
+			return err
+		}
+	}
+
+	endpoint, err := api.client.EndpointPath(endpointName, id)
+	if err != nil {
+		return fmt.Errorf("failed to change custom monitor assignment: %w", err)
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	body := map[string]interface{}{"resourceIds": resourceIDs}
+	if err := api.client.Post(ctx, endpoint, body, nil); err != nil {
+		api.logger.Error("Failed to change custom monitor %s assignment: %v", id, err)
+		return fmt.Errorf("failed to change custom monitor assignment: %w", err)
+	}
+
+	return nil
+}
+
+// ListAssignments lists the resources a custom monitor is assigned to
+func (api *OpsRampMonitorsAPI) ListAssignments(ctx context.Context, id string) ([]types.MonitorAssignedResource, error) {
+	api.logger.Info("Listing resources assigned to custom monitor: %s", id)
+
+	endpoint, err := api.client.EndpointPath("monitors.assignments", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom monitor assignments: %w", err)
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var resources []types.MonitorAssignedResource
+	if err := api.client.Get(ctx, endpoint, &resources); err != nil {
+		api.logger.Error("Failed to list custom monitor %s assignments: %v", id, err)
+		return nil, fmt.Errorf("failed to list custom monitor assignments: %w", err)
+	}
+
+	return resources, nil
+}
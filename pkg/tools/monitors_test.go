@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// recordingMonitorsAPI records created monitors; unused MonitorsAPI methods panic
+type recordingMonitorsAPI struct {
+	MonitorsAPI
+	created *types.CustomMonitorRequest
+}
+
+func (m *recordingMonitorsAPI) Create(ctx context.Context, request types.CustomMonitorRequest) (*types.CustomMonitor, error) {
+	if err := request.Validate(true); err != nil {
+		return nil, err
+	}
+	m.created = &request
+	return &types.CustomMonitor{ID: "cm-1", Name: request.Name, ScriptType: request.ScriptType}, nil
+}
+
+func TestMonitorsCreateWithBase64Script(t *testing.T) {
+	script := "#!/bin/sh\necho 'disk.free 42'\n"
+	api := &recordingMonitorsAPI{}
+	res, err := MonitorsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "create",
+		"config": map[string]interface{}{
+			"name":         "Disk free",
+			"scriptType":   "shell",
+			"scriptBase64": base64.StdEncoding.EncodeToString([]byte(script)),
+		},
+	}), api)
+	if err != nil || res.IsError {
+		t.Fatalf("Expected create to succeed, got %+v (%v)", res, err)
+	}
+	if api.created == nil || api.created.Script != script {
+		t.Errorf("Expected the decoded script to be sent, got %+v", api.created)
+	}
+}
+
+func TestMonitorsRejectsOversizedScript(t *testing.T) {
+	oversized := strings.Repeat("x", types.MaxMonitorScriptBytes+1)
+	for name, config := range map[string]map[string]interface{}{
+		"text":   {"name": "Big", "scriptType": "shell", "script": oversized},
+		"base64": {"name": "Big", "scriptType": "shell", "scriptBase64": base64.StdEncoding.EncodeToString([]byte(oversized))},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := &recordingMonitorsAPI{}
+			res, _ := MonitorsToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+				"action": "create",
+				"config": config,
+			}), api)
+			if !res.IsError || api.created != nil {
+				t.Errorf("Expected an oversized script to be rejected, got %+v", res)
+			}
+		})
+	}
+}
+
+func TestCustomMonitorRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request types.CustomMonitorRequest
+		create  bool
+		wantErr string
+	}{
+		{name: "missing fields", request: types.CustomMonitorRequest{Name: "x"}, create: true, wantErr: "scriptType, script"},
+		{name: "unknown script type", request: types.CustomMonitorRequest{ScriptType: "ruby"}, wantErr: "unsupported scriptType"},
+		{name: "binary script", request: types.CustomMonitorRequest{Script: "MZ\x00\x90"}, wantErr: "UTF-8 text"},
+		{name: "timeout beyond interval", request: types.CustomMonitorRequest{FrequencyMinutes: 1, TimeoutSeconds: 120}, wantErr: "cannot exceed"},
+		{name: "partial update", request: types.CustomMonitorRequest{FrequencyMinutes: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate(tt.create)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
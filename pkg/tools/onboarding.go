@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// defaultOnboardingLimit is the number of resources onboarded per run by default
+	defaultOnboardingLimit = 25
+	// maxOnboardingLimit bounds the number of resources onboarded per run
+	maxOnboardingLimit = 100
+	// unmanagedState is the state of discovered resources not yet under management
+	unmanagedState = "unmanaged"
+	// managedState is the state resources are moved to when onboarded
+	managedState = "managed"
+)
+
+// Onboarding step and outcome statuses
+const (
+	onboardingPlanned   = "planned"
+	onboardingSucceeded = "succeeded"
+	onboardingFailed    = "failed"
+	onboardingSkipped   = "skipped"
+)
+
+// Onboarder brings discovered-but-unmanaged resources under management: it
+// manages each matching resource, assigns it to a device group and site,
+// applies baseline monitoring templates and tags it
+type Onboarder struct {
+	api    ResourcesAPI
+	logger *common.CustomLogger
+}
+
+// NewOnboarder creates a new Onboarder
+func NewOnboarder(api ResourcesAPI) *Onboarder {
+	return &Onboarder{
+		api:    api,
+		logger: common.GetLogger(),
+	}
+}
+
+// onboardingStep is a single named step applied to a resource
+type onboardingStep struct {
+	name string
+	run  func(ctx context.Context, id string) error
+}
+
+// Run onboards the resources matching the request filter and reports the
+// outcome for each one. A failed step stops the remaining steps for that
+// resource but does not affect other resources.
+func (o *Onboarder) Run(ctx context.Context, request types.OnboardingRequest) (*types.OnboardingResult, error) {
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultOnboardingLimit
+	}
+	if limit > maxOnboardingLimit {
+		return nil, fmt.Errorf("limit cannot exceed %d resources per run", maxOnboardingLimit)
+	}
+
+	if request.DeviceGroupID == "" && request.SiteID == "" && len(request.TemplateIDs) == 0 && len(request.Tags) == 0 {
+		return nil, fmt.Errorf("at least one of deviceGroupId, siteId, templateIds or tags is required")
+	}
+	steps := o.plan(request)
+
+	// Find discovered resources that are not yet managed
+	filter := request.Filter
+	if filter.State == "" {
+		filter.State = unmanagedState
+	}
+	filter.PageNo = 1
+	filter.PageSize = limit
+
+	candidates, err := o.api.Search(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unmanaged resources: %w", err)
+	}
+
+	result := &types.OnboardingResult{
+		DryRun:   request.DryRun,
+		Matched:  candidates.TotalResults,
+		Outcomes: make([]types.OnboardingOutcome, 0, len(candidates.Results)),
+	}
+
+	for _, resource := range candidates.Results {
+		if len(result.Outcomes) >= limit {
+			break
+		}
+		outcome := o.onboardResource(ctx, resource, steps, request.DryRun)
+		switch outcome.Status {
+		case onboardingSucceeded:
+			result.Succeeded++
+		case onboardingFailed:
+			result.Failed++
+		}
+		result.Outcomes = append(result.Outcomes, outcome)
+	}
+
+	result.Processed = len(result.Outcomes)
+	if remaining := result.Matched - result.Processed; remaining > 0 {
+		result.Remaining = remaining
+	}
+
+	o.logger.Info("Onboarding run complete: %d processed, %d succeeded, %d failed (dryRun=%v)",
+		result.Processed, result.Succeeded, result.Failed, request.DryRun)
+	return result, nil
+}
+
+// plan returns the ordered steps applied to each resource
+func (o *Onboarder) plan(request types.OnboardingRequest) []onboardingStep {
+	steps := []onboardingStep{{
+		name: "manage",
+		run: func(ctx context.Context, id string) error {
+			return o.api.ChangeState(ctx, id, types.ResourceStateChangeRequest{State: managedState})
+		},
+	}}
+
+	if request.DeviceGroupID != "" {
+		steps = append(steps, onboardingStep{
+			name: "assignDeviceGroup",
+			run: func(ctx context.Context, id string) error {
+				return o.api.AssignToDeviceGroup(ctx, request.DeviceGroupID, []string{id})
+			},
+		})
+	}
+	if request.SiteID != "" {
+		steps = append(steps, onboardingStep{
+			name: "assignSite",
+			run: func(ctx context.Context, id string) error {
+				return o.api.AssignToSite(ctx, request.SiteID, []string{id})
+			},
+		})
+	}
+	if len(request.TemplateIDs) > 0 {
+		steps = append(steps, onboardingStep{
+			name: "applyTemplates",
+			run: func(ctx context.Context, id string) error {
+				return o.api.ApplyTemplates(ctx, id, request.TemplateIDs)
+			},
+		})
+	}
+	if len(request.Tags) > 0 {
+		steps = append(steps, onboardingStep{
+			name: "tag",
+			run: func(ctx context.Context, id string) error {
+				return o.api.UpdateTags(ctx, id, request.Tags)
+			},
+		})
+	}
+
+	return steps
+}
+
+// onboardResource applies the steps to one resource, stopping at the first failure
+func (o *Onboarder) onboardResource(ctx context.Context, resource types.Resource, steps []onboardingStep, dryRun bool) types.OnboardingOutcome {
+	outcome := types.OnboardingOutcome{
+		ResourceID: resource.ID,
+		Name:       displayName(resource.Name, resource.HostName),
+		Status:     onboardingSucceeded,
+		Steps:      make([]types.OnboardingStep, 0, len(steps)),
+	}
+	if dryRun {
+		outcome.Status = onboardingPlanned
+	}
+
+	for _, step := range steps {
+		switch {
+		case dryRun:
+			outcome.Steps = append(outcome.Steps, types.OnboardingStep{Name: step.name, Status: onboardingPlanned})
+		case outcome.Status == onboardingFailed:
+			outcome.Steps = append(outcome.Steps, types.OnboardingStep{Name: step.name, Status: onboardingSkipped})
+		default:
+			if err := step.run(ctx, resource.ID); err != nil {
+				o.logger.Warn("Onboarding step %s failed for resource %s: %v", step.name, resource.ID, err)
+				outcome.Status = onboardingFailed
+				outcome.Steps = append(outcome.Steps, types.OnboardingStep{Name: step.name, Status: onboardingFailed, Error: err.Error()})
+				continue
+			}
+			outcome.Steps = append(outcome.Steps, types.OnboardingStep{Name: step.name, Status: onboardingSucceeded})
+		}
+	}
+
+	return outcome
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// onboardingResourcesAPI records onboarding calls; unused ResourcesAPI methods panic
+type onboardingResourcesAPI struct {
+	ResourcesAPI
+	unmanaged     []types.Resource
+	failTemplates map[string]bool
+	searchedState string
+	managed       []string
+	groupAssigned []string
+	templatesFor  []string
+	tagged        []string
+}
+
+func (m *onboardingResourcesAPI) Search(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+	m.searchedState = params.State
+	return &types.ResourceSearchResponse{Results: m.unmanaged, TotalResults: len(m.unmanaged)}, nil
+}
+
+func (m *onboardingResourcesAPI) ChangeState(ctx context.Context, id string, request types.ResourceStateChangeRequest) error {
+	m.managed = append(m.managed, id)
+	return nil
+}
+
+func (m *onboardingResourcesAPI) AssignToDeviceGroup(ctx context.Context, groupID string, resourceIDs []string) error {
+	m.groupAssigned = append(m.groupAssigned, resourceIDs...)
+	return nil
+}
+
+func (m *onboardingResourcesAPI) ApplyTemplates(ctx context.Context, id string, templateIDs []string) error {
+	if m.failTemplates[id] {
+		return errors.New("template not compatible")
+	}
+	m.templatesFor = append(m.templatesFor, id)
+	return nil
+}
+
+func (m *onboardingResourcesAPI) UpdateTags(ctx context.Context, id string, tags []types.Tag) error {
+	m.tagged = append(m.tagged, id)
+	return nil
+}
+
+func TestOnboarder_Run(t *testing.T) {
+	api := &onboardingResourcesAPI{
+		unmanaged: []types.Resource{
+			{ID: "r1", Name: "web-01"},
+			{ID: "r2", Name: "web-02"},
+		},
+		failTemplates: map[string]bool{"r2": true},
+	}
+
+	result, err := NewOnboarder(api).Run(context.Background(), types.OnboardingRequest{
+		DeviceGroupID: "dg-1",
+		TemplateIDs:   []string{"tpl-linux"},
+		Tags:          []types.Tag{{Name: "env", Value: "prod"}},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if api.searchedState != unmanagedState {
+		t.Errorf("Expected search to target unmanaged resources, got state %q", api.searchedState)
+	}
+	if result.Processed != 2 || result.Succeeded != 1 || result.Failed != 1 {
+		t.Errorf("Unexpected summary: %+v", result)
+	}
+
+	failed := result.Outcomes[1]
+	if failed.Status != onboardingFailed || len(failed.Steps) != 4 {
+		t.Fatalf("Unexpected outcome for r2: %+v", failed)
+	}
+	if failed.Steps[2].Status != onboardingFailed || failed.Steps[3].Status != onboardingSkipped {
+		t.Errorf("Expected template step to fail and tagging to be skipped, got %+v", failed.Steps)
+	}
+	if len(api.tagged) != 1 || api.tagged[0] != "r1" {
+		t.Errorf("Expected only r1 to be tagged, got %v", api.tagged)
+	}
+}
+
+func TestOnboarder_DryRunAndValidation(t *testing.T) {
+	api := &onboardingResourcesAPI{unmanaged: []types.Resource{{ID: "r1"}}}
+
+	result, err := NewOnboarder(api).Run(context.Background(), types.OnboardingRequest{SiteID: "site-1", DryRun: true})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(api.managed) != 0 {
+		t.Error("Dry run must not change any resource")
+	}
+	if result.Outcomes[0].Status != onboardingPlanned {
+		t.Errorf("Expected planned outcome, got %+v", result.Outcomes[0])
+	}
+
+	if _, err := NewOnboarder(api).Run(context.Background(), types.OnboardingRequest{}); err == nil {
+		t.Error("Expected error when no onboarding steps are requested")
+	}
+	if _, err := NewOnboarder(api).Run(context.Background(), types.OnboardingRequest{SiteID: "s", Limit: 1000}); err == nil {
+		t.Error("Expected error when limit exceeds the maximum")
+	}
+}
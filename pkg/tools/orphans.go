@@ -0,0 +1,254 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// maxOrphanResourceScan bounds the resources read to attribute them to integrations
+	maxOrphanResourceScan = 10000
+	// maxOrphanEventScan bounds the alerts read to measure event activity
+	maxOrphanEventScan = 2000
+	// orphanPageSize is the page size used when scanning resources and alerts
+	orphanPageSize = 100
+)
+
+// ResourcesProvider is implemented by integrations APIs that can also query
+// the tenant's resources
+type ResourcesProvider interface {
+	Resources() ResourcesAPI
+}
+
+// integrationsWithResources pairs an integrations API with the resources API
+// of the same tenant
+type integrationsWithResources struct {
+	IntegrationsAPI
+	resources ResourcesAPI
+}
+
+// Resources implements ResourcesProvider
+func (i *integrationsWithResources) Resources() ResourcesAPI {
+	return i.resources
+}
+
+// OrphanFinder cross-references installed integrations with the sources of
+// the tenant's resources and recent alert activity on them
+type OrphanFinder struct {
+	integrations IntegrationsAPI
+	resources    ResourcesAPI
+	alerts       AlertsAPI
+	logger       *common.CustomLogger
+}
+
+// NewOrphanFinder creates an OrphanFinder. Event activity is reported as
+// unavailable when the resources API cannot query alerts.
+func NewOrphanFinder(integrations IntegrationsAPI, resources ResourcesAPI) *OrphanFinder {
+	f := &OrphanFinder{
+		integrations: integrations,
+		resources:    resources,
+		logger:       common.GetLogger(),
+	}
+	if provider, ok := resources.(AlertsProvider); ok {
+		f.alerts = provider.Alerts()
+	}
+	return f
+}
+
+// Find reports every installed integration with the evidence gathered for
+// it, orphaned integrations first. An integration is orphaned when no
+// resource is attributed to it and none of its resources raised an alert
+// within the time range.
+func (f *OrphanFinder) Find(ctx context.Context, timeRange TimeRange) (*types.OrphanedIntegrationsReport, error) {
+	installed, err := f.integrations.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list integrations: %w", err)
+	}
+
+	report := &types.OrphanedIntegrationsReport{
+		WindowStart:  timeRange.Start.UTC().Format(time.RFC3339),
+		WindowEnd:    timeRange.End.UTC().Format(time.RFC3339),
+		Installed:    len(installed),
+		Integrations: make([]types.IntegrationActivity, 0, len(installed)),
+		Unavailable:  map[string]string{},
+	}
+
+	// Attribute resources to integrations by their source
+	sourceByResource, resourcesBySource, truncated, err := f.scanResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.ResourcesScanned = len(sourceByResource)
+	report.Truncated = truncated
+
+	// Count alerts raised within the window per resource source
+	eventsBySource := map[string]int{}
+	lastEventBySource := map[string]string{}
+	if f.alerts == nil {
+		report.Unavailable["events"] = "alert activity is not available from this resources backend"
+	} else {
+		scanned, eventsTruncated, err := f.scanEvents(ctx, timeRange, sourceByResource, eventsBySource, lastEventBySource)
+		if err != nil {
+			f.logger.Warn("Orphaned integrations: event activity unavailable: %v", err)
+			report.Unavailable["events"] = err.Error()
+		}
+		report.EventsScanned = scanned
+		report.Truncated = report.Truncated || eventsTruncated
+	}
+
+	for _, integration := range installed {
+		activity := types.IntegrationActivity{
+			IntegrationID: integration.ID,
+			Name:          displayName(integration.DisplayName, integration.Name),
+			App:           integration.App,
+			State:         defaultString(integration.State, integration.Status),
+			InstalledTime: integration.InstalledTime,
+			Evidence:      []string{},
+		}
+
+		matched := []string{}
+		for _, key := range integrationSourceKeys(integration) {
+			if count := resourcesBySource[key]; count > 0 {
+				activity.Resources += count
+				matched = append(matched, key)
+			}
+			activity.Events += eventsBySource[key]
+			if last := lastEventBySource[key]; last > activity.LastEventTime {
+				activity.LastEventTime = last
+			}
+		}
+		if len(matched) > 0 {
+			activity.Evidence = append(activity.Evidence,
+				fmt.Sprintf("%d resources report source %s", activity.Resources, strings.Join(matched, ", ")))
+		} else {
+			activity.Evidence = append(activity.Evidence, "no resources report this integration as their source")
+		}
+
+		detailed, err := f.integrations.GetDetailed(ctx, integration.ID)
+		if err != nil {
+			activity.Evidence = append(activity.Evidence, fmt.Sprintf("integration details unavailable: %v", err))
+		} else {
+			activity.DiscoveredResources = len(detailed.Resources)
+			activity.LastDiscoveryRun = detailed.LastDiscoveryRun
+			if run := detailed.LastDiscoveryRun; run != nil {
+				activity.Evidence = append(activity.Evidence, fmt.Sprintf("last discovery run %s at %s found %d resources",
+					strings.ToLower(run.Status), run.EndTime.UTC().Format(time.RFC3339), run.Resources))
+			} else {
+				activity.Evidence = append(activity.Evidence, "no discovery run recorded")
+			}
+		}
+
+		if _, ok := report.Unavailable["events"]; !ok {
+			if activity.Events > 0 {
+				activity.Evidence = append(activity.Evidence,
+					fmt.Sprintf("%d alerts on its resources in the window, last at %s", activity.Events, activity.LastEventTime))
+			} else {
+				activity.Evidence = append(activity.Evidence, "no alerts on its resources in the window")
+			}
+		}
+
+		activity.Orphaned = activity.Resources == 0 && activity.DiscoveredResources == 0 && activity.Events == 0
+		if activity.Orphaned {
+			report.OrphanedCount++
+		}
+		report.Integrations = append(report.Integrations, activity)
+	}
+
+	sort.SliceStable(report.Integrations, func(i, j int) bool {
+		return report.Integrations[i].Orphaned && !report.Integrations[j].Orphaned
+	})
+
+	f.logger.Info("Found %d orphaned integrations of %d installed (%d resources, %d events scanned)",
+		report.OrphanedCount, report.Installed, report.ResourcesScanned, report.EventsScanned)
+	return report, nil
+}
+
+// scanResources pages through the tenant's resources, returning each
+// resource's normalized source and the resource count per source
+func (f *OrphanFinder) scanResources(ctx context.Context) (map[string]string, map[string]int, bool, error) {
+	sourceByResource := map[string]string{}
+	resourcesBySource := map[string]int{}
+
+	params := types.ResourceSearchParams{PageSize: orphanPageSize}
+	for params.PageNo = 1; ; params.PageNo++ {
+		page, err := f.resources.Search(ctx, params)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("failed to scan resources: %w", err)
+		}
+		for _, resource := range page.Results {
+			source := normalizeSource(resource.Source)
+			sourceByResource[resource.ID] = source
+			if source != "" {
+				resourcesBySource[source]++
+			}
+		}
+		if !page.NextPage || len(page.Results) == 0 {
+			return sourceByResource, resourcesBySource, false, nil
+		}
+		if len(sourceByResource) >= maxOrphanResourceScan {
+			return sourceByResource, resourcesBySource, true, nil
+		}
+	}
+}
+
+// scanEvents pages through the alerts raised within the time range and
+// attributes them to the source of the resource they were raised on
+func (f *OrphanFinder) scanEvents(ctx context.Context, timeRange TimeRange, sourceByResource map[string]string, eventsBySource map[string]int, lastEventBySource map[string]string) (int, bool, error) {
+	scanned := 0
+	params := types.AlertSearchParams{
+		QueryString: alertSearchQuery("", timeRange),
+		PageSize:    orphanPageSize,
+	}
+	for params.PageNo = 1; ; params.PageNo++ {
+		page, err := f.alerts.Search(ctx, params)
+		if err != nil {
+			return scanned, false, fmt.Errorf("failed to scan alerts: %w", err)
+		}
+		for _, alert := range page.Results {
+			scanned++
+			if alert.Resource == nil {
+				continue
+			}
+			source := sourceByResource[alert.Resource.ID]
+			if source == "" {
+				continue
+			}
+			eventsBySource[source]++
+			if alert.CreatedDate > lastEventBySource[source] {
+				lastEventBySource[source] = alert.CreatedDate
+			}
+		}
+		if !page.NextPage || len(page.Results) == 0 {
+			return scanned, false, nil
+		}
+		if scanned >= maxOrphanEventScan {
+			return scanned, true, nil
+		}
+	}
+}
+
+// integrationSourceKeys returns the distinct normalized names a resource's
+// source may use to refer to the integration
+func integrationSourceKeys(integration types.Integration) []string {
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, candidate := range []string{integration.ID, integration.Name, integration.DisplayName, integration.App} {
+		key := normalizeSource(candidate)
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// normalizeSource folds case and surrounding whitespace out of a source name
+func normalizeSource(source string) string {
+	return strings.ToLower(strings.TrimSpace(source))
+}
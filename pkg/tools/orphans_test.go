@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// orphanIntegrationsAPI serves fixed installed integrations; unused
+// IntegrationsAPI methods panic
+type orphanIntegrationsAPI struct {
+	IntegrationsAPI
+	installed  []types.Integration
+	discovered map[string]int
+}
+
+func (m *orphanIntegrationsAPI) List(ctx context.Context) ([]types.Integration, error) {
+	return m.installed, nil
+}
+
+func (m *orphanIntegrationsAPI) GetDetailed(ctx context.Context, id string) (*types.DetailedIntegration, error) {
+	if id == "broken" {
+		return nil, fmt.Errorf("integration not found")
+	}
+	detailed := &types.DetailedIntegration{}
+	for i := 0; i < m.discovered[id]; i++ {
+		detailed.Resources = append(detailed.Resources, types.IntegrationResource{ID: fmt.Sprintf("%s-r%d", id, i)})
+	}
+	return detailed, nil
+}
+
+// orphanResourcesAPI pages through fixed resources and serves fixed alerts
+type orphanResourcesAPI struct {
+	ResourcesAPI
+	resources []types.Resource
+	alerts    *mockAlertsAPI
+}
+
+func (m *orphanResourcesAPI) Search(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+	start := (params.PageNo - 1) * params.PageSize
+	end := min(start+params.PageSize, len(m.resources))
+	return &types.ResourceSearchResponse{Results: m.resources[start:end], NextPage: end < len(m.resources)}, nil
+}
+
+func (m *orphanResourcesAPI) Alerts() AlertsAPI {
+	return m.alerts
+}
+
+func TestOrphanFinder(t *testing.T) {
+	integrations := &orphanIntegrationsAPI{
+		installed: []types.Integration{
+			{ID: "aws-1", Name: "AWS", State: "INSTALLED"},
+			{ID: "vmware-1", Name: "vcenter-prod", App: "VMware"},
+			{ID: "azure-1", Name: "Azure", DisplayName: "Azure Dev"},
+			{ID: "syslog-1", Name: "syslog"},
+		},
+		discovered: map[string]int{"vmware-1": 2},
+	}
+
+	resources := make([]types.Resource, 0, 150)
+	for i := 0; i < 150; i++ {
+		resources = append(resources, types.Resource{ID: fmt.Sprintf("r%d", i), Source: "aws "})
+	}
+	resources = append(resources, types.Resource{ID: "s1", Source: "Syslog"})
+	api := &orphanResourcesAPI{
+		resources: resources,
+		alerts: &mockAlertsAPI{open: []types.AlertDetails{
+			{ID: "a1", Resource: &types.AlertResource{ID: "r3"}, CreatedDate: "2026-10-15T10:00:00"},
+			{ID: "a2", Resource: &types.AlertResource{ID: "r7"}, CreatedDate: "2026-10-16T09:00:00"},
+			{ID: "a3"},
+		}},
+	}
+
+	end := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	report, err := NewOrphanFinder(integrations, api).Find(context.Background(), TimeRange{Start: end.Add(-7 * 24 * time.Hour), End: end})
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+
+	if report.Installed != 4 || report.OrphanedCount != 1 {
+		t.Fatalf("Expected 1 of 4 integrations orphaned, got %+v", report)
+	}
+	if report.ResourcesScanned != 151 || report.EventsScanned != 3 {
+		t.Errorf("Expected 151 resources and 3 events scanned, got %d and %d", report.ResourcesScanned, report.EventsScanned)
+	}
+
+	orphan := report.Integrations[0]
+	if orphan.IntegrationID != "azure-1" || !orphan.Orphaned || orphan.Name != "Azure Dev" {
+		t.Fatalf("Expected Azure Dev listed first as orphaned, got %+v", orphan)
+	}
+	if !strings.Contains(strings.Join(orphan.Evidence, "; "), "no resources report this integration") {
+		t.Errorf("Expected missing-resources evidence, got %v", orphan.Evidence)
+	}
+
+	byID := map[string]types.IntegrationActivity{}
+	for _, activity := range report.Integrations {
+		byID[activity.IntegrationID] = activity
+	}
+	if aws := byID["aws-1"]; aws.Orphaned || aws.Resources != 150 || aws.Events != 2 || aws.LastEventTime != "2026-10-16T09:00:00" {
+		t.Errorf("Unexpected AWS activity: %+v", aws)
+	}
+	if vmware := byID["vmware-1"]; vmware.Orphaned || vmware.DiscoveredResources != 2 {
+		t.Errorf("Expected VMware kept alive by discovered resources: %+v", vmware)
+	}
+	if syslog := byID["syslog-1"]; syslog.Orphaned || syslog.Resources != 1 || syslog.Events != 0 {
+		t.Errorf("Unexpected syslog activity: %+v", syslog)
+	}
+}
+
+func TestOrphanFinderWithoutAlerts(t *testing.T) {
+	integrations := &orphanIntegrationsAPI{installed: []types.Integration{{ID: "broken", Name: "Broken"}}}
+	api := &selectorResourcesAPI{}
+
+	report, err := NewOrphanFinder(integrations, api).Find(context.Background(), TimeRange{Start: time.Now().Add(-time.Hour), End: time.Now()})
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if _, ok := report.Unavailable["events"]; !ok {
+		t.Errorf("Expected events to be reported unavailable, got %+v", report.Unavailable)
+	}
+	evidence := strings.Join(report.Integrations[0].Evidence, "; ")
+	if !strings.Contains(evidence, "integration details unavailable") || strings.Contains(evidence, "alerts") {
+		t.Errorf("Unexpected evidence: %s", evidence)
+	}
+}
@@ -0,0 +1,234 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// maxOutputSchemaDepth bounds how deeply result types are described in an
+// output schema; deeper values are described only by their JSON type, which
+// keeps tools/list small for types such as DetailedResource
+const maxOutputSchemaDepth = 3
+
+// structuredResultKey holds results that are not JSON objects, such as lists,
+// and results reshaped by a transform expression in structured content
+const structuredResultKey = "result"
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// outputSchema returns the output schema of a tool whose actions return the
+// given results. Every result also may be a result handle summary or be held
+// under "result", as structuredContent does for lists and transformed results.
+func outputSchema(results ...interface{}) map[string]any {
+	variants := []any{
+		map[string]any{
+			"type":       "object",
+			"properties": map[string]any{structuredResultKey: map[string]any{}},
+			"required":   []string{structuredResultKey},
+		},
+		schemaForType(reflect.TypeOf(types.ResultHandleSummary{}), 0),
+	}
+	for _, result := range results {
+		t := reflect.TypeOf(result)
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Struct && t != timeType {
+			variants = append(variants, schemaForType(t, 0))
+		}
+	}
+	return map[string]any{"type": "object", "anyOf": variants}
+}
+
+// schemaForType describes how encoding/json renders a value of type t
+func schemaForType(t reflect.Type, depth int) map[string]any {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	var schema map[string]any
+	switch {
+	case t == timeType:
+		schema = map[string]any{"type": "string"}
+	case t == rawMessageType || t.Kind() == reflect.Interface:
+		return map[string]any{}
+	case t.Kind() == reflect.Struct:
+		schema = map[string]any{"type": "object"}
+		if depth < maxOutputSchemaDepth {
+			schema["properties"] = structProperties(t, depth)
+		}
+	case t.Kind() == reflect.Map:
+		schema = map[string]any{"type": "object"}
+		nullable = true
+		if depth < maxOutputSchemaDepth {
+			schema["additionalProperties"] = schemaForType(t.Elem(), depth+1)
+		}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		schema = map[string]any{"type": "string"}
+		nullable = true
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		schema = map[string]any{"type": "array"}
+		nullable = nullable || t.Kind() == reflect.Slice
+		if depth < maxOutputSchemaDepth {
+			schema["items"] = schemaForType(t.Elem(), depth+1)
+		}
+	case t.Kind() == reflect.String:
+		schema = map[string]any{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		schema = map[string]any{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		schema = map[string]any{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		schema = map[string]any{"type": "number"}
+	default:
+		return map[string]any{}
+	}
+
+	if nullable {
+		schema["type"] = []string{schema["type"].(string), "null"}
+	}
+	return schema
+}
+
+// structProperties describes the JSON fields of a struct, including the
+// fields of embedded structs
+func structProperties(t reflect.Type, depth int) map[string]any {
+	properties := map[string]any{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for key, value := range structProperties(field.Type, depth) {
+				properties[key] = value
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(options, "string") {
+			properties[name] = map[string]any{"type": "string"}
+			continue
+		}
+		properties[name] = schemaForType(field.Type, depth+1)
+	}
+	return properties
+}
+
+// structuredContent returns the structured content for a rendered JSON
+// result: the result itself when it is a JSON object, and otherwise, or when
+// wrap is set, an object holding it under "result"
+func structuredContent(text string, wrap bool) any {
+	var value any
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return nil
+	}
+	if object, ok := value.(map[string]any); ok && !wrap {
+		return object
+	}
+	return map[string]any{structuredResultKey: value}
+}
+
+// validateSchema checks a decoded JSON value against the subset of JSON
+// Schema that outputSchema emits
+func validateSchema(schema map[string]any, value any, path string) error {
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		var errs []string
+		for _, variant := range anyOf {
+			err := validateSchema(variant.(map[string]any), value, path)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return fmt.Errorf("%s matches no variant: %s", path, strings.Join(errs, "; "))
+	}
+
+	if typ, ok := schema["type"]; ok {
+		allowed := []string{}
+		switch typ := typ.(type) {
+		case string:
+			allowed = append(allowed, typ)
+		case []string:
+			allowed = typ
+		}
+		matched := false
+		for _, name := range allowed {
+			matched = matched || jsonTypeMatches(name, value)
+		}
+		if !matched {
+			return fmt.Errorf("%s: %T is not %v", path, value, typ)
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for _, key := range schemaStrings(schema["required"]) {
+			if _, ok := value[key]; !ok {
+				return fmt.Errorf("%s: missing %s", path, key)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		additional, _ := schema["additionalProperties"].(map[string]any)
+		for key, field := range value {
+			if property, ok := properties[key].(map[string]any); ok {
+				if err := validateSchema(property, field, path+"."+key); err != nil {
+					return err
+				}
+			} else if additional != nil {
+				if err := validateSchema(additional, field, path+"."+key); err != nil {
+					return err
+				}
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range value {
+				if err := validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// jsonTypeMatches reports whether a decoded JSON value has the named JSON
+// Schema type
+func jsonTypeMatches(name string, value any) bool {
+	switch value := value.(type) {
+	case nil:
+		return name == "null"
+	case map[string]any:
+		return name == "object"
+	case []any:
+		return name == "array"
+	case string:
+		return name == "string"
+	case bool:
+		return name == "boolean"
+	case float64:
+		return name == "number" || (name == "integer" && value == float64(int64(value)))
+	}
+	return false
+}
+
+// schemaStrings returns a schema keyword holding a list of strings
+func schemaStrings(value any) []string {
+	strs, _ := value.([]string)
+	return strs
+}
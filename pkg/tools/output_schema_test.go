@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// decodeSchema round-trips a schema through JSON, as a client receives it
+func decodeSchema(t *testing.T, schema map[string]any) map[string]any {
+	t.Helper()
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Failed to marshal schema: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode schema: %v", err)
+	}
+	return normalizeSchema(decoded).(map[string]any)
+}
+
+// normalizeSchema turns decoded string lists back into []string
+func normalizeSchema(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, field := range value {
+			if list, ok := field.([]any); ok && (key == "type" || key == "required") {
+				strs := make([]string, len(list))
+				for i, item := range list {
+					strs[i] = item.(string)
+				}
+				value[key] = strs
+				continue
+			}
+			value[key] = normalizeSchema(field)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = normalizeSchema(item)
+		}
+	}
+	return value
+}
+
+func TestSchemaForType(t *testing.T) {
+	schema := schemaForType(reflect.TypeOf(types.ResourceMetricsResponse{}), 0)
+	properties := schema["properties"].(map[string]any)
+	metrics := properties["metrics"].(map[string]any)
+	if fmt.Sprint(metrics["type"]) != "[array null]" {
+		t.Errorf("Expected metrics to be a nullable array, got %v", metrics["type"])
+	}
+	point := metrics["items"].(map[string]any)["properties"].(map[string]any)
+	if point["value"].(map[string]any)["type"] != "number" || point["instance"].(map[string]any)["type"] != "string" {
+		t.Errorf("Unexpected data point schema %v", point)
+	}
+}
+
+// TestOutputSchemasDescribeExamples checks the curated example responses of
+// each tool against its declared output schema
+func TestOutputSchemasDescribeExamples(t *testing.T) {
+	tools := map[string]mcp.Tool{}
+	tools["resources"], _ = createResourcesTool(nil)
+	tools["alerts"], _ = createAlertsTool(nil, "")
+	tools["usage"], _ = createUsageTool(nil)
+	tools["metrics"], _ = createMetricsTool(nil)
+
+	all, err := loadExamples()
+	if err != nil {
+		t.Fatalf("Failed to load examples: %v", err)
+	}
+	for name, actions := range all {
+		tool, ok := tools[name]
+		if !ok {
+			t.Errorf("%s: no tool registered in this test", name)
+			continue
+		}
+		if tool.OutputSchema == nil {
+			t.Fatalf("%s: no output schema", name)
+		}
+		schema := decodeSchema(t, tool.OutputSchema)
+		for action, example := range actions {
+			text, _ := renderResult(example.Response, false)
+			if err := validateSchema(schema, structuredContent(text, false), name+"/"+action); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+}
+
+func TestRespondJSONStructuredContent(t *testing.T) {
+	tool, _ := createResourcesTool(nil)
+	schema := decodeSchema(t, tool.OutputSchema)
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		result    interface{}
+		wantField string
+	}{
+		{"object", map[string]interface{}{}, &types.Resource{ID: "r1", HostName: "web-01"}, "hostName"},
+		{"list", map[string]interface{}{}, []types.ResourceTypeInfo{{Name: "server"}}, "result"},
+		{"compact", map[string]interface{}{"llmCompact": true}, &types.ResourceSearchResponse{Results: []types.Resource{{ID: "r1"}}}, "results"},
+		{"transformed", map[string]interface{}{"transform": ".hostName"}, &types.Resource{ID: "r1", HostName: "web-01"}, "result"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := respondJSON(context.Background(), createTestRequest(tt.args), tt.result)
+			if err != nil || result.IsError {
+				t.Fatalf("Expected a result, got %v", err)
+			}
+			structured, ok := result.StructuredContent.(map[string]any)
+			if !ok {
+				t.Fatalf("Expected structured content, got %T", result.StructuredContent)
+			}
+			if _, ok := structured[tt.wantField]; !ok {
+				t.Errorf("Expected structured content with %s, got %v", tt.wantField, structured)
+			}
+			if err := validateSchema(schema, structured, "result"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// defaultSchemaSample and maxSchemaSample bound the resources sampled to
+	// infer a property schema
+	defaultSchemaSample = 200
+	maxSchemaSample     = 1000
+	// schemaPageSize is the page size of the sampling search
+	schemaPageSize = 100
+	// maxSchemaDepth is how deep nested property objects are described
+	maxSchemaDepth = 3
+	// maxSchemaExamples bounds the distinct example values kept per property
+	maxSchemaExamples = 3
+	// maxSchemaExampleLength truncates long example values
+	maxSchemaExampleLength = 60
+	// reliableFillRate is the fill rate from which a property is reliable
+	reliableFillRate = 0.95
+)
+
+// propertyStats accumulates what was seen of one property key
+type propertyStats struct {
+	types    map[string]bool
+	filled   int
+	examples []string
+}
+
+// inferPropertySchema samples up to sampleSize resources of a type and
+// describes the keys of their properties
+func inferPropertySchema(ctx context.Context, api ResourcesAPI, resourceType string, sampleSize int) (*types.ResourcePropertySchema, error) {
+	if resourceType == "" {
+		return nil, errors.New("resourceType is required for inferPropertySchema")
+	}
+	if sampleSize <= 0 {
+		sampleSize = defaultSchemaSample
+	}
+	sampleSize = min(sampleSize, maxSchemaSample)
+
+	schema := &types.ResourcePropertySchema{ResourceType: resourceType, Properties: []types.ResourcePropertyInfo{}}
+	stats := map[string]*propertyStats{}
+	it := SearchResourcesIter(ctx, api, types.ResourceSearchParams{Type: resourceType, PageSize: min(sampleSize, schemaPageSize)})
+	for it.Next() {
+		resource := it.Value()
+		// The search may match loosely, so keep only the exact type
+		if !strings.EqualFold(resource.Type, resourceType) && !strings.EqualFold(resource.ResourceType, resourceType) {
+			continue
+		}
+		if schema.Sampled == sampleSize {
+			schema.Truncated = true
+			break
+		}
+		schema.Sampled++
+		collectProperties(stats, "", resource.Properties, 1)
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("failed to sample %s resources: %w", resourceType, err)
+	}
+
+	for key, stat := range stats {
+		info := types.ResourcePropertyInfo{Key: key, Filled: stat.filled, Examples: stat.examples}
+		for kind := range stat.types {
+			info.Types = append(info.Types, kind)
+		}
+		sort.Strings(info.Types)
+		if schema.Sampled > 0 {
+			info.FillRate = math.Round(float64(stat.filled)/float64(schema.Sampled)*100) / 100
+		}
+		info.Reliable = float64(stat.filled) >= reliableFillRate*float64(schema.Sampled)
+		schema.Properties = append(schema.Properties, info)
+	}
+	sort.Slice(schema.Properties, func(i, j int) bool {
+		a, b := schema.Properties[i], schema.Properties[j]
+		if a.Filled != b.Filled {
+			return a.Filled > b.Filled
+		}
+		return a.Key < b.Key
+	})
+	schema.Documentation = propertySchemaMarkdown(schema)
+	return schema, nil
+}
+
+// collectProperties records the keys of a properties object, descending
+// into nested objects up to maxSchemaDepth
+func collectProperties(stats map[string]*propertyStats, prefix string, properties map[string]interface{}, depth int) {
+	for key, value := range properties {
+		path := prefix + key
+		stat, ok := stats[path]
+		if !ok {
+			stat = &propertyStats{types: map[string]bool{}}
+			stats[path] = stat
+		}
+		stat.types[jsonTypeName(value)] = true
+		if isEmptyValue(value) {
+			continue
+		}
+		stat.filled++
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			if depth < maxSchemaDepth {
+				collectProperties(stats, path+".", nested, depth+1)
+			}
+			continue
+		}
+		if _, ok := value.([]interface{}); ok || isCredentialField(key) || len(stat.examples) == maxSchemaExamples {
+			continue
+		}
+		example := fmt.Sprint(value)
+		if runes := []rune(example); len(runes) > maxSchemaExampleLength {
+			example = string(runes[:maxSchemaExampleLength]) + "..."
+		}
+		if !slices.Contains(stat.examples, example) {
+			stat.examples = append(stat.examples, example)
+		}
+	}
+}
+
+// jsonTypeName returns the JSON type of a decoded value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, float32, int, int64, int32:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// isEmptyValue reports whether a property carries no information
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// propertySchemaMarkdown renders a property schema as a Markdown table
+func propertySchemaMarkdown(schema *types.ResourcePropertySchema) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### Properties of %s resources\n\n", schema.ResourceType)
+	if len(schema.Properties) == 0 {
+		fmt.Fprintf(&sb, "None of the %d sampled resources have properties.\n", schema.Sampled)
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "Inferred from %d sampled resources. Reliable properties are filled on at least %.0f%% of them; others may be missing.\n\n",
+		schema.Sampled, reliableFillRate*100)
+	sb.WriteString("| Property | Type | Fill rate | Reliable | Examples |\n|---|---|---|---|---|\n")
+	for _, property := range schema.Properties {
+		reliable := "no"
+		if property.Reliable {
+			reliable = "yes"
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %.0f%% | %s | %s |\n", markdownCell(property.Key), strings.Join(property.Types, ", "),
+			property.FillRate*100, reliable, markdownCell(strings.Join(property.Examples, ", ")))
+	}
+	if schema.Truncated {
+		sb.WriteString("\n_Note: more resources of this type exist than were sampled._\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// schemaResources returns a fake serving servers with varying properties and
+// one network device the search matched loosely
+func schemaResources() *FakeResourcesAPI {
+	api := &FakeResourcesAPI{}
+	api.SearchStub = func(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+		page := &types.ResourceSearchResponse{}
+		for i := 0; i < 20; i++ {
+			properties := map[string]interface{}{
+				"os":       "linux",
+				"cpuCount": float64(4),
+				"password": "hunter2",
+				"cloud":    map[string]interface{}{"region": "us-east-1"},
+			}
+			if i%2 == 0 {
+				properties["rack"] = fmt.Sprintf("R%d", i)
+			}
+			if i%4 == 0 {
+				properties["os"] = nil
+			}
+			page.Results = append(page.Results, types.Resource{ID: fmt.Sprintf("r%d", i), Type: "SERVER", Properties: properties})
+		}
+		page.Results = append(page.Results, types.Resource{ID: "n1", Type: "NETWORK", Properties: map[string]interface{}{"ports": float64(48)}})
+		return page, nil
+	}
+	return api
+}
+
+func TestInferPropertySchema(t *testing.T) {
+	schema, err := inferPropertySchema(context.Background(), schemaResources(), "server", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Sampled != 20 || schema.Truncated {
+		t.Fatalf("Expected the 20 servers sampled, got %d (truncated %t)", schema.Sampled, schema.Truncated)
+	}
+
+	properties := map[string]types.ResourcePropertyInfo{}
+	for _, property := range schema.Properties {
+		properties[property.Key] = property
+	}
+	if _, ok := properties["ports"]; ok {
+		t.Error("Expected properties of other types to be left out")
+	}
+	if cpu := properties["cpuCount"]; !cpu.Reliable || cpu.FillRate != 1 || cpu.Types[0] != "number" {
+		t.Errorf("Expected cpuCount to be a reliable number, got %+v", cpu)
+	}
+	if region := properties["cloud.region"]; !region.Reliable || region.Examples[0] != "us-east-1" {
+		t.Errorf("Expected the nested cloud.region key, got %+v", region)
+	}
+	if rack := properties["rack"]; rack.Reliable || rack.FillRate != 0.5 || len(rack.Examples) != maxSchemaExamples {
+		t.Errorf("Expected rack half filled with %d examples, got %+v", maxSchemaExamples, rack)
+	}
+	if os := properties["os"]; os.FillRate != 0.75 || strings.Join(os.Types, ",") != "null,string" {
+		t.Errorf("Expected os filled on 75%% as string or null, got %+v", os)
+	}
+	if password := properties["password"]; len(password.Examples) != 0 {
+		t.Errorf("Expected no examples of credentials, got %v", password.Examples)
+	}
+	if schema.Properties[len(schema.Properties)-1].Key != "rack" {
+		t.Errorf("Expected properties ordered by fill rate, got %+v", schema.Properties)
+	}
+	if !strings.Contains(schema.Documentation, "| cloud.region | string | 100% | yes | us-east-1 |") {
+		t.Errorf("Expected the property documented, got:\n%s", schema.Documentation)
+	}
+}
+
+func TestInferPropertySchemaSampleSize(t *testing.T) {
+	api := pagedResources(50, 10)
+	base := api.SearchStub
+	api.SearchStub = func(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+		page, err := base(ctx, params)
+		for i := range page.Results {
+			page.Results[i].Type = "SERVER"
+		}
+		return page, err
+	}
+
+	schema, err := inferPropertySchema(context.Background(), api, "SERVER", 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Sampled != 15 || !schema.Truncated || api.SearchCallCount() != 2 {
+		t.Errorf("Expected 15 resources sampled from 2 pages, got %d from %d (truncated %t)", schema.Sampled, api.SearchCallCount(), schema.Truncated)
+	}
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// resourceDetailSections are the related sections getDetailed can include
+var resourceDetailSections = []string{"tags", "relationships", "serviceGroups", "patches", "maintenanceWindows"}
+
+// validateDetailSections checks the sections named in getDetailed's include
+func validateDetailSections(include []string) error {
+	for _, section := range include {
+		if !slices.Contains(resourceDetailSections, section) {
+			return fmt.Errorf("unknown include section %q: must be one of %s", section, strings.Join(resourceDetailSections, ", "))
+		}
+	}
+	return nil
+}
+
+// getResourceDetails fetches a detailed resource and the included sections
+// concurrently. A section that cannot be retrieved is reported in Errors
+// rather than failing the call; only a failure to get the resource itself is
+// an error.
+func getResourceDetails(ctx context.Context, api ResourcesAPI, id string, include []string) (*types.ResourceDetails, error) {
+	details := &types.ResourceDetails{}
+	var detailErr error
+	sections := map[string]sectionFetch{
+		"resource": func(ctx context.Context) error {
+			details.Resource, detailErr = api.GetDetailed(ctx, id)
+			return detailErr
+		},
+	}
+	for _, section := range include {
+		sections[section] = resourceDetailSection(api, id, details, section)
+	}
+
+	failed := fetchSections(ctx, toolLogger("resources"), "Details of resource "+id, sections)
+	if detailErr != nil {
+		return nil, detailErr
+	}
+	details.Errors = failed
+	return details, nil
+}
+
+// resourceDetailSection returns the fetch of one included section
+func resourceDetailSection(api ResourcesAPI, id string, details *types.ResourceDetails, section string) sectionFetch {
+	switch section {
+	case "tags":
+		return func(ctx context.Context) (err error) {
+			details.Tags, err = api.GetTags(ctx, id)
+			return err
+		}
+	case "relationships":
+		return func(ctx context.Context) (err error) {
+			details.Relationships, err = api.GetRelationships(ctx, id)
+			return err
+		}
+	case "serviceGroups":
+		return func(ctx context.Context) (err error) {
+			details.ServiceGroups, err = api.GetServiceGroups(ctx, id)
+			return err
+		}
+	case "patches":
+		return func(ctx context.Context) (err error) {
+			provider, ok := api.(PatchProvider)
+			if !ok {
+				return fmt.Errorf("patch status is not supported by this resources backend")
+			}
+			details.Patches, err = provider.GetPatches(ctx, id)
+			return err
+		}
+	default:
+		return func(ctx context.Context) (err error) {
+			provider, ok := api.(MaintenanceProvider)
+			if !ok {
+				return fmt.Errorf("maintenance windows are not supported by this resources backend")
+			}
+			details.MaintenanceWindows, err = provider.GetMaintenanceWindows(ctx, id)
+			return err
+		}
+	}
+}
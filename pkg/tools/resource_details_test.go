@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestResourcesToolGetDetailedIncludesSections(t *testing.T) {
+	api := &FakeResourcesAPI{}
+	resource := &types.DetailedResource{}
+	resource.ID, resource.Name = "r-1", "web-01"
+	api.GetDetailedReturns(resource, nil)
+	api.GetTagsReturns([]types.Tag{{Name: "env", Value: "prod"}}, nil)
+	api.GetRelationshipsReturns(nil, fmt.Errorf("topology service down"))
+	api.GetServiceGroupsReturns([]types.ServiceGroup{{ID: "sg-1", Name: "Storefront"}}, nil)
+
+	result, err := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action":  "getDetailed",
+		"id":      "r-1",
+		"include": []interface{}{"tags", "relationships", "serviceGroups", "patches"},
+	}), api)
+	if err != nil || result.IsError {
+		t.Fatalf("Expected the details despite failed sections, got %v %s", err, resultText(result))
+	}
+
+	var details types.ResourceDetails
+	if err := json.Unmarshal([]byte(resultText(result)), &details); err != nil {
+		t.Fatalf("Invalid details: %v", err)
+	}
+	if details.Resource == nil || details.Resource.Name != "web-01" || len(details.Tags) != 1 || len(details.ServiceGroups) != 1 {
+		t.Errorf("Expected the resource, tags and service groups, got %+v", details)
+	}
+	if len(details.Errors) != 2 || details.Errors["relationships"] != "topology service down" || !strings.Contains(details.Errors["patches"], "not supported") {
+		t.Errorf("Expected relationships and patches to be reported in errors, got %v", details.Errors)
+	}
+}
+
+func TestResourcesToolGetDetailedFailsWithoutResource(t *testing.T) {
+	api := &FakeResourcesAPI{}
+	api.GetDetailedReturns(nil, fmt.Errorf("resource not found"))
+	api.GetTagsReturns([]types.Tag{{Name: "env", Value: "prod"}}, nil)
+
+	result, _ := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "getDetailed", "id": "r-1", "include": []interface{}{"tags"},
+	}), api)
+	if !result.IsError {
+		t.Errorf("Expected a missing resource to fail the call, got %s", resultText(result))
+	}
+
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "getDetailed", "id": "r-1", "include": []interface{}{"history"},
+	}), api)
+	if !result.IsError || !strings.Contains(resultText(result), `unknown include section "history"`) {
+		t.Errorf("Expected an unknown section to be refused, got %s", resultText(result))
+	}
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/pkg/locks"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// validateTags rejects tags without a name and tag names given twice; a
+// resource carries one value per tag name
+func validateTags(tags []types.Tag) error {
+	seen := make(map[string]bool, len(tags))
+	for i, tag := range tags {
+		name := strings.ToLower(strings.TrimSpace(tag.Name))
+		if name == "" {
+			return fmt.Errorf("tags[%d] has no name", i)
+		}
+		if seen[name] {
+			return fmt.Errorf("tag %q is given more than once", tag.Name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// replaceResourceTags sets the complete tag list of a resource
+func replaceResourceTags(ctx context.Context, api ResourcesAPI, id string, tags []types.Tag) (*types.ResourceTags, error) {
+	if err := validateTags(tags); err != nil {
+		return nil, err
+	}
+	if err := api.UpdateTags(ctx, id, tags); err != nil {
+		return nil, err
+	}
+	return &types.ResourceTags{ResourceID: id, Tags: tags, Changed: true}, nil
+}
+
+// addResourceTag sets one tag of a resource, replacing the value of a tag
+// with the same name and keeping the others
+func addResourceTag(ctx context.Context, api ResourcesAPI, tenant, id string, tag types.Tag) (*types.ResourceTags, error) {
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" {
+		return nil, errors.New("tagName is required for addTag action")
+	}
+	return editResourceTags(ctx, api, tenant, id, "addTag", func(tags []types.Tag) ([]types.Tag, bool) {
+		for i, existing := range tags {
+			if strings.EqualFold(existing.Name, tag.Name) {
+				if existing.Value == tag.Value {
+					return tags, false
+				}
+				tags[i].Value = tag.Value
+				return tags, true
+			}
+		}
+		return append(tags, tag), true
+	})
+}
+
+// removeResourceTag removes the tag of a resource with the given name, and
+// only while it holds value when one is given
+func removeResourceTag(ctx context.Context, api ResourcesAPI, tenant, id, name, value string) (*types.ResourceTags, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("tagName is required for removeTag action")
+	}
+	return editResourceTags(ctx, api, tenant, id, "removeTag", func(tags []types.Tag) ([]types.Tag, bool) {
+		kept := make([]types.Tag, 0, len(tags))
+		for _, existing := range tags {
+			if strings.EqualFold(existing.Name, name) && (value == "" || existing.Value == value) {
+				continue
+			}
+			kept = append(kept, existing)
+		}
+		return kept, len(kept) != len(tags)
+	})
+}
+
+// editResourceTags reads the tags of a resource, applies edit and writes
+// them back when they changed. OpsRamp only replaces the whole tag list, so
+// edits of one resource through this server are serialized to keep two
+// concurrent addTag calls from dropping each other's tag.
+func editResourceTags(ctx context.Context, api ResourcesAPI, tenant, id, action string, edit func([]types.Tag) ([]types.Tag, bool)) (*types.ResourceTags, error) {
+	holder := session.IDFromContext(ctx)
+	if holder == "" {
+		holder = "anonymous"
+	}
+	release, err := locks.Default().Acquire(ctx, fmt.Sprintf("tags:resources:%s:%s", tenant, id), "session "+holder, "resources "+action)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	tags, err := api.GetTags(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tags, changed := edit(append([]types.Tag{}, tags...))
+	if changed {
+		if err := api.UpdateTags(ctx, id, tags); err != nil {
+			return nil, err
+		}
+	}
+	return &types.ResourceTags{ResourceID: id, Tags: tags, Changed: changed}, nil
+}
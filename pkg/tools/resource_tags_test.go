@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestResourcesToolTags(t *testing.T) {
+	api := &FakeResourcesAPI{}
+	stored := []types.Tag{{Name: "env", Value: "dev"}, {Name: "owner", Value: "team-a"}}
+	api.GetTagsStub = func(ctx context.Context, id string) ([]types.Tag, error) {
+		return append([]types.Tag{}, stored...), nil
+	}
+	api.UpdateTagsStub = func(ctx context.Context, id string, tags []types.Tag) error {
+		stored = tags
+		return nil
+	}
+	call := func(args map[string]interface{}) (types.ResourceTags, string) {
+		t.Helper()
+		args["id"] = "r-1"
+		result, _ := ResourcesToolHandler(context.Background(), createTestRequest(args), api)
+		var tags types.ResourceTags
+		if result.IsError {
+			return tags, resultText(result)
+		}
+		if err := json.Unmarshal([]byte(resultText(result)), &tags); err != nil {
+			t.Fatalf("Invalid tags result: %v", err)
+		}
+		return tags, ""
+	}
+
+	tags, errText := call(map[string]interface{}{"action": "addTag", "tagName": "ENV", "tagValue": "prod"})
+	if errText != "" || !tags.Changed || len(tags.Tags) != 2 || stored[0] != (types.Tag{Name: "env", Value: "prod"}) {
+		t.Fatalf("Expected addTag to replace the env value, got %+v %s", stored, errText)
+	}
+	if tags, _ = call(map[string]interface{}{"action": "addTag", "tagName": "env", "tagValue": "prod"}); tags.Changed || api.UpdateTagsCallCount() != 1 {
+		t.Errorf("Expected setting an unchanged tag not to write, got %+v", tags)
+	}
+	call(map[string]interface{}{"action": "addTag", "tagName": "tier", "tagValue": "web"})
+	if len(stored) != 3 || stored[2].Name != "tier" {
+		t.Errorf("Expected a new tag to be appended, got %+v", stored)
+	}
+
+	if tags, _ = call(map[string]interface{}{"action": "removeTag", "tagName": "owner", "tagValue": "team-b"}); tags.Changed {
+		t.Errorf("Expected a tag holding another value to be kept, got %+v", tags)
+	}
+	if tags, _ = call(map[string]interface{}{"action": "removeTag", "tagName": "owner"}); !tags.Changed || len(stored) != 2 {
+		t.Errorf("Expected the owner tag to be removed, got %+v", stored)
+	}
+
+	call(map[string]interface{}{"action": "updateTags", "tags": []interface{}{}})
+	if tags, _ = call(map[string]interface{}{"action": "getTags"}); tags.Tags == nil || len(tags.Tags) != 0 {
+		t.Errorf("Expected updateTags with an empty list to clear the tags, got %+v", tags)
+	}
+
+	for want, args := range map[string]map[string]interface{}{
+		"tagName is required":      {"action": "addTag", "tagValue": "x"},
+		"tags is required":         {"action": "updateTags"},
+		"tags[1] has no name":      {"action": "updateTags", "tags": []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"value": "b"}}},
+		`tag "A" is given more th`: {"action": "updateTags", "tags": []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "A"}}},
+	} {
+		if _, errText := call(args); !strings.Contains(errText, want) {
+			t.Errorf("Expected %q, got %q", want, errText)
+		}
+	}
+}
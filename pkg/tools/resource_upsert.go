@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/pkg/locks"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// upsertPageSize is the page size of the lookup searches; more than one
+// match is ambiguous anyway
+const upsertPageSize = 5
+
+// upsertKey is a field createOrUpdate matches existing resources on
+type upsertKey struct {
+	name string
+	// request and resource return the key of a create request and of a resource
+	request  func(request types.ResourceCreateRequest) string
+	resource func(resource types.Resource) string
+	search   func(params *types.ResourceSearchParams, value string)
+}
+
+// upsertKeys are tried in order: an external ID names one resource in the
+// source system, while serial numbers are sometimes reused across vendors
+var upsertKeys = []upsertKey{
+	{
+		name:     "extResourceId",
+		request:  func(r types.ResourceCreateRequest) string { return r.ExtResourceID },
+		resource: func(r types.Resource) string { return r.ExtResourceID },
+		search:   func(p *types.ResourceSearchParams, v string) { p.QueryString = "extResourceId:" + v },
+	},
+	{
+		name:     "serialNumber",
+		request:  func(r types.ResourceCreateRequest) string { return r.SerialNumber },
+		resource: func(r types.Resource) string { return r.SerialNumber },
+		search:   func(p *types.ResourceSearchParams, v string) { p.SerialNumber = v },
+	},
+}
+
+// upsertResource updates the resource matching the request's external ID or
+// serial number, or creates one when none matches. Concurrent upserts of the
+// same key through this server are serialized, so a sync retried while the
+// first attempt is running does not create a duplicate.
+func upsertResource(ctx context.Context, api ResourcesAPI, tenant string, request types.ResourceCreateRequest) (*types.ResourceUpsertResult, error) {
+	var keys []upsertKey
+	for _, key := range upsertKeys {
+		if strings.TrimSpace(key.request(request)) != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("createOrUpdate needs config.extResourceId or config.serialNumber to find an existing resource; use create otherwise")
+	}
+
+	holder := session.IDFromContext(ctx)
+	if holder == "" {
+		holder = "anonymous"
+	}
+	lockName := fmt.Sprintf("upsert:resources:%s:%s=%s", tenant, keys[0].name, strings.TrimSpace(keys[0].request(request)))
+	release, err := locks.Default().Acquire(ctx, lockName, "session "+holder, "resources createOrUpdate")
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	for _, key := range keys {
+		existing, err := findUpsertMatch(ctx, api, key, strings.TrimSpace(key.request(request)))
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			continue
+		}
+		updated, err := api.Update(ctx, existing.ID, updateRequestFromCreate(request))
+		if err != nil {
+			return nil, fmt.Errorf("failed to update resource %s matched by %s: %w", existing.ID, key.name, err)
+		}
+		return &types.ResourceUpsertResult{Operation: types.UpsertUpdated, MatchedBy: key.name, Resource: updated}, nil
+	}
+
+	created, err := api.Create(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ResourceUpsertResult{Operation: types.UpsertCreated, Resource: created}, nil
+}
+
+// findUpsertMatch returns the resource whose key equals value, or nil if
+// there is none. Searches may match loosely, so only exact matches count;
+// more than one is an error rather than a guess.
+func findUpsertMatch(ctx context.Context, api ResourcesAPI, key upsertKey, value string) (*types.Resource, error) {
+	params := types.ResourceSearchParams{PageSize: upsertPageSize}
+	key.search(&params, value)
+	response, err := api.Search(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up resources by %s: %w", key.name, err)
+	}
+
+	var matches []types.Resource
+	for _, resource := range response.Results {
+		if strings.EqualFold(key.resource(resource), value) {
+			matches = append(matches, resource)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+	return nil, fmt.Errorf("%d resources have %s %q (%s); update the right one by ID instead",
+		len(matches), key.name, value, strings.Join(ids, ", "))
+}
+
+// updateRequestFromCreate carries every field of a create request over to
+// an update of an existing resource
+func updateRequestFromCreate(request types.ResourceCreateRequest) types.ResourceUpdateRequest {
+	return types.ResourceUpdateRequest{
+		AliasName:                request.AliasName,
+		AlternateIP:              request.AlternateIP,
+		ExtResourceID:            request.ExtResourceID,
+		ManagementProfile:        request.ManagementProfile,
+		ResourceNetworkInterface: request.ResourceNetworkInterface,
+		LogicalDiskDrives:        request.LogicalDiskDrives,
+		OOBInterfaceCards:        request.OOBInterfaceCards,
+		ResourceType:             request.ResourceType,
+		HostName:                 request.HostName,
+		IPAddress:                request.IPAddress,
+		DNSName:                  request.DNSName,
+		SerialNumber:             request.SerialNumber,
+		Make:                     request.Make,
+		Model:                    request.Model,
+		Description:              request.Description,
+		OS:                       request.OS,
+		Category:                 request.Category,
+		Location:                 request.Location,
+		Tags:                     request.Tags,
+		Properties:               request.Properties,
+	}
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestResourcesToolCreateOrUpdate(t *testing.T) {
+	api := &FakeResourcesAPI{}
+	existing := []types.Resource{
+		// A loose search match that must not be taken for the resource
+		{ID: "r-9", SerialNumber: "SN-100-B"},
+		{ID: "r-1", SerialNumber: "SN-100", ExtResourceID: "cmdb-1"},
+	}
+	api.SearchStub = func(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+		return &types.ResourceSearchResponse{Results: existing}, nil
+	}
+	api.UpdateStub = func(ctx context.Context, id string, request types.ResourceUpdateRequest) (*types.Resource, error) {
+		return &types.Resource{ID: id, HostName: request.HostName}, nil
+	}
+	api.CreateStub = func(ctx context.Context, request types.ResourceCreateRequest) (*types.Resource, error) {
+		return &types.Resource{ID: "r-new", HostName: request.HostName}, nil
+	}
+	upsert := func(config map[string]interface{}) (types.ResourceUpsertResult, string) {
+		t.Helper()
+		result, _ := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+			"action": "createOrUpdate", "config": config,
+		}), api)
+		var upserted types.ResourceUpsertResult
+		if !result.IsError {
+			if err := json.Unmarshal([]byte(resultText(result)), &upserted); err != nil {
+				t.Fatalf("Invalid upsert result: %v", err)
+			}
+			return upserted, ""
+		}
+		return upserted, resultText(result)
+	}
+
+	result, errText := upsert(map[string]interface{}{"resourceType": "SERVER", "hostName": "web-01", "serialNumber": "sn-100"})
+	if errText != "" || result.Operation != types.UpsertUpdated || result.MatchedBy != "serialNumber" || result.Resource.ID != "r-1" {
+		t.Fatalf("Expected the serial number to match r-1, got %+v %s", result, errText)
+	}
+	if _, _, request := api.UpdateArgsForCall(0); request.HostName != "web-01" || api.CreateCallCount() != 0 {
+		t.Errorf("Expected the existing resource to be updated, got %+v", request)
+	}
+
+	result, _ = upsert(map[string]interface{}{"resourceType": "SERVER", "extResourceId": "cmdb-1", "serialNumber": "SN-999"})
+	if result.MatchedBy != "extResourceId" || result.Resource.ID != "r-1" {
+		t.Errorf("Expected the external ID to be tried first, got %+v", result)
+	}
+
+	result, _ = upsert(map[string]interface{}{"resourceType": "SERVER", "hostName": "web-02", "serialNumber": "SN-200"})
+	if result.Operation != types.UpsertCreated || result.Resource.ID != "r-new" || api.CreateCallCount() != 1 {
+		t.Errorf("Expected a new resource when nothing matches, got %+v", result)
+	}
+
+	existing = append(existing, types.Resource{ID: "r-2", SerialNumber: "SN-100"})
+	if _, errText = upsert(map[string]interface{}{"resourceType": "SERVER", "serialNumber": "SN-100"}); !strings.Contains(errText, "2 resources have serialNumber") {
+		t.Errorf("Expected duplicate matches to be refused, got %q", errText)
+	}
+	if _, errText = upsert(map[string]interface{}{"resourceType": "SERVER", "hostName": "web-03"}); !strings.Contains(errText, "needs config.extResourceId or config.serialNumber") {
+		t.Errorf("Expected a request without keys to be refused, got %q", errText)
+	}
+}
@@ -2,13 +2,15 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/opsramp/or-mcp-v2/common"
 	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
 	"github.com/opsramp/or-mcp-v2/pkg/types"
 )
 
@@ -28,6 +30,12 @@ func NewResourcesTool(api ResourcesAPI) *ResourcesTool {
 	}
 }
 
+// backgroundResourceActions are the long-running resources actions, whose
+// OpsRamp requests have background priority
+var backgroundResourceActions = map[string]bool{
+	"bulkUpdate": true, "bulkDelete": true, "onboard": true, "refreshDynamicGroup": true,
+}
+
 // NewResourcesMcpTool returns the MCP tool definition and handler for resources
 func NewResourcesMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
 	// Get the logger
@@ -46,15 +54,41 @@ func NewResourcesMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
 		}
 	}
 
-	// Create and initialize the real API implementation
-	api := NewOpsRampResourcesAPI(client.NewOpsRampClient(config))
+	// Create and initialize the API implementation for the configured backend
+	backend := config.ToolBackend("resources").Backend
+	var api ResourcesAPI
+	if backend == common.BackendMock {
+		// There is no built-in sample data for resources
+		fixtures, err := mockFixtures(config)
+		if err == nil && fixtures == nil {
+			err = fmt.Errorf("the mock backend of the resources tool needs fixtures: set mock.fixtures to their directory")
+		}
+		if err != nil {
+			logger.Error("Failed to load fixtures for OpsRamp Resources API: %v", err)
+			tool, _ := createResourcesTool(nil)
+			return withBackend(tool, backend), backendErrorHandler(err)
+		}
+		api = NewFixtureResourcesAPI(fixtures)
+	} else {
+		opsRampClient, err := newBackendClient(config, "resources")
+		if err != nil {
+			logger.Error("Failed to initialize %s backend for OpsRamp Resources API: %v", backend, err)
+			tool, _ := createResourcesTool(nil)
+			return withBackend(tool, backend), backendErrorHandler(err)
+		}
+		api = NewOpsRampResourcesAPI(opsRampClient)
+	}
 
-	logger.Info("Successfully initialized OpsRamp Resources API")
-	return createResourcesTool(api)
+	logger.Info("Successfully initialized OpsRamp Resources API (%s backend)", backend)
+	tool, handler := createResourcesTool(api)
+	return withBackend(tool, backend), handler
 }
 
 // createResourcesTool creates the MCP tool with the given API implementation
 func createResourcesTool(api ResourcesAPI) (mcp.Tool, server.ToolHandlerFunc) {
+	if api != nil {
+		startGroupSnapshots(api)
+	}
 	return mcp.Tool{
 			Name:        "resources",
 			Description: "Manage HPE OpsRamp resources (devices, servers, network equipment, etc.)",
@@ -63,26 +97,164 @@ func createResourcesTool(api ResourcesAPI) (mcp.Tool, server.ToolHandlerFunc) {
 				Properties: map[string]interface{}{
 					"action": map[string]interface{}{
 						"type":        "string",
-						"description": "Action to perform: list, get, getDetailed, getMinimal, create, update, delete, search, getResourceTypes",
+						"description": "Action to perform: list, get, getDetailed, getMinimal, create, createOrUpdate, update, changeState, delete, bulkUpdate, bulkDelete, planDelete, search, getResourceTypes, getMetrics, listMetricInstances, getAvailability, impactAnalysis, scorecard, timelineReport, onboard, listTemplates, saveTemplate, createFromTemplate, listDynamicGroups, previewDynamicGroup, createDynamicGroup, updateDynamicGroup, refreshDynamicGroup, listDeviceGroups, getDeviceGroup, createDeviceGroup, updateDeviceGroup, deleteDeviceGroup, listSites, getSite, createSite, updateSite, deleteSite, listServiceGroups, getServiceGroup, createServiceGroup, updateServiceGroup, deleteServiceGroup, snapshotGroups, getGroupHistory, diffGroups, inferPropertySchema, getMetricCatalog, getTags, updateTags, addTag, removeTag, resolveId, watch, unwatch, listWatches, invalidateCache. list and search return one page of results unless allPages is set. Searches and lists are cached for the configured cache_ttl, and resources fetched by ID and resource types for shorter; writes through this server invalidate them, and invalidateCache drops the tenant's cached resource data so the next reads see changes made elsewhere, e.g. in the OpsRamp UI. resolveId translates another identifier of a resource (identity, systemUID, providerUID, deviceUniqueId, serialNumber, dnsName, hostName or ipAddress) into its resource ID and lists all its identifiers; the read actions taking a resource ID also accept these identifiers, while writes given one fail with the resource ID it resolves to, to be repeated with that ID. createOrUpdate updates the resource whose extResourceId or serialNumber matches config, trying extResourceId first, and creates one when none matches; the result says which it did. inferPropertySchema samples resources of a resourceType and reports which properties they carry, their types and how often they are filled. getMetricCatalog lists the metrics collected on resources of a resourceType, with their units, typical ranges and collection intervals; look metric names up there before calling getMetrics. updateTags replaces all tags of a resource; addTag sets one tag, replacing the value of a tag with the same name, and removeTag removes one, leaving the others in place. The device group, site and service group actions manage the groups themselves; their updates change only the fields given, and deleting a group keeps its resources. Device group and site definitions, with group members and dynamic group queries, are snapshotted daily; getGroupHistory lists the changes of one group or site (id) over the timeRange (default last30d), diffGroups compares all of them across the timeRange (default last7d), and snapshotGroups takes a snapshot now. watch subscribes this session to alerts raised on the resource; they arrive as notifications/message entries from the alerts logger with a link to the alert",
 					},
 					"id": map[string]interface{}{
 						"type":        "string",
-						"description": "Resource ID (for get, getDetailed, getMinimal, update, delete)",
+						"description": "Resource ID (for get, getDetailed, getMinimal, update, changeState, delete, getMetrics, listMetricInstances, getAvailability, impactAnalysis, scorecard, timelineReport, getTags, updateTags, addTag, removeTag, watch, unwatch) or the identifier to resolve (for resolveId); device group ID for updateDynamicGroup and refreshDynamicGroup, and for previewDynamicGroup of an existing group; device group or site ID for getGroupHistory; device group, site or service group ID for getDeviceGroup, updateDeviceGroup, deleteDeviceGroup, getSite, updateSite, deleteSite, getServiceGroup, updateServiceGroup and deleteServiceGroup",
+					},
+					"resourceType": map[string]interface{}{
+						"type":        "string",
+						"description": "Resource type to sample, e.g. SERVER or NETWORK (for inferPropertySchema and getMetricCatalog)",
+					},
+					"idKind": map[string]interface{}{
+						"type":        "string",
+						"description": "Kind of identifier id is: id, identity, systemUID, providerUID, deviceUniqueId, serialNumber, dnsName, hostName or ipAddress (for resolveId, defaults to trying each in turn)",
+					},
+					"sampleSize": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("Resources to sample (for inferPropertySchema, default %d, max %d; for getMetricCatalog, default %d, max %d), or members listed per bucket (for search with groupBy, default %d, max %d)", defaultSchemaSample, maxSchemaSample, defaultMetricCatalogSample, maxMetricCatalogSample, defaultGroupSample, maxGroupSample),
+					},
+					"groupBy": map[string]interface{}{
+						"type":        "string",
+						"enum":        searchGroupBys,
+						"description": fmt.Sprintf("Bucket the search results by deviceGroup, site or type (for search). Every page is read, up to %d resources, and each bucket is returned with its count and a sample of its members instead of the rows; a resource in several device groups is counted in each", maxGroupedResources),
+					},
+					"allPages": map[string]interface{}{
+						"type":        "boolean",
+						"description": fmt.Sprintf("Return the results of every page instead of only one page of params.pageSize (for list and search), up to %d resources; pagination.hasMore says whether more remained", maxAllPagesResources),
+					},
+					"refresh": map[string]interface{}{
+						"type":        "boolean",
+						"description": fmt.Sprintf("Sample again instead of reusing a catalog built in the last %s (for getMetricCatalog)", metricCatalogTTL),
+					},
+					"state": map[string]interface{}{
+						"type":        "string",
+						"description": fmt.Sprintf("State to move the resource to (for changeState), e.g. %s to bring a discovered resource under management or %s to stop managing it", managedState, unmanagedState),
+					},
+					"tags": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"name":  map[string]interface{}{"type": "string"},
+								"value": map[string]interface{}{"type": "string"},
+							},
+							"required": []string{"name"},
+						},
+						"description": "Complete tag list of the resource, one value per tag name (for updateTags); tags left out are removed",
+					},
+					"tagName": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the tag to set or remove (for addTag and removeTag)",
+					},
+					"tagValue": map[string]interface{}{
+						"type":        "string",
+						"description": "Value to set (for addTag); for removeTag, remove the tag only while it has this value",
+					},
+					"serviceGroup": map[string]interface{}{
+						"type":        "string",
+						"description": "Service group (for timelineReport instead of id): the timeline covers its member resources",
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Device group name (for createDynamicGroup and updateDynamicGroup), or name of the device group, site or service group (for createDeviceGroup, updateDeviceGroup, createSite, updateSite, createServiceGroup and updateServiceGroup)",
+					},
+					"description": map[string]interface{}{
+						"type":        "string",
+						"description": "Device group description (for createDynamicGroup and updateDynamicGroup), or description of the device group, site or service group (for createDeviceGroup, updateDeviceGroup, createSite, updateSite, createServiceGroup and updateServiceGroup)",
+					},
+					"include": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string", "enum": resourceDetailSections},
+						"description": "Related sections to fetch with the resource (for getDetailed): tags, relationships, serviceGroups, patches, maintenanceWindows. The resource is then returned under resource, each section beside it; a section that cannot be retrieved is reported in errors without failing the call",
+					},
+					"depth": map[string]interface{}{
+						"type":        "integer",
+						"description": "Topology hops to follow downstream (for impactAnalysis, default 3, max 6)",
 					},
 					"config": map[string]interface{}{
 						"type":        "object",
-						"description": "Resource configuration (for create and update)",
+						"description": "Resource configuration (for create, createOrUpdate, update and bulkUpdate). For onboard: {filter, deviceGroupId, siteId, templateIds, tags, limit, dryRun}. For createSite and updateSite: {address, city, state, country, zipCode, timeZone}. For saveTemplate: {description, variables: {name: {required, default, format (ip or hostname), pattern}}, resource} where resource string values may use {{name}} placeholders",
+					},
+					"template": map[string]interface{}{
+						"type":        "string",
+						"description": "Resource template name (for saveTemplate and createFromTemplate)",
+					},
+					"variables": map[string]interface{}{
+						"type":        "object",
+						"description": "Template variable values, e.g. {\"hostname\": \"web-01\", \"ip\": \"10.0.1.5\"} (for createFromTemplate)",
+					},
+					"ids": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Resource IDs (for bulkUpdate, bulkDelete and planDelete; or use selector)",
+					},
+					"selector": map[string]interface{}{
+						"type":        "object",
+						"description": "Select bulkUpdate/bulkDelete/planDelete targets by filter: {type, tag (\"env=dev\"), hostName (\"web-*\"), name, ipAddress (address or CIDR, e.g. 10.0.1.0/24), state, deviceGroup}. Without confirm, returns a preview of the resolved targets and count. Also the membership query of createDynamicGroup, updateDynamicGroup and previewDynamicGroup.",
+					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Apply a selector-based bulk action after reviewing its preview (requires expectedCount)",
+					},
+					"expectedCount": map[string]interface{}{
+						"type":        "integer",
+						"description": "Target count from the preview; the action is refused if the selector now resolves to a different count",
+					},
+					"confirmationToken": map[string]interface{}{
+						"type":        "string",
+						"description": fmt.Sprintf("Token from planDelete (for bulkDelete). Required to delete more than %d resources; valid only for the exact resources planned and expires after a few minutes", getDeleteConfirmation().threshold),
 					},
 					"params": map[string]interface{}{
 						"type":        "object",
 						"description": "Search parameters (for search)",
 					},
+					"metrics": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Metric names, e.g. system.cpu.utilization (for getMetrics)",
+					},
+					"interval": map[string]interface{}{
+						"type":        "string",
+						"description": "Aggregation interval, e.g. 5m or 1h (for getMetrics)",
+					},
+					"metric": map[string]interface{}{
+						"type":        "string",
+						"description": "Dimensioned metric name, e.g. system.disk.usage (for listMetricInstances)",
+					},
+					"instance": map[string]interface{}{
+						"type":        "string",
+						"description": "Metric instance from listMetricInstances, e.g. a disk, interface or process (for getMetrics)",
+					},
+					"timeRange":      timeRangeArgument,
+					"locale":         localeArgument,
+					"tenant":         tenantArgument,
+					"tenants":        tenantsArgument,
+					"llmCompact":     llmCompactArgument,
+					"transform":      transformArgument,
+					"render":         renderArgument,
+					"columns":        columnsArgument,
+					"maxRows":        maxRowsArgument,
+					"example":        exampleArgument,
+					"suggestActions": suggestActionsArgument,
 				},
 				Required: []string{"action"},
 			},
-		}, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return ResourcesToolHandler(ctx, req, api)
-		}
+			OutputSchema: outputSchema(
+				types.ResourceSearchResponse{}, types.ResourceGroups{}, types.Resource{}, types.ResourceUpsertResult{}, types.ResourceStateChange{}, types.ResourceTags{}, types.DetailedResource{}, types.ResourceDetails{}, types.ResourceMinimal{},
+				ResourceTemplateInfo{}, types.BulkSelectionResult{}, types.DeletePlan{}, types.OnboardingResult{}, types.ImpactAnalysis{},
+				types.ResourceScorecard{}, types.TimelineReport{}, types.ResourceMetricsResponse{}, types.ResourceMetricInstances{},
+				types.ResourceAvailability{}, types.DeviceGroup{}, types.Site{}, types.ServiceGroup{}, types.DynamicGroup{}, types.DynamicGroupChange{}, types.ResourcePropertySchema{}, types.MetricCatalog{}, types.ResourceIDResolution{},
+				types.GroupSnapshot{}, types.GroupHistory{}, types.GroupDiff{}, types.TenantFanOutResult{}, types.CacheInvalidation{},
+			),
+		}, withTenantFanOut("resources", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			tenantAPI, err := resourcesAPIForRequest(req, api)
+			if err != nil {
+				return tenantErrorResult(err), nil
+			}
+			return ResourcesToolHandler(ctx, req, tenantAPI)
+		})
 }
 
 // ResourcesToolHandler routes requests to the correct method
@@ -112,9 +284,14 @@ func ResourcesToolHandler(ctx context.Context, req mcp.CallToolRequest, api Reso
 	}
 
 	// Log the tool execution
-	logger := common.GetLogger()
+	logger := toolLogger("resources")
 	logger.LogToolExecution("resources", action, args)
 
+	// Bulk work runs in the background pool, so it cannot delay other calls
+	if backgroundResourceActions[action] {
+		ctx = client.WithPriority(ctx, client.PriorityBackground)
+	}
+
 	var err error
 	var result interface{}
 
@@ -126,6 +303,10 @@ func ResourcesToolHandler(ctx context.Context, req mcp.CallToolRequest, api Reso
 			PageSize: 100,
 			PageNo:   1,
 		}
+		if req.GetBool("allPages", false) {
+			result, err = searchAllPages(ctx, api, searchParams)
+			break
+		}
 		result, err = api.Search(ctx, searchParams)
 	case "get":
 		logger.Info("Executing Get resource with ID: %s", id)
@@ -144,7 +325,18 @@ func ResourcesToolHandler(ctx context.Context, req mcp.CallToolRequest, api Reso
 				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Resource ID is required for getDetailed action"}},
 			}, nil
 		}
-		result, err = api.GetDetailed(ctx, id)
+		include := req.GetStringSlice("include", nil)
+		if len(include) == 0 {
+			result, err = api.GetDetailed(ctx, id)
+			break
+		}
+		if err := validateDetailSections(include); err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+			}, nil
+		}
+		result, err = getResourceDetails(ctx, api, id, include)
 	case "getMinimal":
 		logger.Info("Executing GetMinimal resource with ID: %s", id)
 		if id == "" {
@@ -164,14 +356,154 @@ func ResourcesToolHandler(ctx context.Context, req mcp.CallToolRequest, api Reso
 		}
 		// Convert config to ResourceCreateRequest
 		var createRequest types.ResourceCreateRequest
-		configJSON, _ := json.Marshal(config)
-		if err := json.Unmarshal(configJSON, &createRequest); err != nil {
+		if err := decodeArgument("resources", "config", config, &createRequest); err != nil {
 			return &mcp.CallToolResult{
 				IsError: true,
 				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Failed to parse create request: %v", err)}},
 			}, nil
 		}
 		result, err = api.Create(ctx, createRequest)
+	case "createOrUpdate":
+		logger.Info("Executing CreateOrUpdate resource")
+		if config == nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Configuration is required for createOrUpdate action"}},
+			}, nil
+		}
+		var upsertRequest types.ResourceCreateRequest
+		if err := decodeArgument("resources", "config", config, &upsertRequest); err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Failed to parse createOrUpdate request: %v", err)}},
+			}, nil
+		}
+		result, err = upsertResource(ctx, api, requestTenant(req), upsertRequest)
+	case "listTemplates":
+		logger.Info("Executing ListTemplates")
+		result, err = NewResourceTemplates().List()
+	case "listDynamicGroups":
+		logger.Info("Executing ListDynamicGroups")
+		result, err = NewDynamicGroups(api, requestTenant(req)).List()
+	case "previewDynamicGroup", "createDynamicGroup", "updateDynamicGroup", "refreshDynamicGroup":
+		logger.Info("Executing %s: %s", action, id)
+		var selector *types.ResourceSelector
+		if selectorArg, ok := args["selector"].(map[string]interface{}); ok {
+			selector = &types.ResourceSelector{}
+			if err := decodeArgument("resources", "selector", selectorArg, selector); err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Failed to parse selector: %v", err)}},
+				}, nil
+			}
+		}
+		var missing string
+		switch {
+		case action == "previewDynamicGroup" && (id == "") == (selector == nil):
+			missing = "Exactly one of id or selector is required for previewDynamicGroup action"
+		case action == "createDynamicGroup" && (selector == nil || req.GetString("name", "") == ""):
+			missing = "A name and selector are required for createDynamicGroup action"
+		case (action == "updateDynamicGroup" || action == "refreshDynamicGroup") && id == "":
+			missing = fmt.Sprintf("Device group ID is required for %s action", action)
+		}
+		if missing != "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: missing}},
+			}, nil
+		}
+		groups := NewDynamicGroups(api, requestTenant(req))
+		if action == "previewDynamicGroup" {
+			if selector != nil {
+				result, err = groups.Preview(ctx, *selector)
+			} else {
+				result, err = groups.Refresh(ctx, id, false)
+			}
+			break
+		}
+		release, lockErr := acquireBulkLock(ctx, req, "resources")
+		if lockErr != nil {
+			err = lockErr
+			break
+		}
+		defer release()
+		switch action {
+		case "createDynamicGroup":
+			result, err = groups.Create(ctx, req.GetString("name", ""), req.GetString("description", ""), *selector)
+		case "updateDynamicGroup":
+			result, err = groups.Update(ctx, id, req.GetString("name", ""), req.GetString("description", ""), selector)
+		default:
+			result, err = groups.Refresh(ctx, id, true)
+		}
+	case "snapshotGroups":
+		logger.Info("Executing SnapshotGroups")
+		result, err = NewGroupHistory(api, requestTenant(req)).Snapshot(ctx)
+	case "getGroupHistory", "diffGroups":
+		logger.Info("Executing %s: %s", action, id)
+		if action == "getGroupHistory" && id == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Device group or site ID is required for getGroupHistory action"}},
+			}, nil
+		}
+		fallback := "last7d"
+		if action == "getGroupHistory" {
+			fallback = "last30d"
+		}
+		timeRange, rangeErr := timeRangeFromRequest(req, fallback)
+		if rangeErr != nil {
+			return timeRangeErrorResult(rangeErr), nil
+		}
+		history := NewGroupHistory(api, requestTenant(req))
+		if action == "getGroupHistory" {
+			result, err = history.History(id, timeRange)
+		} else {
+			result, err = history.Diff(timeRange)
+		}
+	case "saveTemplate":
+		name := req.GetString("template", "")
+		logger.Info("Executing SaveTemplate: %s", name)
+		if name == "" || config == nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Template name and configuration are required for saveTemplate action"}},
+			}, nil
+		}
+		var template common.ResourceTemplate
+		if err := decodeArgument("resources", "config", config, &template); err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Failed to parse template: %v", err)}},
+			}, nil
+		}
+		if err = NewResourceTemplates().Save(name, template); err == nil {
+			result = templateInfo(name, "store", template)
+		}
+	case "createFromTemplate":
+		name := req.GetString("template", "")
+		logger.Info("Executing CreateFromTemplate: %s", name)
+		if name == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Template name is required for createFromTemplate action"}},
+			}, nil
+		}
+		variables := map[string]string{}
+		if variablesArg, ok := args["variables"].(map[string]interface{}); ok {
+			for key, value := range variablesArg {
+				if value != nil {
+					variables[key] = fmt.Sprint(value)
+				}
+			}
+		}
+		createRequest, renderErr := NewResourceTemplates().Render(name, variables)
+		if renderErr != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: renderErr.Error()}},
+			}, nil
+		}
+		result, err = api.Create(ctx, *createRequest)
 	case "update":
 		logger.Info("Executing Update resource with ID: %s", id)
 		if id == "" {
@@ -188,14 +520,31 @@ func ResourcesToolHandler(ctx context.Context, req mcp.CallToolRequest, api Reso
 		}
 		// Convert config to ResourceUpdateRequest
 		var updateRequest types.ResourceUpdateRequest
-		configJSON, _ := json.Marshal(config)
-		if err := json.Unmarshal(configJSON, &updateRequest); err != nil {
+		if err := decodeArgument("resources", "config", config, &updateRequest); err != nil {
 			return &mcp.CallToolResult{
 				IsError: true,
 				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Failed to parse update request: %v", err)}},
 			}, nil
 		}
 		result, err = api.Update(ctx, id, updateRequest)
+	case "changeState":
+		logger.Info("Executing ChangeState of resource with ID: %s", id)
+		if id == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Resource ID is required for changeState action"}},
+			}, nil
+		}
+		state := strings.TrimSpace(req.GetString("state", ""))
+		if state == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("state is required for changeState action, e.g. %s or %s", managedState, unmanagedState)}},
+			}, nil
+		}
+		if err = api.ChangeState(ctx, id, types.ResourceStateChangeRequest{State: state}); err == nil {
+			result = &types.ResourceStateChange{ResourceID: id, State: state}
+		}
 	case "delete":
 		logger.Info("Executing Delete resource with ID: %s", id)
 		if id == "" {
@@ -205,13 +554,81 @@ func ResourcesToolHandler(ctx context.Context, req mcp.CallToolRequest, api Reso
 			}, nil
 		}
 		err = api.Delete(ctx, id)
+	case "bulkUpdate", "bulkDelete":
+		logger.Info("Executing %s resources", action)
+		if action == "bulkUpdate" && config == nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Configuration is required for bulkUpdate action"}},
+			}, nil
+		}
+		ids := req.GetStringSlice("ids", nil)
+		selectorArg, hasSelector := args["selector"].(map[string]interface{})
+		if (len(ids) == 0) == !hasSelector {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Exactly one of ids or selector is required for %s action", action)}},
+			}, nil
+		}
+		// Previews only resolve targets, so they run without the bulk lock
+		if !hasSelector || req.GetBool("confirm", false) {
+			release, lockErr := acquireBulkLock(ctx, req, "resources")
+			if lockErr != nil {
+				err = lockErr
+				break
+			}
+			defer release()
+		}
+		if hasSelector {
+			// Convert selector to ResourceSelector
+			var selector types.ResourceSelector
+			if err := decodeArgument("resources", "selector", selectorArg, &selector); err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Failed to parse selector: %v", err)}},
+				}, nil
+			}
+			result, err = NewBulkSelector(api).
+				WithDeleteConfirmation(getDeleteConfirmation(), requestTenant(req), req.GetString("confirmationToken", "")).
+				Run(ctx, action, selector, config, req.GetBool("confirm", false), req.GetInt("expectedCount", -1))
+		} else if action == "bulkUpdate" {
+			err = api.BulkUpdate(ctx, types.ResourceBulkUpdateRequest{ResourceIDs: ids, Updates: config})
+		} else {
+			confirmation := getDeleteConfirmation()
+			if confirmation.Requires(len(ids)) {
+				if err = confirmation.Verify(req.GetString("confirmationToken", ""), requestTenant(req), ids); err != nil {
+					break
+				}
+			}
+			err = api.BulkDelete(ctx, types.ResourceBulkDeleteRequest{ResourceIDs: ids})
+		}
+	case "planDelete":
+		ids := req.GetStringSlice("ids", nil)
+		selectorArg, hasSelector := args["selector"].(map[string]interface{})
+		if (len(ids) == 0) == !hasSelector {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Exactly one of ids or selector is required for planDelete action"}},
+			}, nil
+		}
+		var selector *types.ResourceSelector
+		if hasSelector {
+			selector = &types.ResourceSelector{}
+			if err := decodeArgument("resources", "selector", selectorArg, selector); err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Failed to parse selector: %v", err)}},
+				}, nil
+			}
+		}
+		logger.Info("Executing planDelete for %d ids (selector: %t)", len(ids), hasSelector)
+		result, err = planDelete(ctx, api, getDeleteConfirmation(), requestTenant(req), ids, selector)
 	case "search":
 		logger.Info("Executing Search resources with parameters")
 		// Convert params to ResourceSearchParams
 		var searchParams types.ResourceSearchParams
 		if params != nil {
-			paramsJSON, _ := json.Marshal(params)
-			if err := json.Unmarshal(paramsJSON, &searchParams); err != nil {
+			if err := decodeArgument("resources", "params", params, &searchParams); err != nil {
 				return &mcp.CallToolResult{
 					IsError: true,
 					Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Failed to parse search parameters: %v", err)}},
@@ -224,10 +641,272 @@ func ResourcesToolHandler(ctx context.Context, req mcp.CallToolRequest, api Reso
 				PageNo:   1,
 			}
 		}
+		if groupBy := req.GetString("groupBy", ""); groupBy != "" {
+			if err := validateGroupBy(groupBy); err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+				}, nil
+			}
+			logger.Info("Grouping search results by %s", groupBy)
+			result, err = groupSearchResults(ctx, api, searchParams, groupBy, req.GetInt("sampleSize", defaultGroupSample))
+			break
+		}
+		if req.GetBool("allPages", false) {
+			logger.Info("Fetching every page of the search")
+			result, err = searchAllPages(ctx, api, searchParams)
+			break
+		}
 		result, err = api.Search(ctx, searchParams)
 	case "getResourceTypes":
 		logger.Info("Executing GetResourceTypes")
 		result, err = api.GetResourceTypes(ctx)
+	case "invalidateCache":
+		logger.Info("Executing InvalidateCache")
+		result = invalidateCache(api)
+	case "resolveId":
+		logger.Info("Executing ID resolution for: %s", id)
+		result, err = resourceIDs.Resolve(ctx, api, req.GetString("tenant", ""), id, req.GetString("idKind", ""))
+	case "getTags", "updateTags", "addTag", "removeTag":
+		logger.Info("Executing %s for resource: %s", action, id)
+		if id == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Resource ID is required for %s action", action)}},
+			}, nil
+		}
+		tenant := req.GetString("tenant", "")
+		switch action {
+		case "getTags":
+			var tags []types.Tag
+			if tags, err = api.GetTags(ctx, id); err == nil {
+				if tags == nil {
+					tags = []types.Tag{}
+				}
+				result = &types.ResourceTags{ResourceID: id, Tags: tags}
+			}
+		case "updateTags":
+			tagsArg, ok := args["tags"]
+			if !ok {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "tags is required for updateTags action; pass an empty list to remove every tag"}},
+				}, nil
+			}
+			tags := []types.Tag{}
+			if err := decodeArgument("resources", "tags", tagsArg, &tags); err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Failed to parse tags: %v", err)}},
+				}, nil
+			}
+			result, err = replaceResourceTags(ctx, api, id, tags)
+		case "addTag":
+			result, err = addResourceTag(ctx, api, tenant, id, types.Tag{Name: req.GetString("tagName", ""), Value: req.GetString("tagValue", "")})
+		case "removeTag":
+			result, err = removeResourceTag(ctx, api, tenant, id, req.GetString("tagName", ""), req.GetString("tagValue", ""))
+		}
+	case "listDeviceGroups", "getDeviceGroup", "createDeviceGroup", "updateDeviceGroup", "deleteDeviceGroup",
+		"listSites", "getSite", "createSite", "updateSite", "deleteSite",
+		"listServiceGroups", "getServiceGroup", "createServiceGroup", "updateServiceGroup", "deleteServiceGroup":
+		logger.Info("Executing %s", action)
+		result, err = groupAction(ctx, req, api, action)
+	case "inferPropertySchema":
+		resourceType := req.GetString("resourceType", "")
+		if resourceType == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "resourceType is required for inferPropertySchema action"}},
+			}, nil
+		}
+		logger.Info("Executing InferPropertySchema for resource type: %s", resourceType)
+		result, err = inferPropertySchema(ctx, api, resourceType, req.GetInt("sampleSize", defaultSchemaSample))
+	case "getMetricCatalog":
+		resourceType := req.GetString("resourceType", "")
+		if resourceType == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "resourceType is required for getMetricCatalog action"}},
+			}, nil
+		}
+		logger.Info("Executing GetMetricCatalog for resource type: %s", resourceType)
+		result, err = metricCatalogs.Get(ctx, api, req.GetString("tenant", ""), resourceType,
+			req.GetInt("sampleSize", defaultMetricCatalogSample), req.GetBool("refresh", false))
+	case "onboard":
+		logger.Info("Executing Onboard unmanaged resources")
+		if config == nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Configuration is required for onboard action"}},
+			}, nil
+		}
+		// Convert config to OnboardingRequest
+		var onboardingRequest types.OnboardingRequest
+		if err := decodeArgument("resources", "config", config, &onboardingRequest); err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Failed to parse onboard request: %v", err)}},
+			}, nil
+		}
+		if !onboardingRequest.DryRun {
+			release, lockErr := acquireBulkLock(ctx, req, "resources")
+			if lockErr != nil {
+				err = lockErr
+				break
+			}
+			defer release()
+		}
+		result, err = NewOnboarder(api).Run(ctx, onboardingRequest)
+	case "impactAnalysis":
+		logger.Info("Executing ImpactAnalysis for resource: %s", id)
+		if id == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Resource ID is required for impactAnalysis action"}},
+			}, nil
+		}
+		provider, ok := api.(AlertsProvider)
+		if !ok {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Impact analysis is not supported by this resources backend"}},
+			}, nil
+		}
+		locale, localeErr := localeFromRequest(req)
+		if localeErr != nil {
+			err = localeErr
+			break
+		}
+		analyzer := NewImpactAnalyzer(api, provider.Alerts())
+		analyzer.locale = locale
+		result, err = analyzer.Analyze(ctx, id, req.GetInt("depth", defaultImpactDepth))
+	case "scorecard":
+		logger.Info("Executing Scorecard for resource: %s", id)
+		if id == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Resource ID is required for scorecard action"}},
+			}, nil
+		}
+		locale, localeErr := localeFromRequest(req)
+		if localeErr != nil {
+			err = localeErr
+			break
+		}
+		builder := NewScorecardBuilder(api)
+		builder.locale = locale
+		result, err = builder.Build(ctx, id)
+	case "timelineReport":
+		serviceGroup := req.GetString("serviceGroup", "")
+		logger.Info("Executing TimelineReport for resource: %s, service group: %s", id, serviceGroup)
+		if (id == "") == (serviceGroup == "") {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Either a resource ID or a serviceGroup is required for timelineReport action"}},
+			}, nil
+		}
+		timeRange, rangeErr := timeRangeFromRequest(req, "last24h")
+		if rangeErr != nil {
+			return timeRangeErrorResult(rangeErr), nil
+		}
+		builder := NewTimelineBuilder(api)
+		if serviceGroup != "" {
+			result, err = builder.BuildForServiceGroup(ctx, serviceGroup, timeRange)
+		} else {
+			result, err = builder.BuildForResource(ctx, id, timeRange)
+		}
+	case "getMetrics":
+		logger.Info("Executing GetMetrics for resource: %s", id)
+		if id == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Resource ID is required for getMetrics action"}},
+			}, nil
+		}
+		metricNames := req.GetStringSlice("metrics", nil)
+		if len(metricNames) == 0 {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "At least one metric name is required for getMetrics action"}},
+			}, nil
+		}
+		timeRange, rangeErr := timeRangeFromRequest(req, "last24h")
+		if rangeErr != nil {
+			return timeRangeErrorResult(rangeErr), nil
+		}
+		result, err = getResourceMetrics(ctx, api, id, types.ResourceMetricsRequest{
+			MetricNames: metricNames,
+			StartTime:   timeRange.Start.Format(time.RFC3339),
+			EndTime:     timeRange.End.Format(time.RFC3339),
+			Interval:    req.GetString("interval", ""),
+			Instance:    req.GetString("instance", ""),
+		})
+	case "listMetricInstances":
+		metric := req.GetString("metric", "")
+		logger.Info("Executing ListMetricInstances of %s for resource: %s", metric, id)
+		if id == "" || metric == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Resource ID and metric are required for listMetricInstances action"}},
+			}, nil
+		}
+		provider, ok := api.(MetricInstancesProvider)
+		if !ok {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Metric instances are not supported by this resources backend"}},
+			}, nil
+		}
+		var instances []types.MetricInstance
+		if instances, err = provider.GetMetricInstances(ctx, id, metric); err == nil {
+			result = &types.ResourceMetricInstances{ResourceID: id, Metric: metric, Instances: instances}
+		}
+	case "getAvailability":
+		logger.Info("Executing GetAvailability for resource: %s", id)
+		if id == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Resource ID is required for getAvailability action"}},
+			}, nil
+		}
+		provider, ok := api.(AvailabilityProvider)
+		if !ok {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Availability is not supported by this resources backend"}},
+			}, nil
+		}
+		timeRange, rangeErr := timeRangeFromRequest(req, "last24h")
+		if rangeErr != nil {
+			return timeRangeErrorResult(rangeErr), nil
+		}
+		result, err = provider.GetAvailability(ctx, id, timeRange.Start, timeRange.End)
+	case "watch", "unwatch", "listWatches":
+		logger.Info("Executing %s for resource: %s", action, id)
+		sessionID := session.IDFromContext(ctx)
+		if sessionID == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Watching resources requires an MCP session"}},
+			}, nil
+		}
+		if id == "" && action != "listWatches" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Resource ID is required for %s action", action)}},
+			}, nil
+		}
+		switch action {
+		case "watch":
+			err = session.Watch(sessionID, "resource", id)
+		case "unwatch":
+			if !session.Unwatch(sessionID, "resource", id) {
+				err = fmt.Errorf("this session is not watching resource %s", id)
+			}
+		}
+		if err == nil {
+			result = map[string]interface{}{"watching": session.Watched(sessionID, "resource")}
+		}
 	default:
 		logger.Error("Unknown action: %s", action)
 		return &mcp.CallToolResult{
@@ -239,6 +918,28 @@ func ResourcesToolHandler(ctx context.Context, req mcp.CallToolRequest, api Reso
 	// Log the result
 	logger.LogToolResult("resources", action, result, err)
 
+	// The resource may have been named by another of its identifiers, such
+	// as its identity or hostName; retry a read with its resource ID, and
+	// leave a write for the caller to repeat with the ID
+	if err != nil && (resourceIDReads[action] || resourceIDWrites[action]) && isNotFoundError(err) {
+		resolved, resolveErr := resourceIDs.resolve(ctx, api, req.GetString("tenant", ""), id, identifierKinds[1:])
+		if resolveErr == nil && resolved.ResourceID != id {
+			if resourceIDWrites[action] {
+				err = fmt.Errorf("%w; %s is the %s of resource %s (%s): to %s that resource, call %s again with id %s",
+					err, id, resolved.Kind, resolved.ResourceID, resolved.Name, action, action, resolved.ResourceID)
+			} else {
+				logger.Info("Resolved %s %s to resource ID %s", resolved.Kind, id, resolved.ResourceID)
+				args := make(map[string]interface{}, len(req.GetArguments()))
+				for key, value := range req.GetArguments() {
+					args[key] = value
+				}
+				args["id"] = resolved.ResourceID
+				req.Params.Arguments = args
+				return ResourcesToolHandler(ctx, req, api)
+			}
+		}
+	}
+
 	// If there's an error, return it
 	if err != nil {
 		return &mcp.CallToolResult{
@@ -249,22 +950,37 @@ func ResourcesToolHandler(ctx context.Context, req mcp.CallToolRequest, api Reso
 
 	// Return the result
 	if result != nil {
-		// Convert the result to JSON
-		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		toolResult, err := respondJSON(ctx, req, result)
 		if err != nil {
 			return &mcp.CallToolResult{
 				IsError: true,
-				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Failed to marshal result: %v", err)}},
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
 			}, nil
 		}
-
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: string(resultJSON)}},
-		}, nil
+		return toolResult, nil
 	}
 
 	// Return a simple success message for actions that don't return a result
 	return &mcp.CallToolResult{
-		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Operation completed successfully"}},
+		Content:           []mcp.Content{mcp.TextContent{Type: "text", Text: "Operation completed successfully"}},
+		StructuredContent: map[string]interface{}{structuredResultKey: "Operation completed successfully"},
 	}, nil
 }
+
+// getResourceMetrics retrieves metrics for a resource. For an instance query
+// it drops data points the backend returned for other instances.
+func getResourceMetrics(ctx context.Context, api ResourcesAPI, id string, request types.ResourceMetricsRequest) (*types.ResourceMetricsResponse, error) {
+	response, err := api.GetMetrics(ctx, id, request)
+	if err != nil || request.Instance == "" {
+		return response, err
+	}
+
+	points := response.Metrics[:0]
+	for _, point := range response.Metrics {
+		if point.Instance == "" || point.Instance == request.Instance {
+			points = append(points, point)
+		}
+	}
+	response.Metrics = points
+	return response, nil
+}
@@ -2,13 +2,18 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/opsramp/or-mcp-v2/common"
 	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/httperr"
 	"github.com/opsramp/or-mcp-v2/pkg/types"
 )
 
@@ -55,6 +60,33 @@ type ResourcesAPI interface {
 
 	// GetMinimal retrieves minimal resource information for performance
 	GetMinimal(ctx context.Context, id string) (*types.ResourceMinimal, error)
+
+	// GetRelationships retrieves the topology relationships of a resource
+	GetRelationships(ctx context.Context, id string) ([]types.ResourceRelationship, error)
+
+	// GetServiceGroups retrieves the service groups a resource belongs to
+	GetServiceGroups(ctx context.Context, id string) ([]types.ServiceGroup, error)
+
+	// AssignToDeviceGroup adds resources to a device group
+	AssignToDeviceGroup(ctx context.Context, groupID string, resourceIDs []string) error
+
+	// AssignToSite assigns resources to a site
+	AssignToSite(ctx context.Context, siteID string, resourceIDs []string) error
+
+	// ApplyTemplates applies monitoring templates to a resource
+	ApplyTemplates(ctx context.Context, id string, templateIDs []string) error
+}
+
+// AvailabilityProvider is implemented by resources APIs that can report
+// availability over a time range
+type AvailabilityProvider interface {
+	GetAvailability(ctx context.Context, id string, start, end time.Time) (*types.ResourceAvailability, error)
+}
+
+// MetricInstancesProvider is implemented by resources APIs that can list the
+// instances of a dimensioned metric, such as the disks behind a disk metric
+type MetricInstancesProvider interface {
+	GetMetricInstances(ctx context.Context, id, metric string) ([]types.MetricInstance, error)
 }
 
 // OpsRampResourcesAPI implements the ResourcesAPI interface for OpsRamp
@@ -64,6 +96,29 @@ type OpsRampResourcesAPI struct {
 	config *ResourcesAPIConfig
 }
 
+// entityKindResource keys resources in the entity cache
+const entityKindResource = "resource"
+
+// responseKindResourceSearch keys resource searches in the response cache
+const responseKindResourceSearch = "resources.search"
+
+// invalidate drops the cached resources with the given IDs and every cached
+// search, whose results a write may change
+func (api *OpsRampResourcesAPI) invalidate(ids ...string) {
+	api.client.Entities().Invalidate(entityKindResource, ids...)
+	api.client.Responses().InvalidateKind(responseKindResourceSearch)
+}
+
+// InvalidateCache drops the cached searches, resources and resource types
+// of the API's tenant, so the next reads fetch them from OpsRamp
+func (api *OpsRampResourcesAPI) InvalidateCache() types.CacheInvalidation {
+	return types.CacheInvalidation{
+		Responses: api.client.Responses().InvalidateKind(responseKindResourceSearch),
+		Entities:  api.client.Entities().InvalidateKind(entityKindResource),
+		Catalogs:  api.client.Catalogs().Clear(),
+	}
+}
+
 // ResourcesAPIConfig holds configuration for the Resources API client
 type ResourcesAPIConfig struct {
 	RetryAttempts  int           `json:"retry_attempts"`
@@ -78,7 +133,7 @@ type ResourcesAPIConfig struct {
 // NewOpsRampResourcesAPI creates a new OpsRamp resources API client
 func NewOpsRampResourcesAPI(client *client.OpsRampClient) *OpsRampResourcesAPI {
 	// Get the logger
-	logger := common.GetLogger()
+	logger := client.Logger().WithModule(toolModule("resources"))
 
 	// Default configuration
 	config := &ResourcesAPIConfig{
@@ -101,7 +156,7 @@ func NewOpsRampResourcesAPI(client *client.OpsRampClient) *OpsRampResourcesAPI {
 // NewOpsRampResourcesAPIWithConfig creates a new OpsRamp resources API client with custom configuration
 func NewOpsRampResourcesAPIWithConfig(client *client.OpsRampClient, config *ResourcesAPIConfig) *OpsRampResourcesAPI {
 	// Get the logger
-	logger := common.GetLogger()
+	logger := client.Logger().WithModule(toolModule("resources"))
 
 	return &OpsRampResourcesAPI{
 		client: client,
@@ -110,11 +165,8 @@ func NewOpsRampResourcesAPIWithConfig(client *client.OpsRampClient, config *Reso
 	}
 }
 
-// Search searches for resources based on the provided parameters
-func (api *OpsRampResourcesAPI) Search(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
-	api.logger.Info("Searching for resources with parameters")
-
-	// Build query parameters
+// resourceSearchQuery builds the query parameters of a resource search
+func resourceSearchQuery(params types.ResourceSearchParams) url.Values {
 	queryParams := url.Values{}
 
 	// Add pagination parameters
@@ -248,6 +300,14 @@ func (api *OpsRampResourcesAPI) Search(ctx context.Context, params types.Resourc
 		queryParams.Add("providerUID", params.ProviderUID)
 	}
 
+	return queryParams
+}
+
+// Search searches for resources based on the provided parameters
+func (api *OpsRampResourcesAPI) Search(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+	api.logger.Info("Searching for resources with parameters")
+	queryParams := resourceSearchQuery(params)
+
 	// Build the endpoint with query parameters
 	// Build the endpoint without query parameters
 	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/search", api.client.GetTenantID())
@@ -257,34 +317,105 @@ func (api *OpsRampResourcesAPI) Search(ctx context.Context, params types.Resourc
 		endpoint = fmt.Sprintf("%s?%s", endpoint, queryParams.Encode())
 	}
 
-	api.logger.Debug("Using endpoint: %s", endpoint) // Make the request
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Serve repeated searches from the response cache; the raw body is
+	// cached so each caller gets its own copy of the results
+	responses := api.client.Responses()
+	var body json.RawMessage
+	cached, generation, ok := responses.Get(responseKindResourceSearch, endpoint)
+	if ok {
+		body = cached.(json.RawMessage)
+		api.logger.Debug("Serving resource search from cache")
+	} else {
+		if err := api.client.Get(ctx, endpoint, &body); err != nil {
+			api.logger.Error("Failed to search resources: %v", err)
+			return nil, fmt.Errorf("failed to search resources: %w", err)
+		}
+		responses.Put(responseKindResourceSearch, endpoint, body, generation)
+	}
+
 	var response types.ResourceSearchResponse
-	err := api.client.Get(ctx, endpoint, &response)
-	if err != nil {
+	if err := json.Unmarshal(body, &response); err != nil {
 		api.logger.Error("Failed to search resources: %v", err)
-		return nil, fmt.Errorf("failed to search resources: %w", err)
+		return nil, fmt.Errorf("failed to search resources: failed to parse response: %w", err)
 	}
 
+	response.Normalize(params.PageNo, params.PageSize)
 	api.logger.Info("Successfully searched resources, found %d results", len(response.Results))
 	return &response, nil
 }
 
+// SearchAll runs a search over every page from params.PageNo (or the first
+// page), returning at most limit resources. The pages are not cached, as a
+// full scan is rarely repeated.
+func (api *OpsRampResourcesAPI) SearchAll(ctx context.Context, params types.ResourceSearchParams, limit int) (*types.ResourceSearchResponse, error) {
+	api.logger.Info("Searching for all resources, up to %d", limit)
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/search", api.client.GetTenantID())
+
+	response := &types.ResourceSearchResponse{Results: []types.Resource{}}
+	var last types.PageInfo
+	err := api.client.Paginate(ctx, endpoint, resourceSearchQuery(params), func(page *client.Page) error {
+		var resources []types.Resource
+		if err := page.Decode(&resources); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		if response.PageNo == 0 {
+			response.PageNo, response.PageSize = page.Info.PageNo, page.Info.PageSize
+		}
+		response.Results = append(response.Results, resources...)
+		last = page.Info
+		if len(response.Results) >= limit {
+			return client.ErrStopPagination
+		}
+		return nil
+	})
+	if err != nil {
+		api.logger.Error("Failed to search all resources: %v", err)
+		return nil, fmt.Errorf("failed to search resources: %w", err)
+	}
+
+	hasMore := last.HasMore
+	if len(response.Results) > limit {
+		response.Results, hasMore = response.Results[:limit], true
+	}
+	response.Pagination = allPagesInfo(response.PageNo, response.PageSize, len(response.Results), hasMore, last.EstimatedTotal)
+	response.TotalResults, response.TotalPages, response.NextPage = response.Pagination.EstimatedTotal, response.Pagination.TotalPages(), hasMore
+	api.logger.Info("Successfully searched all resources, found %d results", len(response.Results))
+	return response, nil
+}
+
 // Get retrieves a specific resource by ID
 func (api *OpsRampResourcesAPI) Get(ctx context.Context, id string) (*types.Resource, error) {
 	api.logger.Info("Getting resource with ID: %s", id)
 
 	// Build the endpoint
-	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s", api.client.GetTenantID(), id)
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s", api.client.GetTenantID(), pathID)
 	api.logger.Debug("Using endpoint: %s", endpoint)
 
+	// Serve repeated reads from the entity cache
+	entities := api.client.Entities()
+	cached, generation, ok := entities.Get(entityKindResource, id)
+	if ok {
+		resource := *cached.(*types.Resource)
+		api.logger.Debug("Serving resource %s from cache", id)
+		return &resource, nil
+	}
+
 	// Make the request
 	var resource types.Resource
-	err := api.client.Get(ctx, endpoint, &resource)
+	err = api.client.Get(ctx, endpoint, &resource)
 	if err != nil {
 		api.logger.Error("Failed to get resource %s: %v", id, err)
 		return nil, fmt.Errorf("failed to get resource %s: %w", id, err)
 	}
 
+	stored := resource
+	entities.Put(entityKindResource, id, &stored, generation)
 	api.logger.Info("Successfully retrieved resource: %s", resource.Name)
 	return &resource, nil
 }
@@ -294,12 +425,16 @@ func (api *OpsRampResourcesAPI) GetDetailed(ctx context.Context, id string) (*ty
 	api.logger.Info("Getting detailed resource with ID: %s", id)
 
 	// Build the endpoint
-	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s", api.client.GetTenantID(), id)
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s", api.client.GetTenantID(), pathID)
 	api.logger.Debug("Using endpoint: %s", endpoint)
 
 	// Make the request
 	var detailedResource types.DetailedResource
-	err := api.client.Get(ctx, endpoint, &detailedResource)
+	err = api.client.Get(ctx, endpoint, &detailedResource)
 	if err != nil {
 		api.logger.Error("Failed to get detailed resource %s: %v", id, err)
 		return nil, fmt.Errorf("failed to get detailed resource %s: %w", id, err)
@@ -312,6 +447,7 @@ func (api *OpsRampResourcesAPI) GetDetailed(ctx context.Context, id string) (*ty
 // Create creates a new resource
 func (api *OpsRampResourcesAPI) Create(ctx context.Context, resource types.ResourceCreateRequest) (*types.Resource, error) {
 	api.logger.Info("Creating new resource of type: %s", resource.ResourceType)
+	defer api.invalidate()
 
 	// Build the endpoint
 	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources", api.client.GetTenantID())
@@ -332,14 +468,19 @@ func (api *OpsRampResourcesAPI) Create(ctx context.Context, resource types.Resou
 // Update updates an existing resource
 func (api *OpsRampResourcesAPI) Update(ctx context.Context, id string, resource types.ResourceUpdateRequest) (*types.Resource, error) {
 	api.logger.Info("Updating resource with ID: %s", id)
+	defer api.invalidate(id)
 
 	// Build the endpoint
-	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s", api.client.GetTenantID(), id)
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s", api.client.GetTenantID(), pathID)
 	api.logger.Debug("Using endpoint: %s", endpoint)
 
 	// Make the request
 	var updatedResource types.Resource
-	err := api.client.Post(ctx, endpoint, resource, &updatedResource)
+	err = api.client.Post(ctx, endpoint, resource, &updatedResource)
 	if err != nil {
 		api.logger.Error("Failed to update resource %s: %v", id, err)
 		return nil, fmt.Errorf("failed to update resource %s: %w", id, err)
@@ -352,13 +493,18 @@ func (api *OpsRampResourcesAPI) Update(ctx context.Context, id string, resource
 // Delete deletes a resource by ID
 func (api *OpsRampResourcesAPI) Delete(ctx context.Context, id string) error {
 	api.logger.Info("Deleting resource with ID: %s", id)
+	defer api.invalidate(id)
 
 	// Build the endpoint
-	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s", api.client.GetTenantID(), id)
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s", api.client.GetTenantID(), pathID)
 	api.logger.Debug("Using endpoint: %s", endpoint)
 
 	// Make the request
-	err := api.client.Delete(ctx, endpoint)
+	err = api.client.Delete(ctx, endpoint)
 	if err != nil {
 		api.logger.Error("Failed to delete resource %s: %v", id, err)
 		return fmt.Errorf("failed to delete resource %s: %w", id, err)
@@ -371,6 +517,7 @@ func (api *OpsRampResourcesAPI) Delete(ctx context.Context, id string) error {
 // BulkUpdate updates multiple resources at once
 func (api *OpsRampResourcesAPI) BulkUpdate(ctx context.Context, request types.ResourceBulkUpdateRequest) error {
 	api.logger.Info("Bulk updating %d resources", len(request.ResourceIDs))
+	defer api.invalidate(request.ResourceIDs...)
 
 	// Build the endpoint
 	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/bulk-update", api.client.GetTenantID())
@@ -390,6 +537,7 @@ func (api *OpsRampResourcesAPI) BulkUpdate(ctx context.Context, request types.Re
 // BulkDelete deletes multiple resources at once
 func (api *OpsRampResourcesAPI) BulkDelete(ctx context.Context, request types.ResourceBulkDeleteRequest) error {
 	api.logger.Info("Bulk deleting %d resources", len(request.ResourceIDs))
+	defer api.invalidate(request.ResourceIDs...)
 
 	// Build the endpoint
 	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/bulk-delete", api.client.GetTenantID())
@@ -418,26 +566,553 @@ func (api *OpsRampResourcesAPI) GetResourceTypes(ctx context.Context) ([]types.R
 	var response struct {
 		ResourceTypes []types.ResourceTypeInfo `json:"resourceTypes"`
 	}
-	err := api.client.Get(ctx, endpoint, &response)
+	status, err := api.client.GetCatalog(ctx, endpoint, &response)
 	if err != nil {
 		api.logger.Error("Failed to get resource types: %v", err)
 		return nil, fmt.Errorf("failed to get resource types: %w", err)
 	}
 
-	api.logger.Info("Successfully retrieved %d resource types", len(response.ResourceTypes))
+	api.logger.Info("Successfully retrieved %d resource types (%s)", len(response.ResourceTypes), status)
 	return response.ResourceTypes, nil
 }
 
+// GetRelationships retrieves the topology relationships of a resource
+func (api *OpsRampResourcesAPI) GetRelationships(ctx context.Context, id string) ([]types.ResourceRelationship, error) {
+	api.logger.Info("Getting relationships for resource: %s", id)
+
+	// Build the endpoint
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/relationships", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var response struct {
+		Results []types.ResourceRelationship `json:"results"`
+	}
+	err = api.client.Get(ctx, endpoint, &response)
+	if err != nil {
+		api.logger.Error("Failed to get relationships for resource %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get relationships for resource %s: %w", id, err)
+	}
+
+	api.logger.Info("Successfully retrieved %d relationships for resource %s", len(response.Results), id)
+	return response.Results, nil
+}
+
+// GetServiceGroups retrieves the service groups a resource belongs to
+func (api *OpsRampResourcesAPI) GetServiceGroups(ctx context.Context, id string) ([]types.ServiceGroup, error) {
+	api.logger.Info("Getting service groups for resource: %s", id)
+
+	// Build the endpoint
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/serviceGroups", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var response struct {
+		Results []types.ServiceGroup `json:"results"`
+	}
+	err = api.client.Get(ctx, endpoint, &response)
+	if err != nil {
+		api.logger.Error("Failed to get service groups for resource %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get service groups for resource %s: %w", id, err)
+	}
+
+	return response.Results, nil
+}
+
+// AssignToDeviceGroup adds resources to a device group
+func (api *OpsRampResourcesAPI) AssignToDeviceGroup(ctx context.Context, groupID string, resourceIDs []string) error {
+	api.logger.Info("Assigning %d resources to device group %s", len(resourceIDs), groupID)
+	defer api.invalidate(resourceIDs...)
+
+	// Build the endpoint
+	pathID, err := client.PathID("device group", groupID)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/deviceGroups/%s/childs", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Build the request body
+	children := make([]map[string]string, 0, len(resourceIDs))
+	for _, id := range resourceIDs {
+		children = append(children, map[string]string{"id": id, "type": "DEVICE"})
+	}
+
+	// Make the request
+	err = api.client.Post(ctx, endpoint, children, nil)
+	if err != nil {
+		api.logger.Error("Failed to assign resources to device group %s: %v", groupID, err)
+		return fmt.Errorf("failed to assign resources to device group %s: %w", groupID, err)
+	}
+
+	return nil
+}
+
+// CreateDeviceGroup creates an empty device group
+func (api *OpsRampResourcesAPI) CreateDeviceGroup(ctx context.Context, name, description string) (*types.DeviceGroup, error) {
+	api.logger.Info("Creating device group %s", name)
+
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/deviceGroups", api.client.GetTenantID())
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var group types.DeviceGroup
+	err := api.client.Post(ctx, endpoint, map[string]string{"name": name, "description": description}, &group)
+	if err != nil {
+		api.logger.Error("Failed to create device group %s: %v", name, err)
+		return nil, fmt.Errorf("failed to create device group %s: %w", name, err)
+	}
+
+	api.logger.Info("Successfully created device group %s (%s)", name, group.ID)
+	return &group, nil
+}
+
+// UpdateDeviceGroup renames a device group or changes its description
+func (api *OpsRampResourcesAPI) UpdateDeviceGroup(ctx context.Context, groupID, name, description string) error {
+	api.logger.Info("Updating device group %s", groupID)
+	defer api.invalidate()
+
+	// Build the endpoint
+	pathID, err := client.PathID("device group", groupID)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/deviceGroups/%s", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	err = api.client.Post(ctx, endpoint, map[string]string{"name": name, "description": description}, nil)
+	if err != nil {
+		api.logger.Error("Failed to update device group %s: %v", groupID, err)
+		return fmt.Errorf("failed to update device group %s: %w", groupID, err)
+	}
+
+	return nil
+}
+
+// GetDeviceGroupMembers returns the IDs of the resources in a device group
+func (api *OpsRampResourcesAPI) GetDeviceGroupMembers(ctx context.Context, groupID string) ([]string, error) {
+	api.logger.Info("Getting members of device group %s", groupID)
+
+	// Build the endpoint
+	pathID, err := client.PathID("device group", groupID)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/deviceGroups/%s/childs", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request; child device groups are not resources
+	var children []struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	err = api.client.Get(ctx, endpoint, &children)
+	if err != nil {
+		api.logger.Error("Failed to get members of device group %s: %v", groupID, err)
+		return nil, fmt.Errorf("failed to get members of device group %s: %w", groupID, err)
+	}
+
+	members := make([]string, 0, len(children))
+	for _, child := range children {
+		if strings.EqualFold(child.Type, "DEVICE") {
+			members = append(members, child.ID)
+		}
+	}
+	return members, nil
+}
+
+// RemoveFromDeviceGroup removes resources from a device group
+func (api *OpsRampResourcesAPI) RemoveFromDeviceGroup(ctx context.Context, groupID string, resourceIDs []string) error {
+	api.logger.Info("Removing %d resources from device group %s", len(resourceIDs), groupID)
+	defer api.invalidate(resourceIDs...)
+
+	// Build the endpoint
+	pathID, err := client.PathID("device group", groupID)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/deviceGroups/%s/childs", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Build the request body
+	children := make([]map[string]string, 0, len(resourceIDs))
+	for _, id := range resourceIDs {
+		children = append(children, map[string]string{"id": id, "type": "DEVICE"})
+	}
+
+	// Make the request
+	err = api.client.Request(ctx, http.MethodDelete, endpoint, children, nil)
+	if err != nil {
+		api.logger.Error("Failed to remove resources from device group %s: %v", groupID, err)
+		return fmt.Errorf("failed to remove resources from device group %s: %w", groupID, err)
+	}
+
+	return nil
+}
+
+// ListDeviceGroups returns the tenant's device groups
+func (api *OpsRampResourcesAPI) ListDeviceGroups(ctx context.Context) ([]types.DeviceGroup, error) {
+	api.logger.Info("Listing device groups")
+
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/deviceGroups/minimal", api.client.GetTenantID())
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var groups []types.DeviceGroup
+	err := api.client.Get(ctx, endpoint, &groups)
+	if err != nil {
+		api.logger.Error("Failed to list device groups: %v", err)
+		return nil, fmt.Errorf("failed to list device groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// ListSites returns the tenant's sites, reading every page of the search
+func (api *OpsRampResourcesAPI) ListSites(ctx context.Context) ([]types.Site, error) {
+	api.logger.Info("Listing sites")
+
+	sites := make([]types.Site, 0)
+	for pageNo := 1; ; pageNo++ {
+		endpoint := fmt.Sprintf("/api/v2/tenants/%s/sites/search?pageNo=%d&pageSize=%d", api.client.GetTenantID(), pageNo, sitePageSize)
+		api.logger.Debug("Using endpoint: %s", endpoint)
+
+		// Make the request
+		var response struct {
+			Results  []types.Site `json:"results"`
+			NextPage bool         `json:"nextPage"`
+		}
+		if err := api.client.Get(ctx, endpoint, &response); err != nil {
+			api.logger.Error("Failed to list sites: %v", err)
+			return nil, fmt.Errorf("failed to list sites: %w", err)
+		}
+		sites = append(sites, response.Results...)
+		if !response.NextPage || len(response.Results) == 0 {
+			return sites, nil
+		}
+	}
+}
+
+// AssignToSite assigns resources to a site
+func (api *OpsRampResourcesAPI) AssignToSite(ctx context.Context, siteID string, resourceIDs []string) error {
+	api.logger.Info("Assigning %d resources to site %s", len(resourceIDs), siteID)
+	defer api.invalidate(resourceIDs...)
+
+	// Build the endpoint
+	pathID, err := client.PathID("site", siteID)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/sites/%s/resources", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Build the request body
+	resources := make([]map[string]string, 0, len(resourceIDs))
+	for _, id := range resourceIDs {
+		resources = append(resources, map[string]string{"id": id})
+	}
+
+	// Make the request
+	err = api.client.Post(ctx, endpoint, resources, nil)
+	if err != nil {
+		api.logger.Error("Failed to assign resources to site %s: %v", siteID, err)
+		return fmt.Errorf("failed to assign resources to site %s: %w", siteID, err)
+	}
+
+	return nil
+}
+
+// GetDeviceGroup retrieves a device group by ID
+func (api *OpsRampResourcesAPI) GetDeviceGroup(ctx context.Context, groupID string) (*types.DeviceGroup, error) {
+	api.logger.Info("Getting device group %s", groupID)
+
+	endpoint, err := api.client.EndpointPath("deviceGroups.get", groupID)
+	if err != nil {
+		return nil, err
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var group types.DeviceGroup
+	if err := api.client.Get(ctx, endpoint, &group); err != nil {
+		api.logger.Error("Failed to get device group %s: %v", groupID, err)
+		return nil, fmt.Errorf("failed to get device group %s: %w", groupID, err)
+	}
+	return &group, nil
+}
+
+// DeleteDeviceGroup deletes a device group; its resources are kept
+func (api *OpsRampResourcesAPI) DeleteDeviceGroup(ctx context.Context, groupID string) error {
+	api.logger.Info("Deleting device group %s", groupID)
+	defer api.invalidate()
+
+	endpoint, err := api.client.EndpointPath("deviceGroups.delete", groupID)
+	if err != nil {
+		return err
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	if err := api.client.Delete(ctx, endpoint); err != nil {
+		api.logger.Error("Failed to delete device group %s: %v", groupID, err)
+		return fmt.Errorf("failed to delete device group %s: %w", groupID, err)
+	}
+
+	api.logger.Info("Successfully deleted device group %s", groupID)
+	return nil
+}
+
+// GetSite retrieves a site by ID
+func (api *OpsRampResourcesAPI) GetSite(ctx context.Context, siteID string) (*types.Site, error) {
+	api.logger.Info("Getting site %s", siteID)
+
+	endpoint, err := api.client.EndpointPath("sites.get", siteID)
+	if err != nil {
+		return nil, err
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var site types.Site
+	if err := api.client.Get(ctx, endpoint, &site); err != nil {
+		api.logger.Error("Failed to get site %s: %v", siteID, err)
+		return nil, fmt.Errorf("failed to get site %s: %w", siteID, err)
+	}
+	return &site, nil
+}
+
+// CreateSite creates a site
+func (api *OpsRampResourcesAPI) CreateSite(ctx context.Context, request types.SiteRequest) (*types.Site, error) {
+	api.logger.Info("Creating site %s", request.Name)
+
+	endpoint, err := api.client.EndpointPath("sites.create")
+	if err != nil {
+		return nil, err
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var site types.Site
+	if err := api.client.Post(ctx, endpoint, request, &site); err != nil {
+		api.logger.Error("Failed to create site %s: %v", request.Name, err)
+		return nil, fmt.Errorf("failed to create site %s: %w", request.Name, err)
+	}
+
+	api.logger.Info("Successfully created site %s (%s)", request.Name, site.ID)
+	return &site, nil
+}
+
+// UpdateSite replaces the fields of a site
+func (api *OpsRampResourcesAPI) UpdateSite(ctx context.Context, siteID string, request types.SiteRequest) (*types.Site, error) {
+	api.logger.Info("Updating site %s", siteID)
+	defer api.invalidate()
+
+	endpoint, err := api.client.EndpointPath("sites.update", siteID)
+	if err != nil {
+		return nil, err
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var site types.Site
+	if err := api.client.Post(ctx, endpoint, request, &site); err != nil {
+		api.logger.Error("Failed to update site %s: %v", siteID, err)
+		return nil, fmt.Errorf("failed to update site %s: %w", siteID, err)
+	}
+	return &site, nil
+}
+
+// DeleteSite deletes a site; its resources are kept
+func (api *OpsRampResourcesAPI) DeleteSite(ctx context.Context, siteID string) error {
+	api.logger.Info("Deleting site %s", siteID)
+	defer api.invalidate()
+
+	endpoint, err := api.client.EndpointPath("sites.delete", siteID)
+	if err != nil {
+		return err
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	if err := api.client.Delete(ctx, endpoint); err != nil {
+		api.logger.Error("Failed to delete site %s: %v", siteID, err)
+		return fmt.Errorf("failed to delete site %s: %w", siteID, err)
+	}
+
+	api.logger.Info("Successfully deleted site %s", siteID)
+	return nil
+}
+
+// ListServiceGroups returns the tenant's service groups, reading every page
+// of the search
+func (api *OpsRampResourcesAPI) ListServiceGroups(ctx context.Context) ([]types.ServiceGroup, error) {
+	api.logger.Info("Listing service groups")
+
+	base, err := api.client.EndpointPath("serviceGroups.search")
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]types.ServiceGroup, 0)
+	for pageNo := 1; ; pageNo++ {
+		endpoint := fmt.Sprintf("%s?pageNo=%d&pageSize=%d", base, pageNo, sitePageSize)
+		api.logger.Debug("Using endpoint: %s", endpoint)
+
+		// Make the request
+		var response struct {
+			Results  []types.ServiceGroup `json:"results"`
+			NextPage bool                 `json:"nextPage"`
+		}
+		if err := api.client.Get(ctx, endpoint, &response); err != nil {
+			api.logger.Error("Failed to list service groups: %v", err)
+			return nil, fmt.Errorf("failed to list service groups: %w", err)
+		}
+		groups = append(groups, response.Results...)
+		if !response.NextPage || len(response.Results) == 0 {
+			return groups, nil
+		}
+	}
+}
+
+// GetServiceGroup retrieves a service group by ID
+func (api *OpsRampResourcesAPI) GetServiceGroup(ctx context.Context, groupID string) (*types.ServiceGroup, error) {
+	api.logger.Info("Getting service group %s", groupID)
+
+	endpoint, err := api.client.EndpointPath("serviceGroups.get", groupID)
+	if err != nil {
+		return nil, err
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var group types.ServiceGroup
+	if err := api.client.Get(ctx, endpoint, &group); err != nil {
+		api.logger.Error("Failed to get service group %s: %v", groupID, err)
+		return nil, fmt.Errorf("failed to get service group %s: %w", groupID, err)
+	}
+	return &group, nil
+}
+
+// CreateServiceGroup creates a service group
+func (api *OpsRampResourcesAPI) CreateServiceGroup(ctx context.Context, request types.ServiceGroupRequest) (*types.ServiceGroup, error) {
+	api.logger.Info("Creating service group %s", request.Name)
+
+	endpoint, err := api.client.EndpointPath("serviceGroups.create")
+	if err != nil {
+		return nil, err
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var group types.ServiceGroup
+	if err := api.client.Post(ctx, endpoint, request, &group); err != nil {
+		api.logger.Error("Failed to create service group %s: %v", request.Name, err)
+		return nil, fmt.Errorf("failed to create service group %s: %w", request.Name, err)
+	}
+
+	api.logger.Info("Successfully created service group %s (%s)", request.Name, group.ID)
+	return &group, nil
+}
+
+// UpdateServiceGroup replaces the name and description of a service group
+func (api *OpsRampResourcesAPI) UpdateServiceGroup(ctx context.Context, groupID string, request types.ServiceGroupRequest) (*types.ServiceGroup, error) {
+	api.logger.Info("Updating service group %s", groupID)
+	defer api.invalidate()
+
+	endpoint, err := api.client.EndpointPath("serviceGroups.update", groupID)
+	if err != nil {
+		return nil, err
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var group types.ServiceGroup
+	if err := api.client.Post(ctx, endpoint, request, &group); err != nil {
+		api.logger.Error("Failed to update service group %s: %v", groupID, err)
+		return nil, fmt.Errorf("failed to update service group %s: %w", groupID, err)
+	}
+	return &group, nil
+}
+
+// DeleteServiceGroup deletes a service group; its member resources are kept
+func (api *OpsRampResourcesAPI) DeleteServiceGroup(ctx context.Context, groupID string) error {
+	api.logger.Info("Deleting service group %s", groupID)
+	defer api.invalidate()
+
+	endpoint, err := api.client.EndpointPath("serviceGroups.delete", groupID)
+	if err != nil {
+		return err
+	}
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	if err := api.client.Delete(ctx, endpoint); err != nil {
+		api.logger.Error("Failed to delete service group %s: %v", groupID, err)
+		return fmt.Errorf("failed to delete service group %s: %w", groupID, err)
+	}
+
+	api.logger.Info("Successfully deleted service group %s", groupID)
+	return nil
+}
+
+// ApplyTemplates applies monitoring templates to a resource
+func (api *OpsRampResourcesAPI) ApplyTemplates(ctx context.Context, id string, templateIDs []string) error {
+	api.logger.Info("Applying %d templates to resource %s", len(templateIDs), id)
+	defer api.invalidate(id)
+
+	// Build the endpoint
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/templates", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Build the request body
+	templates := make([]map[string]string, 0, len(templateIDs))
+	for _, templateID := range templateIDs {
+		templates = append(templates, map[string]string{"id": templateID})
+	}
+
+	// Make the request
+	err = api.client.Post(ctx, endpoint, map[string]interface{}{"templates": templates}, nil)
+	if err != nil {
+		api.logger.Error("Failed to apply templates to resource %s: %v", id, err)
+		return fmt.Errorf("failed to apply templates to resource %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// Alerts returns an alerts API sharing this API's client
+func (api *OpsRampResourcesAPI) Alerts() AlertsAPI {
+	return NewOpsRampAlertsAPI(api.client)
+}
+
 // ChangeState changes the state of a resource
 func (api *OpsRampResourcesAPI) ChangeState(ctx context.Context, id string, request types.ResourceStateChangeRequest) error {
 	api.logger.Info("Changing state of resource %s to %s", id, request.State)
+	defer api.invalidate(id)
 
 	// Build the endpoint
-	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/state", api.client.GetTenantID(), id)
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/state", api.client.GetTenantID(), pathID)
 	api.logger.Debug("Using endpoint: %s", endpoint)
 
 	// Make the request
-	err := api.client.Post(ctx, endpoint, request, nil)
+	err = api.client.Post(ctx, endpoint, request, nil)
 	if err != nil {
 		api.logger.Error("Failed to change state of resource %s: %v", id, err)
 		return fmt.Errorf("failed to change state of resource %s: %w", id, err)
@@ -452,12 +1127,16 @@ func (api *OpsRampResourcesAPI) GetMetrics(ctx context.Context, id string, reque
 	api.logger.Info("Getting metrics for resource %s", id)
 
 	// Build the endpoint
-	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/metrics", api.client.GetTenantID(), id)
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/metrics", api.client.GetTenantID(), pathID)
 	api.logger.Debug("Using endpoint: %s", endpoint)
 
 	// Make the request
 	var response types.ResourceMetricsResponse
-	err := api.client.Post(ctx, endpoint, request, &response)
+	err = api.client.Post(ctx, endpoint, request, &response)
 	if err != nil {
 		api.logger.Error("Failed to get metrics for resource %s: %v", id, err)
 		return nil, fmt.Errorf("failed to get metrics for resource %s: %w", id, err)
@@ -467,19 +1146,50 @@ func (api *OpsRampResourcesAPI) GetMetrics(ctx context.Context, id string, reque
 	return &response, nil
 }
 
+// GetMetricInstances lists the instances of a metric on a resource
+func (api *OpsRampResourcesAPI) GetMetricInstances(ctx context.Context, id, metric string) ([]types.MetricInstance, error) {
+	api.logger.Info("Getting instances of metric %s for resource %s", metric, id)
+
+	// Build the endpoint
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/metrics/%s/instances",
+		api.client.GetTenantID(), pathID, url.PathEscape(metric))
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var response struct {
+		Instances []types.MetricInstance `json:"instances"`
+	}
+	err = api.client.Get(ctx, endpoint, &response)
+	if err != nil {
+		api.logger.Error("Failed to get instances of metric %s for resource %s: %v", metric, id, err)
+		return nil, fmt.Errorf("failed to get instances of metric %s for resource %s: %w", metric, id, err)
+	}
+
+	api.logger.Info("Successfully retrieved %d instances of metric %s for resource %s", len(response.Instances), metric, id)
+	return response.Instances, nil
+}
+
 // GetTags retrieves all tags for a resource
 func (api *OpsRampResourcesAPI) GetTags(ctx context.Context, id string) ([]types.Tag, error) {
 	api.logger.Info("Getting tags for resource %s", id)
 
 	// Build the endpoint
-	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/tags", api.client.GetTenantID(), id)
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/tags", api.client.GetTenantID(), pathID)
 	api.logger.Debug("Using endpoint: %s", endpoint)
 
 	// Make the request
 	var response struct {
 		Tags []types.Tag `json:"tags"`
 	}
-	err := api.client.Get(ctx, endpoint, &response)
+	err = api.client.Get(ctx, endpoint, &response)
 	if err != nil {
 		api.logger.Error("Failed to get tags for resource %s: %v", id, err)
 		return nil, fmt.Errorf("failed to get tags for resource %s: %w", id, err)
@@ -492,9 +1202,14 @@ func (api *OpsRampResourcesAPI) GetTags(ctx context.Context, id string) ([]types
 // UpdateTags updates the tags for a resource
 func (api *OpsRampResourcesAPI) UpdateTags(ctx context.Context, id string, tags []types.Tag) error {
 	api.logger.Info("Updating tags for resource %s", id)
+	defer api.invalidate(id)
 
 	// Build the endpoint
-	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/tags", api.client.GetTenantID(), id)
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/tags", api.client.GetTenantID(), pathID)
 	api.logger.Debug("Using endpoint: %s", endpoint)
 
 	// Make the request
@@ -503,7 +1218,7 @@ func (api *OpsRampResourcesAPI) UpdateTags(ctx context.Context, id string, tags
 	}{
 		Tags: tags,
 	}
-	err := api.client.Post(ctx, endpoint, request, nil)
+	err = api.client.Post(ctx, endpoint, request, nil)
 	if err != nil {
 		api.logger.Error("Failed to update tags for resource %s: %v", id, err)
 		return fmt.Errorf("failed to update tags for resource %s: %w", id, err)
@@ -513,6 +1228,99 @@ func (api *OpsRampResourcesAPI) UpdateTags(ctx context.Context, id string, tags
 	return nil
 }
 
+// GetPatches retrieves the patches applicable to a resource and their install status
+func (api *OpsRampResourcesAPI) GetPatches(ctx context.Context, id string) ([]types.ResourcePatch, error) {
+	api.logger.Info("Getting patches for resource %s", id)
+
+	// Build the endpoint
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/patches", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var response struct {
+		Results []types.ResourcePatch `json:"results"`
+	}
+	err = api.client.Get(ctx, endpoint, &response)
+	if err != nil {
+		api.logger.Error("Failed to get patches for resource %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get patches for resource %s: %w", id, err)
+	}
+
+	api.logger.Info("Successfully retrieved %d patches for resource %s", len(response.Results), id)
+	return response.Results, nil
+}
+
+// GetAvailability retrieves the availability of a resource between start and end
+func (api *OpsRampResourcesAPI) GetAvailability(ctx context.Context, id string, start, end time.Time) (*types.ResourceAvailability, error) {
+	api.logger.Info("Getting availability for resource %s", id)
+
+	// Build the endpoint
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/resources/%s/availability?startTime=%d&endTime=%d",
+		api.client.GetTenantID(), pathID, start.Unix(), end.Unix())
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request
+	var response types.ResourceAvailability
+	err = api.client.Get(ctx, endpoint, &response)
+	if err != nil {
+		api.logger.Error("Failed to get availability for resource %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get availability for resource %s: %w", id, err)
+	}
+
+	response.ResourceID = id
+	response.StartTime = start.Format(time.RFC3339)
+	response.EndTime = end.Format(time.RFC3339)
+	api.logger.Info("Successfully retrieved availability for resource %s", id)
+	return &response, nil
+}
+
+// GetMaintenanceWindows retrieves the maintenance windows scheduled for a resource
+func (api *OpsRampResourcesAPI) GetMaintenanceWindows(ctx context.Context, id string) ([]types.MaintenanceWindow, error) {
+	api.logger.Info("Getting maintenance windows for resource %s", id)
+
+	// Build the endpoint
+	pathID, err := client.PathID("resource", id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/scheduleMaintenances/search?queryString=resourceId:%s", api.client.GetTenantID(), pathID)
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	// Make the request; the window times are nested in the schedule
+	var response struct {
+		Results []struct {
+			types.MaintenanceWindow
+			Schedule struct {
+				StartTime string `json:"startTime"`
+				EndTime   string `json:"endTime"`
+			} `json:"schedule"`
+		} `json:"results"`
+	}
+	err = api.client.Get(ctx, endpoint, &response)
+	if err != nil {
+		api.logger.Error("Failed to get maintenance windows for resource %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get maintenance windows for resource %s: %w", id, err)
+	}
+
+	windows := make([]types.MaintenanceWindow, 0, len(response.Results))
+	for _, result := range response.Results {
+		window := result.MaintenanceWindow
+		window.StartTime = defaultString(window.StartTime, result.Schedule.StartTime)
+		window.EndTime = defaultString(window.EndTime, result.Schedule.EndTime)
+		windows = append(windows, window)
+	}
+	api.logger.Info("Successfully retrieved %d maintenance windows for resource %s", len(windows), id)
+	return windows, nil
+}
+
 // ============================================================================
 // RESILIENCE AND ERROR HANDLING METHODS (T3.3.1-T3.3.4)
 // ============================================================================
@@ -542,13 +1350,13 @@ func (api *OpsRampResourcesAPI) retryWithBackoff(ctx context.Context, operation
 		}
 
 		// Check if error is retryable
-		if !isRetryableError(lastErr) {
+		if !httperr.IsRetryable(lastErr) {
 			api.logger.Debug("Non-retryable error for %s: %v", operation, lastErr)
 			break
 		}
 
 		// Check for rate limiting
-		if isRateLimitError(lastErr) {
+		if httperr.IsRateLimited(lastErr) {
 			api.logger.Warn("Rate limit hit for %s, waiting %v", operation, api.config.RateLimitDelay)
 			select {
 			case <-time.After(api.config.RateLimitDelay):
@@ -561,107 +1369,36 @@ func (api *OpsRampResourcesAPI) retryWithBackoff(ctx context.Context, operation
 	return fmt.Errorf("operation %s failed after %d attempts: %w", operation, api.config.RetryAttempts, lastErr)
 }
 
-// isRetryableError determines if an error is retryable
-func isRetryableError(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	// Check for common retryable error patterns
-	errStr := err.Error()
-	retryablePatterns := []string{
-		"timeout",
-		"connection refused",
-		"connection reset",
-		"temporary failure",
-		"server unavailable",
-		"502", "503", "504", // HTTP status codes
-	}
-
-	for _, pattern := range retryablePatterns {
-		if containsSubstring(errStr, pattern) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// isRateLimitError determines if an error is due to rate limiting
-func isRateLimitError(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	errStr := err.Error()
-	rateLimitPatterns := []string{
-		"rate limit",
-		"too many requests",
-		"429", // HTTP status code
-	}
-
-	for _, pattern := range rateLimitPatterns {
-		if containsSubstring(errStr, pattern) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// containsSubstring checks if a string contains a substring (case-insensitive)
-func containsSubstring(str, substr string) bool {
-	return len(str) >= len(substr) &&
-		(str == substr ||
-			str[:len(substr)] == substr ||
-			str[len(str)-len(substr):] == substr ||
-			hasSubstring(str, substr))
-}
-
-// hasSubstring checks if a string contains a substring
-func hasSubstring(str, substr string) bool {
-	for i := 0; i <= len(str)-len(substr); i++ {
-		if str[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
-}
-
-// classifyError classifies errors into ResourceErrorType
+// classifyError classifies errors into ResourceErrorType by HTTP status code
+// or transport failure
 func (api *OpsRampResourcesAPI) classifyError(err error) *types.ResourceError {
 	if err == nil {
 		return nil
 	}
 
-	errStr := err.Error()
-
-	// Check for specific error types
-	if containsSubstring(errStr, "not found") || containsSubstring(errStr, "404") {
-		return types.NewResourceError(types.ResourceErrorTypeNotFound, "RESOURCE_NOT_FOUND", err.Error())
+	var resourceErr *types.ResourceError
+	if errors.As(err, &resourceErr) {
+		return resourceErr
 	}
-
-	if containsSubstring(errStr, "unauthorized") || containsSubstring(errStr, "401") {
-		return types.NewResourceError(types.ResourceErrorTypePermission, "UNAUTHORIZED", err.Error())
+	var invalidID *client.InvalidIDError
+	if errors.As(err, &invalidID) {
+		return types.NewResourceError(types.ResourceErrorTypeValidation, "VALIDATION_ERROR", err.Error())
 	}
 
-	if containsSubstring(errStr, "forbidden") || containsSubstring(errStr, "403") {
+	switch httperr.Classify(err) {
+	case httperr.KindNotFound:
+		return types.NewResourceError(types.ResourceErrorTypeNotFound, "RESOURCE_NOT_FOUND", err.Error())
+	case httperr.KindUnauthorized:
+		return types.NewResourceError(types.ResourceErrorTypePermission, "UNAUTHORIZED", err.Error())
+	case httperr.KindForbidden:
 		return types.NewResourceError(types.ResourceErrorTypePermission, "FORBIDDEN", err.Error())
-	}
-
-	if isRateLimitError(err) {
+	case httperr.KindRateLimited:
 		return types.NewResourceError(types.ResourceErrorTypeRateLimit, "RATE_LIMIT_EXCEEDED", err.Error())
-	}
-
-	if containsSubstring(errStr, "timeout") {
+	case httperr.KindTimeout:
 		return types.NewResourceError(types.ResourceErrorTypeTimeout, "REQUEST_TIMEOUT", err.Error())
-	}
-
-	if containsSubstring(errStr, "conflict") || containsSubstring(errStr, "409") {
+	case httperr.KindConflict:
 		return types.NewResourceError(types.ResourceErrorTypeConflict, "RESOURCE_CONFLICT", err.Error())
-	}
-
-	if containsSubstring(errStr, "validation") || containsSubstring(errStr, "invalid") || containsSubstring(errStr, "400") {
+	case httperr.KindValidation:
 		return types.NewResourceError(types.ResourceErrorTypeValidation, "VALIDATION_ERROR", err.Error())
 	}
 
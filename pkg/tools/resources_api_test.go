@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/httperr"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func apiStatusError(status int, body string) error {
+	return fmt.Errorf("failed to get resource r1: %w", &httperr.StatusError{StatusCode: status, Body: body})
+}
+
+func TestClassifyError(t *testing.T) {
+	api := &OpsRampResourcesAPI{}
+	tests := []struct {
+		name      string
+		err       error
+		errorType types.ResourceErrorType
+		code      string
+	}{
+		{"not found", apiStatusError(http.StatusNotFound, ""), types.ResourceErrorTypeNotFound, "RESOURCE_NOT_FOUND"},
+		{"unauthorized", apiStatusError(http.StatusUnauthorized, ""), types.ResourceErrorTypePermission, "UNAUTHORIZED"},
+		{"forbidden", apiStatusError(http.StatusForbidden, ""), types.ResourceErrorTypePermission, "FORBIDDEN"},
+		{"rate limited", apiStatusError(http.StatusTooManyRequests, ""), types.ResourceErrorTypeRateLimit, "RATE_LIMIT_EXCEEDED"},
+		{"gateway timeout", apiStatusError(http.StatusGatewayTimeout, ""), types.ResourceErrorTypeTimeout, "REQUEST_TIMEOUT"},
+		{"deadline", fmt.Errorf("request failed: %w", context.DeadlineExceeded), types.ResourceErrorTypeTimeout, "REQUEST_TIMEOUT"},
+		{"conflict", apiStatusError(http.StatusConflict, ""), types.ResourceErrorTypeConflict, "RESOURCE_CONFLICT"},
+		{"bad request", apiStatusError(http.StatusBadRequest, ""), types.ResourceErrorTypeValidation, "VALIDATION_ERROR"},
+		{"invalid id", &client.InvalidIDError{Kind: "resource", ID: "../x", Reason: "not a canonical ID"}, types.ResourceErrorTypeValidation, "VALIDATION_ERROR"},
+		{"server error", apiStatusError(http.StatusInternalServerError, ""), types.ResourceErrorTypeServerError, "SERVER_ERROR"},
+		{"unknown", errors.New("something went wrong"), types.ResourceErrorTypeServerError, "SERVER_ERROR"},
+		// Response bodies mentioning other failures do not change the class
+		{"misleading body", apiStatusError(http.StatusInternalServerError, "resource not found: invalid timeout 404"), types.ResourceErrorTypeServerError, "SERVER_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := api.classifyError(tt.err)
+			if got.Type != tt.errorType || got.Code != tt.code {
+				t.Errorf("classifyError = %s/%s, want %s/%s", got.Type, got.Code, tt.errorType, tt.code)
+			}
+			if got.Message != tt.err.Error() {
+				t.Errorf("Expected the original message, got %q", got.Message)
+			}
+		})
+	}
+
+	if api.classifyError(nil) != nil {
+		t.Error("Expected nil for a nil error")
+	}
+	existing := types.NewResourceError(types.ResourceErrorTypeValidation, "INVALID_PAGE_SIZE", "page size must be between 0 and 10000")
+	if got := api.classifyError(existing); got != existing {
+		t.Errorf("Expected resource errors to pass through, got %v", got)
+	}
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	api := &OpsRampResourcesAPI{
+		config: &ResourcesAPIConfig{RetryAttempts: 3, RetryDelay: time.Millisecond, RateLimitDelay: time.Millisecond},
+		logger: common.GetLogger(),
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		attempts int
+	}{
+		{"unavailable", apiStatusError(http.StatusServiceUnavailable, ""), 4},
+		{"rate limited", apiStatusError(http.StatusTooManyRequests, ""), 4},
+		{"not found", apiStatusError(http.StatusNotFound, ""), 1},
+		{"server error", apiStatusError(http.StatusInternalServerError, "gateway timeout"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := api.retryWithBackoff(context.Background(), "Get", func() error {
+				attempts++
+				return tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected the last error to be wrapped, got %v", err)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("Expected %d attempts, got %d", tt.attempts, attempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// newFakeResourcesAPI returns a fake whose calls succeed with sample results
+func newFakeResourcesAPI() *FakeResourcesAPI {
+	api := &FakeResourcesAPI{}
+	resource := &types.Resource{ID: "r1", HostName: "web-01"}
+	api.SearchReturns(&types.ResourceSearchResponse{Results: []types.Resource{*resource}, TotalResults: 1}, nil)
+	api.GetReturns(resource, nil)
+	api.GetDetailedReturns(&types.DetailedResource{}, nil)
+	api.GetMinimalReturns(&types.ResourceMinimal{ID: "r1"}, nil)
+	api.CreateReturns(resource, nil)
+	api.UpdateReturns(resource, nil)
+	api.GetResourceTypesReturns([]types.ResourceTypeInfo{{Name: "server"}}, nil)
+	api.GetMetricsReturns(&types.ResourceMetricsResponse{}, nil)
+	return api
+}
+
+func TestResourcesToolHandlerActions(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  map[string]interface{}
+		calls func(api *FakeResourcesAPI) int
+	}{
+		{"list", map[string]interface{}{"action": "list"}, (*FakeResourcesAPI).SearchCallCount},
+		{"get", map[string]interface{}{"action": "get", "id": "r1"}, (*FakeResourcesAPI).GetCallCount},
+		{"getDetailed", map[string]interface{}{"action": "getDetailed", "id": "r1"}, (*FakeResourcesAPI).GetDetailedCallCount},
+		{"getMinimal", map[string]interface{}{"action": "getMinimal", "id": "r1"}, (*FakeResourcesAPI).GetMinimalCallCount},
+		{"create", map[string]interface{}{"action": "create", "config": map[string]interface{}{"aliasName": "web"}}, (*FakeResourcesAPI).CreateCallCount},
+		{"update", map[string]interface{}{"action": "update", "id": "r1", "config": map[string]interface{}{"aliasName": "web"}}, (*FakeResourcesAPI).UpdateCallCount},
+		{"delete", map[string]interface{}{"action": "delete", "id": "r1"}, (*FakeResourcesAPI).DeleteCallCount},
+		{"bulkUpdate", map[string]interface{}{"action": "bulkUpdate", "ids": []interface{}{"r1", "r2"}, "config": map[string]interface{}{"state": "inactive"}}, (*FakeResourcesAPI).BulkUpdateCallCount},
+		{"bulkDelete", map[string]interface{}{"action": "bulkDelete", "ids": []interface{}{"r1", "r2"}}, (*FakeResourcesAPI).BulkDeleteCallCount},
+		{"search", map[string]interface{}{"action": "search", "params": map[string]interface{}{"hostName": "web"}}, (*FakeResourcesAPI).SearchCallCount},
+		{"getResourceTypes", map[string]interface{}{"action": "getResourceTypes"}, (*FakeResourcesAPI).GetResourceTypesCallCount},
+		{"getMetrics", map[string]interface{}{"action": "getMetrics", "id": "r1", "metrics": []interface{}{"cpu"}}, (*FakeResourcesAPI).GetMetricsCallCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" succeeds", func(t *testing.T) {
+			api := newFakeResourcesAPI()
+			result, err := ResourcesToolHandler(context.Background(), createTestRequest(tt.args), api)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.IsError {
+				t.Fatalf("Expected success, got %s", resultText(result))
+			}
+			if calls := tt.calls(api); calls != 1 {
+				t.Errorf("Expected one API call, got %d", calls)
+			}
+		})
+
+		t.Run(tt.name+" reports API errors", func(t *testing.T) {
+			api := newFakeResourcesAPI()
+			apiErr := errors.New("API request failed with status 503: unavailable")
+			api.SearchReturns(nil, apiErr)
+			api.GetReturns(nil, apiErr)
+			api.GetDetailedReturns(nil, apiErr)
+			api.GetMinimalReturns(nil, apiErr)
+			api.CreateReturns(nil, apiErr)
+			api.UpdateReturns(nil, apiErr)
+			api.DeleteReturns(apiErr)
+			api.BulkUpdateReturns(apiErr)
+			api.BulkDeleteReturns(apiErr)
+			api.GetResourceTypesReturns(nil, apiErr)
+			api.GetMetricsReturns(nil, apiErr)
+
+			result, err := ResourcesToolHandler(context.Background(), createTestRequest(tt.args), api)
+			if err != nil {
+				t.Fatalf("Expected errors as tool results, got %v", err)
+			}
+			if !result.IsError || resultText(result) != apiErr.Error() {
+				t.Errorf("Expected the API error, got %s", resultText(result))
+			}
+		})
+	}
+}
+
+func TestResourcesToolHandlerArguments(t *testing.T) {
+	api := newFakeResourcesAPI()
+	_, err := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "update", "id": "r1", "config": map[string]interface{}{"aliasName": "web"},
+	}), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, id, request := api.UpdateArgsForCall(0)
+	if id != "r1" || request.AliasName != "web" {
+		t.Errorf("Expected the update of r1 with its config, got %s %+v", id, request)
+	}
+
+	_, err = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "getMetrics", "id": "r1", "metrics": []interface{}{"cpu", "memory"}, "interval": "5m",
+	}), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, id, metrics := api.GetMetricsArgsForCall(0)
+	if id != "r1" || len(metrics.MetricNames) != 2 || metrics.Interval != "5m" || metrics.StartTime == "" {
+		t.Errorf("Unexpected metrics request for %s: %+v", id, metrics)
+	}
+}
+
+func TestResourcesToolHandlerValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"get without id", map[string]interface{}{"action": "get"}, "Resource ID is required for get action"},
+		{"getDetailed without id", map[string]interface{}{"action": "getDetailed"}, "Resource ID is required for getDetailed action"},
+		{"getMinimal without id", map[string]interface{}{"action": "getMinimal"}, "Resource ID is required for getMinimal action"},
+		{"create without config", map[string]interface{}{"action": "create"}, "Configuration is required for create action"},
+		{"update without id", map[string]interface{}{"action": "update", "config": map[string]interface{}{}}, "Resource ID is required for update action"},
+		{"update without config", map[string]interface{}{"action": "update", "id": "r1"}, "Configuration is required for update action"},
+		{"delete without id", map[string]interface{}{"action": "delete"}, "Resource ID is required for delete action"},
+		{"bulkUpdate without config", map[string]interface{}{"action": "bulkUpdate", "ids": []interface{}{"r1"}}, "Configuration is required for bulkUpdate action"},
+		{"bulkDelete without targets", map[string]interface{}{"action": "bulkDelete"}, "Exactly one of ids or selector is required for bulkDelete action"},
+		{"getMetrics without id", map[string]interface{}{"action": "getMetrics", "metrics": []interface{}{"cpu"}}, "Resource ID is required for getMetrics action"},
+		{"getMetrics without metrics", map[string]interface{}{"action": "getMetrics", "id": "r1"}, "At least one metric name is required for getMetrics action"},
+		{"unknown action", map[string]interface{}{"action": "explode"}, "Unknown action: explode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeResourcesAPI()
+			result, err := ResourcesToolHandler(context.Background(), createTestRequest(tt.args), api)
+			if err != nil {
+				t.Fatalf("Expected errors as tool results, got %v", err)
+			}
+			if !result.IsError || !strings.Contains(resultText(result), tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, resultText(result))
+			}
+			if api.SearchCallCount()+api.GetCallCount()+api.CreateCallCount()+api.UpdateCallCount()+api.DeleteCallCount()+api.BulkDeleteCallCount() != 0 {
+				t.Error("Expected no API calls for invalid arguments")
+			}
+		})
+	}
+}
+
+// metricInstancesResourcesAPI adds metric instances to the resources fake
+type metricInstancesResourcesAPI struct {
+	*FakeResourcesAPI
+}
+
+func (m metricInstancesResourcesAPI) GetMetricInstances(ctx context.Context, id, metric string) ([]types.MetricInstance, error) {
+	return []types.MetricInstance{{Name: "/"}, {Name: "/var", Labels: map[string]string{"fsType": "xfs"}}}, nil
+}
+
+func TestResourcesToolHandlerMetricInstances(t *testing.T) {
+	api := newFakeResourcesAPI()
+	args := map[string]interface{}{"action": "listMetricInstances", "id": "r1", "metric": "system.disk.usage"}
+	result, err := ResourcesToolHandler(context.Background(), createTestRequest(args), metricInstancesResourcesAPI{api})
+	if err != nil || result.IsError {
+		t.Fatalf("Expected metric instances, got %v", resultText(result))
+	}
+	if text := resultText(result); !strings.Contains(text, `"/var"`) || !strings.Contains(text, `"metric": "system.disk.usage"`) {
+		t.Errorf("Unexpected metric instances %s", text)
+	}
+
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(args), api)
+	if !result.IsError || !strings.Contains(resultText(result), "not supported") {
+		t.Errorf("Expected an unsupported backend error, got %s", resultText(result))
+	}
+
+	api.GetMetricsReturns(&types.ResourceMetricsResponse{ResourceID: "r1", Metrics: []types.ResourceMetricDataPoint{
+		{Name: "system.disk.usage", Instance: "/", Value: 40},
+		{Name: "system.disk.usage", Instance: "/var", Value: 90},
+	}}, nil)
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "getMetrics", "id": "r1", "metrics": []interface{}{"system.disk.usage"}, "instance": "/var",
+	}), api)
+	if text := resultText(result); result.IsError || strings.Contains(text, `"instance": "/"`) || !strings.Contains(text, `"instance": "/var"`) {
+		t.Errorf("Expected only the /var instance, got %s", text)
+	}
+	if _, _, request := api.GetMetricsArgsForCall(0); request.Instance != "/var" {
+		t.Errorf("Expected the instance to be requested, got %+v", request)
+	}
+}
+
+func TestResourcesToolHandlerWatch(t *testing.T) {
+	api := newFakeResourcesAPI()
+	ctx := contextWithSession("resources-watch")
+	defer session.Remove("resources-watch")
+
+	result, err := ResourcesToolHandler(ctx, createTestRequest(map[string]interface{}{"action": "watch", "id": "r1"}), api)
+	if err != nil || result.IsError {
+		t.Fatalf("Expected watch to succeed, got %v", resultText(result))
+	}
+	if watchers := session.Watchers("resource", "r1"); len(watchers) != 1 || watchers[0] != "resources-watch" {
+		t.Errorf("Expected the session to watch r1, got %v", watchers)
+	}
+
+	result, _ = ResourcesToolHandler(ctx, createTestRequest(map[string]interface{}{"action": "listWatches"}), api)
+	if !strings.Contains(resultText(result), `"r1"`) {
+		t.Errorf("Expected listWatches to include r1, got %s", resultText(result))
+	}
+
+	result, _ = ResourcesToolHandler(ctx, createTestRequest(map[string]interface{}{"action": "unwatch", "id": "r1"}), api)
+	if result.IsError || len(session.Watchers("resource", "r1")) != 0 {
+		t.Errorf("Expected unwatch to remove the subscription, got %s", resultText(result))
+	}
+	result, _ = ResourcesToolHandler(ctx, createTestRequest(map[string]interface{}{"action": "unwatch", "id": "r1"}), api)
+	if !result.IsError {
+		t.Error("Expected unwatching an unwatched resource to fail")
+	}
+
+	// Watching needs a session to notify
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{"action": "watch", "id": "r1"}), api)
+	if !result.IsError || !strings.Contains(resultText(result), "requires an MCP session") {
+		t.Errorf("Expected a session error, got %s", resultText(result))
+	}
+}
@@ -2,13 +2,17 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/opsramp/or-mcp-v2/common"
 	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
 )
 
 // setupTestEnvironment sets up the testing environment with proper logging and client
@@ -244,6 +248,31 @@ func TestResourcesTool_InvalidAction(t *testing.T) {
 	logger.Info("Resources invalid action test completed")
 }
 
+func TestResourcesTool_ChangeState(t *testing.T) {
+	api := &FakeResourcesAPI{}
+	res, err := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "changeState", "id": "r-1", "state": " unmanaged ",
+	}), api)
+	if err != nil || res.IsError {
+		t.Fatalf("changeState failed: %v %s", err, resultText(res))
+	}
+	if _, id, request := api.ChangeStateArgsForCall(0); id != "r-1" || request.State != "unmanaged" {
+		t.Errorf("Expected r-1 to be moved to unmanaged, got %s %+v", id, request)
+	}
+	var change types.ResourceStateChange
+	if err := json.Unmarshal([]byte(resultText(res)), &change); err != nil || change.State != "unmanaged" {
+		t.Errorf("Expected the new state to be reported, got %s", resultText(res))
+	}
+
+	api.ChangeStateReturns(errors.New("state transition not allowed"))
+	res, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "changeState", "id": "r-1", "state": "managed",
+	}), api)
+	if !res.IsError || !strings.Contains(resultText(res), "not allowed") {
+		t.Errorf("Expected the API error to be reported, got %s", resultText(res))
+	}
+}
+
 func TestResourcesTool_MissingRequiredParameters(t *testing.T) {
 	logger, cleanup := setupTestEnvironment(t)
 	defer cleanup()
@@ -0,0 +1,250 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/transform"
+)
+
+// sessionCompactKey is the session key holding the sticky llmCompact preference
+const sessionCompactKey = "llmCompact"
+
+// llmCompactArgument is the tool input schema entry for the llmCompact option
+var llmCompactArgument = map[string]interface{}{
+	"type":        "boolean",
+	"description": "Prune verbose fields (consoles, properties, nulls, empty values) and shorten timestamps to save tokens. Sticky for the rest of the session once set.",
+}
+
+// transformArgument is the tool input schema entry for server-side result transforms
+var transformArgument = map[string]interface{}{
+	"type":        "string",
+	"description": "Optional jq-like expression applied to the result before it is returned, e.g. `.results[].hostName` or `.results[] | select(.state == \"active\") | {id, hostName}`",
+}
+
+// ResponseOptions controls how a tool result is rendered for the client
+type ResponseOptions struct {
+	Compact   bool
+	Transform string
+	// Render, when set to table, adds a Markdown table of the result
+	Render  string
+	Columns []string
+	MaxRows int
+}
+
+var (
+	responseDefaults     common.ResponsesConfig
+	responseDefaultsOnce sync.Once
+)
+
+// getResponseDefaults returns the configured response defaults, loading them on first use
+func getResponseDefaults() common.ResponsesConfig {
+	responseDefaultsOnce.Do(func() {
+		if config, err := common.LoadConfig(""); err == nil {
+			responseDefaults = config.Responses
+			return
+		}
+		responseDefaults.LLMCompact = common.GetEnvOrDefault("LLM_COMPACT", "false") == "true"
+		responseDefaults.Locale = common.GetEnvOrDefault("RESPONSE_LOCALE", "")
+		if deny := common.SplitFieldList(common.GetEnvOrDefault("RESPONSE_DENY_FIELDS", "")); len(deny) > 0 {
+			responseDefaults.Fields = map[string]common.FieldPolicy{"*": {Deny: deny}}
+		}
+	})
+	return responseDefaults
+}
+
+// responseOptionsFromRequest resolves the response options for a tool call.
+// An explicit llmCompact argument wins and is remembered for the session;
+// otherwise the session preference applies, then the configured default.
+func responseOptionsFromRequest(ctx context.Context, req mcp.CallToolRequest) ResponseOptions {
+	opts := ResponseOptions{
+		Compact:   getResponseDefaults().LLMCompact,
+		Transform: req.GetString("transform", ""),
+		Render:    req.GetString("render", ""),
+		Columns:   req.GetStringSlice("columns", nil),
+		MaxRows:   req.GetInt("maxRows", 0),
+	}
+	sess := session.FromContext(ctx)
+
+	if value, ok := req.GetArguments()["llmCompact"].(bool); ok {
+		opts.Compact = value
+		if sess != nil {
+			sess.Set(sessionCompactKey, value)
+		}
+		return opts
+	}
+
+	if sess != nil {
+		if value, ok := sess.Get(sessionCompactKey); ok {
+			opts.Compact = value.(bool)
+		}
+	}
+	return opts
+}
+
+// formatResult renders a tool result as JSON text according to the response options.
+// The transform expression, if any, is applied before compaction.
+func formatResult(result interface{}, opts ResponseOptions) (string, error) {
+	result, err := applyTransform(result, opts.Transform)
+	if err != nil {
+		return "", err
+	}
+	return renderResult(result, opts.Compact)
+}
+
+// applyTransform applies a transform expression to a tool result
+func applyTransform(result interface{}, expression string) (interface{}, error) {
+	if expression == "" {
+		return result, nil
+	}
+	transformed, err := transform.Apply(expression, result)
+	if err != nil {
+		return nil, &transformError{err: err}
+	}
+	return transformed, nil
+}
+
+// renderResult marshals a tool result, pruning it first in compact mode
+func renderResult(result interface{}, compact bool) (string, error) {
+	if !compact {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal result: %w", err)
+		}
+		return string(data), nil
+	}
+
+	// Round-trip through JSON so pruning works on any result type
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal result: %w", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return "", fmt.Errorf("failed to decode result: %w", err)
+	}
+
+	pruned, _ := compactValue(generic)
+	data, err = json.Marshal(pruned)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal compact result: %w", err)
+	}
+	return string(data), nil
+}
+
+// transformError reports a transform expression that failed to compile or evaluate
+type transformError struct {
+	err error
+}
+
+// Error implements the error interface
+func (e *transformError) Error() string {
+	return fmt.Sprintf("transform failed: %v", e.err)
+}
+
+// Unwrap returns the underlying transform error
+func (e *transformError) Unwrap() error {
+	return e.err
+}
+
+// respondJSON builds the tool result for a successful call. An invalid
+// transform expression is reported to the client as a tool error. Within a
+// session the returned value is also kept under a short-lived result handle
+// that follow-up utility actions (diff, transform, export, nextPage) can
+// reference; results above the handle threshold are returned only as the
+// handle plus a summary.
+func respondJSON(ctx context.Context, req mcp.CallToolRequest, result interface{}) (*mcp.CallToolResult, error) {
+	return respondResult(ctx, req, result, true)
+}
+
+// respondResult renders a successful result, keeping it under a result
+// handle only when keepHandle is set. Pages read from a stored result are
+// returned inline without a handle of their own. The rendered result is also
+// returned as structured content for clients that consume typed results.
+// Fields the tool's field policy does not permit are stripped first, so
+// neither transforms nor result handles can reach them. Text fields longer
+// than responses.max_text_length are truncated; the handle then keeps the
+// full result for the utility getFull action.
+func respondResult(ctx context.Context, req mcp.CallToolRequest, result interface{}, keepHandle bool) (*mcp.CallToolResult, error) {
+	// A call made for a multi-tenant fan-out is rendered with the merged results
+	if captureFanOutResult(ctx, result) {
+		return &mcp.CallToolResult{}, nil
+	}
+	opts := responseOptionsFromRequest(ctx, req)
+
+	result, err := applyFieldPolicy(result, fieldPolicyFor(req.Params.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := applyTransform(result, opts.Transform)
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+
+	// Long text fields are cut short; the full result stays under a handle
+	sessionID := session.IDFromContext(ctx)
+	handle := ""
+	if limit := maxTextLength(); limit > 0 {
+		if value, handle, err = truncateResult(sessionID, req.Params.Name, value, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	text, err := renderResult(value, opts.Compact)
+	if err != nil {
+		return nil, err
+	}
+
+	// The table is an extra block for display; the JSON stays the result
+	var extra []mcp.Content
+	switch opts.Render {
+	case "":
+	case renderTable:
+		table, err := markdownTable(value, opts.Columns, opts.MaxRows)
+		if err != nil {
+			return nil, err
+		}
+		if table != "" {
+			extra = append(extra, mcp.TextContent{Type: "text", Text: table})
+		}
+	default:
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Unsupported render %q: the only supported value is %q", opts.Render, renderTable)}},
+		}, nil
+	}
+
+	toolResult := &mcp.CallToolResult{
+		Content:           append([]mcp.Content{mcp.TextContent{Type: "text", Text: text}}, extra...),
+		StructuredContent: structuredContent(text, opts.Transform != ""),
+	}
+	if handle == "" {
+		if !keepHandle || sessionID == "" || len(text) > maxHandleBytes {
+			return toolResult, nil
+		}
+		handle = storeResultHandle(sessionID, req.Params.Name, value, len(text))
+	}
+	if len(text) > handleThreshold() {
+		summary, err := renderResult(summarizeResultHandle(handle, req.Params.Name, value, len(text)), opts.Compact)
+		if err != nil {
+			return nil, err
+		}
+		toolResult.Content = append([]mcp.Content{mcp.TextContent{Type: "text", Text: summary}}, extra...)
+		toolResult.StructuredContent = structuredContent(summary, false)
+		return toolResult, nil
+	}
+	toolResult.Content = append(toolResult.Content, mcp.TextContent{
+		Type: "text",
+		Text: fmt.Sprintf("resultHandle: %s (expires in %s)", handle, resultHandleTTL),
+	})
+	return toolResult, nil
+}
@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// testClientSession is a minimal MCP client session for exercising per-session state
+type testClientSession struct {
+	id string
+}
+
+func (s *testClientSession) Initialize()       {}
+func (s *testClientSession) Initialized() bool { return true }
+func (s *testClientSession) SessionID() string { return s.id }
+func (s *testClientSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return make(chan mcp.JSONRPCNotification, 1)
+}
+
+// contextWithSession returns a context bound to a test client session
+func contextWithSession(id string) context.Context {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	return mcpServer.WithContext(context.Background(), &testClientSession{id: id})
+}
+
+func TestFormatResultCompact(t *testing.T) {
+	result := map[string]interface{}{
+		"id":          "r-1",
+		"hostName":    "web-01",
+		"description": "",
+		"consoles":    []string{"ssh://web-01"},
+		"properties":  map[string]interface{}{"blob": strings.Repeat("x", 64)},
+		"agent":       nil,
+		"tags":        []interface{}{},
+		"createdDate": "2024-03-05T10:21:32+0000",
+	}
+
+	text, err := formatResult(result, ResponseOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("formatResult failed: %v", err)
+	}
+
+	var compact map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &compact); err != nil {
+		t.Fatalf("Compact output is not valid JSON: %v", err)
+	}
+
+	for _, dropped := range []string{"description", "consoles", "properties", "agent", "tags"} {
+		if _, ok := compact[dropped]; ok {
+			t.Errorf("Expected %s to be pruned, got %v", dropped, compact[dropped])
+		}
+	}
+	if compact["hostName"] != "web-01" || compact["id"] != "r-1" {
+		t.Errorf("Expected identifying fields to be kept, got %v", compact)
+	}
+	if compact["createdDate"] != "2024-03-05T10:21Z" {
+		t.Errorf("Expected shortened timestamp, got %v", compact["createdDate"])
+	}
+
+	full, _ := formatResult(result, ResponseOptions{})
+	if len(text)*2 > len(full) {
+		t.Errorf("Expected compact output to be less than half the size: %d vs %d bytes", len(text), len(full))
+	}
+}
+
+func TestLLMCompactIsStickyPerSession(t *testing.T) {
+	ctx := contextWithSession("compact-session")
+
+	opts := responseOptionsFromRequest(ctx, createTestRequest(map[string]interface{}{"action": "list", "llmCompact": true}))
+	if !opts.Compact {
+		t.Fatal("Expected explicit llmCompact to enable compact mode")
+	}
+
+	opts = responseOptionsFromRequest(ctx, createTestRequest(map[string]interface{}{"action": "list"}))
+	if !opts.Compact {
+		t.Error("Expected compact mode to persist for the session")
+	}
+
+	other := contextWithSession("other-session")
+	opts = responseOptionsFromRequest(other, createTestRequest(map[string]interface{}{"action": "list"}))
+	if opts.Compact {
+		t.Error("Expected other sessions to be unaffected")
+	}
+}
+
+func TestRespondJSONTransform(t *testing.T) {
+	result := map[string]interface{}{
+		"results": []map[string]interface{}{
+			{"id": "r1", "hostName": "web-01"},
+			{"id": "r2", "hostName": "web-02"},
+		},
+	}
+
+	res, err := respondJSON(context.Background(), createTestRequest(map[string]interface{}{"transform": ".results[].hostName"}), result)
+	if err != nil || res.IsError {
+		t.Fatalf("Expected transformed result, got %v / %v", res, err)
+	}
+	var hosts []string
+	if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &hosts); err != nil {
+		t.Fatalf("Transformed output is not a JSON array: %v", err)
+	}
+	if len(hosts) != 2 || hosts[0] != "web-01" {
+		t.Errorf("Expected host names, got %v", hosts)
+	}
+
+	res, err = respondJSON(context.Background(), createTestRequest(map[string]interface{}{"transform": ".results["}), result)
+	if err != nil || !res.IsError {
+		t.Errorf("Expected tool error for invalid transform, got %v / %v", res, err)
+	}
+}
+
+func TestRespondJSONRenderTable(t *testing.T) {
+	result := map[string]interface{}{
+		"totalResults": 3,
+		"results": []map[string]interface{}{
+			{"id": "r1", "hostName": "web|01", "state": "active", "tags": []string{"prod"}, "location": map[string]interface{}{"name": "dc1"}},
+			{"id": "r2", "hostName": "web-02", "state": "inactive"},
+			{"id": "r3", "hostName": "web-03"},
+		},
+	}
+
+	res, err := respondJSON(context.Background(), createTestRequest(map[string]interface{}{"render": "table", "maxRows": 2}), result)
+	if err != nil || res.IsError || len(res.Content) != 2 {
+		t.Fatalf("Expected the JSON and a table, got %v / %v", res, err)
+	}
+	table := res.Content[1].(mcp.TextContent).Text
+	want := "| id | hostName | state |\n|---|---|---|\n| r1 | web\\|01 | active |\n| r2 | web-02 | inactive |\n\n_Showing 2 of 3 rows._\n"
+	if table != want {
+		t.Errorf("Expected default columns with the identifying fields first, got:\n%s", table)
+	}
+	if !strings.Contains(res.Content[0].(mcp.TextContent).Text, `"totalResults": 3`) {
+		t.Error("Expected the JSON result to be unchanged")
+	}
+
+	res, _ = respondJSON(context.Background(), createTestRequest(map[string]interface{}{
+		"render": "table", "columns": []interface{}{"hostName", "location.name", "tags"},
+	}), result)
+	table = res.Content[1].(mcp.TextContent).Text
+	if !strings.HasPrefix(table, "| hostName | location.name | tags |") || !strings.Contains(table, "| web\\|01 | dc1 | [\"prod\"] |") {
+		t.Errorf("Expected the selected columns, got:\n%s", table)
+	}
+
+	res, err = respondJSON(context.Background(), createTestRequest(map[string]interface{}{"render": "csv"}), result)
+	if err != nil || !res.IsError {
+		t.Errorf("Expected tool error for an unsupported render, got %v / %v", res, err)
+	}
+}
@@ -0,0 +1,26 @@
+package tools
+
+import (
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+)
+
+// toolScopes declares the OpsRamp scope each tool's endpoints must be called in.
+// Tools not listed here operate on the client tenant.
+var toolScopes = map[string]client.Scope{
+	"clients": client.ScopePartner,
+}
+
+// ToolScope returns the OpsRamp scope declared by the named tool
+func ToolScope(toolName string) client.Scope {
+	if scope, ok := toolScopes[toolName]; ok {
+		return scope
+	}
+	return client.ScopeClient
+}
+
+// requireToolScope verifies that c can serve the scope the named tool declares,
+// so partner-scoped tools fail with a configuration error rather than a 403
+func requireToolScope(c *client.OpsRampClient, toolName string) error {
+	_, err := c.ScopeID(ToolScope(toolName))
+	return err
+}
@@ -0,0 +1,222 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// scorecardWindow is the utilization window summarized in a scorecard
+const scorecardWindow = 24 * time.Hour
+
+// scorecardResourceSection is the resource details section of a scorecard,
+// without which there is no scorecard
+const scorecardResourceSection = "resource"
+
+// scorecardMetrics maps scorecard utilization keys to OpsRamp metric names
+var scorecardMetrics = map[string]string{
+	"cpu":    "system.cpu.utilization",
+	"memory": "system.memory.utilization",
+	"disk":   "system.disk.utilization",
+}
+
+// PatchProvider is implemented by resources APIs that can report patch status
+type PatchProvider interface {
+	GetPatches(ctx context.Context, id string) ([]types.ResourcePatch, error)
+}
+
+// ScorecardBuilder assembles resource health scorecards from the resource,
+// metric, alert and patch APIs
+type ScorecardBuilder struct {
+	resources ResourcesAPI
+	alerts    AlertsAPI
+	patches   PatchProvider
+	locale    *Locale
+	now       func() time.Time
+	logger    *common.CustomLogger
+}
+
+// NewScorecardBuilder creates a ScorecardBuilder. Alert and patch sections are
+// reported as unavailable when the resources API does not provide them.
+func NewScorecardBuilder(resources ResourcesAPI) *ScorecardBuilder {
+	b := &ScorecardBuilder{
+		resources: resources,
+		locale:    locales[defaultLocale],
+		now:       time.Now,
+		logger:    common.GetLogger(),
+	}
+	if provider, ok := resources.(AlertsProvider); ok {
+		b.alerts = provider.Alerts()
+	}
+	if provider, ok := resources.(PatchProvider); ok {
+		b.patches = provider
+	}
+	return b
+}
+
+// Build fetches every scorecard section concurrently. A failing section is
+// recorded in Unavailable rather than failing the scorecard; only a failure to
+// find the resource itself is an error.
+func (b *ScorecardBuilder) Build(ctx context.Context, resourceID string) (*types.ResourceScorecard, error) {
+	now := b.now().UTC()
+	scorecard := &types.ResourceScorecard{
+		ResourceID:  resourceID,
+		GeneratedAt: now.Format(time.RFC3339),
+	}
+
+	var detailErr error
+	failed := fetchSections(ctx, b.logger, "Scorecard for "+resourceID, map[string]sectionFetch{
+		scorecardResourceSection: func(ctx context.Context) error {
+			resource, err := b.resources.GetDetailed(ctx, resourceID)
+			if err != nil {
+				detailErr = err
+				return err
+			}
+			scorecard.ResourceName = resource.Name
+			scorecard.Availability = &types.AvailabilitySummary{
+				Status: defaultString(resource.AvailabilityStatus, "UNKNOWN"),
+				Since:  resource.UpDownSince,
+			}
+			scorecard.Agent = &types.AgentSummary{
+				Installed:     resource.AgentInstalled,
+				Status:        resource.AgentStatus,
+				LastConnected: resource.AgentLastConnectedTime,
+			}
+			return nil
+		},
+		"utilization": func(ctx context.Context) (err error) {
+			scorecard.Utilization, err = b.utilization(ctx, resourceID, now)
+			return err
+		},
+		"openAlerts": func(ctx context.Context) (err error) {
+			scorecard.OpenAlerts, err = b.openAlerts(ctx, resourceID)
+			return err
+		},
+		"patchCompliance": func(ctx context.Context) (err error) {
+			scorecard.PatchCompliance, err = b.patchCompliance(ctx, resourceID)
+			return err
+		},
+	})
+
+	if detailErr != nil {
+		return nil, fmt.Errorf("failed to get resource %s: %w", resourceID, detailErr)
+	}
+	scorecard.Unavailable = failed
+	scorecard.Summary = scorecardSummary(scorecard, b.locale)
+	return scorecard, nil
+}
+
+// utilization summarizes the last 24h of CPU, memory and disk utilization
+func (b *ScorecardBuilder) utilization(ctx context.Context, resourceID string, now time.Time) (map[string]types.MetricSummary, error) {
+	names := make([]string, 0, len(scorecardMetrics))
+	keys := make(map[string]string, len(scorecardMetrics))
+	for key, name := range scorecardMetrics {
+		names = append(names, name)
+		keys[name] = key
+	}
+
+	response, err := b.resources.GetMetrics(ctx, resourceID, types.ResourceMetricsRequest{
+		MetricNames: names,
+		StartTime:   now.Add(-scorecardWindow).Format(time.RFC3339),
+		EndTime:     now.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Data points are assumed to be in time order, so the last one is the latest
+	summaries := make(map[string]types.MetricSummary)
+	for _, point := range response.Metrics {
+		key, ok := keys[point.Name]
+		if !ok {
+			continue
+		}
+		summary, seen := summaries[key]
+		if !seen {
+			summary = types.MetricSummary{Metric: point.Name, Unit: point.Unit, Min: point.Value, Max: point.Value}
+		}
+		summary.Min = min(summary.Min, point.Value)
+		summary.Max = max(summary.Max, point.Value)
+		summary.Avg = (summary.Avg*float64(summary.Samples) + point.Value) / float64(summary.Samples+1)
+		summary.Latest = point.Value
+		summary.Samples++
+		summaries[key] = summary
+	}
+	return summaries, nil
+}
+
+// openAlerts counts the resource's open alerts by severity
+func (b *ScorecardBuilder) openAlerts(ctx context.Context, resourceID string) (*types.AlertCounts, error) {
+	if b.alerts == nil {
+		return nil, fmt.Errorf("alerts are not supported by this resources backend")
+	}
+
+	response, err := b.alerts.Search(ctx, types.AlertSearchParams{
+		QueryString: openAlertsQuery([]string{resourceID}),
+		PageSize:    500,
+		PageNo:      1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := &types.AlertCounts{BySeverity: map[string]int{}}
+	for _, alert := range response.Results {
+		counts.BySeverity[strings.ToUpper(alert.CurrentState)]++
+		counts.Total++
+	}
+	return counts, nil
+}
+
+// patchCompliance summarizes installed and missing patches
+func (b *ScorecardBuilder) patchCompliance(ctx context.Context, resourceID string) (*types.PatchCompliance, error) {
+	if b.patches == nil {
+		return nil, fmt.Errorf("patch status is not supported by this resources backend")
+	}
+
+	patches, err := b.patches.GetPatches(ctx, resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	compliance := &types.PatchCompliance{}
+	for _, patch := range patches {
+		if strings.EqualFold(patch.Status, "installed") {
+			compliance.Installed++
+			continue
+		}
+		compliance.Missing++
+		if strings.EqualFold(patch.Severity, "critical") {
+			compliance.MissingCritical++
+		}
+	}
+	compliance.Compliant = compliance.MissingCritical == 0
+	return compliance, nil
+}
+
+// scorecardSummary renders a one-line summary of a scorecard in the given locale
+func scorecardSummary(s *types.ResourceScorecard, l *Locale) string {
+	parts := []string{l.T("scorecard.status", displayName(s.ResourceName, s.ResourceID), s.Availability.Status)}
+	for _, key := range []string{"cpu", "memory", "disk"} {
+		if summary, ok := s.Utilization[key]; ok {
+			parts = append(parts, l.T("scorecard.metric", l.T("metric."+key), l.Decimal(summary.Avg), l.Decimal(summary.Max)))
+		}
+	}
+	if s.OpenAlerts != nil {
+		parts = append(parts, l.T("scorecard.alerts", s.OpenAlerts.Total, defaultString(highestSeverityOf(s.OpenAlerts.BySeverity), l.T("none"))))
+	}
+	if s.PatchCompliance != nil {
+		parts = append(parts, l.T("scorecard.patches", s.PatchCompliance.Missing, s.PatchCompliance.MissingCritical))
+	}
+	if s.Agent != nil && !s.Agent.Installed {
+		parts = append(parts, l.T("scorecard.noAgent"))
+	}
+	if since, err := time.Parse(time.RFC3339, s.Availability.Since); err == nil {
+		parts = append(parts, l.T("scorecard.since", l.Date(since)))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// scorecardResourcesAPI serves fixed details, metrics, alerts and patches;
+// unused ResourcesAPI methods panic
+type scorecardResourcesAPI struct {
+	ResourcesAPI
+	alerts     *mockAlertsAPI
+	patches    []types.ResourcePatch
+	patchErr   error
+	metricsReq types.ResourceMetricsRequest
+}
+
+func (m *scorecardResourcesAPI) GetDetailed(ctx context.Context, id string) (*types.DetailedResource, error) {
+	if id == "missing" {
+		return nil, fmt.Errorf("resource not found")
+	}
+	resource := &types.DetailedResource{AvailabilityStatus: "UP", UpDownSince: "2026-10-01T00:00:00Z"}
+	resource.Name = "web-01"
+	resource.AgentInstalled = true
+	resource.AgentStatus = "CONNECTED"
+	return resource, nil
+}
+
+func (m *scorecardResourcesAPI) GetMetrics(ctx context.Context, id string, req types.ResourceMetricsRequest) (*types.ResourceMetricsResponse, error) {
+	m.metricsReq = req
+	return &types.ResourceMetricsResponse{ResourceID: id, Metrics: []types.ResourceMetricDataPoint{
+		{Name: "system.cpu.utilization", Value: 20, Unit: "%"},
+		{Name: "system.cpu.utilization", Value: 60, Unit: "%"},
+		{Name: "system.cpu.utilization", Value: 40, Unit: "%"},
+		{Name: "system.memory.utilization", Value: 75, Unit: "%"},
+		{Name: "system.network.in", Value: 1000},
+	}}, nil
+}
+
+func (m *scorecardResourcesAPI) Alerts() AlertsAPI {
+	return m.alerts
+}
+
+func (m *scorecardResourcesAPI) GetPatches(ctx context.Context, id string) ([]types.ResourcePatch, error) {
+	return m.patches, m.patchErr
+}
+
+func newTestScorecardBuilder(api ResourcesAPI) *ScorecardBuilder {
+	b := NewScorecardBuilder(api)
+	b.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	return b
+}
+
+func TestScorecard(t *testing.T) {
+	api := &scorecardResourcesAPI{
+		alerts: &mockAlertsAPI{open: []types.AlertDetails{
+			{ID: "a1", CurrentState: "critical"},
+			{ID: "a2", CurrentState: "WARNING"},
+			{ID: "a3", CurrentState: "WARNING"},
+		}},
+		patches: []types.ResourcePatch{
+			{ID: "p1", Severity: "Critical", Status: "Missing"},
+			{ID: "p2", Severity: "Low", Status: "Missing"},
+			{ID: "p3", Severity: "Critical", Status: "Installed"},
+		},
+	}
+
+	scorecard, err := newTestScorecardBuilder(api).Build(context.Background(), "r1")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if api.metricsReq.StartTime != "2026-10-15T12:00:00Z" || api.metricsReq.EndTime != "2026-10-16T12:00:00Z" {
+		t.Errorf("Expected a 24h metrics window, got %s..%s", api.metricsReq.StartTime, api.metricsReq.EndTime)
+	}
+	if scorecard.ResourceName != "web-01" || scorecard.Availability.Status != "UP" || !scorecard.Agent.Installed {
+		t.Errorf("Unexpected resource sections: %+v", scorecard)
+	}
+
+	cpu := scorecard.Utilization["cpu"]
+	if cpu.Min != 20 || cpu.Max != 60 || cpu.Avg != 40 || cpu.Latest != 40 || cpu.Samples != 3 {
+		t.Errorf("Unexpected CPU summary: %+v", cpu)
+	}
+	if _, ok := scorecard.Utilization["disk"]; ok || len(scorecard.Utilization) != 2 {
+		t.Errorf("Expected cpu and memory only, got %v", scorecard.Utilization)
+	}
+
+	if scorecard.OpenAlerts.Total != 3 || scorecard.OpenAlerts.BySeverity["CRITICAL"] != 1 || scorecard.OpenAlerts.BySeverity["WARNING"] != 2 {
+		t.Errorf("Unexpected alert counts: %+v", scorecard.OpenAlerts)
+	}
+	if pc := scorecard.PatchCompliance; pc.Compliant || pc.Installed != 1 || pc.Missing != 2 || pc.MissingCritical != 1 {
+		t.Errorf("Unexpected patch compliance: %+v", pc)
+	}
+	if scorecard.Unavailable != nil {
+		t.Errorf("Expected every section, got unavailable %v", scorecard.Unavailable)
+	}
+	if !strings.HasPrefix(scorecard.Summary, "web-01 is UP; cpu avg 40.0%") {
+		t.Errorf("Unexpected summary: %s", scorecard.Summary)
+	}
+}
+
+func TestScorecardPartialFailure(t *testing.T) {
+	api := &scorecardResourcesAPI{alerts: &mockAlertsAPI{}, patchErr: fmt.Errorf("patch service down")}
+
+	scorecard, err := newTestScorecardBuilder(api).Build(context.Background(), "r1")
+	if err != nil {
+		t.Fatalf("A failing section must not fail the scorecard: %v", err)
+	}
+	if scorecard.PatchCompliance != nil || scorecard.Unavailable["patchCompliance"] != "patch service down" {
+		t.Errorf("Expected patch compliance to be unavailable, got %+v", scorecard)
+	}
+	if scorecard.OpenAlerts == nil || scorecard.OpenAlerts.Total != 0 {
+		t.Errorf("Expected zero open alerts, got %+v", scorecard.OpenAlerts)
+	}
+
+	if _, err := newTestScorecardBuilder(api).Build(context.Background(), "missing"); err == nil {
+		t.Error("Expected an error for a missing resource")
+	}
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// maxAllPagesResources bounds the resources a search with allPages returns
+	maxAllPagesResources = 10000
+	// allPagesPageSize is the page size of a search with allPages
+	allPagesPageSize = 100
+)
+
+// AllPagesSearcher is implemented by resources APIs that can fetch every
+// page of a search themselves
+type AllPagesSearcher interface {
+	SearchAll(ctx context.Context, params types.ResourceSearchParams, limit int) (*types.ResourceSearchResponse, error)
+}
+
+// searchAllPages runs a search over every page from params.PageNo, returning
+// at most maxAllPagesResources resources. APIs without SearchAll are walked
+// with SearchResourcesIter.
+func searchAllPages(ctx context.Context, api ResourcesAPI, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+	if params.PageSize <= 0 {
+		params.PageSize = allPagesPageSize
+	}
+	if searcher, ok := api.(AllPagesSearcher); ok {
+		return searcher.SearchAll(ctx, params, maxAllPagesResources)
+	}
+
+	response := &types.ResourceSearchResponse{Results: []types.Resource{}, PageNo: max(params.PageNo, 1), PageSize: params.PageSize}
+	hasMore := false
+	it := SearchResourcesIter(ctx, api, params)
+	for it.Next() {
+		if len(response.Results) == maxAllPagesResources {
+			hasMore = true
+			break
+		}
+		response.Results = append(response.Results, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search resources: %w", err)
+	}
+
+	response.Pagination = allPagesInfo(response.PageNo, response.PageSize, len(response.Results), hasMore, 0)
+	response.TotalResults, response.TotalPages, response.NextPage = response.Pagination.EstimatedTotal, response.Pagination.TotalPages(), hasMore
+	return response, nil
+}
+
+// allPagesInfo is the pagination metadata of the results of every page from
+// pageNo on. total is the upstream estimate of the results, or zero when
+// unknown; it only counts while more results remain.
+func allPagesInfo(pageNo, pageSize, fetched int, hasMore bool, total int) *types.PageInfo {
+	info := &types.PageInfo{PageNo: pageNo, PageSize: pageSize, FetchedCount: fetched, HasMore: hasMore}
+	seen := (max(pageNo, 1)-1)*pageSize + fetched
+	if hasMore {
+		info.EstimatedTotal = max(total, seen+1)
+	} else {
+		info.EstimatedTotal, info.TotalExact = seen, true
+	}
+	return info
+}
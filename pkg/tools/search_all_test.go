@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestResourcesToolAllPages(t *testing.T) {
+	const total = 250
+	searches := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token":"token-1","token_type":"bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("/api/v2/tenants/client_1/resources/search", func(w http.ResponseWriter, r *http.Request) {
+		searches++
+		pageNo, _ := strconv.Atoi(r.URL.Query().Get("pageNo"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+		resources := []string{}
+		for i := (pageNo-1)*pageSize + 1; i <= min(pageNo*pageSize, total); i++ {
+			resources = append(resources, fmt.Sprintf(`{"id":"r%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"results":[%s],"pageNo":%d,"pageSize":%d,"totalResults":%d}`, strings.Join(resources, ","), pageNo, pageSize, total)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	api := NewOpsRampResourcesAPI(client.NewOpsRampClient(&common.Config{OpsRamp: common.OpsRampConfig{
+		TenantURL: server.URL, AuthURL: server.URL + "/auth/oauth/token", AuthKey: "key-1", AuthSecret: "secret-1", TenantID: "client_1",
+	}}))
+	ctx := context.Background()
+
+	search := func(args map[string]interface{}) types.ResourceSearchResponse {
+		t.Helper()
+		result, err := ResourcesToolHandler(ctx, createTestRequest(args), api)
+		if err != nil || result.IsError {
+			t.Fatalf("Search failed: %v %s", err, resultText(result))
+		}
+		var response types.ResourceSearchResponse
+		if err := json.Unmarshal([]byte(resultText(result)), &response); err != nil {
+			t.Fatalf("Invalid search response: %v", err)
+		}
+		return response
+	}
+
+	if response := search(map[string]interface{}{"action": "list"}); len(response.Results) != 100 || searches != 1 || !response.NextPage {
+		t.Errorf("Expected list to return the first page only, got %d results from %d searches", len(response.Results), searches)
+	}
+
+	searches = 0
+	response := search(map[string]interface{}{"action": "list", "allPages": true})
+	if len(response.Results) != total || searches != 3 || response.Results[total-1].ID != fmt.Sprintf("r%d", total) {
+		t.Fatalf("Expected list with allPages to return all %d resources from 3 pages, got %d from %d searches", total, len(response.Results), searches)
+	}
+	if info := response.Pagination; info == nil || info.HasMore || !info.TotalExact || info.FetchedCount != total || response.TotalResults != total {
+		t.Errorf("Expected the pagination of a complete scan, got %+v", info)
+	}
+
+	searches = 0
+	response = search(map[string]interface{}{"action": "search", "allPages": true, "params": map[string]interface{}{"pageNo": 2, "pageSize": 50}})
+	if len(response.Results) != total-50 || searches != 4 || response.Results[0].ID != "r51" {
+		t.Errorf("Expected search with allPages to follow the pages from params.pageNo, got %d results from %d searches", len(response.Results), searches)
+	}
+}
+
+func TestSearchAllPagesWithoutSearchAll(t *testing.T) {
+	fixtures, err := LoadFixtures(demoFixtures)
+	if err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+	response, err := searchAllPages(context.Background(), NewFixtureResourcesAPI(fixtures), types.ResourceSearchParams{PageSize: 2})
+	if err != nil {
+		t.Fatalf("searchAllPages failed: %v", err)
+	}
+	if len(response.Results) != len(fixtures.resources) || response.NextPage || !response.Pagination.TotalExact {
+		t.Errorf("Expected every fixture resource across pages, got %d of %d (%+v)", len(response.Results), len(fixtures.resources), response.Pagination)
+	}
+}
@@ -0,0 +1,181 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// defaultGroupSample and maxGroupSample bound the members listed per
+	// bucket of a grouped search
+	defaultGroupSample = 5
+	maxGroupSample     = 25
+	// maxGroupedResources bounds the resources a grouped search scans
+	maxGroupedResources = 10000
+	// groupedSearchPageSize is the page size of a grouped search
+	groupedSearchPageSize = 100
+)
+
+// Fields a search can be grouped by
+const (
+	groupByDeviceGroup = "deviceGroup"
+	groupBySite        = "site"
+	groupByType        = "type"
+)
+
+// searchGroupBys are the fields a search can be grouped by
+var searchGroupBys = []string{groupByDeviceGroup, groupBySite, groupByType}
+
+// validateGroupBy checks the groupBy argument of search
+func validateGroupBy(groupBy string) error {
+	if !slices.Contains(searchGroupBys, groupBy) {
+		return fmt.Errorf("unknown groupBy %q: must be one of %s", groupBy, strings.Join(searchGroupBys, ", "))
+	}
+	return nil
+}
+
+// resourceBucket is a bucket a resource falls in
+type resourceBucket struct {
+	key, name string
+}
+
+// groupSearchResults runs a search over every page and buckets the matching
+// resources by groupBy, keeping the count of each bucket and the first
+// sampleSize members. At most maxGroupedResources resources are scanned.
+func groupSearchResults(ctx context.Context, api ResourcesAPI, params types.ResourceSearchParams, groupBy string, sampleSize int) (*types.ResourceGroups, error) {
+	if err := validateGroupBy(groupBy); err != nil {
+		return nil, err
+	}
+	if sampleSize <= 0 {
+		sampleSize = defaultGroupSample
+	}
+	sampleSize = min(sampleSize, maxGroupSample)
+
+	bucketsOf, err := resourceBuckets(ctx, api, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := &types.ResourceGroups{GroupBy: groupBy, Groups: []types.ResourceGroup{}}
+	groups := map[string]*types.ResourceGroup{}
+	if params.PageSize <= 0 {
+		params.PageSize = groupedSearchPageSize
+	}
+	it := SearchResourcesIter(ctx, api, params)
+	for it.Next() {
+		if grouped.Total == maxGroupedResources {
+			grouped.Truncated = true
+			break
+		}
+		grouped.Total++
+		resource := it.Value()
+		for _, bucket := range bucketsOf(resource) {
+			group, ok := groups[bucket.key]
+			if !ok {
+				group = &types.ResourceGroup{Key: bucket.key, Name: bucket.name, Sample: []types.ResourceMinimal{}}
+				groups[bucket.key] = group
+			}
+			group.Count++
+			if len(group.Sample) < sampleSize {
+				group.Sample = append(group.Sample, minimalResource(resource))
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search resources: %w", err)
+	}
+
+	for _, group := range groups {
+		grouped.Groups = append(grouped.Groups, *group)
+	}
+	sort.Slice(grouped.Groups, func(i, j int) bool {
+		a, b := grouped.Groups[i], grouped.Groups[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+	if grouped.Truncated {
+		grouped.Message = fmt.Sprintf("Only the first %d matching resources were grouped; narrow the search params for complete counts", maxGroupedResources)
+	}
+	return grouped, nil
+}
+
+// resourceBuckets returns a function giving the buckets a resource falls
+// in. Resources only say which site they are at, so grouping by device
+// group first reads the members of every device group.
+func resourceBuckets(ctx context.Context, api ResourcesAPI, groupBy string) (func(types.Resource) []resourceBucket, error) {
+	switch groupBy {
+	case groupByType:
+		return func(resource types.Resource) []resourceBucket {
+			kind := resource.Type
+			if kind == "" {
+				kind = resource.ResourceType
+			}
+			if kind == "" {
+				return []resourceBucket{{name: "(no type)"}}
+			}
+			return []resourceBucket{{key: kind, name: kind}}
+		}, nil
+	case groupBySite:
+		return func(resource types.Resource) []resourceBucket {
+			if resource.Location == nil || (resource.Location.ID == nil && resource.Location.Name == "") {
+				return []resourceBucket{{name: "(no site)"}}
+			}
+			key := resource.Location.Name
+			if resource.Location.ID != nil {
+				key = fmt.Sprint(resource.Location.ID)
+			}
+			return []resourceBucket{{key: key, name: resource.Location.Name}}
+		}, nil
+	}
+
+	catalog, ok := api.(GroupCatalogProvider)
+	members, hasMembers := api.(DeviceGroupProvider)
+	if !ok || !hasMembers {
+		return nil, fmt.Errorf("grouping by device group is not supported by this resources backend")
+	}
+	groups, err := catalog.ListDeviceGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device groups: %w", err)
+	}
+	membership := map[string][]resourceBucket{}
+	for _, group := range flattenDeviceGroups(groups) {
+		ids, err := members.GetDeviceGroupMembers(ctx, group.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the members of device group %s: %w", group.Name, err)
+		}
+		for _, id := range ids {
+			membership[id] = append(membership[id], resourceBucket{key: group.ID, name: group.Name})
+		}
+	}
+	return func(resource types.Resource) []resourceBucket {
+		if buckets, ok := membership[resource.ID]; ok {
+			return buckets
+		}
+		return []resourceBucket{{name: "(no device group)"}}
+	}, nil
+}
+
+// minimalResource returns the summary fields of a resource
+func minimalResource(resource types.Resource) types.ResourceMinimal {
+	return types.ResourceMinimal{
+		ID:           resource.ID,
+		HostName:     resource.HostName,
+		IPAddress:    resource.IPAddress,
+		Name:         resource.Name,
+		ResourceName: resource.ResourceName,
+		Type:         resource.Type,
+		ResourceType: resource.ResourceType,
+		State:        resource.State,
+		Status:       resource.Status,
+		Location:     resource.Location,
+		Tags:         resource.Tags,
+		UpdatedDate:  resource.UpdatedDate,
+	}
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestResourcesToolSearchGroupedByDeviceGroup(t *testing.T) {
+	fixtures, err := LoadFixtures(demoFixtures)
+	if err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+	api := NewFixtureResourcesAPI(fixtures)
+
+	result, err := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action":     "search",
+		"params":     map[string]interface{}{"pageSize": 2},
+		"groupBy":    "deviceGroup",
+		"sampleSize": 1,
+	}), api)
+	if err != nil || result.IsError {
+		t.Fatalf("Grouped search failed: %v %s", err, resultText(result))
+	}
+
+	var grouped types.ResourceGroups
+	if err := json.Unmarshal([]byte(resultText(result)), &grouped); err != nil {
+		t.Fatalf("Invalid groups: %v", err)
+	}
+	if grouped.GroupBy != "deviceGroup" || grouped.Total != len(fixtures.resources) || grouped.Truncated {
+		t.Fatalf("Expected every resource across pages to be grouped, got %+v", grouped)
+	}
+	counts := map[string]int{}
+	for _, group := range grouped.Groups {
+		counts[group.Key] = group.Count
+		if len(group.Sample) != 1 {
+			t.Errorf("Expected one sampled member in %s, got %d", group.Name, len(group.Sample))
+		}
+	}
+	if counts["dg-demo-production"] != 4 || counts["dg-demo-storefront"] != 2 {
+		t.Errorf("Expected the fixture memberships to be counted, got %v", counts)
+	}
+	if grouped.Groups[0].Key != "dg-demo-production" {
+		t.Errorf("Expected the largest bucket first, got %s", grouped.Groups[0].Name)
+	}
+}
+
+func TestResourcesToolSearchGroupedBySite(t *testing.T) {
+	api := &FakeResourcesAPI{}
+	api.SearchReturns(&types.ResourceSearchResponse{Results: []types.Resource{
+		{ID: "r-1", Location: &types.Location{ID: 7.0, Name: "Austin"}},
+		{ID: "r-2", Location: &types.Location{ID: 7.0, Name: "Austin"}},
+		{ID: "r-3"},
+	}}, nil)
+
+	result, _ := ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "search", "groupBy": "site",
+	}), api)
+	var grouped types.ResourceGroups
+	if err := json.Unmarshal([]byte(resultText(result)), &grouped); err != nil {
+		t.Fatalf("Invalid groups: %v", err)
+	}
+	if len(grouped.Groups) != 2 || grouped.Groups[0].Key != "7" || grouped.Groups[0].Count != 2 || grouped.Groups[1].Key != "" {
+		t.Errorf("Expected Austin and the resources without a site, got %+v", grouped.Groups)
+	}
+
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "search", "groupBy": "owner",
+	}), api)
+	if !result.IsError || !strings.Contains(resultText(result), `unknown groupBy "owner"`) {
+		t.Errorf("Expected an unknown groupBy to be refused, got %s", resultText(result))
+	}
+
+	result, _ = ResourcesToolHandler(context.Background(), createTestRequest(map[string]interface{}{
+		"action": "search", "groupBy": "deviceGroup",
+	}), api)
+	if !result.IsError || !strings.Contains(resultText(result), "not supported") {
+		t.Errorf("Expected device groups to need a backend listing them, got %s", resultText(result))
+	}
+}
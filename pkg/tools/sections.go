@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"context"
+	"sync"
+
+	"github.com/opsramp/or-mcp-v2/common"
+)
+
+// sectionFetch fetches one section of a composite response, storing it in the
+// response itself. Each fetch must write only its own fields.
+type sectionFetch func(ctx context.Context) error
+
+// fetchSections runs the fetches of a composite response concurrently. A
+// failing section does not fail the others: its error is logged and returned
+// keyed by section name. The map is nil when every section succeeded.
+func fetchSections(ctx context.Context, logger *common.CustomLogger, subject string, sections map[string]sectionFetch) map[string]string {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed map[string]string
+	)
+	for name, fetch := range sections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fetch(ctx); err != nil {
+				logger.Warn("%s: %s unavailable: %v", subject, name, err)
+				mu.Lock()
+				if failed == nil {
+					failed = map[string]string{}
+				}
+				failed[name] = err.Error()
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return failed
+}
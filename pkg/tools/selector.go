@@ -0,0 +1,293 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/locks"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// maxBulkTargets bounds the number of resources a selector may resolve to
+	maxBulkTargets = 500
+	// maxSelectorScan bounds the number of candidates examined while resolving a selector
+	maxSelectorScan = 5000
+	// selectorPageSize is the page size used when resolving a selector
+	selectorPageSize = 100
+)
+
+// acquireBulkLock takes the advisory lock guarding bulk mutations of one kind
+// of object on the tenant targeted by the request, e.g. "bulk:resources:default".
+// It fails fast or waits according to the configured lock mode.
+func acquireBulkLock(ctx context.Context, req mcp.CallToolRequest, kind string) (func(), error) {
+	tenant := requestTenant(req)
+	holder := session.IDFromContext(ctx)
+	if holder == "" {
+		holder = "anonymous"
+	}
+	purpose := fmt.Sprintf("%s %s", req.Params.Name, req.GetString("action", ""))
+	return locks.Default().Acquire(ctx, fmt.Sprintf("bulk:%s:%s", kind, tenant), "session "+holder, purpose)
+}
+
+// requestTenant returns the tenant a request targets, naming the default
+// tenant explicitly
+func requestTenant(req mcp.CallToolRequest) string {
+	if tenant := req.GetString("tenant", ""); tenant != "" {
+		return tenant
+	}
+	return client.DefaultTenant
+}
+
+// BulkSelector resolves resource selectors to concrete IDs and runs bulk
+// mutations against them, previewing the targets until confirmed
+type BulkSelector struct {
+	api    ResourcesAPI
+	logger *common.CustomLogger
+
+	confirmation *deleteConfirmation
+	tenant       string
+	deleteToken  string
+}
+
+// NewBulkSelector creates a new BulkSelector
+func NewBulkSelector(api ResourcesAPI) *BulkSelector {
+	return &BulkSelector{
+		api:    api,
+		logger: common.GetLogger(),
+	}
+}
+
+// WithDeleteConfirmation makes confirmed deletes of more than the
+// confirmation threshold present token, bound to the resolved targets
+func (b *BulkSelector) WithDeleteConfirmation(confirmation *deleteConfirmation, tenant, token string) *BulkSelector {
+	b.confirmation = confirmation
+	b.tenant = tenant
+	b.deleteToken = token
+	return b
+}
+
+// resourceMatcher is a compiled selector
+type resourceMatcher struct {
+	selector types.ResourceSelector
+	network  *net.IPNet
+	ip       net.IP
+	tagName  string
+	tagValue string
+	hasValue bool
+}
+
+// compileSelector validates a selector and prepares it for matching
+func compileSelector(selector types.ResourceSelector) (*resourceMatcher, error) {
+	if selector == (types.ResourceSelector{}) {
+		return nil, fmt.Errorf("selector must set at least one of type, tag, hostName, name, ipAddress, state or deviceGroup")
+	}
+
+	m := &resourceMatcher{selector: selector}
+	for field, pattern := range map[string]string{"hostName": selector.HostName, "name": selector.Name} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %v", field, pattern, err)
+		}
+	}
+
+	if selector.IPAddress != "" {
+		if strings.Contains(selector.IPAddress, "/") {
+			_, network, err := net.ParseCIDR(selector.IPAddress)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ipAddress %q: expected an address or CIDR range such as 10.0.1.0/24", selector.IPAddress)
+			}
+			m.network = network
+		} else if m.ip = net.ParseIP(selector.IPAddress); m.ip == nil {
+			return nil, fmt.Errorf("invalid ipAddress %q: expected an address or CIDR range such as 10.0.1.0/24", selector.IPAddress)
+		}
+	}
+
+	if selector.Tag != "" {
+		m.tagName, m.tagValue, m.hasValue = strings.Cut(selector.Tag, "=")
+		m.tagName = strings.TrimSpace(m.tagName)
+		m.tagValue = strings.TrimSpace(m.tagValue)
+		if m.tagName == "" {
+			return nil, fmt.Errorf("invalid tag %q: expected name=value or name", selector.Tag)
+		}
+	}
+
+	return m, nil
+}
+
+// searchParams narrows the server-side search as far as the API allows;
+// wildcards, CIDR ranges and tags are matched locally
+func (m *resourceMatcher) searchParams() types.ResourceSearchParams {
+	params := types.ResourceSearchParams{
+		Type:        m.selector.Type,
+		State:       m.selector.State,
+		DeviceGroup: m.selector.DeviceGroup,
+		PageSize:    selectorPageSize,
+	}
+	if !strings.Contains(m.selector.HostName, "*") {
+		params.HostName = m.selector.HostName
+	}
+	if !strings.Contains(m.selector.Name, "*") {
+		params.ResourceName = m.selector.Name
+	}
+	if m.ip != nil {
+		params.IPAddress = m.selector.IPAddress
+	}
+	return params
+}
+
+// matches reports whether a resource satisfies every field of the selector.
+// Search filters are re-checked because the API may match them loosely.
+func (m *resourceMatcher) matches(resource types.Resource) bool {
+	s := m.selector
+	if s.Type != "" && !strings.EqualFold(s.Type, resource.Type) && !strings.EqualFold(s.Type, resource.ResourceType) {
+		return false
+	}
+	if s.State != "" && !strings.EqualFold(s.State, resource.State) {
+		return false
+	}
+	if s.HostName != "" && !globMatch(s.HostName, resource.HostName) {
+		return false
+	}
+	if s.Name != "" && !globMatch(s.Name, resource.Name) && !globMatch(s.Name, resource.ResourceName) {
+		return false
+	}
+	if m.ip != nil || m.network != nil {
+		ip := net.ParseIP(resource.IPAddress)
+		if ip == nil || (m.ip != nil && !m.ip.Equal(ip)) || (m.network != nil && !m.network.Contains(ip)) {
+			return false
+		}
+	}
+	if m.tagName != "" && !m.hasTag(resource.Tags) {
+		return false
+	}
+	return true
+}
+
+// hasTag reports whether the tags include the selector's tag
+func (m *resourceMatcher) hasTag(tags []types.Tag) bool {
+	for _, tag := range tags {
+		if strings.EqualFold(tag.Name, m.tagName) && (!m.hasValue || tag.Value == m.tagValue) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch matches a case-insensitive "*" wildcard pattern
+func globMatch(pattern, value string) bool {
+	matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+	return matched
+}
+
+// Resolve returns the resources matching the selector
+func (b *BulkSelector) Resolve(ctx context.Context, selector types.ResourceSelector) ([]types.Resource, error) {
+	matcher, err := compileSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	params := matcher.searchParams()
+	matched := make([]types.Resource, 0)
+	scanned := 0
+	for params.PageNo = 1; ; params.PageNo++ {
+		page, err := b.api.Search(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve selector: %w", err)
+		}
+
+		for _, resource := range page.Results {
+			if matcher.matches(resource) {
+				matched = append(matched, resource)
+			}
+		}
+		if len(matched) > maxBulkTargets {
+			return nil, fmt.Errorf("selector matches more than %d resources; narrow it down", maxBulkTargets)
+		}
+
+		scanned += len(page.Results)
+		if !page.NextPage || len(page.Results) == 0 {
+			break
+		}
+		if scanned >= maxSelectorScan {
+			return nil, fmt.Errorf("selector scanned %d resources without finishing; add type, state, deviceGroup or an exact hostName to narrow the search", scanned)
+		}
+	}
+
+	b.logger.Info("Selector resolved to %d resources (%d scanned)", len(matched), scanned)
+	return matched, nil
+}
+
+// bulkTarget describes a resource targeted by a bulk action
+func bulkTarget(resource types.Resource) types.BulkTarget {
+	return types.BulkTarget{
+		ID:        resource.ID,
+		Name:      displayName(resource.Name, resource.ResourceName),
+		HostName:  resource.HostName,
+		IPAddress: resource.IPAddress,
+		Type:      resource.Type,
+	}
+}
+
+// Run resolves the selector and, when confirmed, applies a bulk update
+// (updates is non-nil) or bulk delete (updates is nil) to the targets.
+// expectedCount must equal the number of resolved targets, so targets that
+// appeared since the preview are never changed without review.
+func (b *BulkSelector) Run(ctx context.Context, action string, selector types.ResourceSelector, updates map[string]interface{}, confirm bool, expectedCount int) (*types.BulkSelectionResult, error) {
+	resources, err := b.Resolve(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.BulkSelectionResult{
+		Action:   action,
+		Selector: selector,
+		Count:    len(resources),
+		Targets:  make([]types.BulkTarget, 0, len(resources)),
+	}
+	ids := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		ids = append(ids, resource.ID)
+		result.Targets = append(result.Targets, bulkTarget(resource))
+	}
+	needsToken := updates == nil && b.confirmation != nil && b.confirmation.Requires(result.Count)
+
+	switch {
+	case result.Count == 0:
+		result.Message = "No resources match the selector; nothing to do"
+		return result, nil
+	case !confirm:
+		result.Message = fmt.Sprintf("Preview only: %d resources would be affected by %s. Re-run with confirm: true and expectedCount: %d to apply.",
+			result.Count, action, result.Count)
+		if needsToken {
+			result.Message += " Deleting this many resources also requires the confirmationToken returned by planDelete for the same selector."
+		}
+		return result, nil
+	case expectedCount != result.Count:
+		return nil, fmt.Errorf("selector now matches %d resources, not the expected %d; review the new preview before confirming", result.Count, expectedCount)
+	}
+	if needsToken {
+		if err := b.confirmation.Verify(b.deleteToken, b.tenant, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	if updates != nil {
+		err = b.api.BulkUpdate(ctx, types.ResourceBulkUpdateRequest{ResourceIDs: ids, Updates: updates})
+	} else {
+		err = b.api.BulkDelete(ctx, types.ResourceBulkDeleteRequest{ResourceIDs: ids})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Executed = true
+	result.Message = fmt.Sprintf("Applied %s to %d resources", action, result.Count)
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// selectorResourcesAPI pages through a fixed inventory and records bulk
+// mutations; unused ResourcesAPI methods panic
+type selectorResourcesAPI struct {
+	ResourcesAPI
+	resources []types.Resource
+	searches  []types.ResourceSearchParams
+	updated   *types.ResourceBulkUpdateRequest
+	deleted   *types.ResourceBulkDeleteRequest
+}
+
+func (m *selectorResourcesAPI) Search(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+	m.searches = append(m.searches, params)
+	start := min((params.PageNo-1)*params.PageSize, len(m.resources))
+	end := min(start+params.PageSize, len(m.resources))
+	return &types.ResourceSearchResponse{Results: m.resources[start:end], NextPage: end < len(m.resources)}, nil
+}
+
+func (m *selectorResourcesAPI) BulkUpdate(ctx context.Context, request types.ResourceBulkUpdateRequest) error {
+	m.updated = &request
+	return nil
+}
+
+func (m *selectorResourcesAPI) BulkDelete(ctx context.Context, request types.ResourceBulkDeleteRequest) error {
+	m.deleted = &request
+	return nil
+}
+
+func newSelectorInventory() *selectorResourcesAPI {
+	resources := make([]types.Resource, 0)
+	for i, host := range []string{"web-01", "web-02", "db-01", "WEB-03"} {
+		resource := types.Resource{
+			ID:        "r" + string(rune('1'+i)),
+			HostName:  host,
+			IPAddress: "10.0." + string(rune('1'+i%2)) + ".5",
+			Type:      "Server",
+			Tags:      []types.Tag{{Name: "env", Value: "dev"}},
+		}
+		if host == "web-02" {
+			resource.Tags = []types.Tag{{Name: "env", Value: "prod"}}
+		}
+		resources = append(resources, resource)
+	}
+	// Enough filler to span several pages
+	for i := 0; i < 2*selectorPageSize; i++ {
+		resources = append(resources, types.Resource{ID: "filler", HostName: "app", Type: "Server"})
+	}
+	return &selectorResourcesAPI{resources: resources}
+}
+
+func TestBulkSelectorResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector types.ResourceSelector
+		want     []string
+	}{
+		{name: "tag", selector: types.ResourceSelector{Type: "server", Tag: "env=dev"}, want: []string{"r1", "r3", "r4"}},
+		{name: "tag name only", selector: types.ResourceSelector{Tag: "env"}, want: []string{"r1", "r2", "r3", "r4"}},
+		{name: "hostname wildcard", selector: types.ResourceSelector{HostName: "web-*"}, want: []string{"r1", "r2", "r4"}},
+		{name: "cidr", selector: types.ResourceSelector{IPAddress: "10.0.1.0/24"}, want: []string{"r1", "r3"}},
+		{name: "combined", selector: types.ResourceSelector{HostName: "web-*", IPAddress: "10.0.1.0/24"}, want: []string{"r1"}},
+		{name: "no match", selector: types.ResourceSelector{HostName: "mail-*"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := NewBulkSelector(newSelectorInventory()).Resolve(context.Background(), tt.selector)
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			ids := make([]string, 0, len(resources))
+			for _, resource := range resources {
+				ids = append(ids, resource.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected %v, got %v", tt.want, ids)
+			}
+		})
+	}
+}
+
+func TestBulkSelectorRejectsInvalidSelectors(t *testing.T) {
+	for _, selector := range []types.ResourceSelector{
+		{},
+		{IPAddress: "10.0.1"},
+		{IPAddress: "10.0.1.0/33"},
+		{Tag: "=dev"},
+		{HostName: "web-["},
+	} {
+		if _, err := NewBulkSelector(newSelectorInventory()).Resolve(context.Background(), selector); err == nil {
+			t.Errorf("Expected an error for selector %+v", selector)
+		}
+	}
+}
+
+func TestBulkSelectorPreviewThenConfirm(t *testing.T) {
+	api := newSelectorInventory()
+	selector := types.ResourceSelector{Type: "Server", Tag: "env=dev"}
+	updates := map[string]interface{}{"state": "unmanaged"}
+
+	preview, err := NewBulkSelector(api).Run(context.Background(), "bulkUpdate", selector, updates, false, -1)
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	if preview.Executed || preview.Count != 3 || len(preview.Targets) != 3 || api.updated != nil {
+		t.Fatalf("Expected a 3-target preview with nothing changed, got %+v", preview)
+	}
+	if api.searches[0].Type != "Server" || api.searches[0].HostName != "" {
+		t.Errorf("Expected the search to be narrowed by type only, got %+v", api.searches[0])
+	}
+
+	if _, err := NewBulkSelector(api).Run(context.Background(), "bulkUpdate", selector, updates, true, 2); err == nil || api.updated != nil {
+		t.Fatal("Expected a count mismatch to be refused")
+	}
+
+	result, err := NewBulkSelector(api).Run(context.Background(), "bulkUpdate", selector, updates, true, 3)
+	if err != nil {
+		t.Fatalf("Confirmed run failed: %v", err)
+	}
+	if !result.Executed || api.updated == nil || strings.Join(api.updated.ResourceIDs, ",") != "r1,r3,r4" {
+		t.Errorf("Expected r1, r3 and r4 to be updated, got %+v", api.updated)
+	}
+
+	if _, err := NewBulkSelector(api).Run(context.Background(), "bulkDelete", types.ResourceSelector{HostName: "db-*"}, nil, true, 1); err != nil || api.deleted == nil {
+		t.Errorf("Expected db-01 to be deleted, got %+v (%v)", api.deleted, err)
+	}
+}
@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// selfTestTimeout bounds each probe of a self-test
+const selfTestTimeout = 30 * time.Second
+
+// Self-test check statuses
+const (
+	selfTestPass    = "pass"
+	selfTestFail    = "fail"
+	selfTestSkipped = "skipped"
+)
+
+// selfTestProbes are the read-only calls a self-test makes to each tool,
+// fetching as little as the action allows. Tools without a probe, such as
+// utility, which only works on earlier results, are skipped.
+var selfTestProbes = map[string]map[string]interface{}{
+	"integrations": {"action": "list"},
+	"resources":    {"action": "search", "params": map[string]interface{}{"pageSize": 1}},
+	"alerts":       {"action": "search", "timeRange": "last15m"},
+	"usage":        {"action": "summary"},
+	"clients":      {"action": "list", "pageSize": 1},
+	"monitors":     {"action": "list", "pageSize": 1},
+	"metrics":      {"action": "search", "pageSize": 1},
+	"session":      {"action": "getContext"},
+	"admin":        {"action": "logLevels"},
+}
+
+// registeredTool is a tool definition with the handler serving it
+type registeredTool struct {
+	tool    mcp.Tool
+	handler server.ToolHandlerFunc
+}
+
+// toolHandlers records the registered tools by name, for the self-test
+var toolHandlers sync.Map
+
+// RegisterToolHandler records the handler of a registered tool, so the
+// selfTest admin action can call it
+func RegisterToolHandler(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	toolHandlers.Store(tool.Name, registeredTool{tool: tool, handler: handler})
+}
+
+// SelfTestCheck is the outcome of probing one tool
+type SelfTestCheck struct {
+	Tool      string                 `json:"tool"`
+	Status    string                 `json:"status"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	LatencyMs int64                  `json:"latencyMs"`
+	// Schema is valid or invalid when the result was checked against the
+	// tool's output schema, and none when the tool declares none
+	Schema string `json:"schema,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SelfTestReport is the result of the selfTest admin action
+type SelfTestReport struct {
+	Passed     bool            `json:"passed"`
+	Checked    int             `json:"checked"`
+	Failed     int             `json:"failed"`
+	Skipped    int             `json:"skipped"`
+	DurationMs int64           `json:"durationMs"`
+	Tools      []SelfTestCheck `json:"tools"`
+}
+
+// selfTest probes every registered tool
+func selfTest(ctx context.Context) *SelfTestReport {
+	var registered []registeredTool
+	toolHandlers.Range(func(_, value any) bool {
+		registered = append(registered, value.(registeredTool))
+		return true
+	})
+	return runSelfTest(ctx, registered, selfTestProbes)
+}
+
+// runSelfTest calls each tool with its probe, one at a time so the
+// latencies are not skewed by each other, and checks the results
+func runSelfTest(ctx context.Context, registered []registeredTool, probes map[string]map[string]interface{}) *SelfTestReport {
+	sort.Slice(registered, func(i, j int) bool { return registered[i].tool.Name < registered[j].tool.Name })
+
+	start := time.Now()
+	report := &SelfTestReport{Tools: make([]SelfTestCheck, 0, len(registered))}
+	for _, entry := range registered {
+		check := SelfTestCheck{Tool: entry.tool.Name}
+		arguments, ok := probes[entry.tool.Name]
+		if !ok {
+			check.Status = selfTestSkipped
+			check.Error = "no read-only probe is defined for this tool"
+			report.Skipped++
+		} else {
+			check.Arguments = arguments
+			probeTool(ctx, entry, &check)
+			report.Checked++
+			if check.Status == selfTestFail {
+				report.Failed++
+			}
+		}
+		report.Tools = append(report.Tools, check)
+	}
+	report.DurationMs = time.Since(start).Milliseconds()
+	report.Passed = report.Failed == 0
+	return report
+}
+
+// probeTool calls a tool with its probe arguments and records whether it
+// answered without an error and with a result its output schema allows
+func probeTool(ctx context.Context, entry registeredTool, check *SelfTestCheck) {
+	check.Status = selfTestFail
+	for name := range check.Arguments {
+		if _, ok := entry.tool.InputSchema.Properties[name]; !ok {
+			check.Error = fmt.Sprintf("probe argument %s is not in the tool's input schema", name)
+			return
+		}
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+	defer cancel()
+	req := mcp.CallToolRequest{}
+	req.Params.Name = entry.tool.Name
+	req.Params.Arguments = check.Arguments
+
+	start := time.Now()
+	result, err := entry.handler(probeCtx, req)
+	check.LatencyMs = time.Since(start).Milliseconds()
+	switch {
+	case err != nil:
+		check.Error = err.Error()
+		return
+	case result == nil:
+		check.Error = "the tool returned no result"
+		return
+	case result.IsError:
+		check.Error = "the tool returned an error"
+		for _, content := range result.Content {
+			if text, ok := content.(mcp.TextContent); ok {
+				check.Error = text.Text
+				break
+			}
+		}
+		return
+	}
+
+	check.Schema = "none"
+	if len(entry.tool.OutputSchema) > 0 {
+		check.Schema = "valid"
+		if err := validateSchema(entry.tool.OutputSchema, result.StructuredContent, "result"); err != nil {
+			check.Schema = "invalid"
+			check.Error = err.Error()
+			return
+		}
+	}
+	check.Status = selfTestPass
+}
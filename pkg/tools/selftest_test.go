@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// selfTestTool returns a tool declaring an action argument and the output
+// schema of usage summaries, answered by handler
+func selfTestTool(name string, handler server.ToolHandlerFunc) registeredTool {
+	return registeredTool{
+		tool: mcp.Tool{
+			Name: name,
+			InputSchema: mcp.ToolInputSchema{
+				Type:       "object",
+				Properties: map[string]interface{}{"action": map[string]interface{}{"type": "string"}},
+			},
+			OutputSchema: outputSchema(types.UsageSummary{}),
+		},
+		handler: handler,
+	}
+}
+
+func TestRunSelfTest(t *testing.T) {
+	respond := func(structured any) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{
+				Content:           []mcp.Content{mcp.TextContent{Type: "text", Text: "{}"}},
+				StructuredContent: structured,
+			}, nil
+		}
+	}
+	registered := []registeredTool{
+		selfTestTool("healthy", respond(map[string]any{"totalManaged": 3, "summary": "ok"})),
+		// A result without structured content does not match an output schema
+		selfTestTool("drifted", respond(nil)),
+		selfTestTool("broken", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, errors.New("connection refused")
+		}),
+		selfTestTool("undeclared", respond(map[string]any{})),
+		selfTestTool("unprobed", respond(map[string]any{})),
+	}
+	probes := map[string]map[string]interface{}{
+		"healthy":    {"action": "summary"},
+		"drifted":    {"action": "summary"},
+		"broken":     {"action": "summary"},
+		"undeclared": {"action": "list", "pageSize": 1},
+	}
+
+	report := runSelfTest(context.Background(), registered, probes)
+	if report.Passed || report.Checked != 4 || report.Failed != 3 || report.Skipped != 1 {
+		t.Fatalf("Expected 1 of 4 probes to pass and 1 tool skipped, got %+v", report)
+	}
+
+	checks := map[string]SelfTestCheck{}
+	for _, check := range report.Tools {
+		checks[check.Tool] = check
+	}
+	if check := checks["healthy"]; check.Status != selfTestPass || check.Schema != "valid" {
+		t.Errorf("Expected healthy to pass with a valid schema, got %+v", check)
+	}
+	if check := checks["drifted"]; check.Status != selfTestFail || check.Schema != "invalid" {
+		t.Errorf("Expected drifted to fail its schema check, got %+v", check)
+	}
+	if check := checks["broken"]; check.Status != selfTestFail || check.Error != "connection refused" {
+		t.Errorf("Expected broken to report its error, got %+v", check)
+	}
+	if check := checks["undeclared"]; check.Status != selfTestFail || !strings.Contains(check.Error, "pageSize") {
+		t.Errorf("Expected undeclared to fail on its probe arguments, got %+v", check)
+	}
+	if check := checks["unprobed"]; check.Status != selfTestSkipped {
+		t.Errorf("Expected unprobed to be skipped, got %+v", check)
+	}
+	if report.Tools[0].Tool != "broken" {
+		t.Errorf("Expected the checks sorted by tool, got %s first", report.Tools[0].Tool)
+	}
+}
+
+// TestSelfTestProbesAreDeclared keeps the probes in step with the input
+// schemas of the tools they call
+func TestSelfTestProbesAreDeclared(t *testing.T) {
+	tools := map[string]mcp.Tool{}
+	for _, tool := range []mcp.Tool{
+		firstTool(createIntegrationsTool(nil, nil)), firstTool(createResourcesTool(nil)), firstTool(createAlertsTool(nil, "")),
+		firstTool(createUsageTool(nil)), firstTool(createClientsTool(nil, nil)), firstTool(createMonitorsTool(nil)),
+		firstTool(createMetricsTool(nil)), firstTool(NewSessionMcpTool()), firstTool(NewAdminMcpTool()),
+	} {
+		tools[tool.Name] = tool
+	}
+
+	for name, arguments := range selfTestProbes {
+		tool, ok := tools[name]
+		if !ok {
+			t.Errorf("Probe for unknown tool %s", name)
+			continue
+		}
+		for argument := range arguments {
+			if _, ok := tool.InputSchema.Properties[argument]; !ok {
+				t.Errorf("Probe for %s passes undeclared argument %s", name, argument)
+			}
+		}
+		action := arguments["action"].(string)
+		if !strings.Contains(tool.InputSchema.Properties["action"].(map[string]interface{})["description"].(string), action) {
+			t.Errorf("Probe for %s calls unknown action %s", name, action)
+		}
+	}
+}
+
+// firstTool returns the tool definition of a tool constructor's results
+func firstTool(tool mcp.Tool, _ server.ToolHandlerFunc) mcp.Tool {
+	return tool
+}
@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+)
+
+// sessionVariablesKey is the session key holding the context variables set via setContext
+const sessionVariablesKey = "contextVariables"
+
+// toolArguments records the input arguments each registered tool declares, so
+// context variables are only merged into tools that accept them
+var toolArguments sync.Map
+
+// RegisterToolSchema records the arguments declared by a tool's input schema
+// and their types
+func RegisterToolSchema(tool mcp.Tool) {
+	toolProperties.Store(tool.Name, tool.InputSchema.Properties)
+	declared := make(map[string]bool, len(tool.InputSchema.Properties))
+	for name := range tool.InputSchema.Properties {
+		declared[name] = true
+	}
+	toolArguments.Store(tool.Name, declared)
+}
+
+// contextArgumentName maps a context variable to the tool argument it fills:
+// defaultDeviceGroup fills deviceGroup, while other names map to themselves
+func contextArgumentName(variable string) string {
+	name := strings.TrimPrefix(variable, "default")
+	if name == variable || name == "" {
+		return variable
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// sessionVariables returns a copy of the context variables stored in a session
+func sessionVariables(sess *session.Session) map[string]interface{} {
+	variables := make(map[string]interface{})
+	if value, ok := sess.Get(sessionVariablesKey); ok {
+		for name, v := range value.(map[string]interface{}) {
+			variables[name] = v
+		}
+	}
+	return variables
+}
+
+// SessionContextMiddleware merges the session's context variables into each
+// tool call. Explicit arguments always win, and a variable is only merged into
+// tools whose schema declares the matching argument.
+func SessionContextMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sess := session.FromContext(ctx)
+		if sess == nil {
+			return next(ctx, req)
+		}
+
+		variables := sessionVariables(sess)
+		if len(variables) == 0 {
+			return next(ctx, req)
+		}
+
+		declared, ok := toolArguments.Load(req.Params.Name)
+		if !ok {
+			return next(ctx, req)
+		}
+
+		args := make(map[string]interface{})
+		for name, value := range req.GetArguments() {
+			args[name] = value
+		}
+
+		var merged []string
+		for variable, value := range variables {
+			name := contextArgumentName(variable)
+			if _, set := args[name]; set || !declared.(map[string]bool)[name] {
+				continue
+			}
+			args[name] = value
+			merged = append(merged, name)
+		}
+
+		if len(merged) > 0 {
+			sort.Strings(merged)
+			common.GetLogger().Debug("Merged session context into %s: %v", req.Params.Name, merged)
+			req.Params.Arguments = args
+		}
+		return next(ctx, req)
+	}
+}
+
+// NewSessionMcpTool returns the MCP tool definition and handler for session context variables
+func NewSessionMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "session",
+		Description: "Manage session context variables (e.g. defaultTenant, defaultDeviceGroup, defaultTimeRange) that are automatically applied to later tool calls in this session. A variable named defaultX fills the x argument of any tool that accepts it; explicit arguments always take precedence. Workspaces save the variables, saved searches and recent results of an investigation to resume it in a later session. The budget action shows the OpsRamp calls left in the session's budget and, given a planned call, estimates how many it would make.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"action": map[string]interface{}{
+					"type":        "string",
+					"description": "Action to perform: setContext, getContext, clearContext, saveWorkspace, loadWorkspace, listWorkspaces, deleteWorkspace, budget",
+				},
+				"workspace": map[string]interface{}{
+					"type":        "string",
+					"description": "Workspace name (for saveWorkspace, loadWorkspace and deleteWorkspace)",
+				},
+				"searches": map[string]interface{}{
+					"type":        "object",
+					"description": "Saved searches to add to the workspace (for saveWorkspace), by name: {\"downServers\": {\"tool\": \"resources\", \"arguments\": {\"action\": \"search\", ...}}}; a null value removes the saved search",
+				},
+				"variables": map[string]interface{}{
+					"type":        "object",
+					"description": "Variables to set (for setContext); a null value removes the variable",
+				},
+				"names": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Variables to remove (for clearContext); omit to clear all",
+				},
+				"call": map[string]interface{}{
+					"type":        "object",
+					"description": "A planned tool call to estimate (for budget): {\"tool\": \"resources\", \"arguments\": {\"action\": \"impactAnalysis\", ...}}",
+				},
+			},
+			Required: []string{"action"},
+		},
+	}, SessionToolHandler
+}
+
+// SessionToolHandler routes requests to the correct method
+// Exported for testing purposes
+func SessionToolHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	action := req.GetString("action", "")
+
+	// Log the tool execution
+	logger := toolLogger("session")
+	logger.LogToolExecution("session", action, req.GetArguments())
+
+	sess := session.FromContext(ctx)
+	if sess == nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "session context requires an MCP session"}},
+		}, nil
+	}
+
+	var err error
+	var result interface{}
+	variables := sessionVariables(sess)
+
+	switch action {
+	case "setContext":
+		updates, ok := req.GetArguments()["variables"].(map[string]interface{})
+		if !ok || len(updates) == 0 {
+			err = fmt.Errorf("variables object is required for setContext")
+			break
+		}
+		for name, value := range updates {
+			if value == nil {
+				delete(variables, name)
+				continue
+			}
+			variables[name] = value
+		}
+		sess.Set(sessionVariablesKey, variables)
+	case "getContext":
+	case "clearContext":
+		names := req.GetStringSlice("names", nil)
+		if len(names) == 0 {
+			variables = make(map[string]interface{})
+		}
+		for _, name := range names {
+			delete(variables, name)
+		}
+		sess.Set(sessionVariablesKey, variables)
+	case "saveWorkspace":
+		searches, _ := req.GetArguments()["searches"].(map[string]interface{})
+		result, err = NewWorkspaces().Save(sess, session.IDFromContext(ctx), req.GetString("workspace", ""), searches, time.Now())
+	case "loadWorkspace":
+		result, err = NewWorkspaces().Load(sess, session.IDFromContext(ctx), req.GetString("workspace", ""))
+	case "listWorkspaces":
+		result, err = NewWorkspaces().List()
+	case "deleteWorkspace":
+		name := req.GetString("workspace", "")
+		if err = NewWorkspaces().Delete(name); err == nil {
+			result = map[string]interface{}{"deleted": name}
+		}
+	case "budget":
+		var planned *mcp.CallToolRequest
+		if call, ok := req.GetArguments()["call"].(map[string]interface{}); ok {
+			tool, _ := call["tool"].(string)
+			if tool == "" {
+				err = fmt.Errorf("call.tool is required to estimate a planned call")
+				break
+			}
+			planned = &mcp.CallToolRequest{}
+			planned.Params.Name = tool
+			planned.Params.Arguments = call["arguments"]
+		}
+		result = getCallBudget().Plan(session.IDFromContext(ctx), planned)
+	default:
+		logger.Error("Unknown action: %s", action)
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Unknown action: %s", action)}},
+		}, nil
+	}
+
+	if result == nil {
+		result = map[string]interface{}{"variables": variables}
+	}
+
+	// Log the result
+	logger.LogToolResult("session", action, result, err)
+
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+
+	return respondJSON(ctx, req, result)
+}
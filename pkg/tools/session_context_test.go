@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSessionContextMergedIntoToolCalls(t *testing.T) {
+	ctx := contextWithSession("session-context-1")
+
+	setReq := mcp.CallToolRequest{}
+	setReq.Params.Name = "session"
+	setReq.Params.Arguments = map[string]interface{}{
+		"action":    "setContext",
+		"variables": map[string]interface{}{"defaultTenant": "emea", "defaultTimeRange": "24h"},
+	}
+	if result, err := SessionToolHandler(ctx, setReq); err != nil || result.IsError {
+		t.Fatalf("setContext failed: %v %+v", err, result)
+	}
+
+	RegisterToolSchema(mcp.Tool{
+		Name:        "context-test",
+		InputSchema: mcp.ToolInputSchema{Properties: map[string]interface{}{"tenant": map[string]interface{}{}, "action": map[string]interface{}{}}},
+	})
+
+	var seen map[string]interface{}
+	handler := SessionContextMiddleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = req.GetArguments()
+		return &mcp.CallToolResult{}, nil
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "context-test"
+	req.Params.Arguments = map[string]interface{}{"action": "list"}
+	handler(ctx, req)
+	if seen["tenant"] != "emea" {
+		t.Errorf("Expected defaultTenant to fill tenant, got %v", seen)
+	}
+	if _, ok := seen["timeRange"]; ok {
+		t.Errorf("Expected undeclared timeRange not to be merged, got %v", seen)
+	}
+
+	req.Params.Arguments = map[string]interface{}{"action": "list", "tenant": "apac"}
+	handler(ctx, req)
+	if seen["tenant"] != "apac" {
+		t.Errorf("Expected explicit tenant to win, got %v", seen["tenant"])
+	}
+
+	clearReq := mcp.CallToolRequest{}
+	clearReq.Params.Arguments = map[string]interface{}{"action": "clearContext"}
+	SessionToolHandler(ctx, clearReq)
+
+	req.Params.Arguments = map[string]interface{}{"action": "list"}
+	handler(ctx, req)
+	if _, ok := seen["tenant"]; ok {
+		t.Errorf("Expected cleared context not to be merged, got %v", seen)
+	}
+}
+
+func TestContextArgumentName(t *testing.T) {
+	cases := map[string]string{
+		"defaultDeviceGroup": "deviceGroup",
+		"defaultTenant":      "tenant",
+		"pageSize":           "pageSize",
+		"default":            "default",
+	}
+	for variable, want := range cases {
+		if got := contextArgumentName(variable); got != want {
+			t.Errorf("contextArgumentName(%q) = %q, want %q", variable, got, want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxSuggestions bounds the follow-up calls suggested for one result
+const maxSuggestions = 3
+
+// suggestActionsArgument is the tool input schema entry for requesting
+// follow-up suggestions
+var suggestActionsArgument = map[string]interface{}{
+	"type":        "boolean",
+	"description": "Append a suggestedActions block listing a few sensible follow-up tool calls, with their arguments, for this result",
+}
+
+// SuggestedAction is a follow-up tool call suggested after a result
+type SuggestedAction struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Reason    string                 `json:"reason"`
+}
+
+// suggestionEdge is an edge of the capability graph: a follow-up call that
+// makes sense after an action succeeded, or after it failed to find what it
+// was asked for when onError is set. Carry names the request arguments the
+// follow-up needs. From, when set, derives further arguments from the
+// request and the decoded result, and returns nil when they give no reason
+// for the follow-up.
+type suggestionEdge struct {
+	onError bool
+	tool    string
+	action  string
+	reason  string
+	carry   []string
+	from    func(req mcp.CallToolRequest, result interface{}) map[string]interface{}
+}
+
+// capabilityGraph maps tool/action to the follow-ups worth suggesting after
+// it, most useful first
+var capabilityGraph = map[string][]suggestionEdge{
+	"resources/get": {
+		{onError: true, tool: "resources", action: "resolveId", carry: []string{"id"}, from: withArgs("idKind", "hostName"),
+			reason: "The ID may be a host name; resolve it to a resource ID"},
+		{onError: true, tool: "resources", action: "search", from: searchForID,
+			reason: "Search for resources matching the ID"},
+		{tool: "resources", action: "getAvailability", carry: []string{"id"}, reason: "Check the resource's recent availability"},
+		{tool: "resources", action: "scorecard", carry: []string{"id"}, reason: "Summarize the resource's health, alerts and configuration"},
+	},
+	"resources/getDetailed": {
+		{onError: true, tool: "resources", action: "resolveId", carry: []string{"id"}, from: withArgs("idKind", "hostName"),
+			reason: "The ID may be a host name; resolve it to a resource ID"},
+		{tool: "resources", action: "getMetrics", carry: []string{"id"}, reason: "Read the resource's metrics"},
+		{tool: "resources", action: "impactAnalysis", carry: []string{"id"}, reason: "See what depends on the resource"},
+	},
+	"resources/search": {
+		{tool: "resources", action: "getAvailability", from: firstDownResource, reason: "A matching resource is down; check its availability"},
+		{tool: "resources", action: "impactAnalysis", from: firstDownResource, reason: "A matching resource is down; see what depends on it"},
+		{tool: "resources", action: "search", from: groupedSearch,
+			reason: "Many resources match; group them by type instead of reading every row"},
+	},
+	"resources/getAvailability": {
+		{tool: "resources", action: "getMetrics", carry: []string{"id", "timeRange"}, reason: "Read the metrics over the same period"},
+		{tool: "resources", action: "impactAnalysis", carry: []string{"id"}, reason: "See what an outage of the resource affects"},
+	},
+	"resources/impactAnalysis": {
+		{tool: "resources", action: "scorecard", carry: []string{"id"}, reason: "Summarize the health of the resource at the root"},
+	},
+	"alerts/search": {
+		{tool: "alerts", action: "get", from: firstResultID, reason: "Read the first matching alert"},
+	},
+	"alerts/get": {
+		{onError: true, tool: "alerts", action: "search", reason: "Search for the alert instead"},
+		{tool: "alerts", action: "getThread", carry: []string{"id"}, reason: "Read the alert's notes and attachments"},
+		{tool: "resources", action: "scorecard", from: alertResource, reason: "Check the health of the alerting resource"},
+	},
+	"integrations/get": {
+		{onError: true, tool: "integrations", action: "list", reason: "List the installed integrations to find the right ID"},
+	},
+}
+
+// withArgs returns a result function adding fixed arguments
+func withArgs(keyValues ...string) func(mcp.CallToolRequest, interface{}) map[string]interface{} {
+	return func(mcp.CallToolRequest, interface{}) map[string]interface{} {
+		args := map[string]interface{}{}
+		for i := 0; i+1 < len(keyValues); i += 2 {
+			args[keyValues[i]] = keyValues[i+1]
+		}
+		return args
+	}
+}
+
+// searchForID searches resources for the ID a call did not find
+func searchForID(req mcp.CallToolRequest, _ interface{}) map[string]interface{} {
+	id := req.GetString("id", "")
+	if id == "" {
+		return nil
+	}
+	return map[string]interface{}{"params": map[string]interface{}{"queryString": id}}
+}
+
+// firstDownResource returns the ID of the first resource of a search that
+// is down
+func firstDownResource(_ mcp.CallToolRequest, result interface{}) map[string]interface{} {
+	for _, entry := range resultEntries(result) {
+		status, _ := entry["status"].(string)
+		if id, ok := entry["id"].(string); ok && id != "" && strings.EqualFold(status, "DOWN") {
+			return map[string]interface{}{"id": id}
+		}
+	}
+	return nil
+}
+
+// firstResultID returns the ID of the first entry of a search
+func firstResultID(_ mcp.CallToolRequest, result interface{}) map[string]interface{} {
+	for _, entry := range resultEntries(result) {
+		if id, ok := entry["id"].(string); ok && id != "" {
+			return map[string]interface{}{"id": id}
+		}
+	}
+	return nil
+}
+
+// groupedSearch repeats an ungrouped search grouped by type when it has more
+// results than fit on one page
+func groupedSearch(req mcp.CallToolRequest, result interface{}) map[string]interface{} {
+	object, _ := result.(map[string]interface{})
+	if more, _ := object["nextPage"].(bool); !more || req.GetString("groupBy", "") != "" {
+		return nil
+	}
+	args := map[string]interface{}{"groupBy": groupByType}
+	if params, ok := req.GetArguments()["params"]; ok {
+		args["params"] = params
+	}
+	return args
+}
+
+// alertResource returns the ID of the resource an alert was raised on
+func alertResource(_ mcp.CallToolRequest, result interface{}) map[string]interface{} {
+	object, _ := result.(map[string]interface{})
+	resource, _ := object["resource"].(map[string]interface{})
+	if id, ok := resource["id"].(string); ok && id != "" {
+		return map[string]interface{}{"id": id}
+	}
+	return nil
+}
+
+// resultEntries returns the entries of a search result
+func resultEntries(result interface{}) []map[string]interface{} {
+	object, _ := result.(map[string]interface{})
+	list, _ := object["results"].([]interface{})
+	entries := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		if entry, ok := item.(map[string]interface{}); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// suggestActions walks the capability graph from the called action. Only
+// errors saying something was not found lead anywhere, since retrying
+// elsewhere does not help a call that was refused or invalid. A follow-up
+// is skipped when an argument it carries is missing from the request or its
+// result function finds no reason for it.
+func suggestActions(req mcp.CallToolRequest, result *mcp.CallToolResult) []SuggestedAction {
+	edges := capabilityGraph[req.Params.Name+"/"+req.GetString("action", "")]
+	if len(edges) == 0 || (result.IsError && !strings.Contains(strings.ToLower(resultText(result)), "not found")) {
+		return nil
+	}
+
+	var decoded interface{}
+	if !result.IsError && len(result.Content) > 0 {
+		if text, ok := result.Content[0].(mcp.TextContent); ok {
+			_ = json.Unmarshal([]byte(text.Text), &decoded)
+		}
+	}
+
+	suggestions := []SuggestedAction{}
+	for _, edge := range edges {
+		if edge.onError != result.IsError || len(suggestions) == maxSuggestions {
+			continue
+		}
+		args := map[string]interface{}{"action": edge.action}
+		complete := true
+		for _, key := range edge.carry {
+			value, ok := req.GetArguments()[key]
+			if !ok {
+				complete = false
+				break
+			}
+			args[key] = value
+		}
+		if !complete {
+			continue
+		}
+		if edge.from != nil {
+			derived := edge.from(req, decoded)
+			if derived == nil {
+				continue
+			}
+			for key, value := range derived {
+				args[key] = value
+			}
+		}
+		if tenant := req.GetString("tenant", ""); tenant != "" {
+			args["tenant"] = tenant
+		}
+		suggestions = append(suggestions, SuggestedAction{Tool: edge.tool, Arguments: args, Reason: edge.reason})
+	}
+	return suggestions
+}
+
+// SuggestionsMiddleware appends a suggestedActions block to the results of
+// calls made with suggestActions set to true. The block is a separate text
+// content entry, so the result and its structured content are unchanged.
+func SuggestionsMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, req)
+		if err != nil || result == nil || !req.GetBool("suggestActions", false) {
+			return result, err
+		}
+		suggestions := suggestActions(req, result)
+		if len(suggestions) == 0 {
+			return result, nil
+		}
+		data, err := json.Marshal(map[string]interface{}{"suggestedActions": suggestions})
+		if err != nil {
+			return result, nil
+		}
+		result.Content = append(result.Content, mcp.TextContent{Type: "text", Text: string(data)})
+		return result, nil
+	}
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// suggestedActions decodes the suggestedActions block of a result, if any
+func suggestedActions(t *testing.T, result *mcp.CallToolResult) []SuggestedAction {
+	t.Helper()
+	if len(result.Content) < 2 {
+		return nil
+	}
+	var block struct {
+		SuggestedActions []SuggestedAction `json:"suggestedActions"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[len(result.Content)-1].(mcp.TextContent).Text), &block); err != nil {
+		t.Fatalf("Invalid suggestions block: %v", err)
+	}
+	return block.SuggestedActions
+}
+
+func TestSuggestionsMiddleware(t *testing.T) {
+	response := mcp.NewToolResultError("resource not found: web-01")
+	handler := SuggestionsMiddleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return response, nil
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "resources"
+	req.Params.Arguments = map[string]interface{}{"action": "get", "id": "web-01", "suggestActions": true}
+	result, _ := handler(context.Background(), req)
+	suggestions := suggestedActions(t, result)
+	if len(suggestions) != 2 || suggestions[0].Arguments["action"] != "resolveId" || suggestions[0].Arguments["idKind"] != "hostName" || suggestions[0].Arguments["id"] != "web-01" {
+		t.Fatalf("Expected a failed get to suggest resolving the host name, got %+v", suggestions)
+	}
+	if params, _ := suggestions[1].Arguments["params"].(map[string]interface{}); params["queryString"] != "web-01" {
+		t.Errorf("Expected a search for the ID, got %+v", suggestions[1])
+	}
+
+	response = mcp.NewToolResultText(`{"results": [{"id": "r-1", "status": "UP"}, {"id": "r-2", "status": "DOWN"}], "nextPage": true}`)
+	req.Params.Arguments = map[string]interface{}{"action": "search", "params": map[string]interface{}{"type": "SERVER"}, "suggestActions": true}
+	result, _ = handler(context.Background(), req)
+	suggestions = suggestedActions(t, result)
+	if len(suggestions) != 3 || suggestions[0].Arguments["action"] != "getAvailability" || suggestions[0].Arguments["id"] != "r-2" {
+		t.Fatalf("Expected the down resource to be followed up, got %+v", suggestions)
+	}
+	if suggestions[2].Arguments["groupBy"] != "type" || suggestions[2].Arguments["params"] == nil {
+		t.Errorf("Expected a paged search to suggest grouping it, got %+v", suggestions[2])
+	}
+
+	// Refused calls and calls without the flag are left alone
+	response = mcp.NewToolResultError("Resource ID is required for get action")
+	req.Params.Arguments = map[string]interface{}{"action": "get", "suggestActions": true}
+	if result, _ = handler(context.Background(), req); len(result.Content) != 1 {
+		t.Errorf("Expected no suggestions for an invalid call, got %+v", result.Content)
+	}
+	response = mcp.NewToolResultText(`{"id": "r-1"}`)
+	req.Params.Arguments = map[string]interface{}{"action": "get", "id": "r-1"}
+	if result, _ = handler(context.Background(), req); len(result.Content) != 1 {
+		t.Errorf("Expected no suggestions without suggestActions, got %+v", result.Content)
+	}
+}
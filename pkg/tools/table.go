@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// renderTable is the render argument value that adds a Markdown table
+const renderTable = "table"
+
+const (
+	// defaultTableRows and maxTableRows bound the rows of a rendered table
+	defaultTableRows = 50
+	maxTableRows     = 500
+	// maxDefaultTableColumns bounds the columns picked when none are selected
+	maxDefaultTableColumns = 8
+	// maxTableCellLength truncates long cell values
+	maxTableCellLength = 80
+)
+
+// renderArgument is the tool input schema entry for rendering results as a table
+var renderArgument = map[string]interface{}{
+	"type":        "string",
+	"enum":        []string{renderTable},
+	"description": "Set to table to also return list and search results as a Markdown table, in a second text block for clients that display text. The JSON result is unchanged",
+}
+
+// columnsArgument is the tool input schema entry selecting table columns
+var columnsArgument = map[string]interface{}{
+	"type":        "array",
+	"items":       map[string]interface{}{"type": "string"},
+	"description": "Fields to show as table columns, in order; nested fields use dots, e.g. resource.name (with render: table, defaults to the main scalar fields)",
+}
+
+// maxRowsArgument is the tool input schema entry limiting table rows
+var maxRowsArgument = map[string]interface{}{
+	"type":        "integer",
+	"description": fmt.Sprintf("Rows to show in the table (with render: table, default %d, max %d)", defaultTableRows, maxTableRows),
+}
+
+// tableColumnPriority orders the default columns of a table; other scalar
+// fields follow alphabetically
+var tableColumnPriority = []string{
+	"id", "name", "displayName", "hostName", "resourceName", "ipAddress", "type", "resourceType",
+	"subject", "currentState", "state", "status", "tenant",
+}
+
+// markdownTable renders the entries of a result as a Markdown table. A list,
+// or the results of a search, gives one row per entry and any other object a
+// single row. It returns "" for results that are not objects.
+func markdownTable(value interface{}, columns []string, maxRows int) (string, error) {
+	generic, err := normalizeJSON(value)
+	if err != nil {
+		return "", err
+	}
+	entries, ok := diffEntries(generic)
+	if !ok {
+		object, isObject := generic.(map[string]interface{})
+		if !isObject {
+			return "", nil
+		}
+		entries = []interface{}{object}
+	}
+
+	if maxRows <= 0 {
+		maxRows = defaultTableRows
+	}
+	maxRows = min(maxRows, maxTableRows)
+	rows := make([]map[string]interface{}, 0, min(len(entries), maxRows))
+	for _, entry := range entries[:min(len(entries), maxRows)] {
+		row, ok := entry.(map[string]interface{})
+		if !ok {
+			row = map[string]interface{}{"value": entry}
+		}
+		rows = append(rows, row)
+	}
+	if len(columns) == 0 {
+		columns = defaultTableColumns(rows)
+	}
+
+	var sb strings.Builder
+	if len(rows) == 0 || len(columns) == 0 {
+		sb.WriteString("_No rows._\n")
+		return sb.String(), nil
+	}
+
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = markdownCell(column)
+	}
+	sb.WriteString("| " + strings.Join(headers, " | ") + " |\n")
+	sb.WriteString("|" + strings.Repeat("---|", len(columns)) + "\n")
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = markdownCell(tableCell(lookupField(row, column)))
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	if len(rows) < len(entries) {
+		fmt.Fprintf(&sb, "\n_Showing %d of %d rows._\n", len(rows), len(entries))
+	}
+	return sb.String(), nil
+}
+
+// defaultTableColumns picks the scalar fields of the rows, the usual
+// identifying fields first
+func defaultTableColumns(rows []map[string]interface{}) []string {
+	scalar := map[string]bool{}
+	for _, row := range rows {
+		for key, value := range row {
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+			default:
+				scalar[key] = true
+			}
+		}
+	}
+
+	var columns []string
+	for _, key := range tableColumnPriority {
+		if scalar[key] {
+			columns = append(columns, key)
+			delete(scalar, key)
+		}
+	}
+	rest := make([]string, 0, len(scalar))
+	for key := range scalar {
+		rest = append(rest, key)
+	}
+	sort.Strings(rest)
+	columns = append(columns, rest...)
+	return columns[:min(len(columns), maxDefaultTableColumns)]
+}
+
+// lookupField returns the value at a dotted path in a row
+func lookupField(row map[string]interface{}, path string) interface{} {
+	var value interface{} = row
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+// tableCell formats a value for a table cell, as compact JSON for objects
+// and lists
+func tableCell(value interface{}) string {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		text = v
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		text = strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		text = string(data)
+	}
+	if runes := []rune(text); len(runes) > maxTableCellLength {
+		text = string(runes[:maxTableCellLength]) + "..."
+	}
+	return text
+}
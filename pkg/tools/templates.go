@@ -0,0 +1,364 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// Template variable formats
+const (
+	templateFormatIP       = "ip"
+	templateFormatHostname = "hostname"
+)
+
+var (
+	// templatePlaceholder matches a {{variable}} placeholder
+	templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	// templateNamePattern restricts template names
+	templateNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+	// hostnamePattern matches an RFC 1123 host name
+	hostnamePattern = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+)
+
+var (
+	configuredTemplates     map[string]common.ResourceTemplate
+	configuredTemplatesOnce sync.Once
+)
+
+// getConfiguredTemplates returns the resource templates defined in config,
+// loading them on first use
+func getConfiguredTemplates() map[string]common.ResourceTemplate {
+	configuredTemplatesOnce.Do(func() {
+		configuredTemplates = map[string]common.ResourceTemplate{}
+		config, err := common.LoadConfig("")
+		if err != nil {
+			return
+		}
+		for name, template := range config.ResourceTemplates {
+			template.Resource, _ = normalizeYAML(template.Resource).(map[string]interface{})
+			if err := validateResourceTemplate(name, template); err != nil {
+				common.GetLogger().Warn("Ignoring resource template %s: %v", name, err)
+				continue
+			}
+			configuredTemplates[name] = template
+		}
+	})
+	return configuredTemplates
+}
+
+// ResourceTemplates resolves resource templates from config and the state
+// store. Config templates take precedence and cannot be overwritten at runtime.
+type ResourceTemplates struct {
+	configured map[string]common.ResourceTemplate
+	store      *store.Store
+}
+
+// NewResourceTemplates creates a template catalog over the configured
+// templates and the default state store, which may be unavailable
+func NewResourceTemplates() *ResourceTemplates {
+	return &ResourceTemplates{
+		configured: getConfiguredTemplates(),
+		store:      store.Default(),
+	}
+}
+
+// List returns the available templates by name, with where each is defined
+func (t *ResourceTemplates) List() ([]ResourceTemplateInfo, error) {
+	templates := make([]ResourceTemplateInfo, 0, len(t.configured))
+	for name, template := range t.configured {
+		templates = append(templates, templateInfo(name, "config", template))
+	}
+
+	if t.store != nil {
+		err := t.store.ForEach(store.BucketResourceTemplates, func(name string, raw []byte) error {
+			if _, ok := t.configured[name]; ok {
+				return nil
+			}
+			var template common.ResourceTemplate
+			if err := json.Unmarshal(raw, &template); err != nil {
+				return fmt.Errorf("failed to decode resource template %s: %w", name, err)
+			}
+			templates = append(templates, templateInfo(name, "store", template))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// Get returns the named template
+func (t *ResourceTemplates) Get(name string) (common.ResourceTemplate, error) {
+	if template, ok := t.configured[name]; ok {
+		return template, nil
+	}
+	if t.store != nil {
+		var template common.ResourceTemplate
+		err := t.store.Get(store.BucketResourceTemplates, name, &template)
+		if err == nil {
+			return template, nil
+		}
+		if !errors.Is(err, store.ErrNotFound) {
+			return common.ResourceTemplate{}, err
+		}
+	}
+	return common.ResourceTemplate{}, fmt.Errorf("unknown resource template: %s (use listTemplates to see available templates)", name)
+}
+
+// Save validates a template and stores it under name
+func (t *ResourceTemplates) Save(name string, template common.ResourceTemplate) error {
+	if t.store == nil {
+		return fmt.Errorf("saving resource templates requires the state store")
+	}
+	if _, ok := t.configured[name]; ok {
+		return fmt.Errorf("resource template %s is defined in config and cannot be replaced", name)
+	}
+	if err := validateResourceTemplate(name, template); err != nil {
+		return err
+	}
+	return t.store.Put(store.BucketResourceTemplates, name, template)
+}
+
+// Render substitutes variables into the named template and returns the
+// resulting create request. Unknown, missing and malformed variables are
+// reported together.
+func (t *ResourceTemplates) Render(name string, variables map[string]string) (*types.ResourceCreateRequest, error) {
+	template, err := t.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := resolveTemplateVariables(template, variables)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+
+	request, err := decodeCreateRequest(substituteTemplate(template.Resource, values))
+	if err != nil {
+		return nil, fmt.Errorf("template %s does not render to a valid resource: %w", name, err)
+	}
+	return request, nil
+}
+
+// decodeCreateRequest converts a rendered template into a create request,
+// rejecting fields the request does not have
+func decodeCreateRequest(resource interface{}) (*types.ResourceCreateRequest, error) {
+	payload, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var request types.ResourceCreateRequest
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// resolveTemplateVariables applies defaults and validates the supplied values
+func resolveTemplateVariables(template common.ResourceTemplate, variables map[string]string) (map[string]string, error) {
+	var problems []string
+	for name := range variables {
+		if _, ok := template.Variables[name]; !ok {
+			problems = append(problems, fmt.Sprintf("unknown variable %q", name))
+		}
+	}
+
+	values := make(map[string]string, len(template.Variables))
+	for name, variable := range template.Variables {
+		value, ok := variables[name]
+		if !ok || value == "" {
+			value = variable.Default
+		}
+		if value == "" {
+			if variable.Required {
+				problems = append(problems, fmt.Sprintf("variable %q is required", name))
+			}
+			values[name] = ""
+			continue
+		}
+		if err := validateTemplateValue(variable, value); err != nil {
+			problems = append(problems, fmt.Sprintf("variable %q: %v", name, err))
+		}
+		values[name] = value
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return values, nil
+}
+
+// validateTemplateValue checks a value against its variable's format and pattern
+func validateTemplateValue(variable common.TemplateVariable, value string) error {
+	switch variable.Format {
+	case templateFormatIP:
+		if net.ParseIP(value) == nil {
+			return fmt.Errorf("%q is not an IP address", value)
+		}
+	case templateFormatHostname:
+		if len(value) > 253 || !hostnamePattern.MatchString(value) {
+			return fmt.Errorf("%q is not a valid host name", value)
+		}
+	}
+	if variable.Pattern != "" {
+		matched, err := regexp.MatchString("^(?:"+variable.Pattern+")$", value)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		if !matched {
+			return fmt.Errorf("%q does not match %s", value, variable.Pattern)
+		}
+	}
+	return nil
+}
+
+// validateResourceTemplate checks a template's name, variables and placeholders
+func validateResourceTemplate(name string, template common.ResourceTemplate) error {
+	if !templateNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid template name %q: use letters, digits, '.', '_' or '-'", name)
+	}
+	if len(template.Resource) == 0 {
+		return fmt.Errorf("template %s has no resource", name)
+	}
+	if resourceType, _ := template.Resource["resourceType"].(string); resourceType == "" {
+		return fmt.Errorf("template %s must set resourceType", name)
+	}
+	for variableName, variable := range template.Variables {
+		switch variable.Format {
+		case "", templateFormatIP, templateFormatHostname:
+		default:
+			return fmt.Errorf("template %s: variable %s has unknown format %q (expected ip or hostname)", name, variableName, variable.Format)
+		}
+		if variable.Pattern != "" {
+			if _, err := regexp.Compile(variable.Pattern); err != nil {
+				return fmt.Errorf("template %s: variable %s has an invalid pattern: %w", name, variableName, err)
+			}
+		}
+	}
+	for _, placeholder := range templatePlaceholders(template.Resource) {
+		if _, ok := template.Variables[placeholder]; !ok {
+			return fmt.Errorf("template %s uses undeclared variable %q", name, placeholder)
+		}
+	}
+	if _, err := decodeCreateRequest(template.Resource); err != nil {
+		return fmt.Errorf("template %s resource is not a valid create request: %w", name, err)
+	}
+	return nil
+}
+
+// templatePlaceholders returns the distinct placeholder names used in value
+func templatePlaceholders(value interface{}) []string {
+	seen := map[string]bool{}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case string:
+			for _, match := range templatePlaceholder.FindAllStringSubmatch(v, -1) {
+				seen[match[1]] = true
+			}
+		case map[string]interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// substituteTemplate returns a copy of value with placeholders replaced.
+// Fields that render to an empty string are dropped, so optional variables
+// left unset do not send empty values.
+func substituteTemplate(value interface{}, values map[string]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return templatePlaceholder.ReplaceAllStringFunc(v, func(match string) string {
+			return values[templatePlaceholder.FindStringSubmatch(match)[1]]
+		})
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			rendered := substituteTemplate(item, values)
+			if s, ok := rendered.(string); ok && s == "" {
+				continue
+			}
+			out[key] = rendered
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			out = append(out, substituteTemplate(item, values))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// normalizeYAML converts the map[interface{}]interface{} values produced by
+// the YAML decoder into JSON-compatible map[string]interface{} values
+func normalizeYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = normalizeYAML(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalizeYAML(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// ResourceTemplateInfo is a template as listed by listTemplates; Source is
+// "config" or "store"
+type ResourceTemplateInfo struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	common.ResourceTemplate
+}
+
+// templateInfo describes a template for listTemplates
+func templateInfo(name, source string, template common.ResourceTemplate) ResourceTemplateInfo {
+	return ResourceTemplateInfo{Name: name, Source: source, ResourceTemplate: template}
+}
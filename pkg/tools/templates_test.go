@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+	"gopkg.in/yaml.v2"
+)
+
+const testTemplatesYAML = `
+linux-server:
+  description: Linux server in a data center
+  variables:
+    hostname: {required: true, format: hostname}
+    ip: {required: true, format: ip}
+    location: {default: dc1, pattern: "dc[0-9]+"}
+    owner: {}
+  resource:
+    resourceType: Server
+    hostName: "{{hostname}}"
+    ipAddress: "{{ ip }}"
+    location: "{{location}}"
+    os: Linux
+    tags:
+      - name: owner
+        value: "{{owner}}"
+    properties:
+      fqdn: "{{hostname}}.example.com"
+`
+
+func newTestResourceTemplates(t *testing.T) *ResourceTemplates {
+	t.Helper()
+	var configured map[string]common.ResourceTemplate
+	if err := yaml.Unmarshal([]byte(testTemplatesYAML), &configured); err != nil {
+		t.Fatalf("Failed to parse templates: %v", err)
+	}
+	for name, template := range configured {
+		template.Resource, _ = normalizeYAML(template.Resource).(map[string]interface{})
+		if err := validateResourceTemplate(name, template); err != nil {
+			t.Fatalf("Template %s is invalid: %v", name, err)
+		}
+		configured[name] = template
+	}
+
+	s, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return &ResourceTemplates{configured: configured, store: s}
+}
+
+func TestResourceTemplatesRender(t *testing.T) {
+	templates := newTestResourceTemplates(t)
+
+	request, err := templates.Render("linux-server", map[string]string{"hostname": "web-01", "ip": "10.0.1.5", "owner": "ops"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if request.ResourceType != "Server" || request.HostName != "web-01" || request.IPAddress != "10.0.1.5" || request.Location != "dc1" || request.OS != "Linux" {
+		t.Errorf("Unexpected request: %+v", request)
+	}
+	if len(request.Tags) != 1 || request.Tags[0].Value != "ops" {
+		t.Errorf("Expected owner tag, got %+v", request.Tags)
+	}
+	if request.Properties["fqdn"] != "web-01.example.com" {
+		t.Errorf("Expected substituted property, got %v", request.Properties)
+	}
+}
+
+func TestResourceTemplatesRejectBadVariables(t *testing.T) {
+	templates := newTestResourceTemplates(t)
+
+	_, err := templates.Render("linux-server", map[string]string{"hostname": "web_01!", "location": "nyc", "colour": "red"})
+	if err == nil {
+		t.Fatal("Expected invalid variables to be rejected")
+	}
+	for _, want := range []string{`unknown variable "colour"`, `variable "ip" is required`, "not a valid host name", "does not match"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in error: %v", want, err)
+		}
+	}
+
+	if _, err := templates.Render("missing", nil); err == nil || !strings.Contains(err.Error(), "unknown resource template") {
+		t.Errorf("Expected unknown template error, got %v", err)
+	}
+}
+
+func TestResourceTemplatesSave(t *testing.T) {
+	templates := newTestResourceTemplates(t)
+
+	switchTemplate := common.ResourceTemplate{
+		Variables: map[string]common.TemplateVariable{"name": {Required: true}},
+		Resource:  map[string]interface{}{"resourceType": "Switch", "hostName": "{{name}}"},
+	}
+	if err := templates.Save("switch", switchTemplate); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := templates.Save("linux-server", switchTemplate); err == nil {
+		t.Error("Expected config templates to be protected")
+	}
+
+	undeclared := common.ResourceTemplate{Resource: map[string]interface{}{"resourceType": "Switch", "hostName": "{{name}}"}}
+	if err := templates.Save("bad", undeclared); err == nil || !strings.Contains(err.Error(), "undeclared variable") {
+		t.Errorf("Expected undeclared variable error, got %v", err)
+	}
+	typo := common.ResourceTemplate{Resource: map[string]interface{}{"resourceType": "Switch", "hostNmae": "sw-01"}}
+	if err := templates.Save("typo", typo); err == nil || !strings.Contains(err.Error(), "unknown field") {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+
+	list, err := templates.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "linux-server" || list[0].Source != "config" || list[1].Name != "switch" || list[1].Source != "store" {
+		t.Errorf("Unexpected template list: %+v", list)
+	}
+
+	request, err := templates.Render("switch", map[string]string{"name": "sw-01"})
+	if err != nil || request.HostName != "sw-01" || request.ResourceType != "Switch" {
+		t.Errorf("Unexpected render of saved template: %+v, %v", request, err)
+	}
+}
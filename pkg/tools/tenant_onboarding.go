@@ -0,0 +1,166 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+)
+
+// TenantOnboarding is the result of the onboardTenant admin action
+type TenantOnboarding struct {
+	Tenant    string `json:"tenant"`
+	Onboarded bool   `json:"onboarded"`
+	// Persisted reports whether the tenant was saved to be restored on restart
+	Persisted   bool                     `json:"persisted"`
+	Credentials client.CredentialCheck   `json:"credentials"`
+	Permissions []client.PermissionCheck `json:"permissions,omitempty"`
+	// Denied lists the tools with endpoints the credentials may not call
+	Denied   []string          `json:"denied,omitempty"`
+	Catalogs map[string]string `json:"catalogs,omitempty"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// TenantOnboarder adds tenants to a running server. Onboarded tenants are
+// saved to the state store, which may be unavailable, and restored on
+// restart by RestoreTenants.
+type TenantOnboarder struct {
+	registry *client.TenantRegistry
+	config   *common.Config
+	store    *store.Store
+}
+
+// NewTenantOnboarder creates an onboarder for the global tenant registry and
+// the default state store
+func NewTenantOnboarder() (*TenantOnboarder, error) {
+	registry := client.GetTenantRegistry()
+	if registry == nil {
+		return nil, errors.New("tenant registry is not available; onboarding needs the server's tenant registry")
+	}
+	config, err := common.LoadConfig("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return &TenantOnboarder{registry: registry, config: config, store: store.Default()}, nil
+}
+
+// Onboard validates a new tenant's credentials, checks which endpoints they
+// may call, registers the tenant and warms its catalogs. A tenant whose
+// credentials fail validation is not registered; the report says why.
+func (o *TenantOnboarder) Onboard(ctx context.Context, tenant common.TenantConfig) (*TenantOnboarding, error) {
+	if err := o.checkName(tenant.Name); err != nil {
+		return nil, err
+	}
+	if err := o.checkEgress(tenant.OpsRampConfig); err != nil {
+		return nil, err
+	}
+	if err := common.PrepareTenant(&tenant); err != nil {
+		return nil, err
+	}
+
+	report := &TenantOnboarding{Tenant: tenant.Name}
+	report.Credentials = client.NewCredentialValidator(o.config.Egress).Validate(ctx, tenant.Name, tenant.OpsRampConfig)
+	if !report.Credentials.Valid {
+		return report, nil
+	}
+
+	// Check permissions with a client of its own so a tenant is only
+	// registered once it is known to work
+	checker := client.NewOpsRampClient(&common.Config{OpsRamp: tenant.OpsRampConfig, Egress: o.config.Egress})
+	report.Permissions = checker.CheckPermissions(ctx)
+	denied := map[string]bool{}
+	for _, check := range report.Permissions {
+		if !check.Allowed && check.Status != 0 {
+			denied[check.Tool] = true
+		}
+	}
+	for tool := range denied {
+		report.Denied = append(report.Denied, tool)
+	}
+	sort.Strings(report.Denied)
+	if len(report.Denied) > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("The credentials may not call some endpoints of: %v; those calls will fail for this tenant", report.Denied))
+	}
+
+	registered := o.registry.AddTenant(tenant.Name, tenant.OpsRampConfig)
+	report.Onboarded = true
+
+	if o.store == nil {
+		report.Warnings = append(report.Warnings, "State store unavailable: the tenant is lost on restart unless added to the config file")
+	} else if err := o.store.Put(store.BucketTenants, tenant.Name, tenant); err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Failed to save the tenant, it is lost on restart: %v", err))
+	} else {
+		report.Persisted = true
+	}
+
+	report.Catalogs = registered.Client.WarmCatalogs(ctx)
+	common.GetLogger().Info("Onboarded tenant %s (%s): %d tools with denied endpoints", tenant.Name, tenant.TenantURL, len(report.Denied))
+	return report, nil
+}
+
+// checkName rejects a missing, reserved or already registered tenant name
+func (o *TenantOnboarder) checkName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("tenant name is required")
+	case name == client.DefaultTenant:
+		return fmt.Errorf("tenant name '%s' is reserved for the opsramp section", client.DefaultTenant)
+	}
+	if _, ok := o.registry.Lookup(name); ok {
+		return fmt.Errorf("tenant %s is already registered", name)
+	}
+	return nil
+}
+
+// checkEgress rejects tenant and auth URLs outside the server's egress
+// allowlist, so onboarding cannot be used to reach arbitrary hosts
+func (o *TenantOnboarder) checkEgress(config common.OpsRampConfig) error {
+	policy := client.NewEgressPolicy(o.config)
+	for _, raw := range []string{config.TenantURL, config.AuthURL} {
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" {
+			// Credential validation reports malformed URLs
+			continue
+		}
+		if !policy.Allows(u.Hostname()) {
+			return fmt.Errorf("host %s is not in the egress allowlist; add it to egress.allowed_hosts to onboard this tenant", u.Hostname())
+		}
+	}
+	return nil
+}
+
+// RestoreTenants registers the tenants onboarded before a restart and
+// returns their names. Tenants now also defined in the config file keep
+// the config file's settings, and unreadable entries are skipped.
+func RestoreTenants(registry *client.TenantRegistry, s *store.Store) ([]string, error) {
+	if registry == nil || s == nil {
+		return nil, nil
+	}
+
+	var restored []string
+	err := s.ForEach(store.BucketTenants, func(key string, raw []byte) error {
+		if _, ok := registry.Lookup(key); ok {
+			common.GetLogger().Warn("Onboarded tenant %s is also configured; using the config file", key)
+			return nil
+		}
+		var tenant common.TenantConfig
+		err := json.Unmarshal(raw, &tenant)
+		if err == nil {
+			err = common.PrepareTenant(&tenant)
+		}
+		if err != nil {
+			common.GetLogger().Warn("Skipping onboarded tenant %s: %v", key, err)
+			return nil
+		}
+		registry.AddTenant(key, tenant.OpsRampConfig)
+		restored = append(restored, key)
+		return nil
+	})
+	return restored, err
+}
@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+)
+
+// newOnboardingTestServer serves an OpsRamp tenant client_1 whose credentials
+// are key-1 and secret-1 and which may not search alerts
+func newOnboardingTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.FormValue("client_id") != "key-1" || r.FormValue("client_secret") != "secret-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client","error_description":"Bad client credentials"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"token-1","token_type":"bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("/api/v2/tenants/client_1/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/alerts/search"):
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"code":"0003","message":"Access denied"}`))
+		case strings.HasSuffix(r.URL.Path, "/resources/types"):
+			w.Write([]byte(`{"resourceTypes":[{"name":"server"}]}`))
+		default:
+			w.Write([]byte(`{"results":[],"totalResults":0}`))
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestOnboarder(t *testing.T, allowedHosts ...string) (*TenantOnboarder, *store.Store) {
+	t.Helper()
+	s, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	config := &common.Config{
+		OpsRamp: common.OpsRampConfig{TenantURL: "https://primary.example.com", TenantID: "client_0", Environment: "production"},
+		Egress:  common.EgressConfig{AllowedHosts: allowedHosts},
+	}
+	return &TenantOnboarder{registry: client.NewTenantRegistry(config), config: config, store: s}, s
+}
+
+func onboardingTenant(server *httptest.Server) common.TenantConfig {
+	return common.TenantConfig{
+		Name: "acme",
+		OpsRampConfig: common.OpsRampConfig{
+			TenantURL:  server.URL,
+			AuthURL:    server.URL + "/auth/oauth/token",
+			AuthKey:    "key-1",
+			AuthSecret: "secret-1",
+			TenantID:   "client_1",
+		},
+	}
+}
+
+func TestOnboardTenant(t *testing.T) {
+	server := newOnboardingTestServer(t)
+	onboarder, s := newTestOnboarder(t, "127.0.0.1")
+
+	report, err := onboarder.Onboard(context.Background(), onboardingTenant(server))
+	if err != nil {
+		t.Fatalf("Onboard failed: %v", err)
+	}
+	if !report.Onboarded || !report.Persisted || !report.Credentials.Valid {
+		t.Fatalf("Expected the tenant to be onboarded and saved, got %+v", report)
+	}
+	if !reflect.DeepEqual(report.Denied, []string{"alerts"}) {
+		t.Errorf("Expected alerts to be denied, got %v", report.Denied)
+	}
+	if report.Catalogs["resources.types"] != string(client.CatalogFetched) {
+		t.Errorf("Expected the resource types catalog to be warmed, got %v", report.Catalogs)
+	}
+	tenant, ok := onboarder.registry.Lookup("acme")
+	if !ok {
+		t.Fatal("Expected acme to be registered")
+	}
+	if tenant.Config.ServiceAccount != common.DefaultServiceAccount {
+		t.Errorf("Expected tenant defaults to be applied, got service account %q", tenant.Config.ServiceAccount)
+	}
+	if tenant.Config.Environment != "production" {
+		t.Errorf("Expected acme to inherit the primary environment, got %q", tenant.Config.Environment)
+	}
+
+	// A restarted server restores the tenant from the state store
+	restarted, _ := newTestOnboarder(t)
+	restored, err := RestoreTenants(restarted.registry, s)
+	if err != nil || !reflect.DeepEqual(restored, []string{"acme"}) {
+		t.Fatalf("Expected acme to be restored, got %v, %v", restored, err)
+	}
+	if tenant, ok := restarted.registry.Lookup("acme"); !ok || tenant.Config.AuthSecret != "secret-1" {
+		t.Errorf("Expected acme to be registered with its credentials, got %+v", tenant)
+	}
+
+	if _, err := onboarder.Onboard(context.Background(), onboardingTenant(server)); err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("Expected onboarding acme again to fail, got %v", err)
+	}
+}
+
+func TestOnboardTenantRefusals(t *testing.T) {
+	server := newOnboardingTestServer(t)
+
+	onboarder, _ := newTestOnboarder(t, "127.0.0.1")
+	reserved := onboardingTenant(server)
+	reserved.Name = client.DefaultTenant
+	if _, err := onboarder.Onboard(context.Background(), reserved); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("Expected the default name to be refused, got %v", err)
+	}
+
+	badSecret := onboardingTenant(server)
+	badSecret.AuthSecret = "secret-2"
+	report, err := onboarder.Onboard(context.Background(), badSecret)
+	if err != nil {
+		t.Fatalf("Onboard failed: %v", err)
+	}
+	if report.Onboarded || report.Credentials.Failure != client.CredentialsBadSecret {
+		t.Errorf("Expected a bad secret to stop onboarding, got %+v", report)
+	}
+	if _, ok := onboarder.registry.Lookup("acme"); ok {
+		t.Error("Expected acme not to be registered with bad credentials")
+	}
+
+	// Onboarding must not reach hosts outside the egress allowlist
+	unlisted, _ := newTestOnboarder(t)
+	if _, err := unlisted.Onboard(context.Background(), onboardingTenant(server)); err == nil || !strings.Contains(err.Error(), "egress allowlist") {
+		t.Errorf("Expected an unlisted host to be refused, got %v", err)
+	}
+}
+
+func TestRedactArguments(t *testing.T) {
+	args := map[string]interface{}{"action": "onboardTenant", "authKey": "key-1", "authSecret": "secret-1"}
+	redacted := redactArguments(args, "authKey", "authSecret", "token")
+	if redacted["authKey"] != "[REDACTED]" || redacted["authSecret"] != "[REDACTED]" || redacted["action"] != "onboardTenant" {
+		t.Errorf("Unexpected redacted arguments %v", redacted)
+	}
+	if _, ok := redacted["token"]; ok {
+		t.Error("Expected absent arguments to stay absent")
+	}
+	if args["authSecret"] != "secret-1" {
+		t.Error("Expected the request arguments to be left unchanged")
+	}
+}
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+)
+
+// tenantArgument is the tool input schema entry for selecting a tenant in multi-tenant mode
+var tenantArgument = map[string]interface{}{
+	"type":        "string",
+	"description": "Tenant to target in multi-tenant mode (defaults to the primary tenant)",
+}
+
+// tenantIntegrationsAPIs caches integrations API clients for non-default tenants
+var tenantIntegrationsAPIs sync.Map
+
+// tenantResourcesAPIs caches resources API clients for non-default tenants,
+// so their retry and circuit breaker state outlives a single call
+var tenantResourcesAPIs sync.Map
+
+// resolveTenant returns the tenant targeted by the request's tenant argument.
+// It returns nil when the request targets the default tenant or no tenant
+// registry is configured, and an error when the tenant is unknown or degraded.
+func resolveTenant(req mcp.CallToolRequest) (*client.Tenant, error) {
+	return lookupTenant(req.GetString("tenant", ""))
+}
+
+// lookupTenant returns the named tenant, or nil for the default tenant
+func lookupTenant(name string) (*client.Tenant, error) {
+	registry := client.GetTenantRegistry()
+	if registry == nil {
+		if name != "" && name != client.DefaultTenant {
+			return nil, fmt.Errorf("unknown tenant: %s (multi-tenant mode is not configured)", name)
+		}
+		return nil, nil
+	}
+
+	tenant, err := registry.Tenant(name)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.Name == client.DefaultTenant {
+		return nil, nil
+	}
+	return tenant, nil
+}
+
+// resourcesAPIForRequest returns the resources API for the tenant targeted by the request
+func resourcesAPIForRequest(req mcp.CallToolRequest, defaultAPI ResourcesAPI) (ResourcesAPI, error) {
+	tenant, err := resolveTenant(req)
+	if err != nil || tenant == nil {
+		return defaultAPI, err
+	}
+
+	// A tenant onboarded again gets a new client, and with it a new API
+	if cached, ok := tenantResourcesAPIs.Load(tenant.Name); ok && cached.(*OpsRampResourcesAPI).client == tenant.Client {
+		return cached.(ResourcesAPI), nil
+	}
+	api := NewOpsRampResourcesAPI(tenant.Client)
+	tenantResourcesAPIs.Store(tenant.Name, api)
+	return api, nil
+}
+
+// integrationsAPIForRequest returns the integrations API for the tenant targeted by the request
+func integrationsAPIForRequest(req mcp.CallToolRequest, defaultAPI IntegrationsAPI) (IntegrationsAPI, error) {
+	return integrationsAPIForTenant(req.GetString("tenant", ""), defaultAPI)
+}
+
+// integrationsAPIForTenant returns the integrations API for the named tenant
+func integrationsAPIForTenant(name string, defaultAPI IntegrationsAPI) (IntegrationsAPI, error) {
+	tenant, err := lookupTenant(name)
+	if err != nil || tenant == nil {
+		return defaultAPI, err
+	}
+
+	if cached, ok := tenantIntegrationsAPIs.Load(tenant.Name); ok {
+		return cached.(IntegrationsAPI), nil
+	}
+
+	api, err := NewOpsRampIntegrationsAPI(&tenant.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize integrations API for tenant %s: %w", tenant.Name, err)
+	}
+	api.logger = tenant.Client.Logger().WithModule(toolModule("integrations"))
+	actual, _ := tenantIntegrationsAPIs.LoadOrStore(tenant.Name, api)
+	return actual.(IntegrationsAPI), nil
+}
+
+// tenantErrorResult converts a tenant resolution error into a tool error result
+func tenantErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Error: %v", err)}},
+	}
+}
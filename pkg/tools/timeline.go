@@ -0,0 +1,459 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// Timeline limits
+const (
+	// maxTimelineResources bounds the service group members a timeline covers
+	maxTimelineResources = 25
+	// maxTimelineAlerts bounds the alerts a timeline scans
+	maxTimelineAlerts = 1000
+	// timelinePageSize is the page size used to scan members and alerts
+	timelinePageSize = 500
+	// anomalyMinSamples is the fewest samples a metric needs for anomaly detection
+	anomalyMinSamples = 12
+	// anomalyDeviations is how many standard deviations from the mean count as an anomaly
+	anomalyDeviations = 3.0
+)
+
+// Timeline scopes
+const (
+	timelineScopeResource     = "resource"
+	timelineScopeServiceGroup = "serviceGroup"
+)
+
+// MaintenanceProvider is implemented by resources APIs that can report the
+// maintenance windows scheduled for a resource
+type MaintenanceProvider interface {
+	GetMaintenanceWindows(ctx context.Context, id string) ([]types.MaintenanceWindow, error)
+}
+
+// TimelineBuilder assembles postmortem timelines of a resource or service
+// group from its alerts, availability changes, maintenance windows and
+// metric anomalies
+type TimelineBuilder struct {
+	resources   ResourcesAPI
+	alerts      AlertsAPI
+	maintenance MaintenanceProvider
+	now         func() time.Time
+	logger      *common.CustomLogger
+}
+
+// NewTimelineBuilder creates a TimelineBuilder. Alert and maintenance events
+// are reported as unavailable when the resources API does not provide them.
+func NewTimelineBuilder(resources ResourcesAPI) *TimelineBuilder {
+	b := &TimelineBuilder{
+		resources: resources,
+		now:       time.Now,
+		logger:    common.GetLogger(),
+	}
+	if provider, ok := resources.(AlertsProvider); ok {
+		b.alerts = provider.Alerts()
+	}
+	if provider, ok := resources.(MaintenanceProvider); ok {
+		b.maintenance = provider
+	}
+	return b
+}
+
+// timelineMember is a resource covered by a timeline
+type timelineMember struct {
+	id   string
+	name string
+}
+
+// BuildForResource assembles the timeline of one resource
+func (b *TimelineBuilder) BuildForResource(ctx context.Context, id string, timeRange TimeRange) (*types.TimelineReport, error) {
+	resource, err := b.resources.GetDetailed(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource %s: %w", id, err)
+	}
+	report := b.newReport(timelineScopeResource, id, displayName(resource.Name, resource.HostName), timeRange)
+	return b.build(ctx, report, []timelineMember{{id: id, name: report.Name}}, timeRange), nil
+}
+
+// BuildForServiceGroup assembles the timeline of the members of a service
+// group, covering at most maxTimelineResources of them
+func (b *TimelineBuilder) BuildForServiceGroup(ctx context.Context, group string, timeRange TimeRange) (*types.TimelineReport, error) {
+	report := b.newReport(timelineScopeServiceGroup, group, group, timeRange)
+
+	var members []timelineMember
+	it := SearchResourcesIter(ctx, b.resources, types.ResourceSearchParams{ServiceGroup: group, PageSize: timelinePageSize})
+	for it.Next() {
+		if len(members) == maxTimelineResources {
+			report.Truncated = true
+			break
+		}
+		resource := it.Value()
+		members = append(members, timelineMember{id: resource.ID, name: displayName(resource.Name, resource.HostName)})
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list members of service group %s: %w", group, err)
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("service group %s has no resources", group)
+	}
+	return b.build(ctx, report, members, timeRange), nil
+}
+
+// newReport creates an empty report for the time range
+func (b *TimelineBuilder) newReport(scope, id, name string, timeRange TimeRange) *types.TimelineReport {
+	return &types.TimelineReport{
+		Scope:       scope,
+		ID:          id,
+		Name:        name,
+		Start:       timeRange.Start.UTC().Format(time.RFC3339),
+		End:         timeRange.End.UTC().Format(time.RFC3339),
+		GeneratedAt: b.now().UTC().Format(time.RFC3339),
+		Counts:      map[types.TimelineEventKind]int{},
+		Unavailable: map[string]string{},
+	}
+}
+
+// build fetches every event source concurrently and merges the events in
+// time order. A failing source is recorded in Unavailable rather than
+// failing the report.
+func (b *TimelineBuilder) build(ctx context.Context, report *types.TimelineReport, members []timelineMember, timeRange TimeRange) *types.TimelineReport {
+	sources := map[string]func(context.Context, []timelineMember, TimeRange) ([]timelineEvent, bool, error){
+		"alerts":       b.alertEvents,
+		"stateChanges": b.stateEvents,
+		"maintenance":  b.maintenanceEvents,
+		"anomalies":    b.anomalyEvents,
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		events []timelineEvent
+	)
+	for name, source := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found, truncated, err := source(ctx, members, timeRange)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				b.logger.Warn("Timeline for %s %s: %s unavailable: %v", report.Scope, report.ID, name, err)
+				report.Unavailable[name] = err.Error()
+			}
+			report.Truncated = report.Truncated || truncated
+			events = append(events, found...)
+		}()
+	}
+	wg.Wait()
+
+	// Ties keep a fixed order so repeated reports read the same
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].at.Equal(events[j].at) {
+			return events[i].at.Before(events[j].at)
+		}
+		if events[i].Kind != events[j].Kind {
+			return events[i].Kind < events[j].Kind
+		}
+		return events[i].Reference < events[j].Reference
+	})
+
+	report.Resources = len(members)
+	report.Events = make([]types.TimelineEvent, 0, len(events))
+	for _, event := range events {
+		event.Time = event.at.UTC().Format(time.RFC3339)
+		report.Events = append(report.Events, event.TimelineEvent)
+		report.Counts[event.Kind]++
+	}
+	if len(report.Unavailable) == 0 {
+		report.Unavailable = nil
+	}
+	report.Report = timelineMarkdown(report)
+	return report
+}
+
+// timelineEvent is a timeline event with its parsed time
+type timelineEvent struct {
+	types.TimelineEvent
+	at time.Time
+}
+
+// alertEvents returns the alerts raised on the members within the time range
+func (b *TimelineBuilder) alertEvents(ctx context.Context, members []timelineMember, timeRange TimeRange) ([]timelineEvent, bool, error) {
+	if b.alerts == nil {
+		return nil, false, fmt.Errorf("alerts are not supported by this resources backend")
+	}
+
+	ids := make([]string, 0, len(members))
+	names := make(map[string]string, len(members))
+	for _, member := range members {
+		ids = append(ids, member.id)
+		names[member.id] = member.name
+	}
+
+	var events []timelineEvent
+	it := SearchAlertsIter(ctx, b.alerts, types.AlertSearchParams{
+		QueryString: alertSearchQuery("resources.id:"+strings.Join(ids, ","), timeRange),
+		PageSize:    timelinePageSize,
+	})
+	for scanned := 0; it.Next(); scanned++ {
+		if scanned == maxTimelineAlerts {
+			return events, true, nil
+		}
+		alert := it.Value()
+		created, err := parseAlertTime(alert.CreatedDate)
+		if err != nil || !timeRange.contains(created) {
+			continue
+		}
+		summary := defaultString(alert.Subject, "Alert raised")
+		if alert.RepeatCount > 1 {
+			summary = fmt.Sprintf("%s (repeated %d times)", summary, alert.RepeatCount)
+		}
+		event := timelineEvent{at: created, TimelineEvent: types.TimelineEvent{
+			Kind:      types.TimelineEventAlert,
+			Severity:  strings.ToUpper(alert.CurrentState),
+			Summary:   summary,
+			Reference: alert.ID,
+		}}
+		if alert.Resource != nil {
+			event.ResourceID = alert.Resource.ID
+			event.ResourceName = defaultString(names[alert.Resource.ID], alert.Resource.Name)
+		}
+		events = append(events, event)
+	}
+	return events, false, it.Err()
+}
+
+// stateEvents returns the availability changes of the members within the
+// time range. OpsRamp reports only when the current availability began, so
+// at most one change per member is known.
+func (b *TimelineBuilder) stateEvents(ctx context.Context, members []timelineMember, timeRange TimeRange) ([]timelineEvent, bool, error) {
+	var events []timelineEvent
+	for _, member := range members {
+		resource, err := b.resources.GetDetailed(ctx, member.id)
+		if err != nil {
+			return events, false, err
+		}
+		since, err := time.Parse(time.RFC3339, resource.UpDownSince)
+		if err != nil || resource.AvailabilityStatus == "" || !timeRange.contains(since) {
+			continue
+		}
+		events = append(events, timelineEvent{at: since, TimelineEvent: types.TimelineEvent{
+			Kind:         types.TimelineEventState,
+			ResourceID:   member.id,
+			ResourceName: member.name,
+			Summary:      "Availability changed to " + strings.ToUpper(resource.AvailabilityStatus),
+		}})
+	}
+	return events, false, nil
+}
+
+// maintenanceEvents returns the starts and ends of maintenance windows of
+// the members that fall within the time range
+func (b *TimelineBuilder) maintenanceEvents(ctx context.Context, members []timelineMember, timeRange TimeRange) ([]timelineEvent, bool, error) {
+	if b.maintenance == nil {
+		return nil, false, fmt.Errorf("maintenance windows are not supported by this resources backend")
+	}
+
+	var events []timelineEvent
+	seen := map[string]bool{}
+	for _, member := range members {
+		windows, err := b.maintenance.GetMaintenanceWindows(ctx, member.id)
+		if err != nil {
+			return events, false, err
+		}
+		for _, window := range windows {
+			// A window covering several members is reported once, against the first
+			if seen[window.ID] {
+				continue
+			}
+			seen[window.ID] = true
+			name := displayName(window.Name, window.ID)
+			for _, boundary := range []struct {
+				value string
+				verb  string
+			}{{window.StartTime, "started"}, {window.EndTime, "ended"}} {
+				at, err := parseAlertTime(boundary.value)
+				if err != nil || !timeRange.contains(at) {
+					continue
+				}
+				events = append(events, timelineEvent{at: at, TimelineEvent: types.TimelineEvent{
+					Kind:         types.TimelineEventMaintenance,
+					ResourceID:   member.id,
+					ResourceName: member.name,
+					Summary:      fmt.Sprintf("Maintenance window %s %s", name, boundary.verb),
+					Reference:    window.ID,
+				}})
+			}
+		}
+	}
+	return events, false, nil
+}
+
+// anomalyEvents returns the CPU, memory and disk utilization anomalies of
+// the members within the time range
+func (b *TimelineBuilder) anomalyEvents(ctx context.Context, members []timelineMember, timeRange TimeRange) ([]timelineEvent, bool, error) {
+	names := make([]string, 0, len(scorecardMetrics))
+	for _, name := range scorecardMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var events []timelineEvent
+	for _, member := range members {
+		response, err := b.resources.GetMetrics(ctx, member.id, types.ResourceMetricsRequest{
+			MetricNames: names,
+			StartTime:   timeRange.Start.UTC().Format(time.RFC3339),
+			EndTime:     timeRange.End.UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			return events, false, err
+		}
+		for _, event := range detectAnomalies(response.Metrics) {
+			event.ResourceID, event.ResourceName = member.id, member.name
+			events = append(events, event)
+		}
+	}
+	return events, false, nil
+}
+
+// detectAnomalies finds the runs of samples of each metric series that lie
+// more than anomalyDeviations standard deviations from the series mean, and
+// returns one event per run at its first sample
+func detectAnomalies(points []types.ResourceMetricDataPoint) []timelineEvent {
+	type sample struct {
+		at    time.Time
+		value float64
+		unit  string
+	}
+	series := map[string][]sample{}
+	var keys []string
+	for _, point := range points {
+		at, err := time.Parse(time.RFC3339, point.Timestamp)
+		if err != nil {
+			continue
+		}
+		key := point.Name
+		if point.Instance != "" {
+			key += " (" + point.Instance + ")"
+		}
+		if _, ok := series[key]; !ok {
+			keys = append(keys, key)
+		}
+		series[key] = append(series[key], sample{at: at, value: point.Value, unit: point.Unit})
+	}
+
+	var events []timelineEvent
+	for _, key := range keys {
+		samples := series[key]
+		if len(samples) < anomalyMinSamples {
+			continue
+		}
+		sort.SliceStable(samples, func(i, j int) bool { return samples[i].at.Before(samples[j].at) })
+
+		var sum, squares float64
+		for _, s := range samples {
+			sum += s.value
+		}
+		mean := sum / float64(len(samples))
+		for _, s := range samples {
+			squares += (s.value - mean) * (s.value - mean)
+		}
+		deviation := math.Sqrt(squares / float64(len(samples)))
+		if deviation == 0 {
+			continue
+		}
+
+		for i := 0; i < len(samples); i++ {
+			if math.Abs(samples[i].value-mean) <= anomalyDeviations*deviation {
+				continue
+			}
+			start, peak := i, samples[i]
+			for i+1 < len(samples) && math.Abs(samples[i+1].value-mean) > anomalyDeviations*deviation {
+				i++
+				if math.Abs(samples[i].value-mean) > math.Abs(peak.value-mean) {
+					peak = samples[i]
+				}
+			}
+			direction := "spiked"
+			if peak.value < mean {
+				direction = "dropped"
+			}
+			events = append(events, timelineEvent{at: samples[start].at, TimelineEvent: types.TimelineEvent{
+				Kind: types.TimelineEventAnomaly,
+				Summary: fmt.Sprintf("%s %s to %s%s (mean %s%s) for %d sample(s)", key, direction,
+					formatMetricValue(peak.value), peak.unit, formatMetricValue(mean), peak.unit, i-start+1),
+			}})
+		}
+	}
+	return events
+}
+
+// formatMetricValue formats a metric value with at most one decimal
+func formatMetricValue(value float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0")
+}
+
+// timelineKindLabels are the event column labels of the Markdown report
+var timelineKindLabels = map[types.TimelineEventKind]string{
+	types.TimelineEventAlert:       "Alert",
+	types.TimelineEventState:       "State change",
+	types.TimelineEventMaintenance: "Maintenance",
+	types.TimelineEventAnomaly:     "Metric anomaly",
+}
+
+// timelineMarkdown renders a timeline as a Markdown heading and table
+func timelineMarkdown(report *types.TimelineReport) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### Timeline: %s (%s to %s UTC)\n\n", displayName(report.Name, report.ID),
+		strings.Replace(strings.TrimSuffix(report.Start, "Z"), "T", " ", 1),
+		strings.Replace(strings.TrimSuffix(report.End, "Z"), "T", " ", 1))
+
+	if len(report.Events) == 0 {
+		sb.WriteString("No events were recorded in this window.\n")
+	} else {
+		sb.WriteString("| Time (UTC) | Resource | Event | Details |\n|---|---|---|---|\n")
+		for _, event := range report.Events {
+			label := timelineKindLabels[event.Kind]
+			if event.Severity != "" {
+				label += " (" + event.Severity + ")"
+			}
+			details := event.Summary
+			if event.Reference != "" {
+				details += " [" + event.Reference + "]"
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n",
+				strings.Replace(strings.TrimSuffix(event.Time, "Z"), "T", " ", 1),
+				markdownCell(displayName(event.ResourceName, event.ResourceID)), label, markdownCell(details))
+		}
+	}
+
+	var notes []string
+	if report.Truncated {
+		notes = append(notes, fmt.Sprintf("the timeline is incomplete: at most %d resources and %d alerts are covered", maxTimelineResources, maxTimelineAlerts))
+	}
+	sources := make([]string, 0, len(report.Unavailable))
+	for source := range report.Unavailable {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		notes = append(notes, fmt.Sprintf("%s could not be retrieved: %s", source, report.Unavailable[source]))
+	}
+	for _, note := range notes {
+		sb.WriteString("\n_Note: " + note + "._\n")
+	}
+	return sb.String()
+}
+
+// markdownCell escapes a value for use in a Markdown table cell
+func markdownCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+}
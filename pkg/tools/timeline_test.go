@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// timelineResourcesAPI serves a fixed outage of every resource: a maintenance
+// window, a CPU spike, an alert and a transition to DOWN; unused ResourcesAPI
+// methods panic
+type timelineResourcesAPI struct {
+	ResourcesAPI
+	alerts     *mockAlertsAPI
+	members    []types.Resource
+	metricsErr error
+}
+
+func (m *timelineResourcesAPI) GetDetailed(ctx context.Context, id string) (*types.DetailedResource, error) {
+	if id == "missing" {
+		return nil, fmt.Errorf("resource not found")
+	}
+	resource := &types.DetailedResource{AvailabilityStatus: "down", UpDownSince: "2026-10-16T03:00:00Z"}
+	resource.ID, resource.Name = id, "web-01"
+	return resource, nil
+}
+
+func (m *timelineResourcesAPI) Search(ctx context.Context, params types.ResourceSearchParams) (*types.ResourceSearchResponse, error) {
+	return &types.ResourceSearchResponse{Results: m.members}, nil
+}
+
+func (m *timelineResourcesAPI) GetMetrics(ctx context.Context, id string, req types.ResourceMetricsRequest) (*types.ResourceMetricsResponse, error) {
+	if m.metricsErr != nil {
+		return nil, m.metricsErr
+	}
+	response := &types.ResourceMetricsResponse{ResourceID: id}
+	for hour := 0; hour < 12; hour++ {
+		value := 30.0
+		if hour == 2 {
+			value = 95
+		}
+		response.Metrics = append(response.Metrics, types.ResourceMetricDataPoint{
+			Name:      "system.cpu.utilization",
+			Timestamp: time.Date(2026, 10, 16, hour, 0, 0, 0, time.UTC).Format(time.RFC3339),
+			Value:     value,
+			Unit:      "%",
+		})
+	}
+	return response, nil
+}
+
+func (m *timelineResourcesAPI) Alerts() AlertsAPI {
+	return m.alerts
+}
+
+func (m *timelineResourcesAPI) GetMaintenanceWindows(ctx context.Context, id string) ([]types.MaintenanceWindow, error) {
+	return []types.MaintenanceWindow{
+		{ID: "mw-1", Name: "Kernel patching", StartTime: "2026-10-16T01:00:00Z", EndTime: "2026-10-16T01:30:00Z"},
+		{ID: "mw-0", Name: "Last month", StartTime: "2026-09-16T01:00:00Z", EndTime: "2026-09-16T01:30:00Z"},
+	}, nil
+}
+
+// timelineWindow is the time range of the test timelines
+var timelineWindow = TimeRange{
+	Start: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+	End:   time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+}
+
+func TestTimelineForResource(t *testing.T) {
+	api := &timelineResourcesAPI{alerts: &mockAlertsAPI{open: []types.AlertDetails{
+		{ID: "a1", Subject: "CPU high | web", CurrentState: "critical", CreatedDate: "2026-10-16T02:05:00+0000",
+			RepeatCount: 3, Resource: &types.AlertResource{ID: "r1", Name: "web-01"}},
+		{ID: "a0", Subject: "Before the window", CurrentState: "WARNING", CreatedDate: "2026-10-15T20:00:00+0000"},
+	}}}
+
+	report, err := NewTimelineBuilder(api).BuildForResource(context.Background(), "r1", timelineWindow)
+	if err != nil {
+		t.Fatalf("BuildForResource failed: %v", err)
+	}
+
+	want := []struct {
+		time string
+		kind types.TimelineEventKind
+	}{
+		{"2026-10-16T01:00:00Z", types.TimelineEventMaintenance},
+		{"2026-10-16T01:30:00Z", types.TimelineEventMaintenance},
+		{"2026-10-16T02:00:00Z", types.TimelineEventAnomaly},
+		{"2026-10-16T02:05:00Z", types.TimelineEventAlert},
+		{"2026-10-16T03:00:00Z", types.TimelineEventState},
+	}
+	if len(report.Events) != len(want) {
+		t.Fatalf("Expected %d events, got %+v", len(want), report.Events)
+	}
+	for i, w := range want {
+		if event := report.Events[i]; event.Time != w.time || event.Kind != w.kind {
+			t.Errorf("Event %d: expected %s at %s, got %s at %s", i, w.kind, w.time, event.Kind, event.Time)
+		}
+	}
+	if report.Counts[types.TimelineEventMaintenance] != 2 || report.Unavailable != nil || report.Name != "web-01" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if summary := report.Events[2].Summary; !strings.Contains(summary, "spiked to 95%") {
+		t.Errorf("Expected the CPU spike, got %q", summary)
+	}
+
+	for _, line := range []string{
+		"### Timeline: web-01 (2026-10-16 00:00:00 to 2026-10-16 12:00:00 UTC)",
+		"| 2026-10-16 02:05:00 | web-01 | Alert (CRITICAL) | CPU high \\| web (repeated 3 times) [a1] |",
+		"| 2026-10-16 03:00:00 | web-01 | State change | Availability changed to DOWN |",
+	} {
+		if !strings.Contains(report.Report, line) {
+			t.Errorf("Expected the report to contain %q:\n%s", line, report.Report)
+		}
+	}
+}
+
+func TestTimelineForServiceGroup(t *testing.T) {
+	api := &timelineResourcesAPI{
+		alerts:     &mockAlertsAPI{},
+		metricsErr: fmt.Errorf("metrics unavailable"),
+	}
+	for i := 0; i <= maxTimelineResources; i++ {
+		api.members = append(api.members, types.Resource{ID: fmt.Sprintf("r%d", i)})
+	}
+
+	report, err := NewTimelineBuilder(api).BuildForServiceGroup(context.Background(), "checkout", timelineWindow)
+	if err != nil {
+		t.Fatalf("BuildForServiceGroup failed: %v", err)
+	}
+	if report.Resources != maxTimelineResources || !report.Truncated {
+		t.Errorf("Expected %d members and a truncated report, got %d", maxTimelineResources, report.Resources)
+	}
+	// The shared maintenance window is reported once
+	if report.Counts[types.TimelineEventMaintenance] != 2 || report.Counts[types.TimelineEventState] != maxTimelineResources {
+		t.Errorf("Unexpected counts %v", report.Counts)
+	}
+	if report.Unavailable["anomalies"] == "" || !strings.Contains(report.Report, "anomalies could not be retrieved") {
+		t.Errorf("Expected anomalies to be reported unavailable, got %v", report.Unavailable)
+	}
+
+	api.members = nil
+	if _, err := NewTimelineBuilder(api).BuildForServiceGroup(context.Background(), "empty", timelineWindow); err == nil {
+		t.Error("Expected an empty service group to fail")
+	}
+}
+
+func TestResourcesToolTimelineReportArguments(t *testing.T) {
+	for _, args := range []map[string]interface{}{
+		{"action": "timelineReport"},
+		{"action": "timelineReport", "id": "r1", "serviceGroup": "checkout"},
+		{"action": "timelineReport", "id": "r1", "timeRange": "yesterday-ish"},
+	} {
+		result, _ := ResourcesToolHandler(context.Background(), createTestRequest(args), &timelineResourcesAPI{})
+		if !result.IsError {
+			t.Errorf("Expected %v to be refused", args)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// timeRangeArgument is the tool input schema entry for time-bounded actions
+var timeRangeArgument = map[string]interface{}{
+	"type":        "string",
+	"description": "Time range: last15m, last24h, last7d, today, yesterday, thisWeek, thisMonth, a date (2026-10-01), or a range of dates or RFC3339 timestamps separated by `..` (2026-10-01..2026-10-07). All times are UTC.",
+}
+
+// maxRelativeAmount bounds the N in lastN<unit> expressions
+const maxRelativeAmount = 100000
+
+var (
+	relativeRangePattern = regexp.MustCompile(`^(?:last|past)(\d*)([a-z]*)$`)
+	slashDatePattern     = regexp.MustCompile(`^\d{1,4}/\d{1,2}/\d{1,4}$`)
+)
+
+// relativeUnits maps the units accepted in lastN<unit> expressions to durations
+var relativeUnits = map[string]time.Duration{
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+// TimeRange is a half-open UTC interval [Start, End)
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// TimeRangeError explains why a time range expression was rejected
+type TimeRangeError struct {
+	Expr   string
+	Reason string
+}
+
+func (e *TimeRangeError) Error() string {
+	return fmt.Sprintf("invalid time range %q: %s", e.Expr, e.Reason)
+}
+
+// ParseTimeRange parses a time range expression relative to now. Expressions
+// are case-insensitive and may contain spaces ("last 24h", "this week").
+// Weeks start on Monday. A date range includes its end date.
+func ParseTimeRange(expr string, now time.Time) (TimeRange, error) {
+	now = now.UTC()
+	fail := func(format string, args ...interface{}) (TimeRange, error) {
+		return TimeRange{}, &TimeRangeError{Expr: expr, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	trimmed := strings.TrimSpace(expr)
+	if trimmed == "" {
+		return fail("must not be empty")
+	}
+
+	// Explicit ranges keep their case: RFC3339 timestamps contain 'T' and 'Z'
+	if start, end, ok := strings.Cut(trimmed, ".."); ok {
+		from, _, err := parseRangeBound(strings.TrimSpace(start))
+		if err != nil {
+			return fail("start: %v", err)
+		}
+		to, wholeDay, err := parseRangeBound(strings.TrimSpace(end))
+		if err != nil {
+			return fail("end: %v", err)
+		}
+		if wholeDay {
+			to = to.AddDate(0, 0, 1)
+		}
+		if !from.Before(to) {
+			return fail("start must be before end")
+		}
+		return TimeRange{Start: from, End: to}, nil
+	}
+	if slashDatePattern.MatchString(trimmed) {
+		return fail("dates with slashes are ambiguous (day/month or month/day); use yyyy-mm-dd")
+	}
+	if day, wholeDay, err := parseRangeBound(trimmed); err == nil {
+		if !wholeDay {
+			return fail("a single timestamp is not a range; use start..end")
+		}
+		return TimeRange{Start: day, End: day.AddDate(0, 0, 1)}, nil
+	}
+
+	normalized := strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(trimmed))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch normalized {
+	case "today":
+		return TimeRange{Start: today, End: now}, nil
+	case "yesterday":
+		return TimeRange{Start: today.AddDate(0, 0, -1), End: today}, nil
+	case "thisweek":
+		offset := (int(today.Weekday()) + 6) % 7
+		return TimeRange{Start: today.AddDate(0, 0, -offset), End: now}, nil
+	case "thismonth":
+		return TimeRange{Start: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), End: now}, nil
+	}
+
+	match := relativeRangePattern.FindStringSubmatch(normalized)
+	if match == nil {
+		return fail("expected lastN<unit> (last15m, last24h, last7d), today, yesterday, thisWeek, thisMonth, or start..end")
+	}
+	amount, unit := match[1], match[2]
+	if amount == "" {
+		return fail("ambiguous relative range; give an amount, e.g. last1h, last24h or last7d (use thisWeek or thisMonth for calendar periods)")
+	}
+	if unit == "" {
+		return fail("missing unit; use m, h, d or w, e.g. last%sh", amount)
+	}
+	step, ok := relativeUnits[unit]
+	if !ok {
+		if strings.HasPrefix(unit, "mo") {
+			return fail("months vary in length; use days (e.g. last30d) or thisMonth")
+		}
+		return fail("unknown unit %q; use m, h, d or w", unit)
+	}
+	n, err := strconv.Atoi(amount)
+	if err != nil || n <= 0 || n > maxRelativeAmount {
+		return fail("amount must be between 1 and %d", maxRelativeAmount)
+	}
+	return TimeRange{Start: now.Add(-time.Duration(n) * step), End: now}, nil
+}
+
+// parseRangeBound parses a yyyy-mm-dd date or an RFC3339 timestamp, reporting
+// whether the value was a whole date
+func parseRangeBound(value string) (time.Time, bool, error) {
+	if day, err := time.Parse(time.DateOnly, value); err == nil {
+		return day, true, nil
+	}
+	if ts, err := time.Parse(time.RFC3339, value); err == nil {
+		return ts.UTC(), false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("%q is not a yyyy-mm-dd date or RFC3339 timestamp", value)
+}
+
+// contains reports whether t lies within the time range
+func (r TimeRange) contains(t time.Time) bool {
+	return !t.Before(r.Start) && !t.After(r.End)
+}
+
+// timeRangeFromRequest parses the request's timeRange argument, falling back
+// to the given default expression when it is absent
+func timeRangeFromRequest(req mcp.CallToolRequest, fallback string) (TimeRange, error) {
+	return ParseTimeRange(req.GetString("timeRange", fallback), time.Now())
+}
+
+// timeRangeErrorResult reports an unparseable timeRange argument as a tool error
+func timeRangeErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+	}
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	// Thursday
+	now := time.Date(2026, 10, 15, 13, 30, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		expr  string
+		start time.Time
+		end   time.Time
+	}{
+		{"last15m", now.Add(-15 * time.Minute), now},
+		{"last24h", now.Add(-24 * time.Hour), now},
+		{"Last 7 days", now.AddDate(0, 0, -7), now},
+		{"past2w", now.AddDate(0, 0, -14), now},
+		{"today", day(15), now},
+		{"yesterday", day(14), day(15)},
+		{"thisWeek", day(12), now},
+		{"this week", day(12), now},
+		{"thisMonth", day(1), now},
+		{"2026-10-01", day(1), day(2)},
+		{"2026-10-01..2026-10-07", day(1), day(8)},
+		{"2026-10-01T06:00:00Z..2026-10-01T08:00:00+01:00", day(1).Add(6 * time.Hour), day(1).Add(7 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeRange(tt.expr, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.expr, err)
+			continue
+		}
+		if !got.Start.Equal(tt.start) || !got.End.Equal(tt.end) {
+			t.Errorf("%q: expected %s..%s, got %s..%s", tt.expr, tt.start, tt.end, got.Start, got.End)
+		}
+	}
+}
+
+func TestParseTimeRangeErrors(t *testing.T) {
+	now := time.Date(2026, 10, 15, 13, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr   string
+		reason string
+	}{
+		{"", "must not be empty"},
+		{"last week", "ambiguous relative range"},
+		{"last24", "missing unit"},
+		{"last3months", "months vary in length"},
+		{"last5y", "unknown unit"},
+		{"last0h", "amount must be between"},
+		{"10/01/2026", "ambiguous"},
+		{"2026-10-07..2026-10-01", "start must be before end"},
+		{"2026-10-01..soon", "end:"},
+		{"2026-10-01T06:00:00Z", "not a range"},
+		{"recently", "expected lastN<unit>"},
+	}
+	for _, tt := range tests {
+		_, err := ParseTimeRange(tt.expr, now)
+		if err == nil {
+			t.Errorf("%q: expected an error", tt.expr)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.reason) {
+			t.Errorf("%q: expected error mentioning %q, got %v", tt.expr, tt.reason, err)
+		}
+	}
+}
+
+func TestAlertSearchQuery(t *testing.T) {
+	timeRange := TimeRange{
+		Start: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC),
+	}
+	if got := alertSearchQuery("currentState:CRITICAL", timeRange); got != "currentState:CRITICAL+startDate:2026-10-01T00:00:00+endDate:2026-10-02T00:00:00" {
+		t.Errorf("Unexpected query: %s", got)
+	}
+}
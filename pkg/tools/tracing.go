@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// errToolResult marks a span whose tool call returned an error result
+var errToolResult = errors.New("tool returned an error result")
+
+// TracingMiddleware traces each tool call as a span, a child of the span of
+// the MCP request that carried it, so the OpsRamp requests the handler makes
+// appear beneath it. A call with an error result is marked as failed like a
+// call that returned an error.
+func TracingMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool, action := req.Params.Name, req.GetString("action", "")
+		attributes := []attribute.KeyValue{
+			attribute.String("mcp.tool.name", tool),
+			attribute.String("mcp.tool.action", action),
+		}
+		if sessionID := session.IDFromContext(ctx); sessionID != "" {
+			attributes = append(attributes, attribute.String("mcp.session.id", sessionID))
+		}
+		ctx, span := tracing.Tracer().Start(ctx, "tools/call "+tool, trace.WithAttributes(attributes...))
+
+		result, err := next(ctx, req)
+
+		spanErr := err
+		if spanErr == nil && result != nil && result.IsError {
+			spanErr = errToolResult
+		}
+		tracing.End(span, spanErr)
+		return result, err
+	}
+}
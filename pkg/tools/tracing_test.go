@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	handler := TracingMiddleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, upstream := tracing.Tracer().Start(ctx, "upstream")
+		upstream.End()
+		switch req.GetString("action", "") {
+		case "fail":
+			return nil, errors.New("boom")
+		case "reject":
+			return mcp.NewToolResultError("bad input"), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+	for _, action := range []string{"list", "fail", "reject"} {
+		handler(context.Background(), createTestRequest(map[string]interface{}{"action": action}))
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 6 {
+		t.Fatalf("Expected 6 spans, got %d", len(spans))
+	}
+	for i, wantStatus := range []codes.Code{codes.Unset, codes.Error, codes.Error} {
+		upstream, call := spans[2*i], spans[2*i+1]
+		if call.Name() != "tools/call resources" {
+			t.Errorf("Expected the tool call span to be named after the tool, got %s", call.Name())
+		}
+		if upstream.Parent().SpanID() != call.SpanContext().SpanID() {
+			t.Errorf("Expected the handler's spans to be children of the tool call span")
+		}
+		if call.Status().Code != wantStatus {
+			t.Errorf("Call %d: expected status %v, got %v", i, wantStatus, call.Status().Code)
+		}
+	}
+	if !hasAttribute(spans[1].Attributes(), attribute.String("mcp.tool.action", "list")) {
+		t.Errorf("Expected the action attribute, got %v", spans[1].Attributes())
+	}
+}
+
+func hasAttribute(attributes []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, kv := range attributes {
+		if kv == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxTextLength is the length in characters above which text fields
+// of a result are truncated, unless responses.max_text_length is set
+const defaultMaxTextLength = 4000
+
+// identifierPattern matches keys a transform path can name without quotes
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// maxTextLength returns the length above which text fields are truncated, or
+// 0 when truncation is disabled
+func maxTextLength() int {
+	switch limit := getResponseDefaults().MaxTextLength; {
+	case limit < 0:
+		return 0
+	case limit > 0:
+		return limit
+	}
+	return defaultMaxTextLength
+}
+
+// hasLongText reports whether a decoded JSON value holds a string longer
+// than limit characters
+func hasLongText(value interface{}, limit int) bool {
+	switch v := value.(type) {
+	case string:
+		return len(v) > limit && len([]rune(v)) > limit
+	case []interface{}:
+		for _, item := range v {
+			if hasLongText(item, limit) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			if hasLongText(item, limit) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pathKey returns the transform path element selecting key
+func pathKey(key string) string {
+	if identifierPattern.MatchString(key) {
+		return "." + key
+	}
+	return "[" + strconv.Quote(key) + "]"
+}
+
+// truncateText returns a copy of a decoded JSON value with its strings
+// longer than limit characters cut short at a character boundary. marker
+// returns the note appended to the text at a path, given its full length.
+func truncateText(value interface{}, path string, limit int, marker func(path string, length int) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		if len(v) <= limit {
+			return v
+		}
+		runes := []rune(v)
+		if len(runes) <= limit {
+			return v
+		}
+		return string(runes[:limit]) + marker(path, len(runes))
+	case []interface{}:
+		truncated := make([]interface{}, len(v))
+		for i, item := range v {
+			truncated[i] = truncateText(item, fmt.Sprintf("%s[%d]", path, i), limit, marker)
+		}
+		return truncated
+	case map[string]interface{}:
+		truncated := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			truncated[key] = truncateText(item, path+pathKey(key), limit, marker)
+		}
+		return truncated
+	}
+	return value
+}
+
+// truncateResult cuts the long text fields of a result short. Within a
+// session the full result is kept under a result handle, returned with the
+// truncated result, and each marker tells how to fetch the full text with
+// the utility getFull action. Results without long text are returned as is.
+func truncateResult(sessionID, tool string, value interface{}, limit int) (interface{}, string, error) {
+	generic, err := normalizeJSON(value)
+	if err != nil {
+		return nil, "", err
+	}
+	if !hasLongText(generic, limit) {
+		return value, "", nil
+	}
+
+	handle := ""
+	if sessionID != "" {
+		if full, err := renderResult(generic, true); err == nil && len(full) <= maxHandleBytes {
+			handle = storeResultHandle(sessionID, tool, generic, len(full))
+		}
+	}
+	truncated := truncateText(generic, "", limit, func(path string, length int) string {
+		if !strings.HasPrefix(path, ".") {
+			path = "." + path
+		}
+		if handle == "" {
+			return fmt.Sprintf(" [truncated, %d of %d characters shown]", limit, length)
+		}
+		return fmt.Sprintf(" [truncated, %d of %d characters shown; use utility getFull with handle %s and path %s]", limit, length, handle, path)
+	})
+	return truncated, handle, nil
+}
@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestLongTextTruncatedWithMarker(t *testing.T) {
+	description := strings.Repeat("é", defaultMaxTextLength+500)
+	result := map[string]interface{}{
+		"results": []interface{}{
+			map[string]interface{}{"name": "web-01", "alert notes": description},
+		},
+	}
+
+	ctx := contextWithSession("truncate-session")
+	req := createTestRequest(map[string]interface{}{"action": "search"})
+	req.Params.Name = "alerts"
+	res, err := respondJSON(ctx, req, result)
+	if err != nil || res.IsError {
+		t.Fatalf("Expected a truncated result, got %+v (%v)", res, err)
+	}
+	var truncated struct {
+		Results []map[string]string `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(resultText(res)), &truncated); err != nil {
+		t.Fatal(err)
+	}
+	notes := truncated.Results[0]["alert notes"]
+	if !strings.HasPrefix(notes, strings.Repeat("é", defaultMaxTextLength)+" [truncated, 4000 of 4500 characters shown") {
+		t.Fatalf("Expected the notes cut at %d characters with a marker, got %q", defaultMaxTextLength, notes[len(notes)-200:])
+	}
+	if truncated.Results[0]["name"] != "web-01" {
+		t.Errorf("Expected short fields to be kept, got %+v", truncated.Results[0])
+	}
+
+	marker := regexp.MustCompile(`handle (rh-\S+) and path (.+)\]$`).FindStringSubmatch(notes)
+	if marker == nil || marker[2] != `.results[0]["alert notes"]` {
+		t.Fatalf("Expected the marker to name a handle and path, got %q", notes[len(notes)-120:])
+	}
+	full, err := UtilityToolHandler(ctx, createTestRequest(map[string]interface{}{
+		"action": "getFull", "handle": marker[1], "path": marker[2],
+	}))
+	if err != nil || full.IsError {
+		t.Fatalf("getFull failed: %v %+v", err, full)
+	}
+	if text := full.Content[0].(mcp.TextContent).Text; text != description {
+		t.Errorf("Expected the full notes, got %d characters", len([]rune(text)))
+	}
+
+	// Without a session there is nowhere to keep the full text
+	res, _ = respondJSON(contextWithSession(""), req, result)
+	if text := resultText(res); !strings.Contains(text, "[truncated, 4000 of 4500 characters shown]") || strings.Contains(text, "getFull") {
+		t.Errorf("Expected a marker without a handle, got %q", text[len(text)-200:])
+	}
+}
+
+func TestShortTextNotTruncated(t *testing.T) {
+	result := map[string]interface{}{"description": strings.Repeat("a", defaultMaxTextLength)}
+	value, handle, err := truncateResult("truncate-session", "alerts", result, defaultMaxTextLength)
+	if err != nil || handle != "" {
+		t.Fatalf("Expected no handle for short text, got %q (%v)", handle, err)
+	}
+	if value.(map[string]interface{})["description"] != result["description"] {
+		t.Error("Expected short text to be returned as is")
+	}
+}
@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// NewUsageMcpTool returns the MCP tool definition and handler for usage and quota inspection
+func NewUsageMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
+	// Get the logger
+	logger := common.GetLogger()
+
+	// Load configuration
+	config, err := common.LoadConfig("")
+	if err != nil {
+		logger.Error("Failed to load config for OpsRamp Usage API: %v", err)
+		return mcp.Tool{}, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Configuration error: %v", err)}},
+			}, nil
+		}
+	}
+
+	// Create and initialize the API implementation for the configured backend
+	backend := config.ToolBackend("usage").Backend
+	opsRampClient, err := newBackendClient(config, "usage")
+	if err != nil {
+		logger.Error("Failed to initialize %s backend for OpsRamp Usage API: %v", backend, err)
+		tool, _ := createUsageTool(nil)
+		return withBackend(tool, backend), backendErrorHandler(err)
+	}
+	api := NewOpsRampUsageAPI(opsRampClient)
+
+	logger.Info("Successfully initialized OpsRamp Usage API (%s backend)", backend)
+	tool, handler := createUsageTool(api)
+	return withBackend(tool, backend), handler
+}
+
+// createUsageTool creates the MCP tool with the given API implementation
+func createUsageTool(api UsageAPI) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "usage",
+		Description: "Inspect HPE OpsRamp licensing and usage: managed resource counts by tier, headroom against license limits, and API usage.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"action": map[string]interface{}{
+					"type":        "string",
+					"description": "Action to perform: summary (how close are we to license limits), licenses, resourceCounts, apiUsage",
+				},
+				"locale":     localeArgument,
+				"tenant":     tenantArgument,
+				"tenants":    tenantsArgument,
+				"llmCompact": llmCompactArgument,
+				"transform":  transformArgument,
+				"render":     renderArgument,
+				"columns":    columnsArgument,
+				"maxRows":    maxRowsArgument,
+				"example":    exampleArgument,
+			},
+			Required: []string{"action"},
+		},
+		OutputSchema: outputSchema(types.UsageSummary{}, types.APIUsage{}, types.TenantFanOutResult{}),
+	}, withTenantFanOut("usage", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tenant, err := resolveTenant(req)
+		if err != nil {
+			return tenantErrorResult(err), nil
+		}
+		if tenant != nil {
+			return UsageToolHandler(ctx, req, NewOpsRampUsageAPI(tenant.Client))
+		}
+		return UsageToolHandler(ctx, req, api)
+	})
+}
+
+// UsageToolHandler routes requests to the correct method
+// Exported for testing purposes
+func UsageToolHandler(ctx context.Context, req mcp.CallToolRequest, api UsageAPI) (*mcp.CallToolResult, error) {
+	action := req.GetString("action", "")
+
+	// Log the tool execution
+	logger := toolLogger("usage")
+	logger.LogToolExecution("usage", action, req.GetArguments())
+
+	var err error
+	var result interface{}
+
+	switch action {
+	case "summary":
+		logger.Info("Executing usage summary")
+		locale, localeErr := localeFromRequest(req)
+		if localeErr != nil {
+			err = localeErr
+			break
+		}
+		usage, usageErr := api.GetLicenseUsage(ctx)
+		if err = usageErr; err == nil {
+			result = summarizeUsage(usage, locale)
+		}
+	case "licenses":
+		logger.Info("Executing List license usage")
+		result, err = api.GetLicenseUsage(ctx)
+	case "resourceCounts":
+		logger.Info("Executing managed resource counts by tier")
+		usage, usageErr := api.GetLicenseUsage(ctx)
+		if err = usageErr; err == nil {
+			result = resourceCountsByTier(usage)
+		}
+	case "apiUsage":
+		logger.Info("Executing Get API usage")
+		result, err = api.GetAPIUsage(ctx)
+	default:
+		logger.Error("Unknown action: %s", action)
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Unknown action: %s", action)}},
+		}, nil
+	}
+
+	// Log the result
+	logger.LogToolResult("usage", action, result, err)
+
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+
+	toolResult, err := respondJSON(ctx, req, result)
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+	return toolResult, nil
+}
@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"github.com/opsramp/or-mcp-v2/pkg/client"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// Quota thresholds, as a percentage of the license limit
+const (
+	quotaWarningPercent  = 80.0
+	quotaCriticalPercent = 95.0
+)
+
+// UsageAPI defines the contract for licensing and usage operations
+type UsageAPI interface {
+	// GetLicenseUsage retrieves licensed limits and consumption per tier
+	GetLicenseUsage(ctx context.Context) ([]types.LicenseUsage, error)
+
+	// GetAPIUsage retrieves API call consumption, if exposed for the tenant
+	GetAPIUsage(ctx context.Context) (*types.APIUsage, error)
+}
+
+// OpsRampUsageAPI implements the UsageAPI interface for OpsRamp
+type OpsRampUsageAPI struct {
+	client *client.OpsRampClient
+	logger *common.CustomLogger
+}
+
+// NewOpsRampUsageAPI creates a new OpsRamp usage API client
+func NewOpsRampUsageAPI(client *client.OpsRampClient) *OpsRampUsageAPI {
+	return &OpsRampUsageAPI{
+		client: client,
+		logger: client.Logger().WithModule(toolModule("usage")),
+	}
+}
+
+// GetLicenseUsage retrieves licensed limits and consumption per tier
+func (api *OpsRampUsageAPI) GetLicenseUsage(ctx context.Context) ([]types.LicenseUsage, error) {
+	api.logger.Info("Getting license usage")
+
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/licenses/usage", api.client.GetTenantID())
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var response struct {
+		Results []types.LicenseUsage `json:"results"`
+	}
+	if err := api.client.Get(ctx, endpoint, &response); err != nil {
+		api.logger.Error("Failed to get license usage: %v", err)
+		return nil, fmt.Errorf("failed to get license usage: %w", err)
+	}
+
+	api.logger.Info("Retrieved license usage for %d tiers", len(response.Results))
+	return response.Results, nil
+}
+
+// GetAPIUsage retrieves API call consumption, if exposed for the tenant.
+// Tenants without API usage reporting get Available=false rather than an error.
+func (api *OpsRampUsageAPI) GetAPIUsage(ctx context.Context) (*types.APIUsage, error) {
+	api.logger.Info("Getting API usage")
+
+	endpoint := fmt.Sprintf("/api/v2/tenants/%s/apiUsage", api.client.GetTenantID())
+	api.logger.Debug("Using endpoint: %s", endpoint)
+
+	var usage types.APIUsage
+	statusCode, err := api.client.GetWithStatusCode(ctx, endpoint, &usage)
+	if err != nil {
+		if statusCode == 403 || statusCode == 404 {
+			return &types.APIUsage{Available: false, Message: "API usage is not exposed for this tenant"}, nil
+		}
+		api.logger.Error("Failed to get API usage: %v", err)
+		return nil, fmt.Errorf("failed to get API usage: %w", err)
+	}
+
+	usage.Available = true
+	return &usage, nil
+}
+
+// summarizeUsage computes quota status for each tier, most constrained first,
+// with a summary in the given locale
+func summarizeUsage(usage []types.LicenseUsage, l *Locale) *types.UsageSummary {
+	summary := &types.UsageSummary{Tiers: make([]types.QuotaStatus, 0, len(usage))}
+
+	for _, tier := range usage {
+		summary.TotalManaged += tier.Used
+		summary.Tiers = append(summary.Tiers, quotaStatus(tier))
+	}
+
+	sort.SliceStable(summary.Tiers, func(i, j int) bool {
+		return summary.Tiers[i].PercentUsed > summary.Tiers[j].PercentUsed
+	})
+
+	for i := range summary.Tiers {
+		if summary.Tiers[i].Limit > 0 {
+			summary.NearestLimit = &summary.Tiers[i]
+			break
+		}
+	}
+
+	if summary.NearestLimit == nil {
+		summary.Summary = l.T("usage.noLimits", summary.TotalManaged)
+	} else {
+		nearest := summary.NearestLimit
+		summary.Summary = l.T("usage.nearestLimit", summary.TotalManaged, nearest.Tier, l.Decimal(nearest.PercentUsed),
+			nearest.Used, nearest.Limit, nearest.Remaining, l.T("quota."+nearest.Status))
+	}
+
+	return summary
+}
+
+// quotaStatus classifies a tier's consumption against its limit
+func quotaStatus(tier types.LicenseUsage) types.QuotaStatus {
+	status := types.QuotaStatus{
+		Tier:      tier.Tier,
+		Limit:     tier.Limit,
+		Used:      tier.Used,
+		Remaining: tier.Limit - tier.Used,
+		Status:    "ok",
+	}
+
+	// A zero limit means the tier is unlimited or not metered
+	if tier.Limit <= 0 {
+		status.Remaining = 0
+		status.Status = "unlimited"
+		return status
+	}
+
+	status.PercentUsed = float64(tier.Used) * 100 / float64(tier.Limit)
+	switch {
+	case tier.Used > tier.Limit:
+		status.Status = "exceeded"
+		status.Remaining = 0
+	case status.PercentUsed >= quotaCriticalPercent:
+		status.Status = "critical"
+	case status.PercentUsed >= quotaWarningPercent:
+		status.Status = "warning"
+	}
+	return status
+}
+
+// resourceCountsByTier returns managed resource counts per tier, largest first
+func resourceCountsByTier(usage []types.LicenseUsage) []types.ResourceTierCount {
+	counts := make([]types.ResourceTierCount, 0, len(usage))
+	for _, tier := range usage {
+		counts = append(counts, types.ResourceTierCount{Tier: tier.Tier, Count: tier.Used})
+	}
+	sort.SliceStable(counts, func(i, j int) bool {
+		return counts[i].Count > counts[j].Count
+	})
+	return counts
+}
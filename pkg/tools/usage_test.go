@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+func TestSummarizeUsage(t *testing.T) {
+	summary := summarizeUsage([]types.LicenseUsage{
+		{Tier: "Standard", Limit: 1000, Used: 500},
+		{Tier: "Premium", Limit: 100, Used: 96},
+		{Tier: "Cloud", Limit: 0, Used: 40},
+		{Tier: "Network", Limit: 50, Used: 55},
+	}, locales[defaultLocale])
+
+	if summary.TotalManaged != 691 {
+		t.Errorf("Expected 691 managed resources, got %d", summary.TotalManaged)
+	}
+	if summary.NearestLimit == nil || summary.NearestLimit.Tier != "Network" {
+		t.Fatalf("Expected Network to be nearest its limit, got %+v", summary.NearestLimit)
+	}
+
+	statuses := map[string]string{}
+	for _, tier := range summary.Tiers {
+		statuses[tier.Tier] = tier.Status
+	}
+	want := map[string]string{"Standard": "ok", "Premium": "critical", "Cloud": "unlimited", "Network": "exceeded"}
+	for tier, status := range want {
+		if statuses[tier] != status {
+			t.Errorf("Expected %s to be %s, got %s", tier, status, statuses[tier])
+		}
+	}
+}
+
+func TestQuotaStatusWarning(t *testing.T) {
+	status := quotaStatus(types.LicenseUsage{Tier: "Standard", Limit: 100, Used: 85})
+	if status.Status != "warning" || status.Remaining != 15 || status.PercentUsed != 85 {
+		t.Errorf("Unexpected quota status: %+v", status)
+	}
+}
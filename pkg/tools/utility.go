@@ -0,0 +1,231 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/transform"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// NewUtilityMcpTool returns the MCP tool definition and handler for result utilities
+func NewUtilityMcpTool() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name: "utility",
+		Description: "Utilities over tool results. Results of earlier calls in this session are kept briefly under result handles (rh-...), and large results are returned only as a handle plus summary. " +
+			"diff compares two results, given as handles or inline JSON, and reports added, removed and changed entries keyed by ID. " +
+			"transform applies a transform expression to a stored result, export registers it as CSV or JSON at an opsramp://exports/ resource URI to fetch with resources/read, and nextPage reads its entries a page at a time, all without fetching it again. " +
+			"Long text fields are truncated with a marker naming a handle and path; getFull returns the full value at that path.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"action": map[string]interface{}{
+					"type":        "string",
+					"description": "Action to perform: diff, transform, export, nextPage, getFull",
+				},
+				"handle": map[string]interface{}{
+					"type":        "string",
+					"description": "Result handle (rh-...) of an earlier result (for transform, export, nextPage, getFull)",
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Path of the value to return in full, as given in a truncation marker, e.g. .results[0].description (for getFull)",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"description": "Export format: csv or json (for export, defaults to json)",
+				},
+				"inline": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Return the export as text in the tool result instead of as a resource (for export)",
+				},
+				"offset": map[string]interface{}{
+					"type":        "number",
+					"description": "Entry offset to read from (for nextPage, defaults to where the previous page ended)",
+				},
+				"pageSize": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Entries per page (for nextPage, default %d, max %d)", defaultHandlePageSize, maxHandlePageSize),
+				},
+				"before": map[string]interface{}{
+					"description": "Earlier result: a result handle (rh-...), a JSON string, or an inline JSON value",
+				},
+				"after": map[string]interface{}{
+					"description": "Later result: a result handle (rh-...), a JSON string, or an inline JSON value",
+				},
+				"keyBy": map[string]interface{}{
+					"type":        "string",
+					"description": "Field used to match entries (defaults to the first of id, uniqueId, resourceId, alertId, name present in every entry)",
+				},
+				"llmCompact": llmCompactArgument,
+				"transform":  transformArgument,
+				"render":     renderArgument,
+				"columns":    columnsArgument,
+				"maxRows":    maxRowsArgument,
+			},
+			Required: []string{"action"},
+		},
+	}, UtilityToolHandler
+}
+
+// UtilityToolHandler routes requests to the correct method
+// Exported for testing purposes
+func UtilityToolHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	action := req.GetString("action", "")
+
+	// Log the tool execution
+	logger := toolLogger("utility")
+	logger.LogToolExecution("utility", action, req.GetArguments())
+
+	var err error
+	var result interface{}
+
+	switch action {
+	case "diff":
+		logger.Info("Executing result diff")
+		var before, after interface{}
+		if before, err = resolveResultArgument(ctx, req, "before"); err != nil {
+			break
+		}
+		if after, err = resolveResultArgument(ctx, req, "after"); err != nil {
+			break
+		}
+		result, err = diffResults(before, after, req.GetString("keyBy", ""))
+	case "transform":
+		if req.GetString("transform", "") == "" {
+			err = fmt.Errorf("transform is required for transform")
+			break
+		}
+		logger.Info("Executing transform of stored result")
+		result, err = loadHandleArgument(ctx, req, action)
+	case "export":
+		logger.Info("Executing export of stored result")
+		var value interface{}
+		if value, err = loadHandleArgument(ctx, req, action); err != nil {
+			break
+		}
+		// Apply the transform here: the export is returned as raw text
+		if value, err = applyTransform(value, req.GetString("transform", "")); err != nil {
+			break
+		}
+		format := req.GetString("format", "json")
+		var text string
+		if text, err = exportResult(value, format); err != nil {
+			break
+		}
+		logger.LogToolResult("utility", action, fmt.Sprintf("%d bytes", len(text)), nil)
+		if req.GetBool("inline", false) {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{mcp.TextContent{Type: "text", Text: text}},
+			}, nil
+		}
+		return exportResourceResult(storeExport(session.IDFromContext(ctx), req.GetString("handle", ""), format, text, exportEntryCount(value)))
+	case "nextPage":
+		handle := req.GetString("handle", "")
+		if handle == "" {
+			err = fmt.Errorf("handle is required for nextPage")
+			break
+		}
+		logger.Info("Executing nextPage of stored result %s", handle)
+		sessionID := session.IDFromContext(ctx)
+		if sessionID == "" {
+			err = fmt.Errorf("result handles require an MCP session")
+			break
+		}
+		var page *types.ResultPage
+		if page, err = pageResultHandle(sessionID, handle, req.GetInt("offset", -1), req.GetInt("pageSize", defaultHandlePageSize)); err == nil {
+			logger.LogToolResult("utility", action, fmt.Sprintf("%d of %d entries", page.Count, page.Total), nil)
+			return respondResult(ctx, req, page, false)
+		}
+	case "getFull":
+		path := req.GetString("path", "")
+		if path == "" {
+			err = fmt.Errorf("path is required for getFull")
+			break
+		}
+		logger.Info("Executing getFull of %s in stored result", path)
+		var value interface{}
+		if value, err = loadHandleArgument(ctx, req, action); err != nil {
+			break
+		}
+		if value, err = transform.Apply(path, value); err != nil {
+			err = fmt.Errorf("invalid path %s: %w", path, err)
+			break
+		}
+		// The full value is returned as is, without truncation; text as plain text
+		text, isText := value.(string)
+		if !isText {
+			if text, err = renderResult(value, false); err != nil {
+				break
+			}
+		}
+		logger.LogToolResult("utility", action, fmt.Sprintf("%d bytes", len(text)), nil)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: text}},
+		}, nil
+	default:
+		logger.Error("Unknown action: %s", action)
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: fmt.Sprintf("Unknown action: %s", action)}},
+		}, nil
+	}
+
+	// Log the result
+	logger.LogToolResult("utility", action, result, err)
+
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: err.Error()}},
+		}, nil
+	}
+
+	return respondJSON(ctx, req, result)
+}
+
+// loadHandleArgument returns the stored result named by the handle argument
+func loadHandleArgument(ctx context.Context, req mcp.CallToolRequest, action string) (interface{}, error) {
+	handle := req.GetString("handle", "")
+	if handle == "" {
+		return nil, fmt.Errorf("handle is required for %s", action)
+	}
+	sessionID := session.IDFromContext(ctx)
+	if sessionID == "" {
+		return nil, fmt.Errorf("result handles require an MCP session")
+	}
+	return loadResultHandle(sessionID, handle)
+}
+
+// resolveResultArgument returns the value of a result argument, which may be a
+// result handle, a JSON-encoded string, or an inline JSON value
+func resolveResultArgument(ctx context.Context, req mcp.CallToolRequest, name string) (interface{}, error) {
+	value, ok := req.GetArguments()[name]
+	if !ok || value == nil {
+		return nil, fmt.Errorf("%s is required for diff", name)
+	}
+
+	text, isString := value.(string)
+	if !isString {
+		return value, nil
+	}
+
+	if strings.HasPrefix(text, "rh-") {
+		sessionID := session.IDFromContext(ctx)
+		if sessionID == "" {
+			return nil, fmt.Errorf("result handles require an MCP session")
+		}
+		return loadResultHandle(sessionID, text)
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		return nil, fmt.Errorf("%s is neither a result handle nor valid JSON: %w", name, err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolCall is a tool call payload: the tool, its action and the other
+// arguments. The action may also be given as the action argument.
+type ToolCall struct {
+	Tool      string                 `json:"tool"`
+	Action    string                 `json:"action,omitempty"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// ToolDefinitions returns the definitions of the tools the server serves,
+// built without a backend so they can be inspected offline
+func ToolDefinitions() []mcp.Tool {
+	definitions := make([]mcp.Tool, 0, 10)
+	for _, create := range []func() mcp.Tool{
+		func() mcp.Tool { tool, _ := createIntegrationsTool(nil, nil); return tool },
+		func() mcp.Tool { tool, _ := createResourcesTool(nil); return tool },
+		func() mcp.Tool { tool, _ := createAlertsTool(nil, ""); return tool },
+		func() mcp.Tool { tool, _ := createUsageTool(nil); return tool },
+		func() mcp.Tool { tool, _ := createClientsTool(nil, nil); return tool },
+		func() mcp.Tool { tool, _ := createMonitorsTool(nil); return tool },
+		func() mcp.Tool { tool, _ := createMetricsTool(nil); return tool },
+		func() mcp.Tool { tool, _ := NewSessionMcpTool(); return tool },
+		func() mcp.Tool { tool, _ := NewUtilityMcpTool(); return tool },
+		func() mcp.Tool { tool, _ := NewAdminMcpTool(); return tool },
+	} {
+		definitions = append(definitions, create())
+	}
+	return definitions
+}
+
+var (
+	// actionListPattern finds the action list in an action argument's description
+	actionListPattern = regexp.MustCompile(`Action to perform: ([^.]+)`)
+	// actionAsidePattern matches the parenthesized notes of an action list
+	actionAsidePattern = regexp.MustCompile(`\([^)]*\)`)
+)
+
+// toolActions returns the actions a tool's action argument lists, or nil when
+// it lists none
+func toolActions(tool mcp.Tool) []string {
+	property, _ := tool.InputSchema.Properties["action"].(map[string]interface{})
+	description, _ := property["description"].(string)
+	match := actionListPattern.FindStringSubmatch(actionAsidePattern.ReplaceAllString(description, ""))
+	if match == nil {
+		return nil
+	}
+	var actions []string
+	for _, name := range strings.Split(match[1], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			actions = append(actions, name)
+		}
+	}
+	return actions
+}
+
+// ValidateToolCall checks a tool call against the input schema of its tool:
+// the tool and action must exist, every argument must be declared, and each
+// must have its declared type once numeric and boolean strings are coerced,
+// as the server does. It returns the normalized call, with the action among
+// the arguments, and every problem found.
+func ValidateToolCall(definitions []mcp.Tool, call ToolCall) (*ToolCall, []string) {
+	i := slices.IndexFunc(definitions, func(tool mcp.Tool) bool { return tool.Name == call.Tool })
+	if i < 0 {
+		names := make([]string, len(definitions))
+		for j, tool := range definitions {
+			names[j] = tool.Name
+		}
+		sort.Strings(names)
+		return nil, []string{fmt.Sprintf("unknown tool %q; the tools are %s", call.Tool, strings.Join(names, ", "))}
+	}
+	tool := definitions[i]
+
+	var problems []string
+	args := make(map[string]interface{}, len(call.Arguments)+1)
+	for name, value := range call.Arguments {
+		args[name] = value
+	}
+	if call.Action != "" {
+		if existing, ok := args["action"]; ok && existing != call.Action {
+			problems = append(problems, fmt.Sprintf("action %q conflicts with the action argument %v", call.Action, existing))
+		}
+		args["action"] = call.Action
+	}
+
+	var coerced []coercion
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": tool.InputSchema.Properties,
+		"required":   tool.InputSchema.Required,
+	}
+	normalized, _ := coerceBySchema(schema, args, "", &coerced).(map[string]interface{})
+
+	names := make([]string, 0, len(normalized))
+	for name := range normalized {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, declared := tool.InputSchema.Properties[name]; !declared {
+			problems = append(problems, fmt.Sprintf("%s has no %s argument", tool.Name, name))
+		}
+	}
+	if err := validateSchema(schema, normalized, "arguments"); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if action, ok := normalized["action"].(string); ok {
+		if actions := toolActions(tool); actions != nil && !slices.Contains(actions, action) {
+			problems = append(problems, fmt.Sprintf("%s has no %s action; the actions are %s", tool.Name, action, strings.Join(actions, ", ")))
+		}
+	}
+
+	action, _ := normalized["action"].(string)
+	delete(normalized, "action")
+	return &ToolCall{Tool: tool.Name, Action: action, Arguments: normalized}, problems
+}
@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateToolCall(t *testing.T) {
+	definitions := ToolDefinitions()
+
+	call := ToolCall{Tool: "resources", Action: "inferPropertySchema", Arguments: map[string]interface{}{"resourceType": "server", "sampleSize": "200"}}
+	normalized, problems := ValidateToolCall(definitions, call)
+	if len(problems) != 0 {
+		t.Fatalf("Expected a valid call, got %v", problems)
+	}
+	if normalized.Action != "inferPropertySchema" || normalized.Arguments["sampleSize"] != float64(200) {
+		t.Errorf("Expected sampleSize coerced to a number, got %+v", normalized)
+	}
+	if call.Arguments["sampleSize"] != "200" {
+		t.Error("Expected the payload to be left unchanged")
+	}
+
+	tests := []struct {
+		call    ToolCall
+		problem string
+	}{
+		{ToolCall{Tool: "nodes", Action: "list"}, `unknown tool "nodes"`},
+		{ToolCall{Tool: "alerts", Action: "explode"}, "alerts has no explode action"},
+		{ToolCall{Tool: "alerts"}, "missing action"},
+		{ToolCall{Tool: "alerts", Action: "search", Arguments: map[string]interface{}{"action": "get"}}, "conflicts with the action argument"},
+		{ToolCall{Tool: "alerts", Action: "search", Arguments: map[string]interface{}{"severity": "high"}}, "alerts has no severity argument"},
+		{ToolCall{Tool: "resources", Action: "inferPropertySchema", Arguments: map[string]interface{}{"sampleSize": "lots"}}, "arguments.sampleSize"},
+	}
+	for _, tt := range tests {
+		_, problems := ValidateToolCall(definitions, tt.call)
+		if !strings.Contains(strings.Join(problems, "; "), tt.problem) {
+			t.Errorf("%+v: expected a problem containing %q, got %v", tt.call, tt.problem, problems)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/pkg/session"
+	"github.com/opsramp/or-mcp-v2/pkg/store"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+const (
+	// maxWorkspaceResults bounds the recent results saved with a workspace
+	maxWorkspaceResults = 10
+	// maxWorkspaceBytes bounds the total rendered size of the saved results
+	maxWorkspaceBytes = 8 << 20
+)
+
+// workspaceNamePattern restricts workspace and saved search names
+var workspaceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Workspaces saves and restores the context of an investigation in the
+// state store, so it can be resumed in a later session
+type Workspaces struct {
+	store *store.Store
+}
+
+// NewWorkspaces creates a workspace set over the default state store, which may be unavailable
+func NewWorkspaces() *Workspaces {
+	return &Workspaces{store: store.Default()}
+}
+
+// Save stores the session's context variables and most recent results under
+// name. searches are added to the workspace's saved searches, a null search
+// removing the saved search of that name.
+func (w *Workspaces) Save(sess *session.Session, sessionID, name string, searches map[string]interface{}, now time.Time) (*types.WorkspaceSummary, error) {
+	if w.store == nil {
+		return nil, errors.New("saving workspaces requires the state store")
+	}
+	if !workspaceNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid workspace name %q: use up to 64 letters, digits, dots, dashes and underscores", name)
+	}
+
+	workspace := types.Workspace{Searches: map[string]types.SavedSearch{}}
+	if err := w.store.Get(store.BucketWorkspaces, name, &workspace); err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, err
+	}
+	if workspace.Searches == nil {
+		workspace.Searches = map[string]types.SavedSearch{}
+	}
+	for searchName, value := range searches {
+		if value == nil {
+			delete(workspace.Searches, searchName)
+			continue
+		}
+		search, err := decodeSavedSearch(searchName, value)
+		if err != nil {
+			return nil, err
+		}
+		workspace.Searches[searchName] = search
+	}
+
+	workspace.Name = name
+	workspace.SavedAt = now.UTC().Format(time.RFC3339)
+	workspace.Variables = sessionVariables(sess)
+	workspace.Results = nil
+
+	var skipped []string
+	size := 0
+	for _, handle := range sessionResultHandles(sessionID) {
+		// Session tool results only echo state the workspace already saves
+		if handle.Tool == "session" {
+			continue
+		}
+		if len(workspace.Results) == maxWorkspaceResults || size+handle.Bytes > maxWorkspaceBytes {
+			skipped = append(skipped, handle.ID)
+			continue
+		}
+		value, err := json.Marshal(handle.Value)
+		if err != nil {
+			skipped = append(skipped, handle.ID)
+			continue
+		}
+		size += handle.Bytes
+		workspace.Results = append(workspace.Results, types.WorkspaceResult{
+			Handle:    handle.ID,
+			Tool:      handle.Tool,
+			Bytes:     handle.Bytes,
+			CreatedAt: handle.CreatedAt.UTC().Format(time.RFC3339),
+			Value:     value,
+		})
+	}
+
+	if err := w.store.Put(store.BucketWorkspaces, name, workspace); err != nil {
+		return nil, err
+	}
+	summary := summarizeWorkspace(workspace)
+	summary.Skipped = skipped
+	return &summary, nil
+}
+
+// Load restores a workspace into a session. Its context variables are set,
+// replacing session variables of the same name, and its results are stored
+// under new result handles.
+func (w *Workspaces) Load(sess *session.Session, sessionID, name string) (*types.LoadedWorkspace, error) {
+	workspace, err := w.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := sessionVariables(sess)
+	for variable, value := range workspace.Variables {
+		variables[variable] = value
+	}
+	sess.Set(sessionVariablesKey, variables)
+
+	loaded := &types.LoadedWorkspace{
+		Name:      workspace.Name,
+		SavedAt:   workspace.SavedAt,
+		Variables: variables,
+		Searches:  workspace.Searches,
+		Handles:   []types.WorkspaceHandle{},
+		Hint: "Context variables are set for this session. Saved results are available under new result handles for the utility tool; " +
+			"run a saved search again with its tool and arguments to get current data.",
+	}
+	if loaded.Searches == nil {
+		loaded.Searches = map[string]types.SavedSearch{}
+	}
+	// Store the oldest first so the most recent results are evicted last
+	for i := len(workspace.Results) - 1; i >= 0; i-- {
+		result := workspace.Results[i]
+		var value interface{}
+		if err := json.Unmarshal(result.Value, &value); err != nil {
+			return nil, fmt.Errorf("failed to decode saved result %s: %w", result.Handle, err)
+		}
+		handle := storeResultHandle(sessionID, result.Tool, value, result.Bytes)
+		loaded.Handles = append(loaded.Handles, types.WorkspaceHandle{
+			Saved:        result.Handle,
+			ResultHandle: handle,
+			Tool:         result.Tool,
+			ExpiresAt:    handleNow().Add(resultHandleTTL).UTC().Format(time.RFC3339),
+		})
+	}
+	return loaded, nil
+}
+
+// List returns the saved workspaces by name; without a state store there are none
+func (w *Workspaces) List() ([]types.WorkspaceSummary, error) {
+	summaries := make([]types.WorkspaceSummary, 0)
+	if w.store == nil {
+		return summaries, nil
+	}
+	err := w.store.ForEach(store.BucketWorkspaces, func(name string, raw []byte) error {
+		var workspace types.Workspace
+		if err := json.Unmarshal(raw, &workspace); err != nil {
+			return fmt.Errorf("failed to decode workspace %s: %w", name, err)
+		}
+		summaries = append(summaries, summarizeWorkspace(workspace))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries, nil
+}
+
+// Delete removes the named workspace
+func (w *Workspaces) Delete(name string) error {
+	if _, err := w.get(name); err != nil {
+		return err
+	}
+	return w.store.Delete(store.BucketWorkspaces, name)
+}
+
+// get returns the named workspace
+func (w *Workspaces) get(name string) (types.Workspace, error) {
+	var workspace types.Workspace
+	if w.store != nil {
+		err := w.store.Get(store.BucketWorkspaces, name, &workspace)
+		if err == nil {
+			return workspace, nil
+		}
+		if !errors.Is(err, store.ErrNotFound) {
+			return workspace, err
+		}
+	}
+	return workspace, fmt.Errorf("unknown workspace: %s (use listWorkspaces to see saved workspaces)", name)
+}
+
+// decodeSavedSearch checks a saved search argument: the tool to call, which
+// must be registered, and its arguments
+func decodeSavedSearch(name string, value interface{}) (types.SavedSearch, error) {
+	var search types.SavedSearch
+	if !workspaceNamePattern.MatchString(name) {
+		return search, fmt.Errorf("invalid saved search name %q: use up to 64 letters, digits, dots, dashes and underscores", name)
+	}
+	payload, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(payload, &search)
+	}
+	if err != nil {
+		return search, fmt.Errorf("invalid saved search %s: expected {tool, arguments}: %w", name, err)
+	}
+	if _, ok := toolArguments.Load(search.Tool); !ok {
+		return search, fmt.Errorf("invalid saved search %s: unknown tool %q", name, search.Tool)
+	}
+	if search.Arguments == nil {
+		search.Arguments = map[string]interface{}{}
+	}
+	return search, nil
+}
+
+// summarizeWorkspace describes a workspace without its results
+func summarizeWorkspace(workspace types.Workspace) types.WorkspaceSummary {
+	summary := types.WorkspaceSummary{
+		Name:      workspace.Name,
+		SavedAt:   workspace.SavedAt,
+		Variables: sortedKeys(workspace.Variables),
+		Searches:  make([]string, 0, len(workspace.Searches)),
+		Results:   len(workspace.Results),
+	}
+	for name := range workspace.Searches {
+		summary.Searches = append(summary.Searches, name)
+	}
+	sort.Strings(summary.Searches)
+	return summary
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/opsramp/or-mcp-v2/pkg/types"
+)
+
+// callSession calls the session tool and decodes its result into out
+func callSession(t *testing.T, ctx context.Context, args map[string]interface{}, out interface{}) *mcp.CallToolResult {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "session"
+	req.Params.Arguments = args
+	result, err := SessionToolHandler(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != nil && !result.IsError {
+		if err := json.Unmarshal([]byte(resultText(result)), out); err != nil {
+			t.Fatalf("Failed to decode %s result: %v", args["action"], err)
+		}
+	}
+	return result
+}
+
+func TestWorkspaceSaveAndLoad(t *testing.T) {
+	useTestStore(t)
+	RegisterToolSchema(mcp.Tool{Name: "resources"})
+
+	yesterday := contextWithSession("workspace-1")
+	callSession(t, yesterday, map[string]interface{}{
+		"action": "setContext", "variables": map[string]interface{}{"defaultTenant": "emea"},
+	}, nil)
+	handle := storeResultHandle("workspace-1", "resources", map[string]interface{}{"results": []interface{}{"r1", "r2"}}, 64)
+
+	var saved types.WorkspaceSummary
+	result := callSession(t, yesterday, map[string]interface{}{
+		"action": "saveWorkspace", "workspace": "outage-42",
+		"searches": map[string]interface{}{
+			"downServers": map[string]interface{}{"tool": "resources", "arguments": map[string]interface{}{"action": "search"}},
+		},
+	}, &saved)
+	if result.IsError {
+		t.Fatalf("saveWorkspace failed: %s", resultText(result))
+	}
+	if saved.Results != 1 || len(saved.Searches) != 1 || saved.Variables[0] != "defaultTenant" {
+		t.Errorf("Expected the variable, search and result saved, got %+v", saved)
+	}
+
+	today := contextWithSession("workspace-2")
+	var loaded types.LoadedWorkspace
+	callSession(t, today, map[string]interface{}{"action": "loadWorkspace", "workspace": "outage-42"}, &loaded)
+	if loaded.Variables["defaultTenant"] != "emea" || loaded.Searches["downServers"].Tool != "resources" {
+		t.Errorf("Expected the saved context, got %+v", loaded)
+	}
+	if len(loaded.Handles) != 1 || loaded.Handles[0].Saved != handle {
+		t.Fatalf("Expected the saved result under a new handle, got %+v", loaded.Handles)
+	}
+	page, err := pageResultHandle("workspace-2", loaded.Handles[0].ResultHandle, 0, 10)
+	if err != nil || page.Total != 2 {
+		t.Errorf("Expected the result readable in the new session, got %+v (%v)", page, err)
+	}
+	if text := resultText(callSession(t, today, map[string]interface{}{"action": "getContext"}, nil)); !strings.Contains(text, "emea") {
+		t.Errorf("Expected the variables set in the new session, got %s", text)
+	}
+
+	// Saving again keeps saved searches unless removed
+	callSession(t, yesterday, map[string]interface{}{
+		"action": "saveWorkspace", "workspace": "outage-42",
+		"searches": map[string]interface{}{"downServers": nil},
+	}, &saved)
+	if len(saved.Searches) != 0 {
+		t.Errorf("Expected the saved search removed, got %v", saved.Searches)
+	}
+
+	var list []types.WorkspaceSummary
+	callSession(t, today, map[string]interface{}{"action": "listWorkspaces"}, &list)
+	if len(list) != 1 || list[0].Name != "outage-42" {
+		t.Errorf("Expected one workspace, got %+v", list)
+	}
+	callSession(t, today, map[string]interface{}{"action": "deleteWorkspace", "workspace": "outage-42"}, nil)
+	if result := callSession(t, today, map[string]interface{}{"action": "loadWorkspace", "workspace": "outage-42"}, nil); !result.IsError {
+		t.Error("Expected a deleted workspace to be unknown")
+	}
+}
+
+func TestWorkspaceValidation(t *testing.T) {
+	useTestStore(t)
+	ctx := contextWithSession("workspace-3")
+
+	for _, args := range []map[string]interface{}{
+		{"action": "saveWorkspace", "workspace": "../etc"},
+		{"action": "saveWorkspace", "workspace": "w1", "searches": map[string]interface{}{"s": map[string]interface{}{"tool": "nope"}}},
+	} {
+		if result := callSession(t, ctx, args, nil); !result.IsError {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+}
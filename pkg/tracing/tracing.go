@@ -0,0 +1,106 @@
+// Package tracing exports OpenTelemetry traces of MCP requests, tool calls
+// and the OpsRamp API requests they make, so one tools/call can be followed
+// from the transport to OpsRamp and back.
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/opsramp/or-mcp-v2/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the server's spans
+const ScopeName = "github.com/opsramp/or-mcp-v2"
+
+// exportTimeout bounds each export to the collector
+const exportTimeout = 10 * time.Second
+
+// Tracer returns the server's tracer. Until Start configures an exporter it
+// is a no-op, so instrumented code costs next to nothing without tracing.
+func Tracer() trace.Tracer {
+	return otel.Tracer(ScopeName)
+}
+
+// Start installs a tracer provider that exports spans to the configured
+// OTLP/HTTP endpoint in batches, and the W3C trace context propagator. It
+// returns a function that flushes pending spans and stops the export; when
+// no endpoint is configured tracing stays off and the function does nothing.
+func Start(ctx context.Context, config common.TracingConfig, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if config.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(config.Endpoint),
+		otlptracehttp.WithHeaders(config.Headers),
+		otlptracehttp.WithTimeout(exportTimeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", config.ServiceName),
+		attribute.String("service.version", version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// End ends a span, recording err as its error when it is not nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// jsonRPCMessage holds the fields of a JSON-RPC message that name its span
+type jsonRPCMessage struct {
+	Method string `json:"method"`
+	Params struct {
+		Name string `json:"name"`
+	} `json:"params"`
+}
+
+// StartMessage starts a server span for a JSON-RPC message received over
+// transport, named after the message's method. The message is only peeked
+// at: batches and invalid JSON get a generic span.
+func StartMessage(ctx context.Context, transport string, message []byte) (context.Context, trace.Span) {
+	name := "jsonrpc"
+	attributes := []attribute.KeyValue{
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("mcp.transport", transport),
+	}
+	var parsed jsonRPCMessage
+	if err := json.Unmarshal(message, &parsed); err == nil && parsed.Method != "" {
+		name = parsed.Method
+		attributes = append(attributes, attribute.String("rpc.method", parsed.Method))
+		if parsed.Method == "tools/call" && parsed.Params.Name != "" {
+			attributes = append(attributes, attribute.String("mcp.tool.name", parsed.Params.Name))
+		}
+	}
+	return Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attributes...))
+}
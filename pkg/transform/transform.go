@@ -0,0 +1,698 @@
+// Package transform implements a small, jq-like expression language for
+// post-processing tool results on the server before they are returned.
+//
+// Supported syntax:
+//
+//	.                      identity
+//	.field .a.b ."x y"     field access (missing fields yield null)
+//	.[0] .[-1] .["key"]    index / key access
+//	.[]                    iterate over array elements or object values
+//	a | b                  pipe the outputs of a into b
+//	[expr]                 collect outputs into an array
+//	{name: .hostName, id}  construct an object
+//	a == b, !=, <, <=, >, >=   comparisons
+//	"str" 42 true false null   literals
+//	length keys first last     builtins
+//	select(cond) map(expr)     filtering and mapping
+//
+// An expression producing exactly one output returns that value; otherwise
+// all outputs are returned as an array.
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxExpressionLength bounds the size of accepted expressions
+const MaxExpressionLength = 512
+
+// maxOutputs bounds the number of values an expression may produce
+const maxOutputs = 100000
+
+// Expression is a compiled transform expression
+type Expression struct {
+	source string
+	root   node
+}
+
+// node evaluates an expression against one input, producing zero or more outputs
+type node func(input interface{}) ([]interface{}, error)
+
+// Compile parses a transform expression
+func Compile(source string) (*Expression, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, fmt.Errorf("transform expression cannot be empty")
+	}
+	if len(source) > MaxExpressionLength {
+		return nil, fmt.Errorf("transform expression exceeds %d characters", MaxExpressionLength)
+	}
+
+	p := &parser{src: source}
+	root, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+
+	return &Expression{source: source, root: root}, nil
+}
+
+// String returns the expression source
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Apply evaluates the expression against a value. The value is first
+// normalised through JSON so any result type can be transformed.
+func (e *Expression) Apply(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value: %w", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to decode value: %w", err)
+	}
+
+	outputs, err := e.root(generic)
+	if err != nil {
+		return nil, err
+	}
+	if len(outputs) == 1 {
+		return outputs[0], nil
+	}
+	if outputs == nil {
+		outputs = []interface{}{}
+	}
+	return outputs, nil
+}
+
+// Apply compiles and evaluates an expression in one step
+func Apply(source string, value interface{}) (interface{}, error) {
+	expr, err := Compile(source)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Apply(value)
+}
+
+// ============================================================================
+// PARSER
+// ============================================================================
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid transform at position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) consume(token string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(token string) error {
+	if !p.consume(token) {
+		return p.errorf("expected %q", token)
+	}
+	return nil
+}
+
+// parsePipe parses: comparison ('|' comparison)*
+func (p *parser) parsePipe() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("|") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = pipeNode(left, right)
+	}
+	return left, nil
+}
+
+// parseComparison parses: postfix (op postfix)?
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			right, err := p.parsePostfix()
+			if err != nil {
+				return nil, err
+			}
+			return compareNode(op, left, right), nil
+		}
+	}
+	return left, nil
+}
+
+// parsePostfix parses a primary term followed by path suffixes
+func (p *parser) parsePostfix() (node, error) {
+	current, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	// Suffixes must directly follow the term: ".a.b" is a path, ".a .b" is an error
+	for {
+		if p.pos >= len(p.src) {
+			return current, nil
+		}
+		switch p.src[p.pos] {
+		case '.':
+			if p.pos+1 < len(p.src) && p.src[p.pos+1] == '[' {
+				p.pos++
+				continue
+			}
+			p.pos++
+			key, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			current = pipeNode(current, fieldNode(key))
+		case '[':
+			suffix, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			current = pipeNode(current, suffix)
+		default:
+			return current, nil
+		}
+	}
+}
+
+// parsePrimary parses identity/paths, literals, arrays, objects and builtins
+func (p *parser) parsePrimary() (node, error) {
+	switch c := p.peek(); {
+	case c == '.':
+		p.pos++
+		if p.pos < len(p.src) && (isIdentStart(p.src[p.pos]) || p.src[p.pos] == '"') {
+			key, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			return fieldNode(key), nil
+		}
+		return identityNode, nil
+	case c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return literalNode(s), nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	case c == '[':
+		p.pos++
+		if p.consume("]") {
+			return literalNode([]interface{}{}), nil
+		}
+		inner, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return collectNode(inner), nil
+	case c == '{':
+		return p.parseObject()
+	case c == '(':
+		p.pos++
+		inner, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case isIdentStart(c):
+		return p.parseIdentifier()
+	case c == 0:
+		return nil, p.errorf("unexpected end of expression")
+	default:
+		return nil, p.errorf("unexpected %q", string(c))
+	}
+}
+
+// parseKey parses an identifier or quoted string used as a field name
+func (p *parser) parseKey() (string, error) {
+	if p.pos < len(p.src) && p.src[p.pos] == '"' {
+		return p.parseString()
+	}
+	start := p.pos
+	for p.pos < len(p.src) && isIdentChar(p.src[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("expected field name")
+	}
+	return p.src[start:p.pos], nil
+}
+
+// parseBracket parses [], [n] and ["key"] suffixes
+func (p *parser) parseBracket() (node, error) {
+	p.pos++ // '['
+	if p.consume("]") {
+		return iterateNode, nil
+	}
+
+	switch c := p.peek(); {
+	case c == '"':
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return fieldNode(key), p.expect("]")
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		if c == '-' {
+			p.pos++
+		}
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		index, err := strconv.Atoi(p.src[start:p.pos])
+		if err != nil {
+			return nil, p.errorf("invalid index")
+		}
+		return indexNode(index), p.expect("]")
+	default:
+		return nil, p.errorf("expected index, key or ]")
+	}
+}
+
+// parseObject parses {key: expr, key, "quoted": expr}
+func (p *parser) parseObject() (node, error) {
+	p.pos++ // '{'
+	type entry struct {
+		key   string
+		value node
+	}
+	var entries []entry
+
+	if !p.consume("}") {
+		for {
+			p.skipSpace()
+			key, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			value := fieldNode(key)
+			if p.consume(":") {
+				if value, err = p.parseComparison(); err != nil {
+					return nil, err
+				}
+			}
+			entries = append(entries, entry{key: key, value: value})
+			if p.consume("}") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return func(input interface{}) ([]interface{}, error) {
+		obj := make(map[string]interface{}, len(entries))
+		for _, e := range entries {
+			values, err := e.value(input)
+			if err != nil {
+				return nil, err
+			}
+			switch len(values) {
+			case 0:
+				obj[e.key] = nil
+			case 1:
+				obj[e.key] = values[0]
+			default:
+				obj[e.key] = values
+			}
+		}
+		return []interface{}{obj}, nil
+	}, nil
+}
+
+// parseIdentifier parses literals and builtin functions
+func (p *parser) parseIdentifier() (node, error) {
+	start := p.pos
+	for p.pos < len(p.src) && isIdentChar(p.src[p.pos]) {
+		p.pos++
+	}
+	name := p.src[start:p.pos]
+
+	switch name {
+	case "true":
+		return literalNode(true), nil
+	case "false":
+		return literalNode(false), nil
+	case "null":
+		return literalNode(nil), nil
+	case "length":
+		return lengthNode, nil
+	case "keys":
+		return keysNode, nil
+	case "first":
+		return indexNode(0), nil
+	case "last":
+		return indexNode(-1), nil
+	case "select", "map":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		arg, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if name == "select" {
+			return selectNode(arg), nil
+		}
+		return collectNode(pipeNode(iterateNode, arg)), nil
+	default:
+		return nil, fmt.Errorf("invalid transform: unknown function %q", name)
+	}
+}
+
+func (p *parser) parseString() (string, error) {
+	start := p.pos
+	p.pos++ // opening quote
+	for p.pos < len(p.src) && p.src[p.pos] != '"' {
+		if p.src[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		return "", p.errorf("unterminated string")
+	}
+	p.pos++ // closing quote
+	s, err := strconv.Unquote(p.src[start:p.pos])
+	if err != nil {
+		return "", p.errorf("invalid string literal")
+	}
+	return s, nil
+}
+
+func (p *parser) parseNumber() (node, error) {
+	start := p.pos
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil {
+		return nil, p.errorf("invalid number %q", p.src[start:p.pos])
+	}
+	return literalNode(f), nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// ============================================================================
+// EVALUATION
+// ============================================================================
+
+func identityNode(input interface{}) ([]interface{}, error) {
+	return []interface{}{input}, nil
+}
+
+func literalNode(value interface{}) node {
+	return func(interface{}) ([]interface{}, error) {
+		return []interface{}{value}, nil
+	}
+}
+
+func pipeNode(left, right node) node {
+	return func(input interface{}) ([]interface{}, error) {
+		values, err := left(input)
+		if err != nil {
+			return nil, err
+		}
+		var outputs []interface{}
+		for _, v := range values {
+			out, err := right(v)
+			if err != nil {
+				return nil, err
+			}
+			outputs = append(outputs, out...)
+			if len(outputs) > maxOutputs {
+				return nil, fmt.Errorf("transform produced more than %d values", maxOutputs)
+			}
+		}
+		return outputs, nil
+	}
+}
+
+func fieldNode(key string) node {
+	return func(input interface{}) ([]interface{}, error) {
+		switch v := input.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case map[string]interface{}:
+			return []interface{}{v[key]}, nil
+		default:
+			return nil, fmt.Errorf("cannot access field %q of %s", key, typeName(input))
+		}
+	}
+}
+
+func indexNode(index int) node {
+	return func(input interface{}) ([]interface{}, error) {
+		switch v := input.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case []interface{}:
+			i := index
+			if i < 0 {
+				i += len(v)
+			}
+			if i < 0 || i >= len(v) {
+				return []interface{}{nil}, nil
+			}
+			return []interface{}{v[i]}, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with a number", typeName(input))
+		}
+	}
+}
+
+func iterateNode(input interface{}) ([]interface{}, error) {
+	switch v := input.(type) {
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
+		keys := sortedKeys(v)
+		values := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, v[key])
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("cannot iterate over %s", typeName(input))
+	}
+}
+
+func collectNode(inner node) node {
+	return func(input interface{}) ([]interface{}, error) {
+		values, err := inner(input)
+		if err != nil {
+			return nil, err
+		}
+		if values == nil {
+			values = []interface{}{}
+		}
+		return []interface{}{values}, nil
+	}
+}
+
+func selectNode(cond node) node {
+	return func(input interface{}) ([]interface{}, error) {
+		results, err := cond(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			if truthy(r) {
+				return []interface{}{input}, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+func lengthNode(input interface{}) ([]interface{}, error) {
+	switch v := input.(type) {
+	case nil:
+		return []interface{}{float64(0)}, nil
+	case string:
+		return []interface{}{float64(len([]rune(v)))}, nil
+	case []interface{}:
+		return []interface{}{float64(len(v))}, nil
+	case map[string]interface{}:
+		return []interface{}{float64(len(v))}, nil
+	default:
+		return nil, fmt.Errorf("%s has no length", typeName(input))
+	}
+}
+
+func keysNode(input interface{}) ([]interface{}, error) {
+	v, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s has no keys", typeName(input))
+	}
+	keys := sortedKeys(v)
+	out := make([]interface{}, len(keys))
+	for i, key := range keys {
+		out[i] = key
+	}
+	return []interface{}{out}, nil
+}
+
+func compareNode(op string, left, right node) node {
+	return func(input interface{}) ([]interface{}, error) {
+		lefts, err := left(input)
+		if err != nil {
+			return nil, err
+		}
+		rights, err := right(input)
+		if err != nil {
+			return nil, err
+		}
+		var outputs []interface{}
+		for _, l := range lefts {
+			for _, r := range rights {
+				outputs = append(outputs, compare(op, l, r))
+			}
+		}
+		return outputs, nil
+	}
+}
+
+// compare applies a comparison operator. Numbers and strings are ordered;
+// other types only support equality.
+func compare(op string, l, r interface{}) bool {
+	switch op {
+	case "==":
+		return equal(l, r)
+	case "!=":
+		return !equal(l, r)
+	}
+
+	var cmp int
+	switch lv := l.(type) {
+	case float64:
+		rv, ok := r.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case lv < rv:
+			cmp = -1
+		case lv > rv:
+			cmp = 1
+		}
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(lv, rv)
+	default:
+		return false
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func equal(l, r interface{}) bool {
+	lj, lerr := json.Marshal(l)
+	rj, rerr := json.Marshal(r)
+	return lerr == nil && rerr == nil && string(lj) == string(rj)
+}
+
+func truthy(v interface{}) bool {
+	return v != nil && v != false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,78 @@
+package transform
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const sampleResults = `{
+	"totalResults": 3,
+	"results": [
+		{"id": "r1", "hostName": "web-01", "state": "active", "cpu": 12, "tags": [{"name": "env", "value": "prod"}]},
+		{"id": "r2", "hostName": "web-02", "state": "inactive", "cpu": 85},
+		{"id": "r3", "hostName": "db-01", "state": "active", "cpu": 40}
+	]
+}`
+
+func sample(t *testing.T) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(sampleResults), &v); err != nil {
+		t.Fatalf("Failed to decode sample: %v", err)
+	}
+	return v
+}
+
+func TestApplyExpressions(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`.`, ``},
+		{`.totalResults`, `3`},
+		{`.results[].hostName`, `["web-01","web-02","db-01"]`},
+		{`.results[0].id`, `"r1"`},
+		{`.results[-1].hostName`, `"db-01"`},
+		{`.results | length`, `3`},
+		{`.results[] | select(.state == "active") | .id`, `["r1","r3"]`},
+		{`[.results[] | select(.cpu > 30) | .hostName]`, `["web-02","db-01"]`},
+		{`.results | map({id, host: .hostName})`, `[{"host":"web-01","id":"r1"},{"host":"web-02","id":"r2"},{"host":"db-01","id":"r3"}]`},
+		{`.results[0].tags[0]["value"]`, `"prod"`},
+		{`.results[1].tags`, `null`},
+		{`.results | first | keys`, `["cpu","hostName","id","state","tags"]`},
+		{`.results[] | select(.state == "missing")`, `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Apply(tt.expr, sample(t))
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if tt.want == "" {
+				return
+			}
+			data, _ := json.Marshal(got)
+			if string(data) != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, data)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{``, `.results[`, `.results | unknownfn`, `{id`, `"unterminated`, `.a .b`} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Expected compile error for %q", expr)
+		}
+	}
+}
+
+func TestApplyTypeErrors(t *testing.T) {
+	if _, err := Apply(`.totalResults[]`, sample(t)); err == nil {
+		t.Error("Expected error iterating over a number")
+	}
+	if _, err := Apply(`.results.hostName`, sample(t)); err == nil {
+		t.Error("Expected error accessing a field of an array")
+	}
+}
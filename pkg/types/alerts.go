@@ -0,0 +1,250 @@
+package types
+
+// AlertDetails represents an OpsRamp alert
+type AlertDetails struct {
+	ID           string                 `json:"id"`
+	UniqueID     string                 `json:"uniqueId,omitempty"`
+	Subject      string                 `json:"subject"`
+	Description  string                 `json:"description,omitempty"`
+	CurrentState string                 `json:"currentState"`
+	Status       string                 `json:"status,omitempty"`
+	Priority     string                 `json:"priority,omitempty"`
+	AlertType    string                 `json:"alertType,omitempty"`
+	Component    string                 `json:"component,omitempty"`
+	Metric       string                 `json:"serviceName,omitempty"`
+	Resource     *AlertResource         `json:"resource,omitempty"`
+	CreatedDate  string                 `json:"createdDate"`
+	UpdatedTime  string                 `json:"updatedTime,omitempty"`
+	RepeatCount  int                    `json:"repeatCount,omitempty"`
+	Properties   map[string]interface{} `json:"properties,omitempty"`
+	// DedupKey identifies the alerts OpsRamp folds into one by incrementing
+	// RepeatCount: those on the same resource, metric and component
+	DedupKey string `json:"dedupKey,omitempty"`
+}
+
+// AlertSearchParams represents parameters for searching alerts
+type AlertSearchParams struct {
+	QueryString string `json:"queryString,omitempty"`
+	PageNo      int    `json:"pageNo,omitempty"`
+	PageSize    int    `json:"pageSize,omitempty"`
+	SortName    string `json:"sortName,omitempty"`
+	SortType    string `json:"sortType,omitempty"`
+}
+
+// AlertSearchResponse is a page of alert search results
+type AlertSearchResponse struct {
+	Results         []AlertDetails `json:"results"`
+	TotalResults    int            `json:"totalResults"`
+	PageNo          int            `json:"pageNo"`
+	PageSize        int            `json:"pageSize"`
+	TotalPages      int            `json:"totalPages"`
+	NextPage        bool           `json:"nextPage"`
+	DescendingOrder bool           `json:"descendingOrder"`
+	Pagination      *PageInfo      `json:"pagination,omitempty"`
+	// Muted and Collapsed count the alerts removed from Results by custom alert rules
+	Muted     int `json:"muted,omitempty"`
+	Collapsed int `json:"collapsed,omitempty"`
+}
+
+// AlertResource identifies the resource an alert was raised on
+type AlertResource struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ResourceType string `json:"resourceType,omitempty"`
+}
+
+// AlertNote represents a note (comment) in an alert's collaboration thread
+type AlertNote struct {
+	ID          string            `json:"id,omitempty"`
+	Text        string            `json:"text"`
+	Author      string            `json:"author,omitempty"`
+	CreatedDate string            `json:"createdDate,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// AlertNoteList is a page of alert notes
+type AlertNoteList struct {
+	Results      []AlertNote `json:"results"`
+	TotalResults int         `json:"totalResults"`
+}
+
+// AlertAttachment describes a file attached to an alert. Only metadata is
+// returned; file contents are not downloaded.
+type AlertAttachment struct {
+	ID           string `json:"id"`
+	FileName     string `json:"fileName"`
+	ContentType  string `json:"contentType,omitempty"`
+	Size         int64  `json:"size,omitempty"`
+	UploadedBy   string `json:"uploadedBy,omitempty"`
+	UploadedDate string `json:"uploadedDate,omitempty"`
+}
+
+// AlertAttachmentList is a page of alert attachments
+type AlertAttachmentList struct {
+	Results      []AlertAttachment `json:"results"`
+	TotalResults int               `json:"totalResults"`
+}
+
+// AlertThread is the full collaboration thread for an alert. Notes or
+// attachments that could not be retrieved are reported in Errors.
+type AlertThread struct {
+	AlertID     string            `json:"alertId"`
+	Notes       []AlertNote       `json:"notes"`
+	Attachments []AlertAttachment `json:"attachments"`
+	Errors      map[string]string `json:"errors,omitempty"`
+}
+
+// AlertNoteRequest is a structured note to post on an alert. Author is the
+// MCP service account; the original requester is captured in Metadata.
+type AlertNoteRequest struct {
+	Text     string            `json:"text"`
+	Author   string            `json:"author"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// AlertRule is a custom rule for noisy checks. A mute rule hides matching
+// alerts; a dedup rule collapses matching alerts with the same dedup key
+// raised within WindowMinutes of each other into the first. Empty match
+// fields match any value and Metric and Component may use * wildcards.
+type AlertRule struct {
+	Name          string `json:"name"`
+	Action        string `json:"action"`
+	ResourceID    string `json:"resourceId,omitempty"`
+	Metric        string `json:"metric,omitempty"`
+	Component     string `json:"component,omitempty"`
+	WindowMinutes int    `json:"windowMinutes,omitempty"`
+	CreatedAt     string `json:"createdAt,omitempty"`
+}
+
+// AlertRulePreview reports how many alerts in a time range a rule would have
+// muted or collapsed
+type AlertRulePreview struct {
+	Rule      AlertRule `json:"rule"`
+	Scanned   int       `json:"scanned"`
+	Matched   int       `json:"matched"`
+	Muted     int       `json:"muted"`
+	Collapsed int       `json:"collapsed"`
+	// Remaining is how many of the matched alerts would still be shown
+	Remaining int  `json:"remaining"`
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// EscalationPolicy is an OpsRamp escalation policy: the alerts it applies to
+// and who is notified at each escalation level
+type EscalationPolicy struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Mode is ON for an active policy and OFF for a disabled one
+	Mode   string            `json:"mode,omitempty"`
+	Filter EscalationFilter  `json:"filterCriteria"`
+	Levels []EscalationLevel `json:"escalations"`
+}
+
+// EscalationFilter selects the alerts an escalation policy applies to. Empty
+// fields match any value and Metrics and Components may use * wildcards.
+type EscalationFilter struct {
+	ResourceIDs   []string `json:"resourceIds,omitempty"`
+	ResourceTypes []string `json:"resourceTypes,omitempty"`
+	Metrics       []string `json:"metrics,omitempty"`
+	Components    []string `json:"components,omitempty"`
+	States        []string `json:"alertStates,omitempty"`
+}
+
+// EscalationLevel is one step of an escalation policy. Its recipients are
+// notified DelayMinutes after the alert is raised if it is still open, then
+// again every RepeatMinutes up to RepeatCount times.
+type EscalationLevel struct {
+	DelayMinutes int      `json:"delay"`
+	Teams        []string `json:"teams,omitempty"`
+	Users        []string `json:"users,omitempty"`
+	// Channels are the notification types used, e.g. EMAIL, SMS or VOICE
+	Channels      []string `json:"channels,omitempty"`
+	RepeatMinutes int      `json:"repeatInterval,omitempty"`
+	RepeatCount   int      `json:"repeatCount,omitempty"`
+}
+
+// EscalationPolicyList is a page of escalation policies
+type EscalationPolicyList struct {
+	Results      []EscalationPolicy `json:"results"`
+	TotalResults int                `json:"totalResults"`
+}
+
+// AlertRoutingQuery is the alert, real or hypothetical, whose routing is
+// previewed
+type AlertRoutingQuery struct {
+	AlertID      string `json:"alertId,omitempty"`
+	ResourceID   string `json:"resourceId,omitempty"`
+	ResourceType string `json:"resourceType,omitempty"`
+	Metric       string `json:"metric,omitempty"`
+	Component    string `json:"component,omitempty"`
+	Severity     string `json:"severity"`
+}
+
+// AlertNotification is one notification an open alert would trigger
+type AlertNotification struct {
+	AfterMinutes int      `json:"afterMinutes"`
+	Policy       string   `json:"policy"`
+	Level        int      `json:"level"`
+	Teams        []string `json:"teams,omitempty"`
+	Users        []string `json:"users,omitempty"`
+	Channels     []string `json:"channels,omitempty"`
+	// Repeat marks a repeated notification of the level
+	Repeat bool `json:"repeat,omitempty"`
+}
+
+// AlertRoutingPreview reports who would be notified about an alert that
+// stays open, and when
+type AlertRoutingPreview struct {
+	Alert AlertRoutingQuery `json:"alert"`
+	// Policies are the active escalation policies that apply to the alert
+	Policies []string `json:"policies"`
+	// Disabled are the policies that would apply if they were turned on
+	Disabled      []string            `json:"disabled,omitempty"`
+	Notifications []AlertNotification `json:"notifications"`
+	Teams         []string            `json:"teams"`
+	Channels      []string            `json:"channels"`
+	// Unrouted means no active policy applies, so nobody is notified
+	Unrouted  bool `json:"unrouted"`
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Alert actions applied in batches
+const (
+	AlertActionAcknowledge = "acknowledge"
+	AlertActionClose       = "close"
+)
+
+// AlertActionRequest applies an action to a batch of alerts, recording
+// Description in each alert's history
+type AlertActionRequest struct {
+	Description string           `json:"description,omitempty"`
+	Alerts      []AlertActionRef `json:"alerts"`
+}
+
+// AlertActionRef identifies an alert in an AlertActionRequest
+type AlertActionRef struct {
+	ID string `json:"id"`
+}
+
+// AlertActionOutcome is the outcome of a batch action for one alert
+type AlertActionOutcome struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// AlertBatchResult is the outcome of a batch acknowledge or close. Alerts
+// selected by a query are previewed until confirmed: Executed is false and
+// Targets lists the matching alerts.
+type AlertBatchResult struct {
+	Action    string               `json:"action"`
+	Query     string               `json:"query,omitempty"`
+	Count     int                  `json:"count"`
+	Targets   []string             `json:"targets,omitempty"`
+	Executed  bool                 `json:"executed"`
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Results   []AlertActionOutcome `json:"results,omitempty"`
+	Message   string               `json:"message"`
+}
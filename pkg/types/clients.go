@@ -0,0 +1,21 @@
+package types
+
+// PartnerClient is a client tenant managed by an MSP partner
+type PartnerClient struct {
+	UniqueID    string `json:"uniqueId"`
+	Name        string `json:"name"`
+	Status      string `json:"status,omitempty"`
+	TimeZone    string `json:"timeZone,omitempty"`
+	Country     string `json:"country,omitempty"`
+	CreatedDate string `json:"createdDate,omitempty"`
+}
+
+// PartnerClientSearchResponse is a page of clients under a partner
+type PartnerClientSearchResponse struct {
+	Results      []PartnerClient `json:"results"`
+	TotalResults int             `json:"totalResults"`
+	PageNo       int             `json:"pageNo"`
+	PageSize     int             `json:"pageSize"`
+	NextPage     bool            `json:"nextPage"`
+	Pagination   *PageInfo       `json:"pagination,omitempty"`
+}
@@ -0,0 +1,49 @@
+package types
+
+// ResultDiff is a structured comparison of two tool results
+type ResultDiff struct {
+	KeyField  string         `json:"keyField,omitempty"`
+	Added     []interface{}  `json:"added"`
+	Removed   []interface{}  `json:"removed"`
+	Changed   []ChangedEntry `json:"changed"`
+	Unchanged int            `json:"unchanged"`
+	Summary   string         `json:"summary"`
+}
+
+// ChangedEntry lists the field changes of one entry present in both results
+type ChangedEntry struct {
+	Key     string        `json:"key"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange is a single changed value, addressed by a dotted path
+type FieldChange struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// ResultHandleSummary stands in for a result too large to return inline. The
+// full result stays on the server under ResultHandle for follow-up actions.
+type ResultHandleSummary struct {
+	ResultHandle string        `json:"resultHandle"`
+	Tool         string        `json:"tool,omitempty"`
+	Bytes        int           `json:"bytes"`
+	ExpiresAt    string        `json:"expiresAt"`
+	Entries      int           `json:"entries,omitempty"`
+	TotalResults int           `json:"totalResults,omitempty"`
+	Fields       []string      `json:"fields,omitempty"`
+	Preview      []interface{} `json:"preview,omitempty"`
+	Hint         string        `json:"hint"`
+}
+
+// ResultPage is a slice of the entries of a stored result
+type ResultPage struct {
+	ResultHandle string        `json:"resultHandle"`
+	Offset       int           `json:"offset"`
+	Count        int           `json:"count"`
+	Total        int           `json:"total"`
+	NextOffset   int           `json:"nextOffset,omitempty"`
+	Done         bool          `json:"done"`
+	Entries      []interface{} `json:"entries"`
+}
@@ -0,0 +1,13 @@
+package types
+
+// ExportArtifact describes an exported result registered as an MCP resource.
+// Clients fetch the content with resources/read on URI.
+type ExportArtifact struct {
+	URI       string `json:"uri"`
+	Name      string `json:"name"`
+	MIMEType  string `json:"mimeType"`
+	Size      int    `json:"size"`
+	Entries   int    `json:"entries,omitempty"`
+	ExpiresAt string `json:"expiresAt"`
+	Hint      string `json:"hint"`
+}
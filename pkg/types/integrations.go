@@ -55,3 +55,44 @@ type IntegrationType struct {
 	Category     string                 `json:"category"`
 	ConfigSchema map[string]interface{} `json:"configSchema"`
 }
+
+// IntegrationActivity is the evidence gathered about whether an installed
+// integration is still doing anything
+type IntegrationActivity struct {
+	IntegrationID       string            `json:"integrationId"`
+	Name                string            `json:"name"`
+	App                 string            `json:"app,omitempty"`
+	State               string            `json:"state,omitempty"`
+	InstalledTime       string            `json:"installedTime,omitempty"`
+	Resources           int               `json:"resources"`
+	DiscoveredResources int               `json:"discoveredResources"`
+	LastDiscoveryRun    *DiscoveryRunInfo `json:"lastDiscoveryRun,omitempty"`
+	Events              int               `json:"events"`
+	LastEventTime       string            `json:"lastEventTime,omitempty"`
+	Orphaned            bool              `json:"orphaned"`
+	Evidence            []string          `json:"evidence"`
+}
+
+// OrphanedIntegrationsReport flags installed integrations that have no
+// resources and no event activity within the window
+type OrphanedIntegrationsReport struct {
+	WindowStart      string                `json:"windowStart"`
+	WindowEnd        string                `json:"windowEnd"`
+	Installed        int                   `json:"installed"`
+	OrphanedCount    int                   `json:"orphanedCount"`
+	Integrations     []IntegrationActivity `json:"integrations"`
+	ResourcesScanned int                   `json:"resourcesScanned"`
+	EventsScanned    int                   `json:"eventsScanned"`
+	Truncated        bool                  `json:"truncated,omitempty"`
+	Unavailable      map[string]string     `json:"unavailable,omitempty"`
+}
+
+// IntegrationClone is the result of cloning an installed integration
+type IntegrationClone struct {
+	SourceID     string       `json:"sourceId"`
+	TargetTenant string       `json:"targetTenant,omitempty"`
+	Integration  *Integration `json:"integration"`
+	// BlankedFields lists the credential fields that must be filled in
+	// before the clone can connect
+	BlankedFields []string `json:"blankedFields"`
+}
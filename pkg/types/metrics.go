@@ -0,0 +1,76 @@
+package types
+
+// MetricRollups are the rollup intervals time-series queries accept, finest
+// first. Raw data is returned without a rollup.
+var MetricRollups = []string{"5m", "15m", "1h", "6h", "1d"}
+
+// MetricDefinition is a metric OpsRamp collects
+type MetricDefinition struct {
+	Name         string `json:"name"`
+	DisplayName  string `json:"displayName,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Unit         string `json:"unit,omitempty"`
+	ResourceType string `json:"resourceType,omitempty"`
+	// Dimensioned metrics, such as per-disk usage, have instances
+	Dimensioned bool `json:"dimensioned,omitempty"`
+}
+
+// MetricSearchResponse is a page of metric definitions
+type MetricSearchResponse struct {
+	Results      []MetricDefinition `json:"results"`
+	TotalResults int                `json:"totalResults"`
+	PageNo       int                `json:"pageNo"`
+	PageSize     int                `json:"pageSize"`
+	TotalPages   int                `json:"totalPages"`
+	NextPage     bool               `json:"nextPage"`
+	Pagination   *PageInfo          `json:"pagination,omitempty"`
+}
+
+// ResourceMetricNames lists the metrics collected on a resource
+type ResourceMetricNames struct {
+	ResourceID string             `json:"resourceId"`
+	Metrics    []MetricDefinition `json:"metrics"`
+}
+
+// MetricQuery requests the time series of a metric on one or more resources
+type MetricQuery struct {
+	Metric      string   `json:"metric"`
+	ResourceIDs []string `json:"resourceIds"`
+	// Instance restricts a dimensioned metric to one of its instances
+	Instance  string `json:"instance,omitempty"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+	// Rollup is one of MetricRollups; empty returns raw data
+	Rollup string `json:"rollup,omitempty"`
+	// Aggregation combines the raw points of each rollup interval: avg, min, max or sum
+	Aggregation string `json:"aggregation,omitempty"`
+}
+
+// MetricPoint is one value of a time series
+type MetricPoint struct {
+	Timestamp string  `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// MetricSeries is the time series of a metric on one resource, or on one
+// instance of a dimensioned metric
+type MetricSeries struct {
+	Metric     string            `json:"metric"`
+	ResourceID string            `json:"resourceId"`
+	Instance   string            `json:"instance,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Unit       string            `json:"unit,omitempty"`
+	Points     []MetricPoint     `json:"points"`
+}
+
+// MetricQueryResult is the answer to a MetricQuery
+type MetricQueryResult struct {
+	Metric      string         `json:"metric"`
+	StartTime   string         `json:"startTime"`
+	EndTime     string         `json:"endTime"`
+	Rollup      string         `json:"rollup,omitempty"`
+	Aggregation string         `json:"aggregation,omitempty"`
+	Series      []MetricSeries `json:"series"`
+	// Message explains an empty result or a rollup chosen by the server
+	Message string `json:"message,omitempty"`
+}
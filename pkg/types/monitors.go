@@ -0,0 +1,144 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxMonitorScriptBytes is the largest script a custom monitor may carry
+const MaxMonitorScriptBytes = 64 * 1024
+
+// MonitorScriptTypes are the script languages OpsRamp agents can run
+var MonitorScriptTypes = []string{"shell", "powershell", "python", "perl"}
+
+// MonitorMetric is a metric emitted by a custom monitor script
+type MonitorMetric struct {
+	Name              string   `json:"name"`
+	Unit              string   `json:"unit,omitempty"`
+	WarningThreshold  *float64 `json:"warningThreshold,omitempty"`
+	CriticalThreshold *float64 `json:"criticalThreshold,omitempty"`
+}
+
+// CustomMonitor is a script monitor run by the OpsRamp agent on assigned resources
+type CustomMonitor struct {
+	ID                string          `json:"id"`
+	Name              string          `json:"name"`
+	Description       string          `json:"description,omitempty"`
+	ScriptType        string          `json:"scriptType"`
+	Platform          string          `json:"platform,omitempty"`
+	Script            string          `json:"script,omitempty"`
+	FrequencyMinutes  int             `json:"frequencyMinutes,omitempty"`
+	TimeoutSeconds    int             `json:"timeoutSeconds,omitempty"`
+	Metrics           []MonitorMetric `json:"metrics,omitempty"`
+	AssignedResources int             `json:"assignedResources"`
+	CreatedDate       string          `json:"createdDate,omitempty"`
+	UpdatedDate       string          `json:"updatedDate,omitempty"`
+}
+
+// CustomMonitorSearchResponse is a page of custom monitors
+type CustomMonitorSearchResponse struct {
+	Results      []CustomMonitor `json:"results"`
+	TotalResults int             `json:"totalResults"`
+	PageNo       int             `json:"pageNo"`
+	PageSize     int             `json:"pageSize"`
+	TotalPages   int             `json:"totalPages"`
+	NextPage     bool            `json:"nextPage"`
+	Pagination   *PageInfo       `json:"pagination,omitempty"`
+}
+
+// CustomMonitorRequest creates or updates a custom monitor. On update only
+// the fields that are set are changed.
+type CustomMonitorRequest struct {
+	Name             string          `json:"name,omitempty"`
+	Description      string          `json:"description,omitempty"`
+	ScriptType       string          `json:"scriptType,omitempty"`
+	Platform         string          `json:"platform,omitempty"`
+	Script           string          `json:"script,omitempty"`
+	FrequencyMinutes int             `json:"frequencyMinutes,omitempty"`
+	TimeoutSeconds   int             `json:"timeoutSeconds,omitempty"`
+	Metrics          []MonitorMetric `json:"metrics,omitempty"`
+}
+
+// Validate checks a create (complete) or update (partial) request
+func (r *CustomMonitorRequest) Validate(create bool) error {
+	if create {
+		var missing []string
+		if strings.TrimSpace(r.Name) == "" {
+			missing = append(missing, "name")
+		}
+		if r.ScriptType == "" {
+			missing = append(missing, "scriptType")
+		}
+		if r.Script == "" {
+			missing = append(missing, "script")
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+		}
+	}
+
+	if r.ScriptType != "" && !containsFold(MonitorScriptTypes, r.ScriptType) {
+		return fmt.Errorf("unsupported scriptType %q: must be one of %s", r.ScriptType, strings.Join(MonitorScriptTypes, ", "))
+	}
+	if r.Script != "" {
+		if err := ValidateMonitorScript(r.Script); err != nil {
+			return err
+		}
+	}
+	if r.FrequencyMinutes < 0 || r.FrequencyMinutes > 1440 {
+		return fmt.Errorf("frequencyMinutes must be between 1 and 1440")
+	}
+	if r.TimeoutSeconds < 0 || r.TimeoutSeconds > 3600 {
+		return fmt.Errorf("timeoutSeconds must be between 1 and 3600")
+	}
+	if r.FrequencyMinutes > 0 && r.TimeoutSeconds > r.FrequencyMinutes*60 {
+		return fmt.Errorf("timeoutSeconds (%d) cannot exceed the run interval (%d minutes)", r.TimeoutSeconds, r.FrequencyMinutes)
+	}
+	for _, metric := range r.Metrics {
+		if strings.TrimSpace(metric.Name) == "" {
+			return fmt.Errorf("every metric needs a name")
+		}
+	}
+	return nil
+}
+
+// ValidateMonitorScript checks that script content is text within the size limit
+func ValidateMonitorScript(script string) error {
+	if len(script) > MaxMonitorScriptBytes {
+		return fmt.Errorf("script is %d bytes; the limit is %d bytes (%d KiB)", len(script), MaxMonitorScriptBytes, MaxMonitorScriptBytes/1024)
+	}
+	if !utf8.ValidString(script) || strings.ContainsRune(script, 0) {
+		return fmt.Errorf("script must be UTF-8 text, not binary content")
+	}
+	if strings.TrimSpace(script) == "" {
+		return fmt.Errorf("script cannot be blank")
+	}
+	return nil
+}
+
+// MonitorAssignment is the result of assigning or unassigning a custom monitor
+type MonitorAssignment struct {
+	MonitorID   string   `json:"monitorId"`
+	Action      string   `json:"action"`
+	ResourceIDs []string `json:"resourceIds"`
+}
+
+// MonitorAssignedResource is a resource a custom monitor is assigned to
+type MonitorAssignedResource struct {
+	ResourceID   string `json:"resourceId"`
+	Name         string `json:"name,omitempty"`
+	Status       string `json:"status,omitempty"`
+	LastRunTime  string `json:"lastRunTime,omitempty"`
+	LastRunError string `json:"lastRunError,omitempty"`
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package types
+
+// PageInfo is pagination metadata computed from what was actually fetched,
+// so it stays consistent even when upstream totals are missing or stale
+type PageInfo struct {
+	PageNo       int  `json:"pageNo"`
+	PageSize     int  `json:"pageSize"`
+	FetchedCount int  `json:"fetchedCount"`
+	HasMore      bool `json:"hasMore"`
+	// EstimatedTotal is exact when TotalExact is set (the last page was
+	// reached); otherwise it is a lower bound informed by upstream totals
+	EstimatedTotal int  `json:"estimatedTotal"`
+	TotalExact     bool `json:"totalExact"`
+}
+
+// TokenPage is a page from an OpsRamp endpoint that pages by opaque
+// continuation token rather than page number. NextPageToken fetches the next
+// page and is empty on the last one.
+type TokenPage[T any] struct {
+	Results       []T    `json:"results"`
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// PageCounts are the pagination fields reported by an OpsRamp search endpoint
+type PageCounts struct {
+	PageNo       int
+	PageSize     int
+	TotalResults int
+	TotalPages   int
+	NextPage     bool
+}
+
+// NormalizePage computes reliable pagination metadata for a fetched page.
+// Endpoints report totalResults, totalPages and nextPage inconsistently (some
+// omit them, some go stale as data changes), so the page contents win: a short
+// page is always the last one, and the total is never less than what has
+// been seen.
+func NormalizePage(requestedPageNo, requestedPageSize, fetched int, upstream PageCounts) PageInfo {
+	info := PageInfo{PageNo: requestedPageNo, PageSize: upstream.PageSize, FetchedCount: fetched}
+	if info.PageNo <= 0 {
+		info.PageNo = max(upstream.PageNo, 1)
+	}
+	if info.PageSize <= 0 {
+		info.PageSize = requestedPageSize
+	}
+	info.PageSize = max(info.PageSize, fetched)
+
+	seen := (info.PageNo-1)*info.PageSize + fetched
+	switch {
+	case fetched == 0 || fetched < info.PageSize:
+		info.HasMore = false
+	default:
+		info.HasMore = upstream.NextPage || upstream.TotalResults > seen || upstream.TotalPages > info.PageNo
+	}
+
+	if !info.HasMore {
+		// Past the last page nothing was seen on this page, so fall back to
+		// the upstream total for the pages before it
+		if fetched == 0 && info.PageNo > 1 {
+			info.EstimatedTotal = min(upstream.TotalResults, seen)
+			return info
+		}
+		info.EstimatedTotal = seen
+		info.TotalExact = true
+		return info
+	}
+
+	if upstream.TotalResults > seen {
+		info.EstimatedTotal = upstream.TotalResults
+	} else {
+		info.EstimatedTotal = max(upstream.TotalPages*info.PageSize, seen+1)
+	}
+	return info
+}
+
+// TotalPages is the page count implied by the estimated total
+func (p PageInfo) TotalPages() int {
+	if p.PageSize <= 0 {
+		return 0
+	}
+	return (p.EstimatedTotal + p.PageSize - 1) / p.PageSize
+}
+
+// Normalize replaces the upstream pagination fields with reliable values
+// for a search made with the given page number and size
+func (r *ResourceSearchResponse) Normalize(pageNo, pageSize int) {
+	info := NormalizePage(pageNo, pageSize, len(r.Results), PageCounts{
+		PageNo: r.PageNo, PageSize: r.PageSize, TotalResults: r.TotalResults, TotalPages: r.TotalPages, NextPage: r.NextPage,
+	})
+	r.Pagination = &info
+	r.PageNo, r.PageSize, r.TotalResults, r.TotalPages, r.NextPage = info.PageNo, info.PageSize, info.EstimatedTotal, info.TotalPages(), info.HasMore
+}
+
+// Normalize replaces the upstream pagination fields with reliable values
+// for a search made with the given page number and size
+func (r *AlertSearchResponse) Normalize(pageNo, pageSize int) {
+	info := NormalizePage(pageNo, pageSize, len(r.Results), PageCounts{
+		PageNo: r.PageNo, PageSize: r.PageSize, TotalResults: r.TotalResults, TotalPages: r.TotalPages, NextPage: r.NextPage,
+	})
+	r.Pagination = &info
+	r.PageNo, r.PageSize, r.TotalResults, r.TotalPages, r.NextPage = info.PageNo, info.PageSize, info.EstimatedTotal, info.TotalPages(), info.HasMore
+}
+
+// Normalize replaces the upstream pagination fields with reliable values
+// for a search made with the given page number and size
+func (r *PartnerClientSearchResponse) Normalize(pageNo, pageSize int) {
+	info := NormalizePage(pageNo, pageSize, len(r.Results), PageCounts{
+		PageNo: r.PageNo, PageSize: r.PageSize, TotalResults: r.TotalResults, NextPage: r.NextPage,
+	})
+	r.Pagination = &info
+	r.PageNo, r.PageSize, r.TotalResults, r.NextPage = info.PageNo, info.PageSize, info.EstimatedTotal, info.HasMore
+}
+
+// Normalize replaces the upstream pagination fields with reliable values
+// for a search made with the given page number and size
+func (r *CustomMonitorSearchResponse) Normalize(pageNo, pageSize int) {
+	info := NormalizePage(pageNo, pageSize, len(r.Results), PageCounts{
+		PageNo: r.PageNo, PageSize: r.PageSize, TotalResults: r.TotalResults, TotalPages: r.TotalPages, NextPage: r.NextPage,
+	})
+	r.Pagination = &info
+	r.PageNo, r.PageSize, r.TotalResults, r.TotalPages, r.NextPage = info.PageNo, info.PageSize, info.EstimatedTotal, info.TotalPages(), info.HasMore
+}
+
+// Normalize replaces the upstream pagination fields with reliable values
+// for a search made with the given page number and size
+func (r *MetricSearchResponse) Normalize(pageNo, pageSize int) {
+	info := NormalizePage(pageNo, pageSize, len(r.Results), PageCounts{
+		PageNo: r.PageNo, PageSize: r.PageSize, TotalResults: r.TotalResults, TotalPages: r.TotalPages, NextPage: r.NextPage,
+	})
+	r.Pagination = &info
+	r.PageNo, r.PageSize, r.TotalResults, r.TotalPages, r.NextPage = info.PageNo, info.PageSize, info.EstimatedTotal, info.TotalPages(), info.HasMore
+}
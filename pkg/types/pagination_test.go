@@ -0,0 +1,73 @@
+package types
+
+import "testing"
+
+func TestNormalizePage(t *testing.T) {
+	tests := []struct {
+		name     string
+		pageNo   int
+		pageSize int
+		fetched  int
+		upstream PageCounts
+		want     PageInfo
+	}{
+		{
+			name: "consistent middle page", pageNo: 2, pageSize: 10, fetched: 10,
+			upstream: PageCounts{PageNo: 2, PageSize: 10, TotalResults: 35, TotalPages: 4, NextPage: true},
+			want:     PageInfo{PageNo: 2, PageSize: 10, FetchedCount: 10, HasMore: true, EstimatedTotal: 35},
+		},
+		{
+			name: "short page ends results despite nextPage", pageNo: 4, pageSize: 10, fetched: 3,
+			upstream: PageCounts{TotalResults: 40, NextPage: true},
+			want:     PageInfo{PageNo: 4, PageSize: 10, FetchedCount: 3, EstimatedTotal: 33, TotalExact: true},
+		},
+		{
+			name: "stale total below what was seen", pageNo: 3, pageSize: 10, fetched: 10,
+			upstream: PageCounts{TotalResults: 25, NextPage: true},
+			want:     PageInfo{PageNo: 3, PageSize: 10, FetchedCount: 10, HasMore: true, EstimatedTotal: 31},
+		},
+		{
+			name: "no upstream metadata on a full last page", pageNo: 1, pageSize: 5, fetched: 5,
+			want: PageInfo{PageNo: 1, PageSize: 5, FetchedCount: 5, EstimatedTotal: 5, TotalExact: true},
+		},
+		{
+			name: "server caps the page size", pageNo: 1, pageSize: 1000, fetched: 500,
+			upstream: PageCounts{PageSize: 500, TotalResults: 1200},
+			want:     PageInfo{PageNo: 1, PageSize: 500, FetchedCount: 500, HasMore: true, EstimatedTotal: 1200},
+		},
+		{
+			name: "past the last page", pageNo: 5, pageSize: 10, fetched: 0,
+			upstream: PageCounts{TotalResults: 32, NextPage: true},
+			want:     PageInfo{PageNo: 5, PageSize: 10, EstimatedTotal: 32},
+		},
+		{
+			name: "defaults when nothing was requested", fetched: 0,
+			want: PageInfo{PageNo: 1, TotalExact: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizePage(tt.pageNo, tt.pageSize, tt.fetched, tt.upstream); got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAlertSearchResponseNormalize(t *testing.T) {
+	response := &AlertSearchResponse{
+		Results:      make([]AlertDetails, 2),
+		TotalResults: 0,
+		TotalPages:   0,
+		NextPage:     true,
+	}
+	response.Normalize(1, 50)
+
+	if response.NextPage || response.TotalResults != 2 || response.TotalPages != 1 || response.PageSize != 50 {
+		t.Errorf("Expected upstream fields to be normalized, got %+v", response)
+	}
+	if response.Pagination == nil || !response.Pagination.TotalExact {
+		t.Errorf("Expected exact pagination metadata, got %+v", response.Pagination)
+	}
+}
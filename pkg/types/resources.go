@@ -3,6 +3,7 @@ package types
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Resource represents an OpsRamp resource
@@ -33,6 +34,7 @@ type Resource struct {
 	ManagementProfile         *ManagementProfile     `json:"managementProfile,omitempty"`
 	DNSName                   string                 `json:"dnsName,omitempty"`
 	SerialNumber              string                 `json:"serialNumber,omitempty"`
+	ExtResourceID             string                 `json:"extResourceId,omitempty"`
 	Make                      string                 `json:"make,omitempty"`
 	Model                     string                 `json:"model,omitempty"`
 	SystemUID                 string                 `json:"systemUID,omitempty"`
@@ -123,6 +125,28 @@ type ResourceSearchResponse struct {
 	TotalPages      int        `json:"totalPages"`
 	NextPage        bool       `json:"nextPage"`
 	DescendingOrder bool       `json:"descendingOrder"`
+	Pagination      *PageInfo  `json:"pagination,omitempty"`
+}
+
+// ResourceGroups is a resource search bucketed by device group, site or
+// type. A resource in several device groups is counted in each, so the
+// counts may add up to more than Total.
+type ResourceGroups struct {
+	GroupBy   string          `json:"groupBy"`
+	Total     int             `json:"total"`
+	Groups    []ResourceGroup `json:"groups"`
+	Truncated bool            `json:"truncated,omitempty"`
+	Message   string          `json:"message,omitempty"`
+}
+
+// ResourceGroup is one bucket of a grouped search: how many matching
+// resources it holds and a sample of them. Resources without a device group
+// or site fall in the bucket with an empty Key.
+type ResourceGroup struct {
+	Key    string            `json:"key"`
+	Name   string            `json:"name"`
+	Count  int               `json:"count"`
+	Sample []ResourceMinimal `json:"sample"`
 }
 
 // DetailedResource represents a detailed view of an OpsRamp resource
@@ -149,6 +173,19 @@ type DetailedResource struct {
 	Warranty              *Warranty              `json:"warranty,omitempty"`
 }
 
+// ResourceDetails is a detailed resource with the related sections named in
+// the include argument of getDetailed. A section that could not be retrieved
+// is left out and its error reported in Errors.
+type ResourceDetails struct {
+	Resource           *DetailedResource      `json:"resource"`
+	Tags               []Tag                  `json:"tags,omitempty"`
+	Relationships      []ResourceRelationship `json:"relationships,omitempty"`
+	ServiceGroups      []ServiceGroup         `json:"serviceGroups,omitempty"`
+	Patches            []ResourcePatch        `json:"patches,omitempty"`
+	MaintenanceWindows []MaintenanceWindow    `json:"maintenanceWindows,omitempty"`
+	Errors             map[string]string      `json:"errors,omitempty"`
+}
+
 // CPU represents a CPU in a resource
 type CPU struct {
 	Name         string `json:"name"`
@@ -234,6 +271,31 @@ type ResourceCreateRequest struct {
 	Properties               map[string]any     `json:"properties,omitempty"`
 }
 
+// Upsert operations reported by ResourceUpsertResult
+const (
+	UpsertCreated = "created"
+	UpsertUpdated = "updated"
+)
+
+// ResourceUpsertResult reports whether createOrUpdate created a resource or
+// updated the one matching its serial number or external ID
+type ResourceUpsertResult struct {
+	Operation string `json:"operation"`
+	// MatchedBy names the key an updated resource was found by:
+	// extResourceId or serialNumber
+	MatchedBy string    `json:"matchedBy,omitempty"`
+	Resource  *Resource `json:"resource"`
+}
+
+// ResourceTags are the tags of a resource after reading or changing them
+type ResourceTags struct {
+	ResourceID string `json:"resourceId"`
+	Tags       []Tag  `json:"tags"`
+	// Changed reports whether the action changed the tags; false when
+	// addTag found the tag already set or removeTag found it absent
+	Changed bool `json:"changed"`
+}
+
 // NetworkInterface represents a network interface for a resource
 type NetworkInterface struct {
 	Name        string `json:"name"`
@@ -310,12 +372,21 @@ type ResourceStateChangeRequest struct {
 	State string `json:"state"`
 }
 
+// ResourceStateChange reports the state a resource was moved to
+type ResourceStateChange struct {
+	ResourceID string `json:"resourceId"`
+	State      string `json:"state"`
+}
+
 // ResourceMetricsRequest represents a request to get metrics for a resource
 type ResourceMetricsRequest struct {
 	MetricNames []string `json:"metricNames"`
 	StartTime   string   `json:"startTime"`
 	EndTime     string   `json:"endTime"`
 	Interval    string   `json:"interval,omitempty"`
+	// Instance restricts a dimensioned metric, such as per-disk or
+	// per-interface usage, to one of its instances
+	Instance string `json:"instance,omitempty"`
 }
 
 // ResourceMetricsResponse represents a response containing resource metrics
@@ -330,6 +401,34 @@ type ResourceMetricDataPoint struct {
 	Timestamp string  `json:"timestamp"`
 	Value     float64 `json:"value"`
 	Unit      string  `json:"unit"`
+	// Instance and Labels identify the instance of a dimensioned metric
+	Instance string            `json:"instance,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+// MetricInstance is one instance of a dimensioned metric on a resource, such
+// as a disk, network interface or process
+type MetricInstance struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// ResourceMetricInstances lists the instances of a metric on a resource
+type ResourceMetricInstances struct {
+	ResourceID string           `json:"resourceId"`
+	Metric     string           `json:"metric"`
+	Instances  []MetricInstance `json:"instances"`
+}
+
+// ResourceAvailability represents a resource's availability over a time range
+type ResourceAvailability struct {
+	ResourceID          string  `json:"resourceId"`
+	StartTime           string  `json:"startTime"`
+	EndTime             string  `json:"endTime"`
+	AvailabilityPercent float64 `json:"availabilityPercent"`
+	UpTimeSeconds       int64   `json:"upTime"`
+	DownTimeSeconds     int64   `json:"downTime"`
+	UnknownTimeSeconds  int64   `json:"unknownTime,omitempty"`
 }
 
 // ============================================================================
@@ -353,6 +452,94 @@ type DeviceGroup struct {
 	Tags          []Tag          `json:"tags,omitempty"`
 }
 
+// DynamicGroup is a device group whose members are the resources matching a
+// selector. OpsRamp holds the group and its members; the selector is kept in
+// the state store and applied by refreshing the group.
+type DynamicGroup struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Tenant      string           `json:"tenant"`
+	Selector    ResourceSelector `json:"selector"`
+	CreatedAt   string           `json:"createdAt"`
+	UpdatedAt   string           `json:"updatedAt"`
+	RefreshedAt string           `json:"refreshedAt,omitempty"`
+	MemberCount int              `json:"memberCount"`
+}
+
+// DynamicGroupChange is the membership change of a dynamic group, previewed
+// or applied. Previewing a selector without a group lists every match as added.
+type DynamicGroupChange struct {
+	Group    *DynamicGroup    `json:"group,omitempty"`
+	Selector ResourceSelector `json:"selector"`
+	Matched  int              `json:"matched"`
+	Added    []BulkTarget     `json:"added"`
+	Removed  []string         `json:"removed"`
+	Applied  bool             `json:"applied"`
+	Message  string           `json:"message"`
+}
+
+// GroupDefinition is a device group as recorded in a group snapshot: its
+// definition, its member resources and, for a dynamic group, the selector
+// query that picks them
+type GroupDefinition struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	ParentID    string            `json:"parentId,omitempty"`
+	UpdatedDate string            `json:"updatedDate,omitempty"`
+	UpdatedBy   string            `json:"updatedBy,omitempty"`
+	Query       *ResourceSelector `json:"query,omitempty"`
+	Members     []string          `json:"members"`
+}
+
+// GroupSnapshot records a tenant's device groups and sites at a point in time
+type GroupSnapshot struct {
+	Tenant       string            `json:"tenant"`
+	TakenAt      string            `json:"takenAt"`
+	DeviceGroups []GroupDefinition `json:"deviceGroups"`
+	Sites        []Site            `json:"sites"`
+}
+
+// GroupChange is a device group or site added, removed or changed between
+// two snapshots. The change happened after Since and before SeenAt.
+type GroupChange struct {
+	Kind           string        `json:"kind"`
+	ID             string        `json:"id"`
+	Name           string        `json:"name"`
+	Change         string        `json:"change"`
+	Since          string        `json:"since"`
+	SeenAt         string        `json:"seenAt"`
+	Fields         []FieldChange `json:"fields,omitempty"`
+	MembersAdded   []string      `json:"membersAdded,omitempty"`
+	MembersRemoved []string      `json:"membersRemoved,omitempty"`
+}
+
+// GroupHistory lists the changes of one device group or site across the
+// snapshots of a time range
+type GroupHistory struct {
+	ID        string        `json:"id"`
+	Kind      string        `json:"kind"`
+	Name      string        `json:"name"`
+	From      string        `json:"from"`
+	To        string        `json:"to"`
+	Snapshots int           `json:"snapshots"`
+	Changes   []GroupChange `json:"changes"`
+	Message   string        `json:"message"`
+}
+
+// GroupDiff is the difference between the device groups and sites of two
+// snapshots
+type GroupDiff struct {
+	Tenant    string        `json:"tenant"`
+	From      string        `json:"from"`
+	To        string        `json:"to"`
+	Changes   []GroupChange `json:"changes"`
+	Unchanged int           `json:"unchanged"`
+	Summary   string        `json:"summary"`
+}
+
 // Site represents an OpsRamp site/location
 type Site struct {
 	ID               string         `json:"id"`
@@ -390,6 +577,25 @@ type ServiceGroup struct {
 	Tags          []Tag          `json:"tags,omitempty"`
 }
 
+// SiteRequest creates a site, or changes the fields of one that are set
+type SiteRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Address     string `json:"address,omitempty"`
+	City        string `json:"city,omitempty"`
+	State       string `json:"state,omitempty"`
+	Country     string `json:"country,omitempty"`
+	ZipCode     string `json:"zipCode,omitempty"`
+	TimeZone    string `json:"timeZone,omitempty"`
+}
+
+// ServiceGroupRequest creates a service group, or changes the fields of one
+// that are set
+type ServiceGroupRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
 // ============================================================================
 // SUPPORTING TYPES (T2.3.1-T2.3.4)
 // ============================================================================
@@ -480,6 +686,328 @@ type ResourceMinimal struct {
 	UpdatedDate  string    `json:"updatedDate"`
 }
 
+// ============================================================================
+// TOPOLOGY AND IMPACT ANALYSIS TYPES
+// ============================================================================
+
+// ResourceRelationship is a topology edge between two resources. The target
+// depends on the source (e.g. a hypervisor hosts a VM).
+type ResourceRelationship struct {
+	SourceID         string `json:"sourceId"`
+	SourceName       string `json:"sourceName,omitempty"`
+	TargetID         string `json:"targetId"`
+	TargetName       string `json:"targetName,omitempty"`
+	TargetType       string `json:"targetType,omitempty"`
+	RelationshipType string `json:"relationshipType"`
+}
+
+// ImpactedResource is a resource affected by an outage of the analysed resource
+type ImpactedResource struct {
+	ID              string `json:"id"`
+	Name            string `json:"name,omitempty"`
+	Type            string `json:"type,omitempty"`
+	Depth           int    `json:"depth"`
+	Via             string `json:"via,omitempty"`
+	OpenAlerts      int    `json:"openAlerts"`
+	HighestSeverity string `json:"highestSeverity,omitempty"`
+}
+
+// ImpactedService is a service group containing the analysed resource or one of its dependents
+type ImpactedService struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	ImpactedResources []string `json:"impactedResources"`
+}
+
+// ImpactAnalysis is the downstream impact of an outage of a resource
+type ImpactAnalysis struct {
+	ResourceID        string             `json:"resourceId"`
+	ResourceName      string             `json:"resourceName,omitempty"`
+	ImpactedResources []ImpactedResource `json:"impactedResources"`
+	ImpactedServices  []ImpactedService  `json:"impactedServices"`
+	SeverityRollup    map[string]int     `json:"severityRollup"`
+	HighestSeverity   string             `json:"highestSeverity,omitempty"`
+	Summary           string             `json:"summary"`
+	Truncated         bool               `json:"truncated,omitempty"`
+}
+
+// ============================================================================
+// SCORECARD TYPES
+// ============================================================================
+
+// ResourceScorecard is a one-page health summary of a resource. Sections that
+// could not be retrieved are left empty and listed in Unavailable.
+type ResourceScorecard struct {
+	ResourceID      string                   `json:"resourceId"`
+	ResourceName    string                   `json:"resourceName,omitempty"`
+	GeneratedAt     string                   `json:"generatedAt"`
+	Availability    *AvailabilitySummary     `json:"availability,omitempty"`
+	Utilization     map[string]MetricSummary `json:"utilization,omitempty"`
+	OpenAlerts      *AlertCounts             `json:"openAlerts,omitempty"`
+	PatchCompliance *PatchCompliance         `json:"patchCompliance,omitempty"`
+	Agent           *AgentSummary            `json:"agent,omitempty"`
+	Unavailable     map[string]string        `json:"unavailable,omitempty"`
+	Summary         string                   `json:"summary"`
+}
+
+// AvailabilitySummary is the current availability of a resource
+type AvailabilitySummary struct {
+	Status string `json:"status"`
+	Since  string `json:"since,omitempty"`
+}
+
+// MetricSummary summarizes a metric's samples over a time window
+type MetricSummary struct {
+	Metric  string  `json:"metric"`
+	Unit    string  `json:"unit,omitempty"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Avg     float64 `json:"avg"`
+	Latest  float64 `json:"latest"`
+	Samples int     `json:"samples"`
+}
+
+// AlertCounts counts open alerts by severity
+type AlertCounts struct {
+	Total      int            `json:"total"`
+	BySeverity map[string]int `json:"bySeverity"`
+}
+
+// ResourcePropertySchema describes the properties found on a sample of the
+// resources of one type
+type ResourcePropertySchema struct {
+	ResourceType string                 `json:"resourceType"`
+	Sampled      int                    `json:"sampled"`
+	Properties   []ResourcePropertyInfo `json:"properties"`
+	// Truncated reports that more resources of the type exist than were sampled
+	Truncated bool `json:"truncated,omitempty"`
+	// Documentation is the schema as a markdown table for prompt authors
+	Documentation string `json:"documentation"`
+}
+
+// ResourcePropertyInfo describes one property key, with nested object keys
+// joined by dots
+type ResourcePropertyInfo struct {
+	Key string `json:"key"`
+	// Types are the JSON types seen: string, number, boolean, object, array or null
+	Types []string `json:"types"`
+	// FillRate is the fraction of sampled resources with a non-empty value
+	FillRate float64 `json:"fillRate"`
+	Filled   int     `json:"filled"`
+	// Reliable marks properties filled on nearly every sampled resource
+	Reliable bool     `json:"reliable"`
+	Examples []string `json:"examples,omitempty"`
+}
+
+// MetricCatalog lists the metrics collected on a sample of the resources of
+// one type
+type MetricCatalog struct {
+	ResourceType string              `json:"resourceType"`
+	Sampled      int                 `json:"sampled"`
+	Metrics      []MetricCatalogInfo `json:"metrics"`
+	// Truncated reports that more resources of the type exist than were sampled
+	Truncated bool `json:"truncated,omitempty"`
+	// BuiltAt is when the catalog was sampled; Cached reports that it was
+	// served from an earlier call
+	BuiltAt string `json:"builtAt"`
+	Cached  bool   `json:"cached,omitempty"`
+}
+
+// MetricCatalogInfo describes one metric of a resource type. The range and
+// interval come from the last day of data on a few of the sampled resources
+// and are missing when none of them reported the metric.
+type MetricCatalogInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Unit        string `json:"unit,omitempty"`
+	// Resources counts the sampled resources collecting the metric
+	Resources int     `json:"resources"`
+	Coverage  float64 `json:"coverage"`
+	// TypicalMin and TypicalMax are the 5th and 95th percentiles of the values seen
+	TypicalMin *float64 `json:"typicalMin,omitempty"`
+	TypicalMax *float64 `json:"typicalMax,omitempty"`
+	// IntervalSeconds is the median time between consecutive data points
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+}
+
+// ResourceIDResolution translates an identifier of a resource into its
+// resource ID, the UUID the resource API paths take
+type ResourceIDResolution struct {
+	Input string `json:"input"`
+	// Kind is the kind of identifier the input matched, e.g. id, identity or hostName
+	Kind       string `json:"kind"`
+	ResourceID string `json:"resourceId"`
+	Name       string `json:"name,omitempty"`
+	// Identifiers are the non-empty identifiers of the resource by kind
+	Identifiers map[string]string `json:"identifiers"`
+	Cached      bool              `json:"cached,omitempty"`
+}
+
+// ResourcePatch is a patch applicable to a resource
+type ResourcePatch struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Severity string `json:"severity,omitempty"`
+	Status   string `json:"status"`
+}
+
+// PatchCompliance summarizes the patch state of a resource
+type PatchCompliance struct {
+	Compliant       bool `json:"compliant"`
+	Installed       int  `json:"installed"`
+	Missing         int  `json:"missing"`
+	MissingCritical int  `json:"missingCritical"`
+}
+
+// AgentSummary is the monitoring agent state of a resource
+type AgentSummary struct {
+	Installed     bool   `json:"installed"`
+	Status        string `json:"status,omitempty"`
+	LastConnected string `json:"lastConnected,omitempty"`
+}
+
+// ============================================================================
+// TIMELINE TYPES
+// ============================================================================
+
+// TimelineEventKind is the source of a timeline event
+type TimelineEventKind string
+
+const (
+	TimelineEventAlert       TimelineEventKind = "alert"
+	TimelineEventState       TimelineEventKind = "stateChange"
+	TimelineEventMaintenance TimelineEventKind = "maintenance"
+	TimelineEventAnomaly     TimelineEventKind = "anomaly"
+)
+
+// TimelineEvent is one entry of a timeline report
+type TimelineEvent struct {
+	Time         string            `json:"time"`
+	Kind         TimelineEventKind `json:"kind"`
+	ResourceID   string            `json:"resourceId,omitempty"`
+	ResourceName string            `json:"resourceName,omitempty"`
+	Severity     string            `json:"severity,omitempty"`
+	Summary      string            `json:"summary"`
+	// Reference is the ID of the alert or maintenance window behind the event
+	Reference string `json:"reference,omitempty"`
+}
+
+// TimelineReport is a chronological account of what happened to a resource
+// or the members of a service group within a time window. Sources that could
+// not be retrieved are listed in Unavailable.
+type TimelineReport struct {
+	Scope       string                    `json:"scope"`
+	ID          string                    `json:"id"`
+	Name        string                    `json:"name,omitempty"`
+	Start       string                    `json:"start"`
+	End         string                    `json:"end"`
+	GeneratedAt string                    `json:"generatedAt"`
+	Resources   int                       `json:"resources"`
+	Events      []TimelineEvent           `json:"events"`
+	Counts      map[TimelineEventKind]int `json:"counts"`
+	Unavailable map[string]string         `json:"unavailable,omitempty"`
+	Truncated   bool                      `json:"truncated,omitempty"`
+	// Report is the timeline as a Markdown table for a postmortem document
+	Report string `json:"report"`
+}
+
+// MaintenanceWindow is a scheduled maintenance of one or more resources
+type MaintenanceWindow struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status,omitempty"`
+	StartTime   string `json:"startTime"`
+	EndTime     string `json:"endTime,omitempty"`
+}
+
+// ============================================================================
+// ONBOARDING TYPES
+// ============================================================================
+
+// OnboardingRequest describes how to bring discovered-but-unmanaged resources under management
+type OnboardingRequest struct {
+	Filter        ResourceSearchParams `json:"filter"`
+	DeviceGroupID string               `json:"deviceGroupId,omitempty"`
+	SiteID        string               `json:"siteId,omitempty"`
+	TemplateIDs   []string             `json:"templateIds,omitempty"`
+	Tags          []Tag                `json:"tags,omitempty"`
+	Limit         int                  `json:"limit,omitempty"`
+	DryRun        bool                 `json:"dryRun,omitempty"`
+}
+
+// OnboardingStep is the outcome of one onboarding step for a resource
+type OnboardingStep struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// OnboardingOutcome is the per-resource result of an onboarding run
+type OnboardingOutcome struct {
+	ResourceID string           `json:"resourceId"`
+	Name       string           `json:"name,omitempty"`
+	Status     string           `json:"status"`
+	Steps      []OnboardingStep `json:"steps"`
+}
+
+// OnboardingResult summarises an onboarding run
+type OnboardingResult struct {
+	DryRun    bool                `json:"dryRun"`
+	Matched   int                 `json:"matched"`
+	Processed int                 `json:"processed"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Remaining int                 `json:"remaining,omitempty"`
+	Outcomes  []OnboardingOutcome `json:"outcomes"`
+}
+
+// ResourceSelector picks the targets of a bulk mutation by filter instead of
+// by explicit IDs. All set fields must match. HostName and Name accept "*"
+// wildcards, IPAddress accepts an exact address or a CIDR range, and Tag is
+// "name=value" or just "name".
+type ResourceSelector struct {
+	Type        string `json:"type,omitempty"`
+	Tag         string `json:"tag,omitempty"`
+	HostName    string `json:"hostName,omitempty"`
+	Name        string `json:"name,omitempty"`
+	IPAddress   string `json:"ipAddress,omitempty"`
+	State       string `json:"state,omitempty"`
+	DeviceGroup string `json:"deviceGroup,omitempty"`
+}
+
+// BulkTarget is one resource resolved from a selector
+type BulkTarget struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	HostName  string `json:"hostName,omitempty"`
+	IPAddress string `json:"ipAddress,omitempty"`
+	Type      string `json:"type,omitempty"`
+}
+
+// BulkSelectionResult is the outcome of a selector-based bulk action. Without
+// confirmation it is a preview: the resolved targets and count, with nothing
+// changed. Re-run with confirm and expectedCount set to Count to execute.
+type BulkSelectionResult struct {
+	Action   string           `json:"action"`
+	Selector ResourceSelector `json:"selector"`
+	Count    int              `json:"count"`
+	Targets  []BulkTarget     `json:"targets"`
+	Executed bool             `json:"executed"`
+	Message  string           `json:"message"`
+}
+
+// DeletePlan lists the resources a bulk delete would remove. Deletes of more
+// than the configured threshold must present ConfirmationToken, which is
+// bound to exactly these IDs and expires at ExpiresAt.
+type DeletePlan struct {
+	Count             int          `json:"count"`
+	Targets           []BulkTarget `json:"targets"`
+	ConfirmationToken string       `json:"confirmationToken"`
+	ExpiresAt         time.Time    `json:"expiresAt"`
+	Message           string       `json:"message"`
+}
+
 // ============================================================================
 // VALIDATION AND SERIALIZATION METHODS (T2.4.1-T2.4.4)
 // ============================================================================
@@ -0,0 +1,27 @@
+package types
+
+// TenantFanOutResult merges the results of one read-only action run on
+// several tenants. Every entry carries a tenant field naming the tenant it
+// came from; tenants whose call failed are listed in Failures instead.
+type TenantFanOutResult struct {
+	Action       string          `json:"action"`
+	Tenants      []string        `json:"tenants"`
+	Results      []interface{}   `json:"results"`
+	TotalResults int             `json:"totalResults"`
+	Failures     []TenantFailure `json:"failures,omitempty"`
+}
+
+// TenantFailure reports why an action failed on one tenant
+type TenantFailure struct {
+	Tenant string `json:"tenant"`
+	Error  string `json:"error"`
+}
+
+// CacheInvalidation reports how many cached OpsRamp responses of one tenant
+// a tool dropped: list and search responses, entities fetched by ID, and
+// type catalogs
+type CacheInvalidation struct {
+	Responses int `json:"responses"`
+	Entities  int `json:"entities"`
+	Catalogs  int `json:"catalogs"`
+}
@@ -0,0 +1,42 @@
+package types
+
+// LicenseUsage is the licensed limit and current consumption for one tier
+type LicenseUsage struct {
+	Tier        string `json:"tier"`
+	Description string `json:"description,omitempty"`
+	Limit       int    `json:"limit"`
+	Used        int    `json:"used"`
+}
+
+// QuotaStatus describes how close a tier is to its license limit
+type QuotaStatus struct {
+	Tier        string  `json:"tier"`
+	Limit       int     `json:"limit"`
+	Used        int     `json:"used"`
+	Remaining   int     `json:"remaining"`
+	PercentUsed float64 `json:"percentUsed"`
+	Status      string  `json:"status"`
+}
+
+// UsageSummary answers "how close are we to our license limits"
+type UsageSummary struct {
+	Tiers        []QuotaStatus `json:"tiers"`
+	TotalManaged int           `json:"totalManaged"`
+	NearestLimit *QuotaStatus  `json:"nearestLimit,omitempty"`
+	Summary      string        `json:"summary"`
+}
+
+// ResourceTierCount is the number of managed resources in a license tier
+type ResourceTierCount struct {
+	Tier  string `json:"tier"`
+	Count int    `json:"count"`
+}
+
+// APIUsage reports API call consumption for the tenant, when exposed by OpsRamp
+type APIUsage struct {
+	Available bool   `json:"available"`
+	Period    string `json:"period,omitempty"`
+	Calls     int64  `json:"calls,omitempty"`
+	Limit     int64  `json:"limit,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
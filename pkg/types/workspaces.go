@@ -0,0 +1,58 @@
+package types
+
+import "encoding/json"
+
+// SavedSearch is a tool call kept in a workspace so it can be run again
+type SavedSearch struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// WorkspaceResult is a stored tool result saved with a workspace
+type WorkspaceResult struct {
+	// Handle is the result handle the result had in the saving session
+	Handle    string          `json:"handle"`
+	Tool      string          `json:"tool"`
+	Bytes     int             `json:"bytes"`
+	CreatedAt string          `json:"createdAt"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Workspace is the saved context of an investigation: session context
+// variables, saved searches and recent results
+type Workspace struct {
+	Name      string                 `json:"name"`
+	SavedAt   string                 `json:"savedAt"`
+	Variables map[string]interface{} `json:"variables"`
+	Searches  map[string]SavedSearch `json:"searches"`
+	Results   []WorkspaceResult      `json:"results,omitempty"`
+}
+
+// WorkspaceSummary describes a saved workspace without its results
+type WorkspaceSummary struct {
+	Name      string   `json:"name"`
+	SavedAt   string   `json:"savedAt"`
+	Variables []string `json:"variables"`
+	Searches  []string `json:"searches"`
+	Results   int      `json:"results"`
+	// Skipped lists the result handles left out of a save to bound its size
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// WorkspaceHandle maps a saved result to its handle in the loading session
+type WorkspaceHandle struct {
+	Saved        string `json:"saved"`
+	ResultHandle string `json:"resultHandle"`
+	Tool         string `json:"tool"`
+	ExpiresAt    string `json:"expiresAt"`
+}
+
+// LoadedWorkspace is the result of loading a workspace into a session
+type LoadedWorkspace struct {
+	Name      string                 `json:"name"`
+	SavedAt   string                 `json:"savedAt"`
+	Variables map[string]interface{} `json:"variables"`
+	Searches  map[string]SavedSearch `json:"searches"`
+	Handles   []WorkspaceHandle      `json:"handles"`
+	Hint      string                 `json:"hint"`
+}